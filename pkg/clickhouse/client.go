@@ -2,6 +2,7 @@ package clickhouse
 
 import (
 	"context"
+	"crypto/tls"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
@@ -22,14 +23,36 @@ type (
 		// and GetDictionaries operations. This is useful for excluding test or temporary databases.
 		IgnoreDatabases []string
 
+		// Username and Password authenticate the connection. When set, they take
+		// precedence over the credentials in the DSN.
+		Username string
+		Password string
+
+		// Database specifies the default database for the connection, overriding the
+		// database in the DSN.
+		Database string
+
+		// Secure enables TLS for the connection. It's implied by TLSConfig and TLSSettings,
+		// and set by ParseDSN for DSNs with secure=true.
+		Secure bool
+
+		// TLSConfig specifies a custom TLS configuration for the connection. It takes
+		// precedence over TLSSettings.
+		TLSConfig *tls.Config
+
 		// TLSSettings specifies the CA and client certificate for mTLS between the client and server
 		TLSSettings
+
+		// Settings specifies ClickHouse settings (e.g. max_execution_time) applied to every
+		// query. They're merged with the settings in the DSN, replacing any with the same name.
+		Settings map[string]any
 	}
 
 	// Client represents a ClickHouse database connection
 	Client struct {
-		conn    driver.Conn
-		options ClientOptions
+		conn     driver.Conn
+		connOpts *clickhouse.Options
+		options  ClientOptions
 	}
 
 	// Specify the CA and client certificate + key for mTLS
@@ -102,23 +125,19 @@ func NewClient(ctx context.Context, dsn string) (*Client, error) {
 //	// Dumped schema will include ON CLUSTER clauses
 //	schema, err := client.GetSchema(ctx)
 //	// All DDL statements will have "ON CLUSTER production_cluster"
+//
+//	// Client with authentication over TLS
+//	client, err := clickhouse.NewClientWithOptions(ctx, "clickhouse.example.com:9440", clickhouse.ClientOptions{
+//	    Username: "housekeeper",
+//	    Password: os.Getenv("CLICKHOUSE_PASSWORD"),
+//	    Database: "analytics",
+//	    Secure:   true,
+//	    Settings: map[string]any{"max_execution_time": 60},
+//	})
 func NewClientWithOptions(ctx context.Context, dsn string, clientOpts ClientOptions) (*Client, error) {
-	// Try to parse as a full DSN first
-	options, err := clickhouse.ParseDSN(dsn)
+	options, err := connectionOptions(dsn, clientOpts)
 	if err != nil {
-		// If parsing fails, assume it's a simple host:port format
-		// and create options manually
-		options = &clickhouse.Options{
-			Addr: []string{dsn},
-		}
-	}
-
-	if clientOpts.CertFile != "" {
-		tlsOpts, err := GetTLSConfig(clientOpts)
-		if err != nil {
-			return nil, err
-		}
-		options.TLS = tlsOpts
+		return nil, err
 	}
 
 	conn, err := clickhouse.Open(options)
@@ -131,11 +150,42 @@ func NewClientWithOptions(ctx context.Context, dsn string, clientOpts ClientOpti
 	}
 
 	return &Client{
-		conn:    conn,
-		options: clientOpts,
+		conn:     conn,
+		connOpts: options,
+		options:  clientOpts,
 	}, nil
 }
 
+// Session opens a connection that runs every query in a single server session, which
+// statements that depend on session state (e.g. BEGIN TRANSACTION and COMMIT) require.
+// The client's own connections are pooled, so consecutive queries may run on different
+// connections and thus in different sessions. The caller must close the returned connection.
+//
+// Example:
+//
+//	session, err := client.Session(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer session.Close()
+//
+//	if err := session.Exec(ctx, "BEGIN TRANSACTION"); err != nil {
+//		log.Fatal(err)
+//	}
+func (c *Client) Session(ctx context.Context) (driver.Conn, error) {
+	conn, err := clickhouse.Open(sessionOptions(c.connOpts))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open clickhouse session")
+	}
+
+	if err := conn.Ping(ctx); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "failed to connect clickhouse session")
+	}
+
+	return conn, nil
+}
+
 // Close closes the ClickHouse connection and releases associated resources.
 // This method should be called when the client is no longer needed to ensure
 // proper cleanup of database connections.
@@ -331,7 +381,7 @@ func (c *Client) GetViews(ctx context.Context) (*parser.SQL, error) {
 // injected into all extracted DDL statements.
 //
 // The extraction includes all dictionary features:
-//   - Column attributes (IS_OBJECT_ID, HIERARCHICAL, INJECTIVE)
+//   - Column attributes (IS_OBJECT_ID, HIERARCHICAL, BIDIRECTIONAL, INJECTIVE)
 //   - Source configurations (MySQL, HTTP, ClickHouse, File, etc.)
 //   - Layout types (FLAT, HASHED, COMPLEX_KEY_HASHED, etc.)
 //   - Lifetime configurations (single values or MIN/MAX ranges)
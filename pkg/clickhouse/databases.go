@@ -14,7 +14,9 @@ import (
 // This function queries the system.databases table to get complete database information
 // and returns them as parsed DDL statements.
 //
-// System databases (system, information_schema) are automatically excluded.
+// System databases (system, information_schema) are automatically excluded. The engine
+// is read from engine_full, so engine arguments and database SETTINGS are extracted as
+// well and compare equal to the schema they were created from.
 // All generated DDL statements are validated using the parser before being returned.
 //
 // Example:
@@ -44,6 +46,7 @@ func extractDatabases(ctx context.Context, client *Client) (*parser.SQL, error)
 		SELECT 
 			name,
 			engine,
+			engine_full,
 			comment
 		FROM system.databases 
 		WHERE %s
@@ -58,13 +61,17 @@ func extractDatabases(ctx context.Context, client *Client) (*parser.SQL, error)
 
 	var statements []string
 	for rows.Next() {
-		var name, engine string
+		var name, engine, engineFull string
 		var comment sql.NullString
 
-		if err := rows.Scan(&name, &engine, &comment); err != nil {
+		if err := rows.Scan(&name, &engine, &engineFull, &comment); err != nil {
 			return nil, errors.Wrap(err, "failed to scan database row")
 		}
 
+		if engineFull != "" {
+			engine = engineFull
+		}
+
 		// Generate CREATE DATABASE statement
 		ddl := generateDatabaseDDL(name, engine, comment.String)
 
@@ -91,7 +98,9 @@ func extractDatabases(ctx context.Context, client *Client) (*parser.SQL, error)
 	return sqlResult, nil
 }
 
-// generateDatabaseDDL creates a CREATE DATABASE DDL statement from database metadata.
+// generateDatabaseDDL creates a CREATE DATABASE DDL statement from database metadata. The
+// engine is the full engine clause as reported by system.databases.engine_full, e.g.
+// "Replicated('/clickhouse/db', 's1', 'r1') SETTINGS max_broken_tables_ratio = 0.5".
 func generateDatabaseDDL(name, engine, comment string) string {
 	var parts []string
 
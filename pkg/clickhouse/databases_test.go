@@ -0,0 +1,51 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDatabaseDDL_RoundTrip(t *testing.T) {
+	// Each case is a schema definition and the row system.databases reports for it
+	tests := []struct {
+		name       string
+		schema     string
+		engineFull string
+		comment    string
+	}{
+		{
+			name:       "atomic",
+			schema:     "CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Analytics';",
+			engineFull: "Atomic",
+			comment:    "Analytics",
+		},
+		{
+			name:       "replicated with settings",
+			schema:     "CREATE DATABASE analytics ENGINE = Replicated('/clickhouse/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;",
+			engineFull: "Replicated('/clickhouse/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50",
+		},
+		{
+			name:       "settings and comment",
+			schema:     "CREATE DATABASE analytics ENGINE = Replicated('/clickhouse/analytics', 's1', 'r1') SETTINGS max_broken_tables_ratio = 1 COMMENT 'It\\'s replicated';",
+			engineFull: "Replicated('/clickhouse/analytics', 's1', 'r1') SETTINGS max_broken_tables_ratio = 1",
+			comment:    "It's replicated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := parser.ParseString(tt.schema)
+			require.NoError(t, err)
+
+			dumped, err := parser.ParseString(generateDatabaseDDL("analytics", tt.engineFull, tt.comment))
+			require.NoError(t, err)
+
+			// A dump of the database must not produce a diff against its definition
+			_, err = schema.GenerateDiff(dumped, source)
+			require.ErrorIs(t, err, schema.ErrNoDiff)
+		})
+	}
+}
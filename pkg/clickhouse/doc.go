@@ -6,6 +6,7 @@
 //
 // Key features:
 //   - Simple connection management with DSN-based configuration
+//   - Authentication, TLS, and query settings via ClientOptions or the DSN
 //   - Comprehensive schema extraction for all ClickHouse object types
 //   - Dedicated SchemaExtractor for granular control over schema operations
 //   - Retrieval of current schemas as parser.SQL structures for migration generation
@@ -18,6 +19,7 @@
 //   - Tables: Full table definitions with columns, engines, and all table properties
 //   - Views: Both regular and materialized views with their definitions
 //   - Dictionaries: Dictionary definitions with source, layout, and lifetime configurations
+//   - Named Collections: Collection parameters, with secret values masked
 //
 // The client is designed to work seamlessly with the parser and migrator packages,
 // retrieving current database state in a format that can be directly compared
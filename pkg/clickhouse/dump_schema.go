@@ -7,7 +7,7 @@ import (
 	"github.com/pseudomuto/housekeeper/pkg/parser"
 )
 
-// DumpSchema retrieves all schema objects (databases, tables, dictionaries, views, roles, functions)
+// DumpSchema retrieves all schema objects (databases, named collections, tables, dictionaries, views, roles, functions)
 // and returns them as a parsed SQL structure ready for use with migration generation.
 //
 // This function combines all individual extraction functions to provide a complete view of the
@@ -16,11 +16,12 @@ import (
 //
 // The extraction follows this order:
 //  1. Databases - extracted first as they define the namespace
-//  2. Tables - extracted with full DDL statements
-//  3. Dictionaries - dictionary definitions with source/layout/lifetime
-//  4. Views - both regular and materialized views (extracted last since they may depend on dictionaries)
-//  5. Roles - global role definitions and privilege grants
-//  6. Functions - user-defined function definitions (global objects)
+//  2. Named Collections - connection settings used by table and dictionary engines (secrets masked)
+//  3. Tables - extracted with full DDL statements
+//  4. Dictionaries - dictionary definitions with source/layout/lifetime
+//  5. Views - both regular and materialized views (extracted last since they may depend on dictionaries)
+//  6. Roles - global role definitions and privilege grants
+//  7. Functions - user-defined function definitions (global objects)
 //
 // All system objects are automatically excluded and all DDL statements are validated.
 //
@@ -54,6 +55,13 @@ func DumpSchema(ctx context.Context, client *Client) (*parser.SQL, error) {
 	}
 	allStatements = append(allStatements, databases.Statements...)
 
+	// Extract named collections (before tables since engines may reference them)
+	collections, err := extractNamedCollections(ctx, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract named collections")
+	}
+	allStatements = append(allStatements, collections.Statements...)
+
 	// Extract tables
 	tables, err := extractTables(ctx, client)
 	if err != nil {
@@ -107,6 +115,11 @@ func extractFunctions(ctx context.Context, client *Client) (*parser.SQL, error)
 	return client.GetFunctions(ctx)
 }
 
+// extractNamedCollections is a wrapper function that calls client.GetNamedCollections
+func extractNamedCollections(ctx context.Context, client *Client) (*parser.SQL, error) {
+	return client.GetNamedCollections(ctx)
+}
+
 // injectOnCluster adds ON CLUSTER clauses to all DDL statements when cluster is specified.
 // This addresses the limitation in ClickHouse where system tables don't include ON CLUSTER
 // information in dumped DDL statements. When running against a distributed ClickHouse cluster,
@@ -120,6 +133,14 @@ func extractFunctions(ctx context.Context, client *Client) (*parser.SQL, error)
 //   - CREATE VIEW statements (both regular and materialized)
 //   - CREATE ROLE statements
 //   - GRANT/REVOKE statements
+//   - CREATE/DROP FUNCTION statements
+//   - SYSTEM statements
+//   - TRUNCATE TABLE statements
+//   - EXCHANGE TABLES/DICTIONARIES statements
+//
+// Functions, SYSTEM, TRUNCATE, and EXCHANGE statements aren't part of the schema, but
+// migrations use them for operations that must run on every node of the cluster, so they
+// are handled the same way.
 //
 // Housekeeper internal objects (database 'housekeeper' and its objects) are excluded
 // from ON CLUSTER injection as they should be shard-local for migration tracking.
@@ -167,12 +188,60 @@ func injectOnCluster(statements []*parser.Statement, cluster string) []*parser.S
 		case stmt.Revoke != nil:
 			// Revokes are cluster-wide by nature
 			stmt.Revoke.OnCluster = clusterName
+		case stmt.CreateFunction != nil:
+			// Functions are cluster-wide by nature. An existing cluster is kept, matching
+			// what GetFunctions does when it adds the clause to the dumped DDL.
+			if stmt.CreateFunction.OnCluster == nil {
+				stmt.CreateFunction.OnCluster = clusterName
+			}
+		case stmt.DropFunction != nil:
+			stmt.DropFunction.OnCluster = clusterName
+		case stmt.System != nil:
+			injectSystemOnCluster(stmt.System, clusterName)
+		case stmt.TruncateTable != nil:
+			dbName := getDatabaseName(stmt.TruncateTable.Database)
+			if !isHousekeeperDatabase(dbName) {
+				stmt.TruncateTable.OnCluster = clusterName
+			}
+		case stmt.ExchangeTables != nil:
+			exchange := stmt.ExchangeTables
+			if !isHousekeeperDatabase(getDatabaseName(exchange.FirstDatabase)) &&
+				!isHousekeeperDatabase(getDatabaseName(exchange.SecondDatabase)) {
+				exchange.OnCluster = clusterName
+			}
+		case stmt.ExchangeDictionaries != nil:
+			exchange := stmt.ExchangeDictionaries
+			if !isHousekeeperDatabase(getDatabaseName(exchange.FirstDatabase)) &&
+				!isHousekeeperDatabase(getDatabaseName(exchange.SecondDatabase)) {
+				exchange.OnCluster = clusterName
+			}
 		}
 	}
 
 	return statements
 }
 
+// injectSystemOnCluster adds the ON CLUSTER clause to whichever SYSTEM operation the
+// statement holds. Operations on a housekeeper object are left shard-local.
+func injectSystemOnCluster(stmt *parser.SystemStmt, clusterName *string) {
+	switch {
+	case stmt.ReloadDictionary != nil:
+		if !isHousekeeperDatabase(getDatabaseName(stmt.ReloadDictionary.Database)) {
+			stmt.ReloadDictionary.OnCluster = clusterName
+		}
+	case stmt.ReloadDictionaries != nil:
+		stmt.ReloadDictionaries.OnCluster = clusterName
+	case stmt.ReloadConfig != nil:
+		stmt.ReloadConfig.OnCluster = clusterName
+	case stmt.FlushLogs != nil:
+		stmt.FlushLogs.OnCluster = clusterName
+	case stmt.SyncReplica != nil:
+		if !isHousekeeperDatabase(getDatabaseName(stmt.SyncReplica.Database)) {
+			stmt.SyncReplica.OnCluster = clusterName
+		}
+	}
+}
+
 // isHousekeeperDatabase determines if a database belongs to housekeeper's internal tracking system.
 // Housekeeper databases and their objects should be shard-local and never created with ON CLUSTER clauses.
 func isHousekeeperDatabase(database string) bool {
@@ -17,6 +17,8 @@ import (
 	"github.com/pseudomuto/housekeeper/pkg/consts"
 	"github.com/pseudomuto/housekeeper/pkg/docker"
 	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/golden"
 )
@@ -78,13 +80,13 @@ func TestDumpSchema(t *testing.T) {
 
 	// Test the main DumpSchema function via client.GetSchema()
 	// This tests the complete schema extraction functionality
-	schema, err := client.GetSchema(ctx)
+	dumped, err := client.GetSchema(ctx)
 	require.NoError(t, err)
-	require.NotNil(t, schema)
+	require.NotNil(t, dumped)
 
 	// Format the extracted schema for golden file comparison
 	var buf bytes.Buffer
-	require.NoError(t, format.FormatSQL(&buf, format.Defaults, schema))
+	require.NoError(t, format.FormatSQL(&buf, format.Defaults, dumped))
 
 	// Compare with golden file using gotest.tools/v3/golden
 	golden.Assert(t, buf.String(), "expected_schema.sql")
@@ -101,6 +103,19 @@ func TestDumpSchema(t *testing.T) {
 
 	_, err = client.GetDictionaries(ctx)
 	require.NoError(t, err)
+
+	// Named collections are extracted with their secrets masked, and compare equal to
+	// the definition they were created from
+	collections, err := client.NewSchemaExtractor().ExtractNamedCollections(ctx)
+	require.NoError(t, err)
+	require.Len(t, collections.Statements, 1)
+	require.NotContains(t, buf.String(), "hunter2")
+
+	defined, err := parser.ParseString("CREATE NAMED COLLECTION postgres_conn AS " +
+		"host = 'postgres', port = 5432, user = 'reader', password = 'hunter2';")
+	require.NoError(t, err)
+	_, err = schema.GenerateDiff(collections, defined)
+	require.ErrorIs(t, err, schema.ErrNoDiff)
 }
 
 // executeSchemaSeparately executes SQL statements individually to avoid multi-statement issues
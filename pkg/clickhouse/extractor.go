@@ -0,0 +1,63 @@
+package clickhouse
+
+import (
+	"context"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// SchemaExtractor extracts individual types of schema objects from a ClickHouse instance.
+// It gives callers granular control over which objects are retrieved, where DumpSchema
+// always extracts the complete schema.
+//
+// Example:
+//
+//	extractor := client.NewSchemaExtractor()
+//	collections, err := extractor.ExtractNamedCollections(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+type SchemaExtractor struct {
+	client *Client
+}
+
+// NewSchemaExtractor returns a SchemaExtractor that extracts objects using the client.
+func (c *Client) NewSchemaExtractor() *SchemaExtractor {
+	return &SchemaExtractor{client: c}
+}
+
+// ExtractDatabases returns the CREATE DATABASE statements of all non-system databases.
+func (e *SchemaExtractor) ExtractDatabases(ctx context.Context) (*parser.SQL, error) {
+	return extractDatabases(ctx, e.client)
+}
+
+// ExtractTables returns the CREATE TABLE statements of all non-system tables.
+func (e *SchemaExtractor) ExtractTables(ctx context.Context) (*parser.SQL, error) {
+	return extractTables(ctx, e.client)
+}
+
+// ExtractDictionaries returns the CREATE DICTIONARY statements of all dictionaries.
+func (e *SchemaExtractor) ExtractDictionaries(ctx context.Context) (*parser.SQL, error) {
+	return extractDictionaries(ctx, e.client)
+}
+
+// ExtractViews returns the CREATE VIEW statements of all regular and materialized views.
+func (e *SchemaExtractor) ExtractViews(ctx context.Context) (*parser.SQL, error) {
+	return extractViews(ctx, e.client)
+}
+
+// ExtractRoles returns the CREATE ROLE and GRANT statements of all roles.
+func (e *SchemaExtractor) ExtractRoles(ctx context.Context) (*parser.SQL, error) {
+	return extractRoles(ctx, e.client)
+}
+
+// ExtractFunctions returns the CREATE FUNCTION statements of all user-defined functions.
+func (e *SchemaExtractor) ExtractFunctions(ctx context.Context) (*parser.SQL, error) {
+	return extractFunctions(ctx, e.client)
+}
+
+// ExtractNamedCollections returns the CREATE NAMED COLLECTION statements of all named
+// collections, with secret values masked (see Client.GetNamedCollections).
+func (e *SchemaExtractor) ExtractNamedCollections(ctx context.Context) (*parser.SQL, error) {
+	return extractNamedCollections(ctx, e.client)
+}
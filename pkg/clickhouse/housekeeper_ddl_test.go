@@ -1,8 +1,10 @@
 package clickhouse
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/pseudomuto/housekeeper/pkg/format"
 	"github.com/pseudomuto/housekeeper/pkg/parser"
 	"github.com/stretchr/testify/require"
 )
@@ -262,3 +264,108 @@ func extractClusterInfo(stmt *parser.Statement) ClusterExpectation {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestInjectOnClusterOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			name:     "create function gets ON CLUSTER",
+			sql:      "CREATE FUNCTION normalize AS (x) -> lower(x);",
+			expected: "CREATE FUNCTION `normalize` ON CLUSTER `production` AS (`x`) -> lower(`x`);",
+		},
+		{
+			name:     "create function keeps existing cluster",
+			sql:      "CREATE FUNCTION normalize ON CLUSTER analytics AS (x) -> lower(x);",
+			expected: "CREATE FUNCTION `normalize` ON CLUSTER `analytics` AS (`x`) -> lower(`x`);",
+		},
+		{
+			name:     "drop function gets ON CLUSTER",
+			sql:      "DROP FUNCTION IF EXISTS normalize;",
+			expected: "DROP FUNCTION IF EXISTS `normalize` ON CLUSTER `production`;",
+		},
+		{
+			name:     "system reload dictionary gets ON CLUSTER",
+			sql:      "SYSTEM RELOAD DICTIONARY analytics.users;",
+			expected: "SYSTEM RELOAD DICTIONARY ON CLUSTER `production` `analytics`.`users`;",
+		},
+		{
+			name:     "system reload dictionary on housekeeper does NOT get ON CLUSTER",
+			sql:      "SYSTEM RELOAD DICTIONARY housekeeper.lookup;",
+			expected: "SYSTEM RELOAD DICTIONARY `housekeeper`.`lookup`;",
+		},
+		{
+			name:     "system reload dictionaries gets ON CLUSTER",
+			sql:      "SYSTEM RELOAD DICTIONARIES;",
+			expected: "SYSTEM RELOAD DICTIONARIES ON CLUSTER `production`;",
+		},
+		{
+			name:     "system reload config gets ON CLUSTER",
+			sql:      "SYSTEM RELOAD CONFIG;",
+			expected: "SYSTEM RELOAD CONFIG ON CLUSTER `production`;",
+		},
+		{
+			name:     "system flush logs gets ON CLUSTER",
+			sql:      "SYSTEM FLUSH LOGS;",
+			expected: "SYSTEM FLUSH LOGS ON CLUSTER `production`;",
+		},
+		{
+			name:     "system sync replica gets ON CLUSTER",
+			sql:      "SYSTEM SYNC REPLICA analytics.events STRICT;",
+			expected: "SYSTEM SYNC REPLICA ON CLUSTER `production` `analytics`.`events` STRICT;",
+		},
+		{
+			name:     "system sync replica on housekeeper does NOT get ON CLUSTER",
+			sql:      "SYSTEM SYNC REPLICA housekeeper.revisions;",
+			expected: "SYSTEM SYNC REPLICA `housekeeper`.`revisions`;",
+		},
+		{
+			name:     "truncate gets ON CLUSTER",
+			sql:      "TRUNCATE TABLE IF EXISTS events SYNC;",
+			expected: "TRUNCATE TABLE IF EXISTS `events` ON CLUSTER `production` SYNC;",
+		},
+		{
+			name:     "truncate on housekeeper does NOT get ON CLUSTER",
+			sql:      "TRUNCATE TABLE housekeeper.revisions;",
+			expected: "TRUNCATE TABLE `housekeeper`.`revisions`;",
+		},
+		{
+			name:     "exchange tables gets ON CLUSTER",
+			sql:      "EXCHANGE TABLES analytics.events AND analytics.events_v2;",
+			expected: "EXCHANGE TABLES `analytics`.`events` AND `analytics`.`events_v2` ON CLUSTER `production`;",
+		},
+		{
+			name:     "exchange tables with housekeeper does NOT get ON CLUSTER",
+			sql:      "EXCHANGE TABLES housekeeper.revisions AND analytics.revisions;",
+			expected: "EXCHANGE TABLES `housekeeper`.`revisions` AND `analytics`.`revisions`;",
+		},
+		{
+			name:     "exchange dictionaries gets ON CLUSTER",
+			sql:      "EXCHANGE DICTIONARIES analytics.users AND analytics.users_v2;",
+			expected: "EXCHANGE DICTIONARIES `analytics`.`users` AND `analytics`.`users_v2` ON CLUSTER `production`;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parser.ParseString(tt.sql)
+			require.NoError(t, err)
+
+			result := injectOnCluster(parsed.Statements, "production")
+
+			var buf bytes.Buffer
+			require.NoError(t, format.Format(&buf, format.Defaults, result...))
+			require.Equal(t, tt.expected, buf.String())
+
+			// The injected clause must survive a round trip through the parser
+			reparsed, err := parser.ParseString(buf.String())
+			require.NoError(t, err)
+
+			buf.Reset()
+			require.NoError(t, format.Format(&buf, format.Defaults, reparsed.Statements...))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/consts"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/utils"
+)
+
+// secretKeyPatterns contains substrings of named collection keys whose values are credentials.
+// Their values are masked in extracted schemas, even when the server is configured to display them.
+var secretKeyPatterns = []string{
+	"password",
+	"secret",
+	"token",
+	"credential",
+	"private_key",
+}
+
+// GetNamedCollections retrieves all named collection definitions from the ClickHouse instance.
+// It queries the system.named_collections table and reconstructs CREATE NAMED COLLECTION
+// statements from each collection's key/value pairs.
+//
+// Secret values are never included in the generated statements: values of credential keys
+// (passwords, secrets, tokens, etc.) are replaced with consts.HiddenSecretValue, the same
+// placeholder ClickHouse reports when the user can't see named collection secrets.
+//
+// Returns a *parser.SQL containing all named collection CREATE statements, or an error if the query fails.
+func (c *Client) GetNamedCollections(ctx context.Context) (*parser.SQL, error) {
+	query := `
+		SELECT
+			name,
+			collection
+		FROM system.named_collections
+		ORDER BY name
+	`
+
+	rows, err := c.conn.Query(ctx, query)
+	if err != nil {
+		// Only ignore "table doesn't exist" errors for backward compatibility with older ClickHouse versions
+		if isTableNotFoundError(err, "system.named_collections") {
+			return &parser.SQL{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to query system.named_collections")
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var (
+			name       string
+			collection map[string]string
+		)
+		if err := rows.Scan(&name, &collection); err != nil {
+			return nil, errors.Wrap(err, "failed to scan named collection row")
+		}
+
+		statements = append(statements, buildCreateNamedCollection(name, collection))
+	}
+
+	if len(statements) == 0 {
+		return &parser.SQL{}, nil
+	}
+
+	// Parse the generated SQL
+	sql := strings.Join(statements, "\n\n")
+	parsedSQL, err := parser.ParseString(sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse generated named collection SQL")
+	}
+
+	return parsedSQL, nil
+}
+
+// buildCreateNamedCollection builds a CREATE NAMED COLLECTION statement with the keys of
+// the collection in sorted order, masking the values of secret keys
+func buildCreateNamedCollection(name string, collection map[string]string) string {
+	keys := make([]string, 0, len(collection))
+	for key := range collection {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	params := make([]string, len(keys))
+	for i, key := range keys {
+		value := collection[key]
+		if isSecretNamedCollectionKey(key) {
+			value = consts.HiddenSecretValue
+		}
+
+		params[i] = fmt.Sprintf("%s = '%s'", utils.BacktickIdentifier(key), escapeSQLString(value))
+	}
+
+	return fmt.Sprintf("CREATE NAMED COLLECTION %s AS %s;", utils.BacktickIdentifier(name), strings.Join(params, ", "))
+}
+
+// isSecretNamedCollectionKey reports whether the value of a named collection key is a credential
+func isSecretNamedCollectionKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range secretKeyPatterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapeSQLString escapes backslashes and single quotes for use in a single-quoted SQL string
+func escapeSQLString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
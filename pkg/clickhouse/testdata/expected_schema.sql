@@ -2,6 +2,12 @@ CREATE DATABASE `analytics` ON CLUSTER `test_cluster` ENGINE = Atomic COMMENT 'A
 
 CREATE DATABASE `user_data` ON CLUSTER `test_cluster` ENGINE = Atomic COMMENT 'User-related data storage';
 
+CREATE NAMED COLLECTION `postgres_conn` ON CLUSTER `test_cluster` AS
+    `host` = 'postgres',
+    `password` = '[HIDDEN]',
+    `port` = '5432',
+    `user` = 'reader';
+
 CREATE TABLE `analytics`.`daily_summary` ON CLUSTER `test_cluster` (
     `date`                 Date,
     `total_events`         UInt64,
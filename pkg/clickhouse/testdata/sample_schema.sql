@@ -6,6 +6,9 @@ CREATE DATABASE analytics ON CLUSTER test_cluster ENGINE = Atomic COMMENT 'Analy
 
 CREATE DATABASE user_data ON CLUSTER test_cluster ENGINE = Atomic COMMENT 'User-related data storage';
 
+-- Create named collection with a secret that must be masked when dumped
+CREATE NAMED COLLECTION postgres_conn ON CLUSTER test_cluster AS host = 'postgres', port = 5432, user = 'reader', password = 'hunter2';
+
 -- Create replicated tables with ON CLUSTER
 CREATE TABLE analytics.events ON CLUSTER test_cluster (
     id UInt64,
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/fx"
+)
+
+type bootstrapRevisionsParams struct {
+	fx.In
+
+	Config    *config.Config
+	Formatter *format.Formatter
+	Version   *Version
+}
+
+// bootstrapRevisions returns a CLI command that creates the housekeeper revision
+// tracking infrastructure (the housekeeper database and revisions table) without
+// running any migrations.
+//
+// This is the adoption path for databases whose schema already exists: with
+// --baseline-applied, every migration up to and including the given version is
+// recorded as applied without being executed, so later migrate runs only apply
+// what comes after the baseline.
+//
+// Command flags:
+//   - --url, -u: ClickHouse connection string (required)
+//   - --baseline-applied: Record migrations up to this version as applied
+//   - --cluster: ClickHouse cluster name for distributed deployments (as with migrate)
+//   - --cafile, --certfile, --keyfile: Certificates for connecting via mtls (as with migrate)
+//
+// Example usage:
+//
+//	# Create the revisions table only
+//	housekeeper bootstrap-revisions --url localhost:9000
+//
+//	# Adopt an existing database whose schema matches migration 20240101120000
+//	housekeeper bootstrap-revisions --url localhost:9000 --baseline-applied 20240101120000
+//
+//	# Adopt a production cluster, connecting via mtls
+//	housekeeper bootstrap-revisions --url prod:9440 --cluster production_cluster \
+//	  --certfile /cert/tls.crt --cafile /cert/ca.crt --keyfile /cert/tls.key --baseline-applied 20240101120000
+func bootstrapRevisions(p bootstrapRevisionsParams) *cli.Command {
+	return &cli.Command{
+		Name:   "bootstrap-revisions",
+		Usage:  "Create the revisions table and optionally baseline already-applied migrations",
+		Before: requireConfig(p.Config),
+		Flags: []cli.Flag{
+			urlFlag,
+			&cli.StringFlag{
+				Name:  "baseline-applied",
+				Usage: "Record migrations up to and including this version as applied without executing them",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			clusterFlag,
+			caFileFlag,
+			certFileFlag,
+			keyFileFlag,
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			client, err := clickhouse.NewClientWithOptions(ctx, cmd.String("url"), clientOptions(cmd))
+			if err != nil {
+				return errors.Wrap(err, "failed to create ClickHouse client")
+			}
+			defer client.Close()
+
+			return runBootstrapRevisions(ctx, cmd.Writer, client, p, cmd.String("baseline-applied"))
+		},
+	}
+}
+
+// runBootstrapRevisions creates the revision tracking infrastructure and, when baseline
+// is set, marks the migrations up to and including baseline as applied.
+func runBootstrapRevisions(ctx context.Context, w io.Writer, ch executor.ClickHouse, p bootstrapRevisionsParams, baseline string) error {
+	// Resolve the baseline before touching the server so a typo doesn't leave a half-adopted database
+	var migrations []*migrator.Migration
+	if baseline != "" {
+		migrationDir, err := migrator.LoadMigrationDir(os.DirFS(p.Config.Dir))
+		if err != nil {
+			return errors.Wrap(err, "failed to load migrations")
+		}
+
+		migrations, err = migrationDir.MigrationsThrough(baseline)
+		if err != nil {
+			return errors.Wrap(err, "invalid baseline")
+		}
+	}
+
+	housekeeperVersion := ""
+	if p.Version != nil {
+		housekeeperVersion = p.Version.Version
+	}
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         ch,
+		Formatter:          p.Formatter,
+		HousekeeperVersion: housekeeperVersion,
+	})
+
+	if baseline == "" {
+		if err := exec.Bootstrap(ctx); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, "✅ Housekeeper revision tracking initialized")
+		return nil
+	}
+
+	results, err := exec.MarkApplied(ctx, migrations)
+	for _, result := range results {
+		if result.Status == executor.StatusSkipped {
+			fmt.Fprintf(w, "  ⏭  %s (already applied)\n", result.Version)
+			continue
+		}
+		fmt.Fprintf(w, "  ✓ %s (marked as applied)\n", result.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n✅ Housekeeper revision tracking initialized with %d migration(s) baselined through %s\n", len(results), baseline)
+	return nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/stretchr/testify/require"
+)
+
+// revisionsClickHouse is a ClickHouse stub for a server with no housekeeper
+// infrastructure. It records every executed statement and revision insert.
+type revisionsClickHouse struct {
+	execs    []string
+	inserted []string
+}
+
+func (c *revisionsClickHouse) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	return &emptyRows{}, nil
+}
+
+func (c *revisionsClickHouse) Exec(ctx context.Context, query string, args ...any) error {
+	c.execs = append(c.execs, query)
+	if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+		c.inserted = append(c.inserted, args[0].(string))
+	}
+	return nil
+}
+
+// emptyRows is a driver.Rows without any rows
+type emptyRows struct {
+	driver.Rows
+}
+
+func (r *emptyRows) Next() bool   { return false }
+func (r *emptyRows) Err() error   { return nil }
+func (r *emptyRows) Close() error { return nil }
+
+func TestBootstrapRevisionsCommand_Structure(t *testing.T) {
+	command := bootstrapRevisions(bootstrapRevisionsParams{})
+
+	require.Equal(t, "bootstrap-revisions", command.Name)
+	require.NotNil(t, command.Before)
+	require.NotNil(t, command.Action)
+
+	var flagNames []string
+	for _, flag := range command.Flags {
+		flagNames = append(flagNames, flag.Names()[0])
+	}
+	require.Equal(t, []string{"url", "baseline-applied", "cluster", "cafile", "certfile", "keyfile"}, flagNames)
+}
+
+func TestRunBootstrapRevisions(t *testing.T) {
+	fixture := testutil.TestProject(t).
+		WithMigrations([]testutil.MigrationFile{
+			{Version: "20240101120000_init", SQL: "CREATE DATABASE analytics ENGINE = Atomic;"},
+			{Version: "20240102120000_events", SQL: "CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;"},
+			{Version: "20240103120000_users", SQL: "CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;"},
+		})
+	defer fixture.Cleanup()
+	t.Chdir(fixture.Dir)
+
+	params := bootstrapRevisionsParams{
+		Config:    fixture.Config,
+		Formatter: format.New(format.Defaults),
+		Version:   &Version{Version: "1.0.0"},
+	}
+
+	t.Run("bootstrap only", func(t *testing.T) {
+		ch := &revisionsClickHouse{}
+		var buf bytes.Buffer
+
+		require.NoError(t, runBootstrapRevisions(context.Background(), &buf, ch, params, ""))
+		require.Len(t, ch.execs, 3)
+		require.Contains(t, ch.execs[0], "CREATE DATABASE IF NOT EXISTS `housekeeper`")
+		require.Contains(t, ch.execs[1], "CREATE TABLE IF NOT EXISTS `housekeeper`.`revisions`")
+		require.Contains(t, ch.execs[2], "CREATE TABLE IF NOT EXISTS `housekeeper`.`migration_lock`")
+		require.Empty(t, ch.inserted)
+		require.Contains(t, buf.String(), "Housekeeper revision tracking initialized")
+	})
+
+	t.Run("baseline marks migrations without executing them", func(t *testing.T) {
+		ch := &revisionsClickHouse{}
+		var buf bytes.Buffer
+
+		require.NoError(t, runBootstrapRevisions(context.Background(), &buf, ch, params, "20240102120000"))
+		require.Equal(t, []string{"20240101120000_init", "20240102120000_events"}, ch.inserted)
+		for _, query := range ch.execs {
+			require.NotContains(t, query, "analytics")
+		}
+
+		output := buf.String()
+		require.Contains(t, output, "20240101120000_init (marked as applied)")
+		require.Contains(t, output, "20240102120000_events (marked as applied)")
+		require.NotContains(t, output, "20240103120000_users")
+		require.Contains(t, output, "2 migration(s) baselined through 20240102120000")
+	})
+
+	t.Run("unknown baseline", func(t *testing.T) {
+		ch := &revisionsClickHouse{}
+		var buf bytes.Buffer
+
+		err := runBootstrapRevisions(context.Background(), &buf, ch, params, "20240105120000")
+		require.EqualError(t, err, "invalid baseline: migration not found: 20240105120000")
+		require.Empty(t, ch.execs, "nothing should be created for an invalid baseline")
+	})
+}
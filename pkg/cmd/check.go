@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/urfave/cli/v3"
+	"go.uber.org/fx"
+)
+
+type (
+	checkParams struct {
+		fx.In
+
+		Config    *config.Config
+		Formatter *format.Formatter
+	}
+
+	// syncReport captures every discrepancy between the local project and a
+	// ClickHouse instance. An empty report means the instance is fully in sync.
+	syncReport struct {
+		// Integrity is set when the migration directory fails sum file validation
+		Integrity error
+
+		// Pending lists migrations that have not been applied yet
+		Pending []*migrator.Migration
+
+		// Failed lists migrations whose last execution failed
+		Failed []*migrator.Migration
+
+		// Drift holds the statements required to bring the instance in line with
+		// the project schema (nil when there is no drift)
+		Drift *parser.SQL
+	}
+)
+
+// check creates the check command, a single pass/fail gate for deploy pipelines.
+//
+// The check command combines status, sum file validation, and drift detection
+// into one command. It exits successfully only when every local migration has
+// been applied, the migration directory matches its sum file, and the schema of
+// the ClickHouse instance matches the compiled project schema.
+//
+// With --all-envs (or one or more --env flags) the check runs against the
+// environments configured in housekeeper.yaml concurrently, using at most
+// --max-parallel connections at a time. Every environment is checked even when
+// others fail, and the results are aggregated into a single report.
+//
+// Command flags:
+//   - --url, -u: ClickHouse connection string (required unless environments are selected)
+//   - --cluster: ClickHouse cluster name for distributed deployments
+//   - --all-envs: Check every configured environment
+//   - --env: Check the named environment (repeatable)
+//   - --max-parallel: Maximum number of environments checked concurrently (default: 4)
+//
+// Example usage:
+//
+//	# Fail the pipeline unless production is fully in sync
+//	housekeeper check --url prod-host:9000
+//
+//	# Confirm every environment is in sync in one pass
+//	housekeeper check --all-envs
+func check(p checkParams) *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Verify migrations are applied, valid, and free of drift",
+		Description: `Verify that the specified ClickHouse instance is fully in sync with the project.
+
+The check command fails when any of the following are found:
+- Pending migrations that have not been applied
+- Failed migrations
+- Migration files that no longer match housekeeper.sum
+- Schema drift between the instance and the compiled project schema
+
+Each discrepancy is described in the report and results in a non-zero exit code,
+making this command suitable as a gate in deploy pipelines.
+
+Use --all-envs or --env to check the environments configured in housekeeper.yaml
+concurrently. A failure in one environment doesn't stop the others from being
+checked.`,
+		Before: requireConfig(p.Config),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    urlFlag.Name,
+				Aliases: urlFlag.Aliases,
+				Usage:   urlFlag.Usage,
+				Sources: urlFlag.Sources,
+				Config:  urlFlag.Config,
+			},
+			&cli.StringFlag{
+				Name:  "cluster",
+				Usage: "ClickHouse cluster name for distributed deployments",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "all-envs",
+				Usage: "Check every environment configured in housekeeper.yaml",
+			},
+			&cli.StringSliceFlag{
+				Name:  "env",
+				Usage: "Check the named environment from housekeeper.yaml (repeatable)",
+			},
+			&cli.IntFlag{
+				Name:  "max-parallel",
+				Usage: "Maximum number of environments checked concurrently",
+				Value: defaultMaxParallelChecks,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Bool("all-envs") || len(cmd.StringSlice("env")) > 0 {
+				return runCheckEnvironments(ctx, cmd, p)
+			}
+
+			if cmd.String("url") == "" {
+				return errors.New("--url is required unless --all-envs or --env is used")
+			}
+
+			return runCheck(ctx, cmd, p)
+		},
+	}
+}
+
+func runCheck(ctx context.Context, cmd *cli.Command, p checkParams) error {
+	report, err := checkEnvironment(ctx, p.Config, cmd.String("url"), cmd.String("cluster"))
+	if err != nil {
+		return err
+	}
+
+	if err := report.Write(cmd.Writer, p.Formatter); err != nil {
+		return err
+	}
+
+	return report.Err()
+}
+
+// checkEnvironment connects to the instance at url and builds a sync report
+// comparing it to the project.
+func checkEnvironment(ctx context.Context, cfg *config.Config, url, cluster string) (*syncReport, error) {
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(cfg.Dir))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load migrations")
+	}
+
+	client, err := clickhouse.NewClientWithOptions(ctx, url, clickhouse.ClientOptions{
+		Cluster:         cluster,
+		IgnoreDatabases: cfg.ClickHouse.IgnoreDatabases,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ClickHouse client")
+	}
+	defer client.Close()
+
+	if err := testConnection(ctx, client); err != nil {
+		return nil, errors.Wrap(err, "failed to connect to ClickHouse")
+	}
+
+	bootstrapped, err := checkBootstrapStatus(ctx, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check bootstrap status")
+	}
+
+	// Without the revisions table every migration is pending
+	revisionSet := migrator.NewRevisionSet(nil)
+	if bootstrapped {
+		revisionSet, err = migrator.LoadRevisions(ctx, client)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load revisions")
+		}
+	}
+
+	current, err := client.GetSchema(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current schema from ClickHouse")
+	}
+
+	target, err := compileProjectSchema(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSyncReport(migrationDir, revisionSet, current, target, schemapkg.CompareOptions{DefaultCodec: cfg.ClickHouse.DefaultCodec})
+}
+
+func runCheckEnvironments(ctx context.Context, cmd *cli.Command, p checkParams) error {
+	envs, err := p.Config.GetEnvironments(cmd.StringSlice("env"))
+	if err != nil {
+		return err
+	}
+
+	if len(envs) == 0 {
+		return errors.New("no environments configured in housekeeper.yaml")
+	}
+
+	results := checkEnvironments(ctx, envs, int(cmd.Int("max-parallel")), func(ctx context.Context, env config.Environment) (*syncReport, error) {
+		cluster := env.Cluster
+		if cluster == "" {
+			cluster = cmd.String("cluster")
+		}
+
+		return checkEnvironment(ctx, p.Config, env.DSN(), cluster)
+	})
+
+	return writeEnvironmentResults(cmd.Writer, p.Formatter, results)
+}
+
+// defaultMaxParallelChecks is the default number of environments checked concurrently
+const defaultMaxParallelChecks = 4
+
+// environmentResult is the outcome of checking a single environment. Err is set
+// when the environment couldn't be checked at all (e.g. it was unreachable).
+type environmentResult struct {
+	Environment config.Environment
+	Report      *syncReport
+	Err         error
+}
+
+// checkEnvironments runs checkFn for every environment concurrently, with at most
+// maxParallel checks in flight. Each environment is checked independently, so a
+// failure in one doesn't abort the others. Results are returned in the same order
+// as envs.
+func checkEnvironments(
+	ctx context.Context,
+	envs []config.Environment,
+	maxParallel int,
+	checkFn func(context.Context, config.Environment) (*syncReport, error),
+) []environmentResult {
+	results := make([]environmentResult, len(envs))
+	errs := forEachEnvironment(envs, maxParallel, func(i int, env config.Environment) (err error) {
+		results[i].Report, err = checkFn(ctx, env)
+		return err
+	})
+
+	for i, env := range envs {
+		results[i].Environment = env
+		results[i].Err = errs[i]
+	}
+
+	return results
+}
+
+// forEachEnvironment calls fn for every environment concurrently, with at most
+// maxParallel calls in flight, and returns the error of each call in the same order as
+// envs. A panic in fn is recovered and returned as the error of its environment, so it
+// doesn't take down the others; deferred cleanup in fn has run by then.
+func forEachEnvironment(envs []config.Environment, maxParallel int, fn func(int, config.Environment) error) []error {
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	errs := make([]error, len(envs))
+	sem := make(chan struct{}, maxParallel)
+
+	var wg sync.WaitGroup
+	for i, env := range envs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = errors.Errorf("panic: %v", r)
+				}
+			}()
+
+			errs[i] = fn(i, env)
+		}()
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// writeEnvironmentResults prints the report for each environment and returns an
+// error naming every environment that is out of sync or couldn't be checked.
+func writeEnvironmentResults(w io.Writer, formatter *format.Formatter, results []environmentResult) error {
+	var failed []string
+	for _, result := range results {
+		fmt.Fprintf(w, "=== %s ===\n", result.Environment.Name)
+
+		if result.Err != nil {
+			fmt.Fprintf(w, "❌ Check failed: %v\n", result.Err)
+			failed = append(failed, result.Environment.Name)
+			continue
+		}
+
+		if err := result.Report.Write(w, formatter); err != nil {
+			return err
+		}
+
+		if !result.Report.InSync() {
+			failed = append(failed, result.Environment.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d environment(s) out of sync: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// newSyncReport compares the migration directory, the recorded revisions, and
+// the current schema of an instance against the target project schema, comparing
+// schema objects according to opts.
+func newSyncReport(
+	migrationDir *migrator.MigrationDir,
+	revisionSet *migrator.RevisionSet,
+	current *parser.SQL,
+	target []*parser.Statement,
+	opts schemapkg.CompareOptions,
+) (*syncReport, error) {
+	report := &syncReport{Failed: revisionSet.GetFailed(migrationDir)}
+
+	// Failed migrations are also pending (they will be retried), only report them once
+	for _, migration := range revisionSet.GetPending(migrationDir) {
+		if !revisionSet.IsFailed(migration) {
+			report.Pending = append(report.Pending, migration)
+		}
+	}
+
+	valid, err := migrationDir.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate migrations")
+	}
+	if !valid {
+		report.Integrity = &migrator.ErrIntegrityMismatch{Reason: "migration directory failed validation - files have been modified"}
+	}
+
+	drift, err := schemapkg.GenerateDiffWithOptions(current, &parser.SQL{Statements: target}, opts)
+	if err != nil && !errors.Is(err, schemapkg.ErrNoDiff) {
+		return nil, errors.Wrap(err, "failed to compare schemas")
+	}
+	report.Drift = drift
+
+	return report, nil
+}
+
+// InSync returns true when the report contains no discrepancies.
+func (r *syncReport) InSync() bool {
+	return len(r.problems()) == 0
+}
+
+// Err returns an error summarizing every discrepancy, or nil when in sync.
+func (r *syncReport) Err() error {
+	problems := r.problems()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.Errorf("instance is out of sync: %s", strings.Join(problems, ", "))
+}
+
+// Write prints a concise, human readable report to w.
+func (r *syncReport) Write(w io.Writer, formatter *format.Formatter) error {
+	if r.InSync() {
+		fmt.Fprintln(w, "✅ In sync: all migrations applied, sum file valid, no schema drift")
+		return nil
+	}
+
+	if r.Integrity != nil {
+		fmt.Fprintf(w, "❌ Integrity: %v\n", r.Integrity)
+	}
+
+	if len(r.Failed) > 0 {
+		fmt.Fprintf(w, "❌ Failed migrations (%d):\n", len(r.Failed))
+		for _, migration := range r.Failed {
+			fmt.Fprintf(w, "  %s\n", migration.Version)
+		}
+	}
+
+	if len(r.Pending) > 0 {
+		fmt.Fprintf(w, "⏳ Pending migrations (%d):\n", len(r.Pending))
+		for _, migration := range r.Pending {
+			fmt.Fprintf(w, "  %s\n", migration.Version)
+		}
+	}
+
+	if r.hasDrift() {
+		fmt.Fprintln(w, "❌ Schema drift detected. Changes required to match the project schema:")
+		if err := formatter.Format(w, r.Drift.Statements...); err != nil {
+			return errors.Wrap(err, "failed to format schema drift")
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func (r *syncReport) hasDrift() bool {
+	return r.Drift != nil && len(r.Drift.Statements) > 0
+}
+
+func (r *syncReport) problems() []string {
+	var problems []string
+	if r.Integrity != nil {
+		problems = append(problems, "integrity check failed")
+	}
+	if len(r.Failed) > 0 {
+		problems = append(problems, fmt.Sprintf("%d failed migration(s)", len(r.Failed)))
+	}
+	if len(r.Pending) > 0 {
+		problems = append(problems, fmt.Sprintf("%d pending migration(s)", len(r.Pending)))
+	}
+	if r.hasDrift() {
+		problems = append(problems, "schema drift detected")
+	}
+
+	return problems
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	checkInitSQL  = "CREATE DATABASE test ENGINE = Atomic;"
+	checkUsersSQL = "CREATE TABLE test.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;"
+)
+
+func TestCheckCommand_Structure(t *testing.T) {
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	command := check(checkParams{Config: fixture.Config, Formatter: format.New(format.Defaults)})
+
+	require.Equal(t, "check", command.Name)
+	require.Equal(t, "Verify migrations are applied, valid, and free of drift", command.Usage)
+	require.NotNil(t, command.Before)
+	require.NotNil(t, command.Action)
+	require.Len(t, command.Flags, 5)
+
+	var flagNames []string
+	for _, flag := range command.Flags {
+		flagNames = append(flagNames, flag.Names()[0])
+	}
+	require.Equal(t, []string{"url", "cluster", "all-envs", "env", "max-parallel"}, flagNames)
+}
+
+func TestCheckEnvironments(t *testing.T) {
+	projectSchema := checkInitSQL + "\n" + checkUsersSQL
+	completed := []*migrator.Revision{completedRevision("001_init", 1), completedRevision("002_users", 1)}
+
+	// Each mock environment reports a different state
+	states := map[string]struct {
+		revisions []*migrator.Revision
+		current   string
+		err       error
+	}{
+		"dev":     {revisions: completed, current: checkInitSQL},
+		"staging": {revisions: completed[:1], current: projectSchema},
+		"prod":    {revisions: completed, current: projectSchema},
+		"qa":      {err: errors.New("connection refused")},
+	}
+
+	envs := []config.Environment{
+		{Name: "dev", URL: "dev-host:9000"},
+		{Name: "staging", URL: "staging-host:9000"},
+		{Name: "prod", URL: "prod-host:9000"},
+		{Name: "qa", URL: "qa-host:9000"},
+	}
+
+	migrationDir := checkMigrationDir(t, false)
+	target, err := parser.ParseString(projectSchema)
+	require.NoError(t, err)
+
+	var inFlight, maxInFlight atomic.Int32
+	results := checkEnvironments(context.Background(), envs, 2, func(ctx context.Context, env config.Environment) (*syncReport, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		state := states[env.Name]
+		if state.err != nil {
+			return nil, state.err
+		}
+
+		current, err := parser.ParseString(state.current)
+		if err != nil {
+			return nil, err
+		}
+
+		return newSyncReport(migrationDir, migrator.NewRevisionSet(state.revisions), current, target.Statements, schemapkg.CompareOptions{})
+	})
+
+	require.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	require.Len(t, results, 4)
+	for i, result := range results {
+		require.Equal(t, envs[i], result.Environment)
+	}
+
+	require.NoError(t, results[0].Err)
+	require.True(t, results[0].Report.hasDrift())
+	require.Len(t, results[1].Report.Pending, 1)
+	require.True(t, results[2].Report.InSync())
+	require.EqualError(t, results[3].Err, "connection refused")
+
+	var buf bytes.Buffer
+	err = writeEnvironmentResults(&buf, format.New(format.Defaults), results)
+	require.EqualError(t, err, "3 of 4 environment(s) out of sync: dev, staging, qa")
+
+	output := buf.String()
+	require.Contains(t, output, "=== dev ===\n❌ Schema drift detected")
+	require.Contains(t, output, "=== staging ===\n⏳ Pending migrations (1):")
+	require.Contains(t, output, "=== prod ===\n✅ In sync")
+	require.Contains(t, output, "=== qa ===\n❌ Check failed: connection refused")
+}
+
+func TestForEachEnvironment_Panic(t *testing.T) {
+	envs := []config.Environment{{Name: "dev"}, {Name: "staging"}, {Name: "prod"}}
+
+	var cleanedUp atomic.Int32
+	errs := forEachEnvironment(envs, 2, func(_ int, env config.Environment) error {
+		defer cleanedUp.Add(1)
+
+		switch env.Name {
+		case "staging":
+			panic("boom")
+		case "prod":
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	require.NoError(t, errs[0])
+	require.EqualError(t, errs[1], "panic: boom")
+	require.EqualError(t, errs[2], "connection refused")
+	require.Equal(t, int32(3), cleanedUp.Load(), "deferred cleanup runs for every environment, including the one that panicked")
+}
+
+func TestWriteEnvironmentResults_AllInSync(t *testing.T) {
+	results := []environmentResult{
+		{Environment: config.Environment{Name: "dev"}, Report: &syncReport{}},
+		{Environment: config.Environment{Name: "prod"}, Report: &syncReport{}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeEnvironmentResults(&buf, format.New(format.Defaults), results))
+	require.Contains(t, buf.String(), "=== prod ===\n✅ In sync")
+}
+
+func TestSyncReport(t *testing.T) {
+	projectSchema := checkInitSQL + "\n" + checkUsersSQL
+
+	tests := []struct {
+		name      string
+		modified  bool
+		revisions []*migrator.Revision
+		current   string
+		expectErr string
+		contains  []string
+	}{
+		{
+			name:      "in sync",
+			revisions: []*migrator.Revision{completedRevision("001_init", 1), completedRevision("002_users", 1)},
+			current:   projectSchema,
+			contains:  []string{"✅ In sync"},
+		},
+		{
+			name:      "pending migration",
+			revisions: []*migrator.Revision{completedRevision("001_init", 1)},
+			current:   projectSchema,
+			expectErr: "instance is out of sync: 1 pending migration(s)",
+			contains:  []string{"⏳ Pending migrations (1):", "002_users"},
+		},
+		{
+			name: "failed migration",
+			revisions: []*migrator.Revision{
+				completedRevision("001_init", 1),
+				failedRevision("002_users", "table already exists"),
+			},
+			current:   projectSchema,
+			expectErr: "instance is out of sync: 1 failed migration(s)",
+			contains:  []string{"❌ Failed migrations (1):", "002_users"},
+		},
+		{
+			name:      "integrity failure",
+			modified:  true,
+			revisions: []*migrator.Revision{completedRevision("001_init", 1), completedRevision("002_users", 1)},
+			current:   projectSchema,
+			expectErr: "instance is out of sync: integrity check failed",
+			contains:  []string{"❌ Integrity: integrity check failed"},
+		},
+		{
+			name:      "schema drift",
+			revisions: []*migrator.Revision{completedRevision("001_init", 1), completedRevision("002_users", 1)},
+			current:   checkInitSQL,
+			expectErr: "instance is out of sync: schema drift detected",
+			contains:  []string{"❌ Schema drift detected", "CREATE TABLE `test`.`users`"},
+		},
+		{
+			name:      "multiple discrepancies",
+			modified:  true,
+			revisions: nil,
+			current:   "",
+			expectErr: "instance is out of sync: integrity check failed, 2 pending migration(s), schema drift detected",
+			contains:  []string{"❌ Integrity", "⏳ Pending migrations (2):", "❌ Schema drift detected"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrationDir := checkMigrationDir(t, tt.modified)
+
+			current, err := parser.ParseString(tt.current)
+			require.NoError(t, err)
+
+			target, err := parser.ParseString(projectSchema)
+			require.NoError(t, err)
+
+			report, err := newSyncReport(migrationDir, migrator.NewRevisionSet(tt.revisions), current, target.Statements, schemapkg.CompareOptions{})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, report.Write(&buf, format.New(format.Defaults)))
+
+			if tt.expectErr != "" {
+				require.False(t, report.InSync())
+				require.EqualError(t, report.Err(), tt.expectErr)
+			} else {
+				require.True(t, report.InSync())
+				require.NoError(t, report.Err())
+			}
+
+			for _, expected := range tt.contains {
+				require.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
+
+// checkMigrationDir loads a two migration directory. When modified is true, the
+// users migration is changed after the sum file was generated.
+func checkMigrationDir(t *testing.T, modified bool) *migrator.MigrationDir {
+	t.Helper()
+
+	files := fstest.MapFS{
+		"001_init.sql":  {Data: []byte(checkInitSQL)},
+		"002_users.sql": {Data: []byte(checkUsersSQL)},
+	}
+
+	migrationDir, err := migrator.LoadMigrationDir(files)
+	require.NoError(t, err)
+
+	var sum bytes.Buffer
+	_, err = migrationDir.SumFile.WriteTo(&sum)
+	require.NoError(t, err)
+	files["housekeeper.sum"] = &fstest.MapFile{Data: sum.Bytes()}
+
+	if modified {
+		files["002_users.sql"] = &fstest.MapFile{
+			Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;"),
+		}
+	}
+
+	migrationDir, err = migrator.LoadMigrationDir(files)
+	require.NoError(t, err)
+
+	return migrationDir
+}
+
+func completedRevision(version string, statements int) *migrator.Revision {
+	return &migrator.Revision{
+		Version: version,
+		Kind:    migrator.StandardRevision,
+		Applied: statements,
+		Total:   statements,
+	}
+}
+
+func failedRevision(version, msg string) *migrator.Revision {
+	return &migrator.Revision{
+		Version: version,
+		Kind:    migrator.StandardRevision,
+		Error:   &msg,
+		Total:   1,
+	}
+}
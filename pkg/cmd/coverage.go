@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/urfave/cli/v3"
+)
+
+// coverage returns a CLI command that reports how much of a live ClickHouse
+// instance is managed by the project schema.
+//
+// Every database, table, dictionary, view, function, and role on the server is
+// matched against the compiled project schema by type and name. Objects in
+// databases listed in ignore_databases (and housekeeper's own tracking database)
+// aren't counted. This is useful for tracking progress when incrementally
+// bringing an existing database under management.
+//
+// Command flags:
+//   - --url, -u: ClickHouse connection string (required unless --env is used)
+//   - --env: Use the URL and cluster of the named environment from housekeeper.yaml
+//
+// Example usage:
+//
+//	# Report coverage for a server
+//	housekeeper coverage --url localhost:9000
+//
+//	# Report coverage for the prod environment
+//	housekeeper coverage --env prod
+func coverage(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "coverage",
+		Usage:  "Report which objects in a live database are managed by the project schema",
+		Before: requireConfig(cfg),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    urlFlag.Name,
+				Aliases: urlFlag.Aliases,
+				Usage:   urlFlag.Usage,
+				Sources: urlFlag.Sources,
+				Config:  urlFlag.Config,
+			},
+			&cli.StringFlag{
+				Name:  "env",
+				Usage: "Use the URL and cluster of the named environment from housekeeper.yaml",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			url, cluster := cmd.String("url"), ""
+			if name := cmd.String("env"); name != "" {
+				envs, err := cfg.GetEnvironments([]string{name})
+				if err != nil {
+					return err
+				}
+
+				cluster = envs[0].Cluster
+				if !cmd.IsSet("url") {
+					url = envs[0].DSN()
+				}
+			}
+
+			if url == "" {
+				return errors.New("--url is required unless --env is used")
+			}
+
+			client, err := clickhouse.NewClientWithOptions(ctx, url, clickhouse.ClientOptions{
+				Cluster:         cluster,
+				IgnoreDatabases: cfg.ClickHouse.IgnoreDatabases,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to create ClickHouse client")
+			}
+			defer client.Close()
+
+			live, err := client.GetSchema(ctx)
+			if err != nil {
+				return errors.Wrap(err, "failed to get current schema from ClickHouse")
+			}
+
+			report, err := schemaCoverage(cfg, live)
+			if err != nil {
+				return err
+			}
+
+			writeCoverage(cmd.Writer, report)
+			return nil
+		},
+	}
+}
+
+// schemaCoverage compiles the project schema and reports which objects in live it defines.
+func schemaCoverage(cfg *config.Config, live *parser.SQL) (*schemapkg.CoverageReport, error) {
+	target, err := compileProjectSchema(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return schemapkg.Coverage(live, &parser.SQL{Statements: target}), nil
+}
+
+// writeCoverage prints the coverage summary followed by the unmanaged objects.
+func writeCoverage(w io.Writer, report *schemapkg.CoverageReport) {
+	fmt.Fprintf(w, "Schema coverage: %.1f%% (%d of %d objects managed)\n",
+		report.Percent(), len(report.Managed), report.Total())
+
+	if len(report.Unmanaged) == 0 {
+		fmt.Fprintln(w, "✅ Every object is managed by the project schema")
+		return
+	}
+
+	fmt.Fprintf(w, "\nUnmanaged objects (%d):\n", len(report.Unmanaged))
+	for _, obj := range report.Unmanaged {
+		fmt.Fprintf(w, "  %-17s %s\n", obj.Type, obj.Name)
+	}
+}
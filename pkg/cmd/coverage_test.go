@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageCommand_Structure(t *testing.T) {
+	command := coverage(nil)
+
+	require.Equal(t, "coverage", command.Name)
+	require.NotNil(t, command.Before)
+	require.NotNil(t, command.Action)
+	require.Len(t, command.Flags, 2)
+	require.Equal(t, "url", command.Flags[0].Names()[0])
+	require.Equal(t, "env", command.Flags[1].Names()[0])
+}
+
+func TestSchemaCoverage(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+	`)
+	defer fixture.Cleanup()
+	t.Chdir(fixture.Dir)
+
+	t.Run("partially managed", func(t *testing.T) {
+		live, err := parser.ParseString(`
+			CREATE DATABASE analytics ENGINE = Atomic;
+			CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			CREATE TABLE analytics.sessions (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/users')) LAYOUT(HASHED()) LIFETIME(300);
+		`)
+		require.NoError(t, err)
+
+		report, err := schemaCoverage(fixture.Config, live)
+		require.NoError(t, err)
+		require.Len(t, report.Managed, 2)
+		require.Len(t, report.Unmanaged, 2)
+
+		var buf bytes.Buffer
+		writeCoverage(&buf, report)
+		output := buf.String()
+		require.Contains(t, output, "Schema coverage: 50.0% (2 of 4 objects managed)")
+		require.Contains(t, output, "Unmanaged objects (2):")
+		require.Contains(t, output, "table             analytics.sessions")
+		require.Contains(t, output, "dictionary        analytics.users_dict")
+	})
+
+	t.Run("fully managed", func(t *testing.T) {
+		live, err := parser.ParseString("CREATE DATABASE analytics ENGINE = Atomic;")
+		require.NoError(t, err)
+
+		report, err := schemaCoverage(fixture.Config, live)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		writeCoverage(&buf, report)
+		require.Contains(t, buf.String(), "Schema coverage: 100.0% (1 of 1 objects managed)")
+		require.Contains(t, buf.String(), "Every object is managed by the project schema")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
@@ -19,12 +20,162 @@ import (
 
 // diff creates a CLI command for generating schema migration files by comparing
 // the current database state with the target schema definition.
-func diff(cfg *config.Config, client docker.DockerClient) *cli.Command {
+//
+// Command flags:
+//   - --header: Header template for the generated migration (overrides migration_header in housekeeper.yaml)
+//   - --env: Environment name exposed to the header template
+//   - --description: Description exposed to the header template
+//   - --annotate-source: Precede each statement with a comment naming the schema file it came from
+//   - --check-view-targets: Warn when a materialized view's SELECT doesn't match its TO table's columns
+//   - --name: Name appended to the migration's timestamp (e.g. 20240101120000_init.sql)
+//   - --split-down: Write the statements reverting the migration to a paired <version>.down.sql file
+//   - --require-approval-for: Exit with code 3 when the migration has destructive (or, with modifying, any non-additive) changes
+//   - --risk: Print the risk score of the migration, weighing mutations by live row counts when --url is set
+//   - --initial: Generate the first migration from the full schema without starting a container
+//   - --reconcile: Supersede a pending migration with one generated against the live database
+//   - --url, -u: ClickHouse connection DSN of the live database (required with --reconcile, optional with --risk)
+//   - --default-codec: Compression codec of columns without a CODEC (overrides clickhouse.default_codec in housekeeper.yaml)
+//   - --detect-column-renames: Rename a dropped column to an added column with identical properties instead of dropping it
+//
+// Example usage:
+//
+//	# Generate a migration with a description in its header
+//	housekeeper diff --description "Add events table"
+//
+//	# Annotate each statement with its schema file, e.g. "-- from db/schemas/analytics/tables/events.sql"
+//	housekeeper diff --annotate-source
+//
+//	# Warn about materialized views whose SELECT doesn't line up with their TO table
+//	housekeeper diff --check-view-targets
+//
+//	# Generate the first migration of a new project, e.g. 20240101120000_init.sql
+//	housekeeper diff --initial --name init
+//
+//	# Also write the reverse migration, e.g. 20240101120000.down.sql, for rollbacks
+//	housekeeper diff --split-down
+//
+//	# Fail the pipeline with exit code 3 when the migration drops, recreates, or narrows anything
+//	housekeeper diff --require-approval-for destructive
+//
+//	# Print how risky the migration is to apply, using the row counts of the production tables
+//	housekeeper diff --risk --url prod-clickhouse:9000
+//
+//	# Replace a partially applied migration with one containing only the remaining changes
+//	housekeeper diff --reconcile 20240101120000 --url localhost:9000
+//
+//	# Treat columns without a CODEC as compressed with ZSTD(1), like the server does
+//	housekeeper diff --default-codec "ZSTD(1)"
+//
+//	# Rename user_id to account_id, keeping its data, rather than dropping it and adding an empty column
+//	housekeeper diff --detect-column-renames
+func diff(cfg *config.Config, client docker.DockerClient, version *Version) *cli.Command {
 	return &cli.Command{
 		Name:   "diff",
 		Usage:  "Generate any missing migrations",
 		Before: requireConfig(cfg),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "header",
+				Usage: "Header template (Go text/template) rendered as comments at the top of the migration",
+			},
+			&cli.StringFlag{
+				Name:  "env",
+				Usage: "Environment name exposed to the header template",
+			},
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "Description exposed to the header template",
+			},
+			&cli.BoolFlag{
+				Name:  "annotate-source",
+				Usage: "Precede each generated statement with a comment naming the schema file it came from",
+			},
+			&cli.BoolFlag{
+				Name:  "check-view-targets",
+				Usage: "Warn when a materialized view's SELECT columns don't match the columns of its TO table",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Name appended to the migration's timestamp (e.g. init produces 20240101120000_init.sql)",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "split-down",
+				Usage: "Write the statements reverting the migration to a paired <version>.down.sql file",
+			},
+			&cli.StringFlag{
+				Name:  "require-approval-for",
+				Usage: "Exit with code 3 when the migration has destructive changes, or with modifying, any change that isn't purely additive",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "risk",
+				Usage: "Print the risk score of the migration (low, medium, or high), using the row counts of the live database when --url is set",
+			},
+			&cli.BoolFlag{
+				Name:  "initial",
+				Usage: "Generate the first migration from the full schema without starting a container (requires an empty migrations directory)",
+			},
+			&cli.StringFlag{
+				Name:  "reconcile",
+				Usage: "Version of a pending migration to replace with one generated against the live database (requires --url)",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			&cli.StringFlag{
+				Name:    "url",
+				Aliases: []string{"u"},
+				Usage:   "ClickHouse connection DSN of the live database to reconcile against, or to read row counts from with --risk",
+				Sources: cli.EnvVars("HOUSEKEEPER_DATABASE_URL"),
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			&cli.StringFlag{
+				Name:  "default-codec",
+				Usage: "Compression codec (e.g. ZSTD(1)) the server uses for columns declared without a CODEC (defaults to LZ4)",
+				Config: cli.StringConfig{
+					TrimSpace: true,
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "detect-column-renames",
+				Usage: "Rename a dropped column to an added column with identical properties, keeping its data, instead of dropping it and adding the new column",
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			opts, err := migrationFileOptions(cmd, cfg, version)
+			if err != nil {
+				return err
+			}
+
+			review, err := newMigrationReview(ctx, cmd, cfg)
+			if err != nil {
+				return err
+			}
+
+			// The first migration is generated against an empty schema, so there's nothing to replay
+			if cmd.Bool("initial") {
+				if cmd.String("reconcile") != "" {
+					return errors.New("--initial and --reconcile can't be used together")
+				}
+				return initialDiff(cmd.Writer, cfg, opts, cmd.Bool("check-view-targets"), review)
+			}
+
+			// Reconciling compares against the live database instead of replaying migrations
+			if reconcile := cmd.String("reconcile"); reconcile != "" {
+				url := cmd.String("url")
+				if url == "" {
+					return errors.New("--reconcile requires --url to connect to the live database")
+				}
+				return reconcileDiff(ctx, cmd.Writer, cfg, opts, url, reconcile, review)
+			}
+
 			// 1. Start container, run migrations, get client
 			container, client, err := runContainer(ctx, cmd.Writer, docker.DockerOptions{
 				Version:   cfg.ClickHouse.Version,
@@ -42,14 +193,66 @@ func diff(cfg *config.Config, client docker.DockerClient) *cli.Command {
 			}()
 
 			// 2. Load project schema and generate diff
-			return generateDiff(ctx, cmd.Writer, client, cfg)
+			return generateDiff(ctx, cmd.Writer, client, cfg, opts, cmd.Bool("check-view-targets"), review)
 		},
 	}
 }
 
+// migrationFileOptions builds the options for the generated migration file from the diff flags
+func migrationFileOptions(cmd *cli.Command, cfg *config.Config, version *Version) (schemapkg.MigrationFileOptions, error) {
+	opts := schemapkg.MigrationFileOptions{
+		HeaderTemplate: cfg.MigrationHeader,
+		Environment:    cmd.String("env"),
+		Description:    cmd.String("description"),
+		Name:           cmd.String("name"),
+		SplitDown:      cmd.Bool("split-down"),
+		Compare:        compareOptions(cmd, cfg),
+	}
+	if cmd.IsSet("header") {
+		opts.HeaderTemplate = cmd.String("header")
+	}
+	if version != nil {
+		opts.ToolVersion = version.Version
+	}
+	if cmd.Bool("annotate-source") {
+		sources, err := schemapkg.CompileSources(cfg.Entrypoint)
+		if err != nil {
+			return opts, errors.Wrapf(err, "failed to compile schema sources from: %s", cfg.Entrypoint)
+		}
+		opts.Sources = sources
+	}
+
+	return opts, nil
+}
+
+// compareOptions builds the options comparing schema objects from the diff flags and the
+// project configuration. The --default-codec flag overrides clickhouse.default_codec.
+func compareOptions(cmd *cli.Command, cfg *config.Config) schemapkg.CompareOptions {
+	opts := schemapkg.CompareOptions{
+		DefaultCodec:        cfg.ClickHouse.DefaultCodec,
+		DetectColumnRenames: cmd.Bool("detect-column-renames"),
+	}
+	if cmd.IsSet("default-codec") {
+		opts.DefaultCodec = cmd.String("default-codec")
+	}
+
+	return opts
+}
+
 // generateDiff compares the current database schema with the target schema
-// and generates a migration file if differences are found.
-func generateDiff(ctx context.Context, w io.Writer, client *clickhouse.Client, cfg *config.Config) error {
+// and generates a migration file if differences are found. When checkViewTargets is
+// set, materialized views in the target schema that don't match their TO table are
+// reported as warnings before the diff is generated. The generated migration is then
+// reviewed (see migrationReview).
+func generateDiff(
+	ctx context.Context,
+	w io.Writer,
+	client *clickhouse.Client,
+	cfg *config.Config,
+	opts schemapkg.MigrationFileOptions,
+	checkViewTargets bool,
+	review migrationReview,
+) error {
 	// NB: Migrations have already been applied by runContainer
 	// Get current and target schemas
 	currentSchema, err := client.GetSchema(ctx)
@@ -63,9 +266,12 @@ func generateDiff(ctx context.Context, w io.Writer, client *clickhouse.Client, c
 	}
 
 	targetSchema := &parser.SQL{Statements: targetStatements}
+	if checkViewTargets {
+		writeViewTargetWarnings(w, targetSchema)
+	}
 
 	// Check if there are differences
-	_, err = schemapkg.GenerateDiff(currentSchema, targetSchema)
+	_, err = schemapkg.GenerateDiffWithOptions(currentSchema, targetSchema, opts.Compare)
 	if err != nil {
 		if errors.Is(err, schemapkg.ErrNoDiff) {
 			fmt.Fprintln(w, "No differences found between current and target schemas")
@@ -74,14 +280,175 @@ func generateDiff(ctx context.Context, w io.Writer, client *clickhouse.Client, c
 		return errors.Wrap(err, "failed to generate schema diff")
 	}
 
+	if err := writeMigration(w, cfg.Dir, currentSchema, targetSchema, opts); err != nil {
+		return err
+	}
+
+	return review.run(w, currentSchema, targetSchema)
+}
+
+// migrationReview configures the checks run against a generated migration
+type migrationReview struct {
+	compare         schemapkg.CompareOptions // The options comparing schema objects (see compareOptions)
+	requireApproval schemapkg.ChangeClass    // The changes requiring approval (see checkApproval)
+	risk            bool                     // Whether to print the risk report (see writeRiskReport)
+	rows            schemapkg.TableRows      // The live row counts weighing the risk report, if any
+}
+
+// newMigrationReview builds the review of the generated migration from the diff flags.
+// When the risk report is requested along with --url, the row counts of the tables
+// managed by the migrations are read from the live database.
+func newMigrationReview(ctx context.Context, cmd *cli.Command, cfg *config.Config) (migrationReview, error) {
+	requireApproval, err := approvalClass(cmd.String("require-approval-for"))
+	if err != nil {
+		return migrationReview{}, err
+	}
+
+	review := migrationReview{compare: compareOptions(cmd, cfg), requireApproval: requireApproval, risk: cmd.Bool("risk")}
+	if review.risk && cmd.String("url") != "" {
+		if review.rows, err = liveTableRows(ctx, cmd.String("url"), cfg); err != nil {
+			return migrationReview{}, err
+		}
+	}
+
+	return review, nil
+}
+
+// run prints the risk report when requested, and then gates the migration from current to
+// target on approval
+func (r migrationReview) run(w io.Writer, current, target *parser.SQL) error {
+	if r.risk {
+		if err := writeRiskReport(w, current, target, r.compare, r.rows); err != nil {
+			return err
+		}
+	}
+
+	return checkApproval(w, current, target, r.compare, r.requireApproval)
+}
+
+// liveTableRows reads the row counts of the tables managed by the project's migrations
+// from the live database at url
+func liveTableRows(ctx context.Context, url string, cfg *config.Config) (schemapkg.TableRows, error) {
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(cfg.Dir))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load migration directory")
+	}
+
+	client, err := clickhouse.NewClient(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ClickHouse client")
+	}
+	defer client.Close()
+
+	stats, err := migrator.CollectTableStats(ctx, client, migrationDir.Migrations)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect table stats")
+	}
+
+	rows := make(schemapkg.TableRows, len(stats))
+	for _, s := range stats {
+		rows[s.Name()] = s.Rows
+	}
+
+	return rows, nil
+}
+
+// writeRiskReport prints the risk score of the migration from current to target along
+// with the score of each change (see schema.ScoreDiff)
+func writeRiskReport(w io.Writer, current, target *parser.SQL, opts schemapkg.CompareOptions, rows schemapkg.TableRows) error {
+	changes, err := schemapkg.CompareMetadataWithOptions(current, target, opts)
+	if err != nil && !errors.Is(err, schemapkg.ErrNoDiff) {
+		return errors.Wrap(err, "failed to score schema changes")
+	}
+
+	printRiskReport(w, schemapkg.ScoreDiff(changes, rows))
+	return nil
+}
+
+// printRiskReport prints the risk score of a migration followed by the score of each change
+func printRiskReport(w io.Writer, report schemapkg.RiskReport) {
+	fmt.Fprintf(w, "Migration risk: %s (weight %d)\n", report.Score, report.Weight)
+	for _, item := range report.Items {
+		fmt.Fprintf(w, "  - [%s] %s", item.Score, item.Change.Description)
+		if item.Rows != nil {
+			fmt.Fprintf(w, " (%d rows)", *item.Rows)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// approvalRequiredExitCode is the exit code of diff when the generated migration has
+// changes that require approval, so pipelines can tell it apart from a failure (1)
+const approvalRequiredExitCode = 3
+
+// approvalClass parses the value of --require-approval-for. Requiring approval for
+// modifying changes also requires it for destructive ones.
+func approvalClass(value string) (schemapkg.ChangeClass, error) {
+	switch class := schemapkg.ChangeClass(value); class {
+	case "", schemapkg.ChangeDestructive, schemapkg.ChangeModifying:
+		return class, nil
+	default:
+		return "", errors.Errorf("invalid --require-approval-for value %q: must be destructive or modifying", value)
+	}
+}
+
+// checkApproval lists the changes from current to target that require approval and
+// returns an error exiting with approvalRequiredExitCode if there are any. Destructive
+// changes always require approval, and modifying ones do too when requireApproval is
+// ChangeModifying. Nothing requires approval when requireApproval is empty.
+func checkApproval(w io.Writer, current, target *parser.SQL, opts schemapkg.CompareOptions, requireApproval schemapkg.ChangeClass) error {
+	if requireApproval == "" {
+		return nil
+	}
+
+	changes, err := schemapkg.CompareMetadataWithOptions(current, target, opts)
+	if errors.Is(err, schemapkg.ErrNoDiff) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to classify schema changes")
+	}
+
+	var gated schemapkg.Changes
+	for _, change := range changes {
+		if change.Class == schemapkg.ChangeDestructive ||
+			(requireApproval == schemapkg.ChangeModifying && change.Class == schemapkg.ChangeModifying) {
+			gated = append(gated, change)
+		}
+	}
+
+	if len(gated) == 0 {
+		fmt.Fprintf(w, "No %s changes, so the migration doesn't require approval\n", requireApproval)
+		return nil
+	}
+
+	fmt.Fprintf(w, "The migration has %d change(s) requiring approval:\n", len(gated))
+	for _, change := range gated {
+		fmt.Fprintf(w, "  - [%s] %s\n", change.Class, change.Description)
+	}
+
+	return cli.Exit("migration requires approval", approvalRequiredExitCode)
+}
+
+// writeViewTargetWarnings reports the materialized views in schema whose SELECT doesn't
+// match the columns of their TO table
+func writeViewTargetWarnings(w io.Writer, schema *parser.SQL) {
+	for _, warning := range schemapkg.ValidateViewTargets(schema) {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+}
+
+// writeMigration generates a migration file in dir that migrates current to target and
+// rewrites the sum file to include it, along with its down file when one was written
+func writeMigration(w io.Writer, dir string, current, target *parser.SQL, opts schemapkg.MigrationFileOptions) error {
 	// Generate migration file using normalized schemas for consistent output
-	filename, err := schemapkg.GenerateMigrationFile(cfg.Dir, currentSchema, targetSchema)
+	filename, err := schemapkg.GenerateMigrationFileWithOptions(dir, current, target, opts)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate migration file")
 	}
 
 	// Reload and rehash migration directory to include the new migration
-	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(cfg.Dir))
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(dir))
 	if err != nil {
 		return errors.Wrap(err, "failed to reload migration directory")
 	}
@@ -91,18 +458,19 @@ func generateDiff(ctx context.Context, w io.Writer, client *clickhouse.Client, c
 	}
 
 	// Write the updated sum file
-	sumFilePath := filepath.Join(cfg.Dir, "housekeeper.sum")
-	sumFile, err := os.Create(sumFilePath)
-	if err != nil {
-		return errors.Wrapf(err, "failed to create sum file: %s", sumFilePath)
-	}
-	defer sumFile.Close()
-
-	if _, err := migrationDir.SumFile.WriteTo(sumFile); err != nil {
-		return errors.Wrap(err, "failed to write sum file")
+	if err := migrationDir.WriteSumFile(filepath.Join(dir, "housekeeper.sum")); err != nil {
+		return err
 	}
 
 	fmt.Fprintf(w, "Generated migration: %s\n", filename)
+	if opts.SplitDown {
+		downFile := strings.TrimSuffix(filename, ".sql") + migrator.DownFileSuffix
+		if _, err := os.Stat(filepath.Join(dir, downFile)); err == nil {
+			fmt.Fprintf(w, "Generated down migration: %s\n", downFile)
+		} else {
+			fmt.Fprintf(w, "None of the changes can be reversed, so no down migration was written\n")
+		}
+	}
 	fmt.Fprintf(w, "Updated sum file: housekeeper.sum\n")
 	return nil
 }
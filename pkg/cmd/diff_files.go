@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/urfave/cli/v3"
+)
+
+type (
+	// fileDiff is the JSON representation of the migration between two schema files
+	fileDiff struct {
+		From    string       `json:"from"`
+		To      string       `json:"to"`
+		Changes []fileChange `json:"changes"`
+	}
+
+	// fileChange is the JSON representation of a single change in a fileDiff (see
+	// schema.Change), with its SQL formatted like the migration
+	fileChange struct {
+		ObjectType  string `json:"object_type"`
+		Operation   string `json:"operation"`
+		Name        string `json:"name"`
+		NewName     string `json:"new_name,omitempty"`
+		Destructive bool   `json:"destructive"`
+		Up          string `json:"up"`
+		Down        string `json:"down"`
+	}
+)
+
+// diffFiles returns a CLI command that generates the migration between two schema
+// files without connecting to ClickHouse.
+//
+// Both files are parsed and compared exactly like the project schema and a live
+// database are by the diff command, which makes this useful for reviewing the
+// effect of a proposed schema change in isolation.
+//
+// Command flags:
+//   - --from: Schema file describing the current state (required)
+//   - --to: Schema file describing the target state (required)
+//   - --output, -o: Output format, either sql (default) or json
+//
+// Example usage:
+//
+//	# Print the migration from one schema file to another
+//	housekeeper diff-files --from schema.sql --to proposed.sql
+//
+//	# Print each change with its up and down SQL as JSON
+//	housekeeper diff-files --from schema.sql --to proposed.sql --output json
+func diffFiles() *cli.Command {
+	return &cli.Command{
+		Name:  "diff-files",
+		Usage: "Generate the migration between two schema files without a database",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "Schema file describing the current state",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    "Schema file describing the target state",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output format (sql or json)",
+				Value:   "sql",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			output := cmd.String("output")
+			if output != "sql" && output != "json" {
+				return errors.Errorf("unsupported output format: %s", output)
+			}
+
+			return writeFileDiff(cmd.Writer, cmd.String("from"), cmd.String("to"), output)
+		},
+	}
+}
+
+// writeFileDiff compares the schema files at from and to, writing the migration in the
+// given output format. The JSON output lists every change (see
+// schema.CompareMetadataWithOptions) with the SQL that applies and reverts it.
+func writeFileDiff(w io.Writer, from, to, output string) error {
+	current, err := parseSchemaFile(from)
+	if err != nil {
+		return err
+	}
+
+	target, err := parseSchemaFile(to)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		changes, err := schemapkg.CompareMetadataWithOptions(current, target, schemapkg.CompareOptions{IncludeSQL: true})
+		if err != nil && !errors.Is(err, schemapkg.ErrNoDiff) {
+			return errors.Wrap(err, "failed to generate schema diff")
+		}
+
+		result := fileDiff{From: from, To: to, Changes: []fileChange{}}
+		for _, change := range changes {
+			up, err := formatChangeSQL(change.UpSQL)
+			if err != nil {
+				return errors.Wrapf(err, "failed to format migration for %s", change.Name)
+			}
+			down, err := formatChangeSQL(change.DownSQL)
+			if err != nil {
+				return errors.Wrapf(err, "failed to format down migration for %s", change.Name)
+			}
+
+			result.Changes = append(result.Changes, fileChange{
+				ObjectType:  string(change.ObjectType),
+				Operation:   change.Type,
+				Name:        change.Name,
+				NewName:     change.NewName,
+				Destructive: change.Destructive,
+				Up:          up,
+				Down:        down,
+			})
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return errors.Wrap(encoder.Encode(result), "failed to write diff")
+	}
+
+	diff, err := schemapkg.GenerateDiff(current, target)
+	if err != nil {
+		if errors.Is(err, schemapkg.ErrNoDiff) {
+			fmt.Fprintln(w, "No differences found between current and target schemas")
+			return nil
+		}
+		return errors.Wrap(err, "failed to generate schema diff")
+	}
+
+	return errors.Wrap(format.FormatSQL(w, format.Defaults, diff), "failed to format migration")
+}
+
+// formatChangeSQL formats the SQL of a change like the statements of a migration
+func formatChangeSQL(sql string) (string, error) {
+	if strings.TrimSpace(sql) == "" {
+		return "", nil
+	}
+
+	parsed, err := parser.ParseString(sql)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := format.FormatSQL(&buf, format.Defaults, parsed); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// parseSchemaFile parses the SQL statements in the file at path
+func parseSchemaFile(path string) (*parser.SQL, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open schema file %s", path)
+	}
+	defer file.Close()
+
+	sql, err := parser.Parse(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse schema file %s", path)
+	}
+
+	return sql, nil
+}
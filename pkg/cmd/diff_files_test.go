@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFilesCommand_Structure(t *testing.T) {
+	command := diffFiles()
+
+	require.Equal(t, "diff-files", command.Name)
+	require.NotNil(t, command.Action)
+	require.Len(t, command.Flags, 3)
+	require.Equal(t, "from", command.Flags[0].Names()[0])
+	require.Equal(t, "to", command.Flags[1].Names()[0])
+	require.Equal(t, "output", command.Flags[2].Names()[0])
+}
+
+func TestWriteFileDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema := func(name, sql string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(sql), 0o600))
+		return path
+	}
+
+	from := writeSchema("from.sql", `
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+		CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/users')) LAYOUT(HASHED()) LIFETIME(300);
+		CREATE VIEW analytics.event_names AS SELECT name FROM analytics.events;
+	`)
+	to := writeSchema("to.sql", `
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+		CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/users')) LAYOUT(HASHED()) LIFETIME(600);
+		CREATE VIEW analytics.event_names AS SELECT name, ts FROM analytics.events;
+	`)
+
+	t.Run("sql output", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeFileDiff(&buf, from, to, "sql"))
+
+		output := buf.String()
+		require.Contains(t, output, "ALTER TABLE `analytics`.`events`")
+		require.Contains(t, output, "ADD COLUMN `ts` DateTime")
+		require.Contains(t, output, "CREATE OR REPLACE DICTIONARY `analytics`.`users_dict`")
+		require.Contains(t, output, "CREATE OR REPLACE VIEW `analytics`.`event_names`")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeFileDiff(&buf, from, to, "json"))
+
+		var result fileDiff
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+		require.Equal(t, from, result.From)
+		require.Equal(t, to, result.To)
+		require.Len(t, result.Changes, 3)
+
+		events := result.Changes[0]
+		require.Equal(t, "table", events.ObjectType)
+		require.Equal(t, "ALTER", events.Operation)
+		require.Equal(t, "analytics.events", events.Name)
+		require.False(t, events.Destructive)
+		require.Equal(t, "ALTER TABLE `analytics`.`events`\n    ADD COLUMN `ts` DateTime;", events.Up)
+		require.Equal(t, "ALTER TABLE `analytics`.`events`\n    DROP COLUMN `ts`;", events.Down)
+
+		dictionary := result.Changes[1]
+		require.Equal(t, "dictionary", dictionary.ObjectType)
+		require.Equal(t, "REPLACE", dictionary.Operation)
+		require.Equal(t, "analytics.users_dict", dictionary.Name)
+		require.Contains(t, dictionary.Up, "LIFETIME(600)")
+		require.Contains(t, dictionary.Down, "LIFETIME(300)")
+
+		view := result.Changes[2]
+		require.Equal(t, "view", view.ObjectType)
+		require.Equal(t, "analytics.event_names", view.Name)
+		require.Contains(t, view.Up, "`ts`")
+		require.NotContains(t, view.Down, "`ts`")
+
+		// Field names are part of the output format
+		require.Contains(t, buf.String(), `"object_type": "table"`)
+		require.Contains(t, buf.String(), `"operation": "ALTER"`)
+		require.NotContains(t, buf.String(), "new_name")
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeFileDiff(&buf, from, from, "sql"))
+		require.Contains(t, buf.String(), "No differences found")
+
+		buf.Reset()
+		require.NoError(t, writeFileDiff(&buf, from, from, "json"))
+		require.Contains(t, buf.String(), `"changes": []`)
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		invalid := writeSchema("invalid.sql", "CREATE TABLE (")
+
+		err := writeFileDiff(&bytes.Buffer{}, filepath.Join(dir, "missing.sql"), to, "sql")
+		require.ErrorContains(t, err, "failed to open schema file")
+
+		err = writeFileDiff(&bytes.Buffer{}, from, invalid, "sql")
+		require.ErrorContains(t, err, "failed to parse schema file")
+	})
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+)
+
+// initialDiff generates the first migration of a project, which creates the entire
+// compiled schema. The schema is compared with an empty one, so no container is needed,
+// and the statements are ordered the same way as any other generated migration (e.g.
+// databases before the tables they contain, and tables before the views reading them).
+//
+// The migrations directory must not contain any migrations yet, since the generated
+// migration would otherwise recreate objects those migrations already create.
+func initialDiff(w io.Writer, cfg *config.Config, opts schemapkg.MigrationFileOptions, checkViewTargets bool, review migrationReview) error {
+	if _, err := os.Stat(cfg.Dir); err == nil {
+		migrationDir, err := migrator.LoadMigrationDir(os.DirFS(cfg.Dir))
+		if err != nil {
+			return errors.Wrap(err, "failed to load migration directory")
+		}
+		if count := len(migrationDir.Migrations); count > 0 {
+			return errors.Errorf("--initial requires an empty migrations directory, but %s contains %d migration(s)", cfg.Dir, count)
+		}
+	}
+
+	targetStatements, err := compileProjectSchema(cfg)
+	if err != nil {
+		return err
+	}
+
+	targetSchema := &parser.SQL{Statements: targetStatements}
+	if checkViewTargets {
+		writeViewTargetWarnings(w, targetSchema)
+	}
+
+	current := &parser.SQL{}
+	if _, err := schemapkg.GenerateDiffWithOptions(current, targetSchema, opts.Compare); err != nil {
+		if errors.Is(err, schemapkg.ErrNoDiff) {
+			fmt.Fprintln(w, "The schema doesn't define any objects, so there's nothing to migrate")
+			return nil
+		}
+		return errors.Wrap(err, "failed to generate schema diff")
+	}
+
+	if err := writeMigration(w, cfg.Dir, current, targetSchema, opts); err != nil {
+		return err
+	}
+
+	return review.run(w, current, targetSchema)
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+// initialSchema defines objects that depend on each other in the reverse of the order
+// they must be created in
+const initialSchema = `
+CREATE VIEW analytics.daily_summary AS SELECT date, sum(total) AS total FROM analytics.daily_totals GROUP BY date;
+CREATE MATERIALIZED VIEW analytics.mv_daily_totals TO analytics.daily_totals AS SELECT toDate(ts) AS date, count() AS total FROM analytics.events_all GROUP BY date;
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(CLICKHOUSE(table 'users' db 'analytics')) LAYOUT(HASHED()) LIFETIME(3600);
+CREATE TABLE analytics.events_all AS analytics.events ENGINE = Distributed(default, analytics, events, rand());
+CREATE TABLE analytics.daily_totals (date Date, total UInt64) ENGINE = SummingMergeTree() ORDER BY date;
+CREATE TABLE analytics.events (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+CREATE DATABASE analytics ENGINE = Atomic;
+`
+
+func runInitialDiff(t *testing.T, fixture *testutil.ProjectFixture, args ...string) (string, error) {
+	t.Helper()
+	t.Chdir(fixture.Dir)
+
+	command := diff(fixture.Config, testutil.NewMockDockerClient(), nil)
+	var out strings.Builder
+	app := &cli.Command{
+		Name:   "test",
+		Flags:  command.Flags,
+		Action: command.Action,
+		Writer: &out,
+	}
+
+	err := app.Run(context.Background(), append([]string{"test", "--initial"}, args...))
+	return out.String(), err
+}
+
+func TestDiffCommand_Initial(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(initialSchema)
+	defer fixture.Cleanup()
+
+	out, err := runInitialDiff(t, fixture, "--name", "init")
+	require.NoError(t, err)
+
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(fixture.Config.Dir))
+	require.NoError(t, err)
+	require.Len(t, migrationDir.Migrations, 1)
+
+	migration := migrationDir.Migrations[0]
+	require.True(t, strings.HasSuffix(migration.Version, "_init"), "unexpected version %s", migration.Version)
+	require.Contains(t, out, "Generated migration: "+migration.Version+".sql")
+	testutil.RequireSumFileValid(t, filepath.Join(fixture.Config.Dir, "housekeeper.sum"))
+
+	var created []string
+	for _, stmt := range migration.Statements {
+		switch {
+		case stmt.CreateDatabase != nil:
+			created = append(created, stmt.CreateDatabase.Name)
+		case stmt.CreateTable != nil:
+			created = append(created, stmt.CreateTable.Name)
+		case stmt.CreateDictionary != nil:
+			created = append(created, stmt.CreateDictionary.Name)
+		case stmt.CreateView != nil:
+			created = append(created, stmt.CreateView.Name)
+		}
+	}
+	require.ElementsMatch(t, []string{
+		"analytics", "events", "users", "daily_totals", "events_all", "users_dict", "mv_daily_totals", "daily_summary",
+	}, created)
+
+	// Every object is created after the objects it depends on
+	before := func(dependency, dependent string) {
+		t.Helper()
+		require.Less(t, slices.Index(created, dependency), slices.Index(created, dependent), "%s must be created before %s", dependency, dependent)
+	}
+	for _, object := range created[1:] {
+		before("analytics", object)
+	}
+	before("events", "events_all")
+	before("users", "users_dict")
+	before("events_all", "mv_daily_totals")
+	before("daily_totals", "mv_daily_totals")
+	before("daily_totals", "daily_summary")
+}
+
+func TestDiffCommand_SplitDown(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(initialSchema)
+	defer fixture.Cleanup()
+
+	out, err := runInitialDiff(t, fixture, "--name", "init", "--split-down")
+	require.NoError(t, err)
+
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(fixture.Config.Dir))
+	require.NoError(t, err)
+	require.Len(t, migrationDir.Migrations, 1, "the down file must not be loaded as a migration")
+
+	migration := migrationDir.Migrations[0]
+	upFile := migration.Version + ".sql"
+	downFile := migration.Version + ".down.sql"
+	require.Contains(t, out, "Generated migration: "+upFile)
+	require.Contains(t, out, "Generated down migration: "+downFile)
+
+	// Both files are covered by the sum file, and the up file has no embedded down section
+	sum, err := os.ReadFile(filepath.Join(fixture.Config.Dir, "housekeeper.sum"))
+	require.NoError(t, err)
+	require.Contains(t, string(sum), downFile+" h1:")
+	require.Contains(t, string(sum), upFile+" h1:")
+
+	valid, err := migrationDir.Validate()
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	up, err := os.ReadFile(filepath.Join(fixture.Config.Dir, upFile))
+	require.NoError(t, err)
+	require.NotContains(t, string(up), "housekeeper:down")
+
+	// The down statements drop every object in the reverse of the order it was created in
+	var dropped []string
+	for _, stmt := range migration.DownStatements {
+		switch {
+		case stmt.DropDatabase != nil:
+			dropped = append(dropped, stmt.DropDatabase.Name)
+		case stmt.DropTable != nil:
+			dropped = append(dropped, stmt.DropTable.Name)
+		case stmt.DropDictionary != nil:
+			dropped = append(dropped, stmt.DropDictionary.Name)
+		case stmt.DropView != nil:
+			dropped = append(dropped, stmt.DropView.Name)
+		}
+	}
+	require.Len(t, dropped, len(migration.Statements))
+	require.Equal(t, "analytics", dropped[len(dropped)-1])
+	require.Less(t, slices.Index(dropped, "mv_daily_totals"), slices.Index(dropped, "events_all"))
+	require.Less(t, slices.Index(dropped, "daily_summary"), slices.Index(dropped, "daily_totals"))
+}
+
+func TestDiffCommand_RequireApproval(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(initialSchema)
+	defer fixture.Cleanup()
+
+	// The initial migration only creates objects, so it never requires approval
+	out, err := runInitialDiff(t, fixture, "--require-approval-for", "modifying")
+	require.NoError(t, err)
+	require.Contains(t, out, "No modifying changes, so the migration doesn't require approval")
+}
+
+func TestDiffCommand_Risk(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(initialSchema)
+	defer fixture.Cleanup()
+
+	// The initial migration only creates objects, so it's scored offline as low risk
+	out, err := runInitialDiff(t, fixture, "--risk")
+	require.NoError(t, err)
+	require.Contains(t, out, "Migration risk: low (weight ")
+	require.Contains(t, out, "  - [low] Create table ")
+}
+
+func TestDiffCommand_RequireApprovalRejectsInvalidClass(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(initialSchema)
+	defer fixture.Cleanup()
+
+	_, err := runInitialDiff(t, fixture, "--require-approval-for", "additive")
+	require.EqualError(t, err, `invalid --require-approval-for value "additive": must be destructive or modifying`)
+}
+
+func TestDiffCommand_InitialRequiresEmptyMigrationsDir(t *testing.T) {
+	fixture := testutil.TestProject(t).
+		WithSchema(initialSchema).
+		WithMigrations([]testutil.MigrationFile{
+			{Version: "20240101000000", SQL: "CREATE DATABASE analytics ENGINE = Atomic;"},
+		})
+	defer fixture.Cleanup()
+
+	_, err := runInitialDiff(t, fixture)
+	require.ErrorContains(t, err, "--initial requires an empty migrations directory")
+	testutil.RequireMigrationCount(t, fixture.Config.Dir, 1)
+}
+
+func TestDiffCommand_InitialEmptySchema(t *testing.T) {
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	out, err := runInitialDiff(t, fixture)
+	require.NoError(t, err)
+	require.Contains(t, out, "nothing to migrate")
+	testutil.RequireMigrationCount(t, fixture.Config.Dir, 0)
+}
+
+func TestDiffCommand_InitialRejectsInvalidName(t *testing.T) {
+	fixture := testutil.TestProject(t).WithSchema(initialSchema)
+	defer fixture.Cleanup()
+
+	_, err := runInitialDiff(t, fixture, "--name", "../init")
+	require.ErrorContains(t, err, `invalid migration name "../init"`)
+	testutil.RequireMigrationCount(t, fixture.Config.Dir, 0)
+}
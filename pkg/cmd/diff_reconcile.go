@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+)
+
+// reconcileDiff regenerates a pending migration that was partially applied by hand.
+//
+// Rather than replaying the migrations in a container, the schema is dumped from the live
+// database at url, so the new migration only contains the changes that are still needed.
+// The new migration replaces the pending migration named by version under the same version
+// (see writeReconciledMigration), and is then reviewed (see migrationReview). When the
+// superseded migration was partially applied, its progress is discarded so the new
+// migration is applied from the start.
+func reconcileDiff(
+	ctx context.Context,
+	w io.Writer,
+	cfg *config.Config,
+	opts schemapkg.MigrationFileOptions,
+	url, version string,
+	review migrationReview,
+) error {
+	client, err := clickhouse.NewClientWithOptions(ctx, url, clickhouse.ClientOptions{
+		Cluster:         cfg.ClickHouse.Cluster,
+		IgnoreDatabases: cfg.ClickHouse.IgnoreDatabases,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create ClickHouse client")
+	}
+	defer client.Close()
+
+	if err := testConnection(ctx, client); err != nil {
+		return errors.Wrap(err, "failed to connect to ClickHouse")
+	}
+
+	// Without the housekeeper infrastructure, no migration has been applied yet
+	revisions := migrator.NewRevisionSet(nil)
+	bootstrapped, err := checkBootstrapStatus(ctx, client)
+	if err != nil {
+		return errors.Wrap(err, "failed to check bootstrap status")
+	}
+	if bootstrapped {
+		if revisions, err = migrator.LoadRevisions(ctx, client); err != nil {
+			return errors.Wrap(err, "failed to load revisions")
+		}
+	}
+
+	currentSchema, err := client.GetSchema(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to dump live schema")
+	}
+
+	targetStatements, err := compileProjectSchema(cfg)
+	if err != nil {
+		return err
+	}
+
+	targetSchema := &parser.SQL{Statements: targetStatements}
+	if err := writeReconciledMigration(w, cfg.Dir, version, revisions, currentSchema, targetSchema, opts); err != nil {
+		return err
+	}
+
+	// The regenerated migration can't resume from the statements applied by hand, so their
+	// progress is discarded and the migration is applied from its first statement
+	if revisions.IsPartiallyApplied(&migrator.Migration{Version: version}) {
+		exec := executor.New(executor.Config{ClickHouse: client})
+		if err := exec.DiscardPartial(ctx, version); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Discarded partial progress of %s\n", version)
+	}
+
+	return review.run(w, currentSchema, targetSchema)
+}
+
+// writeReconciledMigration replaces the pending migration named by version with one that
+// migrates current to target, written under the same version so it keeps its place in the
+// migration order. When current already matches target, the pending migration is removed
+// without generating a replacement. The sum file is rewritten either way.
+//
+// Since target is the whole project schema, the replacement covers the changes of every
+// pending migration, so the superseded migration must be the only one pending.
+func writeReconciledMigration(
+	w io.Writer,
+	dir, version string,
+	revisions *migrator.RevisionSet,
+	current, target *parser.SQL,
+	opts schemapkg.MigrationFileOptions,
+) error {
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(dir))
+	if err != nil {
+		return errors.Wrap(err, "failed to load migration directory")
+	}
+
+	var superseded *migrator.Migration
+	for _, migration := range migrationDir.Migrations {
+		if migration.Version == version {
+			superseded = migration
+			break
+		}
+	}
+
+	switch {
+	case superseded == nil:
+		return errors.Errorf("migration %s not found in %s", version, dir)
+	case superseded.IsSnapshot:
+		return errors.Errorf("migration %s is a snapshot and can't be reconciled", version)
+	case revisions.IsCompleted(superseded):
+		return errors.Errorf("migration %s has already been applied; only pending migrations can be reconciled", version)
+	}
+
+	var otherPending []string
+	for _, migration := range revisions.GetPending(migrationDir) {
+		if migration != superseded {
+			otherPending = append(otherPending, migration.Version)
+		}
+	}
+	if len(otherPending) > 0 {
+		return errors.Errorf("migration %s can only be reconciled when it's the only pending migration; also pending: %s",
+			version, strings.Join(otherPending, ", "))
+	}
+
+	supersededFile := version + ".sql"
+	downFile := filepath.Join(dir, version+migrator.DownFileSuffix)
+	if err := os.Remove(downFile); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove superseded down migration: %s", downFile)
+	}
+
+	filename := ""
+	opts.Version = version
+	if _, err := schemapkg.GenerateDiffWithOptions(current, target, opts.Compare); err != nil {
+		if !errors.Is(err, schemapkg.ErrNoDiff) {
+			return errors.Wrap(err, "failed to generate schema diff")
+		}
+
+		if err := os.Remove(filepath.Join(dir, supersededFile)); err != nil {
+			return errors.Wrapf(err, "failed to remove superseded migration: %s", supersededFile)
+		}
+	} else if filename, err = schemapkg.GenerateMigrationFileWithOptions(dir, current, target, opts); err != nil {
+		return errors.Wrap(err, "failed to generate migration file")
+	}
+
+	migrationDir, err = migrator.LoadMigrationDir(withoutSumFiles{os.DirFS(dir)})
+	if err != nil {
+		return errors.Wrap(err, "failed to reload migration directory")
+	}
+
+	if err := migrationDir.Rehash(); err != nil {
+		return errors.Wrap(err, "failed to rehash migration directory")
+	}
+
+	if err := migrationDir.WriteSumFile(filepath.Join(dir, "housekeeper.sum")); err != nil {
+		return err
+	}
+
+	if filename == "" {
+		fmt.Fprintf(w, "The live database already includes every change in %s\n", supersededFile)
+		fmt.Fprintf(w, "Removed superseded migration: %s\n", supersededFile)
+	} else {
+		fmt.Fprintf(w, "Regenerated migration: %s\n", filename)
+	}
+	fmt.Fprintf(w, "Updated sum file: housekeeper.sum\n")
+	return nil
+}
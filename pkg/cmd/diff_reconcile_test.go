@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestWriteReconciledMigration(t *testing.T) {
+	const (
+		createVersion = "20240101000000"
+		alterVersion  = "20240102000000"
+	)
+
+	setup := func(t *testing.T) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		files := map[string]string{
+			createVersion + ".sql": "CREATE DATABASE analytics ENGINE = Atomic;\n" +
+				"CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;\n",
+			alterVersion + ".sql": "ALTER TABLE analytics.events ADD COLUMN name String;\n" +
+				"ALTER TABLE analytics.events ADD COLUMN ts DateTime;\n",
+		}
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+		return dir
+	}
+
+	parse := func(t *testing.T, sql string) *parser.SQL {
+		t.Helper()
+
+		parsed, err := parser.ParseString(sql)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	partiallyApplied := "statement 2 failed"
+	revisions := migrator.NewRevisionSet([]*migrator.Revision{
+		{Version: createVersion, Kind: migrator.StandardRevision, Applied: 2, Total: 2},
+		{Version: alterVersion, Kind: migrator.StandardRevision, Applied: 1, Total: 2, Error: &partiallyApplied},
+	})
+
+	target := "CREATE DATABASE analytics ENGINE = Atomic;\n" +
+		"CREATE TABLE analytics.events (id UInt64, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;"
+
+	t.Run("generates the remaining changes", func(t *testing.T) {
+		dir := setup(t)
+		current := parse(t, "CREATE DATABASE analytics ENGINE = Atomic;\n"+
+			"CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;")
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, alterVersion+".down.sql"), []byte("ALTER TABLE analytics.events DROP COLUMN ts;\n"), 0o600))
+
+		var buf bytes.Buffer
+		err := writeReconciledMigration(&buf, dir, alterVersion, revisions, current, parse(t, target), schemapkg.MigrationFileOptions{})
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "Regenerated migration: "+alterVersion+".sql")
+		require.NoFileExists(t, filepath.Join(dir, alterVersion+".down.sql"), "the superseded down file no longer matches")
+
+		migrationDir, err := migrator.LoadMigrationDir(os.DirFS(dir))
+		require.NoError(t, err)
+		require.Len(t, migrationDir.Migrations, 2)
+		require.Equal(t, createVersion, migrationDir.Migrations[0].Version)
+
+		// The replacement keeps the superseded version, so it keeps its place in the order
+		reconciled := migrationDir.Migrations[1]
+		require.Equal(t, alterVersion, reconciled.Version)
+		content, err := os.ReadFile(filepath.Join(dir, reconciled.Version+".sql"))
+		require.NoError(t, err)
+		require.Contains(t, string(content), "ADD COLUMN `ts` DateTime")
+		require.NotContains(t, string(content), "`name`", "changes already applied to the live database are skipped")
+
+		testutil.RequireSumFileValid(t, filepath.Join(dir, "housekeeper.sum"))
+		valid, err := migrationDir.Validate()
+		require.NoError(t, err)
+		require.True(t, valid)
+	})
+
+	t.Run("removes a migration the live database already satisfies", func(t *testing.T) {
+		dir := setup(t)
+
+		var buf bytes.Buffer
+		err := writeReconciledMigration(&buf, dir, alterVersion, revisions, parse(t, target), parse(t, target), schemapkg.MigrationFileOptions{})
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "already includes every change in "+alterVersion+".sql")
+		require.Contains(t, buf.String(), "Removed superseded migration: "+alterVersion+".sql")
+		require.NotContains(t, buf.String(), "Regenerated migration")
+
+		migrationDir, err := migrator.LoadMigrationDir(os.DirFS(dir))
+		require.NoError(t, err)
+		require.Len(t, migrationDir.Migrations, 1)
+		require.Equal(t, createVersion, migrationDir.Migrations[0].Version)
+	})
+
+	t.Run("rejects a migration with other pending migrations", func(t *testing.T) {
+		dir := setup(t)
+		const laterVersion = "20240103000000"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, laterVersion+".sql"), []byte("ALTER TABLE analytics.events ADD COLUMN source String;\n"), 0o600))
+
+		// Both the partially applied migration and the one after it are pending, so a
+		// replacement generated against the project schema would include the later changes
+		var buf bytes.Buffer
+		err := writeReconciledMigration(&buf, dir, alterVersion, revisions, parse(t, target), parse(t, target), schemapkg.MigrationFileOptions{})
+		require.EqualError(t, err, "migration "+alterVersion+" can only be reconciled when it's the only pending migration; also pending: "+laterVersion)
+		require.Empty(t, buf.String())
+
+		for _, version := range []string{createVersion, alterVersion, laterVersion} {
+			require.FileExists(t, filepath.Join(dir, version+".sql"))
+		}
+	})
+
+	t.Run("rejects invalid migrations", func(t *testing.T) {
+		tests := map[string]struct {
+			version string
+			err     string
+		}{
+			"applied migration": {version: createVersion, err: "has already been applied"},
+			"unknown migration": {version: "20240103000000", err: "migration 20240103000000 not found"},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				dir := setup(t)
+
+				var buf bytes.Buffer
+				err := writeReconciledMigration(&buf, dir, tt.version, revisions, parse(t, target), parse(t, target), schemapkg.MigrationFileOptions{})
+				require.ErrorContains(t, err, tt.err)
+				require.FileExists(t, filepath.Join(dir, createVersion+".sql"))
+				require.FileExists(t, filepath.Join(dir, alterVersion+".sql"))
+			})
+		}
+	})
+}
+
+func TestDiffCommand_ReconcileRequiresURL(t *testing.T) {
+	t.Setenv("HOUSEKEEPER_DATABASE_URL", "")
+
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	command := diff(fixture.Config, testutil.NewMockDockerClient(), nil)
+	app := &cli.Command{
+		Name:   "test",
+		Flags:  command.Flags,
+		Action: command.Action,
+	}
+
+	err := app.Run(context.Background(), []string{"test", "--reconcile", "20240102000000"})
+	require.ErrorContains(t, err, "--reconcile requires --url")
+}
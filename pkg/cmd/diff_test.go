@@ -4,9 +4,14 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v3"
 )
@@ -22,7 +27,7 @@ func TestDiffCommand_RequiresConfig(t *testing.T) {
 	defer fixture.Cleanup()
 
 	dockerClient := testutil.NewMockDockerClient()
-	command := diff(fixture.Config, dockerClient)
+	command := diff(fixture.Config, dockerClient, nil)
 
 	ctx := context.Background()
 	testCmd := &cli.Command{
@@ -56,7 +61,7 @@ func TestDiffCommand_WithDockerIntegration(t *testing.T) {
 	dockerClient := testutil.NewMockDockerClient()
 
 	// Get the diff command
-	command := diff(fixture.Config, dockerClient)
+	command := diff(fixture.Config, dockerClient, nil)
 
 	ctx := context.Background()
 	testCmd := &cli.Command{
@@ -141,10 +146,16 @@ func TestDiffCommand_ProjectStructure(t *testing.T) {
 	require.FileExists(t, fixture.GetMainSchemaPath())
 
 	// Test that the command can be created
-	command := diff(fixture.Config, testutil.NewMockDockerClient())
+	command := diff(fixture.Config, testutil.NewMockDockerClient(), nil)
 	require.NotNil(t, command)
 	require.Equal(t, "diff", command.Name)
 	require.Equal(t, "Generate any missing migrations", command.Usage)
+
+	flagNames := make([]string, 0, len(command.Flags))
+	for _, flag := range command.Flags {
+		flagNames = append(flagNames, flag.Names()[0])
+	}
+	require.Equal(t, []string{"header", "env", "description", "annotate-source", "check-view-targets", "name", "split-down", "require-approval-for", "risk", "initial", "reconcile", "url", "default-codec", "detect-column-renames"}, flagNames)
 }
 
 func TestDiffCommand_WithExistingSumFile(t *testing.T) {
@@ -208,7 +219,7 @@ func TestDiffCommand_EmptyMigrationsDirectory(t *testing.T) {
 	testutil.RequireDirEmpty(t, fixture.GetMigrationsDir())
 
 	// Create diff command - should work even with no migrations
-	command := diff(fixture.Config, testutil.NewMockDockerClient())
+	command := diff(fixture.Config, testutil.NewMockDockerClient(), nil)
 	require.NotNil(t, command)
 }
 
@@ -226,7 +237,152 @@ func TestDiffCommand_DockerOptionsCreation(t *testing.T) {
 	require.Equal(t, "custom/config", fixture.Config.ClickHouse.ConfigDir)
 
 	// Test command creation doesn't fail
-	command := diff(fixture.Config, testutil.NewMockDockerClient())
+	command := diff(fixture.Config, testutil.NewMockDockerClient(), nil)
 	require.NotNil(t, command)
 	require.NotNil(t, command.Before) // Should have requireConfig
 }
+
+func TestCheckApproval(t *testing.T) {
+	current, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	additive, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	modifying, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Analytics';
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	destructive, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		target          *parser.SQL
+		requireApproval schemapkg.ChangeClass
+		output          string
+		gated           bool
+	}{
+		{name: "not required", target: destructive},
+		{name: "additive", target: additive, requireApproval: schemapkg.ChangeModifying, output: "No modifying changes"},
+		{name: "modifying allowed", target: modifying, requireApproval: schemapkg.ChangeDestructive, output: "No destructive changes"},
+		{
+			name:            "modifying gated",
+			target:          modifying,
+			requireApproval: schemapkg.ChangeModifying,
+			output:          "The migration has 1 change(s) requiring approval:\n  - [modifying] Alter database 'analytics'\n",
+			gated:           true,
+		},
+		{
+			name:            "destructive gated",
+			target:          destructive,
+			requireApproval: schemapkg.ChangeDestructive,
+			output:          "The migration has 1 change(s) requiring approval:\n  - [destructive] Drop table analytics.legacy\n",
+			gated:           true,
+		},
+		{name: "no changes", target: current, requireApproval: schemapkg.ChangeModifying},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := checkApproval(&out, current, tt.target, schemapkg.CompareOptions{}, tt.requireApproval)
+			require.Contains(t, out.String(), tt.output)
+
+			if !tt.gated {
+				require.NoError(t, err)
+				return
+			}
+
+			var exitErr cli.ExitCoder
+			require.True(t, errors.As(err, &exitErr))
+			require.Equal(t, approvalRequiredExitCode, exitErr.ExitCode())
+		})
+	}
+}
+
+func TestCompareOptions(t *testing.T) {
+	// compareOptionsFor runs the diff command with args, returning its compare options
+	compareOptionsFor := func(t *testing.T, cfg *config.Config, args ...string) schemapkg.CompareOptions {
+		t.Helper()
+
+		var opts schemapkg.CompareOptions
+		cmd := diff(cfg, nil, nil)
+		cmd.Before = nil
+		cmd.Action = func(_ context.Context, cmd *cli.Command) error {
+			opts = compareOptions(cmd, cfg)
+			return nil
+		}
+
+		require.NoError(t, cmd.Run(t.Context(), append([]string{"diff"}, args...)))
+		return opts
+	}
+
+	cfg := &config.Config{ClickHouse: config.ClickHouse{DefaultCodec: "ZSTD(1)"}}
+	require.Equal(t, "ZSTD(1)", compareOptionsFor(t, cfg).DefaultCodec)
+	require.Equal(t, "LZ4HC(9)", compareOptionsFor(t, cfg, "--default-codec", "LZ4HC(9)").DefaultCodec,
+		"the flag overrides housekeeper.yaml")
+	require.Empty(t, compareOptionsFor(t, &config.Config{}).DefaultCodec)
+	require.False(t, compareOptionsFor(t, cfg).DetectColumnRenames, "column renames are opt-in")
+	require.True(t, compareOptionsFor(t, cfg, "--detect-column-renames").DetectColumnRenames)
+
+	// A dumped column reporting the server's default codec matches one declared without it
+	dumped, err := parser.ParseString("CREATE TABLE db.events (id UInt64 CODEC(ZSTD(1))) ENGINE = MergeTree() ORDER BY id;")
+	require.NoError(t, err)
+	declared, err := parser.ParseString("CREATE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;")
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.Error(t, checkApproval(&out, dumped, declared, schemapkg.CompareOptions{}, schemapkg.ChangeModifying))
+	out.Reset()
+	require.NoError(t, checkApproval(&out, dumped, declared, compareOptionsFor(t, cfg), schemapkg.ChangeModifying))
+	require.Empty(t, out.String())
+}
+
+func TestWriteRiskReport(t *testing.T) {
+	current, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, count UInt32) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	target, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, count UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.users (id UInt64, email String) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	t.Run("offline", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, writeRiskReport(&out, current, target, schemapkg.CompareOptions{}, nil))
+		require.Equal(t, "Migration risk: high (weight 126)\n"+
+			"  - [low] Create table analytics.users\n"+
+			"  - [medium] Alter table analytics.events\n"+
+			"  - [high] Drop table analytics.legacy\n", out.String())
+	})
+
+	t.Run("with row counts", func(t *testing.T) {
+		var out strings.Builder
+		rows := schemapkg.TableRows{"analytics.events": schemapkg.LargeTableRows, "analytics.legacy": 0}
+		require.NoError(t, writeRiskReport(&out, current, target, schemapkg.CompareOptions{}, rows))
+		require.Contains(t, out.String(), "Migration risk: high (weight 151)\n")
+		require.Contains(t, out.String(), "  - [medium] Alter table analytics.events (10000000 rows)\n")
+		require.Contains(t, out.String(), "  - [high] Drop table analytics.legacy (0 rows)\n")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		var out strings.Builder
+		require.NoError(t, writeRiskReport(&out, current, current, schemapkg.CompareOptions{}, nil))
+		require.Equal(t, "Migration risk: low (weight 0)\n", out.String())
+	})
+}
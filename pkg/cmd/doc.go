@@ -9,10 +9,19 @@
 //
 // The cmd package currently provides:
 //   - init: Initialize a new housekeeper project structure
+//   - init-from: Initialize a new project from an existing schema file
 //   - bootstrap: Create a new project from an existing ClickHouse server
+//   - bootstrap-revisions: Create the revisions table and baseline already-applied migrations
 //   - schema dump: Extract schema from live ClickHouse instances
 //   - schema compile: Compile and format project schema files
 //   - diff: Compare schema with database and generate migrations (planned)
+//   - diff-files: Generate the migration between two schema files without a database
+//   - test: Apply all migrations to an ephemeral ClickHouse container
+//   - verify-down: Check the down migration for pending changes reverses the up migration
+//   - check: Verify an instance has every migration applied, a valid sum file, and no drift
+//   - coverage: Report which objects in a live database are managed by the project schema
+//   - lint migrations: Detect dangerous patterns in migration files
+//   - impact: Preview the rows and data a migration would touch or destroy
 //
 // # Command Structure
 //
@@ -34,11 +43,19 @@
 // from the command line:
 //
 //	housekeeper init                                         # Initialize project
+//	housekeeper init-from --schema schema.sql               # Initialize project from a schema file
 //	housekeeper bootstrap --url localhost:9000              # Bootstrap from ClickHouse server
 //	housekeeper bootstrap --url host:9000 --cluster prod    # Bootstrap with cluster support
+//	housekeeper bootstrap-revisions --url host:9000 --baseline-applied 20240101120000 # Adopt an existing database
 //	housekeeper schema dump --url localhost:9000            # Dump schema from ClickHouse
 //	housekeeper schema compile --env production              # Compile project schema
 //	housekeeper diff --url host:9000 ...                   # Generate migrations (planned)
+//	housekeeper diff-files --from a.sql --to b.sql          # Diff two schema files offline
+//	housekeeper test                                        # Apply migrations to a fresh ClickHouse
+//	housekeeper verify-down                                 # Check pending changes roll back cleanly
+//	housekeeper check --url host:9000                       # Fail unless the instance is in sync
+//	housekeeper lint migrations                             # Check migrations for risky patterns
+//	housekeeper impact --url host:9000 migration.sql        # Preview a migration's blast radius
 //
 // # ClickHouse Integration
 //
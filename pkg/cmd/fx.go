@@ -5,15 +5,24 @@ import "go.uber.org/fx"
 var Module = fx.Module("cli",
 	fx.Provide(
 		fx.Annotate(bootstrap, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(bootstrapRevisions, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(check, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(coverage, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(dev, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(diff, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(diffFiles, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(fmtCmd, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(impact, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(initCmd, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(initFrom, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(lint, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(migrate, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(rehash, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(schema, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(snapshot, fx.ResultTags(`group:"commands"`)),
 		fx.Annotate(status, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(testCmd, fx.ResultTags(`group:"commands"`)),
+		fx.Annotate(verifyDown, fx.ResultTags(`group:"commands"`)),
 	),
 	fx.Invoke(Run),
 )
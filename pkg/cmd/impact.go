@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/urfave/cli/v3"
+)
+
+// impact returns a CLI command that previews how much data a migration would
+// touch or destroy on a live ClickHouse server.
+//
+// For each DROP TABLE, TRUNCATE TABLE, DROP DATABASE, DROP PARTITION, and
+// destructive ALTER operation in the migration, the command reads the targeted
+// object's row count and on-disk size from system.parts. The analysis is
+// advisory and read-only; the migration is never applied.
+//
+// The report ends with the risk score of the migration (see schema.ScoreDiff), which
+// classifies its statements against the server's current schema and weighs mutations
+// by the row counts read for the report.
+//
+// Command flags:
+//   - --url, -u: ClickHouse connection string (required)
+//
+// Example usage:
+//
+//	# Preview the blast radius of a migration
+//	housekeeper impact --url localhost:9000 db/migrations/20240101120000.sql
+func impact() *cli.Command {
+	return &cli.Command{
+		Name:      "impact",
+		Usage:     "Preview how much data a migration would touch or destroy",
+		ArgsUsage: "<migration.sql>",
+		Flags: []cli.Flag{
+			urlFlag,
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			path := cmd.Args().First()
+			if path == "" {
+				return errors.New("migration file is required")
+			}
+
+			migration, err := loadMigrationFile(path)
+			if err != nil {
+				return err
+			}
+
+			client, err := clickhouse.NewClient(ctx, cmd.String("url"))
+			if err != nil {
+				return errors.Wrap(err, "failed to create ClickHouse client")
+			}
+			defer client.Close()
+
+			impacts, err := migrator.AnalyzeImpact(ctx, client, migration)
+			if err != nil {
+				return errors.Wrap(err, "failed to analyze migration impact")
+			}
+
+			writeImpactReport(cmd.Writer, impacts)
+
+			// Snapshots record statements that have already been applied, so they change nothing
+			var changes schemapkg.Changes
+			if !migration.IsSnapshot {
+				current, err := client.GetSchema(ctx)
+				if err != nil {
+					return errors.Wrap(err, "failed to dump current schema")
+				}
+				changes = schemapkg.MigrationChanges(current, migration.Statements)
+			}
+
+			fmt.Fprintln(cmd.Writer)
+			printRiskReport(cmd.Writer, schemapkg.ScoreDiff(changes, impactRows(impacts)))
+			return nil
+		},
+	}
+}
+
+// loadMigrationFile parses the migration at path, using the file name as its version
+func loadMigrationFile(path string) (*migrator.Migration, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open migration %s", path)
+	}
+	defer file.Close()
+
+	version := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	migration, err := migrator.LoadMigration(version, file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load migration %s", path)
+	}
+
+	return migration, nil
+}
+
+// writeImpactReport prints the rows and size affected by each destructive
+// statement, followed by the totals.
+func writeImpactReport(w io.Writer, impacts []migrator.Impact) {
+	if len(impacts) == 0 {
+		fmt.Fprintln(w, "✅ No destructive statements found")
+		return
+	}
+
+	var totalRows, totalBytes uint64
+	for _, impact := range impacts {
+		totalRows += impact.Rows
+		totalBytes += impact.Bytes
+
+		fmt.Fprintf(w, "statement %d %s %s: %d rows, %s\n",
+			impact.Statement, impact.Operation, impact.Name(), impact.Rows, formatBytes(impact.Bytes))
+	}
+
+	fmt.Fprintf(w, "\nTotal: %d rows, %s across %d statement(s)\n", totalRows, formatBytes(totalBytes), len(impacts))
+}
+
+// impactRows returns the row counts of the tables targeted by whole-table impacts, used to
+// weigh the mutations of the migration by the amount of data they rewrite
+func impactRows(impacts []migrator.Impact) schemapkg.TableRows {
+	rows := make(schemapkg.TableRows)
+	for _, impact := range impacts {
+		if impact.Table != "" && impact.Partition == "" {
+			rows[impact.Name()] = impact.Rows
+		}
+	}
+
+	return rows
+}
+
+// formatBytes renders a byte count using binary units
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpactCommand_Structure(t *testing.T) {
+	command := impact()
+
+	require.Equal(t, "impact", command.Name)
+	require.NotNil(t, command.Action)
+	require.Len(t, command.Flags, 1)
+	require.Equal(t, "url", command.Flags[0].Names()[0])
+}
+
+func TestLoadMigrationFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "20240101120000_cleanup.sql")
+	require.NoError(t, os.WriteFile(path, []byte("DROP TABLE db.events;"), 0o600))
+
+	migration, err := loadMigrationFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "20240101120000_cleanup", migration.Version)
+	require.Len(t, migration.Statements, 1)
+
+	_, err = loadMigrationFile(filepath.Join(t.TempDir(), "missing.sql"))
+	require.ErrorContains(t, err, "failed to open migration")
+}
+
+func TestWriteImpactReport(t *testing.T) {
+	t.Run("no destructive statements", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeImpactReport(&buf, nil)
+		require.Contains(t, buf.String(), "No destructive statements found")
+	})
+
+	t.Run("reports each statement and totals", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeImpactReport(&buf, []migrator.Impact{
+			{
+				ImpactTarget: migrator.ImpactTarget{Statement: 1, Operation: "DROP TABLE", Database: "db", Table: "events"},
+				Rows:         1000,
+				Bytes:        3 * 1024 * 1024,
+			},
+			{
+				ImpactTarget: migrator.ImpactTarget{Statement: 2, Operation: "TRUNCATE TABLE", Table: "logs"},
+				Rows:         24,
+				Bytes:        512,
+			},
+		})
+
+		output := buf.String()
+		require.Contains(t, output, "statement 1 DROP TABLE db.events: 1000 rows, 3.0 MiB")
+		require.Contains(t, output, "statement 2 TRUNCATE TABLE logs: 24 rows, 512 B")
+		require.Contains(t, output, "Total: 1024 rows, 3.0 MiB across 2 statement(s)")
+	})
+}
+
+func TestImpactRows(t *testing.T) {
+	rows := impactRows([]migrator.Impact{
+		{ImpactTarget: migrator.ImpactTarget{Operation: "MODIFY COLUMN count", Database: "db", Table: "events"}, Rows: 1000},
+		{ImpactTarget: migrator.ImpactTarget{Operation: "DROP PARTITION", Database: "db", Table: "logs", Partition: "'2024-01-01'"}, Rows: 10},
+		{ImpactTarget: migrator.ImpactTarget{Operation: "DROP DATABASE", Database: "legacy"}, Rows: 5},
+	})
+	require.Equal(t, schemapkg.TableRows{"db.events": 1000}, rows, "only whole-table impacts report a table's row count")
+
+	// The row counts weigh the migration's mutations
+	current, err := parser.ParseString("CREATE TABLE db.events (id UInt64, count UInt32) ENGINE = MergeTree() ORDER BY id;")
+	require.NoError(t, err)
+	migration, err := parser.ParseString("ALTER TABLE db.events MODIFY COLUMN count UInt64;")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	printRiskReport(&buf, schemapkg.ScoreDiff(schemapkg.MigrationChanges(current, migration.Statements), rows))
+	require.Equal(t, "Migration risk: medium (weight 25)\n  - [medium] Alter table db.events (1000 rows)\n", buf.String())
+}
+
+func TestFormatBytes(t *testing.T) {
+	require.Equal(t, "0 B", formatBytes(0))
+	require.Equal(t, "1023 B", formatBytes(1023))
+	require.Equal(t, "1.5 KiB", formatBytes(1536))
+	require.Equal(t, "2.0 GiB", formatBytes(2*1024*1024*1024))
+}
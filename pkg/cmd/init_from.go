@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/project"
+	"github.com/urfave/cli/v3"
+)
+
+// initFrom returns a CLI command that initializes a new housekeeper project from an
+// existing schema SQL file. It's the offline counterpart to bootstrap, allowing teams
+// with a hand-written schema.sql to adopt the project layout without a running
+// ClickHouse server.
+//
+// The initialization process:
+//  1. Parses the schema file, failing before anything is written if it's invalid
+//  2. Organizes the statements into the standard project layout
+//  3. Creates the remaining project files (housekeeper.yaml, config.d, migrations)
+//
+// The resulting project structure matches the output of bootstrap:
+//   - db/main.sql: Main schema file with imports to all databases and global objects
+//   - db/schemas/_global/roles/<role>.sql: Individual role files with their grants
+//   - db/schemas/<database>/schema.sql: Database-specific schema with imports
+//   - db/schemas/<database>/tables/<table>.sql: Individual table files
+//   - db/schemas/<database>/dictionaries/<dict>.sql: Individual dictionary files
+//   - db/schemas/<database>/views/<view>.sql: Individual view files
+//
+// Example usage:
+//
+//	# Initialize a project from an existing schema file
+//	housekeeper init-from --schema schema.sql
+//
+//	# Initialize with a custom cluster name
+//	housekeeper init-from --schema schema.sql --cluster production
+func initFrom(p *project.Project) *cli.Command {
+	return &cli.Command{
+		Name:  "init-from",
+		Usage: "Initialize a project in the current directory from an existing schema file",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "schema",
+				Aliases:  []string{"s"},
+				Usage:    "Path to the schema SQL file to organize into the project",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "cluster",
+				Aliases: []string{"c"},
+				Usage:   "ClickHouse cluster name to use in configuration (defaults to 'cluster')",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			configPath := filepath.Join(p.Dir(), "housekeeper.yaml")
+			if _, err := os.Stat(configPath); err == nil {
+				return errors.Errorf("project already initialized in %s", p.Dir())
+			}
+
+			sql, err := parseSchemaFile(cmd.String("schema"))
+			if err != nil {
+				return err
+			}
+
+			// The generated schema files are written first so the templated main.sql
+			// from Initialize doesn't take the place of the one importing the schema.
+			if err := p.BootstrapFromSchema(sql); err != nil {
+				return err
+			}
+
+			return p.Initialize(project.InitOptions{
+				Cluster: cmd.String("cluster"),
+			})
+		},
+	}
+}
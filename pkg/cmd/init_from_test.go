@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/consts"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/project"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitFromCommand_SplitsSchemaIntoProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaPath := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`
+		CREATE ROLE IF NOT EXISTS analyst;
+		GRANT SELECT ON analytics.* TO analyst;
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE VIEW analytics.recent_events AS SELECT * FROM analytics.events;
+		CREATE DATABASE reporting ENGINE = Atomic;
+		CREATE TABLE reporting.daily (day Date) ENGINE = MergeTree() ORDER BY day;
+	`), consts.ModeFile))
+
+	proj := project.New(project.ProjectParams{
+		Dir:       tmpDir,
+		Formatter: format.New(format.Defaults),
+	})
+
+	command := initFrom(proj)
+	require.NoError(t, command.Run(t.Context(), []string{"init-from", "--schema", schemaPath, "--cluster", "production"}))
+
+	testutil.RequireValidProject(t, tmpDir)
+
+	cfg, err := config.LoadConfigFile(filepath.Join(tmpDir, "housekeeper.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "production", cfg.ClickHouse.Cluster)
+
+	for _, path := range []string{
+		"db/schemas/_global/schema.sql",
+		"db/schemas/_global/roles/analyst.sql",
+		"db/schemas/analytics/schema.sql",
+		"db/schemas/analytics/tables/events.sql",
+		"db/schemas/analytics/tables/users.sql",
+		"db/schemas/analytics/views/recent_events.sql",
+		"db/schemas/reporting/schema.sql",
+		"db/schemas/reporting/tables/daily.sql",
+	} {
+		require.FileExists(t, filepath.Join(tmpDir, path))
+	}
+
+	// main.sql imports the organized schema rather than the init template
+	mainSQL, err := os.ReadFile(filepath.Join(tmpDir, "db", "main.sql"))
+	require.NoError(t, err)
+	require.Contains(t, string(mainSQL), "-- housekeeper:import schemas/_global/schema.sql")
+	require.Contains(t, string(mainSQL), "-- housekeeper:import schemas/analytics/schema.sql")
+	require.Contains(t, string(mainSQL), "-- housekeeper:import schemas/reporting/schema.sql")
+
+	role, err := os.ReadFile(filepath.Join(tmpDir, "db", "schemas", "_global", "roles", "analyst.sql"))
+	require.NoError(t, err)
+	require.Contains(t, string(role), "TO `analyst`")
+
+	// Compiling the project yields the same schema as the original file
+	t.Chdir(tmpDir)
+	statements, err := compileProjectSchema(cfg)
+	require.NoError(t, err)
+	original, err := parseSchemaFile(schemaPath)
+	require.NoError(t, err)
+	_, err = schemapkg.GenerateDiff(original, &parser.SQL{Statements: statements})
+	require.ErrorIs(t, err, schemapkg.ErrNoDiff)
+}
+
+func TestInitFromCommand_InvalidSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaPath := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(schemaPath, []byte("CREATE TABLE ("), consts.ModeFile))
+
+	proj := project.New(project.ProjectParams{
+		Dir:       tmpDir,
+		Formatter: format.New(format.Defaults),
+	})
+
+	err := initFrom(proj).Run(t.Context(), []string{"init-from", "--schema", schemaPath})
+	require.ErrorContains(t, err, "failed to parse schema file")
+	require.NoFileExists(t, filepath.Join(tmpDir, "housekeeper.yaml"))
+}
+
+func TestInitFromCommand_AlreadyInitialized(t *testing.T) {
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	schemaPath := filepath.Join(t.TempDir(), "schema.sql")
+	require.NoError(t, os.WriteFile(schemaPath, []byte("CREATE DATABASE analytics ENGINE = Atomic;"), consts.ModeFile))
+
+	err := initFrom(fixture.Project).Run(t.Context(), []string{"init-from", "--schema", schemaPath})
+	require.ErrorContains(t, err, "project already initialized")
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/urfave/cli/v3"
+)
+
+// lint returns a CLI command that provides static checks for project files.
+// This command serves as a parent for all lint categories.
+//
+// Available subcommands:
+//   - migrations: Detect dangerous patterns in migration files
+//
+// Example usage:
+//
+//	# Lint all migration files
+//	housekeeper lint migrations
+func lint(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "Check project files for risky patterns",
+		Commands: []*cli.Command{
+			lintMigrations(cfg),
+		},
+	}
+}
+
+// lintMigrations returns a CLI command that checks the migrations in the project's
+// migration directory for dangerous patterns before they reach production:
+//   - Migrations mixing schema changes with data operations
+//   - DROP statements without a preceding FREEZE or copy of the data
+//   - ALTER operations that trigger expensive mutations on large tables
+//   - Renames that can break dependent views
+//   - Engine names that don't use ClickHouse's canonical casing (e.g. mergetree)
+//
+// Each finding is reported with its migration version, statement number, and
+// severity. The command fails when any finding has error severity.
+//
+// Example usage:
+//
+//	housekeeper lint migrations
+func lintMigrations(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "migrations",
+		Usage:  "Detect dangerous patterns in migration files",
+		Before: requireConfig(cfg),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			migrationDir, err := migrator.LoadMigrationDir(os.DirFS(cfg.Dir))
+			if err != nil {
+				return errors.Wrap(err, "failed to load migrations")
+			}
+
+			return writeLintFindings(cmd.Writer, migrationDir.Lint())
+		},
+	}
+}
+
+// writeLintFindings prints each finding to w and returns an error when any of the
+// findings has error severity.
+func writeLintFindings(w io.Writer, findings []migrator.LintFinding) error {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "✅ No issues found")
+		return nil
+	}
+
+	errorCount := 0
+	for _, finding := range findings {
+		if finding.Severity == migrator.LintError {
+			errorCount++
+		}
+
+		fmt.Fprintf(w, "%s statement %d [%s] %s: %s\n",
+			finding.Version, finding.Statement, finding.Severity, finding.Rule, finding.Message)
+	}
+
+	if errorCount > 0 {
+		return errors.Errorf("lint found %d error(s) and %d warning(s)", errorCount, len(findings)-errorCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintCommand_Structure(t *testing.T) {
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	command := lint(fixture.Config)
+
+	require.Equal(t, "lint", command.Name)
+	require.Len(t, command.Commands, 1)
+
+	migrations := command.Commands[0]
+	require.Equal(t, "migrations", migrations.Name)
+	require.NotNil(t, migrations.Before)
+	require.NotNil(t, migrations.Action)
+}
+
+func TestWriteLintFindings(t *testing.T) {
+	warning := migrator.LintFinding{
+		Version:   "002_users",
+		Statement: 1,
+		Severity:  migrator.LintWarning,
+		Rule:      migrator.LintRuleExpensiveMutation,
+		Message:   "MODIFY COLUMN id on test.users triggers a mutation",
+	}
+	failure := migrator.LintFinding{
+		Version:   "003_cleanup",
+		Statement: 2,
+		Severity:  migrator.LintError,
+		Rule:      migrator.LintRuleDropWithoutBackup,
+		Message:   "DROP TABLE test.users permanently deletes its data",
+	}
+
+	t.Run("no findings", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeLintFindings(&buf, nil))
+		require.Contains(t, buf.String(), "No issues found")
+	})
+
+	t.Run("warnings only", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeLintFindings(&buf, []migrator.LintFinding{warning}))
+		require.Contains(t, buf.String(), "002_users statement 1 [WARNING] expensive-mutation:")
+	})
+
+	t.Run("errors fail", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := writeLintFindings(&buf, []migrator.LintFinding{warning, failure})
+		require.EqualError(t, err, "lint found 1 error(s) and 1 warning(s)")
+		require.Contains(t, buf.String(), "003_cleanup statement 2 [ERROR] drop-without-backup:")
+	})
+}
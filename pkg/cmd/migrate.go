@@ -36,6 +36,8 @@ type migrateParams struct {
 //   - --url, -u: ClickHouse connection string (required)
 //   - --dry-run: Show what would be executed without applying changes
 //   - --cluster: ClickHouse cluster name for distributed deployments
+//   - --lock-ttl: How long another process's migration lock is honored (default 15m)
+//   - --statement-timeout: Cancel and fail any statement running longer than this (default none)
 //
 // Example usage:
 //
@@ -45,6 +47,9 @@ type migrateParams struct {
 //	# Show what would be executed without applying
 //	housekeeper migrate --url localhost:9000 --dry-run
 //
+//	# Fail the migration if any statement runs longer than 10 minutes
+//	housekeeper migrate --url localhost:9000 --statement-timeout 10m
+//
 //	# Apply migrations with cluster support
 //	housekeeper migrate --url localhost:9000 --cluster production_cluster
 //
@@ -62,9 +67,12 @@ schema to match the current migration state. Each migration is executed atomical
 if any statement fails, the migration is marked as failed and execution stops.
 
 The command automatically handles:
+- Validation of migration files against housekeeper.sum before anything is applied
 - Bootstrap of housekeeper.revisions tracking table on first run
+- Locking via housekeeper.migration_lock so concurrent runs don't apply migrations twice
 - Detection of already-applied migrations to avoid duplicate execution
 - Automatic resume of partially failed migrations from their failure points
+- Cancelling statements that run longer than --statement-timeout
 - Comprehensive error reporting with statement-level details
 - Progress tracking and execution timing
 - Integration with cluster-aware ClickHouse deployments
@@ -80,34 +88,19 @@ convention: yyyyMMddHHmmss_description.sql`,
 				Usage: "Show what would be executed without applying changes",
 				Value: false,
 			},
-			&cli.StringFlag{
-				Name:  "cluster",
-				Usage: "ClickHouse cluster name for distributed deployments",
-				Config: cli.StringConfig{
-					TrimSpace: true,
-				},
-			},
-			&cli.StringFlag{
-				Name:  "cafile",
-				Usage: "Certificate authority pem",
-				Config: cli.StringConfig{
-					TrimSpace: true,
-				},
+			clusterFlag,
+			&cli.DurationFlag{
+				Name:  "lock-ttl",
+				Usage: "How long another process's migration lock is honored before it's considered abandoned",
+				Value: executor.DefaultLockTTL,
 			},
-			&cli.StringFlag{
-				Name:  "certfile",
-				Usage: "Certificate public key file",
-				Config: cli.StringConfig{
-					TrimSpace: true,
-				},
-			},
-			&cli.StringFlag{
-				Name:  "keyfile",
-				Usage: "Certificate private key file",
-				Config: cli.StringConfig{
-					TrimSpace: true,
-				},
+			&cli.DurationFlag{
+				Name:  "statement-timeout",
+				Usage: "Cancel and fail a migration statement that runs longer than this (0 for no limit)",
 			},
+			caFileFlag,
+			certFileFlag,
+			keyFileFlag,
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			return runMigrate(ctx, cmd, p)
@@ -149,14 +142,7 @@ func runMigrate(ctx context.Context, cmd *cli.Command, p migrateParams) error {
 	slog.Info("Loaded migrations", "count", len(migrations))
 
 	// Create ClickHouse client
-	client, err := clickhouse.NewClientWithOptions(ctx, url, clickhouse.ClientOptions{
-		Cluster: cluster,
-		TLSSettings: clickhouse.TLSSettings{
-			CAFile:   ca,
-			CertFile: cert,
-			KeyFile:  key,
-		},
-	})
+	client, err := clickhouse.NewClientWithOptions(ctx, url, clientOptions(cmd))
 	if err != nil {
 		return errors.Wrap(err, "failed to create ClickHouse client")
 	}
@@ -181,6 +167,7 @@ func runMigrate(ctx context.Context, cmd *cli.Command, p migrateParams) error {
 		ClickHouse:         client,
 		Formatter:          p.Formatter,
 		HousekeeperVersion: p.Version.Version,
+		StatementTimeout:   cmd.Duration("statement-timeout"),
 	})
 
 	// Check if bootstrap is needed
@@ -193,14 +180,19 @@ func runMigrate(ctx context.Context, cmd *cli.Command, p migrateParams) error {
 		fmt.Println("Initializing housekeeper migration tracking infrastructure...")
 	}
 
-	// Execute migrations
-	results, err := exec.Execute(ctx, migrations)
-	if err != nil {
+	// Validate and execute migrations
+	report, err := exec.Apply(ctx, migrationDir, executor.ApplyOptions{
+		Locker: exec.NewTableLocker(cmd.Duration("lock-ttl")),
+	})
+	if report.Lock == executor.LockHeld {
+		return errors.Wrap(err, "migrations already running")
+	}
+	if err != nil && report.Failed() == nil {
 		return errors.Wrap(err, "failed to execute migrations")
 	}
 
 	// Report results
-	return reportResults(results)
+	return reportResults(report.Results)
 }
 
 func testConnection(ctx context.Context, client *clickhouse.Client) error {
@@ -3,11 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/pkg/errors"
-	"github.com/pseudomuto/housekeeper/pkg/consts"
 	"github.com/pseudomuto/housekeeper/pkg/migrator"
 	"github.com/pseudomuto/housekeeper/pkg/project"
 	"github.com/urfave/cli/v3"
@@ -25,19 +27,33 @@ import (
 // 1. Loads all existing migrations from the migrations directory
 // 2. Recalculates SHA256 hashes for each migration file
 // 3. Generates a new sum file with updated integrity verification data
-// 4. Writes the updated sum file to disk
+// 4. Atomically replaces the sum file on disk
+//
+// With --dry-run the sum file is left untouched. The command prints the sum file lines
+// that would change and fails if there are any, so CI can check that housekeeper.sum
+// is up to date.
 //
 // Example usage:
 //
 //	# Regenerate sum file for all migrations
 //	housekeeper rehash
 //
+//	# Check the sum file is up to date without modifying it
+//	housekeeper rehash --dry-run
+//
 // The command will output the status of the rehashing operation and indicate
 // how many migration files were processed.
 func rehash(p *project.Project) *cli.Command {
 	return &cli.Command{
 		Name:  "rehash",
 		Usage: "Regenerate the sum file for all migrations",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Report whether the sum file is out of date without modifying it",
+				Value: false,
+			},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			migrationsDir := p.MigrationsDir()
 
@@ -46,33 +62,26 @@ func rehash(p *project.Project) *cli.Command {
 				return errors.Errorf("migrations directory does not exist: %s", migrationsDir)
 			}
 
-			// Load migration directory
-			migrationDir, err := migrator.LoadMigrationDir(os.DirFS(migrationsDir))
+			// Load migration directory. The existing sum file is about to be replaced, so it's
+			// ignored to allow rehashing to repair a corrupt one.
+			migrationDir, err := migrator.LoadMigrationDir(withoutSumFiles{os.DirFS(migrationsDir)})
 			if err != nil {
 				return errors.Wrap(err, "failed to load migration directory")
 			}
 
+			sumPath := filepath.Join(migrationsDir, "housekeeper.sum")
+			if cmd.Bool("dry-run") {
+				return rehashDryRun(cmd.Writer, migrationDir, sumPath)
+			}
+
 			// Rehash all migrations
 			if err := migrationDir.Rehash(); err != nil {
 				return errors.Wrap(err, "failed to rehash migrations")
 			}
 
 			// Write the updated sum file
-			sumFilePath := filepath.Join(migrationsDir, "housekeeper.sum")
-			sumFile, err := os.Create(sumFilePath)
-			if err != nil {
-				return errors.Wrapf(err, "failed to create sum file: %s", sumFilePath)
-			}
-			defer sumFile.Close()
-
-			_, err = migrationDir.SumFile.WriteTo(sumFile)
-			if err != nil {
-				return errors.Wrap(err, "failed to write sum file")
-			}
-
-			// Set appropriate file permissions
-			if err := os.Chmod(sumFilePath, consts.ModeFile); err != nil {
-				return errors.Wrapf(err, "failed to set permissions on sum file: %s", sumFilePath)
+			if err := migrationDir.WriteSumFile(sumPath); err != nil {
+				return err
 			}
 
 			// Output success message
@@ -84,9 +93,58 @@ func rehash(p *project.Project) *cli.Command {
 	}
 }
 
+// rehashDryRun compares the sum file at sumPath with the one rehash would write,
+// printing the lines that differ. A missing sum file is compared as an empty one.
+func rehashDryRun(w io.Writer, migrationDir *migrator.MigrationDir, sumPath string) error {
+	migrationDir.SumFile = migrator.NewSumFile()
+
+	f, err := os.Open(sumPath)
+	switch {
+	case err == nil:
+		defer func() { _ = f.Close() }()
+
+		if migrationDir.SumFile, err = migrator.LoadSumFile(f); err != nil {
+			return errors.Wrapf(err, "failed to load sum file: %s", sumPath)
+		}
+	case !os.IsNotExist(err):
+		return errors.Wrapf(err, "failed to open sum file: %s", sumPath)
+	}
+
+	changed, diff, err := migrationDir.RehashDryRun()
+	if err != nil {
+		return errors.Wrap(err, "failed to rehash migrations")
+	}
+
+	if !changed {
+		fmt.Fprintf(w, "Sum file is up to date (%d migration(s))\n", len(migrationDir.Migrations))
+		return nil
+	}
+
+	fmt.Fprint(w, diff)
+	return errors.New("sum file is out of date: run housekeeper rehash to update it")
+}
+
 // TestableRehash creates a testable version of the rehash command for use in unit tests.
 // This function exposes the same functionality as the main rehash command but allows
 // for easier testing by accepting a project parameter directly.
 func TestableRehash(p *project.Project) *cli.Command {
 	return rehash(p)
 }
+
+// withoutSumFiles hides .sum files from a migration directory so that loading it doesn't
+// fail on a corrupt sum file.
+type withoutSumFiles struct {
+	fs.FS
+}
+
+// ReadDir implements fs.ReadDirFS, omitting .sum files from the listing.
+func (f withoutSumFiles) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(f.FS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(entries, func(entry fs.DirEntry) bool {
+		return filepath.Ext(entry.Name()) == ".sum"
+	}), nil
+}
@@ -251,7 +251,71 @@ func TestRehashCommand_CommandStructure(t *testing.T) {
 
 	require.Equal(t, "rehash", command.Name)
 	require.Equal(t, "Regenerate the sum file for all migrations", command.Usage)
-	require.Empty(t, command.Flags) // No flags
+	require.Len(t, command.Flags, 1)
+	require.Equal(t, "dry-run", command.Flags[0].Names()[0])
+}
+
+func TestRehashCommand_DryRun(t *testing.T) {
+	run := func(t *testing.T, fixture *testutil.ProjectFixture, args ...string) (string, error) {
+		t.Helper()
+
+		var buf bytes.Buffer
+		command := rehash(fixture.Project)
+		command.Writer = &buf
+		err := command.Run(context.Background(), append([]string{"rehash"}, args...))
+		return buf.String(), err
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		fixture := testutil.TestProject(t).
+			WithMigrations(testutil.MinimalMigrations())
+		defer fixture.Cleanup()
+
+		_, err := run(t, fixture)
+		require.NoError(t, err)
+
+		output, err := run(t, fixture, "--dry-run")
+		require.NoError(t, err)
+		require.Contains(t, output, "Sum file is up to date (2 migration(s))")
+	})
+
+	t.Run("out of date", func(t *testing.T) {
+		fixture := testutil.TestProject(t).
+			WithMigrations(testutil.MinimalMigrations())
+		defer fixture.Cleanup()
+
+		_, err := run(t, fixture)
+		require.NoError(t, err)
+
+		sumPath := filepath.Join(fixture.GetMigrationsDir(), "housekeeper.sum")
+		original, err := os.ReadFile(sumPath)
+		require.NoError(t, err)
+
+		migrationPath := filepath.Join(fixture.GetMigrationsDir(), "002_users.sql")
+		require.NoError(t, os.WriteFile(migrationPath, []byte("CREATE DATABASE other;"), consts.ModeFile))
+
+		output, err := run(t, fixture, "--dry-run")
+		require.ErrorContains(t, err, "sum file is out of date")
+		require.Contains(t, output, "-002_users.sql h1:")
+		require.Contains(t, output, "+002_users.sql h1:")
+		require.NotContains(t, output, "001_init.sql")
+
+		// The sum file is left untouched
+		current, err := os.ReadFile(sumPath)
+		require.NoError(t, err)
+		require.Equal(t, original, current)
+	})
+
+	t.Run("missing sum file", func(t *testing.T) {
+		fixture := testutil.TestProject(t).
+			WithMigrations(testutil.MinimalMigrations())
+		defer fixture.Cleanup()
+
+		output, err := run(t, fixture, "--dry-run")
+		require.ErrorContains(t, err, "sum file is out of date")
+		require.Contains(t, output, "+001_init.sql h1:")
+		require.NoFileExists(t, filepath.Join(fixture.GetMigrationsDir(), "housekeeper.sum"))
+	})
 }
 
 func TestRehashCommand_ReadOnlyMigrationsDir(t *testing.T) {
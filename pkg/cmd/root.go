@@ -7,6 +7,7 @@ import (
 	"slices"
 
 	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
 	"github.com/pseudomuto/housekeeper/pkg/config"
 	"github.com/pseudomuto/housekeeper/pkg/project"
 	"github.com/urfave/cli/v3"
@@ -61,6 +62,55 @@ var urlFlag = &cli.StringFlag{
 	},
 }
 
+// Connection flags shared by the commands that change a live database, so they can reach
+// every node of a cluster and connect over (m)TLS. See clientOptions.
+var (
+	clusterFlag = &cli.StringFlag{
+		Name:  "cluster",
+		Usage: "ClickHouse cluster name for distributed deployments",
+		Config: cli.StringConfig{
+			TrimSpace: true,
+		},
+	}
+
+	caFileFlag = &cli.StringFlag{
+		Name:  "cafile",
+		Usage: "Certificate authority pem",
+		Config: cli.StringConfig{
+			TrimSpace: true,
+		},
+	}
+
+	certFileFlag = &cli.StringFlag{
+		Name:  "certfile",
+		Usage: "Certificate public key file",
+		Config: cli.StringConfig{
+			TrimSpace: true,
+		},
+	}
+
+	keyFileFlag = &cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Certificate private key file",
+		Config: cli.StringConfig{
+			TrimSpace: true,
+		},
+	}
+)
+
+// clientOptions builds the ClickHouse client options from the --cluster, --cafile,
+// --certfile, and --keyfile flags.
+func clientOptions(cmd *cli.Command) clickhouse.ClientOptions {
+	return clickhouse.ClientOptions{
+		Cluster: cmd.String("cluster"),
+		TLSSettings: clickhouse.TLSSettings{
+			CAFile:   cmd.String("cafile"),
+			CertFile: cmd.String("certfile"),
+			KeyFile:  cmd.String("keyfile"),
+		},
+	}
+}
+
 // Run creates and executes the main housekeeper CLI application with the given
 // version and command-line arguments. This function serves as the main entry
 // point for all CLI operations and handles global configuration.
@@ -163,6 +163,14 @@ func writeSnapshotAndUpdateMigrations(w io.Writer, migrationsDir, version string
 			} else {
 				fmt.Fprintf(w, "✓ Removed migration file: %s\n", migPath)
 			}
+
+			// The migration's down file can't outlive it, since it would no longer load
+			downPath := filepath.Join(migrationsDir, migVersion+migrator.DownFileSuffix)
+			if err := os.Remove(downPath); err == nil {
+				fmt.Fprintf(w, "✓ Removed down migration file: %s\n", downPath)
+			} else if !os.IsNotExist(err) {
+				fmt.Fprintf(w, "Warning: Could not remove down migration file %s: %v\n", downPath, err)
+			}
 		}
 	}
 
@@ -176,15 +184,5 @@ func writeSnapshotAndUpdateMigrations(w io.Writer, migrationsDir, version string
 		return errors.Wrap(err, "failed to compute new migration hash")
 	}
 
-	sf, err := os.Create(filepath.Join(migrationsDir, "housekeeper.sum"))
-	if err != nil {
-		return errors.Wrapf(err, "failed to create sum file")
-	}
-	defer func() { _ = sf.Close() }()
-
-	if _, err := dir.SumFile.WriteTo(sf); err != nil {
-		return errors.Wrapf(err, "failed to write sum file")
-	}
-
-	return nil
+	return dir.WriteSumFile(filepath.Join(migrationsDir, "housekeeper.sum"))
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 
@@ -31,6 +32,7 @@ type statusParams struct {
 //   - --env: Environment name for migration directory (default: "migrations")
 //   - --cluster: ClickHouse cluster name for distributed deployments
 //   - --verbose: Show detailed migration information
+//   - --with-stats: Show the row count and on-disk size of each managed table
 //
 // Example usage:
 //
@@ -42,6 +44,9 @@ type statusParams struct {
 //
 //	# Show status with cluster support
 //	housekeeper status --url localhost:9000 --cluster production_cluster
+//
+//	# Show the footprint of the managed tables
+//	housekeeper status --url localhost:9000 --with-stats
 func status(p statusParams) *cli.Command {
 	return &cli.Command{
 		Name:  "status",
@@ -52,6 +57,8 @@ The status command shows:
 - Total number of migration files found
 - Number of completed, pending, and failed migrations
 - Execution history with timing information (when --verbose is used)
+- Statement time percentiles and the slowest statements (when --verbose is used)
+- Row count and on-disk size of each managed table (when --with-stats is used)
 - Last migration execution details
 - Bootstrap status of housekeeper infrastructure
 
@@ -75,6 +82,11 @@ This command is useful for:
 				Usage: "Show detailed migration information",
 				Value: false,
 			},
+			&cli.BoolFlag{
+				Name:  "with-stats",
+				Usage: "Show the row count and on-disk size of each managed table (queries system.parts)",
+				Value: false,
+			},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			return runStatus(ctx, cmd, p)
@@ -86,6 +98,7 @@ func runStatus(ctx context.Context, cmd *cli.Command, p statusParams) error {
 	url := cmd.String("url")
 	cluster := cmd.String("cluster")
 	verbose := cmd.Bool("verbose")
+	withStats := cmd.Bool("with-stats")
 
 	slog.Info("Checking migration status",
 		"cluster", cluster,
@@ -121,7 +134,21 @@ func runStatus(ctx context.Context, cmd *cli.Command, p statusParams) error {
 	}
 
 	// Display status with revisions
-	return displayStatusWithRevisions(ctx, client, migrations, verbose)
+	if err := displayStatusWithRevisions(ctx, client, migrations, verbose); err != nil {
+		return err
+	}
+
+	if withStats {
+		stats, err := migrator.CollectTableStats(ctx, client, migrations)
+		if err != nil {
+			return errors.Wrap(err, "failed to collect table stats")
+		}
+
+		fmt.Println()
+		writeTableStats(os.Stdout, stats)
+	}
+
+	return nil
 }
 
 func loadAndValidateMigrations(dir string) ([]*migrator.Migration, error) {
@@ -181,6 +208,7 @@ func displayStatusWithRevisions(ctx context.Context, client *clickhouse.Client,
 
 	if verbose {
 		showVerboseStatus(migrations, revisionSet)
+		showStatementTimings(revisionSet)
 	}
 
 	showRecommendations(pending, failed)
@@ -309,3 +337,45 @@ func showVerboseStatus(migrations []*migrator.Migration, revisionSet *migrator.R
 		fmt.Println()
 	}
 }
+
+// slowStatementLimit is the number of slowest statements shown in verbose status output
+const slowStatementLimit = 5
+
+func showStatementTimings(revisionSet *migrator.RevisionSet) {
+	slowest := revisionSet.SlowestStatements(slowStatementLimit)
+	if len(slowest) == 0 {
+		return
+	}
+
+	fmt.Println("⏱  Statement execution times:")
+	fmt.Printf("   p50: %v, p95: %v, p99: %v\n",
+		revisionSet.StatementTimePercentile(50),
+		revisionSet.StatementTimePercentile(95),
+		revisionSet.StatementTimePercentile(99))
+	fmt.Println("   Slowest statements:")
+	for _, timing := range slowest {
+		fmt.Printf("     %s statement %d: %v\n", timing.Version, timing.Statement, timing.Duration)
+	}
+	fmt.Println()
+}
+
+// writeTableStats prints the row count and on-disk size of each managed table,
+// followed by the totals.
+func writeTableStats(w io.Writer, stats []migrator.TableStats) {
+	if len(stats) == 0 {
+		fmt.Fprintln(w, "📦 No managed tables found")
+		return
+	}
+
+	fmt.Fprintln(w, "📦 Managed tables:")
+
+	var totalRows, totalBytes uint64
+	for _, s := range stats {
+		totalRows += s.Rows
+		totalBytes += s.Bytes
+
+		fmt.Fprintf(w, "  %s: %d rows, %s\n", s.Name(), s.Rows, formatBytes(s.Bytes))
+	}
+
+	fmt.Fprintf(w, "  Total: %d rows, %s across %d table(s)\n", totalRows, formatBytes(totalBytes), len(stats))
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
 	"github.com/pseudomuto/housekeeper/pkg/consts"
 	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
 	"github.com/stretchr/testify/require"
 )
 
@@ -183,6 +185,7 @@ func TestStatusCommand_CommandStructure(t *testing.T) {
 	urlFlag := false
 	clusterFlag := false
 	verboseFlag := false
+	withStatsFlag := false
 
 	for _, flag := range command.Flags {
 		switch flag.Names()[0] {
@@ -192,10 +195,35 @@ func TestStatusCommand_CommandStructure(t *testing.T) {
 			clusterFlag = true
 		case "verbose":
 			verboseFlag = true
+		case "with-stats":
+			withStatsFlag = true
 		}
 	}
 
 	require.True(t, urlFlag, "Should have url flag")
 	require.True(t, clusterFlag, "Should have cluster flag")
 	require.True(t, verboseFlag, "Should have verbose flag")
+	require.True(t, withStatsFlag, "Should have with-stats flag")
+}
+
+func TestWriteTableStats(t *testing.T) {
+	t.Run("no managed tables", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeTableStats(&buf, nil)
+		require.Equal(t, "📦 No managed tables found\n", buf.String())
+	})
+
+	t.Run("reports each table and totals", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeTableStats(&buf, []migrator.TableStats{
+			{Database: "db", Table: "events", Rows: 1000, Bytes: 3 * 1024 * 1024},
+			{Table: "logs", Rows: 24, Bytes: 512},
+		})
+
+		require.Equal(t, `📦 Managed tables:
+  db.events: 1000 rows, 3.0 MiB
+  logs: 24 rows, 512 B
+  Total: 1024 rows, 3.0 MiB across 2 table(s)
+`, buf.String())
+	})
 }
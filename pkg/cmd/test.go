@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/docker"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	testContainerName = "housekeeper-test"
+
+	// defaultTestConcurrency is the default number of environments tested concurrently.
+	// Each one runs its own ClickHouse container, so it's lower than defaultMaxParallelChecks.
+	defaultTestConcurrency = 2
+)
+
+// testCmd creates a CLI command that validates the full migration sequence by
+// applying every migration from scratch against an ephemeral ClickHouse container.
+//
+// This catches ordering and syntax issues that offline parsing cannot detect, such
+// as a table being created before its database or a function that the configured
+// ClickHouse version does not support. The container is always torn down afterward,
+// making the command suitable as a CI gate.
+//
+// With --verify-roundtrip, the schema of the container is dumped after the
+// migrations have been applied and compared against the compiled project schema.
+// Any difference means the migrations do not produce the declared schema.
+//
+// With --all-envs (or one or more --env flags) the migrations are tested once for each
+// environment configured in housekeeper.yaml, using the environment's cluster. Every
+// environment gets its own container, and at most --concurrency containers run at a
+// time. Every environment is tested even when others fail, its containers are torn
+// down even when its test fails or panics, and the results are aggregated into a
+// single report.
+//
+// Example usage:
+//
+//	housekeeper test
+//	housekeeper test --verify-roundtrip
+//	housekeeper test --all-envs --concurrency 3
+func testCmd(cfg *config.Config, client docker.DockerClient) *cli.Command {
+	return &cli.Command{
+		Name:   "test",
+		Usage:  "Apply all migrations to an ephemeral ClickHouse instance",
+		Before: requireConfig(cfg),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "verify-roundtrip",
+				Usage: "Verify the migrated schema matches the compiled project schema",
+				Value: false,
+			},
+			&cli.BoolFlag{
+				Name:  "all-envs",
+				Usage: "Test the migrations for every environment configured in housekeeper.yaml",
+			},
+			&cli.StringSliceFlag{
+				Name:  "env",
+				Usage: "Test the migrations for the named environment from housekeeper.yaml (repeatable)",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Maximum number of environments tested concurrently, each in its own container",
+				Value: defaultTestConcurrency,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Bool("all-envs") || len(cmd.StringSlice("env")) > 0 {
+				return runTestEnvironments(ctx, cmd, cfg, client)
+			}
+
+			if err := runMigrationTest(ctx, cmd.Writer, cmd.ErrWriter, cfg, client, testContainerName, cmd.Bool("verify-roundtrip")); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.Writer, "Migration test passed: all migrations applied cleanly to a fresh ClickHouse instance")
+			return nil
+		},
+	}
+}
+
+// runTestEnvironments tests the migrations for the selected environments concurrently
+// and prints the output of each environment once they're all done
+func runTestEnvironments(ctx context.Context, cmd *cli.Command, cfg *config.Config, client docker.DockerClient) error {
+	envs, err := cfg.GetEnvironments(cmd.StringSlice("env"))
+	if err != nil {
+		return err
+	}
+
+	if len(envs) == 0 {
+		return errors.New("no environments configured in housekeeper.yaml")
+	}
+
+	outputs := make([]bytes.Buffer, len(envs))
+	errs := forEachEnvironment(envs, int(cmd.Int("concurrency")), func(i int, env config.Environment) error {
+		envCfg := *cfg
+		if env.Cluster != "" {
+			envCfg.ClickHouse.Cluster = env.Cluster
+		}
+
+		return runMigrationTest(ctx, &outputs[i], &outputs[i], &envCfg, client, testContainerName+"-"+env.Name, cmd.Bool("verify-roundtrip"))
+	})
+
+	var failed []string
+	for i, env := range envs {
+		fmt.Fprintf(cmd.Writer, "=== %s ===\n", env.Name)
+		_, _ = outputs[i].WriteTo(cmd.Writer)
+
+		if errs[i] != nil {
+			fmt.Fprintf(cmd.Writer, "❌ Migration test failed: %v\n", errs[i])
+			failed = append(failed, env.Name)
+			continue
+		}
+
+		fmt.Fprintln(cmd.Writer, "Migration test passed: all migrations applied cleanly to a fresh ClickHouse instance")
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("migration test failed for %d of %d environment(s): %s", len(failed), len(envs), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// runMigrationTest applies every migration to a fresh container with the given name,
+// verifying the roundtrip when requested. The container is torn down before returning,
+// even when a step fails or panics.
+func runMigrationTest(ctx context.Context, w, errW io.Writer, cfg *config.Config, client docker.DockerClient, name string, verify bool) error {
+	devCfg := loadDevConfigFromConfig(cfg)
+
+	container, chClient, err := runContainer(ctx, w, docker.DockerOptions{
+		Version:   devCfg.version,
+		ConfigDir: devCfg.configDir,
+		Name:      name,
+	}, cfg, client)
+	if err != nil {
+		return errors.Wrap(err, "migration test failed")
+	}
+
+	defer func() {
+		_ = chClient.Close()
+		if stopErr := container.Stop(context.WithoutCancel(ctx)); stopErr != nil {
+			fmt.Fprintf(errW, "Warning: failed to stop container: %v\n", stopErr)
+		}
+	}()
+
+	if verify {
+		return verifyRoundtrip(ctx, w, chClient, cfg)
+	}
+
+	return nil
+}
+
+// verifyRoundtrip dumps the schema produced by the applied migrations and compares it
+// against the compiled project schema, printing any statements needed to reconcile them.
+func verifyRoundtrip(ctx context.Context, w io.Writer, client *clickhouse.Client, cfg *config.Config) error {
+	currentSchema, err := client.GetSchema(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to dump migrated schema")
+	}
+
+	targetStatements, err := compileProjectSchema(cfg)
+	if err != nil {
+		return err
+	}
+
+	diff, err := schemapkg.GenerateDiff(currentSchema, &parser.SQL{Statements: targetStatements})
+	if err != nil {
+		if errors.Is(err, schemapkg.ErrNoDiff) {
+			fmt.Fprintln(w, "Roundtrip verified: migrated schema matches the project schema")
+			return nil
+		}
+		return errors.Wrap(err, "failed to compare migrated schema")
+	}
+
+	fmt.Fprintln(w, "Migrated schema does not match the project schema. Missing changes:")
+	if err := cfg.GetFormatter().Format(w, diff.Statements...); err != nil {
+		return errors.Wrap(err, "failed to format schema diff")
+	}
+	fmt.Fprintln(w)
+
+	return errors.New("migrations do not reproduce the project schema")
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestTestCommand_Structure(t *testing.T) {
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	command := testCmd(fixture.Config, testutil.NewMockDockerClient())
+
+	require.Equal(t, "test", command.Name)
+	require.Equal(t, "Apply all migrations to an ephemeral ClickHouse instance", command.Usage)
+	require.NotNil(t, command.Before)
+	require.NotNil(t, command.Action)
+}
+
+func TestTestCommand_ContainerFailure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	command := testCmd(fixture.Config, testutil.NewMockDockerClient())
+
+	var buf bytes.Buffer
+	err := command.Action(context.Background(), &cli.Command{
+		Flags:     command.Flags,
+		Writer:    &buf,
+		ErrWriter: &buf,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "migration test failed")
+	require.NotContains(t, buf.String(), "Migration test passed")
+}
+
+func TestTestCommand_Environments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixture := testutil.TestProject(t).WithMigrations(testutil.MinimalMigrations())
+	defer fixture.Cleanup()
+	t.Chdir(fixture.Dir)
+	writeTestSumFile(t, fixture.Config.Dir)
+
+	fixture.Config.Environments = []config.Environment{
+		{Name: "staging", URL: "staging-host:9000"},
+		{Name: "prod", URL: "prod-host:9000"},
+	}
+
+	// The containers never become reachable: staging fails to report its ports, and
+	// inspecting prod panics
+	var (
+		mu                sync.Mutex
+		running, maxAlive int
+		stopped           []string
+	)
+	dockerClient := testutil.NewMockDockerClient()
+	dockerClient.ContainerStartFunc = func(context.Context, string, container.StartOptions) error {
+		mu.Lock()
+		defer mu.Unlock()
+		running++
+		maxAlive = max(maxAlive, running)
+		return nil
+	}
+	dockerClient.ContainerStopFunc = func(_ context.Context, name string, _ container.StopOptions) error {
+		mu.Lock()
+		defer mu.Unlock()
+		running--
+		stopped = append(stopped, name)
+		return nil
+	}
+	dockerClient.ContainerInspectFunc = func(_ context.Context, name string) (container.InspectResponse, error) {
+		if name == "housekeeper-test-prod" {
+			panic("inspect failed")
+		}
+		return container.InspectResponse{}, testutil.ErrContainerNotFound
+	}
+
+	var buf bytes.Buffer
+	command := testCmd(fixture.Config, dockerClient)
+	command.Writer = &buf
+	command.ErrWriter = &buf
+
+	err := command.Run(t.Context(), []string{"test", "--all-envs", "--concurrency", "2"})
+	require.EqualError(t, err, "migration test failed for 2 of 2 environment(s): staging, prod")
+
+	output := buf.String()
+	require.Contains(t, output, "=== staging ===\n❌ Migration test failed: migration test failed: failed to get container DSN")
+	require.Contains(t, output, "=== prod ===\n❌ Migration test failed: panic: inspect failed")
+	require.NotContains(t, output, "Migration test passed")
+
+	require.Equal(t, 2, maxAlive, "both environments' containers run at the same time")
+	require.ElementsMatch(t, []string{"housekeeper-test-staging", "housekeeper-test-prod"}, stopped,
+		"every container is torn down, including after a panic")
+	require.Zero(t, running)
+}
+
+func TestTestCommand_WithDockerIntegration(t *testing.T) {
+	testutil.SkipIfNoDocker(t)
+
+	if testing.Short() {
+		t.Skip("Skipping Docker integration test in short mode")
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		schema    string
+		expectErr string
+		contains  []string
+	}{
+		{
+			name:     "applies migrations",
+			contains: []string{"Applying 2 migrations...", "Migration test passed"},
+		},
+		{
+			name: "roundtrip matches schema",
+			args: []string{"--verify-roundtrip"},
+			schema: `CREATE DATABASE test ENGINE = Atomic;
+CREATE TABLE test.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;`,
+			contains: []string{"Roundtrip verified", "Migration test passed"},
+		},
+		{
+			name: "roundtrip detects drift",
+			args: []string{"--verify-roundtrip"},
+			schema: `CREATE DATABASE test ENGINE = Atomic;
+CREATE TABLE test.users (id UInt64, name String, email String) ENGINE = MergeTree() ORDER BY id;`,
+			expectErr: "migrations do not reproduce the project schema",
+			contains:  []string{"Migrated schema does not match the project schema", "ADD COLUMN `email` String"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.TestProject(t).WithMigrations(testutil.MinimalMigrations())
+			defer fixture.Cleanup()
+			if tt.schema != "" {
+				fixture.WithSchema(tt.schema)
+			}
+			t.Chdir(fixture.Dir)
+
+			writeTestSumFile(t, fixture.Config.Dir)
+
+			dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+			require.NoError(t, err)
+			defer dockerClient.Close()
+
+			var buf bytes.Buffer
+			command := testCmd(fixture.Config, dockerClient)
+			command.Writer = &buf
+			command.ErrWriter = &buf
+
+			err = command.Run(t.Context(), append([]string{"test"}, tt.args...))
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for _, expected := range tt.contains {
+				require.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
+
+// writeTestSumFile writes a sum file so the migration directory passes validation
+func writeTestSumFile(t *testing.T, dir string) {
+	t.Helper()
+
+	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(dir))
+	require.NoError(t, err)
+	require.NoError(t, migrationDir.Rehash())
+
+	sumFile, err := os.Create(filepath.Join(dir, "housekeeper.sum"))
+	require.NoError(t, err)
+	defer sumFile.Close()
+
+	_, err = migrationDir.SumFile.WriteTo(sumFile)
+	require.NoError(t, err)
+}
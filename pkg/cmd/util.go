@@ -17,7 +17,8 @@ import (
 )
 
 // runContainer starts a ClickHouse container with the given options, loads and executes
-// existing migrations, and returns the container and client for further use.
+// existing migrations, and returns the container and client for further use. The
+// container is torn down when any step after starting it fails or panics.
 func runContainer(ctx context.Context, w io.Writer, opts docker.DockerOptions, cfg *config.Config, dockerClient docker.DockerClient) (*docker.ClickHouseContainer, *clickhouse.Client, error) {
 	// 1. Load and validate migrations before creating container
 	migrationDir, err := migrator.LoadMigrationDir(os.DirFS(cfg.Dir))
@@ -35,7 +36,7 @@ func runContainer(ctx context.Context, w io.Writer, opts docker.DockerOptions, c
 			return nil, nil, errors.Wrap(err, "failed to validate migrations")
 		}
 		if !isValid {
-			return nil, nil, errors.New("migration directory failed validation - files have been modified")
+			return nil, nil, &migrator.ErrIntegrityMismatch{Reason: "migration directory failed validation - files have been modified"}
 		}
 	}
 
@@ -45,6 +46,20 @@ func runContainer(ctx context.Context, w io.Writer, opts docker.DockerOptions, c
 		return nil, nil, errors.Wrap(err, "failed to create ClickHouse container")
 	}
 
+	// Clean up unless the container is handed to the caller. Start may fail after the
+	// container is running, and the context may be canceled by then.
+	var client *clickhouse.Client
+	handedOff := false
+	defer func() {
+		if handedOff {
+			return
+		}
+		if client != nil {
+			_ = client.Close()
+		}
+		_ = container.Stop(context.WithoutCancel(ctx))
+	}()
+
 	if err := container.Start(ctx); err != nil {
 		return nil, nil, errors.Wrap(err, "failed to start ClickHouse container")
 	}
@@ -52,17 +67,15 @@ func runContainer(ctx context.Context, w io.Writer, opts docker.DockerOptions, c
 	// 3. Get DSN and connect to ClickHouse
 	dsn, err := container.GetDSN(ctx)
 	if err != nil {
-		_ = container.Stop(ctx) // Clean up container on error
 		return nil, nil, errors.Wrap(err, "failed to get container DSN")
 	}
 
 	// Create client with cluster and ignore databases configuration
-	client, err := clickhouse.NewClientWithOptions(ctx, dsn, clickhouse.ClientOptions{
+	client, err = clickhouse.NewClientWithOptions(ctx, dsn, clickhouse.ClientOptions{
 		Cluster:         cfg.ClickHouse.Cluster,
 		IgnoreDatabases: cfg.ClickHouse.IgnoreDatabases,
 	})
 	if err != nil {
-		_ = container.Stop(ctx) // Clean up container on error
 		return nil, nil, errors.Wrap(err, "failed to create ClickHouse client")
 	}
 
@@ -82,14 +95,10 @@ func runContainer(ctx context.Context, w io.Writer, opts docker.DockerOptions, c
 				// Format and execute the statement
 				buf := new(bytes.Buffer)
 				if err := fmtr.Format(buf, stmt); err != nil {
-					_ = container.Stop(ctx)
-					_ = client.Close()
 					return nil, nil, errors.Wrap(err, "failed to format SQL statement")
 				}
 
 				if err := client.ExecuteMigration(ctx, buf.String()); err != nil {
-					_ = container.Stop(ctx)
-					_ = client.Close()
 					return nil, nil, errors.Wrapf(err, "failed to execute statement: %s", buf.String())
 				}
 			}
@@ -99,6 +108,7 @@ func runContainer(ctx context.Context, w io.Writer, opts docker.DockerOptions, c
 		fmt.Fprintln(w, "No migrations found to apply")
 	}
 
+	handedOff = true
 	return container, client, nil
 }
 
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/config"
+	"github.com/pseudomuto/housekeeper/pkg/docker"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/urfave/cli/v3"
+)
+
+const verifyDownContainerName = "housekeeper-verify-down"
+
+// verifyDown creates a CLI command that checks the generated down migration for the
+// pending schema changes actually reverses the up migration.
+//
+// The existing migrations are applied to an ephemeral ClickHouse container, and the
+// changes between the resulting schema and the compiled project schema are generated
+// in both directions. The up and down statements are then applied to the container in
+// turn, and the command fails unless the schema ends up exactly where it started. The
+// container is always torn down afterward, making the command suitable as a CI gate.
+//
+// Example usage:
+//
+//	housekeeper verify-down
+func verifyDown(cfg *config.Config, client docker.DockerClient) *cli.Command {
+	return &cli.Command{
+		Name:   "verify-down",
+		Usage:  "Verify the down migration for pending schema changes reverses the up migration",
+		Before: requireConfig(cfg),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			devCfg := loadDevConfigFromConfig(cfg)
+
+			container, client, err := runContainer(ctx, cmd.Writer, docker.DockerOptions{
+				Version:   devCfg.version,
+				ConfigDir: devCfg.configDir,
+				Name:      verifyDownContainerName,
+			}, cfg, client)
+			if err != nil {
+				return errors.Wrap(err, "down migration verification failed")
+			}
+
+			defer func() {
+				_ = client.Close()
+				if stopErr := container.Stop(ctx); stopErr != nil {
+					fmt.Fprintf(cmd.ErrWriter, "Warning: failed to stop container: %v\n", stopErr)
+				}
+			}()
+
+			return verifyPendingDown(ctx, cmd.Writer, client, cfg)
+		},
+	}
+}
+
+// verifyPendingDown generates the migration from the schema of client to the compiled
+// project schema and checks its down statements restore the original schema.
+func verifyPendingDown(ctx context.Context, w io.Writer, client *clickhouse.Client, cfg *config.Config) error {
+	current, err := client.GetSchema(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to dump migrated schema")
+	}
+
+	targetStatements, err := compileProjectSchema(cfg)
+	if err != nil {
+		return err
+	}
+
+	diff, err := schemapkg.GenerateReversibleDiff(current, &parser.SQL{Statements: targetStatements}, schemapkg.CompareOptions{})
+	if err != nil {
+		if errors.Is(err, schemapkg.ErrNoDiff) {
+			fmt.Fprintln(w, "No pending schema changes to verify")
+			return nil
+		}
+		return errors.Wrap(err, "failed to generate schema diff")
+	}
+
+	exec := executor.New(executor.Config{
+		ClickHouse: client,
+		Formatter:  cfg.GetFormatter(),
+	})
+
+	reversible, err := exec.VerifyReversible(ctx, client, diff)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify down migration")
+	}
+
+	if reversible {
+		fmt.Fprintln(w, "Down migration verified: the schema was restored after applying up and down")
+		return nil
+	}
+
+	fmt.Fprintln(w, "Down migration does not restore the original schema.")
+	fmt.Fprintln(w, "\nUp:")
+	if err := cfg.GetFormatter().FormatSQL(w, diff.Up); err != nil {
+		return errors.Wrap(err, "failed to format up migration")
+	}
+	fmt.Fprintln(w, "\n\nDown:")
+	if err := cfg.GetFormatter().FormatSQL(w, diff.Down); err != nil {
+		return errors.Wrap(err, "failed to format down migration")
+	}
+	fmt.Fprintln(w)
+
+	return errors.New("down migration does not reverse the up migration")
+}
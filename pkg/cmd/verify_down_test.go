@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	schemapkg "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyDownCommand_Structure(t *testing.T) {
+	fixture := testutil.TestProject(t)
+	defer fixture.Cleanup()
+
+	command := verifyDown(fixture.Config, testutil.NewMockDockerClient())
+
+	require.Equal(t, "verify-down", command.Name)
+	require.NotNil(t, command.Before)
+	require.NotNil(t, command.Action)
+}
+
+func TestVerifyDownCommand_WithDockerIntegration(t *testing.T) {
+	testutil.SkipIfNoDocker(t)
+
+	if testing.Short() {
+		t.Skip("Skipping Docker integration test in short mode")
+	}
+
+	tests := []struct {
+		name     string
+		schema   string
+		contains []string
+	}{
+		{
+			name: "no pending changes",
+			schema: `CREATE DATABASE test ENGINE = Atomic;
+CREATE TABLE test.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;`,
+			contains: []string{"No pending schema changes to verify"},
+		},
+		{
+			name: "reversible changes",
+			schema: `CREATE DATABASE test ENGINE = Atomic;
+CREATE TABLE test.users (id UInt64, name String, email String) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE test.sessions (id UInt64) ENGINE = MergeTree() ORDER BY id;`,
+			contains: []string{"Down migration verified"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := testutil.TestProject(t).WithMigrations(testutil.MinimalMigrations())
+			defer fixture.Cleanup()
+			fixture.WithSchema(tt.schema)
+			t.Chdir(fixture.Dir)
+
+			writeTestSumFile(t, fixture.Config.Dir)
+
+			dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+			require.NoError(t, err)
+			defer dockerClient.Close()
+
+			var buf bytes.Buffer
+			command := verifyDown(fixture.Config, dockerClient)
+			command.Writer = &buf
+			command.ErrWriter = &buf
+
+			require.NoError(t, command.Run(t.Context(), []string{"verify-down"}))
+			for _, expected := range tt.contains {
+				require.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
+
+func TestVerifyReversible_DetectsBrokenDown(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping Docker integration test in short mode")
+	}
+
+	_, dsn := testutil.StartClickHouseContainer(t, filepath.Join(t.TempDir(), "config.d"))
+
+	ch, err := clickhouse.NewClient(t.Context(), dsn)
+	require.NoError(t, err)
+	defer ch.Close()
+
+	require.NoError(t, ch.ExecuteMigration(t.Context(), "CREATE DATABASE test ENGINE = Atomic"))
+	require.NoError(t, ch.ExecuteMigration(t.Context(), "CREATE TABLE test.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY id"))
+
+	parse := func(sql string) *parser.SQL {
+		parsed, err := parser.ParseString(sql)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	// The down migration changes the new column instead of dropping it
+	diff := &schemapkg.Diff{
+		Up:   parse("ALTER TABLE test.users ADD COLUMN email String;"),
+		Down: parse("ALTER TABLE test.users MODIFY COLUMN email Nullable(String);"),
+	}
+
+	exec := executor.New(executor.Config{ClickHouse: ch, Formatter: format.New(format.Defaults)})
+	reversible, err := exec.VerifyReversible(t.Context(), ch, diff)
+	require.NoError(t, err)
+	require.False(t, reversible)
+}
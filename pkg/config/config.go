@@ -31,6 +31,11 @@ type (
 		// IgnoreDatabases specifies a list of database names to exclude from schema operations
 		// These databases will be ignored during dump and diff operations
 		IgnoreDatabases []string `yaml:"ignore_databases,omitempty"`
+
+		// DefaultCodec specifies the compression codec (e.g. "ZSTD(1)") the server uses for
+		// columns declared without a CODEC, as set in the compression section of its config.
+		// Defaults to LZ4, ClickHouse's built-in default (see schema.CompareOptions.DefaultCodec)
+		DefaultCodec string `yaml:"default_codec,omitempty"`
 	}
 
 	// FormatterOptionsConfig represents format configuration settings that can be specified in YAML.
@@ -71,6 +76,25 @@ type (
 
 		// PairSize specifies how many arguments constitute a pair for paired formatting
 		PairSize *int `yaml:"pair_size,omitempty"`
+
+		// Canonical sorts object definitions and settings into a deterministic canonical form
+		Canonical *bool `yaml:"canonical,omitempty"`
+	}
+
+	// Environment represents a named ClickHouse instance the project is deployed to.
+	//
+	// Environments allow commands such as check to operate on every instance of a
+	// project (e.g. dev, staging, and prod) in a single pass.
+	Environment struct {
+		// Name identifies the environment (e.g. "staging")
+		Name string `yaml:"name"`
+
+		// URL is the ClickHouse connection DSN. Environment variable references such as
+		// ${PROD_PASSWORD} are expanded so credentials can be kept out of the file.
+		URL string `yaml:"url"`
+
+		// Cluster overrides the project's default cluster for this environment
+		Cluster string `yaml:"cluster,omitempty"`
 	}
 
 	// Config represents the project configuration for ClickHouse schema management.
@@ -86,6 +110,13 @@ type (
 
 		// Dir specifies the directory where migration files are stored
 		Dir string `yaml:"dir"`
+
+		// Environments lists the named ClickHouse instances the project is deployed to
+		Environments []Environment `yaml:"environments,omitempty"`
+
+		// MigrationHeader is a text/template rendered as a comment block at the top of
+		// every migration generated by the diff command (see schema.MigrationHeader)
+		MigrationHeader string `yaml:"migration_header,omitempty"`
 	}
 )
 
@@ -162,6 +193,52 @@ func LoadConfigFile(path string) (*Config, error) {
 	return LoadConfig(f)
 }
 
+// DSN returns the environment's connection DSN with environment variables expanded.
+func (e Environment) DSN() string {
+	return os.ExpandEnv(e.URL)
+}
+
+// GetEnvironments returns the configured environments with the given names, in the
+// order they are configured. When no names are given, every environment is returned.
+//
+// Example:
+//
+//	envs, err := cfg.GetEnvironments([]string{"staging", "prod"})
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, env := range envs {
+//		fmt.Printf("%s: %s\n", env.Name, env.DSN())
+//	}
+func (c *Config) GetEnvironments(names []string) ([]Environment, error) {
+	if len(names) == 0 {
+		return c.Environments, nil
+	}
+
+	known := make(map[string]bool, len(c.Environments))
+	for _, env := range c.Environments {
+		known[env.Name] = true
+	}
+
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !known[name] {
+			return nil, errors.Errorf("unknown environment: %s", name)
+		}
+		selected[name] = true
+	}
+
+	envs := make([]Environment, 0, len(selected))
+	for _, env := range c.Environments {
+		if selected[env.Name] {
+			envs = append(envs, env)
+		}
+	}
+
+	return envs, nil
+}
+
 // GetFormatterOptions returns the merged formatter options, combining defaults with user configuration.
 //
 // This method starts with the default formatter options and applies any non-nil values
@@ -211,6 +288,9 @@ func (c *Config) GetFormatterOptions() format.FormatterOptions {
 	if f.PairSize != nil {
 		result.PairSize = *f.PairSize
 	}
+	if f.Canonical != nil {
+		result.Canonical = *f.Canonical
+	}
 
 	return result
 }
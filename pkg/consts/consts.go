@@ -21,4 +21,8 @@ const (
 	// TableFunctionPrefix is the prefix used to mark AsSourceTable entries that represent table functions
 	// rather than actual table references. Used in CREATE TABLE AS function_name(...) syntax.
 	TableFunctionPrefix = "FUNCTION:"
+
+	// HiddenSecretValue is the placeholder ClickHouse reports in place of secret values (e.g. named
+	// collection parameters) when secrets aren't displayed. Extracted schemas use it to mask secrets.
+	HiddenSecretValue = "[HIDDEN]"
 )
@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+)
+
+type (
+	// Locker acquires the advisory lock that keeps concurrent processes from applying
+	// migrations to the same server at the same time.
+	//
+	// Lock returns a *migrator.ErrMigrationLocked when another process holds the lock.
+	// Otherwise it returns a function that releases the lock once migrations are done.
+	Locker interface {
+		Lock(ctx context.Context) (unlock func(context.Context) error, err error)
+	}
+
+	// SchemaReader reads the current schema of a ClickHouse server, e.g. *clickhouse.Client.
+	SchemaReader interface {
+		GetSchema(ctx context.Context) (*parser.SQL, error)
+	}
+
+	// ApplyOptions controls the workflow run by Apply.
+	ApplyOptions struct {
+		// Locker guards the run against concurrent applies. No lock is taken when nil.
+		Locker Locker
+
+		// Target is the project schema the server is expected to match once every
+		// migration is applied. Drift isn't checked when nil, or in dry run mode since
+		// the pending migrations haven't been applied.
+		Target *parser.SQL
+
+		// Schema reads the server's schema for the drift check. Required with Target.
+		Schema SchemaReader
+	}
+
+	// ApplyReport describes the outcome of Apply.
+	ApplyReport struct {
+		// Results contains the result of each migration that was considered, in order.
+		// Execution stops at the first failed migration, so it's always the last result.
+		Results []*ExecutionResult
+
+		// Lock is the status of the advisory lock for the run
+		Lock LockStatus
+
+		// Drift holds the statements required to bring the server in line with
+		// ApplyOptions.Target after applying the migrations. It's nil when the server
+		// matches the target, or when drift wasn't checked.
+		Drift *parser.SQL
+	}
+
+	// LockStatus describes the advisory lock taken for an Apply run.
+	LockStatus string
+)
+
+const (
+	// LockNotUsed indicates no Locker was configured
+	LockNotUsed LockStatus = "not used"
+
+	// LockAcquired indicates the lock was acquired (and released) for the run
+	LockAcquired LockStatus = "acquired"
+
+	// LockHeld indicates another process held the lock, so no migrations were applied
+	LockHeld LockStatus = "held"
+)
+
+// Apply runs the complete migration workflow for a migration directory, matching what
+// the migrate command does:
+//
+//  1. Validates the migration files against the sum file
+//  2. Acquires the advisory lock (when opts.Locker is set)
+//  3. Bootstraps the revisions table and detects pending migrations
+//  4. Executes pending migrations in order, recording a revision for each
+//  5. Checks the server for drift from opts.Target (when set)
+//
+// Execution stops at the first failed migration. The returned report is never nil and
+// contains everything that happened up to the failure. The error is a
+// *migrator.ErrIntegrityMismatch when validation fails, a *migrator.ErrMigrationLocked
+// when another process holds the lock, and wraps the failed migration's error when a
+// migration fails.
+//
+// Example usage:
+//
+//	report, err := exec.Apply(ctx, migrationDir, executor.ApplyOptions{
+//		Target: projectSchema,
+//		Schema: client,
+//	})
+//	for _, result := range report.Results {
+//		fmt.Printf("%s: %s\n", result.Version, result.Status)
+//	}
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if report.Drift != nil {
+//		fmt.Println("server doesn't match the project schema")
+//	}
+func (e *Executor) Apply(ctx context.Context, migrationDir *migrator.MigrationDir, opts ApplyOptions) (report *ApplyReport, err error) {
+	report = &ApplyReport{Lock: LockNotUsed}
+
+	valid, err := migrationDir.Validate()
+	if err != nil {
+		return report, errors.Wrap(err, "failed to validate migrations")
+	}
+	if !valid {
+		return report, &migrator.ErrIntegrityMismatch{
+			Reason: "migration files don't match the sum file (run housekeeper rehash if the changes are intended)",
+		}
+	}
+
+	if opts.Locker != nil {
+		unlock, lockErr := opts.Locker.Lock(ctx)
+		if lockErr != nil {
+			var locked *migrator.ErrMigrationLocked
+			if errors.As(lockErr, &locked) {
+				report.Lock = LockHeld
+				return report, lockErr
+			}
+			return report, errors.Wrap(lockErr, "failed to acquire migration lock")
+		}
+
+		report.Lock = LockAcquired
+		defer func() {
+			if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+				err = errors.Wrap(unlockErr, "failed to release migration lock")
+			}
+		}()
+	}
+
+	return e.applyLocked(ctx, migrationDir, opts, report)
+}
+
+// applyLocked executes the pending migrations and checks for drift once the lock is held
+func (e *Executor) applyLocked(ctx context.Context, migrationDir *migrator.MigrationDir, opts ApplyOptions, report *ApplyReport) (*ApplyReport, error) {
+	results, err := e.Execute(ctx, migrationDir.Migrations)
+	report.Results = results
+	if err != nil {
+		return report, err
+	}
+
+	if failed := report.Failed(); failed != nil {
+		return report, errors.Wrapf(failed.Error, "migration %s failed after %d of %d statements",
+			failed.Version, failed.StatementsApplied, failed.TotalStatements)
+	}
+
+	if opts.Target == nil || e.dryRun {
+		return report, nil
+	}
+
+	if opts.Schema == nil {
+		return report, errors.New("a schema reader is required to check for drift")
+	}
+
+	current, err := opts.Schema.GetSchema(ctx)
+	if err != nil {
+		return report, errors.Wrap(err, "failed to read schema for drift check")
+	}
+
+	drift, err := schema.GenerateDiff(current, opts.Target)
+	if err != nil && !errors.Is(err, schema.ErrNoDiff) {
+		return report, errors.Wrap(err, "failed to check for drift")
+	}
+	report.Drift = drift
+
+	return report, nil
+}
+
+// Failed returns the result of the migration that stopped execution, or nil if every
+// migration was applied or skipped.
+func (r *ApplyReport) Failed() *ExecutionResult {
+	for _, result := range r.Results {
+		if result.Status == StatusFailed {
+			return result
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package executor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLocker struct {
+	err      error
+	locked   bool
+	unlocked bool
+}
+
+func (m *mockLocker) Lock(ctx context.Context) (func(context.Context) error, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	m.locked = true
+	return func(context.Context) error {
+		m.unlocked = true
+		return nil
+	}, nil
+}
+
+// mockSchemaReader returns each of its schemas in turn, repeating the last one
+type mockSchemaReader struct {
+	schemas []string
+	calls   int
+}
+
+func (m *mockSchemaReader) GetSchema(ctx context.Context) (*parser.SQL, error) {
+	sql := m.schemas[min(m.calls, len(m.schemas)-1)]
+	m.calls++
+	return parser.ParseString(sql)
+}
+
+func TestExecutor_Apply(t *testing.T) {
+	loadDir := func(t *testing.T) (fstest.MapFS, *migrator.MigrationDir) {
+		t.Helper()
+
+		fsys := fstest.MapFS{
+			"20240101120000_init.sql": &fstest.MapFile{
+				Data: []byte("CREATE DATABASE analytics ENGINE = Atomic;"),
+			},
+			"20240101130000_events.sql": &fstest.MapFile{
+				Data: []byte("CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;"),
+			},
+		}
+
+		migrationDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+		return fsys, migrationDir
+	}
+
+	newExecutor := func(mockCH *mockClickHouse) *executor.Executor {
+		// Bootstrap checks report existing infrastructure and there are no revisions
+		queryCallCount := 0
+		mockCH.queryFunc = func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			queryCallCount++
+			if queryCallCount <= 2 {
+				return &mockRows{}, nil
+			}
+			return &mockRows{nextCalled: true}, nil
+		}
+
+		return executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "1.0.0",
+		})
+	}
+
+	t.Run("applies pending migrations", func(t *testing.T) {
+		_, migrationDir := loadDir(t)
+		locker := &mockLocker{}
+
+		report, err := newExecutor(&mockClickHouse{}).Apply(context.Background(), migrationDir, executor.ApplyOptions{
+			Locker: locker,
+			Target: mustParse(t, `
+				CREATE DATABASE analytics ENGINE = Atomic;
+				CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			`),
+			Schema: &mockSchemaReader{schemas: []string{`
+				CREATE DATABASE analytics ENGINE = Atomic;
+				CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			`}},
+		})
+		require.NoError(t, err)
+		require.Len(t, report.Results, 2)
+		require.Equal(t, executor.StatusSuccess, report.Results[0].Status)
+		require.Equal(t, executor.StatusSuccess, report.Results[1].Status)
+		require.Nil(t, report.Failed())
+		require.Nil(t, report.Drift)
+
+		require.Equal(t, executor.LockAcquired, report.Lock)
+		require.True(t, locker.locked)
+		require.True(t, locker.unlocked)
+	})
+
+	t.Run("reports drift", func(t *testing.T) {
+		_, migrationDir := loadDir(t)
+
+		report, err := newExecutor(&mockClickHouse{}).Apply(context.Background(), migrationDir, executor.ApplyOptions{
+			Target: mustParse(t, `
+				CREATE DATABASE analytics ENGINE = Atomic;
+				CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+			`),
+			Schema: &mockSchemaReader{schemas: []string{`
+				CREATE DATABASE analytics ENGINE = Atomic;
+				CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			`}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, executor.LockNotUsed, report.Lock)
+		require.NotNil(t, report.Drift)
+		require.Len(t, report.Drift.Statements, 1)
+		require.NotNil(t, report.Drift.Statements[0].AlterTable)
+	})
+
+	t.Run("skips drift check in dry run mode", func(t *testing.T) {
+		_, migrationDir := loadDir(t)
+
+		// Configure the mock like newExecutor does, but with dry run enabled
+		mockCH := &mockClickHouse{}
+		newExecutor(mockCH)
+		exec := executor.New(executor.Config{
+			ClickHouse: mockCH,
+			Formatter:  format.New(format.Defaults),
+			DryRun:     true,
+		})
+
+		schemaReader := &mockSchemaReader{schemas: []string{""}}
+		report, err := exec.Apply(context.Background(), migrationDir, executor.ApplyOptions{
+			Target: mustParse(t, "CREATE DATABASE analytics ENGINE = Atomic;"),
+			Schema: schemaReader,
+		})
+		require.NoError(t, err)
+		require.Len(t, report.Results, 2)
+		require.Equal(t, executor.StatusDryRun, report.Results[0].Status)
+		require.Equal(t, executor.StatusDryRun, report.Results[1].Status)
+		require.Nil(t, report.Drift)
+		require.Zero(t, schemaReader.calls)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("rejects modified migrations", func(t *testing.T) {
+		fsys, migrationDir := loadDir(t)
+		fsys["20240101130000_events.sql"].Data = []byte("DROP TABLE analytics.events;")
+
+		mockCH := &mockClickHouse{}
+		report, err := newExecutor(mockCH).Apply(context.Background(), migrationDir, executor.ApplyOptions{})
+
+		var mismatch *migrator.ErrIntegrityMismatch
+		require.ErrorAs(t, err, &mismatch)
+		require.Contains(t, mismatch.Reason, "housekeeper rehash")
+		require.NotNil(t, report)
+		require.Empty(t, report.Results)
+		require.Empty(t, mockCH.queries)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("stops on first failure", func(t *testing.T) {
+		_, migrationDir := loadDir(t)
+		locker := &mockLocker{}
+
+		mockCH := &mockClickHouse{
+			execFunc: func(ctx context.Context, query string, args ...any) error {
+				if strings.Contains(query, "analytics") && strings.Contains(query, "CREATE DATABASE") {
+					return errors.New("access denied")
+				}
+				return nil
+			},
+		}
+
+		report, err := newExecutor(mockCH).Apply(context.Background(), migrationDir, executor.ApplyOptions{Locker: locker})
+		require.EqualError(t, err, "migration 20240101120000_init failed after 0 of 1 statements: "+report.Results[0].Error.Error())
+		require.Len(t, report.Results, 1)
+		require.Equal(t, report.Results[0], report.Failed())
+		require.Contains(t, report.Results[0].Error.Error(), "access denied")
+		require.True(t, locker.unlocked)
+
+		for _, query := range mockCH.execs {
+			require.NotContains(t, query, "events")
+		}
+	})
+
+	t.Run("lock contention", func(t *testing.T) {
+		_, migrationDir := loadDir(t)
+		locker := &mockLocker{err: &migrator.ErrMigrationLocked{Holder: "deploy-1"}}
+
+		mockCH := &mockClickHouse{}
+		report, err := newExecutor(mockCH).Apply(context.Background(), migrationDir, executor.ApplyOptions{Locker: locker})
+
+		var locked *migrator.ErrMigrationLocked
+		require.ErrorAs(t, err, &locked)
+		require.Equal(t, "deploy-1", locked.Holder)
+		require.Equal(t, executor.LockHeld, report.Lock)
+		require.Empty(t, report.Results)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("lock failure", func(t *testing.T) {
+		_, migrationDir := loadDir(t)
+		locker := &mockLocker{err: errors.New("connection reset")}
+
+		report, err := newExecutor(&mockClickHouse{}).Apply(context.Background(), migrationDir, executor.ApplyOptions{Locker: locker})
+		require.EqualError(t, err, "failed to acquire migration lock: connection reset")
+		require.Equal(t, executor.LockNotUsed, report.Lock)
+	})
+}
+
+func mustParse(t *testing.T, sql string) *parser.SQL {
+	t.Helper()
+
+	parsed, err := parser.ParseString(sql)
+	require.NoError(t, err)
+	return parsed
+}
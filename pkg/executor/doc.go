@@ -14,6 +14,7 @@
 //   - Config: Configuration options for executor creation
 //   - ExecutionResult: Detailed results of migration execution
 //   - ExecutionStatus: Status enumeration for migration outcomes
+//   - ApplyReport: Outcome of the complete migration workflow run by Apply
 //
 // # Key Features
 //
@@ -57,13 +58,39 @@
 //		}
 //	}
 //
+// # Applying a Migration Directory
+//
+// Execute only runs migrations. Apply runs the same workflow as the migrate command
+// for programmatic callers like deployment tooling: it validates the migration files
+// against the sum file, optionally acquires an advisory lock, executes pending
+// migrations, and optionally checks the server for drift from the project schema.
+// TableLocker implements the lock with the housekeeper.migration_lock table, so two
+// processes migrating the same server (e.g. CI runners) never apply migrations twice.
+//
+//	report, err := exec.Apply(ctx, migrationDir, executor.ApplyOptions{
+//		Locker: exec.NewTableLocker(executor.DefaultLockTTL),
+//		Target: projectSchema,
+//		Schema: clickhouseClient,
+//	})
+//	if err != nil {
+//		if failed := report.Failed(); failed != nil {
+//			log.Printf("%s failed after %d/%d statements", failed.Version,
+//				failed.StatementsApplied, failed.TotalStatements)
+//		}
+//		log.Fatal(err)
+//	}
+//
+// Execution stops at the first failed migration, and the report always describes
+// everything that happened up to that point.
+//
 // # Bootstrap Handling
 //
 // The executor automatically handles the bootstrap process for new ClickHouse
 // instances that don't have the housekeeper migration tracking infrastructure:
 //
 //   - Detects if housekeeper database and revisions table exist
-//   - Creates missing infrastructure automatically before migration execution
+//   - Creates missing infrastructure automatically before migration execution,
+//     including the housekeeper.migration_lock table used by TableLocker
 //   - Uses IF NOT EXISTS clauses for safe, idempotent bootstrap operations
 //   - Handles the special case where revisions table doesn't exist on initial setup
 //
@@ -76,6 +103,33 @@
 //   - Comprehensive revision records for failed migrations
 //   - Safe execution termination on first failure to prevent cascade issues
 //
+// Config.StatementTimeout bounds how long each statement may run. A statement that
+// exceeds it is cancelled and fails its migration with an *ErrStatementTimeout, and
+// the revision records which statement timed out and how long it ran. Like any other
+// failure, the migration resumes from that statement on the next run.
+//
+// # Transactional Migrations
+//
+// Migrations containing a -- housekeeper:transactional comment are wrapped in
+// BEGIN TRANSACTION / COMMIT when the server has experimental transactions enabled.
+// A failure rolls back the transaction and the migration is recorded with no applied
+// statements, so it is retried from the start. ClickHouse transactions only cover
+// data changes on MergeTree-family tables, so this is mostly useful for data
+// migrations. When the server doesn't support transactions, these migrations are
+// executed statement-by-statement like any other migration.
+//
+// # Rolling Back Migrations
+//
+// Rollback reverts the most recently applied migrations by running the statements
+// in their -- housekeeper:down sections or <version>.down.sql files, newest first.
+// Each rollback is recorded as a RollbackRevision, which makes the migration pending
+// again. Migrations applied before the last snapshot can't be rolled back.
+//
+//	results, err := exec.Rollback(ctx, migrationDir.Migrations, 1)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
 // # Integration with Revision System
 //
 // The executor seamlessly integrates with the existing pkg/migrator revision
@@ -84,7 +138,7 @@
 //   - Loads existing revisions to determine pending migrations
 //   - Creates comprehensive revision records for all executions
 //   - Records timing, error information, and integrity hashes
-//   - Supports both StandardRevision and SnapshotRevision types
+//   - Supports StandardRevision, SnapshotRevision, and RollbackRevision types
 //   - Maintains compatibility with existing revision query methods
 //
 // # Testing and Development
@@ -93,6 +147,8 @@
 //
 //   - Interface-based ClickHouse client for easy mocking
 //   - Comprehensive test coverage with testcontainers integration
-//   - Support for dry-run execution modes
+//   - Support for dry-run execution modes (see Config.DryRun)
+//   - Applying migrations without revision tracking, e.g. to throwaway test
+//     containers (see Config.SkipRevisionTracking)
 //   - Integration with existing Housekeeper test infrastructure
 package executor
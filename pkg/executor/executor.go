@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,6 +24,15 @@ type (
 		Exec(context.Context, string, ...any) error
 	}
 
+	// SessionClickHouse is implemented by ClickHouse clients that can open a dedicated
+	// session, e.g. *clickhouse.Client. ClickHouse binds a transaction to the session that
+	// began it, so transactional migrations run every statement through one session rather
+	// than through a connection pool that may hand each statement a different connection.
+	SessionClickHouse interface {
+		ClickHouse
+		Session(context.Context) (driver.Conn, error)
+	}
+
 	// Executor handles the execution of database migrations against ClickHouse.
 	//
 	// The executor provides safe, atomic migration execution with comprehensive
@@ -57,6 +67,9 @@ type (
 		ch                 ClickHouse
 		formatter          *format.Formatter
 		housekeeperVersion string
+		dryRun             bool
+		skipRevisions      bool
+		statementTimeout   time.Duration
 	}
 
 	// Config contains configuration options for creating a new Executor.
@@ -69,6 +82,45 @@ type (
 
 		// HousekeeperVersion to record in revision entries
 		HousekeeperVersion string
+
+		// DryRun reports the statements each pending migration would run without
+		// executing them, bootstrapping the server, or recording revisions
+		DryRun bool
+
+		// SkipRevisionTracking applies migrations without bootstrapping or writing to
+		// housekeeper.revisions. This disables pending detection: every migration is
+		// treated as pending and re-applied on each run, so it's only suitable for
+		// throwaway servers (e.g. test containers) or idempotent replays.
+		SkipRevisionTracking bool
+
+		// StatementTimeout limits how long each migration statement may run, e.g. so that
+		// a heavy OPTIMIZE or ALTER can't hang a deployment. A statement that exceeds it is
+		// cancelled and fails its migration with an *ErrStatementTimeout. Zero means no limit.
+		StatementTimeout time.Duration
+	}
+
+	// ErrStatementTimeout is the error of a migration whose statement was cancelled for
+	// running longer than Config.StatementTimeout. It's recorded in the revision of the
+	// migration, so the failed statement and how long it ran are kept with the revision.
+	//
+	// Example:
+	//
+	//	var timeout *executor.ErrStatementTimeout
+	//	if errors.As(result.Error, &timeout) {
+	//		fmt.Printf("statement %d ran for %s\n", timeout.Statement, timeout.Elapsed)
+	//	}
+	ErrStatementTimeout struct {
+		// Statement is the 1-based index of the statement in the migration
+		Statement int
+
+		// SQL is the statement that timed out
+		SQL string
+
+		// Timeout is the configured statement timeout
+		Timeout time.Duration
+
+		// Elapsed is how long the statement ran before it was cancelled
+		Elapsed time.Duration
 	}
 
 	// ExecutionResult contains the result of executing a single migration.
@@ -97,6 +149,9 @@ type (
 
 		// Revision contains the revision record that was created for this execution
 		Revision *migrator.Revision
+
+		// Statements contains the SQL that would be executed, in order (dry run only)
+		Statements []string
 	}
 
 	// ExecutionStatus represents the outcome of a migration execution.
@@ -112,6 +167,10 @@ const (
 
 	// StatusSkipped indicates the migration was skipped (already applied)
 	StatusSkipped ExecutionStatus = "skipped"
+
+	// StatusDryRun indicates the migration is pending and would have been executed
+	// if dry run mode wasn't enabled
+	StatusDryRun ExecutionStatus = "dry-run"
 )
 
 // New creates a new migration executor with the provided configuration.
@@ -131,9 +190,22 @@ func New(config Config) *Executor {
 		ch:                 config.ClickHouse,
 		formatter:          config.Formatter,
 		housekeeperVersion: config.HousekeeperVersion,
+		dryRun:             config.DryRun,
+		skipRevisions:      config.SkipRevisionTracking,
+		statementTimeout:   config.StatementTimeout,
 	}
 }
 
+// Error implements the error interface.
+func (e *ErrStatementTimeout) Error() string {
+	return fmt.Sprintf("statement %d timed out after %s (timeout %s): %s", e.Statement, e.Elapsed.Round(time.Millisecond), e.Timeout, e.SQL)
+}
+
+// Unwrap returns context.DeadlineExceeded so the error matches the standard timeout error.
+func (e *ErrStatementTimeout) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
 // Execute applies a list of migrations to the ClickHouse database.
 //
 // This method handles the complete migration execution lifecycle:
@@ -147,6 +219,18 @@ func New(config Config) *Executor {
 // the entire migration is marked as failed and execution stops. Previously
 // executed migrations remain applied.
 //
+// Migrations marked with -- housekeeper:transactional are wrapped in a transaction
+// when the server supports it (see SupportsTransactions), so a failure rolls back
+// the statements already applied. Otherwise they run statement-by-statement.
+//
+// In dry run mode (see Config.DryRun) nothing is written to the server. Pending
+// migrations are reported with StatusDryRun and the statements they would run,
+// while partially applied migrations are validated and report only the statements
+// left to resume.
+//
+// When revision tracking is skipped (see Config.SkipRevisionTracking), the server
+// isn't bootstrapped and every migration is applied as if it were pending.
+//
 // Example usage:
 //
 //	migrations := []*migrator.Migration{migration1, migration2}
@@ -166,9 +250,47 @@ func New(config Config) *Executor {
 //		}
 //	}
 func (e *Executor) Execute(ctx context.Context, migrations []*migrator.Migration) ([]*ExecutionResult, error) {
+	if e.dryRun {
+		return e.planMigrations(ctx, migrations)
+	}
+
+	revisionSet, err := e.loadExecutedRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only check for transaction support when a migration opts into it. Servers that
+	// can't run transactions fall back to statement-by-statement execution.
+	transactional := false
+	if slices.ContainsFunc(migrations, func(m *migrator.Migration) bool { return m.IsTransactional }) {
+		transactional, _ = e.SupportsTransactions(ctx)
+	}
+
+	results := make([]*ExecutionResult, 0, len(migrations))
+
+	for _, migration := range migrations {
+		result := e.executeMigration(ctx, migration, revisionSet, transactional)
+		results = append(results, result)
+
+		// Stop execution on first failure
+		if result.Status == StatusFailed {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// loadExecutedRevisions bootstraps the server and loads its revisions. When revision
+// tracking is skipped, nothing is bootstrapped and an empty set is returned.
+func (e *Executor) loadExecutedRevisions(ctx context.Context) (*migrator.RevisionSet, error) {
+	if e.skipRevisions {
+		return migrator.NewRevisionSet(nil), nil
+	}
+
 	// Ensure housekeeper infrastructure exists
-	if err := e.ensureBootstrap(ctx); err != nil {
-		return nil, errors.Wrap(err, "failed to bootstrap housekeeper infrastructure")
+	if err := e.Bootstrap(ctx); err != nil {
+		return nil, err
 	}
 
 	// Load existing revisions to determine what needs to be executed
@@ -177,13 +299,33 @@ func (e *Executor) Execute(ctx context.Context, migrations []*migrator.Migration
 		return nil, errors.Wrap(err, "failed to load existing revisions")
 	}
 
-	results := make([]*ExecutionResult, 0, len(migrations))
+	return revisionSet, nil
+}
+
+// planMigrations reports what Execute would do for each migration without changing the
+// server. Servers that haven't been bootstrapped have no revisions, so every migration
+// is pending, as is the case when revision tracking is skipped.
+func (e *Executor) planMigrations(ctx context.Context, migrations []*migrator.Migration) ([]*ExecutionResult, error) {
+	revisionSet := migrator.NewRevisionSet(nil)
+	if !e.skipRevisions {
+		bootstrapped, err := e.IsBootstrapped(ctx)
+		if err != nil {
+			return nil, err
+		}
 
+		if bootstrapped {
+			if revisionSet, err = migrator.LoadRevisions(ctx, e.ch); err != nil {
+				return nil, errors.Wrap(err, "failed to load existing revisions")
+			}
+		}
+	}
+
+	results := make([]*ExecutionResult, 0, len(migrations))
 	for _, migration := range migrations {
-		result := e.executeMigration(ctx, migration, revisionSet)
+		result := e.planMigration(migration, revisionSet)
 		results = append(results, result)
 
-		// Stop execution on first failure
+		// Stop at the first migration that would fail, matching Execute
 		if result.Status == StatusFailed {
 			break
 		}
@@ -192,6 +334,153 @@ func (e *Executor) Execute(ctx context.Context, migrations []*migrator.Migration
 	return results, nil
 }
 
+// planMigration returns the dry run result for a single migration. Snapshots don't
+// execute their DDL, so a pending snapshot has no statements to report.
+func (e *Executor) planMigration(migration *migrator.Migration, revisionSet *migrator.RevisionSet) *ExecutionResult {
+	total := len(migration.Statements)
+	if revisionSet.IsCompleted(migration) {
+		return &ExecutionResult{
+			Version:           migration.Version,
+			Status:            StatusSkipped,
+			StatementsApplied: total,
+			TotalStatements:   total,
+		}
+	}
+
+	result := &ExecutionResult{
+		Version:         migration.Version,
+		Status:          StatusDryRun,
+		TotalStatements: total,
+	}
+	if migration.IsSnapshot {
+		return result
+	}
+
+	_, startIndex, err := e.getPartialRevision(migration, revisionSet)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = errors.Wrap(err, "failed to validate partial revision")
+		return result
+	}
+	result.StatementsApplied = startIndex
+
+	for i := startIndex; i < total; i++ {
+		stmt := migration.Statements[i]
+
+		// Comment-only statements are never sent to the server
+		if stmt.CommentStatement != nil {
+			continue
+		}
+
+		stmtSQL, err := e.formatStatement(stmt)
+		if err != nil {
+			result.Status = StatusFailed
+			result.Error = errors.Wrapf(err, "failed to format statement %d", i+1)
+			result.Statements = nil
+			return result
+		}
+		result.Statements = append(result.Statements, stmtSQL)
+	}
+
+	return result
+}
+
+// Bootstrap creates the housekeeper database and revisions table without applying
+// any migrations. It is safe to call on an already bootstrapped server, in which
+// case only missing columns are added to the revisions table.
+//
+// Example usage:
+//
+//	if err := executor.Bootstrap(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *Executor) Bootstrap(ctx context.Context) error {
+	if err := e.ensureBootstrap(ctx); err != nil {
+		return errors.Wrap(err, "failed to bootstrap housekeeper infrastructure")
+	}
+	return nil
+}
+
+// MarkApplied records the given migrations as applied without executing them.
+//
+// This is used when adopting housekeeper on a database whose schema already exists:
+// the migrations describing that schema are baselined so later runs of Execute skip
+// them. Migrations that are already completed are reported as skipped. Each marked
+// migration gets a revision with all statements applied and no execution time.
+//
+// Example usage:
+//
+//	baseline, err := migrationDir.MigrationsThrough("20240101120000")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	results, err := executor.MarkApplied(ctx, baseline)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (e *Executor) MarkApplied(ctx context.Context, migrations []*migrator.Migration) ([]*ExecutionResult, error) {
+	if e.skipRevisions {
+		return nil, errors.New("cannot mark migrations as applied when revision tracking is skipped")
+	}
+
+	if err := e.Bootstrap(ctx); err != nil {
+		return nil, err
+	}
+
+	revisionSet, err := migrator.LoadRevisions(ctx, e.ch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load existing revisions")
+	}
+
+	results := make([]*ExecutionResult, 0, len(migrations))
+	for _, migration := range migrations {
+		total := len(migration.Statements)
+		if revisionSet.IsCompleted(migration) {
+			results = append(results, &ExecutionResult{
+				Version:           migration.Version,
+				Status:            StatusSkipped,
+				StatementsApplied: total,
+				TotalStatements:   total,
+			})
+			continue
+		}
+
+		migrationHash, partialHashes := e.ComputeHashes(migration)
+		revision := &migrator.Revision{
+			Version:            migration.Version,
+			ExecutedAt:         time.Now(),
+			Kind:               migrator.StandardRevision,
+			Applied:            total,
+			Total:              total,
+			Hash:               migrationHash,
+			PartialHashes:      partialHashes,
+			HousekeeperVersion: e.housekeeperVersion,
+		}
+
+		// Snapshots are tracked as a single unit, matching executeSnapshotMigration
+		if migration.IsSnapshot {
+			revision.Kind = migrator.SnapshotRevision
+			revision.Applied = 1
+			revision.Total = 1
+		}
+
+		if err := e.saveRevision(ctx, revision); err != nil {
+			return results, errors.Wrapf(err, "failed to mark migration %s as applied", migration.Version)
+		}
+
+		results = append(results, &ExecutionResult{
+			Version:           migration.Version,
+			Status:            StatusSuccess,
+			StatementsApplied: total,
+			TotalStatements:   total,
+			Revision:          revision,
+		})
+	}
+
+	return results, nil
+}
+
 // IsBootstrapped checks whether the housekeeper database and revisions table exist.
 //
 // This method verifies that the migration tracking infrastructure is properly
@@ -230,6 +519,10 @@ func (e *Executor) IsBootstrapped(ctx context.Context) (bool, error) {
 	return rows.Next(), nil
 }
 
+// revisionsUpgradeSQL adds columns introduced after the initial revisions table layout
+const revisionsUpgradeSQL = `ALTER TABLE housekeeper.revisions
+    ADD COLUMN IF NOT EXISTS statement_times_ms Array(UInt64) COMMENT 'How long each applied statement took to run' AFTER partial_hashes`
+
 // ensureBootstrap creates the housekeeper database and revisions table if they don't exist.
 func (e *Executor) ensureBootstrap(ctx context.Context) error {
 	bootstrapped, err := e.IsBootstrapped(ctx)
@@ -238,6 +531,10 @@ func (e *Executor) ensureBootstrap(ctx context.Context) error {
 	}
 
 	if bootstrapped {
+		// Upgrade revisions tables created before per-statement timing was recorded
+		if err := e.ch.Exec(ctx, revisionsUpgradeSQL); err != nil {
+			return errors.Wrap(err, "failed to upgrade revisions table")
+		}
 		return nil
 	}
 
@@ -258,13 +555,14 @@ CREATE TABLE IF NOT EXISTS housekeeper.revisions (
     total UInt32 COMMENT 'The total number of statements in the migration',
     hash String COMMENT 'The h1 hash of the migration',
     partial_hashes Array(String) COMMENT 'h1 hashes for each statement in the migration',
+    statement_times_ms Array(UInt64) COMMENT 'How long each applied statement took to run',
     housekeeper_version String COMMENT 'The version of housekeeper used to run the migration'
 )
 ENGINE = MergeTree()
 ORDER BY version
 PARTITION BY toYYYYMM(executed_at)
 COMMENT 'Table used to track migrations';
-`
+` + migrationLockTableSQL + ";\n"
 
 	sql, err := parser.ParseString(bootstrapSQL)
 	if err != nil {
@@ -292,7 +590,7 @@ COMMENT 'Table used to track migrations';
 }
 
 // executeMigration executes a single migration and returns the result.
-func (e *Executor) executeMigration(ctx context.Context, migration *migrator.Migration, revisionSet *migrator.RevisionSet) *ExecutionResult {
+func (e *Executor) executeMigration(ctx context.Context, migration *migrator.Migration, revisionSet *migrator.RevisionSet, transactional bool) *ExecutionResult {
 	startTime := time.Now()
 
 	// Check if migration is already completed
@@ -312,7 +610,7 @@ func (e *Executor) executeMigration(ctx context.Context, migration *migrator.Mig
 	}
 
 	// Check for partial execution and determine starting point
-	_, startIndex, err := e.getPartialRevision(migration, revisionSet)
+	partialRevision, startIndex, err := e.getPartialRevision(migration, revisionSet)
 	if err != nil {
 		return &ExecutionResult{
 			Version:           migration.Version,
@@ -324,33 +622,18 @@ func (e *Executor) executeMigration(ctx context.Context, migration *migrator.Mig
 		}
 	}
 
-	// Execute migration statements starting from the determined index
-	statementsApplied := startIndex
-	var executionError error
-
-	for i := startIndex; i < len(migration.Statements); i++ {
-		stmt := migration.Statements[i]
-
-		// Skip comment-only statements as they cannot be executed
-		if stmt.CommentStatement != nil {
-			statementsApplied++
-			continue
-		}
-
-		stmtSQL, err := e.formatStatement(stmt)
-		if err != nil {
-			executionError = errors.Wrapf(err, "failed to format statement %d", i+1)
-			break
-		}
-
-		if err := e.ch.Exec(ctx, stmtSQL); err != nil {
-			executionError = errors.Wrapf(err, "failed to execute statement %d: %s", i+1, stmtSQL)
-			break
-		}
-
-		statementsApplied++
+	// Execute migration statements starting from the determined index. No-op migrations
+	// (empty or comment-only) send nothing to the server, so they're never wrapped in a
+	// transaction, and are recorded as applied like any other migration.
+	apply := e.applyStatements
+	if transactional && migration.IsTransactional && !migration.IsNoOp() {
+		apply = e.applyStatementsInTransaction
 	}
 
+	statementsApplied, statementTimes, executionError := apply(
+		ctx, migration, startIndex, resumedStatementTimes(partialRevision, startIndex),
+	)
+
 	executionTime := time.Since(startTime)
 
 	// Determine execution status
@@ -372,6 +655,7 @@ func (e *Executor) executeMigration(ctx context.Context, migration *migrator.Mig
 		Total:              len(migration.Statements),
 		Hash:               migrationHash,
 		PartialHashes:      partialHashes,
+		StatementTimes:     statementTimes,
 		HousekeeperVersion: e.housekeeperVersion,
 	}
 
@@ -398,6 +682,152 @@ func (e *Executor) executeMigration(ctx context.Context, migration *migrator.Mig
 	}
 }
 
+// applyStatements executes the migration statements one at a time starting at startIndex.
+// It returns the number of statements applied (including those applied before startIndex),
+// the time taken by each applied statement, and the error that stopped execution, if any.
+// Each statement runs under the statement timeout (see execStatement).
+func (e *Executor) applyStatements(ctx context.Context, migration *migrator.Migration, startIndex int, statementTimes []time.Duration) (int, []time.Duration, error) {
+	return e.applyStatementsOn(ctx, e.ch, migration, startIndex, statementTimes)
+}
+
+// applyStatementsOn is applyStatements executing the statements through ch
+func (e *Executor) applyStatementsOn(ctx context.Context, ch ClickHouse, migration *migrator.Migration, startIndex int, statementTimes []time.Duration) (int, []time.Duration, error) {
+	statementsApplied := startIndex
+
+	for i := startIndex; i < len(migration.Statements); i++ {
+		stmt := migration.Statements[i]
+
+		// Skip comment-only statements as they cannot be executed
+		if stmt.CommentStatement != nil {
+			statementsApplied++
+			statementTimes = append(statementTimes, 0)
+			continue
+		}
+
+		stmtSQL, err := e.formatStatement(stmt)
+		if err != nil {
+			return statementsApplied, statementTimes, errors.Wrapf(err, "failed to format statement %d", i+1)
+		}
+
+		elapsed, err := e.execStatement(ctx, ch, stmtSQL)
+		if err != nil {
+			if errors.Is(err, errStatementDeadline) {
+				return statementsApplied, statementTimes, &ErrStatementTimeout{
+					Statement: i + 1,
+					SQL:       stmtSQL,
+					Timeout:   e.statementTimeout,
+					Elapsed:   elapsed,
+				}
+			}
+			return statementsApplied, statementTimes, errors.Wrapf(err, "failed to execute statement %d: %s", i+1, stmtSQL)
+		}
+
+		statementsApplied++
+		statementTimes = append(statementTimes, elapsed)
+	}
+
+	return statementsApplied, statementTimes, nil
+}
+
+// errStatementDeadline is returned by execStatement when the statement timeout expired
+var errStatementDeadline = errors.New("statement timeout expired")
+
+// execStatement executes a single migration statement through ch, returning how long it ran. When a
+// statement timeout is configured, the statement runs under a context that's cancelled once
+// the timeout expires, in which case errStatementDeadline is returned. Cancelling ctx itself
+// is reported as the context's error rather than as a timeout.
+func (e *Executor) execStatement(ctx context.Context, ch ClickHouse, sql string) (time.Duration, error) {
+	stmtCtx := ctx
+	if e.statementTimeout > 0 {
+		var cancel context.CancelFunc
+		stmtCtx, cancel = context.WithTimeoutCause(ctx, e.statementTimeout, errStatementDeadline)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := ch.Exec(stmtCtx, sql)
+	elapsed := time.Since(start)
+
+	if err != nil && ctx.Err() == nil && errors.Is(context.Cause(stmtCtx), errStatementDeadline) {
+		return elapsed, errStatementDeadline
+	}
+	return elapsed, err
+}
+
+// applyStatementsInTransaction executes the migration statements starting at startIndex
+// within a single transaction. If any statement (or the commit) fails, the transaction is
+// rolled back and no statements from this run are reported as applied, so the next run
+// starts again from startIndex.
+//
+// When the client implements SessionClickHouse, the transaction runs in a session opened
+// for it, so BEGIN, the statements, and COMMIT or ROLLBACK all reach the same server session.
+func (e *Executor) applyStatementsInTransaction(ctx context.Context, migration *migrator.Migration, startIndex int, statementTimes []time.Duration) (int, []time.Duration, error) {
+	ch := e.ch
+	if sessions, ok := e.ch.(SessionClickHouse); ok {
+		session, err := sessions.Session(ctx)
+		if err != nil {
+			return startIndex, statementTimes, errors.Wrap(err, "failed to open transaction session")
+		}
+		defer func() { _ = session.Close() }()
+		ch = session
+	}
+
+	if err := ch.Exec(ctx, "BEGIN TRANSACTION"); err != nil {
+		return startIndex, statementTimes, errors.Wrap(err, "failed to begin transaction")
+	}
+
+	statementsApplied, appliedTimes, err := e.applyStatementsOn(ctx, ch, migration, startIndex, statementTimes)
+	if err == nil {
+		if err = ch.Exec(ctx, "COMMIT"); err == nil {
+			return statementsApplied, appliedTimes, nil
+		}
+		err = errors.Wrap(err, "failed to commit transaction")
+	}
+
+	if rollbackErr := ch.Exec(ctx, "ROLLBACK"); rollbackErr != nil {
+		return statementsApplied, appliedTimes, errors.Wrapf(err, "rollback failed (%v)", rollbackErr)
+	}
+
+	return startIndex, statementTimes[:startIndex], err
+}
+
+// SupportsTransactions checks whether the server has experimental transactions enabled.
+//
+// Transactions in ClickHouse are experimental and must be enabled in the server
+// configuration (allow_experimental_transactions). Servers that don't expose the
+// setting, or fail the check, are treated as not supporting transactions.
+//
+// Example usage:
+//
+//	supported, err := executor.SupportsTransactions(ctx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if !supported {
+//		fmt.Println("Transactional migrations will be applied statement by statement")
+//	}
+func (e *Executor) SupportsTransactions(ctx context.Context) (bool, error) {
+	rows, err := e.ch.Query(ctx, "SELECT 1 FROM system.server_settings WHERE name = 'allow_experimental_transactions' AND value IN ('1', 'true')")
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check for transaction support")
+	}
+	defer rows.Close()
+
+	return rows.Next(), nil
+}
+
+// resumedStatementTimes returns the statement times carried over from a partial revision
+// for the statements that were applied before the migration is resumed. Statements without
+// a recorded time (e.g. revisions written before timing was tracked) are recorded as zero.
+func resumedStatementTimes(partialRevision *migrator.Revision, startIndex int) []time.Duration {
+	times := make([]time.Duration, startIndex)
+	if partialRevision != nil {
+		copy(times, partialRevision.StatementTimes)
+	}
+	return times
+}
+
 // executeSnapshotMigration handles the execution of snapshot migrations.
 //
 // Snapshot migrations are treated specially:
@@ -474,6 +904,11 @@ func (e *Executor) getPartialRevision(migration *migrator.Migration, revisionSet
 		return nil, 0, nil
 	}
 
+	// A rolled back migration is applied again from the beginning
+	if revision.Kind != migrator.StandardRevision {
+		return nil, 0, nil
+	}
+
 	// If migration is completed, this shouldn't be called (caught earlier)
 	if revision.Error == nil && revision.Applied == revision.Total {
 		return nil, 0, nil
@@ -502,14 +937,17 @@ func (e *Executor) getPartialRevision(migration *migrator.Migration, revisionSet
 //   - Statement count has changed
 //   - Statement hashes don't match (indicating content changes)
 //
-// Returns error if validation fails, nil if validation passes.
+// Returns a *migrator.ErrIntegrityMismatch if validation fails, nil if validation passes.
 func (e *Executor) validatePartialRevision(migration *migrator.Migration, revision *migrator.Revision) error {
 	// Check that statement count matches
 	if len(migration.Statements) != revision.Total {
-		return errors.Errorf(
-			"migration statement count changed: expected %d statements, found %d in revision",
-			len(migration.Statements), revision.Total,
-		)
+		return &migrator.ErrIntegrityMismatch{
+			Version: migration.Version,
+			Reason: fmt.Sprintf(
+				"migration statement count changed: expected %d statements, found %d in revision",
+				len(migration.Statements), revision.Total,
+			),
+		}
 	}
 
 	// Check that we have partial hashes for validation
@@ -529,18 +967,26 @@ func (e *Executor) validatePartialRevision(migration *migrator.Migration, revisi
 		actualHash := e.computeHash(stmtSQL)
 
 		if expectedHash != actualHash {
-			return errors.Errorf(
-				"statement %d hash mismatch: migration file may have been modified since partial execution (expected %s, got %s)",
-				i+1, expectedHash, actualHash,
-			)
+			return &migrator.ErrIntegrityMismatch{
+				Version:   migration.Version,
+				Statement: i + 1,
+				Expected:  expectedHash,
+				Actual:    actualHash,
+				Reason:    "migration file may have been modified since partial execution",
+			}
 		}
 	}
 
 	return nil
 }
 
-// saveRevision saves a revision record to the housekeeper.revisions table.
+// saveRevision saves a revision record to the housekeeper.revisions table. Nothing is
+// saved when revision tracking is skipped.
 func (e *Executor) saveRevision(ctx context.Context, revision *migrator.Revision) error {
+	if e.skipRevisions {
+		return nil
+	}
+
 	insertSQL := `
 		INSERT INTO housekeeper.revisions (
 			version,
@@ -552,8 +998,9 @@ func (e *Executor) saveRevision(ctx context.Context, revision *migrator.Revision
 			total,
 			hash,
 			partial_hashes,
+			statement_times_ms,
 			housekeeper_version
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var errorValue *string
@@ -561,6 +1008,11 @@ func (e *Executor) saveRevision(ctx context.Context, revision *migrator.Revision
 		errorValue = revision.Error
 	}
 
+	statementTimesMs := make([]uint64, len(revision.StatementTimes))
+	for i, duration := range revision.StatementTimes {
+		statementTimesMs[i] = uint64(max(duration.Milliseconds(), 0))
+	}
+
 	return e.ch.Exec(ctx, insertSQL,
 		revision.Version,
 		revision.ExecutedAt,
@@ -571,6 +1023,7 @@ func (e *Executor) saveRevision(ctx context.Context, revision *migrator.Revision
 		revision.Total,
 		revision.Hash,
 		revision.PartialHashes,
+		statementTimesMs,
 		revision.HousekeeperVersion,
 	)
 }
@@ -5,10 +5,13 @@ import (
 	"math"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
 	"github.com/pseudomuto/housekeeper/pkg/executor"
 	"github.com/pseudomuto/housekeeper/pkg/format"
 	"github.com/pseudomuto/housekeeper/pkg/migrator"
@@ -39,6 +42,40 @@ func (m *mockClickHouse) Exec(ctx context.Context, query string, args ...any) er
 	return nil
 }
 
+var _ executor.SessionClickHouse = (*clickhouse.Client)(nil)
+
+// mockSessionClickHouse is a mockClickHouse whose sessions record their statements
+// separately from the statements sent through the pool
+type mockSessionClickHouse struct {
+	*mockClickHouse
+	sessions []*mockSession
+}
+
+func (m *mockSessionClickHouse) Session(context.Context) (driver.Conn, error) {
+	session := &mockSession{mockClickHouse: &mockClickHouse{execFunc: m.execFunc}}
+	m.sessions = append(m.sessions, session)
+	return session, nil
+}
+
+type mockSession struct {
+	driver.Conn
+	*mockClickHouse
+	closed bool
+}
+
+func (m *mockSession) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	return m.mockClickHouse.Query(ctx, query, args...)
+}
+
+func (m *mockSession) Exec(ctx context.Context, query string, args ...any) error {
+	return m.mockClickHouse.Exec(ctx, query, args...)
+}
+
+func (m *mockSession) Close() error {
+	m.closed = true
+	return nil
+}
+
 type mockRows struct {
 	nextCalled bool
 	scanCalled bool
@@ -272,8 +309,8 @@ func TestExecutor_Execute(t *testing.T) {
 
 				// Mock execution failure
 				m.execFunc = func(ctx context.Context, query string, args ...any) error {
-					if len(args) > 0 {
-						// This is the INSERT statement for revision - let it succeed
+					if len(args) > 0 || strings.Contains(query, "ALTER TABLE housekeeper.revisions") {
+						// This is the revisions table upgrade or INSERT - let it succeed
 						return nil
 					}
 					// This is the migration statement - make it fail
@@ -312,6 +349,39 @@ func TestExecutor_Execute(t *testing.T) {
 	}
 }
 
+func TestExecutor_ExecuteSystemStatements(t *testing.T) {
+	migrationDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+		"20240101120000_reload.sql": &fstest.MapFile{
+			Data: []byte("ALTER TABLE analytics.users ADD COLUMN email String;\nSYSTEM RELOAD DICTIONARY analytics.users_dict;"),
+		},
+	})
+	require.NoError(t, err)
+
+	queryCallCount := 0
+	mockCH := &mockClickHouse{
+		queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			queryCallCount++
+			if queryCallCount <= 2 {
+				return &mockRows{}, nil
+			}
+			return &mockRows{nextCalled: true}, nil
+		},
+	}
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         mockCH,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "1.0.0",
+	})
+
+	results, err := exec.Execute(context.Background(), migrationDir.Migrations)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, executor.StatusSuccess, results[0].Status)
+	require.Equal(t, 2, results[0].StatementsApplied)
+	require.Contains(t, mockCH.execs, "SYSTEM RELOAD DICTIONARY `analytics`.`users_dict`;")
+}
+
 // mockCompletedRevisionRows simulates a successful revision in the database
 type mockCompletedRevisionRows struct {
 	nextCalled bool
@@ -383,6 +453,11 @@ func (m *mockCompletedRevisionRows) Scan(dest ...any) error {
 		}
 	})
 	scanField(9, func(d any) {
+		if statementTimes, ok := d.(*[]uint64); ok {
+			*statementTimes = []uint64{1000}
+		}
+	})
+	scanField(10, func(d any) {
 		if housekeeperVersion, ok := d.(*string); ok {
 			*housekeeperVersion = "1.0.0"
 		}
@@ -471,8 +546,9 @@ func TestExecutor_SnapshotExecution(t *testing.T) {
 				// Track exec calls to ensure DDL is NOT executed for snapshots
 				m.execFunc = func(ctx context.Context, query string, args ...any) error {
 					execCallCount++
-					// Only allow revision insertion, not DDL execution
-					if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+					// Only allow revisions table maintenance, not DDL execution
+					if strings.Contains(query, "INSERT INTO housekeeper.revisions") ||
+						strings.Contains(query, "ALTER TABLE housekeeper.revisions") {
 						return nil
 					}
 					t.Errorf("Unexpected DDL execution for snapshot: %s", query)
@@ -581,6 +657,7 @@ func TestExecutor_ResumePartialMigration(t *testing.T) {
 		expectedResult   executor.ExecutionStatus
 		expectedApplied  int
 		expectError      bool
+		expectMismatch   bool
 	}{
 		{
 			name: "successful resume from partial execution",
@@ -648,6 +725,7 @@ func TestExecutor_ResumePartialMigration(t *testing.T) {
 			},
 			expectedResult: executor.StatusFailed,
 			expectError:    false, // Execute doesn't return error, just failed status
+			expectMismatch: true,
 		},
 		{
 			name: "statement count mismatch",
@@ -677,6 +755,7 @@ func TestExecutor_ResumePartialMigration(t *testing.T) {
 			},
 			expectedResult: executor.StatusFailed,
 			expectError:    false, // Execute doesn't return error, just failed status
+			expectMismatch: true,
 		},
 	}
 
@@ -732,6 +811,12 @@ func TestExecutor_ResumePartialMigration(t *testing.T) {
 			if tt.expectedApplied > 0 {
 				require.Equal(t, tt.expectedApplied, result.StatementsApplied)
 			}
+
+			if tt.expectMismatch {
+				var mismatch *migrator.ErrIntegrityMismatch
+				require.ErrorAs(t, result.Error, &mismatch)
+				require.Equal(t, tt.migration.Version, mismatch.Version)
+			}
 		})
 	}
 }
@@ -790,7 +875,13 @@ func (m *mockResumeRows) Scan(dest ...any) error {
 		if partialHashes, ok := dest[8].(*[]string); ok {
 			*partialHashes = m.revision.PartialHashes
 		}
-		if housekeeperVersion, ok := dest[9].(*string); ok {
+		if statementTimes, ok := dest[9].(*[]uint64); ok {
+			*statementTimes = make([]uint64, len(m.revision.StatementTimes))
+			for i, duration := range m.revision.StatementTimes {
+				(*statementTimes)[i] = uint64(duration.Milliseconds()) //nolint:gosec // test durations are positive
+			}
+		}
+		if housekeeperVersion, ok := dest[10].(*string); ok {
 			*housekeeperVersion = m.revision.HousekeeperVersion
 		}
 	}
@@ -811,7 +902,7 @@ func (m *mockResumeRows) ColumnTypes() []driver.ColumnType {
 }
 
 func (m *mockResumeRows) Columns() []string {
-	return []string{"version", "executed_at", "execution_time_ms", "kind", "error", "applied", "total", "hash", "partial_hashes", "housekeeper_version"}
+	return []string{"version", "executed_at", "execution_time_ms", "kind", "error", "applied", "total", "hash", "partial_hashes", "statement_times_ms", "housekeeper_version"}
 }
 
 func (m *mockResumeRows) ScanStruct(dest any) error {
@@ -826,3 +917,853 @@ func (m *mockResumeRows) Totals(dest ...any) error {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestExecutor_RecordsStatementTimes(t *testing.T) {
+	comment := "-- just a comment"
+	migration := &migrator.Migration{
+		Version: "20240101120000_timed",
+		Statements: []*parser.Statement{
+			{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db1"}},
+			{CommentStatement: &parser.CommentStatement{Comment: comment}},
+			{CreateDatabase: &parser.CreateDatabaseStmt{Name: "slow_db"}},
+		},
+	}
+
+	t.Run("fresh execution", func(t *testing.T) {
+		var recorded []uint64
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "FROM housekeeper.revisions") {
+					return &mockRows{nextCalled: true}, nil
+				}
+				return &mockRows{}, nil
+			},
+			execFunc: func(ctx context.Context, query string, args ...any) error {
+				if strings.Contains(query, "slow_db") {
+					time.Sleep(5 * time.Millisecond)
+				}
+				if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+					recorded = args[9].([]uint64)
+				}
+				return nil
+			},
+		}
+
+		exec := executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+		})
+
+		results, err := exec.Execute(context.Background(), []*migrator.Migration{migration})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusSuccess, results[0].Status)
+
+		times := results[0].Revision.StatementTimes
+		require.Len(t, times, 3)
+		require.Zero(t, times[1], "comment statements should not be timed")
+		require.GreaterOrEqual(t, times[2], 5*time.Millisecond)
+		require.Len(t, recorded, 3)
+		require.GreaterOrEqual(t, recorded[2], uint64(5))
+	})
+
+	t.Run("resumed execution keeps earlier times", func(t *testing.T) {
+		tempExecutor := executor.New(executor.Config{
+			ClickHouse: &mockClickHouse{},
+			Formatter:  format.New(format.Defaults),
+		})
+		_, partialHashes := tempExecutor.ComputeHashes(migration)
+
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "FROM housekeeper.revisions") {
+					return &mockResumeRows{revision: &migrator.Revision{
+						Version:        migration.Version,
+						Kind:           migrator.StandardRevision,
+						Applied:        1,
+						Total:          3,
+						PartialHashes:  partialHashes,
+						StatementTimes: []time.Duration{2 * time.Second},
+						Error:          stringPtr("failed at statement 2"),
+					}}, nil
+				}
+				return &mockRows{}, nil
+			},
+		}
+
+		exec := executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+		})
+
+		results, err := exec.Execute(context.Background(), []*migrator.Migration{migration})
+		require.NoError(t, err)
+		require.Equal(t, executor.StatusSuccess, results[0].Status)
+
+		times := results[0].Revision.StatementTimes
+		require.Len(t, times, 3)
+		require.Equal(t, 2*time.Second, times[0])
+	})
+}
+
+func TestExecutor_StatementTimeout(t *testing.T) {
+	migrations := []*migrator.Migration{
+		{
+			Version: "20240101120000_slow",
+			Statements: []*parser.Statement{
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db1"}},
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "slow_db"}},
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db3"}},
+			},
+		},
+		{
+			Version: "20240101130000_next",
+			Statements: []*parser.Statement{
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "next_db"}},
+			},
+		},
+	}
+
+	// newMock returns a client on which the slow_db statement blocks until it's cancelled
+	newMock := func(recordedError **string) *mockClickHouse {
+		return &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "FROM housekeeper.revisions") {
+					return &mockRows{nextCalled: true}, nil
+				}
+				return &mockRows{}, nil
+			},
+			execFunc: func(ctx context.Context, query string, args ...any) error {
+				if strings.Contains(query, "slow_db") {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				if strings.Contains(query, "INSERT INTO housekeeper.revisions") && recordedError != nil {
+					*recordedError = args[4].(*string)
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("timeout fails the migration", func(t *testing.T) {
+		var recordedError *string
+		mockCH := newMock(&recordedError)
+
+		exec := executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+			StatementTimeout:   20 * time.Millisecond,
+		})
+
+		results, err := exec.Execute(context.Background(), migrations)
+		require.NoError(t, err)
+		require.Len(t, results, 1, "execution should stop at the timed out migration")
+
+		result := results[0]
+		require.Equal(t, executor.StatusFailed, result.Status)
+		require.Equal(t, 1, result.StatementsApplied)
+
+		var timeout *executor.ErrStatementTimeout
+		require.ErrorAs(t, result.Error, &timeout)
+		require.Equal(t, 2, timeout.Statement)
+		require.Contains(t, timeout.SQL, "slow_db")
+		require.Equal(t, 20*time.Millisecond, timeout.Timeout)
+		require.GreaterOrEqual(t, timeout.Elapsed, 20*time.Millisecond)
+		require.ErrorIs(t, result.Error, context.DeadlineExceeded)
+
+		require.NotNil(t, result.Revision)
+		require.Equal(t, 1, result.Revision.Applied)
+		require.NotNil(t, result.Revision.Error)
+		require.Contains(t, *result.Revision.Error, "statement 2 timed out after ")
+		require.NotNil(t, recordedError)
+		require.Equal(t, *result.Revision.Error, *recordedError)
+
+		for _, sql := range mockCH.execs {
+			require.NotContains(t, sql, "db3")
+			require.NotContains(t, sql, "next_db")
+		}
+	})
+
+	t.Run("cancellation is not a timeout", func(t *testing.T) {
+		exec := executor.New(executor.Config{
+			ClickHouse:       newMock(nil),
+			Formatter:        format.New(format.Defaults),
+			StatementTimeout: time.Minute,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		results, err := exec.Execute(ctx, migrations)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusFailed, results[0].Status)
+
+		var timeout *executor.ErrStatementTimeout
+		require.False(t, errors.As(results[0].Error, &timeout))
+		require.ErrorContains(t, results[0].Error, "failed to execute statement 2")
+	})
+}
+
+func TestExecutor_TruncateTable(t *testing.T) {
+	migration := &migrator.Migration{
+		Version: "20240101120000_truncate",
+		Statements: []*parser.Statement{
+			{TruncateTable: &parser.TruncateTableStmt{
+				IfExists:  true,
+				Database:  stringPtr("analytics"),
+				Name:      "events",
+				OnCluster: stringPtr("production"),
+			}},
+		},
+	}
+
+	mockCH := &mockClickHouse{
+		queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			if strings.Contains(query, "FROM housekeeper.revisions") {
+				return &mockRows{nextCalled: true}, nil
+			}
+			return &mockRows{}, nil
+		},
+	}
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         mockCH,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "test-version",
+	})
+
+	results, err := exec.Execute(context.Background(), []*migrator.Migration{migration})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, executor.StatusSuccess, results[0].Status)
+	require.Contains(t, mockCH.execs, "TRUNCATE TABLE IF EXISTS `analytics`.`events` ON CLUSTER `production`;")
+}
+
+func TestExecutor_TransactionalMigrations(t *testing.T) {
+	migration := &migrator.Migration{
+		Version:         "20240101120000_backfill",
+		IsTransactional: true,
+		Statements: []*parser.Statement{
+			{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db1"}},
+			{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db2"}},
+		},
+	}
+
+	// newMock returns a mock that reports the given transaction support and fails
+	// executing any statement containing failOn (when set)
+	newMock := func(supported bool, failOn string) *mockClickHouse {
+		return &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "FROM housekeeper.revisions") {
+					return &mockRows{nextCalled: true}, nil
+				}
+				if strings.Contains(query, "allow_experimental_transactions") {
+					return &mockRows{nextCalled: !supported}, nil
+				}
+				return &mockRows{}, nil
+			},
+			execFunc: func(ctx context.Context, query string, args ...any) error {
+				if failOn != "" && strings.Contains(query, failOn) {
+					return errors.New("execution failed")
+				}
+				return nil
+			},
+		}
+	}
+
+	// migrationExecs returns the executed statements, excluding bookkeeping queries
+	migrationExecs := func(m *mockClickHouse) []string {
+		var execs []string
+		for _, query := range m.execs {
+			if !strings.Contains(query, "housekeeper.revisions") {
+				execs = append(execs, strings.TrimSpace(query))
+			}
+		}
+		return execs
+	}
+
+	execute := func(t *testing.T, m executor.ClickHouse, migration *migrator.Migration) *executor.ExecutionResult {
+		t.Helper()
+
+		exec := executor.New(executor.Config{
+			ClickHouse:         m,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+		})
+
+		results, err := exec.Execute(context.Background(), []*migrator.Migration{migration})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	t.Run("wraps statements in a transaction", func(t *testing.T) {
+		mockCH := newMock(true, "")
+		result := execute(t, mockCH, migration)
+
+		require.Equal(t, executor.StatusSuccess, result.Status)
+		require.Equal(t, 2, result.StatementsApplied)
+		require.Equal(t, []string{
+			"BEGIN TRANSACTION",
+			"CREATE DATABASE `db1`;",
+			"CREATE DATABASE `db2`;",
+			"COMMIT",
+		}, migrationExecs(mockCH))
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		mockCH := newMock(true, "db2")
+		result := execute(t, mockCH, migration)
+
+		require.Equal(t, executor.StatusFailed, result.Status)
+		require.Zero(t, result.StatementsApplied)
+		require.Zero(t, result.Revision.Applied)
+		require.Empty(t, result.Revision.StatementTimes)
+		require.Equal(t, []string{
+			"BEGIN TRANSACTION",
+			"CREATE DATABASE `db1`;",
+			"CREATE DATABASE `db2`;",
+			"ROLLBACK",
+		}, migrationExecs(mockCH))
+	})
+
+	t.Run("rolls back when commit fails", func(t *testing.T) {
+		mockCH := newMock(true, "COMMIT")
+		result := execute(t, mockCH, migration)
+
+		require.Equal(t, executor.StatusFailed, result.Status)
+		require.ErrorContains(t, result.Error, "failed to commit transaction")
+		require.Zero(t, result.StatementsApplied)
+		execs := migrationExecs(mockCH)
+		require.Equal(t, []string{"COMMIT", "ROLLBACK"}, execs[len(execs)-2:])
+	})
+
+	t.Run("runs the transaction in one session", func(t *testing.T) {
+		for _, failOn := range []string{"", "db2"} {
+			mockCH := &mockSessionClickHouse{mockClickHouse: newMock(true, failOn)}
+			execute(t, mockCH, migration)
+
+			// Only bookkeeping goes through the pool, every transaction statement is
+			// sent through the single session opened for the migration
+			require.Empty(t, migrationExecs(mockCH.mockClickHouse))
+			require.Len(t, mockCH.sessions, 1)
+			require.True(t, mockCH.sessions[0].closed)
+
+			end := "COMMIT"
+			if failOn != "" {
+				end = "ROLLBACK"
+			}
+			require.Equal(t, []string{
+				"BEGIN TRANSACTION",
+				"CREATE DATABASE `db1`;",
+				"CREATE DATABASE `db2`;",
+				end,
+			}, migrationExecs(mockCH.sessions[0].mockClickHouse))
+		}
+	})
+
+	t.Run("falls back when unsupported", func(t *testing.T) {
+		mockCH := newMock(false, "db2")
+		result := execute(t, mockCH, migration)
+
+		require.Equal(t, executor.StatusFailed, result.Status)
+		require.Equal(t, 1, result.StatementsApplied)
+		require.Equal(t, []string{
+			"CREATE DATABASE `db1`;",
+			"CREATE DATABASE `db2`;",
+		}, migrationExecs(mockCH))
+	})
+
+	t.Run("ignores support for migrations without the marker", func(t *testing.T) {
+		mockCH := newMock(true, "")
+		result := execute(t, mockCH, &migrator.Migration{
+			Version:    migration.Version,
+			Statements: migration.Statements,
+		})
+
+		require.Equal(t, executor.StatusSuccess, result.Status)
+		require.Equal(t, []string{
+			"CREATE DATABASE `db1`;",
+			"CREATE DATABASE `db2`;",
+		}, migrationExecs(mockCH))
+		for _, query := range mockCH.queries {
+			require.NotContains(t, query, "allow_experimental_transactions")
+		}
+	})
+}
+
+func TestExecutor_NoOpMigrations(t *testing.T) {
+	migrationDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+		"001_notes.sql": &fstest.MapFile{Data: []byte("-- Placeholder for the analytics rollout\n/* Nothing to apply yet */\n")},
+		"002_empty.sql": &fstest.MapFile{Data: []byte("\n")},
+		"003_init.sql":  &fstest.MapFile{Data: []byte("CREATE DATABASE analytics ENGINE = Atomic;")},
+		"004_todo.sql":  &fstest.MapFile{Data: []byte("-- housekeeper:transactional\n-- TODO: backfill events\n")},
+	})
+	require.NoError(t, err)
+	migrations := migrationDir.Migrations
+	require.True(t, migrations[0].IsNoOp())
+	require.True(t, migrations[1].IsNoOp())
+	require.False(t, migrations[2].IsNoOp())
+	require.True(t, migrations[3].IsNoOp())
+
+	// newExecutor returns an executor for a server with transaction support and the
+	// given revisions, which records the revisions it saves
+	newExecutor := func(mockCH *mockClickHouse, revisions []*migrator.Revision, saved *[]string) *executor.Executor {
+		mockCH.queryFunc = func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			if strings.Contains(query, "FROM housekeeper.revisions") {
+				return &mockRevisionRows{revisions: revisions}, nil
+			}
+			return &mockRows{}, nil
+		}
+		mockCH.execFunc = func(ctx context.Context, query string, args ...any) error {
+			if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+				*saved = append(*saved, args[0].(string))
+			}
+			return nil
+		}
+
+		return executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+		})
+	}
+
+	// First run: every migration is recorded as applied, but only the DDL is executed
+	mockCH := &mockClickHouse{}
+	var saved []string
+	results, err := newExecutor(mockCH, nil, &saved).Execute(context.Background(), migrations)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	require.Equal(t, []string{"001_notes", "002_empty", "003_init", "004_todo"}, saved)
+
+	revisions := make([]*migrator.Revision, 0, len(results))
+	for _, result := range results {
+		require.Equal(t, executor.StatusSuccess, result.Status, result.Version)
+		require.Equal(t, result.TotalStatements, result.StatementsApplied, result.Version)
+		require.Nil(t, result.Revision.Error)
+		require.Equal(t, result.Revision.Total, result.Revision.Applied)
+		revisions = append(revisions, result.Revision)
+	}
+	require.Zero(t, results[1].TotalStatements)
+
+	for _, query := range mockCH.execs {
+		require.NotContains(t, query, "TRANSACTION", "no-op migrations aren't wrapped in a transaction")
+		require.NotContains(t, query, "Placeholder")
+	}
+	require.Contains(t, mockCH.execs, "CREATE DATABASE `analytics` ENGINE = Atomic;")
+
+	// Second run: the recorded migrations are completed, so nothing is run or recorded again
+	mockCH = &mockClickHouse{}
+	saved = nil
+	results, err = newExecutor(mockCH, revisions, &saved).Execute(context.Background(), migrations)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for _, result := range results {
+		require.Equal(t, executor.StatusSkipped, result.Status, result.Version)
+	}
+	require.Empty(t, saved)
+	require.NotContains(t, mockCH.execs, "CREATE DATABASE `analytics` ENGINE = Atomic;")
+
+	// A no-op migration can be rolled back without a down section
+	mockCH = &mockClickHouse{}
+	rollbacks, err := newExecutor(mockCH, revisions, &saved).Rollback(context.Background(), migrations, 1)
+	require.NoError(t, err)
+	require.Len(t, rollbacks, 1)
+	require.Equal(t, "004_todo", rollbacks[0].Version)
+	require.Equal(t, executor.StatusSuccess, rollbacks[0].Status)
+	require.Equal(t, []string{"004_todo"}, saved)
+
+	_, err = newExecutor(mockCH, revisions, &saved).Rollback(context.Background(), migrations, 2)
+	require.EqualError(t, err, "cannot roll back 003_init: migration has no down section")
+}
+
+func TestExecutor_Bootstrap(t *testing.T) {
+	t.Run("creates infrastructure", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				// Neither the database nor the table exist yet
+				return &mockRows{nextCalled: true}, nil
+			},
+		}
+
+		exec := executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+		})
+
+		require.NoError(t, exec.Bootstrap(context.Background()))
+		require.Len(t, mockCH.execs, 3)
+		require.Contains(t, mockCH.execs[0], "CREATE DATABASE IF NOT EXISTS `housekeeper`")
+		require.Contains(t, mockCH.execs[1], "CREATE TABLE IF NOT EXISTS `housekeeper`.`revisions`")
+		require.Contains(t, mockCH.execs[2], "CREATE TABLE IF NOT EXISTS `housekeeper`.`migration_lock`")
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				return &mockRows{nextCalled: true}, nil
+			},
+			execFunc: func(ctx context.Context, query string, args ...any) error {
+				return errors.New("permission denied")
+			},
+		}
+
+		exec := executor.New(executor.Config{
+			ClickHouse: mockCH,
+			Formatter:  format.New(format.Defaults),
+		})
+
+		err := exec.Bootstrap(context.Background())
+		require.ErrorContains(t, err, "failed to bootstrap housekeeper infrastructure")
+		require.ErrorContains(t, err, "permission denied")
+	})
+}
+
+func TestExecutor_LegacyRevisionsTable_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	testutil.SkipIfNoDocker(t)
+
+	ctx := context.Background()
+	_, dsn := testutil.StartClickHouseContainer(t, "")
+
+	client, err := clickhouse.NewClient(ctx, dsn)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	// The revisions table as created before per-statement timings were recorded
+	for _, sql := range []string{
+		"CREATE DATABASE housekeeper ENGINE = Atomic",
+		`CREATE TABLE housekeeper.revisions (
+			version String,
+			executed_at DateTime(3, 'UTC'),
+			execution_time_ms UInt64,
+			kind String,
+			error Nullable(String),
+			applied UInt32,
+			total UInt32,
+			hash String,
+			partial_hashes Array(String),
+			housekeeper_version String
+		) ENGINE = MergeTree() ORDER BY version`,
+		`INSERT INTO housekeeper.revisions VALUES
+			('20240101120000_init', now64(3), 10, 'migration', NULL, 1, 1, 'h1:abc=', ['h1:abc='], '0.1.0')`,
+	} {
+		require.NoError(t, client.Exec(ctx, sql))
+	}
+
+	// Read paths such as status and dry runs load revisions without upgrading the table
+	revisionSet, err := migrator.LoadRevisions(ctx, client)
+	require.NoError(t, err)
+	require.True(t, revisionSet.HasRevision("20240101120000_init"))
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         client,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "test-1.0.0",
+		DryRun:             true,
+	})
+
+	migration := &migrator.Migration{
+		Version:    "20240101120000_init",
+		Statements: []*parser.Statement{{CreateDatabase: &parser.CreateDatabaseStmt{Name: "analytics"}}},
+	}
+	results, err := exec.Execute(ctx, []*migrator.Migration{migration})
+	require.NoError(t, err)
+	require.Equal(t, executor.StatusSkipped, results[0].Status)
+
+	// Migrating upgrades the table, after which timings are recorded and loaded
+	exec = executor.New(executor.Config{
+		ClickHouse:         client,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "test-1.0.0",
+	})
+	migration = &migrator.Migration{
+		Version:    "20240102120000_analytics",
+		Statements: []*parser.Statement{{CreateDatabase: &parser.CreateDatabaseStmt{Name: "analytics"}}},
+	}
+	results, err = exec.Execute(ctx, []*migrator.Migration{migration})
+	require.NoError(t, err)
+	require.Equal(t, executor.StatusSuccess, results[0].Status)
+
+	revisionSet, err = migrator.LoadRevisions(ctx, client)
+	require.NoError(t, err)
+	require.Len(t, revisionSet.GetRevision(migration).StatementTimes, 1)
+}
+
+func TestExecutor_MarkApplied(t *testing.T) {
+	migrations := []*migrator.Migration{
+		{
+			Version:    "20240101120000_init",
+			Statements: []*parser.Statement{{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db1"}}},
+		},
+		{
+			Version: "20240101130000_tables",
+			Statements: []*parser.Statement{
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db2"}},
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db3"}},
+			},
+		},
+	}
+
+	var inserted [][]any
+	mockCH := &mockClickHouse{
+		queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			if strings.Contains(query, "FROM housekeeper.revisions") {
+				// The first migration was already recorded
+				return &mockResumeRows{
+					revision: &migrator.Revision{
+						Version:    "20240101120000_init",
+						ExecutedAt: time.Now(),
+						Kind:       migrator.StandardRevision,
+						Applied:    1,
+						Total:      1,
+					},
+				}, nil
+			}
+			return &mockRows{}, nil
+		},
+		execFunc: func(ctx context.Context, query string, args ...any) error {
+			if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+				inserted = append(inserted, args)
+			}
+			return nil
+		},
+	}
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         mockCH,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "test-version",
+	})
+
+	results, err := exec.MarkApplied(context.Background(), migrations)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, executor.StatusSkipped, results[0].Status)
+	require.Nil(t, results[0].Revision)
+
+	require.Equal(t, executor.StatusSuccess, results[1].Status)
+	require.Equal(t, 2, results[1].StatementsApplied)
+	require.NotNil(t, results[1].Revision)
+	require.Equal(t, migrator.StandardRevision, results[1].Revision.Kind)
+	require.Equal(t, 2, results[1].Revision.Applied)
+	require.Equal(t, 2, results[1].Revision.Total)
+	require.Nil(t, results[1].Revision.Error)
+
+	hash, partialHashes := exec.ComputeHashes(migrations[1])
+	require.Equal(t, hash, results[1].Revision.Hash)
+	require.Equal(t, partialHashes, results[1].Revision.PartialHashes)
+
+	// Only the pending migration is recorded, and none of its statements are executed
+	require.Len(t, inserted, 1)
+	require.Equal(t, "20240101130000_tables", inserted[0][0])
+	for _, query := range mockCH.execs {
+		require.NotContains(t, query, "db2")
+		require.NotContains(t, query, "db3")
+	}
+}
+
+func TestExecutor_DryRun(t *testing.T) {
+	migrations := []*migrator.Migration{
+		{
+			Version: "20240101120000_init",
+			Statements: []*parser.Statement{
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db1"}},
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db2"}},
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db3"}},
+			},
+		},
+		{
+			Version:    "20240101130000_snapshot",
+			IsSnapshot: true,
+			Statements: []*parser.Statement{{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db4"}}},
+		},
+	}
+
+	newExecutor := func(mockCH *mockClickHouse) *executor.Executor {
+		return executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "test-version",
+			DryRun:             true,
+		})
+	}
+
+	t.Run("reports pending migrations on a new server", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				// The housekeeper database doesn't exist
+				return &mockRows{nextCalled: true}, nil
+			},
+		}
+
+		results, err := newExecutor(mockCH).Execute(context.Background(), migrations)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		require.Equal(t, executor.StatusDryRun, results[0].Status)
+		require.Equal(t, 0, results[0].StatementsApplied)
+		require.Equal(t, 3, results[0].TotalStatements)
+		require.Equal(t, []string{
+			"CREATE DATABASE `db1`;",
+			"CREATE DATABASE `db2`;",
+			"CREATE DATABASE `db3`;",
+		}, results[0].Statements)
+		require.Nil(t, results[0].Revision)
+
+		require.Equal(t, executor.StatusDryRun, results[1].Status)
+		require.Empty(t, results[1].Statements, "snapshots don't execute their statements")
+
+		// Nothing is bootstrapped, executed, or recorded
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("reports the statements left in a partial migration", func(t *testing.T) {
+		_, partialHashes := newExecutor(&mockClickHouse{}).ComputeHashes(migrations[0])
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "FROM housekeeper.revisions") {
+					return &mockResumeRows{
+						revision: &migrator.Revision{
+							Version:       "20240101120000_init",
+							ExecutedAt:    time.Now(),
+							Kind:          migrator.StandardRevision,
+							Applied:       1,
+							Total:         3,
+							PartialHashes: partialHashes,
+							Error:         stringPtr("statement 2 failed"),
+						},
+					}, nil
+				}
+				return &mockRows{}, nil
+			},
+		}
+
+		results, err := newExecutor(mockCH).Execute(context.Background(), migrations[:1])
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusDryRun, results[0].Status)
+		require.Equal(t, 1, results[0].StatementsApplied)
+		require.Equal(t, []string{"CREATE DATABASE `db2`;", "CREATE DATABASE `db3`;"}, results[0].Statements)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("stops at a modified partial migration", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "FROM housekeeper.revisions") {
+					return &mockResumeRows{
+						revision: &migrator.Revision{
+							Version:       "20240101120000_init",
+							ExecutedAt:    time.Now(),
+							Kind:          migrator.StandardRevision,
+							Applied:       1,
+							Total:         3,
+							PartialHashes: []string{"h1:original_hash=", "h1:hash2=", "h1:hash3="},
+						},
+					}, nil
+				}
+				return &mockRows{}, nil
+			},
+		}
+
+		results, err := newExecutor(mockCH).Execute(context.Background(), migrations)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusFailed, results[0].Status)
+
+		var mismatch *migrator.ErrIntegrityMismatch
+		require.ErrorAs(t, results[0].Error, &mismatch)
+		require.Empty(t, mockCH.execs)
+	})
+}
+
+func TestExecutor_SkipRevisionTracking(t *testing.T) {
+	migrations := []*migrator.Migration{
+		{
+			Version: "20240101120000_init",
+			Statements: []*parser.Statement{
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db1"}},
+				{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db2"}},
+			},
+		},
+		{
+			Version:    "20240101130000_snapshot",
+			IsSnapshot: true,
+			Statements: []*parser.Statement{{CreateDatabase: &parser.CreateDatabaseStmt{Name: "db3"}}},
+		},
+	}
+
+	newExecutor := func(mockCH *mockClickHouse) *executor.Executor {
+		return executor.New(executor.Config{
+			ClickHouse:           mockCH,
+			Formatter:            format.New(format.Defaults),
+			HousekeeperVersion:   "test-version",
+			SkipRevisionTracking: true,
+		})
+	}
+
+	t.Run("applies migrations without touching revisions", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		exec := newExecutor(mockCH)
+
+		// Every run re-applies all migrations since nothing is recorded
+		for range 2 {
+			mockCH.execs = nil
+
+			results, err := exec.Execute(context.Background(), migrations)
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+			require.Equal(t, executor.StatusSuccess, results[0].Status)
+			require.Equal(t, 2, results[0].StatementsApplied)
+			require.Equal(t, executor.StatusSuccess, results[1].Status)
+
+			require.Equal(t, []string{"CREATE DATABASE `db1`;", "CREATE DATABASE `db2`;"}, mockCH.execs)
+		}
+
+		for _, query := range mockCH.queries {
+			require.NotContains(t, query, "housekeeper")
+		}
+	})
+
+	t.Run("dry run treats every migration as pending", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		exec := executor.New(executor.Config{
+			ClickHouse:           mockCH,
+			Formatter:            format.New(format.Defaults),
+			DryRun:               true,
+			SkipRevisionTracking: true,
+		})
+
+		results, err := exec.Execute(context.Background(), migrations[:1])
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusDryRun, results[0].Status)
+		require.Equal(t, []string{"CREATE DATABASE `db1`;", "CREATE DATABASE `db2`;"}, results[0].Statements)
+		require.Empty(t, mockCH.queries)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("revision operations are rejected", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		exec := newExecutor(mockCH)
+
+		_, err := exec.MarkApplied(context.Background(), migrations)
+		require.ErrorContains(t, err, "revision tracking is skipped")
+
+		_, err = exec.Rollback(context.Background(), migrations, 1)
+		require.ErrorContains(t, err, "revision tracking is skipped")
+
+		require.Empty(t, mockCH.execs)
+	})
+}
@@ -0,0 +1,192 @@
+package executor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+)
+
+const (
+	// DefaultLockTTL is how long a migration lock is honored when TableLocker isn't
+	// given a TTL. Locks older than the TTL are considered abandoned, e.g. by a runner
+	// that was killed before it could release the lock.
+	DefaultLockTTL = 15 * time.Minute
+
+	// migrationLockID is the key of the single lock guarding migrations
+	migrationLockID = "migrations"
+
+	// migrationLockTableSQL creates the table holding migration lock claims. Claims are
+	// timestamped by the server so runners with skewed clocks agree on their order.
+	migrationLockTableSQL = `CREATE TABLE IF NOT EXISTS housekeeper.migration_lock (
+    lock_id String COMMENT 'The key of the lock',
+    token String COMMENT 'Unique token identifying a single claim of the lock',
+    holder String COMMENT 'The host and pid of the process claiming the lock',
+    acquired_at DateTime64(6, 'UTC') DEFAULT now64(6, 'UTC') COMMENT 'The UTC time at which the lock was claimed'
+)
+ENGINE = MergeTree()
+ORDER BY (lock_id, acquired_at)
+COMMENT 'Table used to keep concurrent processes from applying migrations'`
+)
+
+// TableLocker is a Locker backed by the housekeeper.migration_lock table, which keeps
+// processes running housekeeper against the same server (e.g. two CI runners) from
+// applying migrations at the same time.
+//
+// ClickHouse has no row locks, so acquiring the lock is a claim and check: the locker
+// fails when a live claim already exists, otherwise it inserts its own claim and then
+// verifies it's the oldest live one. Concurrent claims all agree on the oldest, so only
+// one process wins, and the others withdraw their claims. A claim is released once the
+// migrations are done, and claims older than the TTL are ignored so that a crashed
+// process doesn't block migrations forever. The TTL must therefore be longer than the
+// longest expected run.
+type TableLocker struct {
+	executor *Executor
+	ttl      time.Duration
+	holder   string
+}
+
+// NewTableLocker returns a TableLocker that honors claims for ttl, or DefaultLockTTL
+// when ttl isn't positive. The lock table is created, along with the rest of the
+// housekeeper infrastructure, the first time the lock is acquired.
+//
+// Example usage:
+//
+//	report, err := exec.Apply(ctx, migrationDir, executor.ApplyOptions{
+//		Locker: exec.NewTableLocker(30 * time.Minute),
+//	})
+//
+//	var locked *migrator.ErrMigrationLocked
+//	if errors.As(err, &locked) {
+//		log.Fatalf("migrations already running: %v", err)
+//	}
+func (e *Executor) NewTableLocker(ttl time.Duration) *TableLocker {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &TableLocker{
+		executor: e,
+		ttl:      ttl,
+		holder:   fmt.Sprintf("%s (pid %d)", host, os.Getpid()),
+	}
+}
+
+// Lock implements Locker. It returns a *migrator.ErrMigrationLocked naming the holder
+// when another process holds a live claim on the lock. Nothing is written to the server
+// in dry run mode, so no lock is taken.
+func (l *TableLocker) Lock(ctx context.Context) (func(context.Context) error, error) {
+	if l.executor.dryRun {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if err := l.executor.ensureBootstrap(ctx); err != nil {
+		return nil, err
+	}
+
+	// Servers bootstrapped before migration locking was added don't have the lock table
+	if err := l.executor.ch.Exec(ctx, migrationLockTableSQL); err != nil {
+		return nil, errors.Wrap(err, "failed to create migration lock table")
+	}
+
+	if claim, err := l.oldestClaim(ctx); err != nil {
+		return nil, err
+	} else if claim != nil {
+		return nil, claim.locked()
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	insertSQL := `INSERT INTO housekeeper.migration_lock (lock_id, token, holder) VALUES (?, ?, ?)`
+	if err := l.executor.ch.Exec(ctx, insertSQL, migrationLockID, token, l.holder); err != nil {
+		return nil, errors.Wrap(err, "failed to claim migration lock")
+	}
+
+	unlock := func(ctx context.Context) error {
+		deleteSQL := `DELETE FROM housekeeper.migration_lock WHERE token = ?`
+		return errors.Wrap(l.executor.ch.Exec(ctx, deleteSQL, token), "failed to delete migration lock claim")
+	}
+
+	// Another process may have claimed the lock between the check and the insert, in
+	// which case only the oldest claim wins
+	claim, err := l.oldestClaim(ctx)
+	if err != nil || claim == nil || claim.token != token {
+		if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+			err = unlockErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		if claim == nil {
+			return nil, errors.New("migration lock claim disappeared before it could be verified")
+		}
+		return nil, claim.locked()
+	}
+
+	return unlock, nil
+}
+
+// lockClaim is a row of the housekeeper.migration_lock table
+type lockClaim struct {
+	token      string
+	holder     string
+	acquiredAt time.Time
+}
+
+// locked returns the error reported when the claim is held by another process
+func (c *lockClaim) locked() error {
+	return &migrator.ErrMigrationLocked{
+		Holder: fmt.Sprintf("%s since %s", c.holder, c.acquiredAt.UTC().Format(time.RFC3339)),
+	}
+}
+
+// oldestClaim returns the oldest claim on the lock that hasn't outlived the TTL, or nil
+// when the lock is free
+func (l *TableLocker) oldestClaim(ctx context.Context) (*lockClaim, error) {
+	query := `
+		SELECT token, holder, acquired_at
+		FROM housekeeper.migration_lock
+		WHERE lock_id = ? AND acquired_at > now64(6, 'UTC') - toIntervalMillisecond(?)
+		ORDER BY acquired_at, token
+		LIMIT 1
+	`
+
+	rows, err := l.executor.ch.Query(ctx, query, migrationLockID, l.ttl.Milliseconds())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query migration lock")
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, errors.Wrap(rows.Err(), "failed to read migration lock")
+	}
+
+	var claim lockClaim
+	if err := rows.Scan(&claim.token, &claim.holder, &claim.acquiredAt); err != nil {
+		return nil, errors.Wrap(err, "failed to scan migration lock")
+	}
+
+	return &claim, nil
+}
+
+// newLockToken returns a random token identifying a single claim of the lock
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate migration lock token")
+	}
+	return hex.EncodeToString(b), nil
+}
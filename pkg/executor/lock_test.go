@@ -0,0 +1,272 @@
+package executor_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+// lockClaim is a row of the fake housekeeper.migration_lock table
+type lockClaim struct {
+	token      string
+	holder     string
+	acquiredAt time.Time
+}
+
+// lockServer is a ClickHouse server with an in-memory housekeeper.migration_lock table,
+// shared by concurrent executors. Every other query reports existing infrastructure.
+type lockServer struct {
+	mu     sync.Mutex
+	claims []lockClaim
+	now    time.Time
+	execs  []string
+}
+
+func (s *lockServer) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM housekeeper.migration_lock") {
+		return &mockRows{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ttl := time.Duration(args[1].(int64)) * time.Millisecond
+	var live []lockClaim
+	for _, claim := range s.claims {
+		if claim.acquiredAt.After(time.Now().Add(-ttl)) {
+			live = append(live, claim)
+		}
+	}
+	slices.SortFunc(live, func(a, b lockClaim) int {
+		if c := a.acquiredAt.Compare(b.acquiredAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.token, b.token)
+	})
+
+	return &lockClaimRows{claims: live[:min(len(live), 1)]}, nil
+}
+
+func (s *lockServer) Exec(ctx context.Context, query string, args ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.execs = append(s.execs, query)
+	switch {
+	case strings.HasPrefix(query, "INSERT INTO housekeeper.migration_lock"):
+		// The server timestamps claims, so every claim is strictly newer than the last
+		s.now = s.now.Add(time.Microsecond)
+		if now := time.Now(); now.After(s.now) {
+			s.now = now
+		}
+		s.claims = append(s.claims, lockClaim{token: args[1].(string), holder: args[2].(string), acquiredAt: s.now})
+	case strings.HasPrefix(query, "DELETE FROM housekeeper.migration_lock"):
+		s.claims = slices.DeleteFunc(s.claims, func(claim lockClaim) bool { return claim.token == args[0] })
+	}
+
+	return nil
+}
+
+func (s *lockServer) holders() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	holders := make([]string, len(s.claims))
+	for i, claim := range s.claims {
+		holders[i] = claim.holder
+	}
+	return holders
+}
+
+// lockClaimRows returns each of its claims as a row of housekeeper.migration_lock
+type lockClaimRows struct {
+	mockRows
+	claims  []lockClaim
+	current int
+}
+
+func (m *lockClaimRows) Next() bool {
+	m.current++
+	return m.current <= len(m.claims)
+}
+
+func (m *lockClaimRows) Scan(dest ...any) error {
+	claim := m.claims[m.current-1]
+	*dest[0].(*string) = claim.token
+	*dest[1].(*string) = claim.holder
+	*dest[2].(*time.Time) = claim.acquiredAt
+	return nil
+}
+
+func TestTableLocker(t *testing.T) {
+	newExecutor := func(ch executor.ClickHouse) *executor.Executor {
+		return executor.New(executor.Config{
+			ClickHouse: ch,
+			Formatter:  format.New(format.Defaults),
+		})
+	}
+
+	t.Run("concurrent executors", func(t *testing.T) {
+		server := &lockServer{}
+
+		const runners = 10
+		var (
+			wg      sync.WaitGroup
+			start   = make(chan struct{})
+			unlocks = make(chan func(context.Context) error, runners)
+			errs    = make(chan error, runners)
+		)
+		for range runners {
+			locker := newExecutor(server).NewTableLocker(time.Minute)
+			wg.Go(func() {
+				<-start
+				unlock, err := locker.Lock(context.Background())
+				if err != nil {
+					errs <- err
+					return
+				}
+				unlocks <- unlock
+			})
+		}
+
+		close(start)
+		wg.Wait()
+		close(unlocks)
+		close(errs)
+
+		// Exactly one runner holds the lock and every other one was turned away
+		require.Len(t, unlocks, 1)
+		require.Len(t, errs, runners-1)
+		for err := range errs {
+			var locked *migrator.ErrMigrationLocked
+			require.ErrorAs(t, err, &locked)
+			require.Contains(t, locked.Holder, "(pid ")
+		}
+		require.Len(t, server.holders(), 1, "losing claims must be withdrawn")
+
+		// Once released, the lock can be acquired again
+		require.NoError(t, (<-unlocks)(context.Background()))
+		require.Empty(t, server.holders())
+
+		unlock, err := newExecutor(server).NewTableLocker(time.Minute).Lock(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, unlock(context.Background()))
+	})
+
+	t.Run("held lock", func(t *testing.T) {
+		server := &lockServer{claims: []lockClaim{
+			{token: "abc", holder: "ci-runner-1 (pid 42)", acquiredAt: time.Now().Add(-5 * time.Minute)},
+		}}
+
+		_, err := newExecutor(server).NewTableLocker(10 * time.Minute).Lock(context.Background())
+
+		var locked *migrator.ErrMigrationLocked
+		require.ErrorAs(t, err, &locked)
+		require.ErrorContains(t, err, "migrations are locked by ci-runner-1 (pid 42) since ")
+		require.Equal(t, []string{"ci-runner-1 (pid 42)"}, server.holders())
+	})
+
+	t.Run("stale lock", func(t *testing.T) {
+		server := &lockServer{claims: []lockClaim{
+			{token: "abc", holder: "ci-runner-1 (pid 42)", acquiredAt: time.Now().Add(-time.Hour)},
+		}}
+
+		unlock, err := newExecutor(server).NewTableLocker(10 * time.Minute).Lock(context.Background())
+		require.NoError(t, err)
+		require.Len(t, server.holders(), 2)
+
+		require.NoError(t, unlock(context.Background()))
+		require.Equal(t, []string{"ci-runner-1 (pid 42)"}, server.holders())
+	})
+
+	t.Run("creates lock table", func(t *testing.T) {
+		// Servers bootstrapped before locking was added get the lock table too
+		server := &lockServer{}
+
+		unlock, err := newExecutor(server).NewTableLocker(0).Lock(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, unlock(context.Background()))
+		require.True(t, slices.ContainsFunc(server.execs, func(sql string) bool {
+			return strings.HasPrefix(sql, "CREATE TABLE IF NOT EXISTS housekeeper.migration_lock")
+		}))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		exec := executor.New(executor.Config{
+			ClickHouse: mockCH,
+			Formatter:  format.New(format.Defaults),
+			DryRun:     true,
+		})
+
+		unlock, err := exec.NewTableLocker(time.Minute).Lock(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, unlock(context.Background()))
+		require.Empty(t, mockCH.queries)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				if strings.Contains(query, "migration_lock") {
+					return nil, errors.New("connection reset")
+				}
+				return &mockRows{}, nil
+			},
+		}
+
+		_, err := newExecutor(mockCH).NewTableLocker(time.Minute).Lock(context.Background())
+		require.ErrorContains(t, err, "failed to query migration lock: connection reset")
+	})
+}
+
+func TestTableLocker_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	testutil.SkipIfNoDocker(t)
+
+	ctx := context.Background()
+	_, dsn := testutil.StartClickHouseContainer(t, "")
+
+	newLocker := func(t *testing.T) *executor.TableLocker {
+		t.Helper()
+
+		client, err := clickhouse.NewClient(ctx, dsn)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = client.Close() })
+
+		return executor.New(executor.Config{
+			ClickHouse: client,
+			Formatter:  format.New(format.Defaults),
+		}).NewTableLocker(time.Minute)
+	}
+
+	first, second := newLocker(t), newLocker(t)
+
+	unlock, err := first.Lock(ctx)
+	require.NoError(t, err)
+
+	_, err = second.Lock(ctx)
+	var locked *migrator.ErrMigrationLocked
+	require.ErrorAs(t, err, &locked)
+
+	require.NoError(t, unlock(ctx))
+
+	unlock, err = second.Lock(ctx)
+	require.NoError(t, err)
+	require.NoError(t, unlock(ctx))
+}
@@ -0,0 +1,208 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+)
+
+// Rollback reverts the last n applied migrations by executing their down statements
+// (see migrator.Migration.DownStatements), newest first.
+//
+// The applied migrations are read from housekeeper.revisions, and migrations supplies
+// the down statements for each of them. Each rolled back migration is recorded with a
+// migrator.RollbackRevision, after which it's pending again and will be re-applied by
+// the next call to Execute.
+//
+// Before anything is executed, Rollback checks that all n migrations can be rolled
+// back. It refuses to roll back past a snapshot, since the migrations it consolidates
+// can't be reverted individually, and it fails if any of the migrations has no down
+// section or isn't in migrations. No-op migrations (see migrator.Migration.IsNoOp) are
+// rolled back without a down section. Execution stops at the first migration whose down
+// statements fail. That migration is left partially rolled back and must be repaired
+// by hand.
+//
+// In dry run mode (see Config.DryRun) the down statements are reported with
+// StatusDryRun instead of being executed.
+//
+// Example usage:
+//
+//	results, err := executor.Rollback(ctx, migrationDir.Migrations, 2)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	for _, result := range results {
+//		fmt.Printf("Rolled back %s: %s\n", result.Version, result.Status)
+//	}
+func (e *Executor) Rollback(ctx context.Context, migrations []*migrator.Migration, n int) ([]*ExecutionResult, error) {
+	if n < 1 {
+		return nil, errors.Errorf("invalid rollback count %d: must roll back at least one migration", n)
+	}
+	if e.skipRevisions {
+		return nil, errors.New("cannot roll back migrations when revision tracking is skipped")
+	}
+
+	bootstrapped, err := e.IsBootstrapped(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionSet := migrator.NewRevisionSet(nil)
+	if bootstrapped {
+		if revisionSet, err = migrator.LoadRevisions(ctx, e.ch); err != nil {
+			return nil, errors.Wrap(err, "failed to load existing revisions")
+		}
+	}
+
+	targets, err := rollbackTargets(revisionSet, migrations, n)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ExecutionResult, 0, len(targets))
+	for _, migration := range targets {
+		result := e.rollbackMigration(ctx, migration)
+		results = append(results, result)
+
+		// Stop rolling back on first failure
+		if result.Status == StatusFailed {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// rollbackTargets returns the last n applied migrations, newest first. Only migrations
+// applied after the last snapshot can be rolled back.
+func rollbackTargets(revisionSet *migrator.RevisionSet, migrations []*migrator.Migration, n int) ([]*migrator.Migration, error) {
+	applied := revisionSet.GetMigrationsAfterSnapshot()
+	slices.Reverse(applied)
+
+	if n > len(applied) {
+		if snapshot := revisionSet.GetLastSnapshot(); snapshot != nil {
+			return nil, errors.Errorf(
+				"cannot roll back %d migration(s) past snapshot %s: rolling back would affect %s",
+				n, snapshot.Version, strings.Join(append(applied, snapshot.Version), ", "),
+			)
+		}
+
+		if len(applied) == 0 {
+			return nil, errors.Errorf("cannot roll back %d migration(s): no migrations have been applied", n)
+		}
+		return nil, errors.Errorf(
+			"cannot roll back %d migration(s): only %d applied (%s)",
+			n, len(applied), strings.Join(applied, ", "),
+		)
+	}
+
+	byVersion := make(map[string]*migrator.Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	targets := make([]*migrator.Migration, 0, n)
+	for _, version := range applied[:n] {
+		migration, ok := byVersion[version]
+		if !ok {
+			return nil, errors.Errorf("cannot roll back %s: migration file not found", version)
+		}
+		// A no-op migration has nothing to revert, so it doesn't need a down section
+		if len(migration.DownStatements) == 0 && !migration.IsNoOp() {
+			return nil, errors.Errorf("cannot roll back %s: migration has no down section", version)
+		}
+		targets = append(targets, migration)
+	}
+
+	return targets, nil
+}
+
+// rollbackMigration executes the down statements of a single migration and records
+// the rollback revision.
+func (e *Executor) rollbackMigration(ctx context.Context, migration *migrator.Migration) *ExecutionResult {
+	// The down statements are run like the statements of a migration with the same version
+	down := &migrator.Migration{
+		Version:    migration.Version,
+		Statements: migration.DownStatements,
+	}
+
+	if e.dryRun {
+		return e.planMigration(down, migrator.NewRevisionSet(nil))
+	}
+
+	startTime := time.Now()
+	statementsApplied, statementTimes, executionError := e.applyStatements(ctx, down, 0, nil)
+	executionTime := time.Since(startTime)
+
+	migrationHash, partialHashes := e.ComputeHashes(down)
+	revision := &migrator.Revision{
+		Version:            migration.Version,
+		ExecutedAt:         startTime,
+		ExecutionTime:      executionTime,
+		Kind:               migrator.RollbackRevision,
+		Applied:            statementsApplied,
+		Total:              len(down.Statements),
+		Hash:               migrationHash,
+		PartialHashes:      partialHashes,
+		StatementTimes:     statementTimes,
+		HousekeeperVersion: e.housekeeperVersion,
+	}
+
+	if executionError != nil {
+		errorStr := executionError.Error()
+		revision.Error = &errorStr
+	}
+
+	// Without its revision the migration would still appear to be applied, so a failure
+	// to save it fails the rollback
+	if err := e.saveRevision(ctx, revision); err != nil && executionError == nil {
+		executionError = errors.Wrap(err, "failed to save rollback revision")
+	}
+
+	status := StatusSuccess
+	if executionError != nil {
+		status = StatusFailed
+	}
+
+	return &ExecutionResult{
+		Version:           migration.Version,
+		Status:            status,
+		Error:             executionError,
+		ExecutionTime:     executionTime,
+		StatementsApplied: statementsApplied,
+		TotalStatements:   len(down.Statements),
+		Revision:          revision,
+	}
+}
+
+// DiscardPartial records that the progress of a partially applied migration no longer
+// applies, e.g. because the migration was regenerated under the same version to contain
+// only the statements that are still needed. It's recorded as a migrator.RollbackRevision
+// without statements, so the migration is pending again and the next call to Execute
+// applies it from its first statement instead of failing to resume it.
+//
+// Example usage:
+//
+//	if revisionSet.IsPartiallyApplied(migration) {
+//		if err := executor.DiscardPartial(ctx, migration.Version); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func (e *Executor) DiscardPartial(ctx context.Context, version string) error {
+	if e.skipRevisions {
+		return errors.New("cannot discard partial migrations when revision tracking is skipped")
+	}
+
+	err := e.saveRevision(ctx, &migrator.Revision{
+		Version:            version,
+		ExecutedAt:         time.Now(),
+		Kind:               migrator.RollbackRevision,
+		HousekeeperVersion: e.housekeeperVersion,
+	})
+	return errors.Wrapf(err, "failed to discard partial migration: %s", version)
+}
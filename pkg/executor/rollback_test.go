@@ -0,0 +1,410 @@
+package executor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRevisionRows returns each of its revisions as a row of housekeeper.revisions
+type mockRevisionRows struct {
+	mockRows
+	revisions []*migrator.Revision
+	current   int
+}
+
+func (m *mockRevisionRows) Next() bool {
+	m.current++
+	return m.current <= len(m.revisions)
+}
+
+func (m *mockRevisionRows) Scan(dest ...any) error {
+	return (&mockResumeRows{revision: m.revisions[m.current-1]}).Scan(dest...)
+}
+
+func TestExecutor_Rollback(t *testing.T) {
+	loadMigrations := func(t *testing.T, files fstest.MapFS) []*migrator.Migration {
+		t.Helper()
+
+		migrationDir, err := migrator.LoadMigrationDir(files)
+		require.NoError(t, err)
+		return migrationDir.Migrations
+	}
+
+	migrations := loadMigrations(t, fstest.MapFS{
+		"001_init.sql": &fstest.MapFile{
+			Data: []byte("CREATE DATABASE analytics ENGINE = Atomic;\n-- housekeeper:down\nDROP DATABASE analytics;"),
+		},
+		"002_events.sql": &fstest.MapFile{
+			Data: []byte("CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;\n-- housekeeper:down\nDROP TABLE analytics.events;"),
+		},
+		"003_users.sql": &fstest.MapFile{
+			Data: []byte("CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;\n-- housekeeper:down\nDROP TABLE analytics.users;"),
+		},
+	})
+
+	applied := func(version string) *migrator.Revision {
+		return &migrator.Revision{Version: version, Kind: migrator.StandardRevision, Applied: 1, Total: 1}
+	}
+
+	// newExecutor returns an executor for a bootstrapped server with the given revisions
+	newExecutor := func(mockCH *mockClickHouse, dryRun bool, revisions ...*migrator.Revision) *executor.Executor {
+		mockCH.queryFunc = func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			if strings.Contains(query, "FROM housekeeper.revisions") {
+				return &mockRevisionRows{revisions: revisions}, nil
+			}
+			return &mockRows{}, nil
+		}
+
+		return executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "1.0.0",
+			DryRun:             dryRun,
+		})
+	}
+
+	t.Run("rolls back the last n migrations newest first", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		var saved [][]any
+		mockCH.execFunc = func(ctx context.Context, query string, args ...any) error {
+			if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+				saved = append(saved, args)
+			}
+			return nil
+		}
+		exec := newExecutor(mockCH, false, applied("001_init"), applied("002_events"), applied("003_users"))
+
+		results, err := exec.Rollback(context.Background(), migrations, 2)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, "003_users", results[0].Version)
+		require.Equal(t, "002_events", results[1].Version)
+
+		for _, result := range results {
+			require.Equal(t, executor.StatusSuccess, result.Status)
+			require.Equal(t, 1, result.StatementsApplied)
+			require.Equal(t, migrator.RollbackRevision, result.Revision.Kind)
+		}
+
+		require.Len(t, mockCH.execs, 4)
+		require.Contains(t, mockCH.execs[0], "DROP TABLE `analytics`.`users`")
+		require.Contains(t, mockCH.execs[2], "DROP TABLE `analytics`.`events`")
+
+		// Each rollback is recorded as a rollback revision of the migration
+		require.Len(t, saved, 2)
+		require.Equal(t, "003_users", saved[0][0])
+		require.Equal(t, "rollback", saved[0][3])
+		require.Equal(t, "002_events", saved[1][0])
+		require.Equal(t, "rollback", saved[1][3])
+	})
+
+	t.Run("rolled back migrations are applied again", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		rollback := &migrator.Revision{Version: "003_users", Kind: migrator.RollbackRevision, Applied: 1, Total: 1}
+		exec := newExecutor(mockCH, false, applied("001_init"), applied("002_events"), applied("003_users"), rollback)
+
+		results, err := exec.Execute(context.Background(), migrations)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.Equal(t, executor.StatusSkipped, results[0].Status)
+		require.Equal(t, executor.StatusSkipped, results[1].Status)
+		require.Equal(t, executor.StatusSuccess, results[2].Status)
+
+		// Rolling back again only reaches the migrations that are still applied
+		results, err = exec.Rollback(context.Background(), migrations, 1)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "002_events", results[0].Version)
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		var saved [][]any
+		mockCH.execFunc = func(ctx context.Context, query string, args ...any) error {
+			if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+				saved = append(saved, args)
+				return nil
+			}
+			if strings.Contains(query, "users") {
+				return errors.New("table is in use")
+			}
+			return nil
+		}
+		exec := newExecutor(mockCH, false, applied("001_init"), applied("002_events"), applied("003_users"))
+
+		results, err := exec.Rollback(context.Background(), migrations, 2)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusFailed, results[0].Status)
+		require.ErrorContains(t, results[0].Error, "table is in use")
+		require.Equal(t, 0, results[0].StatementsApplied)
+
+		// The failed attempt is still recorded
+		require.Len(t, saved, 1)
+		require.Equal(t, "rollback", saved[0][3])
+		require.NotNil(t, saved[0][4])
+	})
+
+	t.Run("fails when the revision can't be saved", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		mockCH.execFunc = func(ctx context.Context, query string, args ...any) error {
+			if strings.Contains(query, "INSERT INTO housekeeper.revisions") {
+				return errors.New("connection reset")
+			}
+			return nil
+		}
+		exec := newExecutor(mockCH, false, applied("001_init"), applied("002_events"), applied("003_users"))
+
+		results, err := exec.Rollback(context.Background(), migrations, 2)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, executor.StatusFailed, results[0].Status)
+		require.ErrorContains(t, results[0].Error, "failed to save rollback revision")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		exec := newExecutor(mockCH, true, applied("001_init"), applied("002_events"), applied("003_users"))
+
+		results, err := exec.Rollback(context.Background(), migrations, 2)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, executor.StatusDryRun, results[0].Status)
+		require.Equal(t, []string{"DROP TABLE `analytics`.`users`;"}, results[0].Statements)
+		require.Equal(t, executor.StatusDryRun, results[1].Status)
+		require.Equal(t, []string{"DROP TABLE `analytics`.`events`;"}, results[1].Statements)
+		require.Empty(t, mockCH.execs)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		snapshot := &migrator.Revision{Version: "001_init", Kind: migrator.SnapshotRevision, Applied: 1, Total: 1}
+		withoutDown := loadMigrations(t, fstest.MapFS{
+			"001_init.sql":   &fstest.MapFile{Data: []byte("CREATE DATABASE analytics ENGINE = Atomic;")},
+			"002_events.sql": &fstest.MapFile{Data: []byte("CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+		})
+
+		tests := map[string]struct {
+			migrations []*migrator.Migration
+			revisions  []*migrator.Revision
+			n          int
+			err        string
+		}{
+			"invalid count": {
+				migrations: migrations,
+				revisions:  []*migrator.Revision{applied("001_init")},
+				n:          0,
+				err:        "invalid rollback count 0",
+			},
+			"past a snapshot": {
+				migrations: migrations,
+				revisions:  []*migrator.Revision{snapshot, applied("002_events"), applied("003_users")},
+				n:          3,
+				err:        "cannot roll back 3 migration(s) past snapshot 001_init: rolling back would affect 003_users, 002_events, 001_init",
+			},
+			"more than applied": {
+				migrations: migrations,
+				revisions:  []*migrator.Revision{applied("001_init"), applied("002_events")},
+				n:          3,
+				err:        "cannot roll back 3 migration(s): only 2 applied (002_events, 001_init)",
+			},
+			"nothing applied": {
+				migrations: migrations,
+				n:          1,
+				err:        "no migrations have been applied",
+			},
+			"failed migrations aren't applied": {
+				migrations: migrations,
+				revisions: []*migrator.Revision{
+					applied("001_init"),
+					{Version: "002_events", Kind: migrator.StandardRevision, Error: stringPtr("boom"), Applied: 0, Total: 1},
+				},
+				n:   2,
+				err: "only 1 applied (001_init)",
+			},
+			"no down section": {
+				migrations: withoutDown,
+				revisions:  []*migrator.Revision{applied("001_init"), applied("002_events")},
+				n:          1,
+				err:        "cannot roll back 002_events: migration has no down section",
+			},
+			"missing migration file": {
+				migrations: migrations[:2],
+				revisions:  []*migrator.Revision{applied("001_init"), applied("002_events"), applied("003_users")},
+				n:          1,
+				err:        "cannot roll back 003_users: migration file not found",
+			},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				mockCH := &mockClickHouse{}
+				exec := newExecutor(mockCH, false, tt.revisions...)
+
+				results, err := exec.Rollback(context.Background(), tt.migrations, tt.n)
+				require.ErrorContains(t, err, tt.err)
+				require.Nil(t, results)
+				require.Empty(t, mockCH.execs, "nothing should be executed")
+			})
+		}
+	})
+}
+
+func TestExecutor_DiscardPartial(t *testing.T) {
+	migrationDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+		"001_users.sql": &fstest.MapFile{Data: []byte("ALTER TABLE analytics.users ADD COLUMN ts DateTime;")},
+	})
+	require.NoError(t, err)
+
+	// The migration was regenerated after its first statement of two was applied by hand
+	failed := "statement 2 failed"
+	partial := &migrator.Revision{Version: "001_users", Kind: migrator.StandardRevision, Applied: 1, Total: 2, Error: &failed}
+
+	newExecutor := func(mockCH *mockClickHouse, revisions ...*migrator.Revision) *executor.Executor {
+		mockCH.queryFunc = func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+			if strings.Contains(query, "FROM housekeeper.revisions") {
+				return &mockRevisionRows{revisions: revisions}, nil
+			}
+			return &mockRows{}, nil
+		}
+
+		return executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "1.0.0",
+		})
+	}
+
+	t.Run("records a rollback revision", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		var saved [][]any
+		mockCH.execFunc = func(ctx context.Context, query string, args ...any) error {
+			saved = append(saved, args)
+			return nil
+		}
+
+		require.NoError(t, newExecutor(mockCH, partial).DiscardPartial(context.Background(), "001_users"))
+		require.Len(t, saved, 1)
+		require.Equal(t, "001_users", saved[0][0])
+		require.Equal(t, "rollback", saved[0][3])
+		require.Nil(t, saved[0][4])
+		require.Equal(t, 0, saved[0][5])
+	})
+
+	t.Run("regenerated migration is applied from the start", func(t *testing.T) {
+		results, err := newExecutor(&mockClickHouse{}, partial).Execute(context.Background(), migrationDir.Migrations)
+		require.NoError(t, err)
+		require.Equal(t, executor.StatusFailed, results[0].Status)
+		require.ErrorContains(t, results[0].Error, "partial revision validation failed")
+
+		discarded := &migrator.Revision{Version: "001_users", Kind: migrator.RollbackRevision}
+		mockCH := &mockClickHouse{}
+		results, err = newExecutor(mockCH, partial, discarded).Execute(context.Background(), migrationDir.Migrations)
+		require.NoError(t, err)
+		require.Equal(t, executor.StatusSuccess, results[0].Status)
+		require.Equal(t, 1, results[0].StatementsApplied)
+		require.Contains(t, mockCH.execs, "ALTER TABLE `analytics`.`users`\n    ADD COLUMN `ts` DateTime;")
+	})
+
+	t.Run("fails when the revision can't be saved", func(t *testing.T) {
+		mockCH := &mockClickHouse{execFunc: func(ctx context.Context, query string, args ...any) error {
+			return errors.New("connection reset")
+		}}
+
+		err := newExecutor(mockCH, partial).DiscardPartial(context.Background(), "001_users")
+		require.EqualError(t, err, "failed to discard partial migration: 001_users: connection reset")
+	})
+}
+
+func TestExecutor_Rollback_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	testutil.SkipIfNoDocker(t)
+
+	ctx := context.Background()
+	_, dsn := testutil.StartClickHouseContainer(t, "")
+
+	client, err := clickhouse.NewClient(ctx, dsn)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	migrationDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+		"20240101120000_init.sql": &fstest.MapFile{Data: []byte(`
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+
+-- housekeeper:down
+DROP TABLE analytics.events;
+DROP DATABASE analytics;
+`)},
+		"20240102120000_users.sql": &fstest.MapFile{Data: []byte(`
+CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;
+ALTER TABLE analytics.events ADD COLUMN user_id UInt64;
+
+-- housekeeper:down
+ALTER TABLE analytics.events DROP COLUMN user_id;
+DROP TABLE analytics.users;
+`)},
+	})
+	require.NoError(t, err)
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         client,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "test-1.0.0",
+	})
+
+	dumpSchema := func(t *testing.T) string {
+		t.Helper()
+
+		sql, err := client.GetSchema(ctx)
+		require.NoError(t, err)
+
+		var buf strings.Builder
+		require.NoError(t, format.FormatSQL(&buf, format.Defaults, sql))
+		return buf.String()
+	}
+
+	// Apply the first migration and capture the schema it produces
+	results, err := exec.Execute(ctx, migrationDir.Migrations[:1])
+	require.NoError(t, err)
+	require.Equal(t, executor.StatusSuccess, results[0].Status)
+	before := dumpSchema(t)
+
+	results, err = exec.Execute(ctx, migrationDir.Migrations)
+	require.NoError(t, err)
+	require.Equal(t, executor.StatusSuccess, results[1].Status)
+	require.NotEqual(t, before, dumpSchema(t))
+
+	// Revisions are ordered by execution time within a version, so wait for a later timestamp
+	time.Sleep(10 * time.Millisecond)
+
+	results, err = exec.Rollback(ctx, migrationDir.Migrations, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, executor.StatusSuccess, results[0].Status, "rollback failed: %v", results[0].Error)
+	require.Equal(t, before, dumpSchema(t))
+
+	// The rolled back migration is pending again and can be re-applied
+	revisions, err := migrator.LoadRevisions(ctx, client)
+	require.NoError(t, err)
+	require.True(t, revisions.IsPending(migrationDir.Migrations[1]))
+	require.True(t, revisions.IsCompleted(migrationDir.Migrations[0]))
+
+	results, err = exec.Execute(ctx, migrationDir.Migrations)
+	require.NoError(t, err)
+	require.Equal(t, executor.StatusSkipped, results[0].Status)
+	require.Equal(t, executor.StatusSuccess, results[1].Status)
+}
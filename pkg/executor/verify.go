@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+)
+
+// VerifyReversible checks that a diff's down statements undo its up statements. It
+// snapshots the server's schema, applies diff.Up followed by diff.Down, and compares the
+// resulting schema against the snapshot. It returns false when the schemas differ, which
+// means rolling the migration back wouldn't restore the original schema.
+//
+// Both directions are applied to the server the executor is connected to and nothing is
+// recorded in the revisions table, so this must only be used against a disposable
+// instance, e.g. a Docker container.
+//
+// Example usage:
+//
+//	diff, err := schema.GenerateReversibleDiff(current, target, schema.CompareOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	reversible, err := exec.VerifyReversible(ctx, client, diff)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if !reversible {
+//		fmt.Println("down migration doesn't restore the original schema")
+//	}
+func (e *Executor) VerifyReversible(ctx context.Context, reader SchemaReader, diff *schema.Diff) (bool, error) {
+	if diff == nil || diff.Up == nil {
+		return false, errors.New("no up migration to verify")
+	}
+
+	before, err := reader.GetSchema(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read schema before up migration")
+	}
+
+	if err := e.applyDiffStatements(ctx, "up", diff.Up); err != nil {
+		return false, err
+	}
+
+	if diff.Down != nil {
+		if err := e.applyDiffStatements(ctx, "down", diff.Down); err != nil {
+			return false, err
+		}
+	}
+
+	after, err := reader.GetSchema(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read schema after down migration")
+	}
+
+	if _, err := schema.GenerateDiff(after, before); err != nil {
+		if errors.Is(err, schema.ErrNoDiff) {
+			return true, nil
+		}
+		return false, errors.Wrap(err, "failed to compare schemas")
+	}
+
+	return false, nil
+}
+
+// applyDiffStatements executes the statements of one direction of a diff
+func (e *Executor) applyDiffStatements(ctx context.Context, direction string, sql *parser.SQL) error {
+	_, _, err := e.applyStatements(ctx, &migrator.Migration{Version: direction, Statements: sql.Statements}, 0, nil)
+	return errors.Wrapf(err, "failed to apply %s migration", direction)
+}
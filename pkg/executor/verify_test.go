@@ -0,0 +1,127 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/clickhouse"
+	"github.com/pseudomuto/housekeeper/pkg/cmd/testutil"
+	"github.com/pseudomuto/housekeeper/pkg/executor"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_VerifyReversible(t *testing.T) {
+	const (
+		original = `CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;`
+		migrated = `CREATE TABLE analytics.events (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY id;`
+	)
+
+	diff := &schema.Diff{
+		Up:   mustParse(t, "ALTER TABLE analytics.events ADD COLUMN ts DateTime;"),
+		Down: mustParse(t, "ALTER TABLE analytics.events DROP COLUMN ts;"),
+	}
+
+	newExecutor := func(mockCH *mockClickHouse) *executor.Executor {
+		return executor.New(executor.Config{
+			ClickHouse:         mockCH,
+			Formatter:          format.New(format.Defaults),
+			HousekeeperVersion: "1.0.0",
+		})
+	}
+
+	t.Run("reversible", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		reader := &mockSchemaReader{schemas: []string{original, original}}
+
+		reversible, err := newExecutor(mockCH).VerifyReversible(context.Background(), reader, diff)
+		require.NoError(t, err)
+		require.True(t, reversible)
+		require.Equal(t, []string{
+			"ALTER TABLE `analytics`.`events`\n    ADD COLUMN `ts` DateTime;",
+			"ALTER TABLE `analytics`.`events`\n    DROP COLUMN `ts`;",
+		}, mockCH.execs)
+	})
+
+	t.Run("not reversible", func(t *testing.T) {
+		reader := &mockSchemaReader{schemas: []string{original, migrated}}
+
+		reversible, err := newExecutor(&mockClickHouse{}).VerifyReversible(context.Background(), reader, diff)
+		require.NoError(t, err)
+		require.False(t, reversible)
+	})
+
+	t.Run("failed down migration", func(t *testing.T) {
+		mockCH := &mockClickHouse{}
+		mockCH.execFunc = func(ctx context.Context, query string, args ...any) error {
+			if len(mockCH.execs) == 2 {
+				return errors.New("column is referenced")
+			}
+			return nil
+		}
+		reader := &mockSchemaReader{schemas: []string{original}}
+
+		_, err := newExecutor(mockCH).VerifyReversible(context.Background(), reader, diff)
+		require.ErrorContains(t, err, "failed to apply down migration")
+		require.ErrorContains(t, err, "column is referenced")
+	})
+
+	t.Run("missing up migration", func(t *testing.T) {
+		_, err := newExecutor(&mockClickHouse{}).VerifyReversible(context.Background(), &mockSchemaReader{}, &schema.Diff{})
+		require.EqualError(t, err, "no up migration to verify")
+	})
+}
+
+func TestExecutor_VerifyReversible_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	testutil.SkipIfNoDocker(t)
+
+	ctx := context.Background()
+	_, dsn := testutil.StartClickHouseContainer(t, "")
+
+	client, err := clickhouse.NewClient(ctx, dsn)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.Exec(ctx, "CREATE DATABASE analytics ENGINE = Atomic"))
+	require.NoError(t, client.Exec(ctx, "CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id"))
+
+	exec := executor.New(executor.Config{
+		ClickHouse:         client,
+		Formatter:          format.New(format.Defaults),
+		HousekeeperVersion: "test-1.0.0",
+	})
+
+	t.Run("reversible", func(t *testing.T) {
+		current, err := client.GetSchema(ctx)
+		require.NoError(t, err)
+
+		target := mustParse(t, `
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.events (id UInt64, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+`)
+
+		diff, err := schema.GenerateReversibleDiff(current, target, schema.CompareOptions{})
+		require.NoError(t, err)
+
+		reversible, err := exec.VerifyReversible(ctx, client, diff)
+		require.NoError(t, err)
+		require.True(t, reversible)
+	})
+
+	t.Run("not reversible", func(t *testing.T) {
+		// The down migration restores the column with a different type than it started with
+		diff := &schema.Diff{
+			Up:   mustParse(t, "ALTER TABLE analytics.events MODIFY COLUMN name LowCardinality(String);"),
+			Down: mustParse(t, "ALTER TABLE analytics.events MODIFY COLUMN name Nullable(String);"),
+		}
+
+		reversible, err := exec.VerifyReversible(ctx, client, diff)
+		require.NoError(t, err)
+		require.False(t, reversible)
+	})
+}
@@ -143,3 +143,9 @@ func (d *DDLFormatter) buildRenameStatement(objectType string, renames []RenameI
 	parts = d.appendOnCluster(parts, onCluster)
 	return parts
 }
+
+// buildExchangeStatement builds common EXCHANGE statement parts.
+func (d *DDLFormatter) buildExchangeStatement(objectType, first, second string, onCluster *string) []string {
+	parts := []string{d.formatter.keyword("EXCHANGE " + objectType), first, d.formatter.keyword("AND"), second}
+	return d.appendOnCluster(parts, onCluster)
+}
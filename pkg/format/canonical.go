@@ -0,0 +1,138 @@
+package format
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// Canonical ordering ranks for object definitions. Global objects come first so that
+// databases, tables, dictionaries, and views can reference them.
+const (
+	canonicalRankRole = iota
+	canonicalRankFunction
+	canonicalRankNamedCollection
+	canonicalRankDatabase
+	canonicalRankTable
+	canonicalRankTableAs
+	canonicalRankDictionary
+	canonicalRankView
+)
+
+// canonicalUnit is a statement along with the standalone comments directly preceding it.
+// Units are moved as a whole so comments stay attached to the statement they describe.
+type canonicalUnit struct {
+	statements []*parser.Statement
+	sortable   bool
+	rank       int
+	name       string
+}
+
+// canonicalOrder reorders statements into a deterministic, canonical order without
+// changing their meaning.
+//
+// Runs of CREATE statements are sorted by kind (roles, functions, named collections,
+// databases, tables, dictionaries, views) and then alphabetically by qualified name.
+// Statements whose creation order can matter keep their relative order: functions and
+// views may reference each other, and tables created AS another table are placed after
+// all other tables. Any other statement (ALTER, DROP, GRANT, etc.) acts as a barrier that
+// nothing is moved across, so order-sensitive scripts such as migrations remain valid.
+func canonicalOrder(statements []*parser.Statement) []*parser.Statement {
+	var units []canonicalUnit
+	var comments []*parser.Statement
+
+	for _, stmt := range statements {
+		if stmt == nil {
+			continue
+		}
+		if stmt.CommentStatement != nil {
+			comments = append(comments, stmt)
+			continue
+		}
+
+		unit := canonicalUnitFor(stmt)
+		unit.statements = append(comments, stmt)
+		units = append(units, unit)
+		comments = nil
+	}
+
+	result := make([]*parser.Statement, 0, len(statements))
+	start := 0
+	for i := 0; i <= len(units); i++ {
+		if i < len(units) && units[i].sortable {
+			continue
+		}
+
+		// Sort the run of definitions preceding the barrier (or the end of input)
+		run := units[start:i]
+		slices.SortStableFunc(run, func(a, b canonicalUnit) int {
+			return cmp.Or(cmp.Compare(a.rank, b.rank), cmp.Compare(a.name, b.name))
+		})
+		for _, unit := range run {
+			result = append(result, unit.statements...)
+		}
+
+		if i < len(units) {
+			result = append(result, units[i].statements...)
+		}
+		start = i + 1
+	}
+
+	// Trailing comments stay at the end
+	return append(result, comments...)
+}
+
+// canonicalUnitFor determines how a statement is ordered. Statements that keep their
+// relative order within a kind are given an empty name so the stable sort preserves it.
+func canonicalUnitFor(stmt *parser.Statement) canonicalUnit {
+	switch {
+	case stmt.CreateRole != nil:
+		return canonicalUnit{sortable: true, rank: canonicalRankRole, name: stmt.CreateRole.Name}
+	case stmt.CreateFunction != nil:
+		return canonicalUnit{sortable: true, rank: canonicalRankFunction}
+	case stmt.CreateNamedCollection != nil:
+		return canonicalUnit{sortable: true, rank: canonicalRankNamedCollection, name: stmt.CreateNamedCollection.Name}
+	case stmt.CreateDatabase != nil:
+		return canonicalUnit{sortable: true, rank: canonicalRankDatabase, name: stmt.CreateDatabase.Name}
+	case stmt.CreateTable != nil:
+		table := stmt.CreateTable
+		if table.AsTable != nil && table.AsTable.Function == nil {
+			return canonicalUnit{sortable: true, rank: canonicalRankTableAs}
+		}
+		return canonicalUnit{sortable: true, rank: canonicalRankTable, name: canonicalName(table.Database, table.Name)}
+	case stmt.CreateDictionary != nil:
+		dict := stmt.CreateDictionary
+		return canonicalUnit{sortable: true, rank: canonicalRankDictionary, name: canonicalName(dict.Database, dict.Name)}
+	case stmt.CreateView != nil:
+		return canonicalUnit{sortable: true, rank: canonicalRankView}
+	default:
+		return canonicalUnit{}
+	}
+}
+
+// canonicalName returns the sort key for a possibly database-qualified object name
+func canonicalName(database *string, name string) string {
+	if database == nil {
+		return "." + name
+	}
+	return *database + "." + name
+}
+
+// sortedTableSettings returns a copy of the settings sorted by name
+func sortedTableSettings(settings []parser.TableSetting) []parser.TableSetting {
+	sorted := slices.Clone(settings)
+	slices.SortStableFunc(sorted, func(a, b parser.TableSetting) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+// sortedDictionarySettings returns a copy of the settings sorted by name
+func sortedDictionarySettings(settings []*parser.DictionarySetting) []*parser.DictionarySetting {
+	sorted := slices.Clone(settings)
+	slices.SortStableFunc(sorted, func(a, b *parser.DictionarySetting) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
@@ -0,0 +1,80 @@
+package format
+
+import (
+	"strings"
+)
+
+// compactSQL collapses a formatted statement onto a single line. Every run of whitespace
+// outside of string literals, quoted identifiers, and comments becomes a single space,
+// except after an opening or before a closing parenthesis, where a line break is simply
+// removed. Line comments are rewritten as block comments so they don't swallow the rest
+// of the statement.
+func compactSQL(sql string) string {
+	var sb strings.Builder
+	sb.Grow(len(sql))
+
+	for i := 0; i < len(sql); {
+		switch c := sql[i]; {
+		case c == '\'' || c == '`':
+			end := quotedEnd(sql, i)
+			sb.WriteString(sql[i:end])
+			i = end
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexAny(sql[i:], "\r\n")
+			if end == -1 {
+				end = len(sql) - i
+			}
+			text := strings.TrimSpace(sql[i+2 : i+end])
+			sb.WriteString("/* " + strings.ReplaceAll(text, "*/", "* /") + " */")
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end == -1 {
+				end = len(sql) - i - 2
+			} else {
+				end += 2
+			}
+			sb.WriteString(strings.Join(strings.Fields(sql[i:i+2+end]), " "))
+			i += 2 + end
+		case isSpace(c):
+			end := i
+			for end < len(sql) && isSpace(sql[end]) {
+				end++
+			}
+
+			out := sb.String()
+			atEdge := len(out) == 0 || end == len(sql)
+			lineBreak := strings.ContainsAny(sql[i:end], "\r\n")
+			nextToParen := (len(out) > 0 && out[len(out)-1] == '(') || (end < len(sql) && sql[end] == ')')
+			if !atEdge && (!lineBreak || !nextToParen) {
+				sb.WriteByte(' ')
+			}
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+
+	return sb.String()
+}
+
+// quotedEnd returns the index just past the string literal or quoted identifier starting
+// at start, honoring backslash escapes
+func quotedEnd(sql string, start int) int {
+	quote := sql[start]
+	for i := start + 1; i < len(sql); i++ {
+		switch sql[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(sql)
+}
+
+// isSpace reports whether c is an ASCII whitespace character
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -23,6 +23,11 @@ func (f *Formatter) createDatabase(w io.Writer, stmt *parser.CreateDatabaseStmt)
 			parts = append(parts, f.keyword("ENGINE"), "=", f.formatDatabaseEngine(stmt.Engine))
 		}
 
+		// SETTINGS
+		if stmt.Settings != nil {
+			parts = append(parts, f.formatTableSettings(stmt.Settings))
+		}
+
 		// COMMENT
 		parts = ddl.appendComment(parts, stmt.Comment)
 
@@ -44,8 +49,13 @@ func (f *Formatter) alterDatabase(w io.Writer, stmt *parser.AlterDatabaseStmt) e
 		}
 
 		// Action
-		if stmt.Action != nil && stmt.Action.ModifyComment != nil {
-			parts = append(parts, f.keyword("MODIFY COMMENT"), *stmt.Action.ModifyComment)
+		if stmt.Action != nil {
+			switch {
+			case stmt.Action.ModifyComment != nil:
+				parts = append(parts, f.keyword("MODIFY COMMENT"), *stmt.Action.ModifyComment)
+			case len(stmt.Action.ModifySetting) > 0:
+				parts = append(parts, f.formatModifyDatabaseSetting(stmt.Action.ModifySetting))
+			}
 		}
 
 		_, err := w.Write([]byte(strings.Join(parts, " ") + ";"))
@@ -53,6 +63,16 @@ func (f *Formatter) alterDatabase(w io.Writer, stmt *parser.AlterDatabaseStmt) e
 	})
 }
 
+// formatModifyDatabaseSetting formats the settings of a MODIFY SETTING action
+func (f *Formatter) formatModifyDatabaseSetting(settings []parser.TableSetting) string {
+	settingParts := make([]string, 0, len(settings))
+	for _, setting := range settings {
+		settingParts = append(settingParts, setting.Name+" = "+f.formatTableSettingValue(&setting))
+	}
+
+	return f.keyword("MODIFY SETTING") + " " + strings.Join(settingParts, ", ")
+}
+
 // AttachDatabase formats an ATTACH DATABASE statement
 func (f *Formatter) attachDatabase(w io.Writer, stmt *parser.AttachDatabaseStmt) error {
 	return f.formatWithComments(w, stmt, func(w io.Writer) error {
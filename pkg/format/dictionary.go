@@ -144,6 +144,18 @@ func (f *Formatter) renameDictionary(w io.Writer, stmt *parser.RenameDictionaryS
 	})
 }
 
+// exchangeDictionaries formats an EXCHANGE DICTIONARIES statement
+func (f *Formatter) exchangeDictionaries(w io.Writer, stmt *parser.ExchangeDictionariesStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		ddl := NewDDLFormatter(f)
+		parts := ddl.buildExchangeStatement("DICTIONARIES",
+			f.qualifiedName(stmt.FirstDatabase, stmt.FirstName),
+			f.qualifiedName(stmt.SecondDatabase, stmt.SecondName),
+			stmt.OnCluster)
+		return ddl.formatBasicDDL(w, parts)
+	})
+}
+
 // formatDictionaryColumns formats dictionary column definitions
 func (f *Formatter) formatDictionaryColumns(columns []*parser.DictionaryColumn) []string {
 	if len(columns) == 0 {
@@ -276,8 +288,13 @@ func (f *Formatter) formatDictionarySettings(settings *parser.DictionarySettings
 	var parts []string
 	parts = append(parts, f.keyword("SETTINGS"))
 
-	settingParts := make([]string, 0, len(settings.Settings))
-	for _, setting := range settings.Settings {
+	dictSettings := settings.Settings
+	if f.options.Canonical {
+		dictSettings = sortedDictionarySettings(dictSettings)
+	}
+
+	settingParts := make([]string, 0, len(dictSettings))
+	for _, setting := range dictSettings {
 		settingParts = append(settingParts, setting.Name+" = "+setting.Value)
 	}
 	parts = append(parts, "("+strings.Join(settingParts, ", ")+")")
@@ -209,6 +209,8 @@ func (f *Formatter) formatPrimaryExpressionWithContext(primary *parser.PrimaryEx
 		return f.formatLiteral(primary.Literal)
 	case primary.Identifier != nil:
 		return f.formatIdentifierExpr(primary.Identifier)
+	case primary.Lambda != nil:
+		return f.formatLambdaExpression(primary.Lambda)
 	case primary.Function != nil:
 		return f.formatFunctionCallWithContext(primary.Function, multilineContext)
 	case primary.Parentheses != nil:
@@ -217,6 +219,8 @@ func (f *Formatter) formatPrimaryExpressionWithContext(primary *parser.PrimaryEx
 		return f.formatTupleExpression(primary.Tuple)
 	case primary.Array != nil:
 		return f.formatArrayExpression(primary.Array)
+	case primary.Map != nil:
+		return f.formatMapExpression(primary.Map)
 	case primary.Cast != nil:
 		return f.formatCastExpression(primary.Cast)
 	case primary.Interval != nil:
@@ -303,6 +307,9 @@ func (f *Formatter) formatFunctionCallWithContext(fn *parser.FunctionCall, multi
 		}
 		result += strings.Join(args, ", ")
 	}
+	if len(fn.OrderBy) > 0 {
+		result += " " + f.formatArgumentOrderBy(fn.OrderBy)
+	}
 	result += ")"
 
 	// Handle second parentheses for parameterized functions
@@ -318,12 +325,21 @@ func (f *Formatter) formatFunctionCallWithContext(fn *parser.FunctionCall, multi
 
 	// Handle OVER clause for window functions
 	if fn.Over != nil {
-		result += " " + fn.Over.String()
+		result += " " + f.formatOverClause(fn.Over)
 	}
 
 	return result
 }
 
+// formatOverClause formats the OVER clause of a window function, quoting references
+// to named windows like the WINDOW clause that defines them
+func (f *Formatter) formatOverClause(over *parser.OverClause) string {
+	if over.Name != nil {
+		return f.keyword("OVER") + " " + f.identifier(*over.Name)
+	}
+	return over.String()
+}
+
 // shouldFormatFunctionMultiline determines if a function should be formatted multi-line
 func (f *Formatter) shouldFormatFunctionMultiline(fn *parser.FunctionCall, multilineContext bool) bool {
 	if !f.options.MultilineFunctions {
@@ -362,6 +378,9 @@ func (f *Formatter) formatFunctionCallMultiline(fn *parser.FunctionCall) string
 			result += f.formatArgumentsLineByLine(fn.FirstParentheses, indentStr)
 		}
 	}
+	if len(fn.OrderBy) > 0 {
+		result += indentStr + f.formatArgumentOrderBy(fn.OrderBy) + "\n"
+	}
 
 	result += ")"
 
@@ -375,7 +394,7 @@ func (f *Formatter) formatFunctionCallMultiline(fn *parser.FunctionCall) string
 
 	// Handle OVER clause for window functions
 	if fn.Over != nil {
-		result += " " + fn.Over.String()
+		result += " " + f.formatOverClause(fn.Over)
 	}
 
 	return result
@@ -438,6 +457,24 @@ func (f *Formatter) formatArgumentsLineByLine(args []parser.FunctionArg, indentS
 	return results.String()
 }
 
+// formatArgumentOrderBy formats the ORDER BY within a function's arguments, e.g. the
+// "ORDER BY y" in groupArray(x ORDER BY y)
+func (f *Formatter) formatArgumentOrderBy(orderBy []parser.OrderByExpr) string {
+	items := make([]string, 0, len(orderBy))
+	for _, item := range orderBy {
+		itemStr := f.formatExpression(&item.Expression)
+		if item.Desc {
+			itemStr += " " + f.keyword("DESC")
+		}
+		if item.Nulls != nil {
+			itemStr += " " + f.keyword("NULLS") + " " + f.keyword(*item.Nulls)
+		}
+		items = append(items, itemStr)
+	}
+
+	return f.keyword("ORDER BY") + " " + strings.Join(items, ", ")
+}
+
 // formatFunctionArg formats a function argument
 func (f *Formatter) formatFunctionArg(arg *parser.FunctionArg) string {
 	if arg == nil {
@@ -466,6 +503,35 @@ func (f *Formatter) formatArrayExpression(arr *parser.ArrayExpression) string {
 	return "[" + strings.Join(elements, ", ") + "]"
 }
 
+// formatMapExpression formats a map literal expression
+func (f *Formatter) formatMapExpression(m *parser.MapExpression) string {
+	if m == nil || len(m.Entries) == 0 {
+		return "{}"
+	}
+
+	entries := make([]string, len(m.Entries))
+	for i, entry := range m.Entries {
+		entries[i] = f.formatExpression(&entry.Key) + ": " + f.formatExpression(&entry.Value)
+	}
+
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// formatLambdaExpression formats a lambda, parenthesizing multiple parameters
+func (f *Formatter) formatLambdaExpression(lambda *parser.LambdaExpression) string {
+	params := make([]string, len(lambda.Parameters))
+	for i, param := range lambda.Parameters {
+		params[i] = f.identifier(param)
+	}
+
+	paramStr := params[0]
+	if len(params) > 1 {
+		paramStr = "(" + strings.Join(params, ", ") + ")"
+	}
+
+	return paramStr + " -> " + f.formatExpression(&lambda.Body)
+}
+
 // formatTupleExpression formats a tuple expression
 func (f *Formatter) formatTupleExpression(tuple *parser.TupleExpression) string {
 	if tuple == nil || len(tuple.Elements) == 0 {
@@ -502,11 +568,26 @@ func (f *Formatter) formatExtractExpression(extract *parser.ExtractExpression) s
 	return extract.String()
 }
 
+// formatCaseExpression formats a CASE expression, formatting each condition and result
 func (f *Formatter) formatCaseExpression(caseExpr *parser.CaseExpression) string {
 	if caseExpr == nil {
 		return ""
 	}
-	return caseExpr.String()
+
+	var result strings.Builder
+	result.WriteString(f.keyword("CASE"))
+
+	for _, when := range caseExpr.WhenClauses {
+		result.WriteString(" " + f.keyword("WHEN") + " " + f.formatExpression(&when.Condition))
+		result.WriteString(" " + f.keyword("THEN") + " " + f.formatExpression(&when.Result))
+	}
+
+	if caseExpr.ElseClause != nil {
+		result.WriteString(" " + f.keyword("ELSE") + " " + f.formatExpression(&caseExpr.ElseClause.Result))
+	}
+
+	result.WriteString(" " + f.keyword("END"))
+	return result.String()
 }
 
 // formatDataType formats a data type specification
@@ -67,6 +67,17 @@ type (
 		PairedFunctionNames []string
 		// PairSize specifies how many arguments to group per line (default: 2)
 		PairSize int
+		// Canonical produces a deterministic canonical form suitable for a committed baseline.
+		// Object definitions are sorted by kind and name, SETTINGS are sorted by key, and all
+		// other options are replaced with Defaults. Columns, ORDER BY keys, and any other
+		// order-sensitive constructs are never reordered.
+		Canonical bool
+		// Compact writes each statement on a single line with single spaces between tokens,
+		// e.g. for embedding generated SQL in logs or revision records. Columns aren't
+		// aligned, function calls aren't broken across lines, and comments within a
+		// statement are written as /* */ block comments. Statements are separated by a
+		// newline. The output parses back to the same statements.
+		Compact bool
 	}
 
 	// Formatter handles SQL statement formatting with configurable options
@@ -162,6 +173,17 @@ var Defaults = FormatterOptions{
 //	err := formatter.Format(&buf1, stmt1, stmt2)
 //	err = formatter.Format(&buf2, stmt3, stmt4)
 func New(options FormatterOptions) *Formatter {
+	if options.Canonical {
+		compact := options.Compact
+		options = Defaults
+		options.Canonical = true
+		options.Compact = compact
+	}
+	if options.Compact {
+		options.AlignColumns = false
+		options.MultilineFunctions = false
+		options.SmartFunctionPairing = false
+	}
 	return &Formatter{options: &options}
 }
 
@@ -217,8 +239,12 @@ func FormatSQL(w io.Writer, opts FormatterOptions, sql *parser.SQL) error {
 // Format writes formatted SQL statements to the provided writer.
 //
 // Each statement is formatted according to the formatter's configuration and
-// written to the writer. Sequential comments have no blank lines between them,
-// while comments and SQL statements are separated by blank lines.
+// written to the writer. SQL statements are separated by blank lines. For parsed
+// statements, comments keep their layout from the source: a comment directly above
+// a statement or another comment stays attached to it, blank lines between comment
+// blocks are preserved, and a comment on the same line as the end of a statement
+// stays on that line. Without source positions, sequential comments have no blank
+// lines between them, while comments and SQL statements are separated by blank lines.
 // Any write errors are returned immediately.
 //
 // Parameters:
@@ -231,41 +257,85 @@ func (f *Formatter) Format(w io.Writer, statements ...*parser.Statement) error {
 		return nil
 	}
 
-	first := true
-	var prevWasComment bool
+	if f.options.Canonical {
+		statements = canonicalOrder(statements)
+	}
 
+	var prev *parser.Statement
 	for _, stmt := range statements {
 		if stmt == nil {
 			continue
 		}
 
-		currentIsComment := stmt.CommentStatement != nil
-
-		if !first { // nolint: nestif
-			// Sequential comments: single newline
-			// Comment to SQL or SQL to comment: double newline
-			// SQL to SQL: double newline
-			if prevWasComment && currentIsComment {
-				if _, err := w.Write([]byte("\n")); err != nil {
-					return err
-				}
-			} else {
-				if _, err := w.Write([]byte("\n\n")); err != nil {
-					return err
-				}
+		if prev != nil {
+			if _, err := io.WriteString(w, f.statementSeparator(prev, stmt)); err != nil {
+				return err
 			}
 		}
 
-		if err := f.statement(w, stmt); err != nil {
+		if f.options.Compact {
+			var buf strings.Builder
+			if err := f.statement(&buf, stmt); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, compactSQL(buf.String())); err != nil {
+				return err
+			}
+		} else if err := f.statement(w, stmt); err != nil {
 			return err
 		}
 
-		first = false
-		prevWasComment = currentIsComment
+		prev = stmt
 	}
 	return nil
 }
 
+// statementSeparator returns the whitespace written between two consecutive statements.
+// When both were parsed from the same input in this order, comments keep their position
+// relative to their neighbours (see Format). In compact mode every statement starts on a
+// new line, except a comment trailing the previous statement.
+func (f *Formatter) statementSeparator(prev, current *parser.Statement) string {
+	sep := statementSeparator(prev, current)
+	if f.options.Compact && sep != " " {
+		return "\n"
+	}
+	return sep
+}
+
+// statementSeparator returns the whitespace written between two consecutive statements
+// in the regular (non-compact) output
+func statementSeparator(prev, current *parser.Statement) string {
+	prevIsComment := prev.CommentStatement != nil
+	currentIsComment := current.CommentStatement != nil
+	if !prevIsComment && !currentIsComment {
+		return "\n\n"
+	}
+
+	if !hasSourceOrder(prev, current) {
+		if prevIsComment && currentIsComment {
+			return "\n"
+		}
+		return "\n\n"
+	}
+
+	lines := current.Pos.Line - prev.EndPos.Line
+	switch {
+	case currentIsComment && !prevIsComment && lines == 0:
+		return " " // trailing comment on the last line of the statement
+	case prevIsComment && lines <= 1:
+		return "\n" // comment directly above the next comment or statement
+	default:
+		return "\n\n"
+	}
+}
+
+// hasSourceOrder reports whether current directly follows prev in the same parsed input
+func hasSourceOrder(prev, current *parser.Statement) bool {
+	return prev.EndPos.Line > 0 && current.Pos.Line > 0 &&
+		prev.EndPos.Filename == current.Pos.Filename &&
+		current.Pos.Offset >= prev.EndPos.Offset
+}
+
 // FormatSQL formats all statements from a parsed SQL structure using this formatter's configuration.
 //
 // This is equivalent to calling f.Format(w, sqlResult.Statements...) but more concise
@@ -335,8 +405,12 @@ func (f *Formatter) formatTableStatements(w io.Writer, stmt *parser.Statement) e
 		return f.detachTable(w, stmt.DetachTable)
 	case stmt.DropTable != nil:
 		return f.dropTable(w, stmt.DropTable)
+	case stmt.TruncateTable != nil:
+		return f.truncateTable(w, stmt.TruncateTable)
 	case stmt.RenameTable != nil:
 		return f.renameTable(w, stmt.RenameTable)
+	case stmt.ExchangeTables != nil:
+		return f.exchangeTables(w, stmt.ExchangeTables)
 	}
 	return nil
 }
@@ -360,6 +434,8 @@ func (f *Formatter) formatDictionaryStatements(w io.Writer, stmt *parser.Stateme
 		return f.dropDictionary(w, stmt.DropDictionary)
 	case stmt.RenameDictionary != nil:
 		return f.renameDictionary(w, stmt.RenameDictionary)
+	case stmt.ExchangeDictionaries != nil:
+		return f.exchangeDictionaries(w, stmt.ExchangeDictionaries)
 	}
 	return nil
 }
@@ -379,7 +455,7 @@ func (f *Formatter) formatViewStatements(w io.Writer, stmt *parser.Statement) er
 	return nil
 }
 
-// formatRoleStatements handles role-related statements
+// formatRoleStatements handles role, user, and grant statements
 func (f *Formatter) formatRoleStatements(w io.Writer, stmt *parser.Statement) error {
 	switch {
 	case stmt.CreateRole != nil:
@@ -388,6 +464,12 @@ func (f *Formatter) formatRoleStatements(w io.Writer, stmt *parser.Statement) er
 		return f.alterRole(w, stmt.AlterRole)
 	case stmt.DropRole != nil:
 		return f.dropRole(w, stmt.DropRole)
+	case stmt.CreateUser != nil:
+		return f.createUser(w, stmt.CreateUser)
+	case stmt.AlterUser != nil:
+		return f.alterUser(w, stmt.AlterUser)
+	case stmt.DropUser != nil:
+		return f.dropUser(w, stmt.DropUser)
 	case stmt.SetRole != nil:
 		return f.setRole(w, stmt.SetRole)
 	case stmt.SetDefaultRole != nil:
@@ -416,6 +498,8 @@ func (f *Formatter) formatOtherStatements(w io.Writer, stmt *parser.Statement) e
 	switch {
 	case stmt.CommentStatement != nil:
 		return f.formatCommentStatement(w, stmt.CommentStatement)
+	case stmt.System != nil:
+		return f.systemStatement(w, stmt.System)
 	case stmt.SelectStatement != nil:
 		return f.selectStatement(w, stmt.SelectStatement)
 	}
@@ -2,10 +2,13 @@ package format_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/alecthomas/participle/v2/lexer"
 	. "github.com/pseudomuto/housekeeper/pkg/format"
 	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
 	"github.com/stretchr/testify/require"
 )
 
@@ -267,3 +270,193 @@ func TestFormatter_BooleanDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatter_Canonical(t *testing.T) {
+	input := `CREATE VIEW analytics.z_summary AS SELECT * FROM analytics.events;
+CREATE VIEW analytics.a_recent AS SELECT * FROM analytics.z_summary;
+CREATE TABLE analytics.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY (name, id) SETTINGS storage_policy = 'hot', index_granularity = 4096;
+-- Events table
+CREATE TABLE analytics.events (ts DateTime, id UInt64) ENGINE = MergeTree() ORDER BY (ts, id);
+CREATE TABLE analytics.events_copy AS analytics.events ENGINE = MergeTree() ORDER BY (ts, id);
+CREATE DICTIONARY analytics.lookup (id UInt64, name String) PRIMARY KEY id SOURCE(NULL()) LAYOUT(FLAT()) LIFETIME(0);
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE ROLE reader;`
+
+	canonical := func(t *testing.T, sql string, opts FormatterOptions) string {
+		t.Helper()
+
+		parsed, err := parser.ParseString(sql)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, Format(&buf, opts, parsed.Statements...))
+		return buf.String()
+	}
+
+	t.Run("sorts definitions and settings", func(t *testing.T) {
+		formatted := canonical(t, input, FormatterOptions{Canonical: true})
+
+		order := []string{
+			"CREATE ROLE `reader`",
+			"CREATE DATABASE `analytics`",
+			"-- Events table",
+			"CREATE TABLE `analytics`.`events`",
+			"CREATE TABLE `analytics`.`users`",
+			"CREATE TABLE `analytics`.`events_copy`",
+			"CREATE DICTIONARY `analytics`.`lookup`",
+			"CREATE VIEW `analytics`.`z_summary`",
+			"CREATE VIEW `analytics`.`a_recent`",
+		}
+		last := -1
+		for _, expected := range order {
+			idx := strings.Index(formatted, expected)
+			require.Greater(t, idx, last, "expected %q to follow the previous definition", expected)
+			last = idx
+		}
+
+		require.Contains(t, formatted, "SETTINGS index_granularity = 4096, storage_policy = 'hot'")
+		require.Contains(t, formatted, "ORDER BY (`name`, `id`)", "ORDER BY keys must not be reordered")
+		require.Regexp(t, "`id`\\s+UInt64,\\s+`name`\\s+String", formatted, "columns must not be reordered")
+	})
+
+	t.Run("sorts dictionary settings", func(t *testing.T) {
+		formatted := canonical(t, `CREATE DICTIONARY db.lookup (id UInt64) PRIMARY KEY id SOURCE(NULL()) LAYOUT(FLAT()) LIFETIME(0)
+SETTINGS(max_threads = 2, format_csv_allow_single_quotes = 1);`, FormatterOptions{Canonical: true})
+		require.Contains(t, formatted, "SETTINGS(format_csv_allow_single_quotes = 1, max_threads = 2)")
+	})
+
+	t.Run("ignores other options", func(t *testing.T) {
+		require.Equal(t,
+			canonical(t, input, FormatterOptions{Canonical: true}),
+			canonical(t, input, FormatterOptions{Canonical: true, IndentSize: 2, UppercaseKeywords: false}))
+	})
+
+	t.Run("is stable", func(t *testing.T) {
+		once := canonical(t, input, FormatterOptions{Canonical: true})
+		require.Equal(t, once, canonical(t, once, FormatterOptions{Canonical: true}))
+	})
+
+	t.Run("is semantically equivalent", func(t *testing.T) {
+		original, err := parser.ParseString(input)
+		require.NoError(t, err)
+
+		formatted, err := parser.ParseString(canonical(t, input, FormatterOptions{Canonical: true}))
+		require.NoError(t, err)
+
+		_, err = schema.GenerateDiff(original, formatted)
+		require.ErrorIs(t, err, schema.ErrNoDiff)
+	})
+
+	t.Run("does not move statements across barriers", func(t *testing.T) {
+		formatted := canonical(t, `CREATE TABLE db.b (id UInt64) ENGINE = Memory;
+ALTER TABLE db.b ADD COLUMN name String;
+CREATE TABLE db.a (id UInt64) ENGINE = Memory;
+DROP TABLE db.b;`, FormatterOptions{Canonical: true})
+
+		create := strings.Index(formatted, "CREATE TABLE `db`.`b`")
+		alter := strings.Index(formatted, "ALTER TABLE `db`.`b`")
+		createA := strings.Index(formatted, "CREATE TABLE `db`.`a`")
+		drop := strings.Index(formatted, "DROP TABLE `db`.`b`")
+		require.True(t, create < alter && alter < createA && createA < drop, formatted)
+	})
+}
+
+func TestFormatter_Compact(t *testing.T) {
+	tests := map[string]string{
+		"table": `CREATE TABLE analytics.events (
+			id UInt64 COMMENT 'event id',
+			user_id UInt64 CODEC(ZSTD(1)),
+			payload String DEFAULT 'it\'s quoted',
+			tags Array(String),
+			INDEX idx_user user_id TYPE bloom_filter GRANULARITY 4,
+			PROJECTION by_user (SELECT user_id, count() GROUP BY user_id)
+		) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')
+		ORDER BY (user_id, id)
+		PARTITION BY intDiv(id, 1000)
+		TTL toDateTime(id) + INTERVAL 30 DAY
+		SETTINGS index_granularity = 8192
+		COMMENT 'Events table';`,
+		"view": `CREATE MATERIALIZED VIEW analytics.mv_daily TO analytics.daily AS
+		SELECT toDate(ts) AS day, multiIf(status = 1, 'ok', status = 2, 'warn', 'error') AS level, count() AS total
+		FROM analytics.events
+		WHERE user_id > 0 AND status IN (1, 2, 3)
+		GROUP BY day, level
+		ORDER BY day;`,
+		"dictionary": `CREATE DICTIONARY analytics.users_dict (
+			id UInt64 IS_OBJECT_ID,
+			parent_id UInt64 DEFAULT 0 HIERARCHICAL,
+			name String DEFAULT 'unknown'
+		)
+		PRIMARY KEY id
+		SOURCE(CLICKHOUSE(host 'localhost' port 9000 table 'users' user 'default'))
+		LAYOUT(HASHED())
+		LIFETIME(MIN 300 MAX 600)
+		COMMENT 'Users lookup';`,
+	}
+
+	compactOpts := Defaults
+	compactOpts.Compact = true
+
+	formatWith := func(t *testing.T, opts FormatterOptions, sql *parser.SQL) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		require.NoError(t, FormatSQL(&buf, opts, sql))
+		return buf.String()
+	}
+
+	// parse parses sql, clearing the statement positions so ASTs of differently laid out
+	// SQL can be compared
+	parse := func(t *testing.T, sql string) *parser.SQL {
+		t.Helper()
+
+		parsed, err := parser.ParseString(sql)
+		require.NoError(t, err)
+		for _, stmt := range parsed.Statements {
+			stmt.Pos, stmt.EndPos = lexer.Position{}, lexer.Position{}
+		}
+		return parsed
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsed := parse(t, input)
+
+			compact := formatWith(t, compactOpts, parsed)
+			require.NotContains(t, compact, "\n")
+			require.NotContains(t, compact, "  ", "tokens must be separated by a single space")
+
+			expected := parse(t, formatWith(t, Defaults, parsed))
+			require.Equal(t, expected, parse(t, compact))
+		})
+	}
+
+	t.Run("one statement per line", func(t *testing.T) {
+		parsed := parse(t, "CREATE DATABASE db;\n\nCREATE TABLE db.t (id UInt64) ENGINE = Memory;")
+		require.Equal(t,
+			"CREATE DATABASE `db`;\nCREATE TABLE `db`.`t` (`id` UInt64) ENGINE = Memory();",
+			formatWith(t, compactOpts, parsed))
+	})
+
+	t.Run("preserves string literals", func(t *testing.T) {
+		parsed := parse(t, "CREATE TABLE db.t (id UInt64 COMMENT 'two  spaces\\nand a newline') ENGINE = Memory;")
+		require.Contains(t, formatWith(t, compactOpts, parsed), "'two  spaces\\nand a newline'")
+	})
+
+	t.Run("applies to the zero value options", func(t *testing.T) {
+		parsed := parse(t, tests["table"])
+		parse(t, formatWith(t, FormatterOptions{Compact: true}, parsed))
+	})
+
+	t.Run("rewrites line comments", func(t *testing.T) {
+		parsed := parse(t, `CREATE TABLE db.t (
+			-- primary key
+			id UInt64
+		) ENGINE = Memory;`)
+
+		compact := formatWith(t, compactOpts, parsed)
+		require.Contains(t, compact, "/* primary key */")
+		require.NotContains(t, compact, "--")
+		parse(t, compact)
+	})
+}
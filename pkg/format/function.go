@@ -10,7 +10,15 @@ import (
 // formatCreateFunction formats CREATE FUNCTION statements
 func (f *Formatter) formatCreateFunction(w io.Writer, stmt *parser.CreateFunctionStmt) error {
 	return f.formatWithComments(w, stmt, func(w io.Writer) error {
-		if _, err := w.Write([]byte(f.keyword("create") + " " + f.keyword("function") + " ")); err != nil {
+		create := f.keyword("create") + " "
+		if stmt.OrReplace {
+			create += f.keyword("or") + " " + f.keyword("replace") + " "
+		}
+		create += f.keyword("function") + " "
+		if stmt.IfNotExists {
+			create += f.keyword("if") + " " + f.keyword("not") + " " + f.keyword("exists") + " "
+		}
+		if _, err := w.Write([]byte(create)); err != nil {
 			return err
 		}
 
@@ -51,7 +59,7 @@ func (f *Formatter) formatCreateFunction(w io.Writer, stmt *parser.CreateFunctio
 
 		// Format the expression with multi-line context if needed
 		// Calculate base indentation for the expression (length of "CREATE FUNCTION name ON CLUSTER cluster AS (params) -> ")
-		baseIndent := len("CREATE FUNCTION ") + len(stmt.Name)
+		baseIndent := len(create) + len(stmt.Name)
 		if stmt.OnCluster != nil {
 			baseIndent += len(" ON CLUSTER ") + len(*stmt.OnCluster)
 		}
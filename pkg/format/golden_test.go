@@ -48,6 +48,14 @@ func TestGoldenFiles(t *testing.T) {
 
 			// Compare with golden file
 			golden.Assert(t, result, outputName)
+
+			// Formatting is stable: the formatted SQL, comments included, formats to itself
+			reparsed, err := parser.ParseString(result)
+			require.NoError(t, err)
+
+			var reformatted bytes.Buffer
+			require.NoError(t, Format(&reformatted, Defaults, reparsed.Statements...))
+			require.Equal(t, strings.TrimSuffix(result, "\n"), reformatted.String())
 		})
 	}
 }
@@ -186,16 +186,16 @@ func (f *Formatter) addSetOperation(lines *[]string, ops *parser.AlterNamedColle
 }
 
 // addDeleteOperation adds a DELETE-only operation to the lines
-func (f *Formatter) addDeleteOperation(lines *[]string, deleteParams []*string) {
+func (f *Formatter) addDeleteOperation(lines *[]string, deleteParams []string) {
 	deleteKeys := f.formatDeleteKeys(deleteParams)
 	*lines = append(*lines, f.indent(1)+f.keyword("DELETE")+" "+deleteKeys)
 }
 
 // formatDeleteKeys formats a list of delete parameter keys
-func (f *Formatter) formatDeleteKeys(deleteParams []*string) string {
+func (f *Formatter) formatDeleteKeys(deleteParams []string) string {
 	deleteKeys := make([]string, len(deleteParams))
 	for i, key := range deleteParams {
-		deleteKeys[i] = f.identifier(*key)
+		deleteKeys[i] = f.identifier(key)
 	}
 	return strings.Join(deleteKeys, ", ")
 }
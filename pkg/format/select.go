@@ -50,6 +50,16 @@ func (f *Formatter) formatSelectStatement(stmt *parser.SelectStatement) string {
 		lines = append(lines, f.formatFromClause(stmt.From))
 	}
 
+	// ARRAY JOIN clause
+	if stmt.ArrayJoin != nil {
+		lines = append(lines, f.formatArrayJoinClause(stmt.ArrayJoin))
+	}
+
+	// PREWHERE clause
+	if stmt.PreWhere != nil {
+		lines = append(lines, f.formatPreWhereClause(stmt.PreWhere))
+	}
+
 	// WHERE clause
 	if stmt.Where != nil {
 		lines = append(lines, f.formatWhereClause(stmt.Where))
@@ -65,6 +75,11 @@ func (f *Formatter) formatSelectStatement(stmt *parser.SelectStatement) string {
 		lines = append(lines, f.formatHavingClause(stmt.Having))
 	}
 
+	// WINDOW clause
+	if stmt.Window != nil {
+		lines = append(lines, f.formatWindowClause(stmt.Window))
+	}
+
 	// ORDER BY clause
 	if stmt.OrderBy != nil {
 		lines = append(lines, f.formatSelectOrderByClause(stmt.OrderBy))
@@ -80,6 +95,15 @@ func (f *Formatter) formatSelectStatement(stmt *parser.SelectStatement) string {
 		lines = append(lines, f.formatSettingsClause(stmt.Settings))
 	}
 
+	// UNION with the next SELECT
+	if stmt.Union != nil {
+		unionLine := f.keyword("UNION")
+		if stmt.Union.Mode != nil {
+			unionLine += " " + f.keyword(strings.ToUpper(*stmt.Union.Mode))
+		}
+		lines = append(lines, unionLine, f.formatSelectStatement(stmt.Union.Select))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -250,6 +274,29 @@ func (f *Formatter) formatJoinClause(join *parser.JoinClause) string {
 	return result
 }
 
+// formatArrayJoinClause formats [LEFT] ARRAY JOIN clause
+func (f *Formatter) formatArrayJoinClause(arrayJoin *parser.ArrayJoinClause) string {
+	if arrayJoin == nil {
+		return ""
+	}
+
+	result := f.keyword("ARRAY JOIN")
+	if arrayJoin.Left {
+		result = f.keyword("LEFT") + " " + result
+	}
+
+	arrays := make([]string, 0, len(arrayJoin.Arrays))
+	for _, array := range arrayJoin.Arrays {
+		formatted := f.formatExpression(&array.Expression)
+		if array.Alias != nil {
+			formatted += " " + f.keyword("AS") + " " + f.identifier(*array.Alias)
+		}
+		arrays = append(arrays, formatted)
+	}
+
+	return result + " " + strings.Join(arrays, ", ")
+}
+
 // formatWhereClause formats WHERE clause
 func (f *Formatter) formatWhereClause(where *parser.WhereClause) string {
 	if where == nil {
@@ -258,6 +305,14 @@ func (f *Formatter) formatWhereClause(where *parser.WhereClause) string {
 	return f.keyword("WHERE") + " " + f.formatExpression(&where.Condition)
 }
 
+// formatPreWhereClause formats PREWHERE clause
+func (f *Formatter) formatPreWhereClause(preWhere *parser.PreWhereClause) string {
+	if preWhere == nil {
+		return ""
+	}
+	return f.keyword("PREWHERE") + " " + f.formatExpression(&preWhere.Condition)
+}
+
 // formatGroupByClause formats GROUP BY clause
 func (f *Formatter) formatGroupByClause(groupBy *parser.GroupByClause) string {
 	if groupBy == nil {
@@ -266,11 +321,7 @@ func (f *Formatter) formatGroupByClause(groupBy *parser.GroupByClause) string {
 
 	// Handle GROUP BY ALL
 	if groupBy.All {
-		result := f.keyword("GROUP BY") + " " + f.keyword("ALL")
-		if groupBy.WithClause != nil {
-			result += " " + f.keyword("WITH") + " " + f.keyword(*groupBy.WithClause)
-		}
-		return result
+		return f.keyword("GROUP BY") + " " + f.keyword("ALL") + f.formatGroupByModifiers(groupBy)
 	}
 
 	// Handle regular GROUP BY with columns
@@ -282,12 +333,22 @@ func (f *Formatter) formatGroupByClause(groupBy *parser.GroupByClause) string {
 	for _, expr := range groupBy.Columns {
 		exprs = append(exprs, f.formatExpression(&expr))
 	}
-	result := f.keyword("GROUP BY") + " " + strings.Join(exprs, ", ")
+	return f.keyword("GROUP BY") + " " + strings.Join(exprs, ", ") + f.formatGroupByModifiers(groupBy)
+}
 
-	if groupBy.WithClause != nil {
-		result += " " + f.keyword("WITH") + " " + f.keyword(*groupBy.WithClause)
+// formatGroupByModifiers formats the WITH ROLLUP, WITH CUBE, and WITH TOTALS modifiers of a
+// GROUP BY clause, with a leading space when there are any
+func (f *Formatter) formatGroupByModifiers(groupBy *parser.GroupByClause) string {
+	var result string
+	switch {
+	case groupBy.WithRollup:
+		result += " " + f.keyword("WITH") + " " + f.keyword("ROLLUP")
+	case groupBy.WithCube:
+		result += " " + f.keyword("WITH") + " " + f.keyword("CUBE")
+	}
+	if groupBy.WithTotals {
+		result += " " + f.keyword("WITH") + " " + f.keyword("TOTALS")
 	}
-
 	return result
 }
 
@@ -299,6 +360,19 @@ func (f *Formatter) formatHavingClause(having *parser.HavingClause) string {
 	return f.keyword("HAVING") + " " + f.formatExpression(&having.Condition)
 }
 
+// formatWindowClause formats WINDOW clause with its named window definitions
+func (f *Formatter) formatWindowClause(window *parser.WindowClause) string {
+	if window == nil || len(window.Definitions) == 0 {
+		return ""
+	}
+
+	defs := make([]string, 0, len(window.Definitions))
+	for _, def := range window.Definitions {
+		defs = append(defs, f.identifier(def.Name)+" "+f.keyword("AS")+" "+def.String())
+	}
+	return f.keyword("WINDOW") + " " + strings.Join(defs, ", ")
+}
+
 // formatSelectOrderByClause formats ORDER BY clause for SELECT
 func (f *Formatter) formatSelectOrderByClause(orderBy *parser.SelectOrderByClause) string {
 	if orderBy == nil || len(orderBy.Columns) == 0 {
@@ -0,0 +1,39 @@
+package format
+
+import (
+	"io"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// systemStatement formats a SYSTEM statement
+func (f *Formatter) systemStatement(w io.Writer, stmt *parser.SystemStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		ddl := NewDDLFormatter(f)
+
+		var parts []string
+		switch {
+		case stmt.ReloadDictionary != nil:
+			parts = f.systemObject(ddl, "SYSTEM RELOAD DICTIONARY", stmt.ReloadDictionary)
+		case stmt.ReloadDictionaries != nil:
+			parts = ddl.appendOnCluster([]string{f.keyword("SYSTEM RELOAD DICTIONARIES")}, stmt.ReloadDictionaries.OnCluster)
+		case stmt.ReloadConfig != nil:
+			parts = ddl.appendOnCluster([]string{f.keyword("SYSTEM RELOAD CONFIG")}, stmt.ReloadConfig.OnCluster)
+		case stmt.FlushLogs != nil:
+			parts = ddl.appendOnCluster([]string{f.keyword("SYSTEM FLUSH LOGS")}, stmt.FlushLogs.OnCluster)
+		case stmt.SyncReplica != nil:
+			parts = f.systemObject(ddl, "SYSTEM SYNC REPLICA", &stmt.SyncReplica.SystemObjectTarget)
+			if stmt.SyncReplica.Mode != nil {
+				parts = append(parts, f.keyword(*stmt.SyncReplica.Mode))
+			}
+		}
+
+		return ddl.formatBasicDDL(w, parts)
+	})
+}
+
+// systemObject builds the parts of a SYSTEM statement targeting a table or dictionary
+func (f *Formatter) systemObject(ddl *DDLFormatter, command string, target *parser.SystemObjectTarget) []string {
+	parts := ddl.appendOnCluster([]string{f.keyword(command)}, target.OnCluster)
+	return append(parts, f.qualifiedName(target.Database, target.Name))
+}
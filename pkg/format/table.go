@@ -31,7 +31,7 @@ func (f *Formatter) createTable(w io.Writer, stmt *parser.CreateTableStmt) error
 		lines = lines[:0] // Clear lines since we already wrote them
 	}
 
-	lines = f.appendEngineAndClauses(lines, stmt)
+	lines = f.appendEngineAndClauses(lines, stmt.Engine, stmt.Clauses, stmt.Comment)
 
 	// Write the main statement
 	if _, err := w.Write([]byte(strings.Join(lines, "\n") + ";")); err != nil {
@@ -80,7 +80,7 @@ func (f *Formatter) buildCreateTableHeader(stmt *parser.CreateTableStmt) []strin
 	return []string{strings.Join(headerParts, " ") + " ("}
 }
 
-// appendTableElements formats and appends table elements (columns, indexes, constraints)
+// appendTableElements formats and appends table elements (columns, indexes, constraints, projections)
 func (f *Formatter) appendTableElements(lines []string, elements []parser.TableElement) []string {
 	// Convert slice to pointer slice
 	elementPtrs := make([]*parser.TableElement, len(elements))
@@ -113,7 +113,7 @@ func (f *Formatter) appendTableElements(lines []string, elements []parser.TableE
 	return lines
 }
 
-// formatTableElement formats a single table element (column, index, or constraint)
+// formatTableElement formats a single table element (column, index, constraint, or projection)
 func (f *Formatter) formatTableElement(element *parser.TableElement, maxWidth int) string {
 	if element.Column != nil {
 		return f.formatColumnWithoutComments(element.Column, maxWidth)
@@ -124,6 +124,9 @@ func (f *Formatter) formatTableElement(element *parser.TableElement, maxWidth in
 	if element.Constraint != nil {
 		return f.formatConstraintDefinition(element.Constraint)
 	}
+	if element.Projection != nil {
+		return f.formatProjectionDefinition(element.Projection)
+	}
 	return ""
 }
 
@@ -143,27 +146,27 @@ func (f *Formatter) writePreEngineComments(w io.Writer, lines []string, comments
 }
 
 // appendEngineAndClauses appends engine and table clauses to lines
-func (f *Formatter) appendEngineAndClauses(lines []string, stmt *parser.CreateTableStmt) []string {
+func (f *Formatter) appendEngineAndClauses(lines []string, engine *parser.TableEngine, clauses []parser.TableClause, comment *string) []string {
 	// Format table options
-	if stmt.Engine != nil {
-		lines = append(lines, f.keyword("ENGINE")+" = "+f.formatTableEngine(stmt.Engine))
+	if engine != nil {
+		lines = append(lines, f.keyword("ENGINE")+" = "+f.formatTableEngine(engine))
 
 		// Handle trailing comments from engine
-		if len(stmt.Engine.TrailingComments) > 0 {
-			lines = append(lines, stmt.Engine.TrailingComments...)
+		if len(engine.TrailingComments) > 0 {
+			lines = append(lines, engine.TrailingComments...)
 		}
 	}
 
 	// Sort clauses into canonical ClickHouse order before formatting
-	sortedClauses := f.sortTableClauses(stmt.Clauses)
+	sortedClauses := f.sortTableClauses(clauses)
 
 	// Format clauses with their comments
 	for _, clause := range sortedClauses {
 		lines = f.appendTableClause(lines, clause)
 	}
 
-	if stmt.Comment != nil {
-		lines = append(lines, f.keyword("COMMENT")+" "+*stmt.Comment)
+	if comment != nil {
+		lines = append(lines, f.keyword("COMMENT")+" "+*comment)
 	}
 
 	return lines
@@ -313,7 +316,8 @@ func (f *Formatter) alterTable(w io.Writer, stmt *parser.AlterTableStmt) error {
 	})
 }
 
-// AttachTable formats an ATTACH TABLE statement
+// AttachTable formats an ATTACH TABLE statement. A statement carrying the table's
+// definition is laid out like CREATE TABLE.
 func (f *Formatter) attachTable(w io.Writer, stmt *parser.AttachTableStmt) error {
 	return f.formatWithComments(w, stmt, func(w io.Writer) error {
 		ddl := NewDDLFormatter(f)
@@ -321,7 +325,28 @@ func (f *Formatter) attachTable(w io.Writer, stmt *parser.AttachTableStmt) error
 		parts := ddl.buildAttachStatement("TABLE", stmt.IfNotExists, f.qualifiedName(stmt.Database, stmt.Name))
 		parts = ddl.appendOnCluster(parts, stmt.OnCluster)
 
-		return ddl.formatBasicDDL(w, parts)
+		if len(stmt.Elements) == 0 && stmt.Engine == nil {
+			return ddl.formatBasicDDL(w, parts)
+		}
+
+		lines := []string{strings.Join(parts, " ")}
+		if len(stmt.Elements) > 0 {
+			lines[0] += " ("
+			lines = f.appendTableElements(lines, stmt.Elements)
+			lines = append(lines, ")")
+		}
+
+		if len(stmt.PreEngineComments) > 0 {
+			if err := f.writePreEngineComments(w, lines, stmt.PreEngineComments); err != nil {
+				return err
+			}
+			lines = lines[:0]
+		}
+
+		lines = f.appendEngineAndClauses(lines, stmt.Engine, stmt.Clauses, stmt.Comment)
+
+		_, err := w.Write([]byte(strings.Join(lines, "\n") + ";"))
+		return err
 	})
 }
 
@@ -352,6 +377,21 @@ func (f *Formatter) dropTable(w io.Writer, stmt *parser.DropTableStmt) error {
 	})
 }
 
+// truncateTable formats a TRUNCATE TABLE statement
+func (f *Formatter) truncateTable(w io.Writer, stmt *parser.TruncateTableStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		ddl := NewDDLFormatter(f)
+
+		parts := []string{f.keyword("TRUNCATE TABLE")}
+		parts = ddl.appendIfExists(parts, stmt.IfExists)
+		parts = append(parts, f.qualifiedName(stmt.Database, stmt.Name))
+		parts = ddl.appendOnCluster(parts, stmt.OnCluster)
+		parts = ddl.appendSync(parts, stmt.Sync)
+
+		return ddl.formatBasicDDL(w, parts)
+	})
+}
+
 // RenameTable formats a RENAME TABLE statement
 func (f *Formatter) renameTable(w io.Writer, stmt *parser.RenameTableStmt) error {
 	return f.formatWithComments(w, stmt, func(w io.Writer) error {
@@ -370,6 +410,18 @@ func (f *Formatter) renameTable(w io.Writer, stmt *parser.RenameTableStmt) error
 	})
 }
 
+// exchangeTables formats an EXCHANGE TABLES statement
+func (f *Formatter) exchangeTables(w io.Writer, stmt *parser.ExchangeTablesStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		ddl := NewDDLFormatter(f)
+		parts := ddl.buildExchangeStatement("TABLES",
+			f.qualifiedName(stmt.FirstDatabase, stmt.FirstName),
+			f.qualifiedName(stmt.SecondDatabase, stmt.SecondName),
+			stmt.OnCluster)
+		return ddl.formatBasicDDL(w, parts)
+	})
+}
+
 // formatColumn formats a single column definition with leading and trailing comments
 func (f *Formatter) formatColumn(col *parser.Column, alignWidth int) string {
 	var parts []string
@@ -387,16 +439,17 @@ func (f *Formatter) formatColumn(col *parser.Column, alignWidth int) string {
 	}
 	parts = append(parts, name)
 
-	// Data type
-	parts = append(parts, f.formatDataType(col.DataType))
+	// Data type (may be omitted when inferred from the default expression)
+	if col.DataType != nil {
+		parts = append(parts, f.formatDataType(col.DataType))
+	}
 
 	// Note: ClickHouse columns don't have NULL/NOT NULL constraints like other databases
 	// Instead they use Nullable(T) data types
 
 	// Default value
 	if defaultClause := col.GetDefault(); defaultClause != nil {
-		parts = append(parts, f.keyword(defaultClause.Type))
-		parts = append(parts, f.formatExpression(&defaultClause.Expression))
+		parts = append(parts, f.formatDefaultClause(defaultClause)...)
 	}
 
 	// Codec
@@ -414,6 +467,11 @@ func (f *Formatter) formatColumn(col *parser.Column, alignWidth int) string {
 		parts = append(parts, f.keyword("COMMENT"), *comment)
 	}
 
+	// Per-column settings
+	if settings := col.GetSettings(); settings != nil {
+		parts = append(parts, f.formatColumnSettings(settings))
+	}
+
 	// Trailing comments (inline with column)
 	if len(col.TrailingComments) > 0 {
 		parts = append(parts, col.TrailingComments...)
@@ -434,13 +492,14 @@ func (f *Formatter) formatColumnWithoutComments(col *parser.Column, alignWidth i
 	}
 	parts = append(parts, name)
 
-	// Data type
-	parts = append(parts, f.formatDataType(col.DataType))
+	// Data type (may be omitted when inferred from the default expression)
+	if col.DataType != nil {
+		parts = append(parts, f.formatDataType(col.DataType))
+	}
 
 	// Default value
 	if defaultClause := col.GetDefault(); defaultClause != nil {
-		parts = append(parts, f.keyword(defaultClause.Type))
-		parts = append(parts, f.formatExpression(&defaultClause.Expression))
+		parts = append(parts, f.formatDefaultClause(defaultClause)...)
 	}
 
 	// Codec
@@ -458,6 +517,11 @@ func (f *Formatter) formatColumnWithoutComments(col *parser.Column, alignWidth i
 		parts = append(parts, f.keyword("COMMENT"), *comment)
 	}
 
+	// Per-column settings
+	if settings := col.GetSettings(); settings != nil {
+		parts = append(parts, f.formatColumnSettings(settings))
+	}
+
 	return strings.Join(parts, " ")
 }
 
@@ -468,7 +532,7 @@ func (f *Formatter) formatIndexDefinition(idx *parser.IndexDefinition) string {
 	parts = append(parts, f.keyword("INDEX"))
 	parts = append(parts, f.identifier(idx.Name))
 	parts = append(parts, f.formatExpression(&idx.Expression))
-	parts = append(parts, f.keyword("TYPE"), idx.Type)
+	parts = append(parts, f.keyword("TYPE"), idx.IndexType.String())
 
 	if idx.Granularity != nil {
 		parts = append(parts, f.keyword("GRANULARITY"), *idx.Granularity)
@@ -489,6 +553,17 @@ func (f *Formatter) formatConstraintDefinition(constraint *parser.ConstraintDefi
 	return strings.Join(parts, " ")
 }
 
+// formatProjectionDefinition formats a projection definition
+func (f *Formatter) formatProjectionDefinition(projection *parser.ProjectionDefinition) string {
+	var parts []string
+
+	parts = append(parts, f.keyword("PROJECTION"))
+	parts = append(parts, f.identifier(projection.Name))
+	parts = append(parts, "("+f.formatSelectStatement(&projection.SelectClause.SelectStmt)+")")
+
+	return strings.Join(parts, " ")
+}
+
 // formatTableEngine formats a table engine specification
 func (f *Formatter) formatTableEngine(engine *parser.TableEngine) string {
 	if engine == nil {
@@ -566,15 +641,35 @@ func (f *Formatter) formatTableSettings(settings *parser.TableSettingsClause) st
 	var parts []string
 	parts = append(parts, f.keyword("SETTINGS"))
 
-	settingParts := make([]string, 0, len(settings.Settings))
-	for _, setting := range settings.Settings {
-		settingParts = append(settingParts, setting.Name+" = "+setting.Value)
+	tableSettings := settings.Settings
+	if f.options.Canonical {
+		tableSettings = sortedTableSettings(tableSettings)
+	}
+
+	settingParts := make([]string, 0, len(tableSettings))
+	for _, setting := range tableSettings {
+		settingParts = append(settingParts, setting.Name+" = "+f.formatTableSettingValue(&setting))
 	}
 	parts = append(parts, strings.Join(settingParts, ", "))
 
 	return strings.Join(parts, " ")
 }
 
+// formatColumnSettings formats a per-column SETTINGS clause
+func (f *Formatter) formatColumnSettings(settings *parser.ColumnSettingsClause) string {
+	columnSettings := settings.Settings
+	if f.options.Canonical {
+		columnSettings = sortedTableSettings(columnSettings)
+	}
+
+	settingParts := make([]string, 0, len(columnSettings))
+	for _, setting := range columnSettings {
+		settingParts = append(settingParts, setting.Name+" = "+f.formatTableSettingValue(&setting))
+	}
+
+	return f.keyword("SETTINGS") + " (" + strings.Join(settingParts, ", ") + ")"
+}
+
 // formatAlterOperation formats a single ALTER TABLE operation
 func (f *Formatter) formatAlterOperation(op *parser.AlterTableOperation) string {
 	switch {
@@ -588,6 +683,8 @@ func (f *Formatter) formatAlterOperation(op *parser.AlterTableOperation) string
 		return f.formatRenameColumn(op.RenameColumn)
 	case op.CommentColumn != nil:
 		return f.formatCommentColumn(op.CommentColumn)
+	case op.ModifyComment != nil:
+		return f.formatModifyComment(op.ModifyComment)
 	case op.ClearColumn != nil:
 		return f.formatClearColumn(op.ClearColumn)
 	case op.AddIndex != nil:
@@ -602,6 +699,8 @@ func (f *Formatter) formatAlterOperation(op *parser.AlterTableOperation) string
 		return f.formatModifyTTL(op.ModifyTTL)
 	case op.DeleteTTL != nil:
 		return f.formatDeleteTTL(op.DeleteTTL)
+	case op.RemoveTTL != nil:
+		return f.formatRemoveTTL(op.RemoveTTL)
 	case op.Update != nil:
 		return f.formatUpdate(op.Update)
 	case op.Delete != nil:
@@ -671,6 +770,16 @@ func (f *Formatter) formatDropColumn(op *parser.DropColumnOperation) string {
 	return strings.Join(parts, " ")
 }
 
+// formatDefaultClause formats a DEFAULT, MATERIALIZED, EPHEMERAL, or ALIAS clause,
+// omitting the expression of a bare EPHEMERAL
+func (f *Formatter) formatDefaultClause(clause *parser.DefaultClause) []string {
+	parts := []string{f.keyword(clause.Type)}
+	if clause.Expression != nil {
+		parts = append(parts, f.formatExpression(clause.Expression))
+	}
+	return parts
+}
+
 func (f *Formatter) formatModifyColumn(op *parser.ModifyColumnOperation) string {
 	var parts []string
 	parts = append(parts, f.keyword("MODIFY COLUMN"))
@@ -684,8 +793,7 @@ func (f *Formatter) formatModifyColumn(op *parser.ModifyColumnOperation) string
 		parts = append(parts, f.formatDataType(op.Type))
 	}
 	if op.Default != nil {
-		parts = append(parts, f.keyword(op.Default.Type))
-		parts = append(parts, f.formatExpression(&op.Default.Expression))
+		parts = append(parts, f.formatDefaultClause(op.Default)...)
 	}
 	if op.Codec != nil {
 		parts = append(parts, f.formatCodec(op.Codec))
@@ -695,8 +803,10 @@ func (f *Formatter) formatModifyColumn(op *parser.ModifyColumnOperation) string
 		parts = append(parts, f.formatExpression(op.TTL))
 	}
 	if op.Comment != nil {
-		parts = append(parts, f.keyword("COMMENT"))
-		parts = append(parts, "'"+*op.Comment+"'")
+		parts = append(parts, f.keyword("COMMENT"), *op.Comment)
+	}
+	if op.Settings != nil {
+		parts = append(parts, f.formatColumnSettings(op.Settings))
 	}
 	return strings.Join(parts, " ")
 }
@@ -725,6 +835,10 @@ func (f *Formatter) formatCommentColumn(op *parser.CommentColumnOperation) strin
 	return strings.Join(parts, " ")
 }
 
+func (f *Formatter) formatModifyComment(op *parser.ModifyCommentOperation) string {
+	return f.keyword("MODIFY COMMENT") + " " + op.Comment
+}
+
 func (f *Formatter) formatClearColumn(op *parser.ClearColumnOperation) string {
 	var parts []string
 	parts = append(parts, f.keyword("CLEAR COLUMN"))
@@ -756,9 +870,7 @@ func (f *Formatter) formatAddIndex(op *parser.AddIndexOperation) string {
 	parts = append(parts, f.identifier(op.Name))
 	parts = append(parts, f.formatExpression(&op.Expression))
 
-	if op.Type != "" {
-		parts = append(parts, f.keyword("TYPE"), f.identifier(op.Type))
-	}
+	parts = append(parts, f.keyword("TYPE"), op.IndexType.String())
 
 	if op.Granularity != "" {
 		parts = append(parts, f.keyword("GRANULARITY"), op.Granularity)
@@ -861,6 +973,15 @@ func (f *Formatter) formatDeleteTTL(op *parser.DeleteTTLOperation) string {
 	return f.keyword("DELETE TTL")
 }
 
+// formatRemoveTTL formats REMOVE TTL operations
+func (f *Formatter) formatRemoveTTL(op *parser.RemoveTTLOperation) string {
+	if op == nil {
+		return ""
+	}
+
+	return f.keyword("REMOVE TTL")
+}
+
 // formatUpdate formats UPDATE operations
 func (f *Formatter) formatUpdate(op *parser.UpdateOperation) string {
 	if op == nil {
@@ -1071,11 +1192,29 @@ func (f *Formatter) formatModifySetting(op *parser.ModifySettingOperation) strin
 	parts = append(parts, f.keyword("MODIFY SETTING"))
 	parts = append(parts, f.identifier(op.Setting.Name))
 	parts = append(parts, "=")
-	parts = append(parts, op.Setting.Value)
+	parts = append(parts, f.formatTableSettingValue(&op.Setting))
 
 	return strings.Join(parts, " ")
 }
 
+// formatTableSettingValue formats a setting value, including array, map, and tuple values
+func (f *Formatter) formatTableSettingValue(setting *parser.TableSetting) string {
+	if setting.Compound == nil {
+		return setting.Value
+	}
+
+	switch {
+	case setting.Compound.Array != nil:
+		return f.formatArrayExpression(setting.Compound.Array)
+	case setting.Compound.Map != nil:
+		return f.formatMapExpression(setting.Compound.Map)
+	case setting.Compound.Tuple != nil:
+		return f.formatTupleExpression(setting.Compound.Tuple)
+	default:
+		return setting.GetValue()
+	}
+}
+
 // formatResetSetting formats RESET SETTING operations
 func (f *Formatter) formatResetSetting(op *parser.ResetSettingOperation) string {
 	if op == nil {
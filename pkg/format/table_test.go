@@ -111,7 +111,7 @@ func TestFormatter_alterTable(t *testing.T) {
 			sql:  "ALTER TABLE users ADD INDEX idx_email email TYPE minmax GRANULARITY 1;",
 			expected: []string{
 				"ALTER TABLE `users`",
-				"    ADD INDEX `idx_email` `email` TYPE `minmax` GRANULARITY 1;",
+				"    ADD INDEX `idx_email` `email` TYPE minmax GRANULARITY 1;",
 			},
 		},
 		{
@@ -119,7 +119,7 @@ func TestFormatter_alterTable(t *testing.T) {
 			sql:  "ALTER TABLE users ADD INDEX IF NOT EXISTS idx_name name TYPE minmax GRANULARITY 1;",
 			expected: []string{
 				"ALTER TABLE `users`",
-				"    ADD INDEX IF NOT EXISTS `idx_name` `name` TYPE `minmax` GRANULARITY 1;",
+				"    ADD INDEX IF NOT EXISTS `idx_name` `name` TYPE minmax GRANULARITY 1;",
 			},
 		},
 		{
@@ -1,5 +1,4 @@
 -- Complex SELECT statements
-
 SELECT
     `user_id`,
     toDate(`timestamp`) AS `date`,
@@ -5,6 +5,9 @@ CREATE DATABASE IF NOT EXISTS warehouse ON CLUSTER production ENGINE = Materiali
 
 ALTER DATABASE analytics MODIFY COMMENT 'Updated analytics database';
 
+ALTER DATABASE replicated_db MODIFY SETTING max_broken_tables_ratio=0.5, max_replication_lag_to_enqueue=50;
+
 RENAME DATABASE old_analytics TO analytics, temp_warehouse TO warehouse;
 
-DROP DATABASE IF EXISTS legacy_db ON CLUSTER production SYNC;
\ No newline at end of file
+DROP DATABASE IF EXISTS legacy_db ON CLUSTER production SYNC;
+CREATE DATABASE replicated_db ENGINE = Replicated('/clickhouse/databases/replicated_db', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio=0.5 COMMENT 'Replicated database';
@@ -1,11 +1,14 @@
 -- Database operations with various features
-
 CREATE DATABASE `analytics` ENGINE = Atomic COMMENT 'Analytics database';
 
 CREATE DATABASE IF NOT EXISTS `warehouse` ON CLUSTER `production` ENGINE = MaterializedMySQL('localhost:3306', 'warehouse', 'user', 'password') COMMENT 'Data warehouse';
 
 ALTER DATABASE `analytics` MODIFY COMMENT 'Updated analytics database';
 
+ALTER DATABASE `replicated_db` MODIFY SETTING max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;
+
 RENAME DATABASE `old_analytics` TO `analytics`, `temp_warehouse` TO `warehouse`;
 
 DROP DATABASE IF EXISTS `legacy_db` ON CLUSTER `production` SYNC;
+
+CREATE DATABASE `replicated_db` ENGINE = Replicated('/clickhouse/databases/replicated_db', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5 COMMENT 'Replicated database';
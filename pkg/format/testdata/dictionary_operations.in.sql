@@ -19,4 +19,6 @@ parent_id UInt32 DEFAULT 0
 ) PRIMARY KEY category_id
 SOURCE(CLICKHOUSE(host 'localhost', port 9000, db 'warehouse', table 'categories', user 'default', password ''))
 LAYOUT(HASHED())
-LIFETIME(3600);
\ No newline at end of file
+LIFETIME(3600);
+-- Dictionary with hierarchical and bidirectional attributes
+CREATE DICTIONARY analytics.regions (id UInt64 IS_OBJECT_ID, parent_id UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL, name String INJECTIVE) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);
@@ -1,5 +1,4 @@
 -- Dictionary operations with complex configurations
-
 CREATE DICTIONARY `analytics`.`users_dict` (
     `id`         UInt64 IS_OBJECT_ID,
     `name`       String INJECTIVE,
@@ -23,3 +22,14 @@ PRIMARY KEY `category_id`
 SOURCE(CLICKHOUSE(host 'localhost' port 9000 db 'warehouse' table 'categories' user 'default' password ''))
 LAYOUT(HASHED())
 LIFETIME(3600);
+
+-- Dictionary with hierarchical and bidirectional attributes
+CREATE DICTIONARY `analytics`.`regions` (
+    `id`        UInt64 IS_OBJECT_ID,
+    `parent_id` UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL,
+    `name`      String INJECTIVE
+)
+PRIMARY KEY `id`
+SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow'))
+LAYOUT(HASHED())
+LIFETIME(3600);
@@ -42,4 +42,8 @@ DROP FUNCTION normalizedBrowser ON CLUSTER warehouse;
 DROP FUNCTION IF EXISTS `special-name`;
 
 -- DROP FUNCTION with ON CLUSTER and backticks
-DROP FUNCTION `cluster-func` ON CLUSTER `production-cluster`;
\ No newline at end of file
+DROP FUNCTION `cluster-func` ON CLUSTER `production-cluster`;
+-- Multi-argument lambdas with infix operators
+CREATE FUNCTION linear_eq AS (x, k, b) -> k*x + b;
+CREATE OR REPLACE FUNCTION linear_eq ON CLUSTER warehouse AS (x, k, b) -> k * x + b;
+CREATE FUNCTION IF NOT EXISTS clamp AS (value, low, high) -> greatest(low, least(value, high));
@@ -1,5 +1,4 @@
 -- Mixed DDL operations
-
 CREATE DATABASE `analytics` ENGINE = Atomic;
 
 CREATE TABLE `analytics`.`raw_events` (
@@ -0,0 +1,33 @@
+-- Analytics schema
+-- Owned by the data team
+
+/*
+ * Raw events, partitioned by month.
+ * Retained for 90 days.
+ */
+CREATE TABLE `analytics`.`events` (
+    `id` UInt64,
+    `ts` DateTime
+)
+ENGINE = MergeTree()
+ORDER BY `id`
+PARTITION BY toYYYYMM(`ts`); -- hot table
+
+-- Users are looked up by id
+CREATE DICTIONARY `analytics`.`users_dict` (
+    `id`   UInt64,
+    `name` String
+)
+PRIMARY KEY `id`
+SOURCE(HTTP(url 'http://localhost/users.json' format 'JSONEachRow'))
+LAYOUT(HASHED())
+LIFETIME(3600); /* refreshed hourly */
+
+-- Section separated from the next statement by a blank line
+
+CREATE VIEW `analytics`.`recent_events`
+AS SELECT *
+FROM `analytics`.`events`
+WHERE `ts` > now() - INTERVAL 1 DAY;
+
+-- Trailing notes on their own line
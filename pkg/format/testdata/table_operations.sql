@@ -1,5 +1,4 @@
 -- Table operations with complex features
-
 CREATE TABLE `analytics`.`events` (
     `id`         UInt64,
     `user_id`    UInt64,
@@ -29,5 +28,5 @@ COMMENT 'Product catalog table';
 
 ALTER TABLE `analytics`.`events`
     ADD COLUMN `session_id` String DEFAULT '' AFTER `user_id`,
-    MODIFY COLUMN `metadata` String COMMENT ''Updated metadata column'',
+    MODIFY COLUMN `metadata` String COMMENT 'Updated metadata column',
     DROP COLUMN `version`;
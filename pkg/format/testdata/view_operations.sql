@@ -1,5 +1,4 @@
 -- View operations with SELECT statements
-
 CREATE VIEW `analytics`.`daily_summary`
 AS SELECT
     toDate(`timestamp`) AS `date`,
@@ -28,3 +27,59 @@ AS SELECT
     count() AS `cnt`
 FROM `analytics`.`events`
 GROUP BY `hour`, `event_type`;
+
+-- View with ordered aggregates
+CREATE VIEW `analytics`.`user_journeys`
+AS SELECT
+    `user_id`,
+    groupArray(`page` ORDER BY `ts`) AS `pages`,
+    groupArray(`event` ORDER BY `ts` DESC NULLS LAST, `id`) AS `recent_events`
+FROM `analytics`.`events`
+GROUP BY `user_id`;
+
+-- Materialized view with PREWHERE
+CREATE MATERIALIZED VIEW `analytics`.`mv_completed_orders`
+TO `analytics`.`completed_orders`
+AS SELECT
+    `id`,
+    `user_id`,
+    `amount`
+FROM `analytics`.`orders`
+PREWHERE `event_date` >= '2024-01-01'
+WHERE `status` = 'completed';
+
+-- Materialized views with ARRAY JOIN
+CREATE MATERIALIZED VIEW `analytics`.`mv_event_tags`
+TO `analytics`.`event_tags`
+AS SELECT
+    `id`,
+    `tag`,
+    `idx`
+FROM `analytics`.`events`
+ARRAY JOIN `tags` AS `tag`, arrayEnumerate(`tags`) AS `idx`;
+
+CREATE MATERIALIZED VIEW `analytics`.`mv_session_pages`
+TO `analytics`.`session_pages`
+AS SELECT
+    `session_id`,
+    `url`
+FROM `analytics`.`sessions`
+LEFT ARRAY JOIN `urls` AS `url`
+WHERE `session_id` > 0;
+
+-- Materialized view with CASE expression
+CREATE MATERIALIZED VIEW `analytics`.`mv_order_tiers`
+TO `analytics`.`order_tiers`
+AS SELECT
+    `id`,
+    CASE WHEN `amount` >= 1000 THEN 'large' WHEN `amount` >= 100 THEN concat('medium-', `status`) ELSE 'small' END AS `tier`
+FROM `analytics`.`orders`;
+
+-- Materialized view with higher-order functions
+CREATE MATERIALIZED VIEW `analytics`.`mv_event_tags`
+TO `analytics`.`event_tags`
+AS SELECT
+    `id`,
+    arrayMap(`t` -> lower(`t`), `tags`) AS `tags`,
+    arrayFilter((`t`, `w`) -> `w` > 0.5, `tags`, `weights`) AS `strong_tags`
+FROM `analytics`.`events`;
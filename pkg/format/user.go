@@ -0,0 +1,183 @@
+package format
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// createUser formats a CREATE USER statement
+func (f *Formatter) createUser(w io.Writer, stmt *parser.CreateUserStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		var parts []string
+
+		// CREATE [OR REPLACE] USER [IF NOT EXISTS]
+		if stmt.OrReplace {
+			parts = append(parts, f.keyword("CREATE OR REPLACE USER"))
+		} else {
+			parts = append(parts, f.keyword("CREATE USER"))
+			if stmt.IfNotExists {
+				parts = append(parts, f.keyword("IF NOT EXISTS"))
+			}
+		}
+
+		// User names
+		names := make([]string, len(stmt.Names))
+		for i, name := range stmt.Names {
+			names[i] = f.identifier(name)
+		}
+		parts = append(parts, strings.Join(names, ", "))
+
+		// ON CLUSTER
+		if stmt.OnCluster != nil {
+			parts = append(parts, f.keyword("ON CLUSTER"), f.identifier(*stmt.OnCluster))
+		}
+
+		// Authentication, hosts, defaults, and settings
+		parts = append(parts, f.formatUserOptions(&stmt.Options)...)
+
+		_, err := w.Write([]byte(strings.Join(parts, " ") + ";"))
+		return err
+	})
+}
+
+// alterUser formats an ALTER USER statement
+func (f *Formatter) alterUser(w io.Writer, stmt *parser.AlterUserStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		var parts []string
+
+		// ALTER USER
+		parts = append(parts, f.keyword("ALTER USER"))
+
+		// IF EXISTS
+		if stmt.IfExists {
+			parts = append(parts, f.keyword("IF EXISTS"))
+		}
+
+		// User name
+		parts = append(parts, f.identifier(stmt.Name))
+
+		// ON CLUSTER
+		if stmt.OnCluster != nil {
+			parts = append(parts, f.keyword("ON CLUSTER"), f.identifier(*stmt.OnCluster))
+		}
+
+		// RENAME TO
+		if stmt.RenameTo != nil {
+			parts = append(parts, f.keyword("RENAME TO"), f.identifier(*stmt.RenameTo))
+		}
+
+		// Authentication, hosts, defaults, and settings
+		parts = append(parts, f.formatUserOptions(&stmt.Options)...)
+
+		_, err := w.Write([]byte(strings.Join(parts, " ") + ";"))
+		return err
+	})
+}
+
+// dropUser formats a DROP USER statement
+func (f *Formatter) dropUser(w io.Writer, stmt *parser.DropUserStmt) error {
+	return f.formatWithComments(w, stmt, func(w io.Writer) error {
+		ddl := NewDDLFormatter(f)
+
+		// Format user names as comma-separated list
+		names := make([]string, len(stmt.Names))
+		for i, name := range stmt.Names {
+			names[i] = f.identifier(name)
+		}
+
+		parts := ddl.buildDropStatement("USER", stmt.IfExists, strings.Join(names, ", "))
+		parts = ddl.appendOnCluster(parts, stmt.OnCluster)
+
+		return ddl.formatBasicDDL(w, parts)
+	})
+}
+
+// formatUserOptions formats the clauses shared by CREATE USER and ALTER USER in their
+// canonical order, regardless of the order they were written in
+func (f *Formatter) formatUserOptions(opts *parser.UserOptions) []string {
+	var parts []string
+
+	if auth := opts.GetAuthentication(); auth != nil {
+		parts = append(parts, f.formatUserAuthentication(auth))
+	}
+
+	if hosts := opts.GetHosts(); hosts != nil {
+		parts = append(parts, f.formatUserHosts(hosts))
+	}
+
+	if validUntil := opts.GetValidUntil(); validUntil != nil {
+		parts = append(parts, f.keyword("VALID UNTIL"), *validUntil)
+	}
+
+	if defaultRole := opts.GetDefaultRole(); defaultRole != nil {
+		parts = append(parts, f.keyword("DEFAULT ROLE"))
+		switch {
+		case defaultRole.None:
+			parts = append(parts, f.keyword("NONE"))
+		case defaultRole.AllExcept != nil:
+			parts = append(parts, f.keyword("ALL EXCEPT"), f.formatRoleList(defaultRole.AllExcept))
+		case defaultRole.All:
+			parts = append(parts, f.keyword("ALL"))
+		case defaultRole.Roles != nil:
+			parts = append(parts, f.formatRoleList(defaultRole.Roles))
+		}
+	}
+
+	if database := opts.GetDefaultDatabase(); database != nil {
+		parts = append(parts, f.keyword("DEFAULT DATABASE"), f.identifier(*database))
+	}
+
+	if settings := opts.GetSettings(); settings != nil {
+		parts = append(parts, f.formatRoleSettings(settings))
+	}
+
+	return parts
+}
+
+// formatUserAuthentication formats a NOT IDENTIFIED or IDENTIFIED clause
+func (f *Formatter) formatUserAuthentication(auth *parser.UserAuthentication) string {
+	if auth.NotIdentified {
+		return f.keyword("NOT IDENTIFIED")
+	}
+
+	parts := []string{f.keyword("IDENTIFIED")}
+	if auth.Method != nil {
+		parts = append(parts, f.keyword("WITH"), strings.ToLower(*auth.Method))
+	}
+	if auth.Secret != nil {
+		parts = append(parts, f.keyword("BY"), *auth.Secret)
+	}
+	if auth.Server != nil {
+		parts = append(parts, f.keyword("SERVER"), *auth.Server)
+	}
+	if auth.Realm != nil {
+		parts = append(parts, f.keyword("REALM"), *auth.Realm)
+	}
+	if auth.CommonName != nil {
+		parts = append(parts, f.keyword("CN"), *auth.CommonName)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatUserHosts formats a HOST clause
+func (f *Formatter) formatUserHosts(hosts *parser.UserHosts) string {
+	switch {
+	case hosts.Any:
+		return f.keyword("HOST ANY")
+	case hosts.None:
+		return f.keyword("HOST NONE")
+	}
+
+	rules := make([]string, len(hosts.Rules))
+	for i, rule := range hosts.Rules {
+		rules[i] = f.keyword(strings.ToUpper(rule.Kind))
+		if rule.Value != nil {
+			rules[i] += " " + *rule.Value
+		}
+	}
+
+	return f.keyword("HOST") + " " + strings.Join(rules, ", ")
+}
@@ -60,6 +60,11 @@ func (f *Formatter) createView(w io.Writer, stmt *parser.CreateViewStmt) error {
 			lines = append(lines, f.keyword("POPULATE"))
 		}
 
+		// DEFINER and SQL SECURITY
+		if security := f.formatViewSecurity(stmt); security != "" {
+			lines = append(lines, security)
+		}
+
 		// AS SELECT
 		if stmt.AsSelect != nil {
 			lines = append(lines, f.keyword("AS")+" "+f.formatSelectStatement(stmt.AsSelect))
@@ -70,6 +75,22 @@ func (f *Formatter) createView(w io.Writer, stmt *parser.CreateViewStmt) error {
 	})
 }
 
+// formatViewSecurity formats the DEFINER and SQL SECURITY clauses of a view
+func (f *Formatter) formatViewSecurity(stmt *parser.CreateViewStmt) string {
+	var parts []string
+	if stmt.Definer != nil {
+		definer := f.identifier(*stmt.Definer)
+		if strings.EqualFold(*stmt.Definer, "CURRENT_USER") {
+			definer = f.keyword("CURRENT_USER")
+		}
+		parts = append(parts, f.keyword("DEFINER")+" = "+definer)
+	}
+	if stmt.SQLSecurity != nil {
+		parts = append(parts, f.keyword("SQL SECURITY")+" "+f.keyword(*stmt.SQLSecurity))
+	}
+	return strings.Join(parts, " ")
+}
+
 // AttachView formats an ATTACH VIEW statement
 func (f *Formatter) attachView(w io.Writer, stmt *parser.AttachViewStmt) error {
 	return f.formatWithComments(w, stmt, func(w io.Writer) error {
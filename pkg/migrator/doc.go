@@ -52,14 +52,8 @@
 //		log.Fatal(err)
 //	}
 //
-//	// Write updated sum file
-//	file, err := os.Create("migrations.sum")
-//	if err != nil {
-//		log.Fatal(err)
-//	}
-//	defer file.Close()
-//
-//	_, err = migDir.SumFile.WriteTo(file)
+//	// Write updated sum file (atomically, so a crash can't leave it truncated)
+//	err = migDir.WriteSumFile("migrations/housekeeper.sum")
 //	if err != nil {
 //		log.Fatal(err)
 //	}
@@ -83,6 +77,22 @@
 //		log.Fatal(err)
 //	}
 //
+// # Remote Migrations
+//
+// LoadMigrationDir accepts any fs.FS, so migrations distributed as an artifact can
+// be loaded without copying them to disk first. HTTPFS serves a migration directory
+// from an HTTP(S) endpoint, using its housekeeper.sum to learn which files it holds:
+//
+//	remote, err := migrator.NewHTTPFS(ctx, "https://artifacts.example.com/migrations/v1.2.0/")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	migDir, err := migrator.LoadMigrationDir(remote)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
 // # Integrity Verification
 //
 // The SumFile type implements a reverse one-branch Merkle tree using chained
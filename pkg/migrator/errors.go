@@ -0,0 +1,88 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCorruptSumFile is returned when a sum file can't be trusted because it is
+// malformed or its total hash doesn't match its entries, which usually means the
+// file was truncated by an interrupted write. Running `housekeeper rehash`
+// regenerates it.
+//
+// Example:
+//
+//	if errors.Is(err, migrator.ErrCorruptSumFile) {
+//		fmt.Println("sum file is corrupt, run housekeeper rehash")
+//	}
+var ErrCorruptSumFile = errors.New("corrupt sum file")
+
+type (
+	// ErrIntegrityMismatch is returned when migration content no longer matches
+	// the hashes recorded for it, either in the sum file or in a revision record.
+	// This usually means a migration file was edited after it was generated or applied.
+	//
+	// Example:
+	//
+	//	var mismatch *migrator.ErrIntegrityMismatch
+	//	if errors.As(err, &mismatch) {
+	//		fmt.Printf("migration %s was modified\n", mismatch.Version)
+	//	}
+	ErrIntegrityMismatch struct {
+		// Version of the offending migration (empty when the whole directory failed validation)
+		Version string
+
+		// Statement is the 1-based index of the mismatched statement (0 if not statement-specific)
+		Statement int
+
+		// Expected is the recorded hash (if known)
+		Expected string
+
+		// Actual is the hash computed from the current content (if known)
+		Actual string
+
+		// Reason describes what didn't match
+		Reason string
+	}
+
+	// ErrMigrationLocked is returned when migrations cannot be applied because
+	// another process currently holds the migration lock.
+	//
+	// Example:
+	//
+	//	var locked *migrator.ErrMigrationLocked
+	//	if errors.As(err, &locked) {
+	//		fmt.Printf("migrations locked by %s, retry later\n", locked.Holder)
+	//	}
+	ErrMigrationLocked struct {
+		// Holder identifies the process holding the lock (if known)
+		Holder string
+	}
+)
+
+// Error implements the error interface.
+func (e *ErrIntegrityMismatch) Error() string {
+	msg := "integrity check failed"
+	if e.Version != "" {
+		msg += " for migration " + e.Version
+	}
+	if e.Statement > 0 {
+		msg += fmt.Sprintf(" (statement %d)", e.Statement)
+	}
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	if e.Expected != "" || e.Actual != "" {
+		msg += fmt.Sprintf(" (expected %s, got %s)", e.Expected, e.Actual)
+	}
+	return msg
+}
+
+// Error implements the error interface.
+func (e *ErrMigrationLocked) Error() string {
+	if e.Holder == "" {
+		return "migrations are locked by another process"
+	}
+	return "migrations are locked by " + e.Holder
+}
@@ -0,0 +1,36 @@
+package migrator_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrIntegrityMismatch(t *testing.T) {
+	err := errors.Wrap(&migrator.ErrIntegrityMismatch{
+		Version:   "20240101120000",
+		Statement: 2,
+		Expected:  "h1:abc=",
+		Actual:    "h1:def=",
+		Reason:    "statement modified",
+	}, "failed to execute migration")
+
+	var mismatch *migrator.ErrIntegrityMismatch
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, "20240101120000", mismatch.Version)
+	require.Equal(t, 2, mismatch.Statement)
+	require.EqualError(t, mismatch,
+		"integrity check failed for migration 20240101120000 (statement 2): statement modified (expected h1:abc=, got h1:def=)")
+}
+
+func TestErrMigrationLocked(t *testing.T) {
+	err := errors.Wrap(&migrator.ErrMigrationLocked{Holder: "ci-runner-1"}, "failed to apply migrations")
+
+	var locked *migrator.ErrMigrationLocked
+	require.ErrorAs(t, err, &locked)
+	require.Equal(t, "ci-runner-1", locked.Holder)
+	require.EqualError(t, locked, "migrations are locked by ci-runner-1")
+	require.EqualError(t, &migrator.ErrMigrationLocked{}, "migrations are locked by another process")
+}
@@ -0,0 +1,280 @@
+package migrator
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sumFileName is the name of the sum file stored alongside the migrations in a
+// migration directory.
+const sumFileName = "housekeeper.sum"
+
+type (
+	// HTTPFS is a read-only fs.FS serving a migration directory from an HTTP(S)
+	// endpoint, such as a release artifact server or an S3 bucket exposed over HTTPS.
+	//
+	// HTTP has no standard way to list a directory, so the directory's housekeeper.sum
+	// acts as its manifest: it is fetched when the HTTPFS is created and lists every
+	// migration file in the directory. Files are fetched on first use and cached, since
+	// LoadMigrationDir and Validate open each migration more than once.
+	//
+	// Example usage:
+	//
+	//	remote, err := migrator.NewHTTPFS(ctx, "https://artifacts.example.com/migrations/v1.2.0/")
+	//	if err != nil {
+	//		log.Fatal(err)
+	//	}
+	//
+	//	migDir, err := migrator.LoadMigrationDir(remote)
+	//	if err != nil {
+	//		log.Fatal(err)
+	//	}
+	HTTPFS struct {
+		ctx     context.Context
+		baseURL *url.URL
+		opts    HTTPFSOptions
+		files   []string // File names listed in the manifest, sorted
+
+		mu    sync.Mutex
+		cache map[string][]byte
+	}
+
+	// HTTPFSOptions configures how an HTTPFS fetches files.
+	HTTPFSOptions struct {
+		// Client is the HTTP client used for requests (defaults to http.DefaultClient)
+		Client *http.Client
+		// Header is added to every request, e.g. for an Authorization header
+		Header http.Header
+	}
+
+	// httpFile is an open file read from an HTTPFS
+	httpFile struct {
+		*bytes.Reader
+		info httpFileInfo
+	}
+
+	// httpDir is the open root directory of an HTTPFS
+	httpDir struct {
+		entries []fs.DirEntry
+		offset  int
+	}
+
+	// httpDirEntry is a file listed in the root directory of an HTTPFS
+	httpDirEntry struct {
+		fs   *HTTPFS
+		name string
+	}
+
+	// httpFileInfo describes a file or the root directory of an HTTPFS
+	httpFileInfo struct {
+		name  string
+		size  int64
+		isDir bool
+	}
+)
+
+// NewHTTPFS creates an HTTPFS for the migration directory at baseURL using the
+// default HTTP client. See NewHTTPFSWithOptions.
+func NewHTTPFS(ctx context.Context, baseURL string) (*HTTPFS, error) {
+	return NewHTTPFSWithOptions(ctx, baseURL, HTTPFSOptions{})
+}
+
+// NewHTTPFSWithOptions creates an HTTPFS for the migration directory at baseURL and
+// fetches its housekeeper.sum to learn which migrations it contains.
+//
+// fs.FS methods don't take a context, so every request made by the returned HTTPFS
+// uses ctx. Returns an error if the sum file can't be fetched or parsed, or if it
+// lists a file outside of the directory.
+func NewHTTPFSWithOptions(ctx context.Context, baseURL string, opts HTTPFSOptions) (*HTTPFS, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid migrations URL: %s", baseURL)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, errors.Errorf("unsupported migrations URL scheme %q: must be http or https", base.Scheme)
+	}
+	// Resolve file names relative to the directory rather than replacing its last segment
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+
+	hfs := &HTTPFS{
+		ctx:     ctx,
+		baseURL: base,
+		opts:    opts,
+		cache:   make(map[string][]byte),
+	}
+
+	content, err := hfs.fetch(sumFileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch migration manifest")
+	}
+	sumFile, err := LoadSumFile(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load sum file from: %s", base.JoinPath(sumFileName))
+	}
+
+	hfs.files = []string{sumFileName}
+	for _, entry := range sumFile.entries {
+		if !fs.ValidPath(entry.version) || strings.Contains(entry.version, "/") {
+			return nil, errors.Errorf("sum file lists %q, which is not a file in the migration directory", entry.version)
+		}
+		hfs.files = append(hfs.files, entry.version)
+	}
+	slices.Sort(hfs.files)
+
+	return hfs, nil
+}
+
+// Open opens the named file, fetching it from the remote directory if it hasn't
+// been read yet. Only the root directory (".") and files listed in the sum file exist.
+func (h *HTTPFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		entries, err := h.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &httpDir{entries: entries}, nil
+	}
+
+	if _, found := slices.BinarySearch(h.files, name); !found {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	content, err := h.fetch(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &httpFile{
+		Reader: bytes.NewReader(content),
+		info:   httpFileInfo{name: name, size: int64(len(content))},
+	}, nil
+}
+
+// ReadDir lists the files in the root directory, sorted by name. Like os.DirEntry,
+// an entry's Info is loaded on demand, which fetches the file.
+func (h *HTTPFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		if !fs.ValidPath(name) {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(h.files))
+	for _, file := range h.files {
+		entries = append(entries, httpDirEntry{fs: h, name: file})
+	}
+	return entries, nil
+}
+
+// fetch returns the content of the named file, requesting it on first use
+func (h *HTTPFS) fetch(name string) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if content, ok := h.cache[name]; ok {
+		return content, nil
+	}
+
+	fileURL := h.baseURL.JoinPath(name)
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodGet, fileURL.String(), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request for: %s", fileURL)
+	}
+	for key, values := range h.opts.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := h.opts.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch: %s", fileURL)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.Wrapf(fs.ErrNotExist, "%s returned %s", fileURL, resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, errors.Errorf("%s returned %s", fileURL, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read: %s", fileURL)
+	}
+
+	h.cache[name] = content
+	return content, nil
+}
+
+func (f *httpFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *httpFile) Close() error               { return nil }
+
+func (d *httpDir) Stat() (fs.FileInfo, error) { return httpFileInfo{name: ".", isDir: true}, nil }
+func (d *httpDir) Close() error               { return nil }
+
+func (d *httpDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile, following the paging semantics of os.File.ReadDir
+func (d *httpDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+func (e httpDirEntry) Name() string      { return e.name }
+func (e httpDirEntry) IsDir() bool       { return false }
+func (e httpDirEntry) Type() fs.FileMode { return 0 }
+
+func (e httpDirEntry) Info() (fs.FileInfo, error) {
+	content, err := e.fs.fetch(e.name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: e.name, Err: err}
+	}
+	return httpFileInfo{name: e.name, size: int64(len(content))}, nil
+}
+
+func (i httpFileInfo) Name() string       { return i.name }
+func (i httpFileInfo) Size() int64        { return i.size }
+func (i httpFileInfo) ModTime() time.Time { return time.Time{} }
+func (i httpFileInfo) IsDir() bool        { return i.isDir }
+func (i httpFileInfo) Sys() any           { return nil }
+
+func (i httpFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
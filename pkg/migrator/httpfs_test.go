@@ -0,0 +1,161 @@
+package migrator_test
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFS(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_init.sql":  {Data: []byte("CREATE DATABASE analytics ENGINE = Atomic;\n")},
+		"002_users.sql": {Data: []byte("CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;\n")},
+	}
+
+	local, err := migrator.LoadMigrationDir(migrations)
+	require.NoError(t, err)
+
+	var sum bytes.Buffer
+	_, err = local.SumFile.WriteTo(&sum)
+	require.NoError(t, err)
+	migrations["housekeeper.sum"] = &fstest.MapFile{Data: sum.Bytes()}
+
+	// serve publishes the migrations under /releases/v1/, counting requests for each file
+	serve := func(t *testing.T, files fstest.MapFS) (*httptest.Server, map[string]*atomic.Int32) {
+		t.Helper()
+
+		requests := make(map[string]*atomic.Int32)
+		for name := range files {
+			requests[name] = &atomic.Int32{}
+		}
+
+		fileServer := http.StripPrefix("/releases/v1/", http.FileServerFS(files))
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if counter, ok := requests[strings.TrimPrefix(r.URL.Path, "/releases/v1/")]; ok {
+				counter.Add(1)
+			}
+			fileServer.ServeHTTP(w, r)
+		}))
+		t.Cleanup(server.Close)
+		return server, requests
+	}
+
+	t.Run("loads and validates migrations", func(t *testing.T) {
+		server, requests := serve(t, migrations)
+
+		remote, err := migrator.NewHTTPFS(context.Background(), server.URL+"/releases/v1")
+		require.NoError(t, err)
+
+		migDir, err := migrator.LoadMigrationDir(remote)
+		require.NoError(t, err)
+		require.Len(t, migDir.Migrations, 2)
+		require.Equal(t, "001_init", migDir.Migrations[0].Version)
+		require.Equal(t, "002_users", migDir.Migrations[1].Version)
+
+		valid, err := migDir.Validate()
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		for name, count := range requests {
+			require.Equal(t, int32(1), count.Load(), "%s should be fetched once", name)
+		}
+	})
+
+	t.Run("implements fs.FS", func(t *testing.T) {
+		server, _ := serve(t, migrations)
+
+		remote, err := migrator.NewHTTPFS(context.Background(), server.URL+"/releases/v1/")
+		require.NoError(t, err)
+		require.NoError(t, fstest.TestFS(remote, "001_init.sql", "002_users.sql", "housekeeper.sum"))
+
+		_, err = remote.Open("003_unlisted.sql")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("sends configured headers", func(t *testing.T) {
+		fileServer := http.FileServerFS(migrations)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			fileServer.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+
+		_, err := migrator.NewHTTPFS(context.Background(), server.URL)
+		require.ErrorContains(t, err, "401 Unauthorized")
+
+		remote, err := migrator.NewHTTPFSWithOptions(context.Background(), server.URL, migrator.HTTPFSOptions{
+			Header: http.Header{"Authorization": []string{"Bearer secret"}},
+		})
+		require.NoError(t, err)
+
+		migDir, err := migrator.LoadMigrationDir(remote)
+		require.NoError(t, err)
+		require.Len(t, migDir.Migrations, 2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var outside bytes.Buffer
+		outsideSum := migrator.NewSumFile()
+		require.NoError(t, outsideSum.Add("../secrets.sql", strings.NewReader("SELECT 1;")))
+		_, err := outsideSum.WriteTo(&outside)
+		require.NoError(t, err)
+
+		tests := map[string]struct {
+			files fstest.MapFS
+			url   string
+			err   string
+		}{
+			"missing sum file": {
+				files: fstest.MapFS{"001_init.sql": migrations["001_init.sql"]},
+				err:   "failed to fetch migration manifest",
+			},
+			"file outside the directory": {
+				files: fstest.MapFS{"housekeeper.sum": {Data: outside.Bytes()}},
+				err:   `sum file lists "../secrets.sql"`,
+			},
+			"unsupported scheme": {
+				url: "file:///var/migrations",
+				err: `unsupported migrations URL scheme "file"`,
+			},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				url := tt.url
+				if url == "" {
+					server, _ := serve(t, tt.files)
+					url = server.URL + "/releases/v1/"
+				}
+
+				_, err := migrator.NewHTTPFS(context.Background(), url)
+				require.ErrorContains(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("missing migration", func(t *testing.T) {
+		incomplete := fstest.MapFS{
+			"001_init.sql":    migrations["001_init.sql"],
+			"housekeeper.sum": migrations["housekeeper.sum"],
+		}
+		server, _ := serve(t, incomplete)
+
+		remote, err := migrator.NewHTTPFS(context.Background(), server.URL+"/releases/v1/")
+		require.NoError(t, err)
+
+		_, err = migrator.LoadMigrationDir(remote)
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
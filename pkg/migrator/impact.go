@@ -0,0 +1,206 @@
+package migrator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/utils"
+)
+
+type (
+	// ImpactTarget identifies a destructive statement in a migration and the
+	// database object whose data it touches or destroys.
+	ImpactTarget struct {
+		// Statement is the 1-based index of the statement within the migration
+		Statement int
+
+		// Operation describes the destructive operation (e.g. "DROP TABLE")
+		Operation string
+
+		// Database is the targeted database, or empty for the connection's current database
+		Database string
+
+		// Table is the targeted table, or empty when the whole database is affected
+		Table string
+
+		// Partition is the targeted partition expression for partition operations
+		Partition string
+	}
+
+	// Impact reports how much data a destructive statement would affect on a live server.
+	Impact struct {
+		ImpactTarget
+
+		// Rows is the number of rows in the affected object
+		Rows uint64
+
+		// Bytes is the on-disk size of the affected object
+		Bytes uint64
+	}
+)
+
+// Name returns the unquoted, qualified name of the targeted object.
+func (t ImpactTarget) Name() string {
+	name := t.Database
+	if t.Table != "" {
+		if name != "" {
+			name += "."
+		}
+		name += t.Table
+	}
+
+	if t.Partition != "" {
+		name += " PARTITION " + t.Partition
+	}
+
+	return name
+}
+
+// ImpactTargets returns the destructive statements of a migration along with the
+// objects they target. DROP TABLE, TRUNCATE TABLE, DROP DATABASE, DROP PARTITION,
+// and ALTER operations that delete or rewrite data are included. Snapshot
+// migrations have no targets since their statements have already been applied.
+func ImpactTargets(m *Migration) []ImpactTarget {
+	if m.IsSnapshot {
+		return nil
+	}
+
+	var targets []ImpactTarget
+	for i, stmt := range m.Statements {
+		target := ImpactTarget{Statement: i + 1}
+
+		switch {
+		case stmt.DropTable != nil:
+			target.Operation = "DROP TABLE"
+			target.Database, target.Table = impactName(stmt.DropTable.Database, stmt.DropTable.Name)
+			targets = append(targets, target)
+		case stmt.TruncateTable != nil:
+			target.Operation = "TRUNCATE TABLE"
+			target.Database, target.Table = impactName(stmt.TruncateTable.Database, stmt.TruncateTable.Name)
+			targets = append(targets, target)
+		case stmt.DropDatabase != nil:
+			target.Operation = "DROP DATABASE"
+			target.Database = utils.StripBackticks(stmt.DropDatabase.Name)
+			targets = append(targets, target)
+		case stmt.AlterTable != nil:
+			target.Database, target.Table = impactName(stmt.AlterTable.Database, stmt.AlterTable.Name)
+			for _, op := range stmt.AlterTable.Operations {
+				if operation, partition := destructiveOperation(&op); operation != "" {
+					opTarget := target
+					opTarget.Operation = operation
+					opTarget.Partition = partition
+					targets = append(targets, opTarget)
+				}
+			}
+		}
+	}
+
+	return targets
+}
+
+// AnalyzeImpact queries the server for the row count and on-disk size of every
+// object targeted by the migration's destructive statements. Sizes are read from
+// the active parts in system.parts, so the analysis is read-only and advisory.
+//
+// Example:
+//
+//	impacts, err := migrator.AnalyzeImpact(ctx, client, migration)
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, impact := range impacts {
+//		fmt.Printf("%s %s: %d rows\n", impact.Operation, impact.Name(), impact.Rows)
+//	}
+func AnalyzeImpact(ctx context.Context, ch ClickHouse, m *Migration) ([]Impact, error) {
+	targets := ImpactTargets(m)
+
+	impacts := make([]Impact, 0, len(targets))
+	for _, target := range targets {
+		impact := Impact{ImpactTarget: target}
+
+		var err error
+		if impact.Rows, impact.Bytes, err = querySize(ctx, ch, target); err != nil {
+			return nil, err
+		}
+
+		impacts = append(impacts, impact)
+	}
+
+	return impacts, nil
+}
+
+// querySize reads the row count and on-disk size of the given target from system.parts
+func querySize(ctx context.Context, ch ClickHouse, target ImpactTarget) (uint64, uint64, error) {
+	query, args := impactQuery(target)
+	rows, err := ch.Query(ctx, query, args...)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to query size of %s", target.Name())
+	}
+	defer rows.Close()
+
+	var rowCount, bytes uint64
+	if rows.Next() {
+		if err := rows.Scan(&rowCount, &bytes); err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to scan size of %s", target.Name())
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to read size of %s", target.Name())
+	}
+
+	return rowCount, bytes, nil
+}
+
+// impactQuery builds the system.parts query that sizes the given target
+func impactQuery(target ImpactTarget) (string, []any) {
+	var (
+		conditions = []string{"active"}
+		args       []any
+	)
+
+	if target.Database == "" {
+		conditions = append(conditions, "database = currentDatabase()")
+	} else {
+		conditions = append(conditions, "database = ?")
+		args = append(args, target.Database)
+	}
+
+	if target.Table != "" {
+		conditions = append(conditions, "table = ?")
+		args = append(args, target.Table)
+	}
+
+	if target.Partition != "" {
+		// system.parts renders partition values without the quotes used in DDL
+		conditions = append(conditions, "(partition = ? OR partition = ?)")
+		args = append(args, target.Partition, strings.Trim(target.Partition, "'"))
+	}
+
+	return "SELECT sum(rows), sum(bytes_on_disk) FROM system.parts WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// destructiveOperation describes the ALTER operation if it deletes or rewrites
+// existing data, along with the partition it is limited to (if any)
+func destructiveOperation(op *parser.AlterTableOperation) (string, string) {
+	switch {
+	case op.DropPartition != nil:
+		return "DROP PARTITION", op.DropPartition.Partition
+	case op.DropColumn != nil:
+		return "DROP COLUMN " + op.DropColumn.Name, ""
+	default:
+		return mutationOperation(op), ""
+	}
+}
+
+// impactName returns the unquoted database and table names of a table reference
+func impactName(database *string, name string) (string, string) {
+	if database == nil {
+		return "", utils.StripBackticks(name)
+	}
+
+	return utils.StripBackticks(*database), utils.StripBackticks(name)
+}
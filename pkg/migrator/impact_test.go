@@ -0,0 +1,126 @@
+package migrator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpactTargets(t *testing.T) {
+	sql := `CREATE TABLE db.staging (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		DROP TABLE db.events;
+		TRUNCATE TABLE logs;
+		ALTER TABLE db.users DROP PARTITION '2024-01', ADD COLUMN name String, DROP COLUMN email;
+		ALTER TABLE db.users MODIFY COLUMN age UInt16;
+		DROP DATABASE archive;`
+
+	migration, err := migrator.LoadMigration("001_cleanup", strings.NewReader(sql))
+	require.NoError(t, err)
+
+	targets := migrator.ImpactTargets(migration)
+	require.Equal(t, []migrator.ImpactTarget{
+		{Statement: 2, Operation: "DROP TABLE", Database: "db", Table: "events"},
+		{Statement: 3, Operation: "TRUNCATE TABLE", Table: "logs"},
+		{Statement: 4, Operation: "DROP PARTITION", Database: "db", Table: "users", Partition: "'2024-01'"},
+		{Statement: 4, Operation: "DROP COLUMN email", Database: "db", Table: "users"},
+		{Statement: 5, Operation: "MODIFY COLUMN age", Database: "db", Table: "users"},
+		{Statement: 6, Operation: "DROP DATABASE", Database: "archive"},
+	}, targets)
+
+	require.Equal(t, "db.events", targets[0].Name())
+	require.Equal(t, "logs", targets[1].Name())
+	require.Equal(t, "db.users PARTITION '2024-01'", targets[2].Name())
+	require.Equal(t, "archive", targets[5].Name())
+}
+
+func TestImpactTargets_Snapshot(t *testing.T) {
+	sql := `-- housekeeper:snapshot
+-- version: 20240101000000_snapshot
+-- description: Test snapshot
+-- created_at: 2024-01-01T00:00:00Z
+-- included_migrations: 001_init
+-- cumulative_hash: abc123
+
+DROP TABLE db.events;`
+
+	migration, err := migrator.LoadMigration("20240101000000_snapshot", strings.NewReader(sql))
+	require.NoError(t, err)
+	require.True(t, migration.IsSnapshot)
+	require.Empty(t, migrator.ImpactTargets(migration))
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	sql := `DROP TABLE db.events;
+		TRUNCATE TABLE logs;
+		ALTER TABLE db.users DROP PARTITION '2024-01';`
+
+	migration, err := migrator.LoadMigration("001_cleanup", strings.NewReader(sql))
+	require.NoError(t, err)
+
+	t.Run("reports sizes", func(t *testing.T) {
+		sizes := map[string][]any{
+			"db.events":          {int64(1000), int64(4096)},
+			"logs":               {int64(50), int64(512)},
+			"db.users.'2024-01'": {int64(25), int64(256)},
+		}
+
+		var queries []string
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				queries = append(queries, query)
+
+				key := make([]string, 0, len(args))
+				for _, arg := range args {
+					key = append(key, arg.(string))
+				}
+
+				// Partition queries match both the quoted and unquoted value
+				if len(key) == 4 {
+					key = key[:3]
+				}
+
+				return &mockRows{data: [][]any{sizes[strings.Join(key, ".")]}}, nil
+			},
+		}
+
+		impacts, err := migrator.AnalyzeImpact(context.Background(), mockCH, migration)
+		require.NoError(t, err)
+		require.Len(t, impacts, 3)
+
+		require.Equal(t, "DROP TABLE", impacts[0].Operation)
+		require.Equal(t, uint64(1000), impacts[0].Rows)
+		require.Equal(t, uint64(4096), impacts[0].Bytes)
+
+		require.Equal(t, "TRUNCATE TABLE", impacts[1].Operation)
+		require.Equal(t, uint64(50), impacts[1].Rows)
+		require.Equal(t, uint64(512), impacts[1].Bytes)
+
+		require.Equal(t, "DROP PARTITION", impacts[2].Operation)
+		require.Equal(t, uint64(25), impacts[2].Rows)
+		require.Equal(t, uint64(256), impacts[2].Bytes)
+
+		require.Len(t, queries, 3)
+		for _, query := range queries {
+			require.Contains(t, query, "FROM system.parts WHERE active")
+		}
+		require.Contains(t, queries[1], "database = currentDatabase()")
+		require.Contains(t, queries[2], "partition = ?")
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+
+		impacts, err := migrator.AnalyzeImpact(context.Background(), mockCH, migration)
+		require.ErrorContains(t, err, "failed to query size of db.events")
+		require.Nil(t, impacts)
+	})
+}
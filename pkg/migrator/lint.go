@@ -0,0 +1,317 @@
+package migrator
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/utils"
+)
+
+const (
+	// LintWarning marks a finding that should be reviewed before the migration is applied
+	LintWarning LintSeverity = "WARNING"
+	// LintError marks a finding that is likely to cause data loss or breakage
+	LintError LintSeverity = "ERROR"
+
+	// LintRuleMixedDDLAndData flags data operations in migrations that also change the schema
+	LintRuleMixedDDLAndData = "mixed-ddl-and-data"
+	// LintRuleDropWithoutBackup flags destructive statements that aren't preceded by a backup
+	LintRuleDropWithoutBackup = "drop-without-backup"
+	// LintRuleExpensiveMutation flags ALTER operations that rewrite data parts
+	LintRuleExpensiveMutation = "expensive-mutation"
+	// LintRuleRenameBreaksViews flags renames that views may depend on
+	LintRuleRenameBreaksViews = "rename-breaks-views"
+	// LintRuleEngineCasing flags engine names that don't use ClickHouse's canonical casing
+	LintRuleEngineCasing = "engine-casing"
+)
+
+type (
+	// LintSeverity indicates how serious a lint finding is
+	LintSeverity string
+
+	// LintFinding describes a risky pattern found in a migration.
+	//
+	// Example:
+	//
+	//	for _, finding := range migrationDir.Lint() {
+	//		fmt.Printf("%s:%d [%s] %s: %s\n",
+	//			finding.Version, finding.Statement, finding.Severity, finding.Rule, finding.Message)
+	//	}
+	LintFinding struct {
+		// Version of the migration containing the statement
+		Version string
+
+		// Statement is the 1-based index of the offending statement in the migration
+		Statement int
+
+		// Severity of the finding
+		Severity LintSeverity
+
+		// Rule is the identifier of the rule that produced the finding
+		Rule string
+
+		// Message describes the problem
+		Message string
+	}
+
+	// linter tracks state across the migrations being linted
+	linter struct {
+		// views maps qualified view names to the formatted view definition, used to find
+		// views that depend on renamed tables
+		views map[string]string
+
+		findings []LintFinding
+	}
+)
+
+// Lint checks every migration in the directory for risky patterns. Migrations are
+// linted in order so renames can be checked against views created by earlier migrations.
+//
+// Example:
+//
+//	migrationDir, err := migrator.LoadMigrationDir(os.DirFS("db/migrations"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	for _, finding := range migrationDir.Lint() {
+//		fmt.Printf("%s:%d %s\n", finding.Version, finding.Statement, finding.Message)
+//	}
+func (m *MigrationDir) Lint() []LintFinding {
+	l := &linter{views: make(map[string]string)}
+	for _, migration := range m.Migrations {
+		l.lint(migration)
+	}
+	return l.findings
+}
+
+// LintMigration checks a single migration for risky patterns:
+//   - Data operations (UPDATE, DELETE, partition operations) mixed with schema changes
+//   - DROP statements that aren't preceded by a FREEZE or copy of the affected table
+//   - ALTER operations that trigger mutations rewriting data parts
+//   - Renames that can break views depending on the old name
+func LintMigration(migration *Migration) []LintFinding {
+	l := &linter{views: make(map[string]string)}
+	l.lint(migration)
+	return l.findings
+}
+
+func (l *linter) lint(migration *Migration) {
+	if migration.IsSnapshot {
+		return
+	}
+
+	// Tables that have been backed up (frozen or copied) earlier in the migration
+	backedUp := make(map[string]bool)
+	hasDDL := slices.ContainsFunc(migration.Statements, isSchemaChange)
+
+	for i, stmt := range migration.Statements {
+		report := func(severity LintSeverity, rule, msg string, args ...any) {
+			l.findings = append(l.findings, LintFinding{
+				Version:   migration.Version,
+				Statement: i + 1,
+				Severity:  severity,
+				Rule:      rule,
+				Message:   fmt.Sprintf(msg, args...),
+			})
+		}
+
+		if engine := statementEngine(stmt); engine != "" {
+			if canonical, ok := canonicalEngines[strings.ToLower(engine)]; ok && canonical != engine {
+				report(LintError, LintRuleEngineCasing,
+					"engine %s is not canonically cased; ClickHouse expects %s", engine, canonical)
+			}
+		}
+
+		switch {
+		case stmt.CreateTable != nil:
+			if source := stmt.CreateTable.AsTable; source != nil && source.TableRef != nil {
+				backedUp[lintName(source.TableRef.Database, source.TableRef.Table)] = true
+			}
+		case stmt.CreateView != nil:
+			l.views[lintName(stmt.CreateView.Database, stmt.CreateView.Name)] = formatStatement(stmt)
+		case stmt.DropTable != nil:
+			name := lintName(stmt.DropTable.Database, stmt.DropTable.Name)
+			delete(l.views, name)
+			if !backedUp[name] {
+				report(LintError, LintRuleDropWithoutBackup,
+					"DROP TABLE %s permanently deletes its data; FREEZE or copy the table first", name)
+			}
+		case stmt.TruncateTable != nil:
+			name := lintName(stmt.TruncateTable.Database, stmt.TruncateTable.Name)
+			if !backedUp[name] {
+				report(LintError, LintRuleDropWithoutBackup,
+					"TRUNCATE TABLE %s permanently deletes its data; FREEZE or copy the table first", name)
+			}
+			if hasDDL {
+				report(LintWarning, LintRuleMixedDDLAndData,
+					"data operation on %s is mixed with schema changes; move it to a separate migration", name)
+			}
+		case stmt.DropView != nil:
+			delete(l.views, lintName(stmt.DropView.Database, stmt.DropView.Name))
+		case stmt.DropDatabase != nil:
+			report(LintError, LintRuleDropWithoutBackup,
+				"DROP DATABASE %s permanently deletes every table in it", stmt.DropDatabase.Name)
+		case stmt.RenameTable != nil:
+			for _, rename := range stmt.RenameTable.Renames {
+				from := lintName(rename.FromDatabase, rename.FromName)
+				if dependents := l.dependentViews(from); len(dependents) > 0 {
+					report(LintError, LintRuleRenameBreaksViews,
+						"renaming %s breaks dependent views: %s", from, strings.Join(dependents, ", "))
+				} else {
+					report(LintWarning, LintRuleRenameBreaksViews,
+						"renaming %s breaks any views that select from it", from)
+				}
+			}
+		case stmt.AlterTable != nil:
+			name := lintName(stmt.AlterTable.Database, stmt.AlterTable.Name)
+			for _, op := range stmt.AlterTable.Operations {
+				if op.Freeze != nil {
+					backedUp[name] = true
+				}
+				if op.DropColumn != nil && !backedUp[name] {
+					report(LintWarning, LintRuleDropWithoutBackup,
+						"DROP COLUMN %s permanently deletes data from %s; FREEZE the table first", op.DropColumn.Name, name)
+				}
+				if op.DropPartition != nil && !backedUp[name] {
+					report(LintWarning, LintRuleDropWithoutBackup,
+						"DROP PARTITION %s permanently deletes data from %s; FREEZE the partition first", op.DropPartition.Partition, name)
+				}
+				if mutation := mutationOperation(&op); mutation != "" {
+					report(LintWarning, LintRuleExpensiveMutation,
+						"%s on %s triggers a mutation that rewrites data parts, which is expensive on large tables", mutation, name)
+				}
+				if op.RenameColumn != nil {
+					report(LintWarning, LintRuleRenameBreaksViews,
+						"renaming column %s of %s breaks any views that select it", op.RenameColumn.From, name)
+				}
+				if hasDDL && isDataOperation(&op) {
+					report(LintWarning, LintRuleMixedDDLAndData,
+						"data operation on %s is mixed with schema changes; move it to a separate migration", name)
+				}
+			}
+		}
+	}
+}
+
+// dependentViews returns the sorted names of known views that reference the given table
+func (l *linter) dependentViews(table string) []string {
+	ref := utils.BacktickIdentifier(table)
+
+	var dependents []string
+	for view, definition := range l.views {
+		if strings.Contains(definition, ref) {
+			dependents = append(dependents, view)
+		}
+	}
+
+	slices.Sort(dependents)
+	return dependents
+}
+
+// isSchemaChange returns true for statements that change the schema
+func isSchemaChange(stmt *parser.Statement) bool {
+	if stmt.AlterTable != nil {
+		return slices.ContainsFunc(stmt.AlterTable.Operations, func(op parser.AlterTableOperation) bool {
+			return !isDataOperation(&op)
+		})
+	}
+
+	return stmt.CreateDatabase != nil || stmt.AlterDatabase != nil || stmt.DropDatabase != nil ||
+		stmt.CreateTable != nil || stmt.DropTable != nil || stmt.RenameTable != nil || stmt.ExchangeTables != nil ||
+		stmt.CreateView != nil || stmt.DropView != nil ||
+		stmt.CreateDictionary != nil || stmt.DropDictionary != nil || stmt.RenameDictionary != nil ||
+		stmt.ExchangeDictionaries != nil
+}
+
+// isDataOperation returns true for ALTER operations that modify data rather than schema
+func isDataOperation(op *parser.AlterTableOperation) bool {
+	return op.Update != nil || op.Delete != nil ||
+		op.AttachPartition != nil || op.DetachPartition != nil || op.DropPartition != nil ||
+		op.MovePartition != nil || op.ReplacePartition != nil || op.FetchPartition != nil
+}
+
+// mutationOperation returns a description of the ALTER operation if it rewrites data parts
+func mutationOperation(op *parser.AlterTableOperation) string {
+	switch {
+	case op.ModifyColumn != nil:
+		return "MODIFY COLUMN " + op.ModifyColumn.Name
+	case op.ClearColumn != nil:
+		return "CLEAR COLUMN " + op.ClearColumn.Name
+	case op.Update != nil:
+		return "UPDATE"
+	case op.Delete != nil:
+		return "DELETE"
+	case op.ModifyTTL != nil:
+		return "MODIFY TTL"
+	default:
+		return ""
+	}
+}
+
+// statementEngine returns the engine name of a CREATE statement, if it has one
+func statementEngine(stmt *parser.Statement) string {
+	switch {
+	case stmt.CreateDatabase != nil && stmt.CreateDatabase.Engine != nil:
+		return stmt.CreateDatabase.Engine.Name
+	case stmt.CreateTable != nil && stmt.CreateTable.Engine != nil:
+		return stmt.CreateTable.Engine.Name
+	case stmt.CreateView != nil && stmt.CreateView.Engine != nil:
+		return stmt.CreateView.Engine.Name
+	default:
+		return ""
+	}
+}
+
+// canonicalEngines maps lowercased engine names to the casing ClickHouse accepts
+var canonicalEngines = func() map[string]string {
+	engines := []string{
+		// Database engines
+		"Atomic", "Ordinary", "Lazy", "Replicated", "MaterializedMySQL", "Backup", "Filesystem", "DataLakeCatalog",
+
+		// MergeTree family
+		"MergeTree", "ReplacingMergeTree", "SummingMergeTree", "AggregatingMergeTree",
+		"CollapsingMergeTree", "VersionedCollapsingMergeTree", "GraphiteMergeTree", "CoalescingMergeTree",
+		"ReplicatedMergeTree", "ReplicatedReplacingMergeTree", "ReplicatedSummingMergeTree",
+		"ReplicatedAggregatingMergeTree", "ReplicatedCollapsingMergeTree",
+		"ReplicatedVersionedCollapsingMergeTree", "ReplicatedGraphiteMergeTree",
+		"SharedMergeTree", "SharedReplacingMergeTree", "SharedSummingMergeTree",
+		"SharedAggregatingMergeTree", "SharedCollapsingMergeTree", "SharedVersionedCollapsingMergeTree",
+
+		// Log family
+		"TinyLog", "StripeLog", "Log",
+
+		// Integration engines
+		"Kafka", "RabbitMQ", "NATS", "FileLog", "MySQL", "PostgreSQL", "MaterializedPostgreSQL",
+		"MongoDB", "Redis", "SQLite", "ODBC", "JDBC", "HDFS", "S3", "S3Queue", "AzureBlobStorage",
+		"AzureQueue", "DeltaLake", "Hudi", "Iceberg", "Hive", "EmbeddedRocksDB", "ExternalDistributed",
+
+		// Special engines
+		"Distributed", "Dictionary", "Merge", "File", "URL", "Null", "Set", "Join", "Memory", "Buffer",
+		"View", "MaterializedView", "GenerateRandom", "KeeperMap", "Executable", "ExecutablePool",
+		"TimeSeries", "Loop",
+	}
+
+	canonical := make(map[string]string, len(engines))
+	for _, engine := range engines {
+		canonical[strings.ToLower(engine)] = engine
+	}
+
+	return canonical
+}()
+
+// lintName returns the unquoted, qualified name used in lint messages and lookups
+func lintName(database *string, name string) string {
+	return utils.StripBackticks(utils.BacktickQualifiedName(database, name))
+}
+
+// formatStatement renders a statement using the default formatter
+func formatStatement(stmt *parser.Statement) string {
+	var buf bytes.Buffer
+	_ = format.Format(&buf, format.Defaults, stmt)
+	return buf.String()
+}
@@ -0,0 +1,205 @@
+package migrator_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintMigration(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected []migrator.LintFinding
+	}{
+		{
+			name: "safe migration",
+			sql: `CREATE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+				ALTER TABLE db.events ADD COLUMN name String;`,
+		},
+		{
+			name: "data operation mixed with ddl",
+			sql: `ALTER TABLE db.events ADD COLUMN name String;
+				ALTER TABLE db.events ATTACH PARTITION '2024-01' FROM db.staging;`,
+			expected: []migrator.LintFinding{
+				{Statement: 2, Severity: migrator.LintWarning, Rule: migrator.LintRuleMixedDDLAndData},
+			},
+		},
+		{
+			name: "data operation alone",
+			sql:  `ALTER TABLE db.events ATTACH PARTITION '2024-01' FROM db.staging;`,
+		},
+		{
+			name: "drop table without backup",
+			sql:  `DROP TABLE db.events;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintError, Rule: migrator.LintRuleDropWithoutBackup},
+			},
+		},
+		{
+			name: "drop table after freeze",
+			sql: `ALTER TABLE db.events FREEZE;
+				DROP TABLE db.events;`,
+		},
+		{
+			name: "drop table after copy",
+			sql: `CREATE TABLE db.events_backup AS db.events ENGINE = MergeTree() ORDER BY id;
+				DROP TABLE db.events;`,
+		},
+		{
+			name: "truncate table without backup",
+			sql:  `TRUNCATE TABLE db.events;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintError, Rule: migrator.LintRuleDropWithoutBackup},
+			},
+		},
+		{
+			name: "truncate table mixed with ddl",
+			sql: `ALTER TABLE db.events FREEZE;
+				ALTER TABLE db.events ADD COLUMN name String;
+				TRUNCATE TABLE db.events;`,
+			expected: []migrator.LintFinding{
+				{Statement: 3, Severity: migrator.LintWarning, Rule: migrator.LintRuleMixedDDLAndData},
+			},
+		},
+		{
+			name: "non-canonical engine casing",
+			sql: `CREATE DATABASE analytics ENGINE = atomic;
+				CREATE TABLE db.events (id UInt64) ENGINE = mergetree() ORDER BY id;
+				CREATE MATERIALIZED VIEW db.mv ENGINE = SUMMINGMERGETREE() ORDER BY id AS SELECT id FROM db.events;
+				CREATE TABLE db.logs (id UInt64) ENGINE = ReplicatedMergeTree() ORDER BY id;
+				CREATE TABLE db.custom (id UInt64) ENGINE = myEngine();`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintError, Rule: migrator.LintRuleEngineCasing},
+				{Statement: 2, Severity: migrator.LintError, Rule: migrator.LintRuleEngineCasing},
+				{Statement: 3, Severity: migrator.LintError, Rule: migrator.LintRuleEngineCasing},
+			},
+		},
+		{
+			name: "drop database",
+			sql:  `DROP DATABASE analytics;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintError, Rule: migrator.LintRuleDropWithoutBackup},
+			},
+		},
+		{
+			name: "drop column without backup",
+			sql:  `ALTER TABLE db.events DROP COLUMN name;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintWarning, Rule: migrator.LintRuleDropWithoutBackup},
+			},
+		},
+		{
+			name: "drop column after freeze",
+			sql: `ALTER TABLE db.events FREEZE;
+				ALTER TABLE db.events DROP COLUMN name;`,
+		},
+		{
+			name: "modify column mutation",
+			sql:  `ALTER TABLE db.events MODIFY COLUMN id UInt128;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintWarning, Rule: migrator.LintRuleExpensiveMutation},
+			},
+		},
+		{
+			name: "update mutation mixed with ddl",
+			sql: `ALTER TABLE db.events ADD COLUMN name String;
+				ALTER TABLE db.events UPDATE name = 'unknown' WHERE 1;`,
+			expected: []migrator.LintFinding{
+				{Statement: 2, Severity: migrator.LintWarning, Rule: migrator.LintRuleExpensiveMutation},
+				{Statement: 2, Severity: migrator.LintWarning, Rule: migrator.LintRuleMixedDDLAndData},
+			},
+		},
+		{
+			name: "delete and modify ttl mutations",
+			sql: `ALTER TABLE db.events DELETE WHERE id = 0;
+				ALTER TABLE db.events MODIFY TTL created_at + INTERVAL 30 DAY;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintWarning, Rule: migrator.LintRuleExpensiveMutation},
+				{Statement: 1, Severity: migrator.LintWarning, Rule: migrator.LintRuleMixedDDLAndData},
+				{Statement: 2, Severity: migrator.LintWarning, Rule: migrator.LintRuleExpensiveMutation},
+			},
+		},
+		{
+			name: "rename table",
+			sql:  `RENAME TABLE db.events TO db.events_v1;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintWarning, Rule: migrator.LintRuleRenameBreaksViews},
+			},
+		},
+		{
+			name: "rename table with dependent view",
+			sql: `CREATE VIEW db.daily AS SELECT count() FROM db.events;
+				RENAME TABLE db.events TO db.events_v1;`,
+			expected: []migrator.LintFinding{
+				{Statement: 2, Severity: migrator.LintError, Rule: migrator.LintRuleRenameBreaksViews},
+			},
+		},
+		{
+			name: "rename column",
+			sql:  `ALTER TABLE db.events RENAME COLUMN name TO title;`,
+			expected: []migrator.LintFinding{
+				{Statement: 1, Severity: migrator.LintWarning, Rule: migrator.LintRuleRenameBreaksViews},
+			},
+		},
+		{
+			name: "statement index counts comments",
+			sql: `-- Remove the legacy table
+				DROP TABLE db.legacy;`,
+			expected: []migrator.LintFinding{
+				{Statement: 2, Severity: migrator.LintError, Rule: migrator.LintRuleDropWithoutBackup},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := migrator.LoadMigration("001_test", strings.NewReader(tt.sql))
+			require.NoError(t, err)
+
+			findings := migrator.LintMigration(migration)
+			require.Len(t, findings, len(tt.expected))
+
+			for i, expected := range tt.expected {
+				require.Equal(t, "001_test", findings[i].Version)
+				require.Equal(t, expected.Statement, findings[i].Statement)
+				require.Equal(t, expected.Severity, findings[i].Severity)
+				require.Equal(t, expected.Rule, findings[i].Rule)
+				require.NotEmpty(t, findings[i].Message)
+			}
+		})
+	}
+}
+
+func TestMigrationDir_Lint(t *testing.T) {
+	migrationDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+		"001_init.sql": {Data: []byte(`CREATE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			CREATE VIEW db.daily AS SELECT count() FROM db.events;
+			CREATE VIEW db.totals AS SELECT count() FROM db.events;`)},
+		"002_rename.sql": {Data: []byte(`RENAME TABLE db.events TO db.events_v1;`)},
+	})
+	require.NoError(t, err)
+
+	findings := migrationDir.Lint()
+	require.Len(t, findings, 1)
+	require.Equal(t, "002_rename", findings[0].Version)
+	require.Equal(t, 1, findings[0].Statement)
+	require.Equal(t, migrator.LintError, findings[0].Severity)
+	require.Equal(t, "renaming db.events breaks dependent views: db.daily, db.totals", findings[0].Message)
+}
+
+func TestMigrationDir_Lint_DroppedViews(t *testing.T) {
+	migrationDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+		"001_init.sql":   {Data: []byte(`CREATE VIEW db.daily AS SELECT count() FROM db.events;`)},
+		"002_drop.sql":   {Data: []byte(`DROP VIEW db.daily;`)},
+		"003_rename.sql": {Data: []byte(`RENAME TABLE db.events TO db.events_v1;`)},
+	})
+	require.NoError(t, err)
+
+	findings := migrationDir.Lint()
+	require.Len(t, findings, 1)
+	require.Equal(t, migrator.LintWarning, findings[0].Severity)
+}
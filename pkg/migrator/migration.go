@@ -17,16 +17,28 @@ import (
 	"encoding/base64"
 	"io"
 	"io/fs"
+	"maps"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/consts"
 	"github.com/pseudomuto/housekeeper/pkg/format"
 	"github.com/pseudomuto/housekeeper/pkg/parser"
 )
 
+const (
+	transactionalMarker = "-- housekeeper:transactional"
+	downMarker          = "-- housekeeper:down"
+
+	// DownFileSuffix is the suffix of a file holding the down statements of the migration
+	// with the same version, e.g. 20240101120000_init.down.sql reverts 20240101120000_init.sql.
+	DownFileSuffix = ".down.sql"
+)
+
 type (
 	// Migration represents a single ClickHouse database migration containing
 	// a version identifier and the parsed DDL statements to be executed.
@@ -48,10 +60,26 @@ type (
 		// such as CREATE TABLE, ALTER DATABASE, etc.
 		Statements []*parser.Statement
 
+		// DownStatements contains the parsed statements that revert the migration,
+		// taken from the optional section following a -- housekeeper:down comment,
+		// or from the migration's <version>.down.sql file when it's loaded as part
+		// of a MigrationDir. It's empty when the migration has neither.
+		DownStatements []*parser.Statement
+
 		// IsSnapshot indicates whether this migration is a snapshot that consolidates
 		// previous migrations. Snapshot migrations are handled differently during
 		// execution - they are not executed as DDL but serve as consolidation points.
 		IsSnapshot bool
+
+		// IsTransactional indicates the migration opted into transactional execution
+		// with a -- housekeeper:transactional comment. When the server supports
+		// transactions, the executor wraps all of its statements in a single
+		// transaction so that a failure rolls back the statements already applied.
+		IsTransactional bool
+
+		// hasDownFile records that DownStatements were loaded from a separate
+		// <version>.down.sql file, which is hashed along with the migration.
+		hasDownFile bool
 	}
 
 	// MigrationDir represents a collection of migrations loaded from a directory
@@ -88,11 +116,16 @@ type (
 //
 // This function walks the provided filesystem in lexical order, loading all .sql files
 // as migrations and any .sum files for integrity verification. The filesystem can be
-// a regular directory, embedded filesystem, or any implementation of fs.FS.
+// a regular directory, embedded filesystem, remote directory (see HTTPFS), or any
+// implementation of fs.FS.
 //
 // Snapshot files (marked with -- housekeeper:snapshot) are automatically detected
 // and stored separately from regular migrations.
 //
+// Files named <version>.down.sql aren't migrations of their own. They hold the down
+// statements of the migration with the same version, as an alternative to a
+// -- housekeeper:down section, and are covered by the sum file along with it.
+//
 // Supported file extensions:
 //   - .sql: Migration files containing ClickHouse DDL statements or snapshot data
 //   - .sum: Sum files containing integrity hashes (currently loaded but not processed)
@@ -129,21 +162,31 @@ type (
 //		fmt.Printf("Found snapshot: %s\n", snapshot.Version)
 //	}
 //
-// Returns an error if the directory cannot be read or any migration file
-// contains invalid ClickHouse DDL syntax.
+// Returns an error if the directory cannot be read, any migration file contains
+// invalid ClickHouse DDL syntax, or a down file doesn't belong to a migration without
+// a down section.
 func LoadMigrationDir(dir fs.FS) (*MigrationDir, error) {
 	mig := &MigrationDir{
 		fs:      dir,
 		SumFile: NewSumFile(),
 	}
 	var loadedSumFile *SumFile
+	downs := make(map[string][]*parser.Statement)
 
 	// Walk directory and load files
-	err := walkMigrationDirectory(dir, mig, &loadedSumFile)
+	err := walkMigrationDirectory(dir, mig, downs, &loadedSumFile)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := attachDownFiles(mig.Migrations, downs); err != nil {
+		return nil, err
+	}
+
+	if err := validateVersions(mig); err != nil {
+		return nil, err
+	}
+
 	// Use loaded sum file if one was found, otherwise generate one
 	if loadedSumFile != nil {
 		mig.SumFile = loadedSumFile
@@ -159,8 +202,64 @@ func LoadMigrationDir(dir fs.FS) (*MigrationDir, error) {
 	return mig, nil
 }
 
-// walkMigrationDirectory walks the directory and loads migrations and sum files.
-func walkMigrationDirectory(dir fs.FS, mig *MigrationDir, loadedSumFile **SumFile) error {
+// validateVersions checks the loaded migrations can be applied in the order they were
+// loaded. Each version must start with a numeric prefix (e.g. "20240101120000" in
+// "20240101120000_init") that is unique, and prefixes must sort the same lexically and
+// numerically, otherwise "9_users" would be applied after "10_orders". A snapshot must
+// include every migration that sorts before it, since those would otherwise be applied
+// after the snapshot despite being older.
+func validateVersions(mig *MigrationDir) error {
+	seen := make(map[string]string, len(mig.Migrations))
+	var prev string
+
+	for _, m := range mig.Migrations {
+		prefix, _, _ := strings.Cut(m.Version, "_")
+		if prefix == "" || strings.Trim(prefix, "0123456789") != "" {
+			return errors.Errorf("malformed migration version %q: must start with a numeric prefix such as 20240101120000", m.Version)
+		}
+
+		if other, ok := seen[prefix]; ok {
+			return errors.Errorf("duplicate migration version %s: claimed by both %s.sql and %s.sql", prefix, other, m.Version)
+		}
+		seen[prefix] = m.Version
+
+		if prev != "" && len(prefix) != len(prev) {
+			return errors.Errorf(
+				"migration version %s is not the same width as %s: prefixes must be zero-padded to sort in order",
+				prefix, prev,
+			)
+		}
+		prev = prefix
+	}
+
+	if mig.snapshot == nil {
+		return nil
+	}
+
+	included := make(map[string]bool, len(mig.snapshot.IncludedMigrations))
+	for _, v := range mig.snapshot.IncludedMigrations {
+		included[v] = true
+	}
+
+	for _, m := range mig.Migrations {
+		if m.Version == mig.snapshot.Version {
+			break
+		}
+
+		if !included[m.Version] {
+			return errors.Errorf(
+				"migration %s sorts before snapshot %s but is not included in it",
+				m.Version, mig.snapshot.Version,
+			)
+		}
+	}
+
+	return nil
+}
+
+// walkMigrationDirectory walks the directory and loads migrations and sum files. The
+// statements of down files are collected in downs by the version they belong to.
+func walkMigrationDirectory(dir fs.FS, mig *MigrationDir, downs map[string][]*parser.Statement, loadedSumFile **SumFile) error {
 	exts := []string{".sql", ".sum"}
 
 	// NB: WalkDir always walks in lexical order.
@@ -180,10 +279,12 @@ func walkMigrationDirectory(dir fs.FS, mig *MigrationDir, loadedSumFile **SumFil
 		}
 		defer func() { _ = f.Close() }()
 
-		switch filepath.Ext(path) {
-		case ".sql":
+		switch {
+		case strings.HasSuffix(path, DownFileSuffix):
+			return loadDownFile(f, path, downs)
+		case ext == ".sql":
 			return loadSQLFile(f, path, mig)
-		case ".sum":
+		case ext == ".sum":
 			return loadSumFileFromPath(f, path, loadedSumFile)
 		}
 
@@ -230,6 +331,61 @@ func loadSQLFile(f fs.File, path string, mig *MigrationDir) error {
 	return nil
 }
 
+// loadDownFile parses a <version>.down.sql file into the down statements of its migration.
+func loadDownFile(r io.Reader, path string, downs map[string][]*parser.Statement) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read down file: %s", path)
+	}
+
+	sql, err := parser.ParseString(string(content))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse down file: %s", path)
+	}
+
+	downs[strings.TrimSuffix(filepath.Base(path), DownFileSuffix)] = sql.Statements
+	return nil
+}
+
+// attachDownFiles sets the statements loaded from down files as the down statements of
+// their migrations. Every down file must belong to a regular migration that doesn't
+// have a -- housekeeper:down section of its own.
+func attachDownFiles(migrations []*Migration, downs map[string][]*parser.Statement) error {
+	for _, m := range migrations {
+		statements, ok := downs[m.Version]
+		if !ok {
+			continue
+		}
+		delete(downs, m.Version)
+
+		if m.IsSnapshot {
+			return errors.Errorf("snapshots can't have a down file: %s%s", m.Version, DownFileSuffix)
+		}
+		if len(m.DownStatements) > 0 {
+			return errors.Errorf("migration %s has both a down section and a down file: %s%s", m.Version, m.Version, DownFileSuffix)
+		}
+
+		m.DownStatements = statements
+		m.hasDownFile = true
+	}
+
+	if len(downs) > 0 {
+		version := slices.Sorted(maps.Keys(downs))[0]
+		return errors.Errorf("down file has no matching migration: %s%s", version, DownFileSuffix)
+	}
+
+	return nil
+}
+
+// migrationFiles returns the files of a migration in the order they're hashed, which is
+// the lexical order they're walked in, so a down file comes before its migration.
+func migrationFiles(m *Migration) []string {
+	if m.hasDownFile {
+		return []string{m.Version + DownFileSuffix, m.Version + ".sql"}
+	}
+	return []string{m.Version + ".sql"}
+}
+
 // loadSumFileFromPath loads a sum file from the given path.
 func loadSumFileFromPath(f fs.File, path string, loadedSumFile **SumFile) error {
 	var err error
@@ -243,15 +399,20 @@ func loadSumFileFromPath(f fs.File, path string, loadedSumFile **SumFile) error
 // generateSumFileForMigrations generates sum file entries for all loaded migrations.
 func generateSumFileForMigrations(mig *MigrationDir) error {
 	for _, migration := range mig.Migrations {
-		filePath := migration.Version + ".sql"
-		file, err := mig.fs.Open(filePath)
-		if err != nil {
+		if _, err := fs.Stat(mig.fs, migration.Version+".sql"); err != nil {
 			// Try to find the file in the filesystem (for embedded filesystems)
-			err = findAndAddMigrationFile(mig, migration.Version)
-			if err != nil {
+			if err := findAndAddMigrationFile(mig, migration.Version); err != nil {
 				return err
 			}
-		} else {
+			continue
+		}
+
+		for _, filePath := range migrationFiles(migration) {
+			file, err := mig.fs.Open(filePath)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open migration file: %s", filePath)
+			}
+
 			err = mig.SumFile.Add(filePath, file)
 			_ = file.Close()
 			if err != nil {
@@ -314,6 +475,14 @@ func findAndAddMigrationFile(mig *MigrationDir, version string) error {
 //   - DROP statements for cleanup
 //   - Any other supported ClickHouse DDL operations
 //
+// Statements following a -- housekeeper:down comment form the migration's down
+// section. They are parsed into DownStatements rather than Statements:
+//
+//	CREATE TABLE users (id UInt64) ENGINE = MergeTree() ORDER BY id;
+//
+//	-- housekeeper:down
+//	DROP TABLE users;
+//
 // Example usage:
 //
 //	// Load from string
@@ -365,8 +534,9 @@ func findAndAddMigrationFile(mig *MigrationDir, version string) error {
 //		}
 //	}
 //
-// Returns an error if the reader content contains invalid ClickHouse DDL syntax
-// or if the reader cannot be read.
+// Returns an error if the reader content contains invalid ClickHouse DDL syntax,
+// if the reader cannot be read, if a snapshot has a down section, or if the content
+// has more than one -- housekeeper:down comment.
 func LoadMigration(v string, r io.Reader) (*Migration, error) {
 	// Read all content first so we can check for snapshot marker and parse SQL
 	content, err := io.ReadAll(r)
@@ -380,17 +550,84 @@ func LoadMigration(v string, r io.Reader) (*Migration, error) {
 		return nil, errors.Wrapf(err, "failed to check snapshot marker: %s.sql", v)
 	}
 
+	up, down, hasDown, err := splitDownSection(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid down section: %s.sql", v)
+	}
+	if hasDown && isSnapshot {
+		return nil, errors.Errorf("snapshots can't have a down section: %s.sql", v)
+	}
+
 	// Parse the SQL content
-	sql, err := parser.ParseString(string(content))
+	sql, err := parser.ParseString(up)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse: %s.sql", v)
 	}
 
-	return &Migration{
-		Version:    v,
-		Statements: sql.Statements,
-		IsSnapshot: isSnapshot,
-	}, nil
+	migration := &Migration{
+		Version:         v,
+		Statements:      sql.Statements,
+		IsSnapshot:      isSnapshot,
+		IsTransactional: hasTransactionalMarker(up),
+	}
+
+	if hasDown {
+		downSQL, err := parser.ParseString(down)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse down section: %s.sql", v)
+		}
+		migration.DownStatements = downSQL.Statements
+	}
+
+	return migration, nil
+}
+
+// IsNoOp returns true when the migration has nothing to execute, i.e. it's empty or only
+// contains comments (e.g. documentation or a placeholder). Such migrations are valid: the
+// executor records them as applied without sending anything to the server, and rolling
+// one back doesn't require a down section. Snapshots are never no-ops.
+func (m *Migration) IsNoOp() bool {
+	if m.IsSnapshot {
+		return false
+	}
+
+	return !slices.ContainsFunc(m.Statements, func(stmt *parser.Statement) bool {
+		return stmt.CommentStatement == nil
+	})
+}
+
+// splitDownSection splits migration content at the -- housekeeper:down comment into
+// the up and down sections. The marker line itself belongs to neither section.
+func splitDownSection(content string) (up, down string, hasDown bool, err error) {
+	var upSection, downSection strings.Builder
+	for line := range strings.Lines(content) {
+		if strings.TrimSpace(line) == downMarker {
+			if hasDown {
+				return "", "", false, errors.New("multiple -- housekeeper:down comments")
+			}
+			hasDown = true
+			continue
+		}
+
+		if hasDown {
+			downSection.WriteString(line)
+		} else {
+			upSection.WriteString(line)
+		}
+	}
+
+	return upSection.String(), downSection.String(), hasDown, nil
+}
+
+// hasTransactionalMarker returns true when any line of the migration content is the
+// -- housekeeper:transactional marker comment.
+func hasTransactionalMarker(content string) bool {
+	for line := range strings.Lines(content) {
+		if strings.TrimSpace(line) == transactionalMarker {
+			return true
+		}
+	}
+	return false
 }
 
 // Rehash reloads all migration files from the filesystem and recalculates the SumFile.
@@ -403,7 +640,7 @@ func LoadMigration(v string, r io.Reader) (*Migration, error) {
 // The method performs the following operations:
 //  1. Clears existing migrations and sum file
 //  2. Reloads all .sql files from the filesystem in lexical order
-//  3. Recalculates the chained SHA256 hashes for each migration
+//  3. Recalculates the chained SHA256 hashes for each migration and down file
 //  4. Updates the SumFile with new integrity verification data
 //
 // Example usage:
@@ -420,13 +657,7 @@ func LoadMigration(v string, r io.Reader) (*Migration, error) {
 //	}
 //
 //	// Write updated sum file
-//	file, err := os.Create("migrations.sum")
-//	if err != nil {
-//		log.Fatal(err)
-//	}
-//	defer file.Close()
-//
-//	_, err = migDir.SumFile.WriteTo(file)
+//	err = migDir.WriteSumFile("migrations/housekeeper.sum")
 //	if err != nil {
 //		log.Fatal(err)
 //	}
@@ -444,6 +675,7 @@ func (m *MigrationDir) Rehash() error {
 
 	// Track .sql files for sum file generation
 	var sqlFiles []string
+	downs := make(map[string][]*parser.Statement)
 
 	// Walk directory in lexical order
 	if err := fs.WalkDir(m.fs, ".", func(path string, d fs.DirEntry, err error) error {
@@ -465,6 +697,10 @@ func (m *MigrationDir) Rehash() error {
 		}
 		defer func() { _ = f.Close() }()
 
+		if strings.HasSuffix(path, DownFileSuffix) {
+			return loadDownFile(f, path, downs)
+		}
+
 		filename := filepath.Base(path)
 		version := filename[:strings.Index(filename, ".")]
 		migration, err := LoadMigration(version, f)
@@ -478,6 +714,10 @@ func (m *MigrationDir) Rehash() error {
 		return errors.Wrap(err, "failed to walk migration directory")
 	}
 
+	if err := attachDownFiles(m.Migrations, downs); err != nil {
+		return err
+	}
+
 	// Recalculate sum file with all migrations in order
 	for _, path := range sqlFiles {
 		f, err := m.fs.Open(path)
@@ -495,6 +735,95 @@ func (m *MigrationDir) Rehash() error {
 	return nil
 }
 
+// RehashDryRun reports what Rehash would change without modifying the MigrationDir
+// or writing anything to disk.
+//
+// The sum file is recomputed from the migration files on the filesystem and compared
+// with the directory's current SumFile. The returned diff lists the sum file lines that
+// would change, prefixed with "-" for lines that would be removed and "+" for lines
+// that would be added. Because hashes are chained, changing one migration also changes
+// the entries of every migration after it.
+//
+// Example usage:
+//
+//	changed, diff, err := migDir.RehashDryRun()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if changed {
+//		fmt.Print(diff)
+//		log.Fatal("housekeeper.sum is out of date, run housekeeper rehash")
+//	}
+//
+// Returns an error under the same conditions as Rehash.
+func (m *MigrationDir) RehashDryRun() (changed bool, diff string, err error) {
+	if m.fs == nil {
+		return false, "", errors.New("cannot rehash: filesystem reference is nil")
+	}
+
+	rehashed := &MigrationDir{fs: m.fs}
+	if err := rehashed.Rehash(); err != nil {
+		return false, "", err
+	}
+
+	current := m.SumFile
+	if current == nil {
+		current = NewSumFile()
+	}
+
+	diff = current.diff(rehashed.SumFile)
+	return diff != "", diff, nil
+}
+
+// WriteSumFile atomically writes the directory's sum file to path.
+//
+// The sum file is written to a temporary file in the same directory, synced to
+// disk, and then renamed over path. A crash part way through leaves the previous
+// sum file untouched rather than a partially written one.
+//
+// Example usage:
+//
+//	if err := migDir.Rehash(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if err := migDir.WriteSumFile(filepath.Join("db/migrations", "housekeeper.sum")); err != nil {
+//		log.Fatal(err)
+//	}
+func (m *MigrationDir) WriteSumFile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary sum file for: %s", path)
+	}
+	// Removing the temporary file is a no-op once it has been renamed
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := m.SumFile.WriteTo(tmp); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "failed to write sum file: %s", path)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "failed to sync sum file: %s", path)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close sum file: %s", path)
+	}
+
+	if err := os.Chmod(tmp.Name(), consts.ModeFile); err != nil {
+		return errors.Wrapf(err, "failed to set permissions on sum file: %s", path)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrapf(err, "failed to replace sum file: %s", path)
+	}
+
+	return nil
+}
+
 // Validate verifies the integrity of the MigrationDir by ensuring that all migrations
 // are present in the sum file and that the sum file validates correctly against
 // the current migration content.
@@ -557,17 +886,17 @@ func (m *MigrationDir) Validate() (bool, error) {
 	// Create a temporary sum file from current migration files to compare
 	tempSumFile := NewSumFile()
 	for _, migration := range m.Migrations {
-		filePath := migration.Version + ".sql"
-
-		file, err := m.fs.Open(filePath)
-		if err != nil {
-			return false, errors.Wrapf(err, "failed to open migration file: %s", filePath)
-		}
+		for _, filePath := range migrationFiles(migration) {
+			file, err := m.fs.Open(filePath)
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to open migration file: %s", filePath)
+			}
 
-		err = tempSumFile.Add(filePath, file)
-		_ = file.Close()
-		if err != nil {
-			return false, errors.Wrapf(err, "failed to hash migration: %s", filePath)
+			err = tempSumFile.Add(filePath, file)
+			_ = file.Close()
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to hash migration: %s", filePath)
+			}
 		}
 	}
 
@@ -728,6 +1057,32 @@ func (m *MigrationDir) GetMigrationsAfterSnapshot() []*Migration {
 	return afterSnapshot
 }
 
+// MigrationsThrough returns the migrations up to and including the given version, in order.
+//
+// The version may be a full migration version (e.g. "20240101120000_init") or just its
+// timestamp prefix (e.g. "20240101120000"). An error is returned if no migration matches.
+//
+// Example usage:
+//
+//	migDir, err := migrator.LoadMigrationDir(os.DirFS("./migrations"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	applied, err := migDir.MigrationsThrough("20240101120000")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (m *MigrationDir) MigrationsThrough(version string) ([]*Migration, error) {
+	for i, mig := range m.Migrations {
+		if mig.Version == version || strings.HasPrefix(mig.Version, version+"_") {
+			return m.Migrations[:i+1], nil
+		}
+	}
+
+	return nil, errors.Errorf("migration not found: %s", version)
+}
+
 // computeSumFileHash computes a SHA256 hash of all the entry hashes in a SumFile.
 // This provides a simple way to compare two sum files for equality.
 func computeSumFileHash(sumFile *SumFile) ([]byte, error) {
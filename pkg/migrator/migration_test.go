@@ -3,11 +3,13 @@ package migrator_test
 import (
 	"bytes"
 	"embed"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"testing/fstest"
 
+	"github.com/pseudomuto/housekeeper/pkg/consts"
 	"github.com/pseudomuto/housekeeper/pkg/migrator"
 	"github.com/stretchr/testify/require"
 )
@@ -75,6 +77,16 @@ func TestLoadMigration(t *testing.T) {
 			stmtCount:   4,
 			description: "Complex migration with multiple object types should parse",
 		},
+		{
+			name:    "attach_tables",
+			version: "004_attach_tables",
+			sql: `ATTACH TABLE analytics.mv_daily_stats;
+				  ATTACH TABLE analytics.events_archive (id UInt64, ts DateTime)
+				  ENGINE = MergeTree() ORDER BY (id, ts);`,
+			wantErr:     false,
+			stmtCount:   2,
+			description: "ATTACH TABLE with and without a table definition should parse",
+		},
 		{
 			name:        "empty_migration",
 			version:     "004_empty",
@@ -125,12 +137,15 @@ func TestLoadMigration_StatementTypes(t *testing.T) {
 			CREATE TABLE test.users (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
 			ALTER TABLE test.users ADD COLUMN email String DEFAULT '';
 			CREATE VIEW test.user_view AS SELECT id, name FROM test.users;
-			DROP VIEW test.user_view;`
+			DROP VIEW test.user_view;
+			EXCHANGE TABLES test.users AND test.users_new ON CLUSTER prod;
+			TRUNCATE TABLE IF EXISTS test.users_new;
+			ALTER TABLE test.users MODIFY COMMENT 'Registered users';`
 
 	migration, err := migrator.LoadMigration("test_types", strings.NewReader(sql))
 	require.NoError(t, err)
 	require.Equal(t, "test_types", migration.Version)
-	require.Len(t, migration.Statements, 5)
+	require.Len(t, migration.Statements, 8)
 
 	// Verify statement types
 	require.NotNil(t, migration.Statements[0].CreateDatabase)
@@ -154,6 +169,20 @@ func TestLoadMigration_StatementTypes(t *testing.T) {
 	require.Equal(t, "user_view", migration.Statements[4].DropView.Name)
 	require.NotNil(t, migration.Statements[4].DropView.Database)
 	require.Equal(t, "test", *migration.Statements[4].DropView.Database)
+
+	require.NotNil(t, migration.Statements[5].ExchangeTables)
+	require.Equal(t, "users", migration.Statements[5].ExchangeTables.FirstName)
+	require.Equal(t, "users_new", migration.Statements[5].ExchangeTables.SecondName)
+	require.Equal(t, "prod", *migration.Statements[5].ExchangeTables.OnCluster)
+
+	require.NotNil(t, migration.Statements[6].TruncateTable)
+	require.Equal(t, "users_new", migration.Statements[6].TruncateTable.Name)
+	require.True(t, migration.Statements[6].TruncateTable.IfExists)
+
+	require.NotNil(t, migration.Statements[7].AlterTable)
+	require.Len(t, migration.Statements[7].AlterTable.Operations, 1)
+	require.NotNil(t, migration.Statements[7].AlterTable.Operations[0].ModifyComment)
+	require.Equal(t, "'Registered users'", migration.Statements[7].AlterTable.Operations[0].ModifyComment.Comment)
 }
 
 func TestLoadMigrationDir(t *testing.T) {
@@ -189,7 +218,7 @@ func TestLoadMigrationDir(t *testing.T) {
 			files: map[string]string{
 				"20240101120000.sql": "CREATE DATABASE test ENGINE = Atomic;",
 				"20240101120100.sql": "CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;",
-				"migrations.sum":     "h1:dGVzdA==\n20240101120000.sql h1:aGFzaDE=\n20240101120100.sql h1:aGFzaDI=",
+				"migrations.sum":     "h1:2Oq4AAxYJvvyHmNAyWqRHHzzYsBUaVtzyxqArQ2sHLA=\n20240101120000.sql h1:aGFzaDE=\n20240101120100.sql h1:aGFzaDI=",
 			},
 			wantErr:        false,
 			migrationCount: 2,
@@ -299,6 +328,131 @@ func TestLoadMigrationDir_LexicalOrdering(t *testing.T) {
 	}
 }
 
+func TestLoadMigrationDir_VersionValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		err   string
+	}{
+		{
+			name:  "unique timestamp versions",
+			files: []string{"20240101120000_init.sql", "20240101130000.sql", "20240102120000_users.sql"},
+		},
+		{
+			name:  "unique sequential versions",
+			files: []string{"001_init.sql", "002_users.sql", "010_orders.sql"},
+		},
+		{
+			name:  "duplicate versions",
+			files: []string{"20240101120000_init.sql", "20240101120000_users.sql"},
+			err:   "duplicate migration version 20240101120000: claimed by both 20240101120000_init.sql and 20240101120000_users.sql",
+		},
+		{
+			name:  "duplicate version without description",
+			files: []string{"20240101120000.sql", "20240101120000_init.sql"},
+			err:   "duplicate migration version 20240101120000",
+		},
+		{
+			name:  "non-numeric version",
+			files: []string{"20240101120000_init.sql", "init_users.sql"},
+			err:   `malformed migration version "init_users"`,
+		},
+		{
+			name:  "partially numeric version",
+			files: []string{"2024-01-01_init.sql"},
+			err:   `malformed migration version "2024-01-01_init"`,
+		},
+		{
+			name:  "missing version",
+			files: []string{"_init.sql"},
+			err:   `malformed migration version "_init"`,
+		},
+		{
+			name:  "unpadded versions",
+			files: []string{"10_orders.sql", "9_users.sql"},
+			err:   "migration version 9 is not the same width as 10",
+		},
+		{
+			name:  "mixed version schemes",
+			files: []string{"001_init.sql", "20240101120000_users.sql"},
+			err:   "migration version 20240101120000 is not the same width as 001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := make(fstest.MapFS)
+			for _, filename := range tt.files {
+				fsys[filename] = &fstest.MapFile{Data: []byte("CREATE DATABASE test ENGINE = Atomic;")}
+			}
+
+			migrationDir, err := migrator.LoadMigrationDir(fsys)
+			if tt.err == "" {
+				require.NoError(t, err)
+				require.Len(t, migrationDir.Migrations, len(tt.files))
+				return
+			}
+
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	t.Run("migration before snapshot not included", func(t *testing.T) {
+		// The snapshot only includes 001_init and 002_users
+		fsys := fstest.MapFS{
+			"000_setup.sql":    {Data: []byte("CREATE DATABASE setup ENGINE = Atomic;")},
+			"001_init.sql":     {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+			"002_users.sql":    {Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"003_snapshot.sql": {Data: []byte(snapshotFile)},
+		}
+
+		_, err := migrator.LoadMigrationDir(fsys)
+		require.EqualError(t, err, "migration 000_setup sorts before snapshot 003_snapshot but is not included in it")
+	})
+}
+
+func TestMigrationDir_MigrationsThrough(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101120000_init.sql":   {Data: []byte("CREATE DATABASE first ENGINE = Atomic;")},
+		"20240101120200_tables.sql": {Data: []byte("CREATE TABLE first.t (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+		"20240101120300.sql":        {Data: []byte("CREATE VIEW first.v AS SELECT 1;")},
+	}
+
+	migrationDir, err := migrator.LoadMigrationDir(fsys)
+	require.NoError(t, err)
+
+	versions := func(migrations []*migrator.Migration) []string {
+		result := make([]string, 0, len(migrations))
+		for _, mig := range migrations {
+			result = append(result, mig.Version)
+		}
+		return result
+	}
+
+	t.Run("timestamp prefix", func(t *testing.T) {
+		migrations, err := migrationDir.MigrationsThrough("20240101120200")
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240101120000_init", "20240101120200_tables"}, versions(migrations))
+	})
+
+	t.Run("full version", func(t *testing.T) {
+		migrations, err := migrationDir.MigrationsThrough("20240101120000_init")
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240101120000_init"}, versions(migrations))
+	})
+
+	t.Run("last migration", func(t *testing.T) {
+		migrations, err := migrationDir.MigrationsThrough("20240101120300")
+		require.NoError(t, err)
+		require.Len(t, migrations, 3)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := migrationDir.MigrationsThrough("20240101120100")
+		require.EqualError(t, err, "migration not found: 20240101120100")
+	})
+}
+
 func TestMigration_EmptyVersion(t *testing.T) {
 	migration, err := migrator.LoadMigration("", strings.NewReader("CREATE DATABASE test;"))
 	require.NoError(t, err)
@@ -371,6 +525,60 @@ CREATE DATABASE test ENGINE = Atomic;`,
 	}
 }
 
+func TestMigration_IsNoOp(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		isNoOp  bool
+	}{
+		{name: "empty", content: "", isNoOp: true},
+		{name: "whitespace", content: "\n\n", isNoOp: true},
+		{name: "comments_only", content: "-- Placeholder\n/* Nothing to apply yet */", isNoOp: true},
+		{name: "transactional_comments_only", content: "-- housekeeper:transactional\n-- TODO", isNoOp: true},
+		{name: "statements", content: "-- Init\nCREATE DATABASE test ENGINE = Atomic;", isNoOp: false},
+		{name: "comments_with_down_section", content: "-- Placeholder\n-- housekeeper:down\nDROP DATABASE test;", isNoOp: true},
+		{
+			name:    "snapshot",
+			content: "-- housekeeper:snapshot\n-- version: 002_snapshot\n-- included_migrations: 001_init\n",
+			isNoOp:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := migrator.LoadMigration("001_test", strings.NewReader(tt.content))
+			require.NoError(t, err)
+			require.Equal(t, tt.isNoOp, migration.IsNoOp())
+		})
+	}
+
+	t.Run("included in sum file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"001_init.sql":  {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+			"002_notes.sql": {Data: []byte("-- Placeholder for the users table\n")},
+			"003_empty.sql": {Data: []byte("")},
+		}
+
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+		require.Len(t, migDir.Migrations, 3)
+
+		content := sumFileContent(t, migDir.SumFile)
+		require.Contains(t, content, "002_notes.sql")
+		require.Contains(t, content, "003_empty.sql")
+
+		valid, err := migDir.Validate()
+		require.NoError(t, err)
+		require.True(t, valid)
+
+		// Editing a comment-only migration is detected like any other change
+		fsys["002_notes.sql"] = &fstest.MapFile{Data: []byte("-- Placeholder for the orders table\n")}
+		valid, err = migDir.Validate()
+		require.NoError(t, err)
+		require.False(t, valid)
+	})
+}
+
 func TestMigrationDir_Rehash(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -549,6 +757,144 @@ func TestMigrationDir_Rehash_NilFilesystem(t *testing.T) {
 	require.Contains(t, err.Error(), "filesystem reference is nil")
 }
 
+func TestMigrationDir_RehashDryRun(t *testing.T) {
+	newFS := func() fstest.MapFS {
+		return fstest.MapFS{
+			"20240101120000.sql": {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+			"20240101120100.sql": {Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+		}
+	}
+
+	sumFileContent := func(t *testing.T, migDir *migrator.MigrationDir) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		_, err := migDir.SumFile.WriteTo(&buf)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		migDir, err := migrator.LoadMigrationDir(newFS())
+		require.NoError(t, err)
+
+		changed, diff, err := migDir.RehashDryRun()
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Empty(t, diff)
+	})
+
+	t.Run("modified migration", func(t *testing.T) {
+		fsys := newFS()
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+		before := sumFileContent(t, migDir)
+
+		fsys["20240101120000.sql"] = &fstest.MapFile{Data: []byte("CREATE DATABASE modified ENGINE = Atomic;")}
+
+		changed, diff, err := migDir.RehashDryRun()
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		// Hashes are chained, so the total and both entries change
+		lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+		require.Len(t, lines, 6)
+		require.True(t, strings.HasPrefix(lines[0], "-h1:"))
+		require.True(t, strings.HasPrefix(lines[1], "+h1:"))
+		require.True(t, strings.HasPrefix(lines[2], "-20240101120000.sql h1:"))
+		require.True(t, strings.HasPrefix(lines[3], "+20240101120000.sql h1:"))
+		require.True(t, strings.HasPrefix(lines[4], "-20240101120100.sql h1:"))
+		require.True(t, strings.HasPrefix(lines[5], "+20240101120100.sql h1:"))
+
+		// Nothing is modified
+		require.Equal(t, before, sumFileContent(t, migDir))
+		require.Len(t, migDir.Migrations, 2)
+	})
+
+	t.Run("added migration", func(t *testing.T) {
+		fsys := newFS()
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		fsys["20240101120200.sql"] = &fstest.MapFile{Data: []byte("CREATE DATABASE other ENGINE = Atomic;")}
+
+		changed, diff, err := migDir.RehashDryRun()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.NotContains(t, diff, "20240101120000.sql")
+		require.NotContains(t, diff, "20240101120100.sql")
+		require.Contains(t, diff, "+20240101120200.sql h1:")
+	})
+
+	t.Run("removed migration", func(t *testing.T) {
+		fsys := newFS()
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		delete(fsys, "20240101120100.sql")
+
+		changed, diff, err := migDir.RehashDryRun()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Contains(t, diff, "-20240101120100.sql h1:")
+		require.NotContains(t, diff, "+20240101120100.sql")
+	})
+
+	t.Run("invalid migration", func(t *testing.T) {
+		fsys := newFS()
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		fsys["20240101120200.sql"] = &fstest.MapFile{Data: []byte("NOT VALID SQL;")}
+
+		_, _, err = migDir.RehashDryRun()
+		require.ErrorContains(t, err, "failed to load migration")
+	})
+
+	t.Run("nil filesystem", func(t *testing.T) {
+		migDir := &migrator.MigrationDir{SumFile: migrator.NewSumFile()}
+
+		_, _, err := migDir.RehashDryRun()
+		require.ErrorContains(t, err, "filesystem reference is nil")
+	})
+}
+
+func TestMigrationDir_WriteSumFile(t *testing.T) {
+	dir := t.TempDir()
+	fsys := fstest.MapFS{
+		"20240101120000.sql": &fstest.MapFile{Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+		"20240101120100.sql": &fstest.MapFile{Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+	}
+
+	migDir, err := migrator.LoadMigrationDir(fsys)
+	require.NoError(t, err)
+
+	sumPath := filepath.Join(dir, "housekeeper.sum")
+	require.NoError(t, os.WriteFile(sumPath, []byte("h1:stale="), consts.ModeFile))
+	require.NoError(t, migDir.WriteSumFile(sumPath))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary file should be renamed into place")
+
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	require.Equal(t, consts.ModeFile, info.Mode().Perm())
+
+	var expected bytes.Buffer
+	_, err = migDir.SumFile.WriteTo(&expected)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(sumPath)
+	require.NoError(t, err)
+	require.Equal(t, expected.String(), string(content))
+
+	t.Run("missing directory", func(t *testing.T) {
+		err := migDir.WriteSumFile(filepath.Join(dir, "missing", "housekeeper.sum"))
+		require.ErrorContains(t, err, "failed to create temporary sum file")
+	})
+}
+
 func TestMigrationDir_Rehash_OrderPreservation(t *testing.T) {
 	// Test that rehash preserves lexical ordering
 	files := map[string]string{
@@ -977,3 +1323,248 @@ func TestMigrationDir_LoadFromTestdata(t *testing.T) {
 	}
 	require.True(t, found, "Should find 004_products migration after snapshot")
 }
+
+func TestMigration_TransactionalDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected bool
+	}{
+		{
+			name:     "no marker",
+			sql:      "CREATE DATABASE test ENGINE = Atomic;",
+			expected: false,
+		},
+		{
+			name: "marker",
+			sql: `-- housekeeper:transactional
+ALTER TABLE test.events DELETE WHERE id = 0;`,
+			expected: true,
+		},
+		{
+			name: "marker after other comments",
+			sql: `-- Backfill events
+  -- housekeeper:transactional
+ALTER TABLE test.events DELETE WHERE id = 0;`,
+			expected: true,
+		},
+		{
+			name:     "marker text inside another comment",
+			sql:      "-- not -- housekeeper:transactional\nCREATE DATABASE test ENGINE = Atomic;",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := migrator.LoadMigration("001_test", strings.NewReader(tt.sql))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, migration.IsTransactional)
+		})
+	}
+}
+
+func TestMigration_DownSection(t *testing.T) {
+	t.Run("parses up and down sections", func(t *testing.T) {
+		sql := `-- housekeeper:transactional
+CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;
+ALTER TABLE test.users ADD COLUMN name String;
+
+-- housekeeper:down
+ALTER TABLE test.users DROP COLUMN name;
+DROP TABLE test.users;`
+
+		migration, err := migrator.LoadMigration("001_test", strings.NewReader(sql))
+		require.NoError(t, err)
+		require.True(t, migration.IsTransactional)
+		require.Len(t, migration.Statements, 3)
+		require.NotNil(t, migration.Statements[0].CommentStatement)
+		require.NotNil(t, migration.Statements[1].CreateTable)
+		require.NotNil(t, migration.Statements[2].AlterTable)
+		require.Len(t, migration.DownStatements, 2)
+		require.NotNil(t, migration.DownStatements[0].AlterTable)
+		require.NotNil(t, migration.DownStatements[1].DropTable)
+	})
+
+	t.Run("no down section", func(t *testing.T) {
+		migration, err := migrator.LoadMigration("001_test", strings.NewReader("CREATE DATABASE test ENGINE = Atomic;"))
+		require.NoError(t, err)
+		require.Len(t, migration.Statements, 1)
+		require.Empty(t, migration.DownStatements)
+	})
+
+	t.Run("transactional marker in down section only", func(t *testing.T) {
+		sql := `CREATE DATABASE test ENGINE = Atomic;
+-- housekeeper:down
+-- housekeeper:transactional
+DROP DATABASE test;`
+
+		migration, err := migrator.LoadMigration("001_test", strings.NewReader(sql))
+		require.NoError(t, err)
+		require.False(t, migration.IsTransactional)
+		require.Len(t, migration.DownStatements, 2)
+		require.NotNil(t, migration.DownStatements[1].DropDatabase)
+	})
+
+	errorTests := map[string]struct {
+		sql string
+		err string
+	}{
+		"multiple markers": {
+			sql: "CREATE DATABASE test ENGINE = Atomic;\n-- housekeeper:down\nDROP DATABASE test;\n-- housekeeper:down\n",
+			err: "multiple -- housekeeper:down comments",
+		},
+		"snapshot with down section": {
+			sql: "-- housekeeper:snapshot\n-- version: 001_test\nCREATE DATABASE test ENGINE = Atomic;\n-- housekeeper:down\nDROP DATABASE test;",
+			err: "snapshots can't have a down section",
+		},
+		"invalid down section": {
+			sql: "CREATE DATABASE test ENGINE = Atomic;\n-- housekeeper:down\nDROP NONSENSE;",
+			err: "failed to parse down section",
+		},
+	}
+
+	for name, tt := range errorTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := migrator.LoadMigration("001_test", strings.NewReader(tt.sql))
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	t.Run("sum file covers the down section", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"001_test.sql": {Data: []byte("CREATE DATABASE test ENGINE = Atomic;\n-- housekeeper:down\nDROP DATABASE test;\n")},
+		}
+
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		isValid, err := migDir.Validate()
+		require.NoError(t, err)
+		require.True(t, isValid)
+
+		fsys["001_test.sql"] = &fstest.MapFile{
+			Data: []byte("CREATE DATABASE test ENGINE = Atomic;\n-- housekeeper:down\nDROP DATABASE IF EXISTS test;\n"),
+		}
+
+		isValid, err = migDir.Validate()
+		require.NoError(t, err)
+		require.False(t, isValid, "Validation should fail after changing only the down section")
+	})
+}
+
+func TestMigrationDir_DownFile(t *testing.T) {
+	files := func() fstest.MapFS {
+		return fstest.MapFS{
+			"001_init.sql":        {Data: []byte("CREATE DATABASE test ENGINE = Atomic;\n")},
+			"002_users.sql":       {Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;\n")},
+			"002_users.down.sql":  {Data: []byte("DROP TABLE test.users;\n")},
+			"003_events.sql":      {Data: []byte("CREATE TABLE test.events (id UInt64) ENGINE = MergeTree() ORDER BY id;\n")},
+			"003_events.down.sql": {Data: []byte("DROP TABLE test.events;\n")},
+		}
+	}
+
+	t.Run("loads down statements", func(t *testing.T) {
+		migDir, err := migrator.LoadMigrationDir(files())
+		require.NoError(t, err)
+		require.Len(t, migDir.Migrations, 3)
+
+		require.Empty(t, migDir.Migrations[0].DownStatements)
+		require.Len(t, migDir.Migrations[1].DownStatements, 1)
+		require.Equal(t, "users", migDir.Migrations[1].DownStatements[0].DropTable.Name)
+		require.Len(t, migDir.Migrations[2].DownStatements, 1)
+		require.Equal(t, "events", migDir.Migrations[2].DownStatements[0].DropTable.Name)
+	})
+
+	t.Run("rehash includes down files", func(t *testing.T) {
+		fsys := files()
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+		require.NoError(t, migDir.Rehash())
+		require.Len(t, migDir.Migrations, 3)
+		require.Len(t, migDir.Migrations[1].DownStatements, 1)
+
+		var buf bytes.Buffer
+		_, err = migDir.SumFile.WriteTo(&buf)
+		require.NoError(t, err)
+
+		var entries []string
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n")[1:] {
+			entries = append(entries, strings.Fields(line)[0])
+		}
+		require.Equal(t, []string{
+			"001_init.sql",
+			"002_users.down.sql",
+			"002_users.sql",
+			"003_events.down.sql",
+			"003_events.sql",
+		}, entries)
+
+		isValid, err := migDir.Validate()
+		require.NoError(t, err)
+		require.True(t, isValid)
+
+		fsys["002_users.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE IF EXISTS test.users;\n")}
+
+		isValid, err = migDir.Validate()
+		require.NoError(t, err)
+		require.False(t, isValid, "Validation should fail after changing only the down file")
+	})
+
+	t.Run("generated sum file matches rehash", func(t *testing.T) {
+		loaded, err := migrator.LoadMigrationDir(files())
+		require.NoError(t, err)
+
+		rehashed, err := migrator.LoadMigrationDir(files())
+		require.NoError(t, err)
+		require.NoError(t, rehashed.Rehash())
+
+		var loadedBuf, rehashedBuf bytes.Buffer
+		_, err = loaded.SumFile.WriteTo(&loadedBuf)
+		require.NoError(t, err)
+		_, err = rehashed.SumFile.WriteTo(&rehashedBuf)
+		require.NoError(t, err)
+		require.Equal(t, rehashedBuf.String(), loadedBuf.String())
+	})
+
+	errorTests := map[string]struct {
+		files fstest.MapFS
+		err   string
+	}{
+		"down section and down file": {
+			files: fstest.MapFS{
+				"001_init.sql":      {Data: []byte("CREATE DATABASE test ENGINE = Atomic;\n-- housekeeper:down\nDROP DATABASE test;\n")},
+				"001_init.down.sql": {Data: []byte("DROP DATABASE test;\n")},
+			},
+			err: "migration 001_init has both a down section and a down file",
+		},
+		"no matching migration": {
+			files: fstest.MapFS{
+				"001_init.sql":       {Data: []byte("CREATE DATABASE test ENGINE = Atomic;\n")},
+				"002_users.down.sql": {Data: []byte("DROP TABLE test.users;\n")},
+			},
+			err: "down file has no matching migration: 002_users.down.sql",
+		},
+		"snapshot": {
+			files: fstest.MapFS{
+				"001_snapshot.sql":      {Data: []byte("-- housekeeper:snapshot\n-- version: 001_snapshot\n-- description: test\n-- created_at: 2024-01-01T00:00:00Z\n-- included_migrations: \n-- cumulative_hash: abc\nCREATE DATABASE test ENGINE = Atomic;\n")},
+				"001_snapshot.down.sql": {Data: []byte("DROP DATABASE test;\n")},
+			},
+			err: "snapshots can't have a down file: 001_snapshot.down.sql",
+		},
+		"invalid down file": {
+			files: fstest.MapFS{
+				"001_init.sql":      {Data: []byte("CREATE DATABASE test ENGINE = Atomic;\n")},
+				"001_init.down.sql": {Data: []byte("DROP NONSENSE;\n")},
+			},
+			err: "failed to parse down file: 001_init.down.sql",
+		},
+	}
+
+	for name, tt := range errorTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := migrator.LoadMigrationDir(tt.files)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package migrator
 
 import (
+	"cmp"
 	"context"
+	"math"
+	"slices"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
@@ -22,6 +25,11 @@ const (
 	// significant migration milestones. Snapshots may not contain
 	// actual DDL statements but serve as metadata markers.
 	SnapshotRevision RevisionKind = "snapshot"
+
+	// RollbackRevision records the execution of a migration's down statements.
+	// A migration whose latest revision is a rollback is no longer applied,
+	// so it's pending again and will be re-applied by the next migrate.
+	RollbackRevision RevisionKind = "rollback"
 )
 
 type (
@@ -96,6 +104,12 @@ type (
 		// and identification of specific statement modifications.
 		PartialHashes []string
 
+		// StatementTimes records the execution duration of each applied
+		// statement in the migration, in statement order. Comment-only
+		// statements are recorded with a zero duration. Used to identify
+		// expensive DDL across migrations.
+		StatementTimes []time.Duration
+
 		// HousekeeperVersion records the version of the Housekeeper tool
 		// that executed the migration. Used for compatibility tracking
 		// and debugging version-specific migration behaviors.
@@ -117,10 +131,10 @@ type (
 	// have been executed, failed, or are pending, encapsulating the logic for
 	// handling different revision kinds and error states.
 	RevisionSet struct {
-		// revisions contains all revisions indexed by version for fast lookup
+		// revisions contains the latest revision of each version for fast lookup
 		revisions map[string]*Revision
 
-		// orderedVersions maintains the order of revisions as they appear in the database
+		// orderedVersions maintains the order of versions as they appear in the database
 		orderedVersions []string
 	}
 )
@@ -129,7 +143,8 @@ type (
 //
 // The RevisionSet provides convenient methods for querying migration status
 // without requiring callers to understand the internal revision structure
-// or filtering logic.
+// or filtering logic. Revisions must be in execution order: when a version has
+// several revisions, such as a retried or rolled back migration, the last one wins.
 //
 // Example usage:
 //
@@ -151,8 +166,10 @@ func NewRevisionSet(revisions []*Revision) *RevisionSet {
 	orderedVersions := make([]string, 0, len(revisions))
 
 	for _, revision := range revisions {
+		if _, seen := revisionMap[revision.Version]; !seen {
+			orderedVersions = append(orderedVersions, revision.Version)
+		}
 		revisionMap[revision.Version] = revision
-		orderedVersions = append(orderedVersions, revision.Version)
 	}
 
 	return &RevisionSet{
@@ -186,8 +203,16 @@ func NewRevisionSet(revisions []*Revision) *RevisionSet {
 //	// Bulk operations
 //	pending := revisionSet.GetPending(migrationDir)
 //
+// Revisions tables created before per-statement timings were recorded are read as is,
+// with empty timings, since only the executor upgrades the table when it migrates.
+//
 // Returns an error if the database query fails.
 func LoadRevisions(ctx context.Context, ch ClickHouse) (*RevisionSet, error) {
+	statementTimes, err := statementTimesColumn(ctx, ch)
+	if err != nil {
+		return nil, err
+	}
+
 	rows, err := ch.Query(ctx, `
 		SELECT
 			version,
@@ -199,9 +224,10 @@ func LoadRevisions(ctx context.Context, ch ClickHouse) (*RevisionSet, error) {
 			total,
 			hash,
 			partial_hashes,
+			`+statementTimes+`,
 			housekeeper_version
 		FROM housekeeper.revisions
-		ORDER BY version ASC
+		ORDER BY version ASC, executed_at ASC
 	`)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load revisions")
@@ -216,6 +242,7 @@ func LoadRevisions(ctx context.Context, ch ClickHouse) (*RevisionSet, error) {
 		var kindStr string
 		var applied uint32
 		var total uint32
+		var statementTimesMs []uint64
 
 		err := rows.Scan(
 			&revision.Version,
@@ -227,17 +254,19 @@ func LoadRevisions(ctx context.Context, ch ClickHouse) (*RevisionSet, error) {
 			&total,
 			&revision.Hash,
 			&revision.PartialHashes,
+			&statementTimesMs,
 			&revision.HousekeeperVersion,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan revision row")
 		}
 
-		const maxDurationMs = 9223372036854775 // max safe milliseconds before overflow
-		if executionTimeMs <= maxDurationMs {
-			revision.ExecutionTime = time.Duration(int64(executionTimeMs)) * time.Millisecond
-		} else {
-			revision.ExecutionTime = time.Duration(1<<63 - 1) // max time.Duration
+		revision.ExecutionTime = millisecondsToDuration(executionTimeMs)
+		if len(statementTimesMs) > 0 {
+			revision.StatementTimes = make([]time.Duration, len(statementTimesMs))
+			for i, ms := range statementTimesMs {
+				revision.StatementTimes[i] = millisecondsToDuration(ms)
+			}
 		}
 		revision.Kind = RevisionKind(kindStr)
 		revision.Applied = int(applied)
@@ -256,6 +285,39 @@ func LoadRevisions(ctx context.Context, ch ClickHouse) (*RevisionSet, error) {
 	return NewRevisionSet(revisions), nil
 }
 
+// statementTimesColumn returns the expression selecting the per-statement timings of a
+// revision. Tables that predate the statement_times_ms column get an empty array instead.
+func statementTimesColumn(ctx context.Context, ch ClickHouse) (string, error) {
+	rows, err := ch.Query(ctx, `
+		SELECT 1
+		FROM system.columns
+		WHERE database = 'housekeeper' AND table = 'revisions' AND name = 'statement_times_ms'
+	`)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to check revisions table columns")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return "statement_times_ms", nil
+	}
+	if err := rows.Err(); err != nil {
+		return "", errors.Wrap(err, "failed to check revisions table columns")
+	}
+
+	return "CAST([], 'Array(UInt64)') AS statement_times_ms", nil
+}
+
+// millisecondsToDuration converts a millisecond count stored in ClickHouse to a
+// time.Duration, clamping values that would overflow.
+func millisecondsToDuration(ms uint64) time.Duration {
+	const maxDurationMs = 9223372036854775 // max safe milliseconds before overflow
+	if ms <= maxDurationMs {
+		return time.Duration(int64(ms)) * time.Millisecond
+	}
+	return time.Duration(1<<63 - 1) // max time.Duration
+}
+
 // IsCompleted returns true if the migration has been successfully executed.
 //
 // A migration is considered completed if:
@@ -645,3 +707,83 @@ func (rs *RevisionSet) GetPartiallyApplied(migrationDir *MigrationDir) []*Migrat
 
 	return partiallyApplied
 }
+
+// StatementTiming describes the recorded execution time of a single statement
+// within a migration revision.
+type StatementTiming struct {
+	// Version is the migration version containing the statement
+	Version string
+
+	// Statement is the 1-based index of the statement within the migration
+	Statement int
+
+	// Duration is how long the statement took to execute
+	Duration time.Duration
+}
+
+// SlowestStatements returns up to n statements with the longest recorded execution
+// times across all revisions, ordered from slowest to fastest.
+//
+// Revisions recorded before per-statement timing was tracked have no statement
+// times and are ignored.
+//
+// Example usage:
+//
+//	for _, timing := range revisionSet.SlowestStatements(5) {
+//		fmt.Printf("%s statement %d: %v\n", timing.Version, timing.Statement, timing.Duration)
+//	}
+func (rs *RevisionSet) SlowestStatements(n int) []StatementTiming {
+	timings := rs.statementTimings()
+	slices.SortStableFunc(timings, func(a, b StatementTiming) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+
+	if n >= 0 && len(timings) > n {
+		timings = timings[:n]
+	}
+
+	return timings
+}
+
+// StatementTimePercentile returns the p-th percentile (0-100) of recorded statement
+// execution times across all revisions using the nearest-rank method.
+//
+// Returns zero if no statement times have been recorded.
+//
+// Example usage:
+//
+//	fmt.Printf("p50: %v, p95: %v, p99: %v\n",
+//		revisionSet.StatementTimePercentile(50),
+//		revisionSet.StatementTimePercentile(95),
+//		revisionSet.StatementTimePercentile(99))
+func (rs *RevisionSet) StatementTimePercentile(p float64) time.Duration {
+	timings := rs.statementTimings()
+	if len(timings) == 0 {
+		return 0
+	}
+
+	durations := make([]time.Duration, len(timings))
+	for i, timing := range timings {
+		durations[i] = timing.Duration
+	}
+	slices.Sort(durations)
+
+	p = max(0, min(p, 100))
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	return durations[max(rank-1, 0)]
+}
+
+// statementTimings flattens the statement times of all revisions in database order
+func (rs *RevisionSet) statementTimings() []StatementTiming {
+	var timings []StatementTiming
+	for _, version := range rs.orderedVersions {
+		for i, duration := range rs.revisions[version].StatementTimes {
+			timings = append(timings, StatementTiming{
+				Version:   version,
+				Statement: i + 1,
+				Duration:  duration,
+			})
+		}
+	}
+	return timings
+}
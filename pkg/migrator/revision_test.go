@@ -2,6 +2,7 @@ package migrator_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,9 +14,13 @@ import (
 )
 
 type (
-	// mockClickHouse implements the ClickHouse interface for testing
+	// mockClickHouse implements the ClickHouse interface for testing. Revisions tables
+	// have the statement_times_ms column unless legacyRevisions is set, and checking
+	// for the column fails with columnsErr when set.
 	mockClickHouse struct {
-		queryFunc func(ctx context.Context, query string, args ...any) (driver.Rows, error)
+		queryFunc       func(ctx context.Context, query string, args ...any) (driver.Rows, error)
+		legacyRevisions bool
+		columnsErr      error
 	}
 
 	// mockRows implements driver.Rows for testing
@@ -46,6 +51,7 @@ func TestLoadRevisions(t *testing.T) {
 					5,                     // total
 					"abc123hash",          // hash
 					[]string{"h1", "h2"},  // partial_hashes
+					[]uint64{1500, 1000},  // statement_times_ms
 					"1.0.0",               // housekeeper_version
 				},
 				{
@@ -58,6 +64,7 @@ func TestLoadRevisions(t *testing.T) {
 					5,
 					"def456hash",
 					[]string{"h3", "h4", "h5"},
+					nil,
 					"1.0.1",
 				},
 			},
@@ -95,6 +102,7 @@ func TestLoadRevisions(t *testing.T) {
 		require.Equal(t, 5, rev1.Total)
 		require.Equal(t, "abc123hash", rev1.Hash)
 		require.Equal(t, []string{"h1", "h2"}, rev1.PartialHashes)
+		require.Equal(t, []time.Duration{1500 * time.Millisecond, time.Second}, rev1.StatementTimes)
 		require.Equal(t, "1.0.0", rev1.HousekeeperVersion)
 
 		// Check second revision via RevisionSet
@@ -116,11 +124,46 @@ func TestLoadRevisions(t *testing.T) {
 		require.Equal(t, 5, rev2.Total)
 		require.Equal(t, "def456hash", rev2.Hash)
 		require.Equal(t, []string{"h3", "h4", "h5"}, rev2.PartialHashes)
+		require.Nil(t, rev2.StatementTimes)
 		require.Equal(t, "1.0.1", rev2.HousekeeperVersion)
 
 		require.True(t, mockRows.closed)
 	})
 
+	t.Run("legacy_table", func(t *testing.T) {
+		// Revisions tables created before statement timings were recorded
+		mockRows := &mockRows{
+			data: [][]any{
+				{"20240101120000_init", time.Now(), int64(1000), "migration", nil, 2, 2, "hash", []string{"h1", "h2"}, []uint64{}, "1.0.0"},
+			},
+		}
+
+		var query string
+		mockCH := &mockClickHouse{
+			legacyRevisions: true,
+			queryFunc: func(ctx context.Context, q string, args ...any) (driver.Rows, error) {
+				query = q
+				return mockRows, nil
+			},
+		}
+
+		revisionSet, err := migrator.LoadRevisions(context.Background(), mockCH)
+		require.NoError(t, err)
+		require.Contains(t, query, "CAST([], 'Array(UInt64)') AS statement_times_ms")
+		require.True(t, revisionSet.IsCompleted(&migrator.Migration{
+			Version:    "20240101120000_init",
+			Statements: make([]*parser.Statement, 2),
+		}))
+		require.Empty(t, revisionSet.GetRevision(&migrator.Migration{Version: "20240101120000_init"}).StatementTimes)
+	})
+
+	t.Run("columns_error", func(t *testing.T) {
+		mockCH := &mockClickHouse{columnsErr: errors.New("connection reset")}
+
+		_, err := migrator.LoadRevisions(context.Background(), mockCH)
+		require.ErrorContains(t, err, "failed to check revisions table columns: connection reset")
+	})
+
 	t.Run("query_error", func(t *testing.T) {
 		mockCH := &mockClickHouse{
 			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
@@ -139,7 +182,7 @@ func TestLoadRevisions(t *testing.T) {
 	t.Run("scan_error", func(t *testing.T) {
 		mockRows := &mockRows{
 			data: [][]any{
-				{"20240101120000_init", time.Now(), int64(1000), "migration", nil, 1, 1, "hash", []string{}, "1.0.0"},
+				{"20240101120000_init", time.Now(), int64(1000), "migration", nil, 1, 1, "hash", []string{}, []uint64{}, "1.0.0"},
 			},
 			scanErr: errors.New("scan failed"),
 		}
@@ -199,6 +242,15 @@ func TestLoadRevisions(t *testing.T) {
 }
 
 func (m *mockClickHouse) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	if strings.Contains(query, "FROM system.columns") {
+		if m.columnsErr != nil {
+			return nil, m.columnsErr
+		}
+		if m.legacyRevisions {
+			return &mockRows{}, nil
+		}
+		return &mockRows{data: [][]any{{1}}}, nil
+	}
 	if m.queryFunc != nil {
 		return m.queryFunc(ctx, query, args...)
 	}
@@ -277,6 +329,12 @@ func (m *mockRows) Scan(dest ...any) error {
 			} else if val == nil {
 				*d = nil
 			}
+		case *[]uint64:
+			if arr, ok := val.([]uint64); ok {
+				*d = arr
+			} else if val == nil {
+				*d = nil
+			}
 		}
 	}
 
@@ -307,7 +365,7 @@ func (m *mockRows) Totals(dest ...any) error {
 func (m *mockRows) Columns() []string {
 	return []string{
 		"version", "executed_at", "execution_time_ms", "kind", "error",
-		"applied", "total", "hash", "partial_hashes", "housekeeper_version",
+		"applied", "total", "hash", "partial_hashes", "statement_times_ms", "housekeeper_version",
 	}
 }
 
@@ -329,6 +387,32 @@ func TestNewRevisionSet(t *testing.T) {
 	require.False(t, revisionSet.HasRevision("nonexistent"))
 }
 
+func TestNewRevisionSet_MultipleAttempts(t *testing.T) {
+	// Revisions are loaded in execution order, so the last revision of a version wins
+	revisions := []*migrator.Revision{
+		{Version: "001_create_users", Kind: migrator.StandardRevision, Applied: 1, Total: 1},
+		{Version: "002_add_email", Kind: migrator.StandardRevision, Error: stringPtr("failed"), Applied: 0, Total: 1},
+		{Version: "002_add_email", Kind: migrator.StandardRevision, Applied: 1, Total: 1},
+		{Version: "003_create_orders", Kind: migrator.StandardRevision, Applied: 1, Total: 1},
+		{Version: "003_create_orders", Kind: migrator.RollbackRevision, Applied: 1, Total: 1},
+	}
+
+	revisionSet := migrator.NewRevisionSet(revisions)
+	require.Equal(t, 3, revisionSet.Count())
+	require.Equal(t, []string{"001_create_users", "002_add_email"}, revisionSet.GetExecutedVersions())
+
+	addEmail := &migrator.Migration{Version: "002_add_email", Statements: make([]*parser.Statement, 1)}
+	require.True(t, revisionSet.IsCompleted(addEmail))
+	require.False(t, revisionSet.IsFailed(addEmail))
+
+	// A rolled back migration is pending again
+	createOrders := &migrator.Migration{Version: "003_create_orders", Statements: make([]*parser.Statement, 1)}
+	require.False(t, revisionSet.IsCompleted(createOrders))
+	require.True(t, revisionSet.IsPending(createOrders))
+	require.False(t, revisionSet.IsFailed(createOrders))
+	require.False(t, revisionSet.IsPartiallyApplied(createOrders))
+}
+
 func TestRevisionSet_IsCompleted(t *testing.T) {
 	now := time.Now()
 	revisions := []*migrator.Revision{
@@ -950,3 +1034,39 @@ func TestRevisionSet_GetPartiallyApplied(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestRevisionSet_StatementTimings(t *testing.T) {
+	revisionSet := migrator.NewRevisionSet([]*migrator.Revision{
+		{
+			Version:        "001_create_users",
+			Kind:           migrator.StandardRevision,
+			StatementTimes: []time.Duration{10 * time.Millisecond, 300 * time.Millisecond},
+		},
+		{Version: "002_legacy", Kind: migrator.StandardRevision}, // recorded before timing was tracked
+		{
+			Version:        "003_add_index",
+			Kind:           migrator.StandardRevision,
+			StatementTimes: []time.Duration{2 * time.Second, 0, 40 * time.Millisecond},
+		},
+	})
+
+	t.Run("slowest statements", func(t *testing.T) {
+		slowest := revisionSet.SlowestStatements(2)
+		require.Equal(t, []migrator.StatementTiming{
+			{Version: "003_add_index", Statement: 1, Duration: 2 * time.Second},
+			{Version: "001_create_users", Statement: 2, Duration: 300 * time.Millisecond},
+		}, slowest)
+
+		require.Len(t, revisionSet.SlowestStatements(10), 5)
+		require.Empty(t, migrator.NewRevisionSet(nil).SlowestStatements(3))
+	})
+
+	t.Run("percentiles", func(t *testing.T) {
+		require.Equal(t, time.Duration(0), revisionSet.StatementTimePercentile(0))
+		require.Equal(t, 40*time.Millisecond, revisionSet.StatementTimePercentile(50))
+		require.Equal(t, 300*time.Millisecond, revisionSet.StatementTimePercentile(80))
+		require.Equal(t, 2*time.Second, revisionSet.StatementTimePercentile(99))
+		require.Equal(t, 2*time.Second, revisionSet.StatementTimePercentile(100))
+		require.Equal(t, time.Duration(0), migrator.NewRevisionSet(nil).StatementTimePercentile(95))
+	})
+}
@@ -16,6 +16,9 @@ import (
 
 const (
 	snapshotMarker = "-- housekeeper:snapshot"
+
+	// snapshotSQLHeader is the comment preceding the cumulative SQL of a snapshot file
+	snapshotSQLHeader = "-- Cumulative SQL from all included migrations"
 )
 
 type (
@@ -264,7 +267,7 @@ func (c *Snapshot) WriteTo(w io.Writer) (int64, error) {
 
 	// Write formatted SQL statements
 	if len(c.Statements) > 0 {
-		n, err := fmt.Fprintln(w, "-- Cumulative SQL from all included migrations")
+		n, err := fmt.Fprintln(w, snapshotSQLHeader)
 		if err != nil {
 			return totalBytes, errors.Wrap(err, "failed to write SQL header")
 		}
@@ -0,0 +1,248 @@
+package migrator
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// SquashResult is the outcome of MigrationDir.Squash. Nothing is written to disk by
+// Squash: the caller writes the snapshot to Path, deletes the Obsolete files, and
+// writes the SumFile, e.g. with MigrationDir.WriteSumFile after reloading the directory.
+type SquashResult struct {
+	// Snapshot is the snapshot replacing the squashed migrations.
+	Snapshot *Snapshot
+
+	// Path is the name of the snapshot file within the migration directory.
+	Path string
+
+	// Content is the rendered snapshot file, as hashed into SumFile.
+	Content []byte
+
+	// Obsolete lists the files, relative to the migration directory, that are covered
+	// by the snapshot and safe to delete once it's written. This includes down files
+	// and any previous snapshot.
+	Obsolete []string
+
+	// SumFile is the sum file of the directory once the snapshot is written and the
+	// obsolete files are deleted.
+	SumFile *SumFile
+}
+
+// Squash replaces all migrations up to and including throughVersion with a single
+// snapshot. Unlike CreateSnapshot, it works on a prefix of the directory, so newer
+// migrations are kept, and it reports which files the snapshot makes obsolete along
+// with the sum file that results from replacing them.
+//
+// The version may be a full migration version or its timestamp prefix, as accepted by
+// MigrationsThrough. The snapshot is named after the last squashed migration's prefix
+// (e.g. "20240101120000_snapshot"), so it sorts exactly where the squashed migrations
+// did. An existing snapshot among the squashed migrations is folded into the new one,
+// while a snapshot newer than throughVersion is an error since the migrations before it
+// are already squashed.
+//
+// Before returning, the rendered snapshot is parsed back and its statements are checked
+// against the statements of the squashed migrations, so a snapshot that wouldn't
+// recreate the same schema is never reported as a success.
+//
+// Example usage:
+//
+//	result, err := migDir.Squash("20240601000000")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if err := os.WriteFile(filepath.Join(dir, result.Path), result.Content, consts.ModeFile); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	for _, path := range result.Obsolete {
+//		if err := os.Remove(filepath.Join(dir, path)); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func (m *MigrationDir) Squash(throughVersion string) (*SquashResult, error) {
+	if m.fs == nil {
+		return nil, errors.New("cannot squash: filesystem reference is nil")
+	}
+
+	squashed, err := m.MigrationsThrough(throughVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	last := squashed[len(squashed)-1]
+	if m.snapshot != nil && !slices.ContainsFunc(squashed, func(mig *Migration) bool { return mig.IsSnapshot }) {
+		return nil, errors.Errorf("migrations through %s are already squashed into snapshot %s", last.Version, m.snapshot.Version)
+	}
+	if len(squashed) == 1 && last.IsSnapshot {
+		return nil, errors.Errorf("nothing to squash: %s is already a snapshot", last.Version)
+	}
+
+	prefix, _, _ := strings.Cut(last.Version, "_")
+	version := prefix + "_snapshot"
+
+	snapshot, err := squashSnapshot(version, m.snapshot, squashed)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	if _, err := snapshot.WriteTo(&content); err != nil {
+		return nil, errors.Wrapf(err, "failed to render snapshot: %s", version)
+	}
+
+	if err := verifySquash(version, content.Bytes(), snapshot.Statements); err != nil {
+		return nil, err
+	}
+
+	result := &SquashResult{
+		Snapshot: snapshot,
+		Path:     version + ".sql",
+		Content:  content.Bytes(),
+	}
+
+	for _, mig := range squashed {
+		for _, path := range migrationFiles(mig) {
+			if path != result.Path {
+				result.Obsolete = append(result.Obsolete, path)
+			}
+		}
+	}
+
+	if result.SumFile, err = m.squashedSumFile(result, m.Migrations[len(squashed):]); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// squashSnapshot generates the snapshot replacing the squashed migrations. The body of
+// an existing snapshot stands in for the migrations it includes, and those migrations
+// are skipped when their files are still around.
+func squashSnapshot(version string, existing *Snapshot, squashed []*Migration) (*Snapshot, error) {
+	included := make(map[string]bool)
+	var versions []string
+	include := func(v string) {
+		if !included[v] {
+			included[v] = true
+			versions = append(versions, v)
+		}
+	}
+
+	sources := make([]*Migration, 0, len(squashed))
+	if existing != nil {
+		for _, v := range existing.IncludedMigrations {
+			include(v)
+		}
+		sources = append(sources, &Migration{Version: existing.Version, Statements: snapshotBody(existing)})
+	}
+
+	for _, mig := range squashed {
+		if mig.IsSnapshot || included[mig.Version] {
+			continue
+		}
+
+		include(mig.Version)
+		sources = append(sources, mig)
+	}
+
+	snapshot, err := GenerateSnapshot(version, "Squashed migrations through "+squashed[len(squashed)-1].Version, sources)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate snapshot: %s", version)
+	}
+
+	snapshot.IncludedMigrations = versions
+	return snapshot, nil
+}
+
+// verifySquash checks the rendered snapshot parses as a snapshot migration and that
+// its statements are the same as the statements it was generated from
+func verifySquash(version string, content []byte, statements []*parser.Statement) error {
+	mig, err := LoadMigration(version, bytes.NewReader(content))
+	if err != nil {
+		return errors.Wrapf(err, "squashed snapshot doesn't parse: %s", version)
+	}
+	if !mig.IsSnapshot {
+		return errors.Errorf("squashed snapshot isn't recognized as a snapshot: %s", version)
+	}
+
+	loaded, err := LoadSnapshot(bytes.NewReader(content))
+	if err != nil {
+		return errors.Wrapf(err, "squashed snapshot doesn't load: %s", version)
+	}
+
+	want, err := formatStatements(statements)
+	if err != nil {
+		return err
+	}
+	got, err := formatStatements(snapshotBody(loaded))
+	if err != nil {
+		return err
+	}
+
+	if got != want {
+		return errors.Errorf("squashed snapshot %s doesn't match the migrations it replaces", version)
+	}
+
+	return nil
+}
+
+// squashedSumFile hashes the files that remain once the snapshot replaces the
+// obsolete files, in the lexical order used by Rehash
+func (m *MigrationDir) squashedSumFile(result *SquashResult, remaining []*Migration) (*SumFile, error) {
+	paths := []string{result.Path}
+	for _, mig := range remaining {
+		paths = append(paths, migrationFiles(mig)...)
+	}
+	slices.Sort(paths)
+
+	sumFile := NewSumFile()
+	for _, path := range paths {
+		if path == result.Path {
+			if err := sumFile.Add(path, bytes.NewReader(result.Content)); err != nil {
+				return nil, errors.Wrapf(err, "failed to hash migration: %s", path)
+			}
+			continue
+		}
+
+		f, err := m.fs.Open(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open for hashing: %s", path)
+		}
+
+		err = sumFile.Add(path, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to hash migration: %s", path)
+		}
+	}
+
+	return sumFile, nil
+}
+
+// snapshotBody returns the statements of a snapshot without the comment introducing
+// its cumulative SQL, which WriteTo adds back when the snapshot is written
+func snapshotBody(s *Snapshot) []*parser.Statement {
+	if len(s.Statements) > 0 {
+		if comment := s.Statements[0].CommentStatement; comment != nil && comment.Comment == snapshotSQLHeader {
+			return s.Statements[1:]
+		}
+	}
+
+	return s.Statements
+}
+
+// formatStatements returns the statements formatted with the default options
+func formatStatements(statements []*parser.Statement) (string, error) {
+	var buf strings.Builder
+	if err := format.Format(&buf, format.Defaults, statements...); err != nil {
+		return "", errors.Wrap(err, "failed to format statements")
+	}
+
+	return buf.String(), nil
+}
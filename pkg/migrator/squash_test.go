@@ -0,0 +1,150 @@
+package migrator_test
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationDir_Squash(t *testing.T) {
+	// applySquash writes the squash result to a copy of fsys and reloads it
+	applySquash := func(t *testing.T, fsys fstest.MapFS, result *migrator.SquashResult) *migrator.MigrationDir {
+		t.Helper()
+
+		squashed := maps.Clone(fsys)
+		for _, path := range result.Obsolete {
+			require.Contains(t, squashed, path)
+			delete(squashed, path)
+		}
+		squashed[result.Path] = &fstest.MapFile{Data: result.Content}
+
+		migDir, err := migrator.LoadMigrationDir(squashed)
+		require.NoError(t, err)
+
+		// The returned sum file is the one Rehash computes for the squashed directory
+		require.NoError(t, migDir.Rehash())
+		require.Equal(t, sumFileContent(t, migDir.SumFile), sumFileContent(t, result.SumFile))
+		return migDir
+	}
+
+	t.Run("without snapshot", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"001_init.sql":        {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+			"002_users.sql":       {Data: []byte("-- Users\nCREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"002_users.down.sql":  {Data: []byte("DROP TABLE test.users;")},
+			"003_products.sql":    {Data: []byte("CREATE TABLE test.products (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"004_orders.sql":      {Data: []byte("CREATE TABLE test.orders (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"004_orders.down.sql": {Data: []byte("DROP TABLE test.orders;")},
+		}
+
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		result, err := migDir.Squash("003")
+		require.NoError(t, err)
+		require.Equal(t, "003_snapshot", result.Snapshot.Version)
+		require.Equal(t, "003_snapshot.sql", result.Path)
+		require.Equal(t, []string{"001_init", "002_users", "003_products"}, result.Snapshot.IncludedMigrations)
+		require.Equal(t, []string{"001_init.sql", "002_users.down.sql", "002_users.sql", "003_products.sql"}, result.Obsolete)
+		require.Contains(t, string(result.Content), "-- Users\nCREATE TABLE `test`.`users`")
+
+		squashed := applySquash(t, fsys, result)
+		require.Len(t, squashed.Migrations, 2)
+		require.True(t, squashed.Migrations[0].IsSnapshot)
+		require.Equal(t, "004_orders", squashed.Migrations[1].Version)
+		require.Len(t, squashed.Migrations[1].DownStatements, 1)
+		require.Equal(t, []*migrator.Migration{squashed.Migrations[1]}, squashed.GetMigrationsAfterSnapshot())
+	})
+
+	t.Run("with existing snapshot", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"001_init.sql":     {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+			"002_users.sql":    {Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"003_products.sql": {Data: []byte("CREATE TABLE test.products (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"004_orders.sql":   {Data: []byte("CREATE TABLE test.orders (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+		}
+
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		first, err := migDir.Squash("002_users")
+		require.NoError(t, err)
+
+		fsys = maps.Clone(fsys)
+		for _, path := range first.Obsolete {
+			delete(fsys, path)
+		}
+		fsys[first.Path] = &fstest.MapFile{Data: first.Content}
+
+		migDir, err = migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		result, err := migDir.Squash("003")
+		require.NoError(t, err)
+		require.Equal(t, "003_snapshot", result.Snapshot.Version)
+		require.Equal(t, []string{"001_init", "002_users", "003_products"}, result.Snapshot.IncludedMigrations)
+		require.Equal(t, []string{"002_snapshot.sql", "003_products.sql"}, result.Obsolete)
+
+		// The previous snapshot's SQL is carried over once, without its header
+		content := string(result.Content)
+		require.Equal(t, 1, strings.Count(content, "-- Cumulative SQL from all included migrations"))
+		require.Equal(t, 1, strings.Count(content, "CREATE DATABASE `test`"))
+		require.Less(t, strings.Index(content, "`test`.`users`"), strings.Index(content, "`test`.`products`"))
+
+		squashed := applySquash(t, fsys, result)
+		require.Len(t, squashed.Migrations, 2)
+		require.Equal(t, "003_snapshot", squashed.GetSnapshot().Version)
+		require.Equal(t, "004_orders", squashed.Migrations[1].Version)
+	})
+
+	t.Run("snapshot with original files", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"001_init.sql":     {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+			"002_users.sql":    {Data: []byte("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")},
+			"003_snapshot.sql": {Data: []byte(snapshotFile)},
+		}
+
+		migDir, err := migrator.LoadMigrationDir(fsys)
+		require.NoError(t, err)
+
+		_, err = migDir.Squash("002")
+		require.EqualError(t, err, "migrations through 002_users are already squashed into snapshot 003_snapshot")
+
+		// Squashing through the snapshot cleans up the files it already includes
+		result, err := migDir.Squash("003")
+		require.NoError(t, err)
+		require.Equal(t, "003_snapshot.sql", result.Path)
+		require.Equal(t, []string{"001_init", "002_users"}, result.Snapshot.IncludedMigrations)
+		require.Equal(t, []string{"001_init.sql", "002_users.sql"}, result.Obsolete)
+		require.Equal(t, 1, strings.Count(string(result.Content), "CREATE TABLE `test`.`users`"))
+
+		squashed := applySquash(t, fsys, result)
+		require.Len(t, squashed.Migrations, 1)
+
+		_, err = squashed.Squash("003")
+		require.EqualError(t, err, "nothing to squash: 003_snapshot is already a snapshot")
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		migDir, err := migrator.LoadMigrationDir(fstest.MapFS{
+			"001_init.sql": {Data: []byte("CREATE DATABASE test ENGINE = Atomic;")},
+		})
+		require.NoError(t, err)
+
+		_, err = migDir.Squash("002")
+		require.EqualError(t, err, "migration not found: 002")
+	})
+}
+
+func sumFileContent(t *testing.T, sumFile *migrator.SumFile) string {
+	t.Helper()
+
+	var buf strings.Builder
+	_, err := sumFile.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.String()
+}
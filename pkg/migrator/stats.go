@@ -0,0 +1,102 @@
+package migrator
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pseudomuto/housekeeper/pkg/utils"
+)
+
+// TableStats reports the current row count and on-disk size of a table managed by
+// the migrations.
+type TableStats struct {
+	// Database is the table's database, or empty for the connection's current database
+	Database string
+
+	// Table is the name of the table
+	Table string
+
+	// Rows is the number of rows in the table
+	Rows uint64
+
+	// Bytes is the on-disk size of the table
+	Bytes uint64
+}
+
+// Name returns the unquoted, qualified name of the table.
+func (s TableStats) Name() string {
+	return ImpactTarget{Database: s.Database, Table: s.Table}.Name()
+}
+
+// ManagedTables returns the tables that exist after applying the migrations in order,
+// i.e. every table created by a CREATE TABLE statement that hasn't since been dropped.
+// Renamed tables are reported under their new name. Tables are returned in the order
+// they were created, without any stats.
+func ManagedTables(migrations []*Migration) []TableStats {
+	var tables []TableStats
+
+	remove := func(database, table string) {
+		tables = slices.DeleteFunc(tables, func(s TableStats) bool {
+			return s.Database == database && s.Table == table
+		})
+	}
+
+	for _, m := range migrations {
+		for _, stmt := range m.Statements {
+			switch {
+			case stmt.CreateTable != nil:
+				database, table := impactName(stmt.CreateTable.Database, stmt.CreateTable.Name)
+				remove(database, table)
+				tables = append(tables, TableStats{Database: database, Table: table})
+			case stmt.DropTable != nil:
+				remove(impactName(stmt.DropTable.Database, stmt.DropTable.Name))
+			case stmt.DropDatabase != nil:
+				database := utils.StripBackticks(stmt.DropDatabase.Name)
+				tables = slices.DeleteFunc(tables, func(s TableStats) bool {
+					return s.Database == database
+				})
+			case stmt.RenameTable != nil:
+				for _, rename := range stmt.RenameTable.Renames {
+					fromDatabase, fromTable := impactName(rename.FromDatabase, rename.FromName)
+					toDatabase, toTable := impactName(rename.ToDatabase, rename.ToName)
+					for i := range tables {
+						if tables[i].Database == fromDatabase && tables[i].Table == fromTable {
+							tables[i].Database, tables[i].Table = toDatabase, toTable
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return tables
+}
+
+// CollectTableStats queries the server for the current row count and on-disk size of
+// every table returned by ManagedTables. Like AnalyzeImpact, sizes are read from the
+// active parts in system.parts, so tables that don't exist on the server report zero.
+//
+// Example:
+//
+//	stats, err := migrator.CollectTableStats(ctx, client, migrationDir.Migrations)
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, s := range stats {
+//		fmt.Printf("%s: %d rows\n", s.Name(), s.Rows)
+//	}
+func CollectTableStats(ctx context.Context, ch ClickHouse, migrations []*Migration) ([]TableStats, error) {
+	tables := ManagedTables(migrations)
+
+	for i := range tables {
+		target := ImpactTarget{Database: tables[i].Database, Table: tables[i].Table}
+
+		var err error
+		if tables[i].Rows, tables[i].Bytes, err = querySize(ctx, ch, target); err != nil {
+			return nil, err
+		}
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,89 @@
+package migrator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/migrator"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedTables(t *testing.T) {
+	first, err := migrator.LoadMigration("001_init", strings.NewReader(`
+		CREATE DATABASE db ENGINE = Atomic;
+		CREATE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE db.staging (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE logs (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE archive.old (id UInt64) ENGINE = MergeTree() ORDER BY id;`))
+	require.NoError(t, err)
+
+	second, err := migrator.LoadMigration("002_cleanup", strings.NewReader(`
+		DROP TABLE db.staging;
+		RENAME TABLE logs TO db.logs;
+		DROP DATABASE archive;
+		CREATE TABLE IF NOT EXISTS db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;`))
+	require.NoError(t, err)
+
+	tables := migrator.ManagedTables([]*migrator.Migration{first, second})
+	require.Equal(t, []migrator.TableStats{
+		{Database: "db", Table: "logs"},
+		{Database: "db", Table: "events"},
+	}, tables)
+	require.Equal(t, "db.logs", tables[0].Name())
+}
+
+func TestCollectTableStats(t *testing.T) {
+	migration, err := migrator.LoadMigration("001_init", strings.NewReader(`
+		CREATE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE logs (id UInt64) ENGINE = MergeTree() ORDER BY id;`))
+	require.NoError(t, err)
+
+	t.Run("reports sizes", func(t *testing.T) {
+		sizes := map[string][]any{
+			"db.events": {int64(1000), int64(4096)},
+		}
+
+		var queries []string
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				queries = append(queries, query)
+
+				key := make([]string, 0, len(args))
+				for _, arg := range args {
+					key = append(key, arg.(string))
+				}
+
+				if size, ok := sizes[strings.Join(key, ".")]; ok {
+					return &mockRows{data: [][]any{size}}, nil
+				}
+				return &mockRows{}, nil
+			},
+		}
+
+		stats, err := migrator.CollectTableStats(context.Background(), mockCH, []*migrator.Migration{migration})
+		require.NoError(t, err)
+		require.Equal(t, []migrator.TableStats{
+			{Database: "db", Table: "events", Rows: 1000, Bytes: 4096},
+			{Table: "logs"},
+		}, stats)
+
+		require.Equal(t, []string{
+			"SELECT sum(rows), sum(bytes_on_disk) FROM system.parts WHERE active AND database = ? AND table = ?",
+			"SELECT sum(rows), sum(bytes_on_disk) FROM system.parts WHERE active AND database = currentDatabase() AND table = ?",
+		}, queries)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockCH := &mockClickHouse{
+			queryFunc: func(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+
+		_, err := migrator.CollectTableStats(context.Background(), mockCH, []*migrator.Migration{migration})
+		require.ErrorContains(t, err, "failed to query size of db.events")
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 
@@ -129,6 +131,8 @@ func NewSumFile() *SumFile {
 //	fmt.Printf("Loaded %d migration entries\n", len(sumFile.entries))
 //
 // Returns an error if the reader contains invalid format or corrupted hash data.
+// Malformed entries and a total hash that doesn't match the entries (as happens
+// when the file was truncated) are reported as ErrCorruptSumFile.
 func LoadSumFile(r io.Reader) (*SumFile, error) {
 	f := NewSumFile()
 
@@ -144,6 +148,10 @@ func LoadSumFile(r io.Reader) (*SumFile, error) {
 
 	for scanner.Scan() {
 		parts := strings.SplitN(scanner.Text(), " ", 2)
+		if len(parts) != 2 {
+			return nil, errors.Wrapf(ErrCorruptSumFile, "malformed entry: %q", scanner.Text())
+		}
+
 		sum, err := readHash(parts[1])
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to parse hash for: %s", parts[0])
@@ -159,6 +167,12 @@ func LoadSumFile(r io.Reader) (*SumFile, error) {
 		return nil, errors.Wrap(err, "error reading sum file")
 	}
 
+	// The total hash covers every entry, so a file that lost entries (e.g. from
+	// an interrupted write) no longer matches its first line.
+	if !equalHashes(f.totalHash(), f.sum) {
+		return nil, errors.Wrapf(ErrCorruptSumFile, "total hash doesn't match its %d entries (was it truncated?)", len(f.entries))
+	}
+
 	return f, nil
 }
 
@@ -289,18 +303,8 @@ func (f *SumFile) WriteTo(w io.Writer) (int64, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	// Compute the total hash from all entries if we have any
-	var totalHash []byte
-	if len(f.entries) > 0 {
-		h := sha256.New()
-		for _, entry := range f.entries {
-			h.Write(entry.hash)
-		}
-		totalHash = h.Sum(nil)
-	}
-
 	bytesWritten := int64(0)
-	n, err := fmt.Fprintln(w, writeHash(totalHash))
+	n, err := fmt.Fprintln(w, writeHash(f.totalHash()))
 	if err != nil {
 		return bytesWritten, err
 	}
@@ -408,6 +412,80 @@ func (f *SumFile) Validate(files map[string]io.Reader) (bool, error) {
 	return true, nil
 }
 
+// totalHash returns the SHA256 of all entry hashes in order, or nil when there
+// are no entries.
+func (f *SumFile) totalHash() []byte {
+	if len(f.entries) == 0 {
+		return nil
+	}
+
+	h := sha256.New()
+	for _, entry := range f.entries {
+		h.Write(entry.hash)
+	}
+	return h.Sum(nil)
+}
+
+// diff returns the sum file lines that differ between f and other, prefixing lines
+// only in f with "-" and lines only in other with "+". Entries are listed by version
+// after the total hash line, and an empty string means the files are identical.
+func (f *SumFile) diff(other *SumFile) string {
+	current, currentTotal := f.lines()
+	next, nextTotal := other.lines()
+
+	var b strings.Builder
+	if currentTotal != nextTotal {
+		if currentTotal != "" {
+			fmt.Fprintf(&b, "-%s\n", currentTotal)
+		}
+		if nextTotal != "" {
+			fmt.Fprintf(&b, "+%s\n", nextTotal)
+		}
+	}
+
+	versions := slices.Sorted(maps.Keys(current))
+	for version := range maps.Keys(next) {
+		if _, ok := current[version]; !ok {
+			versions = append(versions, version)
+		}
+	}
+	slices.Sort(versions)
+
+	for _, version := range versions {
+		currentHash, inCurrent := current[version]
+		nextHash, inNext := next[version]
+		if currentHash == nextHash {
+			continue
+		}
+
+		if inCurrent {
+			fmt.Fprintf(&b, "-%s %s\n", version, currentHash)
+		}
+		if inNext {
+			fmt.Fprintf(&b, "+%s %s\n", version, nextHash)
+		}
+	}
+
+	return b.String()
+}
+
+// lines returns the encoded hash of each entry keyed by version, along with the
+// encoded total hash line, which is empty when there are no entries.
+func (f *SumFile) lines() (map[string]string, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries := make(map[string]string, len(f.entries))
+	for _, entry := range f.entries {
+		entries[entry.version] = writeHash(entry.hash)
+	}
+
+	if len(f.entries) == 0 {
+		return entries, ""
+	}
+	return entries, writeHash(f.totalHash())
+}
+
 // equalHashes compares two byte slices for equality in constant time.
 // This prevents timing attacks on hash comparisons.
 func equalHashes(a, b []byte) bool {
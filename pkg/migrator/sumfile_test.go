@@ -130,7 +130,7 @@ func TestSumFile_ChainedHashing(t *testing.T) {
 
 func TestLoadSumFile(t *testing.T) {
 	// Create a valid sum file content
-	sumContent := `h1:dGVzdF90b3RhbF9oYXNo
+	sumContent := `h1:6Gc2euIXreerGs8lr7sEzz862Iy6UCLjxjoyjbISQZQ=
 20240101120000.sql h1:aGFzaDE=
 20240101120100.sql h1:aGFzaDI=
 20240101120200.sql h1:aGFzaDM=`
@@ -260,6 +260,42 @@ func TestSumFile_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestLoadSumFile_Truncated(t *testing.T) {
+	sumFile := migrator.NewSumFile()
+	require.NoError(t, sumFile.Add("20240101120000.sql", strings.NewReader("CREATE DATABASE test ENGINE = Atomic;")))
+	require.NoError(t, sumFile.Add("20240101120100.sql", strings.NewReader("CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;")))
+	require.NoError(t, sumFile.Add("20240101120200.sql", strings.NewReader("DROP TABLE test.users;")))
+
+	var buf bytes.Buffer
+	_, err := sumFile.WriteTo(&buf)
+	require.NoError(t, err)
+	content := buf.String()
+
+	_, err = migrator.LoadSumFile(strings.NewReader(content))
+	require.NoError(t, err)
+
+	lines := strings.SplitAfter(content, "\n")
+	tests := map[string]string{
+		"missing last entry":   strings.Join(lines[:3], ""),
+		"only total hash line": lines[0],
+		"partial entry line":   strings.Join(lines[:3], "") + "20240101",
+		"partial entry hash":   strings.Join(lines[:3], "") + lines[3][:30],
+	}
+
+	for name, truncated := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := migrator.LoadSumFile(strings.NewReader(truncated))
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("reports corruption", func(t *testing.T) {
+		_, err := migrator.LoadSumFile(strings.NewReader(tests["missing last entry"]))
+		require.ErrorIs(t, err, migrator.ErrCorruptSumFile)
+		require.ErrorContains(t, err, "was it truncated?")
+	})
+}
+
 func TestSumFile_EmptyReaders(t *testing.T) {
 	sumFile := migrator.NewSumFile()
 
@@ -3,6 +3,7 @@ package parser
 import (
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/pseudomuto/housekeeper/pkg/compare"
 )
 
@@ -21,6 +22,30 @@ type (
 	}
 )
 
+// ParseCodec parses a compression codec specification such as "ZSTD(1)" or
+// "Delta, LZ4". The surrounding CODEC(...) is optional.
+//
+// Example:
+//
+//	codec, err := parser.ParseCodec("ZSTD(1)")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(codec.String()) // CODEC(ZSTD(1))
+func ParseCodec(codec string) (*CodecClause, error) {
+	codec = strings.TrimSpace(codec)
+	if !strings.HasPrefix(strings.ToUpper(codec), "CODEC(") {
+		codec = "CODEC(" + codec + ")"
+	}
+
+	clause, err := codecParser.ParseString("", codec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid codec %q", codec)
+	}
+
+	return clause, nil
+}
+
 // Equal compares two CodecClause instances for equality
 func (c *CodecClause) Equal(other *CodecClause) bool {
 	if eq, done := compare.NilCheck(c, other); !done {
@@ -210,3 +210,30 @@ func TestCodecClause(t *testing.T) {
 		}
 	})
 }
+
+func TestParseCodec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "LZ4", expected: "CODEC(LZ4)"},
+		{input: " ZSTD(1) ", expected: "CODEC(ZSTD(1))"},
+		{input: "Delta, ZSTD(3)", expected: "CODEC(Delta, ZSTD(3))"},
+		{input: "codec(LZ4HC(9))", expected: "CODEC(LZ4HC(9))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			codec, err := ParseCodec(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, codec.String())
+		})
+	}
+
+	_, err := ParseCodec("ZSTD(")
+	require.ErrorContains(t, err, "invalid codec")
+}
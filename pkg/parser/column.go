@@ -1,6 +1,10 @@
 package parser
 
-import "github.com/pseudomuto/housekeeper/pkg/compare"
+import (
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/compare"
+)
 
 type (
 	// Column represents a complete column definition in ClickHouse DDL.
@@ -10,7 +14,7 @@ type (
 	Column struct {
 		LeadingComments  []string          `parser:"@(Comment | MultilineComment)*"`
 		Name             string            `parser:"@(Ident | BacktickIdent)"`
-		DataType         *DataType         `parser:"@@"`
+		DataType         *DataType         `parser:"((?! 'DEFAULT' | 'MATERIALIZED' | 'EPHEMERAL' | 'ALIAS') @@)?"`
 		Attributes       []ColumnAttribute `parser:"@@*"`
 		TrailingComments []string          `parser:"@(Comment | MultilineComment)*"`
 	}
@@ -18,16 +22,26 @@ type (
 	// ColumnAttribute represents any attribute that can appear after the data type
 	// This allows attributes to be specified in any order
 	ColumnAttribute struct {
-		Default *DefaultClause `parser:"@@"`
-		Codec   *CodecClause   `parser:"| @@"`
-		TTL     *TTLClause     `parser:"| @@"`
-		Comment *string        `parser:"| ('COMMENT' @String)"`
+		Default  *DefaultClause        `parser:"@@"`
+		Codec    *CodecClause          `parser:"| @@"`
+		TTL      *TTLClause            `parser:"| @@"`
+		Comment  *string               `parser:"| ('COMMENT' @String)"`
+		Settings *ColumnSettingsClause `parser:"| @@"`
+	}
+
+	// ColumnSettingsClause represents per-column storage settings
+	// ClickHouse syntax: SETTINGS (max_compress_block_size = 1048576, ...)
+	ColumnSettingsClause struct {
+		Settings []TableSetting `parser:"'SETTINGS' '(' @@ (',' @@)* ')'"`
 	}
 
-	// DefaultClause represents DEFAULT, MATERIALIZED, EPHEMERAL, or ALIAS expressions
+	// DefaultClause represents DEFAULT, MATERIALIZED, EPHEMERAL, or ALIAS expressions.
+	// The expression may be omitted for EPHEMERAL columns (e.g. `unhexed String EPHEMERAL`),
+	// which then default to the default value of their type. AFTER and FIRST end the clause
+	// so that a bare EPHEMERAL column can be positioned by ADD COLUMN.
 	DefaultClause struct {
-		Type       string     `parser:"@('DEFAULT' | 'MATERIALIZED' | 'EPHEMERAL' | 'ALIAS')"`
-		Expression Expression `parser:"@@"`
+		Type       string      `parser:"@('DEFAULT' | 'MATERIALIZED' | 'EPHEMERAL' | 'ALIAS')"`
+		Expression *Expression `parser:"((?! 'CODEC' | 'TTL' | 'COMMENT' | 'SETTINGS' | 'AFTER' | 'FIRST') @@)?"`
 	}
 
 	// TTLClause represents column-level TTL specification
@@ -45,12 +59,38 @@ func (t *TTLClause) Equal(other *TTLClause) bool {
 	return t.Expression.Equal(&other.Expression)
 }
 
+// Equal compares two ColumnSettingsClause instances for equality.
+// Settings are compared by name and value, regardless of their order.
+func (c *ColumnSettingsClause) Equal(other *ColumnSettingsClause) bool {
+	if eq, done := compare.NilCheck(c, other); !done {
+		return eq
+	}
+
+	return compare.SlicesUnordered(c.Settings, other.Settings, func(a, b TableSetting) bool {
+		return a.Name == b.Name && a.GetValue() == b.GetValue()
+	})
+}
+
+// String returns the SQL representation of a column SETTINGS clause.
+func (c *ColumnSettingsClause) String() string {
+	if c == nil || len(c.Settings) == 0 {
+		return ""
+	}
+
+	settings := make([]string, 0, len(c.Settings))
+	for _, setting := range c.Settings {
+		settings = append(settings, setting.Name+" = "+setting.GetValue())
+	}
+
+	return "SETTINGS (" + strings.Join(settings, ", ") + ")"
+}
+
 // Equal compares two DefaultClause instances for equality
 func (d *DefaultClause) Equal(other *DefaultClause) bool {
 	if eq, done := compare.NilCheck(d, other); !done {
 		return eq
 	}
-	return d.Type == other.Type && d.Expression.Equal(&other.Expression)
+	return d.Type == other.Type && d.Expression.Equal(other.Expression)
 }
 
 // GetDefault returns the default clause for the column, if present
@@ -83,6 +123,16 @@ func (c *Column) GetTTL() *TTLClause {
 	return nil
 }
 
+// GetSettings returns the per-column SETTINGS clause, if present
+func (c *Column) GetSettings() *ColumnSettingsClause {
+	for _, attr := range c.Attributes {
+		if attr.Settings != nil {
+			return attr.Settings
+		}
+	}
+	return nil
+}
+
 // GetComment returns the comment for the column, if present
 func (c *Column) GetComment() *string {
 	for _, attr := range c.Attributes {
@@ -4,16 +4,17 @@ import "strings"
 
 type (
 	// CreateDatabaseStmt represents CREATE DATABASE statements
-	// Syntax: CREATE DATABASE [IF NOT EXISTS] db_name [ON CLUSTER cluster] [ENGINE = engine(...)] [COMMENT 'Comment'];
+	// Syntax: CREATE DATABASE [IF NOT EXISTS] db_name [ON CLUSTER cluster] [ENGINE = engine(...)] [SETTINGS name = value, ...] [COMMENT 'Comment'];
 	CreateDatabaseStmt struct {
 		LeadingCommentField
-		Create      string          `parser:"'CREATE'"`
-		Database    string          `parser:"'DATABASE'"`
-		IfNotExists bool            `parser:"@('IF' 'NOT' 'EXISTS')?"`
-		Name        string          `parser:"@(Ident | BacktickIdent)"`
-		OnCluster   *string         `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
-		Engine      *DatabaseEngine `parser:"@@?"`
-		Comment     *string         `parser:"('COMMENT' @String)?"`
+		Create      string               `parser:"'CREATE'"`
+		Database    string               `parser:"'DATABASE'"`
+		IfNotExists bool                 `parser:"@('IF' 'NOT' 'EXISTS')?"`
+		Name        string               `parser:"@(Ident | BacktickIdent)"`
+		OnCluster   *string              `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Engine      *DatabaseEngine      `parser:"@@?"`
+		Settings    *TableSettingsClause `parser:"@@?"`
+		Comment     *string              `parser:"('COMMENT' @String)?"`
 		TrailingCommentField
 		Semicolon bool `parser:"';'"`
 	}
@@ -31,6 +32,7 @@ type (
 
 	// AlterDatabaseStmt represents ALTER DATABASE statements
 	// Syntax: ALTER DATABASE [db].name [ON CLUSTER cluster] MODIFY COMMENT 'Comment';
+	//         ALTER DATABASE [db].name [ON CLUSTER cluster] MODIFY SETTING name = value [, ...];
 	AlterDatabaseStmt struct {
 		LeadingCommentField
 		Alter     string               `parser:"'ALTER'"`
@@ -44,7 +46,8 @@ type (
 
 	// AlterDatabaseAction represents the action to perform on the database
 	AlterDatabaseAction struct {
-		ModifyComment *string `parser:"'MODIFY' 'COMMENT' @String"`
+		ModifyComment *string        `parser:"'MODIFY' ( 'COMMENT' @String"`
+		ModifySetting []TableSetting `parser:"| 'SETTING' @@ (',' @@)* )"`
 	}
 
 	// AttachDatabaseStmt represents ATTACH DATABASE statements
@@ -16,6 +16,7 @@ func TestCreateDatabase(t *testing.T) {
 		{name: "full_options", sql: "CREATE DATABASE IF NOT EXISTS full_db ON CLUSTER production ENGINE = Atomic COMMENT 'Full featured database';"},
 		{name: "with_backticks", sql: "CREATE DATABASE `user-database` ENGINE = Atomic;"},
 		{name: "backticks_full", sql: "CREATE DATABASE IF NOT EXISTS `order-db` ON CLUSTER `prod-cluster` COMMENT 'Database with special chars';"},
+		{name: "with_settings", sql: "CREATE DATABASE replicated_db ON CLUSTER production ENGINE = Replicated('/clickhouse/databases/replicated_db', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5, collection_name = 'replicated' COMMENT 'Replicated database';"},
 	}
 
 	runStatementTests(t, "database/create", tests)
@@ -27,6 +28,7 @@ func TestAlterDatabase(t *testing.T) {
 	tests := []statementTest{
 		{name: "basic", sql: "ALTER DATABASE basic_alter_db MODIFY COMMENT 'Updated comment';"},
 		{name: "on_cluster", sql: "ALTER DATABASE cluster_alter_db ON CLUSTER production MODIFY COMMENT 'Production database';"},
+		{name: "modify_setting", sql: "ALTER DATABASE replicated_db ON CLUSTER production MODIFY SETTING max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;"},
 	}
 
 	runStatementTests(t, "database/alter", tests)
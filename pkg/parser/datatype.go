@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/pseudomuto/housekeeper/pkg/compare"
@@ -117,20 +118,33 @@ type (
 
 // Interface implementations for DataTypeComparable
 
-// Equal compares two SimpleType instances with special handling for ClickHouse normalization patterns
+// Equal compares two SimpleType instances with special handling for ClickHouse normalization patterns.
+//
+// Type names are resolved to their canonical form before comparison (see simpleTypeAliases):
+//   - Bool/Boolean and UInt8 are equal. ClickHouse stores Bool as UInt8 and may report either
+//     in system.columns, so changing a column between the two does not produce a diff. This is
+//     intentional since the on-disk storage is identical.
+//   - INET4/INET6 are aliases for IPv4/IPv6.
+//   - Date, Date32, DateTime, DateTime64, UUID, IPv4 and IPv6 are case-insensitive, and
+//     ClickHouse always reports them in their canonical casing.
+//
+// Types that merely share a representation are NOT normalized, since converting between them
+// rewrites the column: Date vs Date32 (different ranges), IPv4 vs UInt32 and IPv6 vs
+// FixedString(16) (as found in dumps from older versions), and UUID vs FixedString(16).
 func (s *SimpleType) Equal(other DataTypeComparable) bool {
 	otherSimple, ok := other.(*SimpleType)
 	if !ok {
 		return false
 	}
 
-	if s.Name != otherSimple.Name {
+	name := canonicalTypeName(s.Name)
+	if name != canonicalTypeName(otherSimple.Name) {
 		return false
 	}
 
 	// Special handling for DateTime64 timezone normalization
 	// ClickHouse may normalize DateTime64(precision, timezone) to DateTime64(precision) in system.tables
-	if s.Name == "DateTime64" && otherSimple.Name == "DateTime64" {
+	if name == "DateTime64" {
 		return s.isDateTime64CompatibleWith(otherSimple)
 	}
 
@@ -146,6 +160,35 @@ func (s *SimpleType) Equal(other DataTypeComparable) bool {
 	return true
 }
 
+// simpleTypeAliases maps upper-cased ClickHouse type names to the name used for comparison
+var simpleTypeAliases = map[string]string{
+	// Bool is stored as UInt8
+	"BOOL":    "UInt8",
+	"BOOLEAN": "UInt8",
+
+	// MySQL compatible aliases for IP address types
+	"INET4": "IPv4",
+	"INET6": "IPv6",
+
+	// Case-insensitive type names, reported by ClickHouse in their canonical casing
+	"DATE":       "Date",
+	"DATE32":     "Date32",
+	"DATETIME":   "DateTime",
+	"DATETIME64": "DateTime64",
+	"UUID":       "UUID",
+	"IPV4":       "IPv4",
+	"IPV6":       "IPv6",
+}
+
+// canonicalTypeName resolves ClickHouse type aliases (which are case-insensitive) to the
+// name used for comparison. Names without a known alias are returned unchanged.
+func canonicalTypeName(name string) string {
+	if canonical, ok := simpleTypeAliases[strings.ToUpper(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
 // TypeName returns the type name for SimpleType
 func (s *SimpleType) TypeName() string {
 	return "SimpleType"
@@ -616,3 +659,161 @@ func (p *ParametricFunction) String() string {
 
 	return p.Name + "(" + strings.Join(params, ", ") + ")"
 }
+
+// inferredFunctionTypes maps functions commonly used in column DEFAULT expressions
+// to the type ClickHouse infers for them when the column type is omitted.
+var inferredFunctionTypes = map[string]string{
+	"now":            "DateTime",
+	"today":          "Date",
+	"yesterday":      "Date",
+	"generateUUIDv4": "UUID",
+	"generateUUIDv7": "UUID",
+	"rand":           "UInt32",
+	"rand32":         "UInt32",
+	"rand64":         "UInt64",
+	"toString":       "String",
+	"toDate":         "Date",
+	"toDate32":       "Date32",
+	"toDateTime":     "DateTime",
+	"toUUID":         "UUID",
+	"toIPv4":         "IPv4",
+	"toIPv6":         "IPv6",
+	"toInt8":         "Int8",
+	"toInt16":        "Int16",
+	"toInt32":        "Int32",
+	"toInt64":        "Int64",
+	"toUInt8":        "UInt8",
+	"toUInt16":       "UInt16",
+	"toUInt32":       "UInt32",
+	"toUInt64":       "UInt64",
+	"toFloat32":      "Float32",
+	"toFloat64":      "Float64",
+	"toBool":         "Bool",
+}
+
+// InferDataType returns the data type ClickHouse infers for a column declared
+// without an explicit type, e.g. `created_at DEFAULT now()` becomes DateTime.
+// Only simple expressions (literals, casts, and well-known functions) are
+// supported; nil is returned when the type cannot be determined.
+func InferDataType(expr *Expression) *DataType {
+	primary, negative := singlePrimary(expr)
+	if primary == nil {
+		return nil
+	}
+
+	switch {
+	case primary.Parentheses != nil:
+		return InferDataType(&primary.Parentheses.Expression)
+	case primary.Cast != nil:
+		dt := primary.Cast.Type
+		return &dt
+	case primary.Literal != nil:
+		return inferLiteralType(primary.Literal, negative)
+	case primary.Function != nil:
+		return inferFunctionType(primary.Function)
+	}
+
+	return nil
+}
+
+// singlePrimary unwraps an expression consisting of a single primary term
+// (optionally negated) and reports whether a unary minus was applied.
+func singlePrimary(expr *Expression) (*PrimaryExpression, bool) {
+	if expr == nil || expr.Or == nil || len(expr.Or.Rest) > 0 {
+		return nil, false
+	}
+
+	and := expr.Or.And
+	if and == nil || len(and.Rest) > 0 || and.Not == nil || and.Not.Not {
+		return nil, false
+	}
+
+	cmp := and.Not.Comparison
+	if cmp == nil || cmp.Rest != nil || cmp.IsNull != nil {
+		return nil, false
+	}
+
+	add := cmp.Addition
+	if add == nil || len(add.Rest) > 0 || add.Multiplication == nil || len(add.Multiplication.Rest) > 0 {
+		return nil, false
+	}
+
+	unary := add.Multiplication.Unary
+	if unary == nil {
+		return nil, false
+	}
+
+	return unary.Primary, unary.Op == "-"
+}
+
+// inferLiteralType infers the smallest type ClickHouse would pick for a literal.
+func inferLiteralType(lit *Literal, negative bool) *DataType {
+	simple := func(name string) *DataType {
+		return &DataType{Simple: &SimpleType{Name: name}}
+	}
+
+	switch {
+	case lit.StringValue != nil:
+		return simple("String")
+	case lit.Boolean != nil:
+		return simple("Bool")
+	case lit.Number != nil:
+		if strings.Contains(*lit.Number, ".") {
+			return simple("Float64")
+		}
+		return simple(integerLiteralType(*lit.Number, negative))
+	}
+
+	return nil
+}
+
+// integerLiteralType returns the narrowest integer type able to hold the literal.
+func integerLiteralType(number string, negative bool) string {
+	value, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return "UInt64"
+	}
+
+	if negative {
+		switch {
+		case value <= 1<<7:
+			return "Int8"
+		case value <= 1<<15:
+			return "Int16"
+		case value <= 1<<31:
+			return "Int32"
+		default:
+			return "Int64"
+		}
+	}
+
+	switch {
+	case value <= 1<<8-1:
+		return "UInt8"
+	case value <= 1<<16-1:
+		return "UInt16"
+	case value <= 1<<32-1:
+		return "UInt32"
+	default:
+		return "UInt64"
+	}
+}
+
+// inferFunctionType infers the return type of well-known functions.
+func inferFunctionType(fn *FunctionCall) *DataType {
+	// now64([precision[, timezone]]) defaults to millisecond precision
+	if fn.Name == "now64" {
+		precision := "3"
+		if len(fn.FirstParentheses) > 0 && fn.FirstParentheses[0].Expression != nil {
+			precision = fn.FirstParentheses[0].Expression.String()
+		}
+		return &DataType{Simple: &SimpleType{Name: "DateTime64", Parameters: []TypeParameter{{Number: &precision}}}}
+	}
+
+	name, ok := inferredFunctionTypes[fn.Name]
+	if !ok {
+		return nil
+	}
+
+	return &DataType{Simple: &SimpleType{Name: name}}
+}
@@ -170,6 +170,35 @@ func TestSimpleType(t *testing.T) {
 		}
 	})
 
+	t.Run("AliasCompatibility", func(t *testing.T) {
+		t.Parallel()
+
+		// Bool is stored as UInt8 and INET4/INET6 are aliases for IPv4/IPv6
+		tests := []struct {
+			name     string
+			a, b     SimpleType
+			expected bool
+		}{
+			{name: "Bool vs UInt8", a: SimpleType{Name: "Bool"}, b: SimpleType{Name: "UInt8"}, expected: true},
+			{name: "UInt8 vs Bool", a: SimpleType{Name: "UInt8"}, b: SimpleType{Name: "Bool"}, expected: true},
+			{name: "Boolean vs Bool", a: SimpleType{Name: "BOOLEAN"}, b: SimpleType{Name: "Bool"}, expected: true},
+			{name: "Bool vs UInt16", a: SimpleType{Name: "Bool"}, b: SimpleType{Name: "UInt16"}, expected: false},
+			{name: "Bool vs Int8", a: SimpleType{Name: "Bool"}, b: SimpleType{Name: "Int8"}, expected: false},
+			{name: "INET4 vs IPv4", a: SimpleType{Name: "INET4"}, b: SimpleType{Name: "IPv4"}, expected: true},
+			{name: "INET6 vs IPv6", a: SimpleType{Name: "inet6"}, b: SimpleType{Name: "IPv6"}, expected: true},
+			{name: "IPv4 vs IPv6", a: SimpleType{Name: "IPv4"}, b: SimpleType{Name: "IPv6"}, expected: false},
+			{name: "IPv4 vs UInt32", a: SimpleType{Name: "IPv4"}, b: SimpleType{Name: "UInt32"}, expected: false},
+			{name: "IPv6 vs FixedString", a: SimpleType{Name: "IPv6"}, b: SimpleType{Name: "FixedString", Parameters: []TypeParameter{{Number: utils.Ptr("16")}}}, expected: false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				require.Equal(t, tt.expected, tt.a.Equal(&tt.b))
+			})
+		}
+	})
+
 	t.Run("DateTime64Compatibility", func(t *testing.T) {
 		t.Parallel()
 
@@ -208,6 +237,92 @@ func TestSimpleType(t *testing.T) {
 	})
 }
 
+// TestTypeNormalizationMatrix compares declared column types against the types ClickHouse
+// reports when dumping a schema, guarding against both spurious diffs and missed changes.
+func TestTypeNormalizationMatrix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		declared string
+		dumped   string
+		equal    bool
+	}{
+		// Bool is stored as UInt8
+		{declared: "Bool", dumped: "UInt8", equal: true},
+		{declared: "bool", dumped: "Bool", equal: true},
+		{declared: "Bool", dumped: "Int8", equal: false},
+
+		// Date and Date32 have different ranges and storage
+		{declared: "Date", dumped: "Date", equal: true},
+		{declared: "date", dumped: "Date", equal: true},
+		{declared: "Date32", dumped: "Date32", equal: true},
+		{declared: "date32", dumped: "Date32", equal: true},
+		{declared: "Date", dumped: "Date32", equal: false},
+		{declared: "Date32", dumped: "Date", equal: false},
+		{declared: "Nullable(Date32)", dumped: "Nullable(Date)", equal: false},
+		{declared: "DateTime", dumped: "Date", equal: false},
+		{declared: "datetime", dumped: "DateTime", equal: true},
+		{declared: "datetime64(3)", dumped: "DateTime64(3)", equal: true},
+
+		// IPv4/IPv6 and their legacy representations
+		{declared: "IPv4", dumped: "IPv4", equal: true},
+		{declared: "INET4", dumped: "IPv4", equal: true},
+		{declared: "ipv4", dumped: "IPv4", equal: true},
+		{declared: "IPv4", dumped: "UInt32", equal: false},
+		{declared: "IPv6", dumped: "IPv6", equal: true},
+		{declared: "INET6", dumped: "IPv6", equal: true},
+		{declared: "ipv6", dumped: "IPv6", equal: true},
+		{declared: "IPv6", dumped: "FixedString(16)", equal: false},
+		{declared: "IPv4", dumped: "IPv6", equal: false},
+		{declared: "LowCardinality(IPv4)", dumped: "LowCardinality(UInt32)", equal: false},
+
+		// UUID
+		{declared: "UUID", dumped: "UUID", equal: true},
+		{declared: "uuid", dumped: "UUID", equal: true},
+		{declared: "UUID", dumped: "FixedString(16)", equal: false},
+		{declared: "UUID", dumped: "String", equal: false},
+		{declared: "Array(UUID)", dumped: "Array(UUID)", equal: true},
+
+		// FixedString compares by length and is never interchangeable with String
+		{declared: "String", dumped: "String", equal: true},
+		{declared: "FixedString(36)", dumped: "FixedString(36)", equal: true},
+		{declared: "FixedString(1)", dumped: "FixedString(1)", equal: true},
+		{declared: "FixedString(36)", dumped: "FixedString(37)", equal: false},
+		{declared: "FixedString(16)", dumped: "FixedString(32)", equal: false},
+		{declared: "FixedString(36)", dumped: "String", equal: false},
+		{declared: "FixedString(1)", dumped: "String", equal: false},
+		{declared: "String", dumped: "FixedString(255)", equal: false},
+		{declared: "FixedString(36)", dumped: "FixedString", equal: false},
+		{declared: "Nullable(FixedString(36))", dumped: "Nullable(FixedString(37))", equal: false},
+		{declared: "LowCardinality(FixedString(36))", dumped: "LowCardinality(String)", equal: false},
+		{declared: "Array(FixedString(36))", dumped: "Array(FixedString(36))", equal: true},
+
+		// Case-sensitive names are not normalized
+		{declared: "string", dumped: "String", equal: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared+" vs "+tt.dumped, func(t *testing.T) {
+			t.Parallel()
+
+			declared := parseColumnType(t, tt.declared)
+			dumped := parseColumnType(t, tt.dumped)
+
+			require.Equal(t, tt.equal, declared.Equal(dumped))
+			require.Equal(t, tt.equal, dumped.Equal(declared))
+		})
+	}
+}
+
+func parseColumnType(t *testing.T, dataType string) *DataType {
+	t.Helper()
+
+	sql, err := ParseString("CREATE TABLE t (c " + dataType + ") ENGINE = Memory;")
+	require.NoError(t, err)
+
+	return sql.Statements[0].CreateTable.Elements[0].Column.DataType
+}
+
 func TestWrapperTypes(t *testing.T) {
 	t.Parallel()
 
@@ -488,3 +603,51 @@ func TestNormalizeDataType(t *testing.T) {
 		})
 	}
 }
+
+func TestInferDataType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{name: "now", expr: "now()", expected: "DateTime"},
+		{name: "today", expr: "today()", expected: "Date"},
+		{name: "now64 default precision", expr: "now64()", expected: "DateTime64(3)"},
+		{name: "now64 explicit precision", expr: "now64(6)", expected: "DateTime64(6)"},
+		{name: "uuid", expr: "generateUUIDv4()", expected: "UUID"},
+		{name: "conversion function", expr: "toUInt32(42)", expected: "UInt32"},
+		{name: "string literal", expr: "'pending'", expected: "String"},
+		{name: "small integer", expr: "1", expected: "UInt8"},
+		{name: "large integer", expr: "70000", expected: "UInt32"},
+		{name: "negative integer", expr: "-1", expected: "Int8"},
+		{name: "float", expr: "1.5", expected: "Float64"},
+		{name: "boolean", expr: "true", expected: "Bool"},
+		{name: "cast", expr: "CAST(0 AS Int16)", expected: "Int16"},
+		{name: "parentheses", expr: "(today())", expected: "Date"},
+		{name: "unknown function", expr: "someUDF()", expected: ""},
+		{name: "arithmetic", expr: "id + 1", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sql, err := ParseString("CREATE TABLE t (id UInt64, c DEFAULT " + tt.expr + ") ENGINE = Memory;")
+			require.NoError(t, err)
+
+			col := sql.Statements[0].CreateTable.Elements[1].Column
+			require.Nil(t, col.DataType)
+
+			inferred := InferDataType(col.GetDefault().Expression)
+			if tt.expected == "" {
+				require.Nil(t, inferred)
+				return
+			}
+
+			require.NotNil(t, inferred)
+			require.Equal(t, tt.expected, inferred.String())
+		})
+	}
+}
@@ -9,8 +9,8 @@ type (
 	// ClickHouse syntax:
 	//   CREATE [OR REPLACE] DICTIONARY [IF NOT EXISTS] [db.]dict_name [ON CLUSTER cluster]
 	//   (
-	//     column1 Type1 [DEFAULT|EXPRESSION expr] [IS_OBJECT_ID|HIERARCHICAL|INJECTIVE],
-	//     column2 Type2 [DEFAULT|EXPRESSION expr] [IS_OBJECT_ID|HIERARCHICAL|INJECTIVE],
+	//     column1 Type1 [DEFAULT|EXPRESSION expr] [HIERARCHICAL] [BIDIRECTIONAL] [INJECTIVE] [IS_OBJECT_ID],
+	//     column2 Type2 [DEFAULT|EXPRESSION expr] [HIERARCHICAL] [BIDIRECTIONAL] [INJECTIVE] [IS_OBJECT_ID],
 	//     ...
 	//   )
 	//   PRIMARY KEY key1 [, key2, ...]
@@ -61,14 +61,16 @@ type (
 		Expression Expression `parser:"@@"`
 	}
 
-	// DictionaryColumnAttr represents column attributes like IS_OBJECT_ID, HIERARCHICAL, INJECTIVE
+	// DictionaryColumnAttr represents column attributes like IS_OBJECT_ID, HIERARCHICAL,
+	// BIDIRECTIONAL, and INJECTIVE. Attributes may be given in any order.
 	DictionaryColumnAttr struct {
-		Name string `parser:"@('IS_OBJECT_ID' | 'HIERARCHICAL' | 'INJECTIVE')"`
+		Name string `parser:"@('IS_OBJECT_ID' | 'HIERARCHICAL' | 'BIDIRECTIONAL' | 'INJECTIVE')"`
 	}
 
-	// DictionaryPrimaryKey represents PRIMARY KEY clause
+	// DictionaryPrimaryKey represents PRIMARY KEY clause. The key list may be wrapped in
+	// parentheses (as ClickHouse dumps it), e.g. PRIMARY KEY (id, name).
 	DictionaryPrimaryKey struct {
-		Keys []string `parser:"'PRIMARY' 'KEY' @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))*"`
+		Keys []string `parser:"'PRIMARY' 'KEY' ( '(' @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))* ')' | @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))* )"`
 	}
 
 	// DictionarySource represents SOURCE clause
@@ -203,6 +205,22 @@ type (
 		Semicolon bool `parser:"';'"`
 	}
 
+	// ExchangeDictionariesStmt represents EXCHANGE DICTIONARIES statements, which atomically
+	// swap the names of two dictionaries
+	// Syntax: EXCHANGE DICTIONARIES [db.]dict1 AND [db.]dict2 [ON CLUSTER cluster];
+	ExchangeDictionariesStmt struct {
+		LeadingCommentField
+		Exchange       string  `parser:"'EXCHANGE' 'DICTIONARIES'"`
+		FirstDatabase  *string `parser:"(@(Ident | BacktickIdent) '.')?"`
+		FirstName      string  `parser:"@(Ident | BacktickIdent)"`
+		And            string  `parser:"'AND'"`
+		SecondDatabase *string `parser:"(@(Ident | BacktickIdent) '.')?"`
+		SecondName     string  `parser:"@(Ident | BacktickIdent)"`
+		OnCluster      *string `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
 	// DictionaryRename represents a single dictionary rename operation
 	DictionaryRename struct {
 		FromDatabase *string `parser:"((@(Ident | BacktickIdent) '.')?"`
@@ -17,6 +17,9 @@ func TestCreateDictionary(t *testing.T) {
 		{name: "http_with_credentials", sql: `CREATE DICTIONARY user_segments_dict (user_id UInt64, segment String, score Float64) PRIMARY KEY user_id SOURCE(HTTP(url 'http://ml-service:8080/user-segments' format 'TabSeparated' credentials(user 'user' password 'password') headers(header(name 'API-KEY' value 'key')))) LAYOUT(HASHED()) LIFETIME(3600);`},
 		{name: "http_with_multiple_headers", sql: `CREATE DICTIONARY analytics_dict (id UInt64, data String) PRIMARY KEY id SOURCE(HTTP(url 'https://api.analytics.com/data' format 'JSONEachRow' credentials(user 'api_user' password 'secret123') headers(header(name 'Content-Type' value 'application/json') header(name 'X-Custom-Header' value 'custom-value')))) LAYOUT(FLAT()) LIFETIME(MIN 300 MAX 1800);`},
 		{name: "http_complex_nested", sql: `CREATE DICTIONARY complex_api_dict (entity_id UInt64, metadata String, timestamp DateTime) PRIMARY KEY entity_id SOURCE(HTTP(url 'http://internal-api:9000/entities' format 'CSV' timeout 30 credentials(user 'service' password 'pass') headers(header(name 'Authorization' value 'Bearer token123') header(name 'User-Agent' value 'ClickHouse-Dictionary/1.0')))) LAYOUT(COMPLEX_KEY_HASHED(size_in_cells 1000000)) LIFETIME(MIN 60 MAX 3600);`},
+		{name: "parenthesized_primary_key", sql: `CREATE DICTIONARY users_dict (id UInt64, name String) PRIMARY KEY (id) SOURCE(HTTP(url 'http://localhost/users.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);`},
+		{name: "parenthesized_composite_primary_key", sql: `CREATE DICTIONARY user_mapping (user_id UInt64, group_id UInt32, name String) PRIMARY KEY (user_id, group_id) SOURCE(HTTP(url 'http://localhost/mapping.json' format 'JSONEachRow')) LAYOUT(COMPLEX_KEY_HASHED()) LIFETIME(3600);`},
+		{name: "all_column_attributes", sql: `CREATE DICTIONARY analytics.regions (id UInt64 IS_OBJECT_ID, parent_id UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL, code String INJECTIVE, label String EXPRESSION upper(code) INJECTIVE IS_OBJECT_ID, manager_id UInt64 BIDIRECTIONAL HIERARCHICAL) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);`},
 	}
 
 	runStatementTests(t, "dictionary/create", tests)
@@ -75,3 +78,16 @@ func TestRenameDictionary(t *testing.T) {
 
 	runStatementTests(t, "dictionary/rename", tests)
 }
+
+func TestExchangeDictionaries(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "simple", sql: `EXCHANGE DICTIONARIES users_dict AND users_dict_new;`},
+		{name: "with_database", sql: `EXCHANGE DICTIONARIES db.users_dict AND db.users_dict_v2;`},
+		{name: "on_cluster", sql: `EXCHANGE DICTIONARIES db.users_dict AND db.users_dict_v2 ON CLUSTER my_cluster;`},
+		{name: "backticked_cluster", sql: "EXCHANGE DICTIONARIES `users-dict` AND `users-dict-v2` ON CLUSTER `prod-cluster`;"},
+	}
+
+	runStatementTests(t, "dictionary/exchange", tests)
+}
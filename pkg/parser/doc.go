@@ -8,9 +8,10 @@
 //
 // Supported DDL Operations:
 //   - Database operations: CREATE, ALTER, ATTACH, DETACH, DROP, RENAME DATABASE
-//   - Table operations: CREATE, ALTER, ATTACH, DETACH, DROP, RENAME TABLE
-//   - Dictionary operations: CREATE, ATTACH, DETACH, DROP, RENAME DICTIONARY
+//   - Table operations: CREATE, ALTER, ATTACH, DETACH, DROP, TRUNCATE, RENAME, EXCHANGE TABLES
+//   - Dictionary operations: CREATE, ATTACH, DETACH, DROP, RENAME, EXCHANGE DICTIONARIES
 //   - View operations: CREATE, ATTACH, DETACH, DROP VIEW and MATERIALIZED VIEW
+//   - SYSTEM operations: RELOAD DICTIONARY/DICTIONARIES, RELOAD CONFIG, FLUSH LOGS, SYNC REPLICA
 //   - Expression parsing: Complex expressions with proper operator precedence
 //   - Data types: All ClickHouse types including Nullable, Array, Tuple, Map, Nested
 //
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/pseudomuto/housekeeper/pkg/compare"
@@ -129,11 +130,13 @@ type (
 		Interval    *IntervalExpr      `parser:"| @@"`
 		Extract     *ExtractExpression `parser:"| @@"`
 		Cast        *CastExpression    `parser:"| @@"`
+		Lambda      *LambdaExpression  `parser:"| @@"`
 		Function    *FunctionCall      `parser:"| @@"`
 		Identifier  *IdentifierExpr    `parser:"| @@"`
 		Parentheses *ParenExpression   `parser:"| @@"`
 		Tuple       *TupleExpression   `parser:"| @@"`
 		Array       *ArrayExpression   `parser:"| @@"`
+		Map         *MapExpression     `parser:"| @@"`
 	}
 
 	// Literal represents literal values
@@ -154,7 +157,9 @@ type (
 	// FunctionCall represents function invocations, including parameterized functions like quantilesState(0.5, 0.75)(value)
 	FunctionCall struct {
 		Name             string        `parser:"@(Ident | BacktickIdent)"`
-		FirstParentheses []FunctionArg `parser:"'(' (@@ (',' @@)*)? ')'"`
+		FirstParentheses []FunctionArg `parser:"'(' (@@ (',' @@)*)?"`
+		// Optional ORDER BY within the arguments of an aggregate, e.g. groupArray(x ORDER BY y)
+		OrderBy []OrderByExpr `parser:"('ORDER' 'BY' @@ (',' @@)*)? ')'"`
 		// Optional second set of parentheses for parameterized functions
 		SecondParentheses []FunctionArg `parser:"('(' (@@ (',' @@)*)? ')')?"`
 		Over              *OverClause   `parser:"@@?"`
@@ -166,18 +171,20 @@ type (
 		Expression *Expression `parser:"| @@"`
 	}
 
-	// OverClause for window functions
+	// OverClause for window functions. The window is either defined inline or
+	// references a named window from the query's WINDOW clause (e.g. OVER w).
 	OverClause struct {
 		Over        string        `parser:"'OVER'"`
-		PartitionBy []Expression  `parser:"'(' ('PARTITION' 'BY' @@ (',' @@)*)?"`
+		Name        *string       `parser:"( @(Ident | BacktickIdent)"`
+		PartitionBy []Expression  `parser:"| '(' ('PARTITION' 'BY' @@ (',' @@)*)?"`
 		OrderBy     []OrderByExpr `parser:"('ORDER' 'BY' @@ (',' @@)*)?"`
-		Frame       *WindowFrame  `parser:"@@? ')'"`
+		Frame       *WindowFrame  `parser:"@@? ')' )"`
 	}
 
-	// OrderByExpr for ORDER BY in OVER clause
+	// OrderByExpr for ORDER BY in OVER clauses and function arguments
 	OrderByExpr struct {
 		Expression Expression `parser:"@@"`
-		Desc       bool       `parser:"@'DESC'?"`
+		Desc       bool       `parser:"('ASC' | @'DESC')?"`
 		Nulls      *string    `parser:"('NULLS' @('FIRST' | 'LAST'))?"`
 	}
 
@@ -210,6 +217,24 @@ type (
 		Elements []Expression `parser:"'[' (@@ (',' @@)*)? ']'"`
 	}
 
+	// MapExpression represents map literals (e.g. {'key': 'value'})
+	MapExpression struct {
+		Entries []MapEntry `parser:"'{' (@@ (',' @@)*)? '}'"`
+	}
+
+	// MapEntry represents a single key: value pair in a map literal
+	MapEntry struct {
+		Key   Expression `parser:"@@ ':'"`
+		Value Expression `parser:"@@"`
+	}
+
+	// LambdaExpression represents lambdas passed to higher-order functions
+	// Syntax: x -> expression or (x, y, ...) -> expression
+	LambdaExpression struct {
+		Parameters []string   `parser:"( '(' @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))* ')' | @(Ident | BacktickIdent) )"`
+		Body       Expression `parser:"'->' @@"`
+	}
+
 	// CaseExpression represents CASE expressions
 	CaseExpression struct {
 		Case        string       `parser:"'CASE'"`
@@ -219,18 +244,17 @@ type (
 	}
 
 	// WhenClause represents WHEN condition THEN result
-	// Using simpler parsing to avoid recursion issues during debugging
 	WhenClause struct {
-		When      string `parser:"'WHEN'"`
-		Condition string `parser:"@(~'THEN')+"`
-		Then      string `parser:"'THEN'"`
-		Result    string `parser:"@(~('WHEN' | 'ELSE' | 'END'))+"`
+		When      string     `parser:"'WHEN'"`
+		Condition Expression `parser:"@@"`
+		Then      string     `parser:"'THEN'"`
+		Result    Expression `parser:"@@"`
 	}
 
 	// ElseClause represents ELSE result
 	ElseClause struct {
-		Else   string `parser:"'ELSE'"`
-		Result string `parser:"@(~'END')+"`
+		Else   string     `parser:"'ELSE'"`
+		Result Expression `parser:"@@"`
 	}
 
 	// CastExpression represents type casting
@@ -447,6 +471,9 @@ func (p *PrimaryExpression) String() string {
 	if p.Cast != nil {
 		return p.Cast.String()
 	}
+	if p.Lambda != nil {
+		return p.Lambda.String()
+	}
 	if p.Function != nil {
 		return p.Function.String()
 	}
@@ -462,6 +489,9 @@ func (p *PrimaryExpression) String() string {
 	if p.Array != nil {
 		return p.Array.String()
 	}
+	if p.Map != nil {
+		return p.Map.String()
+	}
 	return ""
 }
 
@@ -505,6 +535,15 @@ func (f *FunctionCall) String() string {
 		}
 		results.WriteString(arg.String())
 	}
+	if len(f.OrderBy) > 0 {
+		results.WriteString(" ORDER BY ")
+		for i, orderExpr := range f.OrderBy {
+			if i > 0 {
+				results.WriteString(", ")
+			}
+			results.WriteString(orderExpr.String())
+		}
+	}
 	results.WriteString(")")
 
 	// Second set of parentheses if present (for parameterized functions)
@@ -539,13 +578,23 @@ func (a *FunctionArg) String() string {
 
 // String returns the string representation of an OverClause for window functions
 func (o *OverClause) String() string {
+	if o.Name != nil {
+		return "OVER " + *o.Name
+	}
+
+	return "OVER " + windowSpecString(o.PartitionBy, o.OrderBy, o.Frame)
+}
+
+// windowSpecString returns the parenthesized window specification shared by OVER
+// clauses and WINDOW clause definitions
+func windowSpecString(partitionBy []Expression, orderBy []OrderByExpr, frame *WindowFrame) string {
 	var results strings.Builder
-	results.WriteString("OVER (")
+	results.WriteString("(")
 
 	// Add PARTITION BY clause if present
-	if len(o.PartitionBy) > 0 {
+	if len(partitionBy) > 0 {
 		results.WriteString("PARTITION BY ")
-		for i, expr := range o.PartitionBy {
+		for i, expr := range partitionBy {
 			if i > 0 {
 				results.WriteString(", ")
 			}
@@ -554,13 +603,13 @@ func (o *OverClause) String() string {
 	}
 
 	// Add ORDER BY clause if present
-	if len(o.OrderBy) > 0 {
-		if len(o.PartitionBy) > 0 {
+	if len(orderBy) > 0 {
+		if len(partitionBy) > 0 {
 			results.WriteString(" ")
 		}
 
 		results.WriteString("ORDER BY ")
-		for i, orderExpr := range o.OrderBy {
+		for i, orderExpr := range orderBy {
 			if i > 0 {
 				results.WriteString(", ")
 			}
@@ -569,18 +618,34 @@ func (o *OverClause) String() string {
 	}
 
 	// Add frame clause if present
-	if o.Frame != nil {
-		if len(o.PartitionBy) > 0 || len(o.OrderBy) > 0 {
+	if frame != nil {
+		if len(partitionBy) > 0 || len(orderBy) > 0 {
 			results.WriteString(" ")
 		}
-		results.WriteString(o.Frame.String())
+		results.WriteString(frame.String())
 	}
 
 	results.WriteString(")")
 	return results.String()
 }
 
+// String returns the parenthesized window specification of a named window definition
+func (w *WindowDefinition) String() string {
+	return windowSpecString(w.PartitionBy, w.OrderBy, w.Frame)
+}
+
+// Equal compares two named window definitions
+func (w *WindowDefinition) Equal(other *WindowDefinition) bool {
+	if eq, done := compare.NilCheck(w, other); !done {
+		return eq
+	}
+
+	return w.Name == other.Name &&
+		windowSpecsEqual(w.PartitionBy, other.PartitionBy, w.OrderBy, other.OrderBy, w.Frame, other.Frame)
+}
+
 // String returns the string representation of an OrderByExpr for ORDER BY in OVER clauses
+// and function arguments
 func (o *OrderByExpr) String() string {
 	result := o.Expression.String()
 	if o.Desc {
@@ -645,20 +710,55 @@ func (a *ArrayExpression) String() string {
 	return results.String()
 }
 
+func (m *MapExpression) String() string {
+	var results strings.Builder
+	results.WriteString("{")
+
+	for i, entry := range m.Entries {
+		if i > 0 {
+			results.WriteString(", ")
+		}
+		results.WriteString(entry.Key.String())
+		results.WriteString(": ")
+		results.WriteString(entry.Value.String())
+	}
+
+	results.WriteString("}")
+	return results.String()
+}
+
+// String returns the string representation of a lambda, parenthesizing multiple parameters.
+func (l *LambdaExpression) String() string {
+	params := l.Parameters[0]
+	if len(l.Parameters) > 1 {
+		params = "(" + strings.Join(l.Parameters, ", ") + ")"
+	}
+	return params + " -> " + l.Body.String()
+}
+
 func (i *IntervalExpr) String() string {
 	return "INTERVAL " + i.Value + " " + i.Unit
 }
 
+// Equal compares two INTERVAL expressions, ignoring the case of the unit
+func (i *IntervalExpr) Equal(other *IntervalExpr) bool {
+	if eq, done := compare.NilCheck(i, other); !done {
+		return eq
+	}
+
+	return i.Value == other.Value && strings.EqualFold(i.Unit, other.Unit)
+}
+
 func (c *CaseExpression) String() string {
 	var results strings.Builder
 	results.WriteString("CASE")
 
 	for _, when := range c.WhenClauses {
-		results.WriteString(" WHEN " + when.Condition + " THEN " + when.Result)
+		results.WriteString(" WHEN " + when.Condition.String() + " THEN " + when.Result.String())
 	}
 
 	if c.ElseClause != nil {
-		results.WriteString(" ELSE " + c.ElseClause.Result)
+		results.WriteString(" ELSE " + c.ElseClause.Result.String())
 	}
 
 	results.WriteString(" END")
@@ -702,11 +802,11 @@ func (c *CaseExpression) Equal(other *CaseExpression) bool {
 	}
 
 	whenEqual := compare.Slices(c.WhenClauses, other.WhenClauses, func(a, b WhenClause) bool {
-		return a.Condition == b.Condition && a.Result == b.Result
+		return a.Condition.Equal(&b.Condition) && a.Result.Equal(&b.Result)
 	})
 
 	elseEqual := (c.ElseClause == nil && other.ElseClause == nil) ||
-		(c.ElseClause != nil && other.ElseClause != nil && c.ElseClause.Result == other.ElseClause.Result)
+		(c.ElseClause != nil && other.ElseClause != nil && c.ElseClause.Result.Equal(&other.ElseClause.Result))
 
 	return whenEqual && elseEqual
 }
@@ -796,16 +896,59 @@ func (c *ComparisonRest) Equal(other *ComparisonRest) bool {
 	if (c.InOp != nil) != (other.InOp != nil) {
 		return false
 	}
-	// For now, just check if both are set - full IN comparison would require more work
+	if c.InOp != nil && !c.InOp.Equal(other.InOp) {
+		return false
+	}
 
 	if (c.BetweenOp != nil) != (other.BetweenOp != nil) {
 		return false
 	}
-	// For now, just check if both are set - full BETWEEN comparison would require more work
+	if c.BetweenOp != nil && !c.BetweenOp.Equal(other.BetweenOp) {
+		return false
+	}
 
 	return true
 }
 
+// Equal compares two IN operations, including their list or array of values. Subqueries
+// are only compared by presence, since SELECT statements are compared by the schema package.
+func (i *InComparison) Equal(other *InComparison) bool {
+	if eq, done := compare.NilCheck(i, other); !done {
+		return eq
+	}
+	if i.Not != other.Not {
+		return false
+	}
+	if eq, done := compare.NilCheck(i.Expr, other.Expr); !done {
+		return eq
+	}
+
+	if !equalExpressions(i.Expr.List, other.Expr.List) {
+		return false
+	}
+	if (i.Expr.Array != nil) != (other.Expr.Array != nil) {
+		return false
+	}
+	if i.Expr.Array != nil && !equalExpressions(i.Expr.Array.Elements, other.Expr.Array.Elements) {
+		return false
+	}
+	return (i.Expr.Subquery != nil) == (other.Expr.Subquery != nil)
+}
+
+// Equal compares two BETWEEN operations, including both of their bounds
+func (b *BetweenComparison) Equal(other *BetweenComparison) bool {
+	if eq, done := compare.NilCheck(b, other); !done {
+		return eq
+	}
+	if b.Not != other.Not {
+		return false
+	}
+	if eq, done := compare.NilCheck(b.Expr, other.Expr); !done {
+		return eq
+	}
+	return b.Expr.Low.Equal(&other.Expr.Low) && b.Expr.High.Equal(&other.Expr.High)
+}
+
 // Equal compares two SimpleComparison operations
 func (s *SimpleComparison) Equal(other *SimpleComparison) bool {
 	if s == nil && other == nil {
@@ -930,6 +1073,14 @@ func (p *PrimaryExpression) Equal(other *PrimaryExpression) bool {
 		return false
 	}
 
+	// Check Interval
+	if (p.Interval != nil) != (other.Interval != nil) {
+		return false
+	}
+	if p.Interval != nil && !p.Interval.Equal(other.Interval) {
+		return false
+	}
+
 	// Check Identifier
 	if (p.Identifier != nil) != (other.Identifier != nil) {
 		return false
@@ -938,6 +1089,14 @@ func (p *PrimaryExpression) Equal(other *PrimaryExpression) bool {
 		return false
 	}
 
+	// Check Lambda
+	if (p.Lambda != nil) != (other.Lambda != nil) {
+		return false
+	}
+	if p.Lambda != nil && !p.Lambda.Equal(other.Lambda) {
+		return false
+	}
+
 	// Check Function
 	if (p.Function != nil) != (other.Function != nil) {
 		return false
@@ -954,10 +1113,67 @@ func (p *PrimaryExpression) Equal(other *PrimaryExpression) bool {
 		return false
 	}
 
+	// Check collection literals
+	if (p.Tuple != nil) != (other.Tuple != nil) {
+		return false
+	}
+	if p.Tuple != nil && !equalExpressions(p.Tuple.Elements, other.Tuple.Elements) {
+		return false
+	}
+	if (p.Array != nil) != (other.Array != nil) {
+		return false
+	}
+	if p.Array != nil && !equalExpressions(p.Array.Elements, other.Array.Elements) {
+		return false
+	}
+	if (p.Map != nil) != (other.Map != nil) {
+		return false
+	}
+	if p.Map != nil && !p.Map.Equal(other.Map) {
+		return false
+	}
+
 	// For other types, do basic comparison
 	return true
 }
 
+// Equal compares two map literals entry by entry, preserving order
+func (m *MapExpression) Equal(other *MapExpression) bool {
+	if eq, done := compare.NilCheck(m, other); !done {
+		return eq
+	}
+	if len(m.Entries) != len(other.Entries) {
+		return false
+	}
+	for i := range m.Entries {
+		if !m.Entries[i].Key.Equal(&other.Entries[i].Key) || !m.Entries[i].Value.Equal(&other.Entries[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal compares two lambdas by their parameter names and body
+func (l *LambdaExpression) Equal(other *LambdaExpression) bool {
+	if eq, done := compare.NilCheck(l, other); !done {
+		return eq
+	}
+	return slices.Equal(l.Parameters, other.Parameters) && l.Body.Equal(&other.Body)
+}
+
+// equalExpressions compares two expression lists element by element
+func equalExpressions(a, b []Expression) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // Equal compares two Literal values
 func (l *Literal) Equal(other *Literal) bool {
 	if eq, done := compare.NilCheck(l, other); !done {
@@ -1021,6 +1237,10 @@ func (f *FunctionCall) Equal(other *FunctionCall) bool {
 		}
 	}
 
+	if !compare.Slices(f.OrderBy, other.OrderBy, func(a, b OrderByExpr) bool { return a.Equal(&b) }) {
+		return false
+	}
+
 	if len(f.SecondParentheses) != len(other.SecondParentheses) {
 		return false
 	}
@@ -1030,7 +1250,56 @@ func (f *FunctionCall) Equal(other *FunctionCall) bool {
 		}
 	}
 
-	return true
+	return f.Over.Equal(other.Over)
+}
+
+// Equal compares two OVER clauses, including partitioning, ordering, and frame
+func (o *OverClause) Equal(other *OverClause) bool {
+	if eq, done := compare.NilCheck(o, other); !done {
+		return eq
+	}
+
+	return compare.Pointers(o.Name, other.Name) &&
+		windowSpecsEqual(o.PartitionBy, other.PartitionBy, o.OrderBy, other.OrderBy, o.Frame, other.Frame)
+}
+
+// windowSpecsEqual compares the partitioning, ordering, and frame of two window specifications
+func windowSpecsEqual(partitionBy1, partitionBy2 []Expression, orderBy1, orderBy2 []OrderByExpr, frame1, frame2 *WindowFrame) bool {
+	return equalExpressions(partitionBy1, partitionBy2) &&
+		compare.Slices(orderBy1, orderBy2, func(a, b OrderByExpr) bool { return a.Equal(&b) }) &&
+		frame1.Equal(frame2)
+}
+
+// Equal compares two ORDER BY expressions within an OVER clause or function arguments
+func (o *OrderByExpr) Equal(other *OrderByExpr) bool {
+	if eq, done := compare.NilCheck(o, other); !done {
+		return eq
+	}
+
+	return o.Expression.Equal(&other.Expression) &&
+		o.Desc == other.Desc &&
+		compare.PointersWithEqual(o.Nulls, other.Nulls, func(a, b *string) bool { return strings.EqualFold(*a, *b) })
+}
+
+// Equal compares two window frames
+func (w *WindowFrame) Equal(other *WindowFrame) bool {
+	if eq, done := compare.NilCheck(w, other); !done {
+		return eq
+	}
+
+	return strings.EqualFold(w.Type, other.Type) &&
+		w.Between == other.Between &&
+		w.Start.Equal(&other.Start) &&
+		w.End.Equal(other.End)
+}
+
+// Equal compares two window frame boundaries
+func (f *FrameBound) Equal(other *FrameBound) bool {
+	if eq, done := compare.NilCheck(f, other); !done {
+		return eq
+	}
+
+	return strings.EqualFold(f.Type, other.Type) && strings.EqualFold(f.Direction, other.Direction)
 }
 
 // Equal compares two FunctionArg
@@ -90,11 +90,25 @@ func TestExpressionParsing(t *testing.T) {
 		{"nested functions", "lower(trim(name))", true},
 		// {"function in expression", "age > now() - INTERVAL 18 YEAR", true}, // INTERVAL parsing issue in isolated tests
 
+		// Lambdas
+		{"single parameter lambda", "arrayMap(x -> x * 2, arr)", true},
+		{"multi parameter lambda", "arrayMap((x, y) -> x + y, a, b)", true},
+		{"lambda with function body", "arrayFilter(x -> notEmpty(x) AND x != 'n/a', tags)", true},
+		{"nested lambdas", "arrayMap(x -> arrayMap(y -> y * x, inner), outer)", true},
+		{"backticked lambda parameter", "arrayExists(`t` -> `t` = 'vip', tags)", true},
+		{"lambda missing body", "arrayMap(x ->, arr)", false},
+
 		// Arrays
 		{"array literal", "[1, 2, 3]", true},
 		{"empty array", "[]", true},
 		{"array in expression", "id IN [1, 2, 3]", true},
 
+		// Maps
+		{"map literal", "{'a': 1, 'b': 2}", true},
+		{"empty map", "{}", true},
+		{"map with array values", "{'hot': ['disk1', 'disk2']}", true},
+		{"map missing colon", "{'a' 1}", false},
+
 		// Tuples
 		{"tuple", "(1, 'a', TRUE)", true},
 		{"empty tuple", "()", true},
@@ -193,3 +207,149 @@ func TestExpressionInContext(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionCallEqual_WindowClause(t *testing.T) {
+	// Helper function to parse a column default expression via ParseString
+	parseDefault := func(t *testing.T, expr string) *parser.Expression {
+		t.Helper()
+
+		sql := fmt.Sprintf("CREATE TABLE test (col UInt64 MATERIALIZED %s) ENGINE = Memory();", expr)
+		result, err := parser.ParseString(sql)
+		require.NoError(t, err)
+
+		defaultClause := result.Statements[0].CreateTable.Elements[0].Column.GetDefault()
+		require.NotNil(t, defaultClause)
+		return defaultClause.Expression
+	}
+
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		equal bool
+	}{
+		{
+			name:  "identical window",
+			left:  "sum(value) OVER (PARTITION BY user_id ORDER BY ts)",
+			right: "sum(value) OVER (PARTITION BY user_id ORDER BY ts)",
+			equal: true,
+		},
+		{
+			name:  "whitespace and keyword case",
+			left:  "sum( value ) OVER (PARTITION BY user_id ORDER BY ts DESC NULLS LAST)",
+			right: "sum(value) over (partition by user_id order by ts desc nulls last)",
+			equal: true,
+		},
+		{
+			name:  "window added",
+			left:  "sum(value)",
+			right: "sum(value) OVER (PARTITION BY user_id)",
+			equal: false,
+		},
+		{
+			name:  "different partition",
+			left:  "sum(value) OVER (PARTITION BY user_id)",
+			right: "sum(value) OVER (PARTITION BY session_id)",
+			equal: false,
+		},
+		{
+			name:  "different order direction",
+			left:  "sum(value) OVER (ORDER BY ts)",
+			right: "sum(value) OVER (ORDER BY ts DESC)",
+			equal: false,
+		},
+		{
+			name:  "identical frame",
+			left:  "sum(value) OVER (ORDER BY ts ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)",
+			right: "sum(value) OVER (ORDER BY ts rows between 1 preceding and current row)",
+			equal: true,
+		},
+		{
+			name:  "different frame",
+			left:  "sum(value) OVER (ORDER BY ts ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)",
+			right: "sum(value) OVER (ORDER BY ts ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)",
+			equal: false,
+		},
+		{
+			name:  "nested window argument",
+			left:  "toUInt64(sum(value) OVER (PARTITION BY user_id))",
+			right: "toUInt64(sum(value) OVER (PARTITION BY session_id))",
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left := parseDefault(t, tt.left)
+			right := parseDefault(t, tt.right)
+
+			require.Equal(t, tt.equal, left.Equal(right))
+			require.Equal(t, tt.equal, right.Equal(left))
+		})
+	}
+}
+
+func TestComparisonEqual_InAndBetween(t *testing.T) {
+	parseCheck := func(t *testing.T, expr string) *parser.Expression {
+		t.Helper()
+
+		sql := fmt.Sprintf("CREATE TABLE test (col UInt64, CONSTRAINT c CHECK %s) ENGINE = Memory();", expr)
+		result, err := parser.ParseString(sql)
+		require.NoError(t, err)
+
+		return &result.Statements[0].CreateTable.Elements[1].Constraint.Expression
+	}
+
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		equal bool
+	}{
+		{name: "identical between", left: "col BETWEEN 1 AND 10", right: "col between 1 and 10", equal: true},
+		{name: "different lower bound", left: "col BETWEEN 1 AND 10", right: "col BETWEEN 2 AND 10", equal: false},
+		{name: "different upper bound", left: "col BETWEEN 1 AND 10", right: "col BETWEEN 1 AND 20", equal: false},
+		{name: "negated between", left: "col BETWEEN 1 AND 10", right: "col NOT BETWEEN 1 AND 10", equal: false},
+		{name: "identical in list", left: "col IN (1, 2, 3)", right: "col IN (1, 2, 3)", equal: true},
+		{name: "different in list", left: "col IN (1, 2, 3)", right: "col IN (1, 2, 4)", equal: false},
+		{name: "longer in list", left: "col IN (1, 2)", right: "col IN (1, 2, 3)", equal: false},
+		{name: "negated in", left: "col IN (1, 2)", right: "col NOT IN (1, 2)", equal: false},
+		{name: "different in array", left: "col IN [1, 2]", right: "col IN [1, 3]", equal: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left := parseCheck(t, tt.left)
+			right := parseCheck(t, tt.right)
+
+			require.Equal(t, tt.equal, left.Equal(right))
+			require.Equal(t, tt.equal, right.Equal(left))
+		})
+	}
+}
+
+func TestLambdaExpression(t *testing.T) {
+	lambda := func(sql string) *parser.LambdaExpression {
+		stmts, err := parser.ParseString("CREATE TABLE test (tags Array(String), ids Array(UInt64) DEFAULT " + sql + ") ENGINE = Memory();")
+		require.NoError(t, err)
+
+		arg := stmts.Statements[0].CreateTable.Elements[1].Column.GetDefault().Expression.Or.And.Not.Comparison.Addition.Multiplication.Unary.Primary.Function.FirstParentheses[0]
+		require.NotNil(t, arg.Expression)
+
+		primary := arg.Expression.Or.And.Not.Comparison.Addition.Multiplication.Unary.Primary
+		require.NotNil(t, primary.Lambda)
+		return primary.Lambda
+	}
+
+	single := lambda("arrayMap(x -> length(x), tags)")
+	require.Equal(t, []string{"x"}, single.Parameters)
+	require.Equal(t, "x -> length(x)", single.String())
+
+	multi := lambda("arrayMap((x, i) -> length(x) * i, tags, ids)")
+	require.Equal(t, []string{"x", "i"}, multi.Parameters)
+	require.Equal(t, "(x, i) -> length(x) * i", multi.String())
+
+	require.True(t, multi.Equal(lambda("arrayMap((`x`, `i`) -> length( x ) * i, tags, ids)")))
+	require.False(t, multi.Equal(lambda("arrayMap((i, x) -> length(x) * i, tags, ids)")), "parameter order is significant")
+	require.False(t, single.Equal(lambda("arrayMap(x -> length(x) + 1, tags)")))
+}
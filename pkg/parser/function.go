@@ -2,14 +2,16 @@ package parser
 
 type (
 	// CreateFunctionStmt represents CREATE FUNCTION statements
-	// Syntax: CREATE FUNCTION name [ON CLUSTER cluster] AS (parameter0, ...) -> expression;
-	//     or: CREATE FUNCTION name [ON CLUSTER cluster] AS parameter -> expression;
+	// Syntax: CREATE [OR REPLACE] FUNCTION [IF NOT EXISTS] name [ON CLUSTER cluster] AS (parameter0, ...) -> expression;
+	//     or: CREATE [OR REPLACE] FUNCTION [IF NOT EXISTS] name [ON CLUSTER cluster] AS parameter -> expression;
 	CreateFunctionStmt struct {
 		LeadingCommentField
-		Name       string           `parser:"'CREATE' 'FUNCTION' @(Ident | BacktickIdent)"`
-		OnCluster  *string          `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
-		Parameters []*FunctionParam `parser:"'AS' ( '(' (@@ (',' @@)*)? ')' | @@ )"`
-		Expression *Expression      `parser:"'->' @@"`
+		OrReplace   bool             `parser:"'CREATE' @('OR' 'REPLACE')? 'FUNCTION'"`
+		IfNotExists bool             `parser:"@('IF' 'NOT' 'EXISTS')?"`
+		Name        string           `parser:"@(Ident | BacktickIdent)"`
+		OnCluster   *string          `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Parameters  []*FunctionParam `parser:"'AS' ( '(' (@@ (',' @@)*)? ')' | @@ )"`
+		Expression  *Expression      `parser:"'->' @@"`
 		TrailingCommentField
 		Semicolon bool `parser:"';'?"`
 	}
@@ -16,6 +16,9 @@ func TestCreateFunction(t *testing.T) {
 		{name: "backticked_cluster", sql: "CREATE FUNCTION calc_percentage ON CLUSTER `prod-cluster` AS (part, total) -> if(equals(total, 0), 0, divide(multiply(part, 100.0), total));"},
 		{name: "clickhouse_format", sql: `CREATE FUNCTION normalizedBrowser AS br -> multiIf(lower(br) = 'firefox', 'Firefox', lower(br) = 'edge', 'Edge', lower(br) = 'safari', 'Safari', lower(br) = 'chrome', 'Chrome', lower(br) = 'webview', 'Webview', 'Other');`},
 		{name: "clickhouse_format_on_cluster", sql: `CREATE FUNCTION normalizedOS ON CLUSTER warehouse AS os -> multiIf(startsWith(lower(os), 'windows'), 'Windows', startsWith(lower(os), 'mac'), 'Mac', 'Other');`},
+		{name: "infix_lambda", sql: `CREATE FUNCTION linear_eq AS (x, k, b) -> k*x + b;`},
+		{name: "or_replace", sql: `CREATE OR REPLACE FUNCTION linear_eq ON CLUSTER production AS (x, k, b) -> k * x + b;`},
+		{name: "if_not_exists", sql: `CREATE FUNCTION IF NOT EXISTS clamp AS (value, low, high) -> greatest(low, least(value, high));`},
 	}
 
 	runStatementTests(t, "function/create", tests)
@@ -0,0 +1,114 @@
+package parser
+
+import "fmt"
+
+// ErrDuplicateObject is returned by Merge when the same object is defined more than once.
+// Both definitions are identified by the index of the input SQL they came from and the
+// index of the statement within that input (both 0-based).
+//
+// Example:
+//
+//	var dup *parser.ErrDuplicateObject
+//	if errors.As(err, &dup) {
+//		fmt.Printf("%s %s defined twice\n", dup.Kind, dup.Name)
+//	}
+type ErrDuplicateObject struct {
+	Kind   string         // Object kind: "database", "table", "view", "dictionary", etc.
+	Name   string         // Qualified object name, e.g. analytics.events
+	First  StatementIndex // Location of the first definition
+	Second StatementIndex // Location of the conflicting definition
+}
+
+// StatementIndex locates a statement within the inputs passed to Merge.
+type StatementIndex struct {
+	Input     int // Index of the *SQL argument
+	Statement int // Index of the statement within that SQL
+}
+
+// Error implements the error interface.
+func (e *ErrDuplicateObject) Error() string {
+	return fmt.Sprintf("duplicate definition of %s %s: input %d statement %d conflicts with input %d statement %d",
+		e.Kind, e.Name, e.First.Input, e.First.Statement, e.Second.Input, e.Second.Statement)
+}
+
+// Merge concatenates the statements of multiple parsed SQL inputs, preserving their order.
+// It is the building block for composing schemas from multiple entrypoints or overlaying
+// a base schema.
+//
+// Every CREATE statement defines an object, and an object may only be defined once across
+// all inputs. Tables, views, and dictionaries share a namespace within a database (as they
+// do in ClickHouse), so a view and a table with the same qualified name also conflict.
+// Unqualified names are resolved against the default database. Nil inputs are skipped.
+//
+// Example:
+//
+//	base, _ := parser.ParseString("CREATE DATABASE analytics ENGINE = Atomic;")
+//	overlay, _ := parser.ParseString("CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;")
+//
+//	merged, err := parser.Merge(base, overlay)
+//	if err != nil {
+//		var dup *parser.ErrDuplicateObject
+//		if errors.As(err, &dup) {
+//			log.Fatalf("%s %s is defined twice", dup.Kind, dup.Name)
+//		}
+//	}
+func Merge(sqls ...*SQL) (*SQL, error) {
+	merged := &SQL{}
+	seen := make(map[string]StatementIndex)
+
+	for i, sql := range sqls {
+		if sql == nil {
+			continue
+		}
+
+		for j, stmt := range sql.Statements {
+			merged.Statements = append(merged.Statements, stmt)
+
+			namespace, kind, name := definedObject(stmt)
+			if kind == "" {
+				continue
+			}
+
+			current := StatementIndex{Input: i, Statement: j}
+			key := namespace + ":" + name
+			if first, exists := seen[key]; exists {
+				return nil, &ErrDuplicateObject{Kind: kind, Name: name, First: first, Second: current}
+			}
+
+			seen[key] = current
+		}
+	}
+
+	return merged, nil
+}
+
+// definedObject returns the namespace, kind, and qualified name of the object defined by
+// stmt. The kind is empty for statements that do not define an object.
+func definedObject(stmt *Statement) (string, string, string) {
+	switch {
+	case stmt.CreateDatabase != nil:
+		return "database", "database", stmt.CreateDatabase.Name
+	case stmt.CreateTable != nil:
+		return "relation", "table", qualifiedName(stmt.CreateTable.Database, stmt.CreateTable.Name)
+	case stmt.CreateView != nil:
+		return "relation", "view", qualifiedName(stmt.CreateView.Database, stmt.CreateView.Name)
+	case stmt.CreateDictionary != nil:
+		return "relation", "dictionary", qualifiedName(stmt.CreateDictionary.Database, stmt.CreateDictionary.Name)
+	case stmt.CreateFunction != nil:
+		return "function", "function", stmt.CreateFunction.Name
+	case stmt.CreateRole != nil:
+		return "role", "role", stmt.CreateRole.Name
+	case stmt.CreateNamedCollection != nil:
+		return "named collection", "named collection", stmt.CreateNamedCollection.Name
+	default:
+		return "", "", ""
+	}
+}
+
+// qualifiedName returns database.name, using the default database when none is given.
+func qualifiedName(database *string, name string) string {
+	if database == nil || *database == "" {
+		return "default." + name
+	}
+	return *database + "." + name
+}
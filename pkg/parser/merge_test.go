@@ -0,0 +1,145 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean merge preserves order", func(t *testing.T) {
+		t.Parallel()
+
+		base := mustParse(t, `
+			CREATE DATABASE analytics ENGINE = Atomic;
+			CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;`)
+		overlay := mustParse(t, `
+			CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;
+			CREATE VIEW analytics.recent AS SELECT * FROM analytics.events;
+			CREATE DICTIONARY analytics.lookup (id UInt64) PRIMARY KEY id SOURCE(NULL()) LAYOUT(FLAT()) LIFETIME(0);`)
+
+		merged, err := Merge(base, nil, overlay)
+		require.NoError(t, err)
+		require.Len(t, merged.Statements, 5)
+		require.Equal(t, "analytics", merged.Statements[0].CreateDatabase.Name)
+		require.Equal(t, "events", merged.Statements[1].CreateTable.Name)
+		require.Equal(t, "users", merged.Statements[2].CreateTable.Name)
+		require.Equal(t, "recent", merged.Statements[3].CreateView.Name)
+		require.Equal(t, "lookup", merged.Statements[4].CreateDictionary.Name)
+	})
+
+	t.Run("same name in different databases", func(t *testing.T) {
+		t.Parallel()
+
+		merged, err := Merge(
+			mustParse(t, "CREATE TABLE a.events (id UInt64) ENGINE = MergeTree() ORDER BY id;"),
+			mustParse(t, "CREATE TABLE b.events (id UInt64) ENGINE = MergeTree() ORDER BY id;"),
+		)
+		require.NoError(t, err)
+		require.Len(t, merged.Statements, 2)
+	})
+
+	t.Run("no inputs", func(t *testing.T) {
+		t.Parallel()
+
+		merged, err := Merge()
+		require.NoError(t, err)
+		require.Empty(t, merged.Statements)
+	})
+
+	conflicts := []struct {
+		name     string
+		first    string
+		second   string
+		kind     string
+		object   string
+		expected StatementIndex
+	}{
+		{
+			name:     "database",
+			first:    "CREATE DATABASE analytics ENGINE = Atomic;",
+			second:   "CREATE DATABASE analytics;",
+			kind:     "database",
+			object:   "analytics",
+			expected: StatementIndex{Input: 1, Statement: 0},
+		},
+		{
+			name:     "table",
+			first:    "CREATE DATABASE analytics; CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;",
+			second:   "CREATE TABLE analytics.events (id String) ENGINE = Memory;",
+			kind:     "table",
+			object:   "analytics.events",
+			expected: StatementIndex{Input: 1, Statement: 0},
+		},
+		{
+			name:     "table in default database",
+			first:    "CREATE TABLE events (id UInt64) ENGINE = Memory;",
+			second:   "CREATE TABLE default.events (id UInt64) ENGINE = Memory;",
+			kind:     "table",
+			object:   "default.events",
+			expected: StatementIndex{Input: 1, Statement: 0},
+		},
+		{
+			name:     "view",
+			first:    "CREATE VIEW analytics.recent AS SELECT 1;",
+			second:   "CREATE TABLE analytics.users (id UInt64) ENGINE = Memory; CREATE MATERIALIZED VIEW analytics.recent ENGINE = Memory AS SELECT 1;",
+			kind:     "view",
+			object:   "analytics.recent",
+			expected: StatementIndex{Input: 1, Statement: 1},
+		},
+		{
+			name:     "dictionary",
+			first:    "CREATE DICTIONARY analytics.lookup (id UInt64) PRIMARY KEY id SOURCE(NULL()) LAYOUT(FLAT()) LIFETIME(0);",
+			second:   "CREATE DICTIONARY analytics.lookup (id UInt64) PRIMARY KEY id SOURCE(NULL()) LAYOUT(HASHED()) LIFETIME(0);",
+			kind:     "dictionary",
+			object:   "analytics.lookup",
+			expected: StatementIndex{Input: 1, Statement: 0},
+		},
+		{
+			name:     "view shadowing table",
+			first:    "CREATE TABLE analytics.events (id UInt64) ENGINE = Memory;",
+			second:   "CREATE VIEW analytics.events AS SELECT 1;",
+			kind:     "view",
+			object:   "analytics.events",
+			expected: StatementIndex{Input: 1, Statement: 0},
+		},
+	}
+
+	for _, tt := range conflicts {
+		t.Run("conflicting "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Merge(mustParse(t, tt.first), mustParse(t, tt.second))
+			require.Error(t, err)
+
+			var dup *ErrDuplicateObject
+			require.True(t, errors.As(err, &dup))
+			require.Equal(t, tt.kind, dup.Kind)
+			require.Equal(t, tt.object, dup.Name)
+			require.Equal(t, 0, dup.First.Input)
+			require.Equal(t, tt.expected, dup.Second)
+		})
+	}
+
+	t.Run("conflict within a single input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Merge(mustParse(t, `
+			CREATE DATABASE analytics;
+			CREATE TABLE analytics.events (id UInt64) ENGINE = Memory;
+			CREATE TABLE analytics.events (id UInt64) ENGINE = Memory;`))
+		require.EqualError(t, err, "duplicate definition of table analytics.events: input 0 statement 1 conflicts with input 0 statement 2")
+	})
+}
+
+func mustParse(t *testing.T, sql string) *SQL {
+	t.Helper()
+
+	parsed, err := ParseString(sql)
+	require.NoError(t, err)
+	return parsed
+}
@@ -48,7 +48,7 @@ type (
 	// AlterNamedCollectionOperations represents all operations in an ALTER NAMED COLLECTION statement
 	AlterNamedCollectionOperations struct {
 		SetParams    []*NamedCollectionSetParameter `parser:"('SET' @@ (',' @@)*)?"`
-		DeleteParams []*string                      `parser:"('DELETE' @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))*)?"`
+		DeleteParams []string                       `parser:"('DELETE' @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))*)?"`
 	}
 
 	// NamedCollectionSetParameter represents a single SET parameter in an ALTER NAMED COLLECTION
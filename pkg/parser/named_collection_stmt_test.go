@@ -18,6 +18,7 @@ func TestAlterNamedCollection(t *testing.T) {
 
 	tests := []statementTest{
 		{name: "set_delete", sql: `ALTER NAMED COLLECTION kafka_config SET kafka_topic_list = 'events,logs' OVERRIDABLE, kafka_max_block_size = 2097152 NOT OVERRIDABLE DELETE kafka_skip_broken_messages;`},
+		{name: "delete", sql: `ALTER NAMED COLLECTION kafka_config DELETE kafka_skip_broken_messages, ` + "`kafka_format`" + `;`},
 	}
 
 	runStatementTests(t, "named_collection/alter", tests)
@@ -3,6 +3,7 @@ package parser
 import (
 	"io"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/alecthomas/participle/v2"
@@ -23,18 +24,26 @@ var (
 		{Name: "NotEq", Pattern: `!=|<>`},
 		{Name: "LtEq", Pattern: `<=`},
 		{Name: "GtEq", Pattern: `>=`},
-		{Name: "Punct", Pattern: `[(),.;=+\-*/%<>\[\]!]`},
+		{Name: "Punct", Pattern: `[(),.;=+\-*/%<>\[\]{}:!]`},
 		{Name: "Whitespace", Pattern: `\s+`},
 	})
 
-	// parser is the participle parser instance for ClickHouse DDL
-	parser = participle.MustBuild[SQL](
+	// parserOptions are shared by all participle parser instances
+	parserOptions = []participle.Option{
 		participle.Lexer(clickhouseLexer),
 		participle.Elide("Whitespace"), // Only elide whitespace, capture comments
-		participle.UseLookahead(4),
+		// Unbounded lookahead lets tuples like (toYYYYMM(date), id) backtrack out of the
+		// parenthesized expression branch, however long their first element is
+		participle.UseLookahead(participle.MaxLookahead),
 		participle.CaseInsensitive("Ident"), // Make identifier matching case-insensitive for keywords
 		participle.Map(stripBackticksFromTokens, "BacktickIdent"),
-	)
+	}
+
+	// parser is the participle parser instance for ClickHouse DDL
+	parser = participle.MustBuild[SQL](parserOptions...)
+
+	// codecParser parses standalone CODEC(...) clauses (see ParseCodec)
+	codecParser = participle.MustBuild[CodecClause](parserOptions...)
 )
 
 // stripBackticksFromTokens strips backticks from BacktickIdent tokens during parsing
@@ -63,14 +72,15 @@ func normalizeImplicitAliases(sql string) string {
 	// Process patterns carefully, checking each match individually
 
 	// Pattern 1: FROM tablename alias WHERE/GROUP/ORDER/etc
-	keywords := []string{"WHERE", "LEFT", "RIGHT", "INNER", "JOIN", "GROUP", "ORDER", "LIMIT", "HAVING", "SETTINGS"}
+	keywords := []string{"PREWHERE", "WHERE", "ARRAY", "LEFT", "RIGHT", "INNER", "JOIN", "GROUP", "ORDER", "LIMIT", "HAVING", "SETTINGS"}
 
 	for _, keyword := range keywords {
 		// Only process if the match doesn't already contain AS
 		pattern := regexp.MustCompile(`\bFROM\s+(\w+(?:\.\w+)?)\s+(\w+)\s+` + keyword + `\b`)
 		matches := pattern.FindAllStringSubmatch(result, -1)
 		for _, match := range matches {
-			if len(match) == 3 && !regexp.MustCompile(`\bAS\s+`).MatchString(match[0]) {
+			// Join modifiers (e.g. the LEFT in LEFT JOIN) aren't aliases
+			if len(match) == 3 && !isJoinModifier(match[2]) && !regexp.MustCompile(`\bAS\s+`).MatchString(match[0]) {
 				result = strings.ReplaceAll(result, match[0], "FROM "+match[1]+" AS "+match[2]+" "+keyword)
 			}
 		}
@@ -115,6 +125,123 @@ func normalizeImplicitAliases(sql string) string {
 	return result
 }
 
+// terminateFinalStatement adds the semicolon that the final statement is missing, e.g.
+// "CREATE DATABASE db" becomes "CREATE DATABASE db;". It's inserted directly after the
+// statement, so trailing comments and the positions reported in parse errors are kept.
+// SQL that already ends with a semicolon (or can't be tokenized) is returned unchanged.
+func terminateFinalStatement(sql string) string {
+	lex, err := clickhouseLexer.LexString("", sql)
+	if err != nil {
+		return sql
+	}
+
+	symbols := clickhouseLexer.Symbols()
+	ignored := []lexer.TokenType{symbols["Whitespace"], symbols["Comment"], symbols["MultilineComment"]}
+
+	var last *lexer.Token
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return sql
+		}
+		if token.EOF() {
+			break
+		}
+		if !slices.Contains(ignored, token.Type) {
+			last = &token
+		}
+	}
+
+	if last == nil || last.Value == ";" {
+		return sql
+	}
+
+	end := last.Pos.Offset + len(last.Value)
+	return sql[:end] + ";" + sql[end:]
+}
+
+// isJoinModifier reports whether word is a keyword that can precede JOIN or ARRAY JOIN
+func isJoinModifier(word string) bool {
+	switch strings.ToUpper(word) {
+	case "LEFT", "RIGHT", "INNER", "FULL", "CROSS", "OUTER", "GLOBAL", "ASOF", "ANY", "ALL", "SEMI", "ANTI", "ARRAY":
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeComments moves COMMENT clauses that ClickHouse accepts in more than one
+// position into a single field, so statements compare and format the same regardless
+// of where the comment was written.
+func normalizeComments(sql *SQL) {
+	for _, stmt := range sql.Statements {
+		if stmt == nil {
+			continue
+		}
+
+		if stmt.CreateTable != nil {
+			stmt.CreateTable.Clauses, stmt.CreateTable.Comment = normalizeTableComment(stmt.CreateTable.Clauses, stmt.CreateTable.Comment)
+		}
+
+		if stmt.AttachTable != nil {
+			stmt.AttachTable.Clauses, stmt.AttachTable.Comment = normalizeTableComment(stmt.AttachTable.Clauses, stmt.AttachTable.Comment)
+		}
+
+		if stmt.AlterTable != nil {
+			for _, op := range stmt.AlterTable.Operations {
+				if op.ModifyColumn != nil && op.ModifyColumn.CommentBeforeCodec != nil {
+					if op.ModifyColumn.Comment == nil {
+						op.ModifyColumn.Comment = op.ModifyColumn.CommentBeforeCodec
+					}
+					op.ModifyColumn.CommentBeforeCodec = nil
+				}
+			}
+		}
+	}
+}
+
+// normalizeOnCluster moves the ON CLUSTER clause of ALTER USER statements, which
+// ClickHouse accepts before or after RENAME TO, into OnCluster.
+func normalizeOnCluster(sql *SQL) {
+	for _, stmt := range sql.Statements {
+		if stmt == nil || stmt.AlterUser == nil || stmt.AlterUser.OnClusterAfterRename == nil {
+			continue
+		}
+
+		if stmt.AlterUser.OnCluster == nil {
+			stmt.AlterUser.OnCluster = stmt.AlterUser.OnClusterAfterRename
+		}
+		stmt.AlterUser.OnClusterAfterRename = nil
+	}
+}
+
+// normalizeTableComment moves a table COMMENT written between clauses to the statement,
+// returning the remaining clauses and the statement's comment. Any comment lines attached
+// to it are kept with the clause that followed it.
+func normalizeTableComment(clauses []TableClause, comment *string) ([]TableClause, *string) {
+	for i := 0; i < len(clauses); i++ {
+		clause := clauses[i]
+		if clause.Comment == nil {
+			continue
+		}
+
+		if comment == nil {
+			comment = clause.Comment
+		}
+
+		// The grammar only captures the comment here when another clause follows it
+		if next := i + 1; next < len(clauses) {
+			comments := slices.Concat(clause.LeadingComments, clause.TrailingComments, clauses[next].LeadingComments)
+			clauses[next].LeadingComments = comments
+		}
+
+		clauses = slices.Delete(clauses, i, i+1)
+		i--
+	}
+
+	return clauses, comment
+}
+
 // normalizeDataTypes walks through all statements and normalizes data types
 func normalizeDataTypes(sql *SQL) {
 	if sql == nil {
@@ -152,14 +279,62 @@ func normalizeDataTypes(sql *SQL) {
 	}
 }
 
+// ErrParseFailed is returned when SQL cannot be parsed. It carries the position of
+// the offending token so callers can report it or point editors at the problem.
+//
+// Example:
+//
+//	var parseErr *parser.ErrParseFailed
+//	if errors.As(err, &parseErr) {
+//		fmt.Printf("syntax error at %d:%d: %s\n", parseErr.Line, parseErr.Column, parseErr.Message)
+//	}
+type ErrParseFailed struct {
+	Line    int    // 1-based line of the offending token (0 if unknown)
+	Column  int    // 1-based column of the offending token (0 if unknown)
+	Message string // Parser message without position information
+	Err     error  // Underlying parser error
+}
+
+// newParseError converts a participle error into an ErrParseFailed.
+func newParseError(err error) *ErrParseFailed {
+	parseErr := &ErrParseFailed{Message: err.Error(), Err: err}
+
+	var pErr participle.Error
+	if errors.As(err, &pErr) {
+		pos := pErr.Position()
+		parseErr.Line = pos.Line
+		parseErr.Column = pos.Column
+		parseErr.Message = pErr.Message()
+	}
+
+	return parseErr
+}
+
+// Error implements the error interface.
+func (e *ErrParseFailed) Error() string {
+	return "failed to parse SQL: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying parser error.
+func (e *ErrParseFailed) Unwrap() error {
+	return e.Err
+}
+
 type (
 	// SQL defines the complete ClickHouse DDL/DML SQL structure
+	// Stray semicolons between statements (e.g. ";;") are empty statements and are skipped.
 	SQL struct {
-		Statements []*Statement `parser:"@@*"`
+		Statements []*Statement `parser:"(@@ | ';')*"`
 	}
 
-	// Statement represents any DDL or DML statement
+	// Statement represents any DDL or DML statement. Pos and EndPos record where the
+	// statement starts and ends in the parsed input (they're zero for statements that
+	// weren't parsed), which lets the formatter keep comments next to the statements
+	// they document.
 	Statement struct {
+		Pos    lexer.Position
+		EndPos lexer.Position
+
 		CommentStatement      *CommentStatement          `parser:"@@"`
 		CreateDatabase        *CreateDatabaseStmt        `parser:"| @@"`
 		AlterDatabase         *AlterDatabaseStmt         `parser:"| @@"`
@@ -176,6 +351,9 @@ type (
 		CreateRole            *CreateRoleStmt            `parser:"| @@"`
 		AlterRole             *AlterRoleStmt             `parser:"| @@"`
 		DropRole              *DropRoleStmt              `parser:"| @@"`
+		CreateUser            *CreateUserStmt            `parser:"| @@"`
+		AlterUser             *AlterUserStmt             `parser:"| @@"`
+		DropUser              *DropUserStmt              `parser:"| @@"`
 		SetRole               *SetRoleStmt               `parser:"| @@"`
 		SetDefaultRole        *SetDefaultRoleStmt        `parser:"| @@"`
 		Grant                 *GrantStmt                 `parser:"| @@"`
@@ -192,8 +370,12 @@ type (
 		AttachTable           *AttachTableStmt           `parser:"| @@"`
 		DetachTable           *DetachTableStmt           `parser:"| @@"`
 		DropTable             *DropTableStmt             `parser:"| @@"`
+		TruncateTable         *TruncateTableStmt         `parser:"| @@"`
 		RenameTable           *RenameTableStmt           `parser:"| @@"`
 		RenameDictionary      *RenameDictionaryStmt      `parser:"| @@"`
+		ExchangeTables        *ExchangeTablesStmt        `parser:"| @@"`
+		ExchangeDictionaries  *ExchangeDictionariesStmt  `parser:"| @@"`
+		System                *SystemStmt                `parser:"| @@"`
 		SelectStatement       *TopLevelSelectStatement   `parser:"| @@"`
 	}
 
@@ -247,16 +429,30 @@ type (
 //		}
 //	}
 //
-// Returns an error if the reader cannot be read or contains invalid SQL.
+// The semicolon after the final statement is optional, and empty statements (";;") are
+// ignored, so SQL from hand-written files and other tools parses as-is.
+//
+// Returns an *ErrParseFailed if the reader cannot be read or contains invalid SQL.
 func Parse(reader io.Reader) (*SQL, error) {
-	sqlResult, err := parser.Parse("", reader)
+	data, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse SQL")
+		return nil, newParseError(err)
+	}
+
+	sqlResult, err := parser.ParseString("", terminateFinalStatement(string(data)))
+	if err != nil {
+		return nil, newParseError(err)
 	}
 
 	// Normalize data types in all statements
 	normalizeDataTypes(sqlResult)
 
+	// Move comments written in alternative positions to their canonical fields
+	normalizeComments(sqlResult)
+
+	// Move ON CLUSTER clauses written after RENAME TO to their canonical fields
+	normalizeOnCluster(sqlResult)
+
 	return sqlResult, nil
 }
 
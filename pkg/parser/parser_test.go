@@ -34,3 +34,53 @@ CREATE TABLE test.events (
 	require.Equal(t, "test", *result.Statements[1].CreateTable.Database)
 	require.Len(t, result.Statements[1].CreateTable.Elements, 2)
 }
+
+func TestParseErrorPosition(t *testing.T) {
+	_, err := ParseString("CREATE DATABASE test ENGINE = Atomic;\nCREATE TABLE oops (\n    id UInt64,\n) ENGINE = MergeTree() ORDER BY id;")
+	require.Error(t, err)
+
+	var parseErr *ErrParseFailed
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 4, parseErr.Line)
+	require.Equal(t, 1, parseErr.Column)
+	require.NotEmpty(t, parseErr.Message)
+	require.Contains(t, err.Error(), "failed to parse SQL")
+}
+
+func TestParseSemicolons(t *testing.T) {
+	tests := []struct {
+		name       string
+		sql        string
+		statements int
+	}{
+		{"missing final semicolon", "CREATE DATABASE test ENGINE = Atomic", 1},
+		{"missing final semicolon after statements", "CREATE DATABASE a;\nCREATE DATABASE b", 2},
+		{"missing final semicolon before comment", "CREATE DATABASE test -- trailing comment\n", 2},
+		{"missing final semicolon after multiline statement", "CREATE TABLE test.events (\n    id UInt64\n) ENGINE = MergeTree() ORDER BY id\n\n", 1},
+		{"extra semicolons", "CREATE DATABASE a;;\n;\nCREATE DATABASE b;;", 2},
+		{"leading semicolon", ";CREATE DATABASE test;", 1},
+		{"only semicolons", ";;;", 0},
+		{"only comments", "-- nothing to see here\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseString(tt.sql)
+			require.NoError(t, err)
+			require.Len(t, result.Statements, tt.statements)
+		})
+	}
+
+	t.Run("statements still require separators", func(t *testing.T) {
+		_, err := ParseString("CREATE DATABASE a CREATE DATABASE b")
+		require.Error(t, err)
+	})
+
+	t.Run("error position is unchanged", func(t *testing.T) {
+		_, err := ParseString("CREATE DATABASE test;\nCREATE TABLE oops (")
+
+		var parseErr *ErrParseFailed
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, 2, parseErr.Line)
+	})
+}
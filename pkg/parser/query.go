@@ -5,17 +5,29 @@ package parser
 type (
 	// SelectStatement represents a SELECT statement (for subqueries, no semicolon)
 	SelectStatement struct {
-		With     *WithClause          `parser:"@@?"`
-		Select   string               `parser:"'SELECT'"`
-		Distinct bool                 `parser:"@'DISTINCT'?"`
-		Columns  []SelectColumn       `parser:"@@ (',' @@)*"`
-		From     *FromClause          `parser:"@@?"`
-		Where    *WhereClause         `parser:"@@?"`
-		GroupBy  *GroupByClause       `parser:"@@?"`
-		Having   *HavingClause        `parser:"@@?"`
-		OrderBy  *SelectOrderByClause `parser:"@@?"`
-		Limit    *LimitClause         `parser:"@@?"`
-		Settings *SettingsClause      `parser:"@@?"`
+		With      *WithClause          `parser:"@@?"`
+		Select    string               `parser:"'SELECT'"`
+		Distinct  bool                 `parser:"@'DISTINCT'?"`
+		Columns   []SelectColumn       `parser:"@@ (',' @@)*"`
+		From      *FromClause          `parser:"@@?"`
+		ArrayJoin *ArrayJoinClause     `parser:"@@?"`
+		PreWhere  *PreWhereClause      `parser:"@@?"`
+		Where     *WhereClause         `parser:"@@?"`
+		GroupBy   *GroupByClause       `parser:"@@?"`
+		Having    *HavingClause        `parser:"@@?"`
+		Window    *WindowClause        `parser:"@@?"`
+		OrderBy   *SelectOrderByClause `parser:"@@?"`
+		Limit     *LimitClause         `parser:"@@?"`
+		Settings  *SettingsClause      `parser:"@@?"`
+		Union     *UnionClause         `parser:"@@?"`
+	}
+
+	// UnionClause combines a SELECT with the next one, e.g. UNION ALL SELECT ...
+	// Chains nest to the right, so A UNION ALL B UNION ALL C is A.Union -> B.Union -> C.
+	UnionClause struct {
+		Union  string           `parser:"'UNION'"`
+		Mode   *string          `parser:"@('ALL' | 'DISTINCT')?"`
+		Select *SelectStatement `parser:"@@"`
 	}
 
 	// TopLevelSelectStatement represents a top-level SELECT statement (requires semicolon)
@@ -95,7 +107,7 @@ type (
 	// JoinClause represents JOIN operations
 	JoinClause struct {
 		Type      string         `parser:"@('INNER' | 'LEFT' | 'RIGHT' | 'FULL' | 'CROSS')?"`
-		Join      string         `parser:"@('JOIN' | 'ARRAY' 'JOIN' | 'GLOBAL' 'JOIN' | 'ASOF' 'JOIN')"`
+		Join      string         `parser:"@('JOIN' | 'GLOBAL' 'JOIN' | 'ASOF' 'JOIN')"`
 		Table     TableRef       `parser:"@@"`
 		Condition *JoinCondition `parser:"@@?"`
 	}
@@ -106,18 +118,41 @@ type (
 		Using []string    `parser:"| 'USING' '(' @(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))* ')'"`
 	}
 
+	// ArrayJoinClause represents [LEFT] ARRAY JOIN clause, which unfolds one or more arrays
+	// into rows, e.g. ARRAY JOIN tags AS tag, arrayEnumerate(tags) AS idx
+	ArrayJoinClause struct {
+		Left   bool               `parser:"@'LEFT'? 'ARRAY' 'JOIN'"`
+		Arrays []ArrayJoinElement `parser:"@@ (',' @@)*"`
+	}
+
+	// ArrayJoinElement represents a single array in an ARRAY JOIN clause
+	ArrayJoinElement struct {
+		Expression Expression `parser:"@@"`
+		Alias      *string    `parser:"('AS' @(Ident | BacktickIdent))?"`
+	}
+
 	// WhereClause represents WHERE clause
 	WhereClause struct {
 		Where     string     `parser:"'WHERE'"`
 		Condition Expression `parser:"@@"`
 	}
 
-	// GroupByClause represents GROUP BY clause
+	// PreWhereClause represents PREWHERE clause, which ClickHouse evaluates before reading
+	// the remaining columns (MergeTree family only). It sits between FROM and WHERE.
+	PreWhereClause struct {
+		PreWhere  string     `parser:"'PREWHERE'"`
+		Condition Expression `parser:"@@"`
+	}
+
+	// GroupByClause represents GROUP BY clause, including the WITH ROLLUP and WITH CUBE
+	// modifiers and WITH TOTALS, which may follow either of them
 	GroupByClause struct {
 		GroupBy    string       `parser:"'GROUP' 'BY'"`
 		All        bool         `parser:"(@'ALL'"`
 		Columns    []Expression `parser:"| @@ (',' @@)*)"`
-		WithClause *string      `parser:"('WITH' @('CUBE' | 'ROLLUP' | 'TOTALS'))?"`
+		WithRollup bool         `parser:"('WITH' (@'ROLLUP'"`
+		WithCube   bool         `parser:"| @'CUBE'"`
+		WithTotals bool         `parser:"| @'TOTALS'))*"`
 	}
 
 	// HavingClause represents HAVING clause
@@ -126,6 +161,21 @@ type (
 		Condition Expression `parser:"@@"`
 	}
 
+	// WindowClause represents the WINDOW clause defining named windows that window
+	// functions reference with OVER name
+	WindowClause struct {
+		Window      string             `parser:"'WINDOW'"`
+		Definitions []WindowDefinition `parser:"@@ (',' @@)*"`
+	}
+
+	// WindowDefinition represents a single named window (e.g. w AS (PARTITION BY user_id))
+	WindowDefinition struct {
+		Name        string        `parser:"@(Ident | BacktickIdent) 'AS'"`
+		PartitionBy []Expression  `parser:"'(' ('PARTITION' 'BY' @@ (',' @@)*)?"`
+		OrderBy     []OrderByExpr `parser:"('ORDER' 'BY' @@ (',' @@)*)?"`
+		Frame       *WindowFrame  `parser:"@@? ')'"`
+	}
+
 	// SelectOrderByClause represents ORDER BY clause in SELECT statements
 	SelectOrderByClause struct {
 		OrderBy     string             `parser:"'ORDER' 'BY'"`
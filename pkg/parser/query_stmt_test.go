@@ -74,6 +74,8 @@ func TestSelectGroupBy(t *testing.T) {
 		{name: "with_cube", sql: `SELECT category, count(*) FROM products GROUP BY category WITH CUBE;`},
 		{name: "with_rollup", sql: `SELECT category, count(*) FROM products GROUP BY category WITH ROLLUP;`},
 		{name: "with_totals", sql: `SELECT category, count(*) FROM products GROUP BY category WITH TOTALS;`},
+		{name: "with_rollup_totals", sql: `SELECT category, brand, count(*) FROM products GROUP BY category, brand WITH ROLLUP WITH TOTALS;`},
+		{name: "all_with_cube", sql: `SELECT category, brand, count(*) FROM products GROUP BY ALL WITH CUBE;`},
 		{name: "all", sql: `SELECT domain, browser, count(*) AS total FROM events WHERE date >= '2024-01-01' GROUP BY ALL;`},
 		{name: "having", sql: `SELECT category, count(*) FROM products GROUP BY category HAVING count(*) > 10;`},
 	}
@@ -125,6 +127,8 @@ func TestSelectWindow(t *testing.T) {
 	tests := []statementTest{
 		{name: "rank", sql: `SELECT name, salary, rank() OVER (ORDER BY salary DESC) AS salary_rank FROM employees;`},
 		{name: "partition", sql: `SELECT name, rank() OVER (PARTITION BY department ORDER BY salary DESC) FROM employees;`},
+		{name: "named_window", sql: `SELECT name, rank() OVER w AS salary_rank, sum(salary) OVER w AS running_total FROM employees WINDOW w AS (PARTITION BY department ORDER BY salary DESC);`},
+		{name: "multiple_named_windows", sql: "SELECT name, sum(salary) OVER dept AS total, avg(salary) OVER `running` AS running_avg FROM employees WINDOW dept AS (PARTITION BY department), `running` AS (ORDER BY hired_at ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) ORDER BY name;"},
 	}
 
 	runStatementTests(t, "query/window", tests)
@@ -196,9 +196,10 @@ func TestSemicolonRequirement(t *testing.T) {
 		shouldWork  bool
 		description string
 	}{
-		// Top-level SELECT statements must have semicolons
+		// Top-level SELECT statements must have semicolons, except for the final statement
 		{"top_level_with_semicolon", "SELECT 1;", true, "Top-level SELECT requires semicolon"},
-		{"top_level_without_semicolon", "SELECT 1", false, "Top-level SELECT without semicolon should fail"},
+		{"top_level_without_semicolon", "SELECT 1", true, "Final top-level SELECT may omit its semicolon"},
+		{"top_level_missing_separator", "SELECT 1 SELECT 2;", false, "Top-level SELECTs must be separated by semicolons"},
 
 		// Subqueries must NOT have semicolons
 		{"subquery_in_from", "SELECT * FROM (SELECT id FROM users) AS sub;", true, "Subquery should not need semicolon"},
@@ -0,0 +1,42 @@
+package parser
+
+type (
+	// SystemStmt represents the SYSTEM statements migrations use for operational
+	// follow-ups, such as reloading a dictionary after changing its source. They don't
+	// describe schema, so they are ignored by schema comparison.
+	// ClickHouse syntax:
+	//   SYSTEM RELOAD DICTIONARY [ON CLUSTER cluster] [db.]name
+	//   SYSTEM RELOAD DICTIONARIES [ON CLUSTER cluster]
+	//   SYSTEM RELOAD CONFIG [ON CLUSTER cluster]
+	//   SYSTEM FLUSH LOGS [ON CLUSTER cluster]
+	//   SYSTEM SYNC REPLICA [ON CLUSTER cluster] [db.]table [STRICT | LIGHTWEIGHT | PULL]
+	SystemStmt struct {
+		LeadingCommentField
+		ReloadDictionary   *SystemObjectTarget  `parser:"'SYSTEM' ( 'RELOAD' 'DICTIONARY' @@"`
+		ReloadDictionaries *SystemClusterTarget `parser:"| 'RELOAD' 'DICTIONARIES' @@"`
+		ReloadConfig       *SystemClusterTarget `parser:"| 'RELOAD' 'CONFIG' @@"`
+		FlushLogs          *SystemClusterTarget `parser:"| 'FLUSH' 'LOGS' @@"`
+		SyncReplica        *SyncReplicaTarget   `parser:"| 'SYNC' 'REPLICA' @@ )"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
+	// SystemClusterTarget is the target of SYSTEM operations that apply to the whole server
+	SystemClusterTarget struct {
+		OnCluster *string `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+	}
+
+	// SystemObjectTarget is the target of SYSTEM operations that apply to a single
+	// table or dictionary
+	SystemObjectTarget struct {
+		OnCluster *string `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Database  *string `parser:"(@(Ident | BacktickIdent) '.')?"`
+		Name      string  `parser:"@(Ident | BacktickIdent)"`
+	}
+
+	// SyncReplicaTarget is the target of SYSTEM SYNC REPLICA, with an optional sync mode
+	SyncReplicaTarget struct {
+		SystemObjectTarget
+		Mode *string `parser:"@('STRICT' | 'LIGHTWEIGHT' | 'PULL')?"`
+	}
+)
@@ -0,0 +1,21 @@
+package parser_test
+
+import "testing"
+
+func TestSystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "reload_dictionary", sql: `SYSTEM RELOAD DICTIONARY analytics.users_dict;`},
+		{name: "reload_dictionary_on_cluster", sql: "SYSTEM RELOAD DICTIONARY ON CLUSTER `prod-cluster` users_dict;"},
+		{name: "reload_dictionaries", sql: `system reload dictionaries on cluster production;`},
+		{name: "reload_config", sql: `SYSTEM RELOAD CONFIG;`},
+		{name: "reload_config_on_cluster", sql: "SYSTEM RELOAD CONFIG ON CLUSTER `prod-cluster`;"},
+		{name: "flush_logs", sql: `SYSTEM FLUSH LOGS ON CLUSTER production;`},
+		{name: "sync_replica", sql: `SYSTEM SYNC REPLICA analytics.events;`},
+		{name: "sync_replica_on_cluster", sql: `SYSTEM SYNC REPLICA ON CLUSTER production events;`},
+		{name: "sync_replica_mode", sql: `SYSTEM SYNC REPLICA ON CLUSTER production analytics.events lightweight;`},
+	}
+
+	runStatementTests(t, "system", tests)
+}
@@ -71,7 +71,8 @@ type (
 	}
 
 	// TableClause represents any clause that can appear after ENGINE in a CREATE TABLE statement
-	// This allows clauses to be specified in any order
+	// This allows clauses to be specified in any order. A table COMMENT followed by further
+	// clauses is captured here and moved to CreateTableStmt.Comment after parsing.
 	TableClause struct {
 		LeadingComments  []string             `parser:"@(Comment | MultilineComment)*"`
 		OrderBy          *OrderByClause       `parser:"@@"`
@@ -80,6 +81,7 @@ type (
 		SampleBy         *SampleByClause      `parser:"| @@"`
 		TTL              *TableTTLClause      `parser:"| @@"`
 		Settings         *TableSettingsClause `parser:"| @@"`
+		Comment          *string              `parser:"| ('COMMENT' @String (?= (Comment | MultilineComment)* ('ORDER' | 'PARTITION' | 'PRIMARY' | 'SAMPLE' | 'TTL' | 'SETTINGS')))"`
 		TrailingComments []string             `parser:"@(Comment | MultilineComment)*"`
 	}
 
@@ -228,24 +230,42 @@ type (
 		Settings []TableSetting `parser:"'SETTINGS' @@ (',' @@)*"`
 	}
 
-	// TableSetting represents a single setting in SETTINGS clause
+	// TableSetting represents a single setting in SETTINGS clause.
+	// Scalar values are stored in Value, while array, map, and tuple values
+	// (e.g. additional_table_filters = {'t': 'x > 1'}) are stored in Compound.
 	TableSetting struct {
-		Name  string `parser:"@(Ident | BacktickIdent)"`
-		Eq    string `parser:"'='"`
-		Value string `parser:"@(String | Number | Ident | BacktickIdent)"`
+		Name     string                `parser:"@(Ident | BacktickIdent)"`
+		Eq       string                `parser:"'='"`
+		Value    string                `parser:"( @(String | Number | Ident | BacktickIdent)"`
+		Compound *SettingCompoundValue `parser:"| @@ )"`
+	}
+
+	// SettingCompoundValue represents an array, map, or tuple setting value
+	SettingCompoundValue struct {
+		Array *ArrayExpression `parser:"@@"`
+		Map   *MapExpression   `parser:"| @@"`
+		Tuple *TupleExpression `parser:"| @@"`
 	}
 
 	// AttachTableStmt represents an ATTACH TABLE statement.
 	// Used for materialized views: ATTACH TABLE [db.]materialized_view_name
+	// A detached table can also be attached with its full definition, which follows the
+	// same layout as CREATE TABLE.
 	// ClickHouse syntax:
 	//   ATTACH TABLE [IF NOT EXISTS] [db.]table_name [ON CLUSTER cluster]
+	//   [(column1 Type1, ...) ENGINE = engine_name([parameters]) [ORDER BY ...] [SETTINGS ...] [COMMENT 'comment']]
 	AttachTableStmt struct {
 		LeadingCommentField
-		Attach      string  `parser:"'ATTACH' 'TABLE'"`
-		IfNotExists bool    `parser:"('IF' 'NOT' 'EXISTS')?"`
-		Database    *string `parser:"(@(Ident | BacktickIdent) '.')?"`
-		Name        string  `parser:"@(Ident | BacktickIdent)"`
-		OnCluster   *string `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Attach            string         `parser:"'ATTACH' 'TABLE'"`
+		IfNotExists       bool           `parser:"@('IF' 'NOT' 'EXISTS')?"`
+		Database          *string        `parser:"(@(Ident | BacktickIdent) '.')?"`
+		Name              string         `parser:"@(Ident | BacktickIdent)"`
+		OnCluster         *string        `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Elements          []TableElement `parser:"('(' @@ (',' @@)* ')')?"`
+		PreEngineComments []string       `parser:"@(Comment | MultilineComment)*"`
+		Engine            *TableEngine   `parser:"@@?"`
+		Clauses           []TableClause  `parser:"@@*"`
+		Comment           *string        `parser:"('COMMENT' @String)?"`
 		TrailingCommentField
 		Semicolon bool `parser:"';'"`
 	}
@@ -283,6 +303,22 @@ type (
 		Semicolon bool `parser:"';'"`
 	}
 
+	// TruncateTableStmt represents a TRUNCATE TABLE statement, which removes all data
+	// from a table. It is a data operation and is ignored by schema comparison.
+	// ClickHouse syntax:
+	//   TRUNCATE [TABLE] [IF EXISTS] [db.]table_name [ON CLUSTER cluster] [SYNC]
+	TruncateTableStmt struct {
+		LeadingCommentField
+		Truncate  string  `parser:"'TRUNCATE' 'TABLE'?"`
+		IfExists  bool    `parser:"@('IF' 'EXISTS')?"`
+		Database  *string `parser:"(@(Ident | BacktickIdent) '.')?"`
+		Name      string  `parser:"@(Ident | BacktickIdent)"`
+		OnCluster *string `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Sync      bool    `parser:"@'SYNC'?"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
 	// RenameTableStmt represents a RENAME TABLE statement.
 	// Used for both regular views and materialized views.
 	// ClickHouse syntax:
@@ -305,6 +341,23 @@ type (
 		ToName       string  `parser:"@(Ident | BacktickIdent)"`
 	}
 
+	// ExchangeTablesStmt represents an EXCHANGE TABLES statement, which atomically swaps
+	// the names of two tables (e.g. for blue/green deployments).
+	// ClickHouse syntax:
+	//   EXCHANGE TABLES [db.]table1 AND [db.]table2 [ON CLUSTER cluster]
+	ExchangeTablesStmt struct {
+		LeadingCommentField
+		Exchange       string  `parser:"'EXCHANGE' 'TABLES'"`
+		FirstDatabase  *string `parser:"(@(Ident | BacktickIdent) '.')?"`
+		FirstName      string  `parser:"@(Ident | BacktickIdent)"`
+		And            string  `parser:"'AND'"`
+		SecondDatabase *string `parser:"(@(Ident | BacktickIdent) '.')?"`
+		SecondName     string  `parser:"@(Ident | BacktickIdent)"`
+		OnCluster      *string `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
 	// AlterTableStmt represents an ALTER TABLE statement.
 	// ClickHouse syntax:
 	//   ALTER TABLE [IF EXISTS] [db.]table [ON CLUSTER cluster]
@@ -339,9 +392,11 @@ type (
 		ModifyColumn     *ModifyColumnOperation     `parser:"| @@"`
 		RenameColumn     *RenameColumnOperation     `parser:"| @@"`
 		CommentColumn    *CommentColumnOperation    `parser:"| @@"`
+		ModifyComment    *ModifyCommentOperation    `parser:"| @@"`
 		ClearColumn      *ClearColumnOperation      `parser:"| @@"`
 		ModifyTTL        *ModifyTTLOperation        `parser:"| @@"`
 		DeleteTTL        *DeleteTTLOperation        `parser:"| @@"`
+		RemoveTTL        *RemoveTTLOperation        `parser:"| @@"`
 		AddIndex         *AddIndexOperation         `parser:"| @@"`
 		DropIndex        *DropIndexOperation        `parser:"| @@"`
 		AddConstraint    *AddConstraintOperation    `parser:"| @@"`
@@ -380,17 +435,21 @@ type (
 		Name     string `parser:"@(Ident | BacktickIdent)"`
 	}
 
-	// ModifyColumnOperation represents MODIFY COLUMN operation
+	// ModifyColumnOperation represents MODIFY COLUMN operation.
+	// ClickHouse accepts COMMENT either right after the default expression or after CODEC
+	// and TTL. The former is captured in CommentBeforeCodec and moved to Comment after parsing.
 	ModifyColumnOperation struct {
-		Modify   string              `parser:"'MODIFY' 'COLUMN'"`
-		IfExists bool                `parser:"@('IF' 'EXISTS')?"`
-		Name     string              `parser:"@(Ident | BacktickIdent)"`
-		Type     *DataType           `parser:"@@?"`
-		Default  *DefaultClause      `parser:"@@?"`
-		Codec    *CodecClause        `parser:"@@?"`
-		TTL      *Expression         `parser:"('TTL' @@)?"`
-		Comment  *string             `parser:"('COMMENT' @String)?"`
-		Remove   *ModifyColumnRemove `parser:"@@?"`
+		Modify             string                `parser:"'MODIFY' 'COLUMN'"`
+		IfExists           bool                  `parser:"@('IF' 'EXISTS')?"`
+		Name               string                `parser:"@(Ident | BacktickIdent)"`
+		Type               *DataType             `parser:"((?! 'DEFAULT' | 'MATERIALIZED' | 'EPHEMERAL' | 'ALIAS' | 'REMOVE') @@)?"`
+		Default            *DefaultClause        `parser:"@@?"`
+		CommentBeforeCodec *string               `parser:"('COMMENT' @String)?"`
+		Codec              *CodecClause          `parser:"@@?"`
+		TTL                *Expression           `parser:"('TTL' @@)?"`
+		Comment            *string               `parser:"('COMMENT' @String)?"`
+		Settings           *ColumnSettingsClause `parser:"@@?"`
+		Remove             *ModifyColumnRemove   `parser:"@@?"`
 	}
 
 	// ModifyColumnRemove represents REMOVE clause in MODIFY COLUMN
@@ -409,10 +468,16 @@ type (
 
 	// CommentColumnOperation represents COMMENT COLUMN operation
 	CommentColumnOperation struct {
-		Comment  string `parser:"'COMMENT' 'COLUMN'"`
-		IfExists bool   `parser:"@('IF' 'EXISTS')?"`
+		IfExists bool   `parser:"'COMMENT' 'COLUMN' @('IF' 'EXISTS')?"`
 		Name     string `parser:"@(Ident | BacktickIdent)"`
-		Value    string `parser:"@String"`
+		Comment  string `parser:"@String"`
+	}
+
+	// ModifyCommentOperation represents MODIFY COMMENT operation, which changes the
+	// table's own comment rather than a column comment
+	ModifyCommentOperation struct {
+		Modify  string `parser:"'MODIFY' 'COMMENT'"`
+		Comment string `parser:"@String"`
 	}
 
 	// ClearColumnOperation represents CLEAR COLUMN operation
@@ -442,14 +507,20 @@ type (
 		Delete string `parser:"'DELETE' 'TTL'"`
 	}
 
+	// RemoveTTLOperation represents REMOVE TTL operation
+	RemoveTTLOperation struct {
+		Remove string `parser:"'REMOVE' 'TTL'"`
+	}
+
 	// AddIndexOperation represents ADD INDEX operation
 	AddIndexOperation struct {
 		Add         string     `parser:"'ADD' 'INDEX'"`
 		IfNotExists bool       `parser:"@('IF' 'NOT' 'EXISTS')?"`
 		Name        string     `parser:"@(Ident | BacktickIdent)"`
 		Expression  Expression `parser:"@@"`
-		Type        string     `parser:"'TYPE' @(Ident | BacktickIdent)"`
-		Granularity string     `parser:"'GRANULARITY' @Number"`
+		Type        string     `parser:"'TYPE'"`
+		IndexType   IndexType  `parser:"@@"`
+		Granularity string     `parser:"('GRANULARITY' @Number)?"`
 		After       *string    `parser:"('AFTER' @(Ident | BacktickIdent))?"`
 		First       bool       `parser:"@'FIRST'?"`
 	}
@@ -659,6 +730,29 @@ func (c *CreateTableStmt) GetTTL() *TableTTLClause {
 	return nil
 }
 
+// GetValue returns the setting value as SQL text, rendering compound values
+// (arrays, maps, and tuples) in their canonical form.
+func (s *TableSetting) GetValue() string {
+	if s.Compound != nil {
+		return s.Compound.String()
+	}
+	return s.Value
+}
+
+// String returns the SQL representation of the compound setting value
+func (v *SettingCompoundValue) String() string {
+	switch {
+	case v.Array != nil:
+		return v.Array.String()
+	case v.Map != nil:
+		return v.Map.String()
+	case v.Tuple != nil:
+		return v.Tuple.String()
+	default:
+		return ""
+	}
+}
+
 // GetSettings returns the SETTINGS clause if present
 func (c *CreateTableStmt) GetSettings() *TableSettingsClause {
 	for _, clause := range c.Clauses {
@@ -701,6 +795,29 @@ func (e *TableEngine) String() string {
 	return e.Name + "(" + strings.Join(params, ", ") + ")"
 }
 
+// String returns the SQL representation of an index type, e.g. tokenbf_v1(512, 3, 0).
+func (t *IndexType) String() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.BloomFilter:
+		return "bloom_filter"
+	case t.MinMax:
+		return "minmax"
+	case t.Hypothesis:
+		return "hypothesis"
+	case t.Set != nil:
+		return "set(" + t.Set.MaxRows + ")"
+	case t.TokenBF != nil:
+		return "tokenbf_v1(" + strings.Join([]string{t.TokenBF.Size, t.TokenBF.Hashes, t.TokenBF.Seed}, ", ") + ")"
+	case t.NGramBF != nil:
+		return "ngrambf_v1(" + strings.Join([]string{t.NGramBF.N, t.NGramBF.Size, t.NGramBF.Hashes, t.NGramBF.Seed}, ", ") + ")"
+	case t.Custom != nil:
+		return *t.Custom
+	}
+	return ""
+}
+
 // Equal compares two OrderByClause instances for equality
 func (o *OrderByClause) Equal(other *OrderByClause) bool {
 	if eq, done := compare.NilCheck(o, other); !done {
@@ -741,7 +858,8 @@ func (t *TableTTLClause) Equal(other *TableTTLClause) bool {
 	return t.Expression.Equal(&other.Expression)
 }
 
-// Equal compares two TableEngine instances for equality
+// Equal compares two TableEngine instances for equality. Engine names are
+// compared case-sensitively since ClickHouse only accepts their canonical casing.
 func (t *TableEngine) Equal(other *TableEngine) bool {
 	if eq, done := compare.NilCheck(t, other); !done {
 		return eq
@@ -1,6 +1,15 @@
 package parser_test
 
-import "testing"
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	. "github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/stretchr/testify/require"
+)
 
 func TestCreateTable(t *testing.T) {
 	t.Parallel()
@@ -32,6 +41,14 @@ func TestCreateTable(t *testing.T) {
 			config_data String CODEC(LZ4HC(9))
 		) ENGINE = MergeTree() ORDER BY (device_id, created_at);`},
 
+		// Nothing types
+		{name: "nothing_types", sql: `CREATE TABLE placeholders (
+			id UInt64,
+			always_null Nullable(Nothing),
+			empty_list Array(Nothing),
+			null_list Array(Nullable(Nothing))
+		) ENGINE = MergeTree() ORDER BY id;`},
+
 		// Full options
 		{name: "full_options", sql: `CREATE OR REPLACE TABLE IF NOT EXISTS analytics.events ON CLUSTER production (
 			id UInt64,
@@ -54,6 +71,7 @@ func TestCreateTable(t *testing.T) {
 		COMMENT 'User events table';`},
 
 		// Backticks
+		{name: "order_by_function_tuple", sql: "CREATE TABLE events (date Date, id UInt64, user_id UInt64) ENGINE = MergeTree() PARTITION BY toYYYYMM(`date`) ORDER BY (toYYYYMM(`date`), id, intHash32(user_id)) SAMPLE BY intHash32(user_id);"},
 		{name: "with_backticks", sql: "CREATE TABLE `user-db`.`order-table` (`user-id` UInt64, `order-id` String, `order-date` Date, `select` String, `group` LowCardinality(String)) ENGINE = MergeTree() ORDER BY (`user-id`, `order-date`);"},
 
 		// Indexes
@@ -100,6 +118,18 @@ func TestCreateTable(t *testing.T) {
 			users AggregateFunction(uniq, UUID)
 		) ENGINE = Distributed('datawarehouse', 'sessions', 'web_vital_events_by_hour_local', rand());`},
 
+		// Omitted types (inferred from the default expression)
+		{name: "omitted_types", sql: `CREATE TABLE events (
+			id UInt64,
+			created_at DEFAULT now(),
+			source MATERIALIZED 'web',
+			bucket ALIAS id % 10 COMMENT 'Derived bucket'
+		) ENGINE = MergeTree() ORDER BY id;`},
+
+		// Compound setting values
+		{name: "compound_settings", sql: `CREATE TABLE filtered (id UInt64, tenant String) ENGINE = MergeTree() ORDER BY id
+			SETTINGS index_granularity = 8192, allowed_disks = ['hot', 'cold'], additional_table_filters = {'filtered': 'tenant = \'acme\'', 'other': 'id > 10'};`},
+
 		// CREATE TABLE AS
 		{name: "as_basic", sql: `CREATE TABLE copy AS source ENGINE = MergeTree() ORDER BY id;`},
 		{name: "as_with_database", sql: `CREATE TABLE db1.table_copy AS db2.source_table ENGINE = Memory;`},
@@ -112,6 +142,29 @@ func TestCreateTable(t *testing.T) {
 		{name: "as_s3", sql: `CREATE TABLE s3_import AS s3Table('https://bucket.s3.amazonaws.com/data.csv', 'CSV') ENGINE = MergeTree() ORDER BY tuple();`},
 		{name: "as_numbers", sql: `CREATE TABLE test_numbers AS numbers(1000000) ENGINE = Memory();`},
 		{name: "as_postgresql", sql: `CREATE TABLE pg_import AS postgresql('host:5432', 'database', 'table', 'user', 'password') ENGINE = MergeTree() ORDER BY id;`},
+
+		// Per-column settings
+		{name: "column_settings", sql: `CREATE TABLE blobs (
+			id UInt64,
+			payload String CODEC(ZSTD(3)) COMMENT 'Raw payload' SETTINGS (max_compress_block_size = 1048576, min_compress_block_size = 65536),
+			small String SETTINGS (max_compress_block_size = 4096)
+		) ENGINE = MergeTree() ORDER BY id SETTINGS index_granularity = 8192;`},
+
+		// EPHEMERAL columns, with and without a default expression
+		{name: "ephemeral_columns", sql: `CREATE TABLE hex_values (
+			id UInt64,
+			unhexed String EPHEMERAL,
+			flag UInt8 EPHEMERAL 0,
+			note String EPHEMERAL COMMENT 'Input only',
+			hexed FixedString(4) DEFAULT unhex(unhexed)
+		) ENGINE = MergeTree() ORDER BY id;`},
+
+		// COMMENT in the positions ClickHouse accepts
+		{name: "comment_positions", sql: `CREATE TABLE analytics.sessions (
+			id UInt64 COMMENT 'Session ID' CODEC(Delta, ZSTD(1)),
+			token String CODEC(ZSTD(3)) COMMENT 'Opaque token',
+			started_at DateTime DEFAULT now() COMMENT 'Start time' TTL started_at + INTERVAL 30 DAY
+		) ENGINE = MergeTree() ORDER BY id COMMENT 'User sessions' SETTINGS index_granularity = 8192;`},
 	}
 
 	runStatementTests(t, "table/create", tests)
@@ -126,16 +179,25 @@ func TestAlterTable(t *testing.T) {
 		{name: "add_column_if_not_exists", sql: `ALTER TABLE users ADD COLUMN IF NOT EXISTS age UInt8;`},
 		{name: "add_column_after", sql: `ALTER TABLE users ADD COLUMN middle_name String AFTER first_name;`},
 		{name: "add_column_first", sql: `ALTER TABLE users ADD COLUMN id UInt64 FIRST;`},
+		{name: "add_column_ephemeral_after", sql: `ALTER TABLE hex_values ADD COLUMN raw String EPHEMERAL AFTER flag;`},
 		{name: "drop_column", sql: `ALTER TABLE user_profiles DROP COLUMN computed_field;`},
 		{name: "drop_column_if_exists", sql: `ALTER TABLE users DROP COLUMN IF EXISTS non_existent_column;`},
 		{name: "rename_column", sql: `ALTER TABLE measurements RENAME COLUMN device_id TO device_identifier;`},
 		{name: "comment_column", sql: `ALTER TABLE users COMMENT COLUMN email 'User email address';`},
+		{name: "modify_comment", sql: `ALTER TABLE analytics.events MODIFY COMMENT 'Raw event stream';`},
+		{name: "modify_comment_on_cluster", sql: `ALTER TABLE analytics.events ON CLUSTER prod MODIFY COMMENT 'Raw event stream';`},
 		{name: "modify_column", sql: `ALTER TABLE users MODIFY COLUMN name String;`},
+		{name: "modify_column_comment", sql: `ALTER TABLE users MODIFY COLUMN name String COMMENT 'Display name';`},
+		{name: "modify_column_settings", sql: `ALTER TABLE blobs MODIFY COLUMN payload String CODEC(ZSTD(3)) SETTINGS (max_compress_block_size = 1048576);`},
 		{name: "modify_column_codec", sql: `ALTER TABLE events MODIFY COLUMN timestamp DateTime64(3, UTC) CODEC(DoubleDelta);`},
+		{name: "modify_column_ephemeral", sql: `ALTER TABLE hex_values MODIFY COLUMN unhexed String EPHEMERAL;`},
+		{name: "modify_column_comment_before_codec", sql: `ALTER TABLE users MODIFY COLUMN name String DEFAULT '' COMMENT 'Display name' CODEC(ZSTD(1));`},
 
 		// Index operations
 		{name: "add_index", sql: `ALTER TABLE logs ADD INDEX level_idx level TYPE minmax GRANULARITY 1;`},
 		{name: "add_index_complex", sql: `ALTER TABLE events ADD INDEX user_date_idx (user_id, toDate(timestamp)) TYPE minmax GRANULARITY 1;`},
+		{name: "add_index_parametric_type", sql: `ALTER TABLE search_logs ADD INDEX IF NOT EXISTS query_tokens query TYPE tokenbf_v1(512, 3, 0) GRANULARITY 2;`},
+		{name: "add_index_default_granularity", sql: `ALTER TABLE search_logs ADD INDEX category_set category TYPE set(100);`},
 		{name: "drop_index", sql: `ALTER TABLE logs DROP INDEX level_idx;`},
 
 		// Constraint operations
@@ -149,14 +211,17 @@ func TestAlterTable(t *testing.T) {
 		// TTL operations
 		{name: "modify_ttl", sql: `ALTER TABLE analytics.events MODIFY TTL timestamp + days(30);`},
 		{name: "delete_ttl", sql: `ALTER TABLE analytics.events DELETE TTL;`},
+		{name: "remove_ttl", sql: `ALTER TABLE analytics.events REMOVE TTL;`},
 
 		// Structure operations
+		{name: "modify_order_by_function_tuple", sql: `ALTER TABLE events MODIFY ORDER BY (toStartOfInterval(timestamp, INTERVAL 1 HOUR), id);`},
 		{name: "modify_order_by", sql: `ALTER TABLE measurements MODIFY ORDER BY (device_identifier, created_at, id);`},
 		{name: "modify_sample_by", sql: `ALTER TABLE analytics.events MODIFY SAMPLE BY user_id;`},
 		{name: "remove_sample_by", sql: `ALTER TABLE analytics.events REMOVE SAMPLE BY;`},
 
 		// Settings operations
 		{name: "modify_setting", sql: `ALTER TABLE analytics.events MODIFY SETTING index_granularity = 16384;`},
+		{name: "modify_setting_map", sql: `ALTER TABLE analytics.events MODIFY SETTING additional_table_filters = {'events': 'id > 0'};`},
 		{name: "reset_setting", sql: `ALTER TABLE analytics.events RESET SETTING index_granularity;`},
 
 		// Partition operations
@@ -194,9 +259,41 @@ func TestAttachTable(t *testing.T) {
 		{name: "if_not_exists", sql: `ATTACH TABLE IF NOT EXISTS temp_table;`},
 		{name: "on_cluster", sql: `ATTACH TABLE measurements ON CLUSTER production;`},
 		{name: "full_options", sql: `ATTACH TABLE IF NOT EXISTS analytics.old_events ON CLUSTER production;`},
+
+		// Attaching with the full table definition
+		{name: "with_definition", sql: `ATTACH TABLE analytics.events (
+			id UInt64,
+			name String DEFAULT 'unknown' COMMENT 'Event name',
+			ts DateTime CODEC(Delta, ZSTD),
+			INDEX name_bloom name TYPE bloom_filter GRANULARITY 4
+		) ENGINE = MergeTree() PARTITION BY toYYYYMM(ts) ORDER BY (id, ts) SETTINGS index_granularity = 8192 COMMENT 'Events';`},
+		{name: "with_definition_on_cluster", sql: `ATTACH TABLE IF NOT EXISTS analytics.events_local ON CLUSTER production (
+			id UInt64,
+			ts DateTime
+		)
+		-- replicated across the cluster
+		ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events_local', '{replica}') ORDER BY id;`},
+		{name: "with_definition_engine_only", sql: `ATTACH TABLE logs.raw (message String) ENGINE = Log;`},
 	}
 
 	runStatementTests(t, "table/attach", tests)
+
+	// Formatted ATTACH statements must parse back to the same output
+	for _, tt := range tests {
+		t.Run(tt.name+"/stable", func(t *testing.T) {
+			t.Parallel()
+
+			formatted, err := os.ReadFile(filepath.Join("testdata", "table", "attach", tt.name+".sql"))
+			require.NoError(t, err)
+
+			parsed, err := ParseString(string(formatted))
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, format.Format(&buf, format.Defaults, parsed.Statements...))
+			require.Equal(t, string(formatted), buf.String()+"\n")
+		})
+	}
 }
 
 func TestDetachTable(t *testing.T) {
@@ -242,3 +339,30 @@ func TestRenameTable(t *testing.T) {
 
 	runStatementTests(t, "table/rename", tests)
 }
+
+func TestExchangeTables(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "simple", sql: `EXCHANGE TABLES events AND events_new;`},
+		{name: "with_database", sql: `EXCHANGE TABLES analytics.events AND staging.events;`},
+		{name: "on_cluster", sql: `exchange tables analytics.events and analytics.events_v2 on cluster production;`},
+		{name: "with_backticks", sql: "EXCHANGE TABLES `blue-events` AND `green-events` ON CLUSTER `prod-cluster`;"},
+	}
+
+	runStatementTests(t, "table/exchange", tests)
+}
+
+func TestTruncateTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "simple", sql: `TRUNCATE TABLE events;`},
+		{name: "without_table_keyword", sql: `TRUNCATE analytics.events;`},
+		{name: "if_exists", sql: `TRUNCATE TABLE IF EXISTS analytics.events;`},
+		{name: "on_cluster", sql: `truncate table analytics.events on cluster production sync;`},
+		{name: "backticked_cluster", sql: "TRUNCATE TABLE IF EXISTS `user-events` ON CLUSTER `prod-cluster`;"},
+	}
+
+	runStatementTests(t, "table/truncate", tests)
+}
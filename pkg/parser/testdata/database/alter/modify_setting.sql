@@ -0,0 +1 @@
+ALTER DATABASE `replicated_db` ON CLUSTER `production` MODIFY SETTING max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;
@@ -0,0 +1 @@
+CREATE DATABASE `replicated_db` ON CLUSTER `production` ENGINE = Replicated('/clickhouse/databases/replicated_db', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5, collection_name = 'replicated' COMMENT 'Replicated database';
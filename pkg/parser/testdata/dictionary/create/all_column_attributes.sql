@@ -0,0 +1,11 @@
+CREATE DICTIONARY `analytics`.`regions` (
+    `id`         UInt64 IS_OBJECT_ID,
+    `parent_id`  UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL,
+    `code`       String INJECTIVE,
+    `label`      String EXPRESSION upper(code) INJECTIVE IS_OBJECT_ID,
+    `manager_id` UInt64 BIDIRECTIONAL HIERARCHICAL
+)
+PRIMARY KEY `id`
+SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow'))
+LAYOUT(HASHED())
+LIFETIME(3600);
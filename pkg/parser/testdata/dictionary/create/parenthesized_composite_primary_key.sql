@@ -0,0 +1,9 @@
+CREATE DICTIONARY `user_mapping` (
+    `user_id`  UInt64,
+    `group_id` UInt32,
+    `name`     String
+)
+PRIMARY KEY `user_id`, `group_id`
+SOURCE(HTTP(url 'http://localhost/mapping.json' format 'JSONEachRow'))
+LAYOUT(COMPLEX_KEY_HASHED())
+LIFETIME(3600);
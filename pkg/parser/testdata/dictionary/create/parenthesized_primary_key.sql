@@ -0,0 +1,8 @@
+CREATE DICTIONARY `users_dict` (
+    `id`   UInt64,
+    `name` String
+)
+PRIMARY KEY `id`
+SOURCE(HTTP(url 'http://localhost/users.json' format 'JSONEachRow'))
+LAYOUT(HASHED())
+LIFETIME(3600);
@@ -0,0 +1 @@
+EXCHANGE DICTIONARIES `users-dict` AND `users-dict-v2` ON CLUSTER `prod-cluster`;
@@ -0,0 +1 @@
+EXCHANGE DICTIONARIES `users_dict` AND `users_dict_new`;
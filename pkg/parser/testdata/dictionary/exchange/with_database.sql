@@ -0,0 +1 @@
+EXCHANGE DICTIONARIES `db`.`users_dict` AND `db`.`users_dict_v2`;
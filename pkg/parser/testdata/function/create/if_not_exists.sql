@@ -0,0 +1 @@
+CREATE FUNCTION IF NOT EXISTS `clamp` AS (`value`, `low`, `high`) -> greatest(`low`, least(`value`, `high`));
@@ -0,0 +1 @@
+CREATE FUNCTION `linear_eq` AS (`x`, `k`, `b`) -> `k` * `x` + `b`;
@@ -0,0 +1 @@
+CREATE OR REPLACE FUNCTION `linear_eq` ON CLUSTER `production` AS (`x`, `k`, `b`) -> `k` * `x` + `b`;
@@ -0,0 +1,2 @@
+ALTER NAMED COLLECTION `kafka_config`
+    DELETE `kafka_skip_broken_messages`, `kafka_format`;
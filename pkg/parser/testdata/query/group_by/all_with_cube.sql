@@ -0,0 +1,6 @@
+SELECT
+    `category`,
+    `brand`,
+    count(*)
+FROM `products`
+GROUP BY ALL WITH CUBE;
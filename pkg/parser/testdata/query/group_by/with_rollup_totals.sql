@@ -0,0 +1,6 @@
+SELECT
+    `category`,
+    `brand`,
+    count(*)
+FROM `products`
+GROUP BY `category`, `brand` WITH ROLLUP WITH TOTALS;
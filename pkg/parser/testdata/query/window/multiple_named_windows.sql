@@ -0,0 +1,7 @@
+SELECT
+    `name`,
+    sum(`salary`) OVER `dept` AS `total`,
+    avg(`salary`) OVER `running` AS `running_avg`
+FROM `employees`
+WINDOW `dept` AS (PARTITION BY department), `running` AS (ORDER BY hired_at ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)
+ORDER BY `name`;
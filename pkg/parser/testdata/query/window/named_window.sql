@@ -0,0 +1,6 @@
+SELECT
+    `name`,
+    rank() OVER `w` AS `salary_rank`,
+    sum(`salary`) OVER `w` AS `running_total`
+FROM `employees`
+WINDOW `w` AS (PARTITION BY department ORDER BY salary DESC);
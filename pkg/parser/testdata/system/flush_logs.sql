@@ -0,0 +1 @@
+SYSTEM FLUSH LOGS ON CLUSTER `production`;
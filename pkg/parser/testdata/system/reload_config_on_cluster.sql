@@ -0,0 +1 @@
+SYSTEM RELOAD CONFIG ON CLUSTER `prod-cluster`;
@@ -0,0 +1 @@
+SYSTEM RELOAD DICTIONARIES ON CLUSTER `production`;
@@ -0,0 +1 @@
+SYSTEM RELOAD DICTIONARY `analytics`.`users_dict`;
@@ -0,0 +1 @@
+SYSTEM RELOAD DICTIONARY ON CLUSTER `prod-cluster` `users_dict`;
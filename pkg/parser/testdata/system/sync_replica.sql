@@ -0,0 +1 @@
+SYSTEM SYNC REPLICA `analytics`.`events`;
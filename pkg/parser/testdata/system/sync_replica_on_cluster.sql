@@ -0,0 +1 @@
+SYSTEM SYNC REPLICA ON CLUSTER `production` `events`;
@@ -0,0 +1,2 @@
+ALTER TABLE `hex_values`
+    ADD COLUMN `raw` String EPHEMERAL AFTER `flag`;
@@ -1,2 +1,2 @@
 ALTER TABLE `logs`
-    ADD INDEX `level_idx` `level` TYPE `minmax` GRANULARITY 1;
+    ADD INDEX `level_idx` `level` TYPE minmax GRANULARITY 1;
@@ -0,0 +1,2 @@
+ALTER TABLE `search_logs`
+    ADD INDEX `category_set` `category` TYPE set(100);
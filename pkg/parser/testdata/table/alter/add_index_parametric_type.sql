@@ -0,0 +1,2 @@
+ALTER TABLE `search_logs`
+    ADD INDEX IF NOT EXISTS `query_tokens` `query` TYPE tokenbf_v1(512, 3, 0) GRANULARITY 2;
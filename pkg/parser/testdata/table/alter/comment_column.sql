@@ -1,2 +1,2 @@
 ALTER TABLE `users`
-    COMMENT COLUMN `email` ;
+    COMMENT COLUMN `email` 'User email address';
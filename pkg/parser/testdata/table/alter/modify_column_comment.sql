@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    MODIFY COLUMN `name` String COMMENT 'Display name';
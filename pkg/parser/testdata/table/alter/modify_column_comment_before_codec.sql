@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    MODIFY COLUMN `name` String DEFAULT '' CODEC(ZSTD(1)) COMMENT 'Display name';
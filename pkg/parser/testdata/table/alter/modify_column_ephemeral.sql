@@ -0,0 +1,2 @@
+ALTER TABLE `hex_values`
+    MODIFY COLUMN `unhexed` String EPHEMERAL;
@@ -0,0 +1,2 @@
+ALTER TABLE `blobs`
+    MODIFY COLUMN `payload` String CODEC(ZSTD(3)) SETTINGS (max_compress_block_size = 1048576);
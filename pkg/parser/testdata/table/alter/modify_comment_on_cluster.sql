@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events` ON CLUSTER `prod`
+    MODIFY COMMENT 'Raw event stream';
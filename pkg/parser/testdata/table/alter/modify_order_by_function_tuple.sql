@@ -0,0 +1,2 @@
+ALTER TABLE `events`
+    MODIFY ORDER BY (toStartOfInterval(`timestamp`, INTERVAL 1 HOUR), `id`);
@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events`
+    MODIFY SETTING `additional_table_filters` = {'events': 'id > 0'};
@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events`
+    REMOVE TTL;
@@ -1 +1 @@
-ATTACH TABLE `analytics`.`old_events` ON CLUSTER `production`;
+ATTACH TABLE IF NOT EXISTS `analytics`.`old_events` ON CLUSTER `production`;
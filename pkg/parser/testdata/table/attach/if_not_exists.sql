@@ -1 +1 @@
-ATTACH TABLE `temp_table`;
+ATTACH TABLE IF NOT EXISTS `temp_table`;
@@ -0,0 +1,11 @@
+ATTACH TABLE `analytics`.`events` (
+    `id`   UInt64,
+    `name` String DEFAULT 'unknown' COMMENT 'Event name',
+    `ts`   DateTime CODEC(Delta, ZSTD),
+    INDEX `name_bloom` `name` TYPE bloom_filter GRANULARITY 4
+)
+ENGINE = MergeTree()
+ORDER BY (`id`, `ts`)
+PARTITION BY toYYYYMM(`ts`)
+SETTINGS index_granularity = 8192
+COMMENT 'Events';
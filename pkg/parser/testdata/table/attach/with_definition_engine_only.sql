@@ -0,0 +1,4 @@
+ATTACH TABLE `logs`.`raw` (
+    `message` String
+)
+ENGINE = Log();
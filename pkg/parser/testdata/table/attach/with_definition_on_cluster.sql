@@ -0,0 +1,7 @@
+ATTACH TABLE IF NOT EXISTS `analytics`.`events_local` ON CLUSTER `production` (
+    `id` UInt64,
+    `ts` DateTime
+)
+-- replicated across the cluster
+ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events_local', '{replica}')
+ORDER BY `id`;
@@ -0,0 +1,8 @@
+CREATE TABLE `blobs` (
+    `id`      UInt64,
+    `payload` String CODEC(ZSTD(3)) COMMENT 'Raw payload' SETTINGS (max_compress_block_size = 1048576, min_compress_block_size = 65536),
+    `small`   String SETTINGS (max_compress_block_size = 4096)
+)
+ENGINE = MergeTree()
+ORDER BY `id`
+SETTINGS index_granularity = 8192;
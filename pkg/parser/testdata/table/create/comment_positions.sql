@@ -0,0 +1,9 @@
+CREATE TABLE `analytics`.`sessions` (
+    `id`         UInt64 CODEC(Delta, ZSTD(1)) COMMENT 'Session ID',
+    `token`      String CODEC(ZSTD(3)) COMMENT 'Opaque token',
+    `started_at` DateTime DEFAULT now() TTL `started_at` + INTERVAL 30 DAY COMMENT 'Start time'
+)
+ENGINE = MergeTree()
+ORDER BY `id`
+SETTINGS index_granularity = 8192
+COMMENT 'User sessions';
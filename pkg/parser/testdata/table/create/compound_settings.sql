@@ -0,0 +1,7 @@
+CREATE TABLE `filtered` (
+    `id`     UInt64,
+    `tenant` String
+)
+ENGINE = MergeTree()
+ORDER BY `id`
+SETTINGS index_granularity = 8192, allowed_disks = ['hot', 'cold'], additional_table_filters = {'filtered': 'tenant = \'acme\'', 'other': 'id > 10'};
@@ -0,0 +1,9 @@
+CREATE TABLE `hex_values` (
+    `id`      UInt64,
+    `unhexed` String EPHEMERAL,
+    `flag`    UInt8 EPHEMERAL 0,
+    `note`    String EPHEMERAL COMMENT 'Input only',
+    `hexed`   FixedString(4) DEFAULT unhex(`unhexed`)
+)
+ENGINE = MergeTree()
+ORDER BY `id`;
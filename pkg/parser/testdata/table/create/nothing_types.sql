@@ -0,0 +1,8 @@
+CREATE TABLE `placeholders` (
+    `id`          UInt64,
+    `always_null` Nullable(Nothing),
+    `empty_list`  Array(Nothing),
+    `null_list`   Array(Nullable(Nothing))
+)
+ENGINE = MergeTree()
+ORDER BY `id`;
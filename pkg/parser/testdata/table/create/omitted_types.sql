@@ -0,0 +1,8 @@
+CREATE TABLE `events` (
+    `id`         UInt64,
+    `created_at` DEFAULT now(),
+    `source`     MATERIALIZED 'web',
+    `bucket`     ALIAS `id` % 10 COMMENT 'Derived bucket'
+)
+ENGINE = MergeTree()
+ORDER BY `id`;
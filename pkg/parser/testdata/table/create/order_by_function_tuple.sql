@@ -0,0 +1,9 @@
+CREATE TABLE `events` (
+    `date`    Date,
+    `id`      UInt64,
+    `user_id` UInt64
+)
+ENGINE = MergeTree()
+ORDER BY (toYYYYMM(`date`), `id`, intHash32(`user_id`))
+PARTITION BY toYYYYMM(`date`)
+SAMPLE BY intHash32(`user_id`);
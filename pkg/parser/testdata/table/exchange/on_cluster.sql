@@ -0,0 +1 @@
+EXCHANGE TABLES `analytics`.`events` AND `analytics`.`events_v2` ON CLUSTER `production`;
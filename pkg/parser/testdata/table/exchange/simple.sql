@@ -0,0 +1 @@
+EXCHANGE TABLES `events` AND `events_new`;
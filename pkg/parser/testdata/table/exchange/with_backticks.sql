@@ -0,0 +1 @@
+EXCHANGE TABLES `blue-events` AND `green-events` ON CLUSTER `prod-cluster`;
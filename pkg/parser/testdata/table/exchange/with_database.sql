@@ -0,0 +1 @@
+EXCHANGE TABLES `analytics`.`events` AND `staging`.`events`;
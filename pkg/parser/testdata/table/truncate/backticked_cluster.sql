@@ -0,0 +1 @@
+TRUNCATE TABLE IF EXISTS `user-events` ON CLUSTER `prod-cluster`;
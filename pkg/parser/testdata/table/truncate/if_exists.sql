@@ -0,0 +1 @@
+TRUNCATE TABLE IF EXISTS `analytics`.`events`;
@@ -0,0 +1 @@
+TRUNCATE TABLE `events`;
@@ -0,0 +1 @@
+TRUNCATE TABLE `analytics`.`events`;
@@ -0,0 +1 @@
+ALTER USER `alice` DEFAULT ROLE ALL;
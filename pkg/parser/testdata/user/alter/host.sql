@@ -0,0 +1 @@
+ALTER USER `alice` HOST IP '192.168.0.0/16', LOCAL;
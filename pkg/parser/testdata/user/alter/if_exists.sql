@@ -0,0 +1 @@
+ALTER USER IF EXISTS `bob` RENAME TO `robert`;
@@ -0,0 +1 @@
+ALTER USER `carol` ON CLUSTER `production` NOT IDENTIFIED;
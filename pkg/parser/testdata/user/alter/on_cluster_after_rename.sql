@@ -0,0 +1 @@
+ALTER USER `alice` ON CLUSTER `production` RENAME TO `alicia`;
@@ -0,0 +1 @@
+ALTER USER IF EXISTS `etl` ON CLUSTER `staging` RENAME TO `loader` IDENTIFIED WITH ldap SERVER 'corp_ldap' HOST ANY DEFAULT ROLE ALL EXCEPT `admin` SETTINGS `max_threads` = 4;
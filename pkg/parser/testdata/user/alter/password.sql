@@ -0,0 +1 @@
+ALTER USER `alice` IDENTIFIED WITH sha256_password BY 'new_secret';
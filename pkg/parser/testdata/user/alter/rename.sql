@@ -0,0 +1 @@
+ALTER USER `alice` RENAME TO `alicia`;
@@ -0,0 +1 @@
+ALTER USER `alice` SETTINGS `max_memory_usage` = 5000000000;
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH bcrypt_password BY 'secret';
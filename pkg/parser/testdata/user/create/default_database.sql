@@ -0,0 +1 @@
+CREATE USER `alice` DEFAULT DATABASE `analytics`;
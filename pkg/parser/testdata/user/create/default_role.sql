@@ -0,0 +1 @@
+CREATE USER `alice` DEFAULT ROLE `analyst`, `reader`;
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH double_sha1_password BY 'secret';
@@ -0,0 +1 @@
+CREATE USER `alice` HOST IP '10.0.0.0/8';
@@ -0,0 +1 @@
+CREATE USER `alice` HOST LOCAL, NAME 'db.example.com', REGEXP '.*\\.example\\.com', LIKE '%.internal';
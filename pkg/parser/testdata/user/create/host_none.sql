@@ -0,0 +1 @@
+CREATE USER `alice` HOST NONE;
@@ -0,0 +1 @@
+CREATE USER IF NOT EXISTS `bob`;
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH kerberos REALM 'EXAMPLE.COM';
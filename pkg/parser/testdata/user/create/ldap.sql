@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH ldap SERVER 'corp_ldap';
@@ -0,0 +1 @@
+CREATE USER `alice`, `bob` ON CLUSTER `production` IDENTIFIED WITH sha256_password BY 'secret';
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH no_password;
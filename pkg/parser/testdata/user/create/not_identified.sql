@@ -0,0 +1 @@
+CREATE USER `guest` NOT IDENTIFIED;
@@ -0,0 +1 @@
+CREATE USER `dave` ON CLUSTER `production`;
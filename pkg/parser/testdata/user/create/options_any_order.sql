@@ -0,0 +1 @@
+CREATE USER IF NOT EXISTS `etl` ON CLUSTER `production` IDENTIFIED WITH sha256_password BY 'secret' HOST IP '10.0.0.0/8' DEFAULT ROLE `writer` DEFAULT DATABASE `analytics` SETTINGS `max_threads` = 8;
@@ -0,0 +1 @@
+CREATE OR REPLACE USER `carol`;
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH plaintext_password BY 'secret';
@@ -0,0 +1 @@
+CREATE USER `alice` SETTINGS `max_memory_usage` = 10000000000, `readonly` = 1;
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH sha256_password BY 'secret';
@@ -0,0 +1 @@
+CREATE USER `alice` IDENTIFIED WITH ssl_certificate CN 'alice.example.com';
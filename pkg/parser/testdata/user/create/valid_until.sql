@@ -0,0 +1 @@
+CREATE USER `alice` VALID UNTIL '2030-01-01 00:00:00';
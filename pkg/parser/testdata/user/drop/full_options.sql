@@ -0,0 +1 @@
+DROP USER IF EXISTS `etl`, `loader` ON CLUSTER `staging`;
@@ -0,0 +1 @@
+DROP USER IF EXISTS `bob`;
@@ -1 +1 @@
-ATTACH TABLE `analytics`.`mv_aggregated`;
+ATTACH TABLE IF NOT EXISTS `analytics`.`mv_aggregated`;
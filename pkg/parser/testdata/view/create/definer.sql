@@ -0,0 +1,6 @@
+CREATE VIEW `analytics`.`secure_summary`
+DEFINER = `analyst` SQL SECURITY DEFINER
+AS SELECT
+    `id`,
+    `total`
+FROM `orders`;
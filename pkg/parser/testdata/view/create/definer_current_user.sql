@@ -0,0 +1,4 @@
+CREATE OR REPLACE VIEW `analytics`.`current_summary`
+DEFINER = CURRENT_USER SQL SECURITY DEFINER
+AS SELECT `id`
+FROM `orders`;
@@ -0,0 +1,4 @@
+CREATE VIEW `analytics`.`invoker_summary`
+SQL SECURITY INVOKER
+AS SELECT `id`
+FROM `orders`;
@@ -0,0 +1,10 @@
+CREATE VIEW `analytics`.`all_events`
+AS SELECT
+    `id`,
+    'web' AS `source`
+FROM `analytics`.`web_events`
+UNION DISTINCT
+SELECT
+    `id`,
+    'mobile' AS `source`
+FROM `analytics`.`mobile_events`;
@@ -0,0 +1,8 @@
+CREATE VIEW `analytics`.`user_running_totals`
+AS SELECT
+    `user_id`,
+    `ts`,
+    sum(`amount`) OVER `w` AS `running_total`,
+    row_number() OVER `w` AS `event_number`
+FROM `analytics`.`events`
+WINDOW `w` AS (PARTITION BY user_id ORDER BY ts ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW);
@@ -0,0 +1,7 @@
+CREATE VIEW `analytics`.`user_journeys`
+AS SELECT
+    `user_id`,
+    groupArray(`page` ORDER BY `ts`) AS `pages`,
+    groupArray(`event` ORDER BY `ts` DESC NULLS LAST, `id`) AS `recent_events`
+FROM `analytics`.`events`
+GROUP BY `user_id`;
@@ -0,0 +1,8 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_secure`
+TO `analytics`.`totals`
+DEFINER = `etl` SQL SECURITY DEFINER
+AS SELECT
+    `id`,
+    sum(`amount`) AS `total`
+FROM `orders`
+GROUP BY `id`;
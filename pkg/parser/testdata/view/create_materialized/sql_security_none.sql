@@ -0,0 +1,5 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_open`
+TO `analytics`.`totals`
+SQL SECURITY NONE
+AS SELECT `id`
+FROM `orders`;
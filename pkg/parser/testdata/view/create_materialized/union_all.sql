@@ -0,0 +1,20 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_all_events`
+TO `analytics`.`events`
+AS SELECT
+    `id`,
+    `ts`,
+    'web' AS `source`
+FROM `analytics`.`web_events`
+WHERE `id` > 0
+UNION ALL
+SELECT
+    `id`,
+    `ts`,
+    'mobile' AS `source`
+FROM `analytics`.`mobile_events`
+UNION ALL
+SELECT
+    `id`,
+    `ts`,
+    'api' AS `source`
+FROM `analytics`.`api_events`;
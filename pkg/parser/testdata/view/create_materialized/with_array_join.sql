@@ -0,0 +1,9 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_event_tags`
+TO `analytics`.`event_tags`
+AS SELECT
+    `e`.`id`,
+    `tag`,
+    `idx`
+FROM `analytics`.`events` AS `e`
+ARRAY JOIN `e`.`tags` AS `tag`, arrayEnumerate(`e`.`tags`) AS `idx`
+WHERE `e`.`id` > 0;
@@ -0,0 +1,6 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_order_tiers`
+TO `analytics`.`order_tiers`
+AS SELECT
+    `id`,
+    CASE WHEN `amount` >= 1000 AND `status` = 'completed' THEN 'large' WHEN `amount` >= 100 THEN concat('medium-', `status`) ELSE 'small' END AS `tier`
+FROM `analytics`.`orders`;
@@ -0,0 +1,7 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_event_tags`
+TO `analytics`.`event_tags`
+AS SELECT
+    `id`,
+    arrayMap(`t` -> lower(`t`), `tags`) AS `tags`,
+    arrayFilter((`t`, `w`) -> `w` > 0.5, `tags`, `weights`) AS `strong_tags`
+FROM `analytics`.`events`;
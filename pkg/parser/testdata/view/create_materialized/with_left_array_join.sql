@@ -0,0 +1,8 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_session_pages`
+TO `analytics`.`session_pages`
+AS SELECT
+    `session_id`,
+    tupleElement(`page`, 1) AS `url`,
+    tupleElement(`page`, 2) AS `duration`
+FROM `analytics`.`sessions`
+LEFT ARRAY JOIN arrayZip(`urls`, `durations`) AS `page`, `ids`;
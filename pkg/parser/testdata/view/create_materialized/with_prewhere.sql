@@ -0,0 +1,9 @@
+CREATE MATERIALIZED VIEW `analytics`.`mv_completed_orders`
+TO `analytics`.`completed_orders`
+AS SELECT
+    `o`.`id`,
+    `o`.`user_id`,
+    `o`.`amount`
+FROM `analytics`.`orders` AS `o`
+PREWHERE `o`.`event_date` >= '2024-01-01'
+WHERE `o`.`status` = 'completed';
@@ -0,0 +1,161 @@
+package parser
+
+type (
+	// CreateUserStmt represents CREATE USER statements
+	// Syntax: CREATE USER [IF NOT EXISTS | OR REPLACE] name [,...] [ON CLUSTER cluster]
+	//   [NOT IDENTIFIED | IDENTIFIED [WITH method] [BY 'password'] ...]
+	//   [HOST {LOCAL | IP 'address' | NAME 'name' | REGEXP 'pattern' | LIKE 'pattern'} [,...] | ANY | NONE]
+	//   [VALID UNTIL 'datetime'] [DEFAULT ROLE role [,...]] [DEFAULT DATABASE database | NONE] [SETTINGS ...];
+	// The options may be written in any order.
+	CreateUserStmt struct {
+		LeadingCommentField
+		OrReplace   bool        `parser:"'CREATE' (@'OR' 'REPLACE')? 'USER'"`
+		IfNotExists bool        `parser:"@('IF' 'NOT' 'EXISTS')?"`
+		Names       []string    `parser:"@(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))*"`
+		OnCluster   *string     `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Options     UserOptions `parser:"@@"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
+	// AlterUserStmt represents ALTER USER statements
+	// Syntax: ALTER USER [IF EXISTS] name [ON CLUSTER cluster] [RENAME TO new_name] [ON CLUSTER cluster]
+	//   [NOT IDENTIFIED | IDENTIFIED ...] [HOST ...] [VALID UNTIL 'datetime']
+	//   [DEFAULT ROLE ...] [DEFAULT DATABASE ...] [SETTINGS ...];
+	// ON CLUSTER may be written before or after RENAME TO. The latter is captured in
+	// OnClusterAfterRename and moved to OnCluster after parsing. The options may be
+	// written in any order.
+	AlterUserStmt struct {
+		LeadingCommentField
+		IfExists             bool        `parser:"'ALTER' 'USER' @('IF' 'EXISTS')?"`
+		Name                 string      `parser:"@(Ident | BacktickIdent)"`
+		OnCluster            *string     `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		RenameTo             *string     `parser:"('RENAME' 'TO' @(Ident | BacktickIdent))?"`
+		OnClusterAfterRename *string     `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		Options              UserOptions `parser:"@@"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
+	// DropUserStmt represents DROP USER statements
+	// Syntax: DROP USER [IF EXISTS] name [,...] [ON CLUSTER cluster];
+	DropUserStmt struct {
+		LeadingCommentField
+		IfExists  bool     `parser:"'DROP' 'USER' @('IF' 'EXISTS')?"`
+		Names     []string `parser:"@(Ident | BacktickIdent) (',' @(Ident | BacktickIdent))*"`
+		OnCluster *string  `parser:"('ON' 'CLUSTER' @(Ident | BacktickIdent))?"`
+		TrailingCommentField
+		Semicolon bool `parser:"';'"`
+	}
+
+	// UserOptions holds the clauses shared by CREATE USER and ALTER USER. This allows
+	// clauses to be specified in any order.
+	UserOptions struct {
+		Clauses []UserClause `parser:"@@*"`
+	}
+
+	// UserClause represents any option clause of a CREATE USER or ALTER USER statement
+	UserClause struct {
+		Authentication  *UserAuthentication `parser:"@@"`
+		Hosts           *UserHosts          `parser:"| @@"`
+		ValidUntil      *string             `parser:"| ('VALID' 'UNTIL' @String)"`
+		DefaultRole     *UserDefaultRole    `parser:"| ('DEFAULT' 'ROLE' @@)"`
+		DefaultDatabase *string             `parser:"| ('DEFAULT' 'DATABASE' @(Ident | BacktickIdent))"`
+		Settings        *RoleSettings       `parser:"| @@"`
+	}
+
+	// UserAuthentication represents how a user authenticates
+	// Syntax: NOT IDENTIFIED | IDENTIFIED [WITH method] [BY 'secret'] [SERVER 'name'] [REALM 'realm'] [CN 'name']
+	UserAuthentication struct {
+		NotIdentified bool    `parser:"@('NOT' 'IDENTIFIED')"`
+		Identified    bool    `parser:"| @'IDENTIFIED'"`
+		Method        *string `parser:"('WITH' @(Ident | BacktickIdent))?"`
+		Secret        *string `parser:"('BY' @String)?"`
+		Server        *string `parser:"('SERVER' @String)?"`
+		Realm         *string `parser:"('REALM' @String)?"`
+		CommonName    *string `parser:"('CN' @String)?"`
+	}
+
+	// UserHosts represents the HOST restrictions on where a user may connect from
+	// Syntax: HOST {ANY | NONE | rule [,...]}
+	UserHosts struct {
+		Any   bool        `parser:"'HOST' (@'ANY'"`
+		None  bool        `parser:"| @'NONE'"`
+		Rules []*HostRule `parser:"| @@ (',' @@)*)"`
+	}
+
+	// HostRule represents a single HOST restriction, e.g. IP '10.0.0.0/8'
+	HostRule struct {
+		Kind  string  `parser:"@('LOCAL' | 'IP' | 'NAME' | 'REGEXP' | 'LIKE')"`
+		Value *string `parser:"@String?"`
+	}
+
+	// UserDefaultRole represents the roles enabled when a user logs in
+	// Syntax: DEFAULT ROLE {NONE | ALL | ALL EXCEPT role [,...] | role [,...]}
+	UserDefaultRole struct {
+		None      bool      `parser:"@'NONE'"`
+		AllExcept *RoleList `parser:"| 'ALL' 'EXCEPT' @@"`
+		All       bool      `parser:"| @'ALL'"`
+		Roles     *RoleList `parser:"| @@"`
+	}
+)
+
+// GetAuthentication returns the NOT IDENTIFIED or IDENTIFIED clause if present
+func (o *UserOptions) GetAuthentication() *UserAuthentication {
+	for _, clause := range o.Clauses {
+		if clause.Authentication != nil {
+			return clause.Authentication
+		}
+	}
+	return nil
+}
+
+// GetHosts returns the HOST clause if present
+func (o *UserOptions) GetHosts() *UserHosts {
+	for _, clause := range o.Clauses {
+		if clause.Hosts != nil {
+			return clause.Hosts
+		}
+	}
+	return nil
+}
+
+// GetValidUntil returns the VALID UNTIL datetime if present
+func (o *UserOptions) GetValidUntil() *string {
+	for _, clause := range o.Clauses {
+		if clause.ValidUntil != nil {
+			return clause.ValidUntil
+		}
+	}
+	return nil
+}
+
+// GetDefaultRole returns the DEFAULT ROLE clause if present
+func (o *UserOptions) GetDefaultRole() *UserDefaultRole {
+	for _, clause := range o.Clauses {
+		if clause.DefaultRole != nil {
+			return clause.DefaultRole
+		}
+	}
+	return nil
+}
+
+// GetDefaultDatabase returns the DEFAULT DATABASE if present
+func (o *UserOptions) GetDefaultDatabase() *string {
+	for _, clause := range o.Clauses {
+		if clause.DefaultDatabase != nil {
+			return clause.DefaultDatabase
+		}
+	}
+	return nil
+}
+
+// GetSettings returns the SETTINGS clause if present
+func (o *UserOptions) GetSettings() *RoleSettings {
+	for _, clause := range o.Clauses {
+		if clause.Settings != nil {
+			return clause.Settings
+		}
+	}
+	return nil
+}
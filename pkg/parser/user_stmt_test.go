@@ -0,0 +1,74 @@
+package parser_test
+
+import "testing"
+
+func TestCreateUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "basic", sql: `CREATE USER alice;`},
+		{name: "if_not_exists", sql: `CREATE USER IF NOT EXISTS bob;`},
+		{name: "multiple", sql: `CREATE USER alice, bob ON CLUSTER production IDENTIFIED WITH sha256_password BY 'secret';`},
+		{name: "or_replace", sql: `CREATE OR REPLACE USER carol;`},
+		{name: "on_cluster", sql: `CREATE USER dave ON CLUSTER production;`},
+		{name: "not_identified", sql: `CREATE USER guest NOT IDENTIFIED;`},
+		{name: "plaintext_password", sql: `CREATE USER alice IDENTIFIED WITH plaintext_password BY 'secret';`},
+		{name: "sha256_password", sql: `CREATE USER alice IDENTIFIED WITH sha256_password BY 'secret';`},
+		{name: "sha256_hash", sql: `CREATE USER alice IDENTIFIED WITH sha256_hash BY '2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b';`},
+		{name: "double_sha1_password", sql: `CREATE USER alice IDENTIFIED WITH double_sha1_password BY 'secret';`},
+		{name: "bcrypt_password", sql: `CREATE USER alice IDENTIFIED WITH bcrypt_password BY 'secret';`},
+		{name: "identified_by", sql: `CREATE USER alice IDENTIFIED BY 'secret';`},
+		{name: "no_password", sql: `CREATE USER alice IDENTIFIED WITH no_password;`},
+		{name: "ldap", sql: `CREATE USER alice IDENTIFIED WITH ldap SERVER 'corp_ldap';`},
+		{name: "kerberos", sql: `CREATE USER alice IDENTIFIED WITH kerberos REALM 'EXAMPLE.COM';`},
+		{name: "ssl_certificate", sql: `CREATE USER alice IDENTIFIED WITH ssl_certificate CN 'alice.example.com';`},
+		{name: "host_ip", sql: `CREATE USER alice HOST IP '10.0.0.0/8';`},
+		{name: "host_multiple", sql: `CREATE USER alice HOST LOCAL, NAME 'db.example.com', REGEXP '.*\\.example\\.com', LIKE '%.internal';`},
+		{name: "host_any", sql: `CREATE USER alice HOST ANY;`},
+		{name: "host_none", sql: `CREATE USER alice HOST NONE;`},
+		{name: "valid_until", sql: `CREATE USER alice VALID UNTIL '2030-01-01 00:00:00';`},
+		{name: "default_role", sql: `CREATE USER alice DEFAULT ROLE analyst, reader;`},
+		{name: "default_role_all_except", sql: `CREATE USER alice DEFAULT ROLE ALL EXCEPT admin;`},
+		{name: "default_database", sql: `CREATE USER alice DEFAULT DATABASE analytics;`},
+		{name: "settings", sql: `CREATE USER alice SETTINGS max_memory_usage = 10000000000, readonly = 1;`},
+		{name: "full_options", sql: `CREATE USER IF NOT EXISTS etl ON CLUSTER production IDENTIFIED WITH sha256_password BY 'secret' HOST IP '10.0.0.0/8' DEFAULT ROLE writer DEFAULT DATABASE analytics SETTINGS max_threads = 8;`},
+		// Formatted like full_options, since the options may be written in any order
+		{name: "options_any_order", sql: `CREATE USER IF NOT EXISTS etl ON CLUSTER production SETTINGS max_threads = 8 DEFAULT DATABASE analytics DEFAULT ROLE writer HOST IP '10.0.0.0/8' IDENTIFIED WITH sha256_password BY 'secret';`},
+	}
+
+	runStatementTests(t, "user/create", tests)
+}
+
+func TestAlterUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "rename", sql: `ALTER USER alice RENAME TO alicia;`},
+		{name: "if_exists", sql: `ALTER USER IF EXISTS bob RENAME TO robert;`},
+		{name: "on_cluster", sql: `ALTER USER carol ON CLUSTER production NOT IDENTIFIED;`},
+		{name: "password", sql: `ALTER USER alice IDENTIFIED WITH sha256_password BY 'new_secret';`},
+		{name: "host", sql: `ALTER USER alice HOST IP '192.168.0.0/16', LOCAL;`},
+		{name: "default_role_none", sql: `ALTER USER alice DEFAULT ROLE NONE;`},
+		{name: "default_role_all", sql: `ALTER USER alice DEFAULT ROLE ALL;`},
+		{name: "settings", sql: `ALTER USER alice SETTINGS max_memory_usage = 5000000000;`},
+		{name: "full_options", sql: `ALTER USER IF EXISTS etl ON CLUSTER staging RENAME TO loader IDENTIFIED WITH ldap SERVER 'corp_ldap' HOST ANY DEFAULT ROLE ALL EXCEPT admin SETTINGS max_threads = 4;`},
+		// Formatted like full_options, since ON CLUSTER may follow RENAME TO and the options may be written in any order
+		{name: "options_any_order", sql: `ALTER USER IF EXISTS etl RENAME TO loader ON CLUSTER staging SETTINGS max_threads = 4 DEFAULT ROLE ALL EXCEPT admin HOST ANY IDENTIFIED WITH ldap SERVER 'corp_ldap';`},
+		{name: "on_cluster_after_rename", sql: `ALTER USER alice RENAME TO alicia ON CLUSTER production;`},
+	}
+
+	runStatementTests(t, "user/alter", tests)
+}
+
+func TestDropUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []statementTest{
+		{name: "basic", sql: `DROP USER alice;`},
+		{name: "if_exists", sql: `DROP USER IF EXISTS bob;`},
+		{name: "multiple", sql: `DROP USER alice, bob, carol;`},
+		{name: "full_options", sql: `DROP USER IF EXISTS etl, loader ON CLUSTER staging;`},
+	}
+
+	runStatementTests(t, "user/drop", tests)
+}
@@ -18,6 +18,7 @@ type (
 	// ClickHouse syntax:
 	//   CREATE [OR REPLACE] [MATERIALIZED] VIEW [IF NOT EXISTS] [db.]view_name [ON CLUSTER cluster]
 	//   [TO [db.]table_name] [ENGINE = engine] [POPULATE]
+	//   [DEFINER = { user | CURRENT_USER }] [SQL SECURITY { DEFINER | INVOKER | NONE }]
 	//   AS SELECT ...
 	CreateViewStmt struct {
 		LeadingCommentField
@@ -32,6 +33,8 @@ type (
 		To           *ViewTableTarget `parser:"('TO' @@)?"`
 		Engine       *ViewEngine      `parser:"@@?"`
 		Populate     bool             `parser:"@'POPULATE'?"`
+		Definer      *string          `parser:"('DEFINER' '=' @(Ident | BacktickIdent))?"`
+		SQLSecurity  *string          `parser:"('SQL' 'SECURITY' @('DEFINER' | 'INVOKER' | 'NONE'))?"`
 		AsSelect     *SelectStatement `parser:"'AS' @@"`
 		TrailingCommentField
 		Semicolon bool `parser:"';'"`
@@ -11,7 +11,13 @@ func TestCreateView(t *testing.T) {
 		{name: "on_cluster", sql: `CREATE VIEW stats_view ON CLUSTER production AS SELECT * FROM statistics;`},
 		{name: "or_replace", sql: `CREATE OR REPLACE VIEW analytics.updated_view AS SELECT id, name, updated_at FROM users ORDER BY updated_at DESC;`},
 		{name: "with_backticks", sql: "CREATE VIEW `analytics-db`.`daily-summary` AS SELECT `order-date` AS `date`, count(*) AS `total-orders` FROM `orders-table` GROUP BY `order-date`;"},
+		{name: "definer", sql: `CREATE VIEW analytics.secure_summary DEFINER = analyst SQL SECURITY DEFINER AS SELECT id, total FROM orders;`},
+		{name: "sql_security_invoker", sql: `CREATE VIEW analytics.invoker_summary SQL SECURITY INVOKER AS SELECT id FROM orders;`},
+		{name: "definer_current_user", sql: `CREATE OR REPLACE VIEW analytics.current_summary DEFINER = CURRENT_USER SQL SECURITY DEFINER AS SELECT id FROM orders;`},
 		{name: "with_window_functions", sql: `CREATE VIEW analytics.user_rankings AS SELECT user_id, name, score, row_number() OVER (ORDER BY score DESC) AS rank, rank() OVER (PARTITION BY category ORDER BY score DESC) AS category_rank FROM user_scores ORDER BY score DESC;`},
+		{name: "union", sql: `CREATE VIEW analytics.all_events AS SELECT id, 'web' AS source FROM analytics.web_events UNION DISTINCT SELECT id, 'mobile' AS source FROM analytics.mobile_events;`},
+		{name: "with_named_windows", sql: `CREATE VIEW analytics.user_running_totals AS SELECT user_id, ts, sum(amount) OVER w AS running_total, row_number() OVER w AS event_number FROM analytics.events WINDOW w AS (PARTITION BY user_id ORDER BY ts ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW);`},
+		{name: "with_ordered_aggregates", sql: `CREATE VIEW analytics.user_journeys AS SELECT user_id, groupArray(page ORDER BY ts) AS pages, groupArray(event ORDER BY ts DESC NULLS LAST, id) AS recent_events FROM analytics.events GROUP BY user_id;`},
 	}
 
 	runStatementTests(t, "view/create", tests)
@@ -27,7 +33,15 @@ func TestCreateMaterializedView(t *testing.T) {
 		{name: "populate", sql: `CREATE MATERIALIZED VIEW mv_with_populate ENGINE = AggregatingMergeTree() ORDER BY date POPULATE AS SELECT toDate(timestamp) AS date, sum(amount) AS total FROM transactions GROUP BY date;`},
 		{name: "full_options", sql: `CREATE OR REPLACE MATERIALIZED VIEW IF NOT EXISTS analytics.mv_complex ON CLUSTER production TO analytics.destination_table ENGINE = ReplacingMergeTree(version) POPULATE AS SELECT id, name, max(version) AS version, argMax(data, version) AS data FROM source GROUP BY id, name;`},
 		{name: "with_joins", sql: `CREATE MATERIALIZED VIEW analytics.mv_joins ENGINE = MergeTree() ORDER BY (date, category) AS SELECT toDate(e.timestamp) AS date, e.user_id, u.name AS user_name, e.category, count() AS event_count, sum(e.value) AS total_value FROM events AS e LEFT JOIN users AS u ON e.user_id = u.id WHERE e.status = 'completed' GROUP BY date, e.user_id, u.name, e.category;`},
+		{name: "definer", sql: `CREATE MATERIALIZED VIEW analytics.mv_secure TO analytics.totals DEFINER = etl SQL SECURITY DEFINER AS SELECT id, sum(amount) AS total FROM orders GROUP BY id;`},
+		{name: "sql_security_none", sql: `CREATE MATERIALIZED VIEW analytics.mv_open TO analytics.totals SQL SECURITY NONE AS SELECT id FROM orders;`},
 		{name: "with_states", sql: `CREATE MATERIALIZED VIEW metrics.mv_user_stats_state TO metrics.user_stats_aggregated AS SELECT toDate(timestamp) AS date, user_id, sumState(amount) AS total_amount_state, avgState(duration) AS avg_duration_state, uniqState(session_id) AS unique_sessions_state FROM raw_events GROUP BY date, user_id;`},
+		{name: "union_all", sql: `CREATE MATERIALIZED VIEW analytics.mv_all_events TO analytics.events AS SELECT id, ts, 'web' AS source FROM analytics.web_events WHERE id > 0 UNION ALL SELECT id, ts, 'mobile' AS source FROM analytics.mobile_events UNION ALL SELECT id, ts, 'api' AS source FROM analytics.api_events;`},
+		{name: "with_prewhere", sql: `CREATE MATERIALIZED VIEW analytics.mv_completed_orders TO analytics.completed_orders AS SELECT o.id, o.user_id, o.amount FROM analytics.orders o PREWHERE o.event_date >= '2024-01-01' WHERE o.status = 'completed';`},
+		{name: "with_array_join", sql: `CREATE MATERIALIZED VIEW analytics.mv_event_tags TO analytics.event_tags AS SELECT e.id, tag, idx FROM analytics.events e ARRAY JOIN e.tags AS tag, arrayEnumerate(e.tags) AS idx WHERE e.id > 0;`},
+		{name: "with_left_array_join", sql: `CREATE MATERIALIZED VIEW analytics.mv_session_pages TO analytics.session_pages AS SELECT session_id, tupleElement(page, 1) AS url, tupleElement(page, 2) AS duration FROM analytics.sessions LEFT ARRAY JOIN arrayZip(urls, durations) AS page, ids;`},
+		{name: "with_case", sql: `CREATE MATERIALIZED VIEW analytics.mv_order_tiers TO analytics.order_tiers AS SELECT id, CASE WHEN amount >= 1000 AND status = 'completed' THEN 'large' WHEN amount >= 100 THEN concat('medium-', status) ELSE 'small' END AS tier FROM analytics.orders;`},
+		{name: "with_lambdas", sql: `CREATE MATERIALIZED VIEW analytics.mv_event_tags TO analytics.event_tags AS SELECT id, arrayMap(t -> lower(t), tags) AS tags, arrayFilter((t, w) -> w > 0.5, tags, weights) AS strong_tags FROM analytics.events;`},
 	}
 
 	runStatementTests(t, "view/create_materialized", tests)
@@ -0,0 +1,119 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// detectColumnRenames finds columns that were renamed between current and target.
+//
+// A column is considered renamed when it's missing from target, and exactly one column
+// missing from current has identical properties (type, default, codec, TTL, comment,
+// and settings). The match must be unambiguous in both directions, so dropping and
+// adding several columns of the same type is still treated as DROP+ADD.
+//
+// Renames are returned in current column order.
+func detectColumnRenames(current, target []ColumnInfo, currentCols, targetCols map[string]ColumnInfo) []RenamePair {
+	var dropped, added []ColumnInfo
+	for _, col := range current {
+		if _, exists := targetCols[col.Name]; !exists {
+			dropped = append(dropped, col)
+		}
+	}
+	for _, col := range target {
+		if _, exists := currentCols[col.Name]; !exists {
+			added = append(added, col)
+		}
+	}
+
+	var renames []RenamePair
+	for _, droppedCol := range dropped {
+		matches := matchingColumns(droppedCol, added)
+		if len(matches) != 1 {
+			continue
+		}
+
+		addedCol := matches[0]
+		if len(matchingColumns(addedCol, dropped)) != 1 {
+			continue
+		}
+
+		renames = append(renames, RenamePair{OldName: droppedCol.Name, NewName: addedCol.Name})
+	}
+
+	return renames
+}
+
+// matchingColumns returns the candidates whose properties, excluding the name, match col
+func matchingColumns(col ColumnInfo, candidates []ColumnInfo) []ColumnInfo {
+	var matches []ColumnInfo
+	for _, candidate := range candidates {
+		renamed := col
+		renamed.Name = candidate.Name
+		if renamed.Equal(candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
+
+// renameColumnReferences returns a copy of col whose DEFAULT, MATERIALIZED, ALIAS, or
+// EPHEMERAL expression references the new names of renamed columns. ClickHouse rewrites
+// these expressions itself when a column is renamed, so the rewritten column is what the
+// table will look like after the RENAME COLUMN operations are applied.
+//
+// Only unqualified column references are rewritten. Conditions in CASE expressions are
+// stored as raw text by the parser and are left untouched.
+func renameColumnReferences(col ColumnInfo, renames map[string]string) ColumnInfo {
+	if col.Default == nil || len(renames) == 0 {
+		return col
+	}
+
+	rewritten := copyRenamingIdentifiers(reflect.ValueOf(*col.Default), renames).Interface().(parser.Expression)
+	col.Default = &rewritten
+	return col
+}
+
+// copyRenamingIdentifiers deep copies an AST value, replacing the names of unqualified
+// identifiers found in renames. The original AST is never modified.
+func copyRenamingIdentifiers(v reflect.Value, renames map[string]string) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Elem().Type())
+		copied.Elem().Set(copyRenamingIdentifiers(v.Elem(), renames))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			copied.Index(i).Set(copyRenamingIdentifiers(v.Index(i), renames))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		for i := range v.NumField() {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(copyRenamingIdentifiers(v.Field(i), renames))
+			}
+		}
+
+		if ident, ok := copied.Addr().Interface().(*parser.IdentifierExpr); ok && ident.Database == nil && ident.Table == nil {
+			if newName, renamed := renames[normalizeIdentifier(ident.Name)]; renamed {
+				// Keep the original quoting so the copy compares equal to an identically written target
+				if ident.Name != normalizeIdentifier(ident.Name) {
+					newName = "`" + newName + "`"
+				}
+				ident.Name = newName
+			}
+		}
+		return copied
+	default:
+		return v
+	}
+}
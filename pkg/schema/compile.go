@@ -32,6 +32,18 @@ import (
 //		log.Fatal(err)
 //	}
 func Compile(path string, w io.Writer) error {
+	return scanSchemaFile(
+		path,
+		func(line string) { fmt.Fprintln(w, line) },
+		func(importPath string) error { return Compile(importPath, w) },
+	)
+}
+
+// scanSchemaFile reads the schema file at path line by line. Import directives (lines
+// starting with "-- housekeeper:import") are passed to onImport with their path resolved
+// relative to the file's directory, in the order they appear, while every other line is
+// passed to onLine.
+func scanSchemaFile(path string, onLine func(line string), onImport func(importPath string) error) error {
 	f, err := os.Open(path)
 	if err != nil {
 		return errors.Wrapf(err, "failed to read file %s", path)
@@ -47,18 +59,17 @@ func Compile(path string, w io.Writer) error {
 
 			// Resolve import path relative to current file's directory
 			if !filepath.IsAbs(importPath) {
-				dir := filepath.Dir(path)
-				importPath = filepath.Join(dir, importPath)
+				importPath = filepath.Join(filepath.Dir(path), importPath)
 			}
 
-			if err := Compile(importPath, w); err != nil {
+			if err := onImport(importPath); err != nil {
 				return err
 			}
 
 			continue
 		}
 
-		fmt.Fprintln(w, line)
+		onLine(line)
 	}
 
 	return errors.Wrapf(scanner.Err(), "failed scanning %s", path)
@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+const (
+	// ConstraintDiffAdd indicates a CHECK constraint needs to be added
+	ConstraintDiffAdd ConstraintDiffType = "ADD"
+	// ConstraintDiffDrop indicates a CHECK constraint needs to be dropped
+	ConstraintDiffDrop ConstraintDiffType = "DROP"
+)
+
+type (
+	// ConstraintInfo represents a CHECK constraint declared in a table definition
+	ConstraintInfo struct {
+		Name       string             // Constraint name
+		Expression *parser.Expression // CHECK expression AST
+	}
+
+	// ConstraintDiff represents a difference in CHECK constraint definitions. ClickHouse
+	// can't modify a constraint, so a changed constraint is expressed as a DROP followed by an ADD.
+	ConstraintDiff struct {
+		Type        ConstraintDiffType // Type of constraint operation
+		Constraint  *ConstraintInfo    // Constraint being added or dropped
+		Description string             // Human-readable description
+	}
+
+	// ConstraintDiffType represents the type of constraint difference
+	ConstraintDiffType string
+)
+
+// Equal compares two ConstraintInfo instances for equality, ignoring redundant
+// parentheses around the expression
+func (c ConstraintInfo) Equal(other ConstraintInfo) bool {
+	return c.Name == other.Name && keyExpressionsEqual(c.Expression, other.Expression)
+}
+
+// newConstraintInfo extracts constraint information from a constraint definition
+func newConstraintInfo(def *parser.ConstraintDefinition) ConstraintInfo {
+	return ConstraintInfo{
+		Name:       normalizeIdentifier(def.Name),
+		Expression: &def.Expression,
+	}
+}
+
+// elementName returns the name the constraint is matched by (see tableElement)
+func (c ConstraintInfo) elementName() string {
+	return c.Name
+}
+
+// renameColumns returns the constraint with renamed columns replaced in its expression
+func (c ConstraintInfo) renameColumns(renames map[string]string) ConstraintInfo {
+	if c.Expression == nil || len(renames) == 0 {
+		return c
+	}
+
+	rewritten := copyRenamingIdentifiers(reflect.ValueOf(*c.Expression), renames).Interface().(parser.Expression)
+	c.Expression = &rewritten
+	return c
+}
+
+// newConstraintDiff creates the ConstraintDiff adding or dropping a constraint (see compareTableElements)
+func newConstraintDiff(add bool, constraint ConstraintInfo) ConstraintDiff {
+	if add {
+		return ConstraintDiff{Type: ConstraintDiffAdd, Constraint: &constraint, Description: "Add constraint " + constraint.Name}
+	}
+	return ConstraintDiff{Type: ConstraintDiffDrop, Constraint: &constraint, Description: "Drop constraint " + constraint.Name}
+}
+
+// change returns whether the constraint is added, and the constraint (see tableElementDiff)
+func (d ConstraintDiff) change() (bool, ConstraintInfo) {
+	return d.Type == ConstraintDiffAdd, *d.Constraint
+}
+
+// formatConstraintDefinition formats a constraint for CREATE TABLE and ALTER TABLE ... ADD CONSTRAINT
+func formatConstraintDefinition(constraint ConstraintInfo) string {
+	return "`" + constraint.Name + "` CHECK " + constraint.Expression.String()
+}
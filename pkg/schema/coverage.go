@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// CoverageReport describes how much of a live database is managed by a project schema.
+type CoverageReport struct {
+	// Managed lists the live objects that are defined in the project schema
+	Managed []ObjectRef
+
+	// Unmanaged lists the live objects that aren't defined in the project schema
+	Unmanaged []ObjectRef
+}
+
+// Total returns the number of objects in the live database.
+func (r *CoverageReport) Total() int {
+	return len(r.Managed) + len(r.Unmanaged)
+}
+
+// Percent returns the percentage of live objects managed by the project schema. An
+// empty database is considered fully covered.
+func (r *CoverageReport) Percent() float64 {
+	if r.Total() == 0 {
+		return 100
+	}
+	return float64(len(r.Managed)) * 100 / float64(r.Total())
+}
+
+// Coverage compares the objects in a live database schema with those defined in the
+// project schema and reports which live objects are managed. Objects are matched by
+// type and fully-qualified name (see ListObjects); their definitions aren't compared,
+// so a managed object may still have drift. Objects that are only defined in the
+// project schema don't affect coverage.
+//
+// Example:
+//
+//	live, err := client.GetSchema(ctx)
+//	if err != nil {
+//		return err
+//	}
+//
+//	report := schema.Coverage(live, projectSchema)
+//	fmt.Printf("%.1f%% managed, %d unmanaged object(s)\n", report.Percent(), len(report.Unmanaged))
+func Coverage(live, managed *parser.SQL) *CoverageReport {
+	defined := make(map[ObjectRef]bool)
+	for _, obj := range ListObjects(managed) {
+		defined[ObjectRef{Type: obj.Type, Name: obj.Name}] = true
+	}
+
+	report := &CoverageReport{}
+	for _, obj := range ListObjects(live) {
+		if defined[ObjectRef{Type: obj.Type, Name: obj.Name}] {
+			report.Managed = append(report.Managed, obj)
+		} else {
+			report.Unmanaged = append(report.Unmanaged, obj)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,49 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	live, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE DATABASE legacy ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.sessions (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE legacy.orders (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE VIEW analytics.recent_events AS SELECT * FROM analytics.events;
+	`)
+	require.NoError(t, err)
+
+	managed, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Drifted comment';
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.recent_events (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.not_deployed (id UInt64) ENGINE = MergeTree() ORDER BY id;
+	`)
+	require.NoError(t, err)
+
+	report := schema.Coverage(live, managed)
+	require.Equal(t, []schema.ObjectRef{
+		{Type: schema.ObjectTypeDatabase, Name: "analytics"},
+		{Type: schema.ObjectTypeTable, Name: "analytics.events"},
+	}, report.Managed)
+	require.Equal(t, []schema.ObjectRef{
+		{Type: schema.ObjectTypeDatabase, Name: "legacy"},
+		{Type: schema.ObjectTypeTable, Name: "analytics.sessions"},
+		{Type: schema.ObjectTypeTable, Name: "legacy.orders"},
+		{Type: schema.ObjectTypeView, Name: "analytics.recent_events"},
+	}, report.Unmanaged, "objects must match by type as well as name")
+	require.Equal(t, 6, report.Total())
+	require.InDelta(t, 33.33, report.Percent(), 0.01)
+}
+
+func TestCoverage_Empty(t *testing.T) {
+	report := schema.Coverage(&parser.SQL{}, &parser.SQL{})
+	require.Equal(t, 0, report.Total())
+	require.InDelta(t, 100.0, report.Percent(), 0.001)
+}
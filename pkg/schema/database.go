@@ -2,6 +2,8 @@ package schema
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -37,10 +39,11 @@ type (
 	// This structure contains all the properties needed for database comparison and
 	// migration generation, including metadata for cluster and engine configuration.
 	DatabaseInfo struct {
-		Name    string // Database name
-		Engine  string // Engine type (e.g., "Atomic", "MySQL", "Memory")
-		Comment string // Database comment (without quotes)
-		Cluster string // Cluster name if specified (empty if not clustered)
+		Name     string            // Database name
+		Engine   string            // Engine type (e.g., "Atomic", "MySQL", "Memory")
+		Settings map[string]string // Database settings (e.g., for Replicated databases)
+		Comment  string            // Database comment (without quotes)
+		Cluster  string            // Cluster name if specified (empty if not clustered)
 	}
 )
 
@@ -62,6 +65,7 @@ func (d *DatabaseInfo) PropertiesMatch(other SchemaObject) bool {
 		return false
 	}
 	return d.Engine == otherDB.Engine &&
+		maps.Equal(d.Settings, otherDB.Settings) &&
 		d.Comment == otherDB.Comment &&
 		d.Cluster == otherDB.Cluster
 }
@@ -77,9 +81,12 @@ func (d *DatabaseInfo) PropertiesMatch(other SchemaObject) bool {
 //
 // Rename Detection:
 // The function intelligently detects rename operations by comparing database properties
-// (engine, comment, cluster) excluding the name. If two databases have identical
+// (engine, settings, comment, cluster) excluding the name. If two databases have identical
 // properties but different names, it generates a RENAME operation instead of DROP+CREATE.
-func compareDatabases(current, target *parser.SQL) ([]*DatabaseDiff, error) {
+//
+// Generated DROP DATABASE statements preserve the database's ON CLUSTER clause and
+// include SYNC when opts.UseSyncOnDrop is set.
+func compareDatabases(current, target *parser.SQL, opts CompareOptions) ([]*DatabaseDiff, error) {
 	// Extract database information from both SQL structures
 	currentDBs := extractDatabaseInfo(current)
 	targetDBs := extractDatabaseInfo(target)
@@ -100,9 +107,10 @@ func compareDatabases(current, target *parser.SQL) ([]*DatabaseDiff, error) {
 				Name:        rename.OldName,
 				NewName:     rename.NewName,
 				Description: fmt.Sprintf("Rename database '%s' to '%s'", rename.OldName, rename.NewName),
-				UpSQL:       generateRenameDatabaseSQL(rename.OldName, rename.NewName, currentDB.Cluster),
-				DownSQL:     generateRenameDatabaseSQL(rename.NewName, rename.OldName, currentDB.Cluster),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateRenameDatabaseSQL(rename.OldName, rename.NewName, currentDB.Cluster),
+					generateRenameDatabaseSQL(rename.NewName, rename.OldName, currentDB.Cluster)
+			}),
 			Current: currentDB,
 			Target:  targetDB,
 		}
@@ -113,7 +121,7 @@ func compareDatabases(current, target *parser.SQL) ([]*DatabaseDiff, error) {
 	for _, name := range SortedKeys(processedTarget) {
 		targetDB := processedTarget[name]
 		currentDB, exists := processedCurrent[name]
-		diff, err := createDatabaseDiff(name, currentDB, targetDB, exists)
+		diff, err := createDatabaseDiff(name, currentDB, targetDB, exists, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -131,9 +139,9 @@ func compareDatabases(current, target *parser.SQL) ([]*DatabaseDiff, error) {
 				Type:        string(DatabaseDiffDrop),
 				Name:        name,
 				Description: fmt.Sprintf("Drop database '%s'", name),
-				UpSQL:       generateDropDatabaseSQL(currentDB),
-				DownSQL:     generateCreateDatabaseSQL(currentDB),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropDatabaseSQL(currentDB, opts.UseSyncOnDrop), generateCreateDatabaseSQL(currentDB)
+			}),
 			Current: currentDB,
 		}
 		diffs = append(diffs, diff)
@@ -161,6 +169,13 @@ func extractDatabaseInfo(sql *parser.SQL) map[string]*DatabaseInfo {
 				info.Engine = db.Engine.String()
 			}
 
+			if db.Settings != nil {
+				info.Settings = make(map[string]string, len(db.Settings.Settings))
+				for _, setting := range db.Settings.Settings {
+					info.Settings[setting.Name] = setting.GetValue()
+				}
+			}
+
 			if db.Comment != nil {
 				info.Comment = removeQuotes(*db.Comment)
 			}
@@ -185,32 +200,45 @@ func generateRenameDatabaseSQL(oldName, newName, onCluster string) string {
 // needsModification checks if a database needs to be modified
 func needsModification(current, target *DatabaseInfo) bool {
 	return current.Comment != target.Comment ||
+		!maps.Equal(current.Settings, target.Settings) ||
 		current.Engine != target.Engine ||
 		current.Cluster != target.Cluster
 }
 
 // generateCreateDatabaseSQL generates CREATE DATABASE SQL from database info
 func generateCreateDatabaseSQL(db *DatabaseInfo) string {
-	return utils.NewSQLBuilder().
+	builder := utils.NewSQLBuilder().
 		Create("DATABASE").
 		Name(db.Name).
 		OnCluster(db.Cluster).
-		Engine(db.Engine).
-		Comment(db.Comment).
-		String()
+		Engine(db.Engine)
+
+	if len(db.Settings) > 0 {
+		builder.Raw("SETTINGS " + formatSettings(db.Settings))
+	}
+
+	return builder.Comment(db.Comment).String()
 }
 
-// generateDropDatabaseSQL generates DROP DATABASE SQL from database info
-func generateDropDatabaseSQL(db *DatabaseInfo) string {
-	return utils.NewSQLBuilder().
+// generateDropDatabaseSQL generates DROP DATABASE SQL from database info, adding
+// SYNC when sync is set
+func generateDropDatabaseSQL(db *DatabaseInfo, sync bool) string {
+	builder := utils.NewSQLBuilder().
 		Drop("DATABASE").
 		IfExists().
 		Name(db.Name).
-		OnCluster(db.Cluster).
-		String()
+		OnCluster(db.Cluster)
+
+	if sync {
+		builder.Raw("SYNC")
+	}
+
+	return builder.String()
 }
 
-// generateAlterDatabaseSQL generates ALTER DATABASE SQL to change from current to target state
+// generateAlterDatabaseSQL generates ALTER DATABASE SQL to change from current to target state.
+// ClickHouse can't reset a database setting, so settings missing from target are left as they
+// are (see removedDatabaseSettings).
 func generateAlterDatabaseSQL(current, target *DatabaseInfo) (string, error) {
 	var statements []string
 
@@ -223,18 +251,39 @@ func generateAlterDatabaseSQL(current, target *DatabaseInfo) (string, error) {
 		return "", errors.Wrapf(ErrUnsupported, "cluster change from '%s' to '%s' - requires manual intervention", current.Cluster, target.Cluster)
 	}
 
-	// Check if comment changed
-	if current.Comment != target.Comment {
+	// Check if any settings were added or changed
+	var changedSettings []string
+	for _, name := range slices.Sorted(maps.Keys(target.Settings)) {
+		if value, exists := current.Settings[name]; !exists || value != target.Settings[name] {
+			changedSettings = append(changedSettings, name+" = "+target.Settings[name])
+		}
+	}
+	if len(changedSettings) > 0 {
 		sql := utils.NewSQLBuilder().
 			Alter("DATABASE").
 			Name(target.Name).
 			OnCluster(target.Cluster).
-			Modify("COMMENT").
-			Escaped(target.Comment).
+			Modify("SETTING").
+			Raw(strings.Join(changedSettings, ", ")).
 			String()
 		statements = append(statements, sql)
 	}
 
+	// Check if comment changed. An empty comment removes it.
+	if current.Comment != target.Comment {
+		builder := utils.NewSQLBuilder().
+			Alter("DATABASE").
+			Name(target.Name).
+			OnCluster(target.Cluster).
+			Modify("COMMENT")
+		if target.Comment == "" {
+			builder.Raw("''")
+		} else {
+			builder.Escaped(target.Comment)
+		}
+		statements = append(statements, builder.String())
+	}
+
 	if len(statements) == 0 {
 		return "-- No changes needed", nil
 	}
@@ -242,7 +291,7 @@ func generateAlterDatabaseSQL(current, target *DatabaseInfo) (string, error) {
 	return strings.Join(statements, "\n"), nil
 }
 
-func createDatabaseDiff(name string, currentDB, targetDB *DatabaseInfo, exists bool) (*DatabaseDiff, error) {
+func createDatabaseDiff(name string, currentDB, targetDB *DatabaseInfo, exists bool, opts CompareOptions) (*DatabaseDiff, error) {
 	// Validate operation before proceeding
 	if err := validateDatabaseOperation(currentDB, targetDB); err != nil {
 		return nil, err
@@ -255,9 +304,9 @@ func createDatabaseDiff(name string, currentDB, targetDB *DatabaseInfo, exists b
 				Type:        string(DatabaseDiffCreate),
 				Name:        name,
 				Description: fmt.Sprintf("Create database '%s'", name),
-				UpSQL:       generateCreateDatabaseSQL(targetDB),
-				DownSQL:     generateDropDatabaseSQL(targetDB),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateCreateDatabaseSQL(targetDB), generateDropDatabaseSQL(targetDB, opts.UseSyncOnDrop)
+			}),
 			Target: targetDB,
 		}, nil
 	}
@@ -267,6 +316,24 @@ func createDatabaseDiff(name string, currentDB, targetDB *DatabaseInfo, exists b
 		return nil, nil
 	}
 
+	if removed := removedDatabaseSettings(currentDB, targetDB); len(removed) > 0 {
+		return nil, errors.Wrapf(ErrUnsupported, "removing settings %s from database '%s' - ClickHouse cannot reset database settings",
+			strings.Join(removed, ", "), name)
+	}
+
+	diff := &DatabaseDiff{
+		DiffBase: DiffBase{
+			Type:        string(DatabaseDiffAlter),
+			Name:        name,
+			Description: fmt.Sprintf("Alter database '%s'", name),
+		},
+		Current: currentDB,
+		Target:  targetDB,
+	}
+	if opts.metadataOnly {
+		return diff, nil
+	}
+
 	upSQL, err := generateAlterDatabaseSQL(currentDB, targetDB)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to generate UP migration for database '%s'", name)
@@ -277,15 +344,29 @@ func createDatabaseDiff(name string, currentDB, targetDB *DatabaseInfo, exists b
 		return nil, errors.Wrapf(err, "failed to generate DOWN migration for database '%s'", name)
 	}
 
-	return &DatabaseDiff{
-		DiffBase: DiffBase{
-			Type:        string(DatabaseDiffAlter),
-			Name:        name,
-			Description: fmt.Sprintf("Alter database '%s'", name),
-			UpSQL:       upSQL,
-			DownSQL:     downSQL,
-		},
-		Current: currentDB,
-		Target:  targetDB,
-	}, nil
+	// Settings added by the up migration can't be reset, so the down migration says so
+	// rather than silently leaving them in place
+	if added := removedDatabaseSettings(targetDB, currentDB); len(added) > 0 {
+		note := fmt.Sprintf("-- ClickHouse cannot reset database settings, revert manually: %s", strings.Join(added, ", "))
+		if downSQL == "-- No changes needed" {
+			downSQL = note
+		} else {
+			downSQL = note + "\n" + downSQL
+		}
+	}
+
+	diff.UpSQL, diff.DownSQL = upSQL, downSQL
+	return diff, nil
+}
+
+// removedDatabaseSettings returns the sorted names of the settings of current that aren't set in target
+func removedDatabaseSettings(current, target *DatabaseInfo) []string {
+	var removed []string
+	for _, name := range slices.Sorted(maps.Keys(current.Settings)) {
+		if _, exists := target.Settings[name]; !exists {
+			removed = append(removed, name)
+		}
+	}
+
+	return removed
 }
@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareDatabases_Drop(t *testing.T) {
+	current, err := parser.ParseString(`
+		CREATE DATABASE analytics ON CLUSTER production
+		ENGINE = Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}')
+		COMMENT 'Analytics database';
+		CREATE DATABASE local ENGINE = Atomic;
+	`)
+	require.NoError(t, err)
+
+	target := &parser.SQL{}
+
+	tests := []struct {
+		name     string
+		opts     CompareOptions
+		expected map[string]string
+	}{
+		{
+			name: "without sync",
+			opts: CompareOptions{},
+			expected: map[string]string{
+				"analytics": "DROP DATABASE IF EXISTS `analytics` ON CLUSTER `production`;",
+				"local":     "DROP DATABASE IF EXISTS `local`;",
+			},
+		},
+		{
+			name: "with sync",
+			opts: CompareOptions{UseSyncOnDrop: true},
+			expected: map[string]string{
+				"analytics": "DROP DATABASE IF EXISTS `analytics` ON CLUSTER `production` SYNC;",
+				"local":     "DROP DATABASE IF EXISTS `local` SYNC;",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs, err := compareDatabases(current, target, tt.opts)
+			require.NoError(t, err)
+			require.Len(t, diffs, len(tt.expected))
+
+			for _, diff := range diffs {
+				require.Equal(t, string(DatabaseDiffDrop), diff.Type)
+				require.Equal(t, tt.expected[diff.Name], diff.UpSQL)
+
+				// The down migration must parse and recreate the original database
+				_, err := parser.ParseString(diff.UpSQL)
+				require.NoError(t, err)
+				_, err = parser.ParseString(diff.DownSQL)
+				require.NoError(t, err)
+			}
+
+			require.Equal(t,
+				"CREATE DATABASE `analytics` ON CLUSTER `production` ENGINE = Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') COMMENT 'Analytics database';",
+				diffs[0].DownSQL,
+			)
+			require.Equal(t, "CREATE DATABASE `local` ENGINE = Atomic;", diffs[1].DownSQL)
+		})
+	}
+}
+
+func TestCompareDatabases_CreateDownSync(t *testing.T) {
+	target, err := parser.ParseString("CREATE DATABASE analytics ON CLUSTER production ENGINE = Atomic;")
+	require.NoError(t, err)
+
+	diffs, err := compareDatabases(&parser.SQL{}, target, CompareOptions{UseSyncOnDrop: true})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, "DROP DATABASE IF EXISTS `analytics` ON CLUSTER `production` SYNC;", diffs[0].DownSQL)
+}
+
+func TestCompareDatabases_Alter(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		up      string
+		down    string
+	}{
+		{
+			name:    "comment changed",
+			current: "CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Old comment';",
+			target:  "CREATE DATABASE analytics ENGINE = Atomic COMMENT 'New comment';",
+			up:      "ALTER DATABASE `analytics` MODIFY COMMENT 'New comment';",
+			down:    "ALTER DATABASE `analytics` MODIFY COMMENT 'Old comment';",
+		},
+		{
+			name:    "comment added",
+			current: "CREATE DATABASE analytics ON CLUSTER production ENGINE = Atomic;",
+			target:  "CREATE DATABASE analytics ON CLUSTER production ENGINE = Atomic COMMENT 'Analytics';",
+			up:      "ALTER DATABASE `analytics` ON CLUSTER `production` MODIFY COMMENT 'Analytics';",
+			down:    "ALTER DATABASE `analytics` ON CLUSTER `production` MODIFY COMMENT '';",
+		},
+		{
+			name:    "settings changed",
+			current: "CREATE DATABASE analytics ENGINE = Replicated('/clickhouse/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 1;",
+			target:  "CREATE DATABASE analytics ENGINE = Replicated('/clickhouse/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;",
+			up:      "ALTER DATABASE `analytics` MODIFY SETTING max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;",
+			down:    "-- ClickHouse cannot reset database settings, revert manually: max_replication_lag_to_enqueue\nALTER DATABASE `analytics` MODIFY SETTING max_broken_tables_ratio = 1;",
+		},
+		{
+			name:    "settings added",
+			current: "CREATE DATABASE analytics ENGINE = Atomic;",
+			target:  "CREATE DATABASE analytics ENGINE = Atomic SETTINGS a = 1, b = 2;",
+			up:      "ALTER DATABASE `analytics` MODIFY SETTING a = 1, b = 2;",
+			down:    "-- ClickHouse cannot reset database settings, revert manually: a, b",
+		},
+		{
+			name:    "settings and comment changed",
+			current: "CREATE DATABASE analytics ENGINE = Atomic SETTINGS a = 1 COMMENT 'Old';",
+			target:  "CREATE DATABASE analytics ENGINE = Atomic SETTINGS a = 2 COMMENT 'New';",
+			up:      "ALTER DATABASE `analytics` MODIFY SETTING a = 2;\nALTER DATABASE `analytics` MODIFY COMMENT 'New';",
+			down:    "ALTER DATABASE `analytics` MODIFY SETTING a = 1;\nALTER DATABASE `analytics` MODIFY COMMENT 'Old';",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := parser.ParseString(tt.current)
+			require.NoError(t, err)
+			target, err := parser.ParseString(tt.target)
+			require.NoError(t, err)
+
+			diffs, err := compareDatabases(current, target, CompareOptions{})
+			require.NoError(t, err)
+			require.Len(t, diffs, 1)
+			require.Equal(t, string(DatabaseDiffAlter), diffs[0].Type)
+			require.Equal(t, tt.up, diffs[0].UpSQL)
+			require.Equal(t, tt.down, diffs[0].DownSQL)
+
+			_, err = parser.ParseString(diffs[0].UpSQL)
+			require.NoError(t, err)
+			_, err = parser.ParseString(diffs[0].DownSQL)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCompareDatabases_RemoveSetting(t *testing.T) {
+	current, err := parser.ParseString("CREATE DATABASE analytics ENGINE = Atomic SETTINGS a = 1, b = 2;")
+	require.NoError(t, err)
+	target, err := parser.ParseString("CREATE DATABASE analytics ENGINE = Atomic SETTINGS a = 1;")
+	require.NoError(t, err)
+
+	_, err = compareDatabases(current, target, CompareOptions{})
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.ErrorContains(t, err, "removing settings b from database 'analytics'")
+}
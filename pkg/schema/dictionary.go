@@ -3,6 +3,8 @@ package schema
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pseudomuto/housekeeper/pkg/parser"
@@ -42,6 +44,8 @@ type (
 		Cluster   string                       // Cluster name if specified (empty if not clustered)
 		Comment   string                       // Dictionary comment (without quotes)
 		Statement *parser.CreateDictionaryStmt // Full parsed CREATE DICTIONARY statement for deep comparison
+
+		strictComments bool // Compare comments exactly (see CompareOptions.StrictComments)
 	}
 )
 
@@ -85,11 +89,20 @@ func (d *DictionaryInfo) PropertiesMatch(other SchemaObject) bool {
 // operation instead of DROP+CREATE.
 //
 // Since dictionaries cannot be altered in ClickHouse, any modification requires CREATE OR REPLACE.
-func compareDictionaries(current, target *parser.SQL) ([]*DictionaryDiff, error) {
+func compareDictionaries(current, target *parser.SQL, opts CompareOptions) ([]*DictionaryDiff, error) {
 	// Extract dictionary information from both SQL structures
 	currentDicts := extractDictionaryInfo(current)
 	targetDicts := extractDictionaryInfo(target)
 
+	if opts.StrictComments {
+		for _, dict := range currentDicts {
+			dict.strictComments = true
+		}
+		for _, dict := range targetDicts {
+			dict.strictComments = true
+		}
+	}
+
 	// Pre-allocate diffs slice with estimated capacity
 	diffs := make([]*DictionaryDiff, 0, len(currentDicts)+len(targetDicts))
 
@@ -106,9 +119,10 @@ func compareDictionaries(current, target *parser.SQL) ([]*DictionaryDiff, error)
 				Name:        rename.OldName,
 				NewName:     rename.NewName,
 				Description: fmt.Sprintf("Rename dictionary '%s' to '%s'", rename.OldName, rename.NewName),
-				UpSQL:       generateRenameDictionarySQL(rename.OldName, rename.NewName, currentDict.Cluster),
-				DownSQL:     generateRenameDictionarySQL(rename.NewName, rename.OldName, currentDict.Cluster),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateRenameDictionarySQL(rename.OldName, rename.NewName, currentDict.Cluster),
+					generateRenameDictionarySQL(rename.NewName, rename.OldName, currentDict.Cluster)
+			}),
 			Current: currentDict,
 			Target:  targetDict,
 		}
@@ -132,9 +146,9 @@ func compareDictionaries(current, target *parser.SQL) ([]*DictionaryDiff, error)
 					Type:        string(DictionaryDiffCreate),
 					Name:        name,
 					Description: fmt.Sprintf("Create dictionary '%s'", name),
-					UpSQL:       generateCreateDictionarySQL(targetDict),
-					DownSQL:     generateDropDictionarySQL(targetDict),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateCreateDictionarySQL(targetDict), generateDropDictionarySQL(targetDict)
+				}),
 				Target: targetDict,
 			}
 			diffs = append(diffs, diff)
@@ -147,9 +161,9 @@ func compareDictionaries(current, target *parser.SQL) ([]*DictionaryDiff, error)
 						Type:        string(DictionaryDiffReplace),
 						Name:        name,
 						Description: fmt.Sprintf("Replace dictionary '%s'", name),
-						UpSQL:       generateReplaceDictionarySQL(targetDict),
-						DownSQL:     generateReplaceDictionarySQL(currentDict),
-					},
+					}.withSQL(opts, func() (string, string) {
+						return generateReplaceDictionarySQL(targetDict), generateReplaceDictionarySQL(currentDict)
+					}),
 					Current: currentDict,
 					Target:  targetDict,
 				}
@@ -172,9 +186,9 @@ func compareDictionaries(current, target *parser.SQL) ([]*DictionaryDiff, error)
 				Type:        string(DictionaryDiffDrop),
 				Name:        name,
 				Description: fmt.Sprintf("Drop dictionary '%s'", name),
-				UpSQL:       generateDropDictionarySQL(currentDict),
-				DownSQL:     generateCreateDictionarySQL(currentDict),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropDictionarySQL(currentDict), generateCreateDictionarySQL(currentDict)
+			}),
 			Current: currentDict,
 		}
 		diffs = append(diffs, diff)
@@ -223,7 +237,7 @@ func extractDictionaryInfo(sql *parser.SQL) map[string]*DictionaryInfo {
 // dictionaryPropertiesMatch checks if two dictionaries have identical properties (excluding name)
 func dictionaryPropertiesMatch(dict1, dict2 *DictionaryInfo) bool {
 	// Compare basic metadata (excluding name) with normalized comment comparison
-	if !commentsEqual(dict1.Comment, dict2.Comment) ||
+	if !commentsEqual(dict1.strictComments || dict2.strictComments, dict1.Comment, dict2.Comment) ||
 		dict1.Database != dict2.Database {
 		return false
 	}
@@ -250,7 +264,7 @@ func generateRenameDictionarySQL(oldName, newName, onCluster string) string {
 // This compares the essential properties that would require a CREATE OR REPLACE
 func needsDictionaryModification(current, target *DictionaryInfo) bool {
 	// Basic metadata comparison with normalized comment comparison
-	if !commentsEqual(current.Comment, target.Comment) ||
+	if !commentsEqual(current.strictComments || target.strictComments, current.Comment, target.Comment) ||
 		current.Database != target.Database {
 		return true
 	}
@@ -393,8 +407,10 @@ func dictionaryPrimaryKeyEqual(a, b *parser.DictionaryPrimaryKey) bool {
 	if len(a.Keys) != len(b.Keys) {
 		return false
 	}
+	// Key order is significant for composite keys, so compare positionally. Parentheses
+	// around the key list aren't retained by the parser and backticks are normalized away.
 	for i, keyA := range a.Keys {
-		if keyA != b.Keys[i] {
+		if normalizeIdentifier(keyA) != normalizeIdentifier(b.Keys[i]) {
 			return false
 		}
 	}
@@ -408,7 +424,122 @@ func dictionarySourceEqual(a, b *parser.DictionarySource) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return a.Name == b.Name && dictionaryParametersEqual(a.Parameters, b.Parameters)
+	if a.Name != b.Name {
+		return false
+	}
+
+	// The inline query of a CLICKHOUSE source is compared as a SELECT AST so that
+	// formatting differences between declared and dumped queries don't cause churn
+	if strings.EqualFold(a.Name, "CLICKHOUSE") {
+		queryA, paramsA := splitSourceQuery(a.Parameters)
+		queryB, paramsB := splitSourceQuery(b.Parameters)
+		return sourceQueriesEqual(queryA, queryB) && dictionaryParametersEqual(paramsA, paramsB)
+	}
+
+	return dictionaryParametersEqual(a.Parameters, b.Parameters)
+}
+
+// splitSourceQuery separates the query parameter of a dictionary source from its other parameters
+func splitSourceQuery(params []*parser.DictionaryParameter) (*parser.SimpleParameter, []*parser.DictionaryParameter) {
+	var (
+		query *parser.SimpleParameter
+		rest  = make([]*parser.DictionaryParameter, 0, len(params))
+	)
+
+	for _, param := range params {
+		if param.SimpleParam != nil && strings.EqualFold(param.SimpleParam.Name, "query") {
+			query = param.SimpleParam
+			continue
+		}
+		rest = append(rest, param)
+	}
+
+	return query, rest
+}
+
+// sourceQueriesEqual compares the inline queries of two CLICKHOUSE dictionary sources.
+// Queries that parse as SELECT statements are compared structurally, otherwise the
+// raw expressions are compared.
+func sourceQueriesEqual(a, b *parser.SimpleParameter) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	selectA, selectB := parseSourceQuery(&a.Expression), parseSourceQuery(&b.Expression)
+	if selectA != nil && selectB != nil {
+		return selectStatementsAreEqualAST(selectA, selectB)
+	}
+
+	return expressionEqual(a.Expression, b.Expression)
+}
+
+// stringLiteralPattern matches a single quoted string literal, mirroring the lexer's String token
+var stringLiteralPattern = regexp.MustCompile(`^'((?:[^'\\]|\\.)*)'$`)
+
+// parseSourceQuery parses a string literal query parameter as a SELECT statement,
+// returning nil when the expression isn't a string literal or isn't a valid SELECT
+func parseSourceQuery(expr *parser.Expression) *parser.SelectStatement {
+	match := stringLiteralPattern.FindStringSubmatch(strings.TrimSpace(expr.String()))
+	if match == nil {
+		return nil
+	}
+
+	query := unescapeStringLiteral(match[1])
+	sql, err := parser.ParseString(strings.TrimRight(strings.TrimSpace(query), ";") + ";")
+	if err != nil || len(sql.Statements) != 1 || sql.Statements[0].SelectStatement == nil {
+		return nil
+	}
+
+	return &sql.Statements[0].SelectStatement.SelectStatement
+}
+
+// stringLiteralEscapes maps the characters following a backslash in a string literal to the
+// characters they stand for. \xHH is handled separately.
+var stringLiteralEscapes = map[byte]byte{
+	'0':  0,
+	'a':  '\a',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+	'v':  '\v',
+	'\\': '\\',
+	'\'': '\'',
+	'"':  '"',
+	'`':  '`',
+}
+
+// unescapeStringLiteral decodes the escape sequences in the body of a string literal, so a
+// query dumped by the server (e.g. 'SELECT id\nFROM users') matches one declared with real
+// line breaks. Like ClickHouse, unrecognized sequences (e.g. \d in a regular expression) are
+// kept as is.
+func unescapeStringLiteral(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		next := s[i+1]
+		if c, ok := stringLiteralEscapes[next]; ok {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		if next == 'x' && i+3 < len(s) {
+			if b, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				sb.WriteByte(byte(b))
+				i += 3
+				continue
+			}
+		}
+
+		sb.WriteByte(s[i])
+	}
+
+	return sb.String()
 }
 
 func dictionaryLayoutEqual(a, b *parser.DictionaryLayout) bool {
@@ -497,16 +628,32 @@ func dictionarySettingsEqual(a, b *parser.DictionarySettings) bool {
 	if len(a.Settings) != len(b.Settings) {
 		return false
 	}
-	// Simple comparison - could be enhanced with order-independent comparison
-	for i, settingA := range a.Settings {
-		settingB := b.Settings[i]
-		if settingA.Name != settingB.Name || settingA.Value != settingB.Value {
+
+	// Settings are compared by name so a reordered SETTINGS clause isn't treated as a change
+	aSettings := dictionarySettingsMap(a)
+	bSettings := dictionarySettingsMap(b)
+	if len(aSettings) != len(bSettings) {
+		return false
+	}
+
+	for name, valueA := range aSettings {
+		valueB, exists := bSettings[name]
+		if !exists || valueA != valueB {
 			return false
 		}
 	}
 	return true
 }
 
+// dictionarySettingsMap returns the dictionary settings keyed by name
+func dictionarySettingsMap(settings *parser.DictionarySettings) map[string]string {
+	m := make(map[string]string, len(settings.Settings))
+	for _, setting := range settings.Settings {
+		m[setting.Name] = setting.Value
+	}
+	return m
+}
+
 func dictionaryParametersEqual(a, b []*parser.DictionaryParameter) bool {
 	if len(a) != len(b) {
 		return false
@@ -781,7 +928,7 @@ func buildDictionaryColumns(parts []string, stmt *parser.CreateDictionaryStmt) [
 				columnStr += " " + col.Default.Type + " " + col.Default.GetValue()
 			}
 
-			// Add attributes (IS_OBJECT_ID, HIERARCHICAL, INJECTIVE)
+			// Add attributes (IS_OBJECT_ID, HIERARCHICAL, BIDIRECTIONAL, INJECTIVE)
 			var columnStrSb807 strings.Builder
 			for _, attr := range col.Attributes {
 				columnStrSb807.WriteString(" " + attr.Name)
@@ -823,11 +970,18 @@ func buildDictionaryOptions(parts []string, stmt *parser.CreateDictionaryStmt) [
 		}
 	}
 
-	// SETTINGS
+	// SETTINGS (sorted by name for stable output)
 	if settings := stmt.GetSettings(); settings != nil && len(settings.Settings) > 0 {
-		var settingStrs []string
-		for _, setting := range settings.Settings {
-			settingStrs = append(settingStrs, setting.Name+" = "+setting.Value)
+		settingsMap := dictionarySettingsMap(settings)
+		names := make([]string, 0, len(settingsMap))
+		for name := range settingsMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		settingStrs := make([]string, 0, len(names))
+		for _, name := range names {
+			settingStrs = append(settingStrs, name+" = "+settingsMap[name])
 		}
 		parts = append(parts, "SETTINGS("+strings.Join(settingStrs, ", ")+")")
 	}
@@ -872,9 +1026,12 @@ func buildLayoutClause(layout *parser.DictionaryLayout) string {
 	return layoutStr + ")"
 }
 
-// commentsEqual compares two comments with normalization for SQL keywords
+// commentsEqual compares two comments with normalization for SQL keywords unless strict
 // ClickHouse normalizes keywords even within string literals, so we need to handle this
-func commentsEqual(comment1, comment2 string) bool {
+func commentsEqual(strict bool, comment1, comment2 string) bool {
+	if strict {
+		return comment1 == comment2
+	}
 	return normalizeComment(comment1) == normalizeComment(comment2)
 }
 
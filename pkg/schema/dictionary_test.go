@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnescapeStringLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		literal  string
+		expected string
+	}{
+		{name: "line breaks", literal: `SELECT id\nFROM users\r\n\tWHERE 1`, expected: "SELECT id\nFROM users\r\n\tWHERE 1"},
+		{name: "quotes", literal: `status = \'active\' AND note = \"x\"`, expected: `status = 'active' AND note = "x"`},
+		{name: "backslash", literal: `path = 'C:\\tmp'`, expected: `path = 'C:\tmp'`},
+		{name: "null and hex", literal: `a\0b\x41\x7e`, expected: "a\x00bA~"},
+		{name: "invalid hex", literal: `\xZZ`, expected: `\xZZ`},
+		{name: "unrecognized", literal: `match(name, '\d+')`, expected: `match(name, '\d+')`},
+		{name: "trailing backslash", literal: `abc\`, expected: `abc\`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, unescapeStringLiteral(tt.literal))
+		})
+	}
+}
+
+func TestCompareDictionaries_SourceQueryLineBreaks(t *testing.T) {
+	dumped, err := parser.ParseString(`CREATE DICTIONARY analytics.active_users (id UInt64, name String) PRIMARY KEY id ` +
+		`SOURCE(CLICKHOUSE(query 'SELECT id, name\nFROM analytics.users\nWHERE status = \'active\'' user 'default')) LAYOUT(HASHED()) LIFETIME(300);`)
+	require.NoError(t, err)
+
+	declared, err := parser.ParseString(`CREATE DICTIONARY analytics.active_users (id UInt64, name String)
+PRIMARY KEY id
+SOURCE(CLICKHOUSE(
+    query 'SELECT id, name
+FROM analytics.users
+WHERE status = \'active\''
+    user 'default'
+))
+LAYOUT(HASHED())
+LIFETIME(300);`)
+	require.NoError(t, err)
+
+	diffs, err := compareDictionaries(dumped, declared, CompareOptions{})
+	require.NoError(t, err)
+	require.Empty(t, diffs, "escaped line breaks should match real ones")
+
+	changed, err := parser.ParseString(`CREATE DICTIONARY analytics.active_users (id UInt64, name String) PRIMARY KEY id ` +
+		`SOURCE(CLICKHOUSE(query 'SELECT id, name\nFROM analytics.users\nWHERE status = \'inactive\'' user 'default')) LAYOUT(HASHED()) LIFETIME(300);`)
+	require.NoError(t, err)
+
+	diffs, err = compareDictionaries(dumped, changed, CompareOptions{})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+}
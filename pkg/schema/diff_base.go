@@ -4,7 +4,7 @@ package schema
 // (DatabaseDiff, TableDiff, DictionaryDiff, ViewDiff, FunctionDiff, RoleDiff).
 //
 // Embedding this struct in diff types eliminates the need to implement
-// GetDiffType() and GetUpSQL() methods on each type individually.
+// GetDiffType(), GetUpSQL(), and GetDownSQL() methods on each type individually.
 //
 // Example usage:
 //
@@ -41,3 +41,23 @@ func (d *DiffBase) GetDiffType() string {
 func (d *DiffBase) GetUpSQL() string {
 	return d.UpSQL
 }
+
+// GetDownSQL implements diffProcessor interface
+func (d *DiffBase) GetDownSQL() string {
+	return d.DownSQL
+}
+
+// withSQL returns the diff with the UpSQL and DownSQL returned by generate. When opts only
+// compares metadata (see CompareMetadataWithOptions), generate isn't called and the diff
+// has no SQL.
+func (d DiffBase) withSQL(opts CompareOptions, generate func() (up, down string)) DiffBase {
+	if !opts.metadataOnly {
+		d.UpSQL, d.DownSQL = generate()
+	}
+	return d
+}
+
+// base returns the common fields of a diff (see CompareMetadata)
+func (d *DiffBase) base() *DiffBase {
+	return d
+}
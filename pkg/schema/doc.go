@@ -62,6 +62,27 @@
 //	format.FormatSQL(&buf, format.Defaults, diff)
 //	os.WriteFile(migrationFile, buf.Bytes(), consts.ModeFile)
 //
+// When only what changed matters (e.g. for a change summary or approval workflow),
+// CompareMetadata returns the changes with their classifications instead, skipping the
+// costly steps of generating and parsing the migration statements:
+//
+//	changes, err := schema.CompareMetadata(currentSQL, targetSQL)
+//	for _, change := range changes {
+//	    fmt.Printf("%s %s %s (destructive: %t)\n", change.Type, change.ObjectType, change.Name, change.Destructive)
+//	}
+//
+// Changes.Classify splits them into additive, destructive, and modifying changes, e.g.
+// to auto-approve migrations that only add to the schema:
+//
+//	_, destructive, modifying := changes.Classify()
+//	autoApprove := len(destructive) == 0 && len(modifying) == 0
+//
+// ScoreDiff turns them into a low, medium, or high RiskScore, optionally weighing
+// mutations by the live row counts of the tables they rewrite:
+//
+//	report := schema.ScoreDiff(changes, schema.TableRows{"analytics.events": 250_000_000})
+//	fmt.Printf("risk: %s (weight %d)\n", report.Score, report.Weight)
+//
 // The package will return errors for operations that cannot be safely
 // automated, such as database engine changes or cluster modifications.
 // For integration engines and materialized views, it automatically uses
@@ -13,7 +13,7 @@ const (
 	FunctionDiffCreate FunctionDiffType = "CREATE"
 	// FunctionDiffDrop indicates a function needs to be dropped
 	FunctionDiffDrop FunctionDiffType = "DROP"
-	// FunctionDiffReplace indicates a function needs to be replaced (functions use DROP+CREATE for modifications)
+	// FunctionDiffReplace indicates a function needs to be replaced (functions use CREATE OR REPLACE for modifications)
 	FunctionDiffReplace FunctionDiffType = "REPLACE"
 	// FunctionDiffRename indicates a function needs to be renamed
 	FunctionDiffRename FunctionDiffType = "RENAME"
@@ -72,14 +72,14 @@ func (f *FunctionInfo) PropertiesMatch(other SchemaObject) bool {
 //   - Functions that need to be replaced (exist in both but have differences)
 //   - Functions that need to be renamed (same properties but different names)
 //
-// Note: ClickHouse functions use DROP+CREATE strategy for modifications since
+// Note: ClickHouse functions use CREATE OR REPLACE for modifications since
 // ALTER FUNCTION doesn't exist.
 //
 // Rename Detection:
 // The function intelligently detects rename operations by comparing function properties
 // (parameters, expression, cluster) excluding the name. If two functions have identical
 // properties but different names, it generates a RENAME operation instead of DROP+CREATE.
-func compareFunctions(current, target *parser.SQL) []*FunctionDiff {
+func compareFunctions(current, target *parser.SQL, opts CompareOptions) []*FunctionDiff {
 	// Extract function information from both SQL structures
 	currentMap := extractFunctionInfoAsMap(current)
 	targetMap := extractFunctionInfoAsMap(target)
@@ -101,9 +101,10 @@ func compareFunctions(current, target *parser.SQL) []*FunctionDiff {
 				NewName:     rename.NewName,
 				Description: fmt.Sprintf("Rename function %s to %s", rename.OldName, rename.NewName),
 				// Functions don't have RENAME support, use DROP+CREATE
-				UpSQL:   generateDropFunctionSQL(currentFn) + "\n" + generateCreateFunctionSQL(targetFn),
-				DownSQL: generateDropFunctionSQL(targetFn) + "\n" + generateCreateFunctionSQL(currentFn),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropFunctionSQL(currentFn) + "\n" + generateCreateFunctionSQL(targetFn),
+					generateDropFunctionSQL(targetFn) + "\n" + generateCreateFunctionSQL(currentFn)
+			}),
 			Current: currentFn,
 			Target:  targetFn,
 		}
@@ -122,9 +123,9 @@ func compareFunctions(current, target *parser.SQL) []*FunctionDiff {
 					Type:        string(FunctionDiffCreate),
 					Name:        name,
 					Description: "Create function " + name,
-					UpSQL:       generateCreateFunctionSQL(targetFn),
-					DownSQL:     generateDropFunctionSQL(targetFn),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateCreateFunctionSQL(targetFn), generateDropFunctionSQL(targetFn)
+				}),
 				Target: targetFn,
 			}
 			diffs = append(diffs, diff)
@@ -135,9 +136,9 @@ func compareFunctions(current, target *parser.SQL) []*FunctionDiff {
 					Type:        string(FunctionDiffReplace),
 					Name:        name,
 					Description: "Replace function " + name,
-					UpSQL:       generateCreateFunctionSQL(targetFn),
-					DownSQL:     generateCreateFunctionSQL(currentFn),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateReplaceFunctionSQL(targetFn), generateReplaceFunctionSQL(currentFn)
+				}),
 				Current: currentFn,
 				Target:  targetFn,
 			}
@@ -153,9 +154,9 @@ func compareFunctions(current, target *parser.SQL) []*FunctionDiff {
 				Type:        string(FunctionDiffDrop),
 				Name:        name,
 				Description: "Drop function " + name,
-				UpSQL:       generateDropFunctionSQL(currentFn),
-				DownSQL:     generateCreateFunctionSQL(currentFn),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropFunctionSQL(currentFn), generateCreateFunctionSQL(currentFn)
+			}),
 			Current: currentFn,
 		}
 		diffs = append(diffs, diff)
@@ -227,6 +228,26 @@ func functionsEqualIgnoringName(a, b *FunctionInfo) bool {
 
 // generateCreateFunctionSQL generates CREATE FUNCTION SQL statement
 func generateCreateFunctionSQL(fn *FunctionInfo) string {
+	return utils.NewSQLBuilder().
+		Create("FUNCTION").
+		Name(fn.Name).
+		OnCluster(fn.Cluster).
+		As(functionLambda(fn)).
+		String()
+}
+
+// generateReplaceFunctionSQL generates CREATE OR REPLACE FUNCTION SQL statement
+func generateReplaceFunctionSQL(fn *FunctionInfo) string {
+	return utils.NewSQLBuilder().
+		CreateOrReplace("FUNCTION").
+		Name(fn.Name).
+		OnCluster(fn.Cluster).
+		As(functionLambda(fn)).
+		String()
+}
+
+// functionLambda returns the lambda defining a function, e.g. (`x`, `k`) -> k * x
+func functionLambda(fn *FunctionInfo) string {
 	// Build parameter string
 	paramStr := "("
 	var paramStrSb168 strings.Builder
@@ -240,14 +261,7 @@ func generateCreateFunctionSQL(fn *FunctionInfo) string {
 	paramStr += ")"
 
 	// Build full AS expression
-	asExpression := fmt.Sprintf("%s -> %v", paramStr, fn.Expression)
-
-	return utils.NewSQLBuilder().
-		Create("FUNCTION").
-		Name(fn.Name).
-		OnCluster(fn.Cluster).
-		As(asExpression).
-		String()
+	return fmt.Sprintf("%s -> %v", paramStr, fn.Expression)
 }
 
 // generateDropFunctionSQL generates DROP FUNCTION SQL statement
@@ -3,9 +3,13 @@ package schema
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -33,14 +37,43 @@ var (
 	ErrInvalidType = errors.New("invalid type combination")
 	// ErrInvalidClause is returned when unsupported clauses are used with specific engines
 	ErrInvalidClause = errors.New("invalid clause for engine type")
+	// ErrInvalidSampleBy is returned when a SAMPLE BY expression would be rejected by ClickHouse
+	ErrInvalidSampleBy = errors.New("invalid SAMPLE BY expression")
 )
 
+// ErrDestructiveOperation is returned when a schema change could only be applied by
+// dropping and recreating an object (losing its data), such as changing a table's engine.
+// It wraps ErrUnsupported, so errors.Is(err, ErrUnsupported) continues to match.
+//
+// Example:
+//
+//	var destructive *schema.ErrDestructiveOperation
+//	if errors.As(err, &destructive) {
+//		fmt.Printf("refusing to recreate %s %s\n", destructive.ObjectType, destructive.Object)
+//	}
+type ErrDestructiveOperation struct {
+	ObjectType string // Type of the affected object (table, database, ...)
+	Object     string // Qualified name of the affected object
+	Err        error  // Underlying cause
+}
+
+// Error implements the error interface.
+func (e *ErrDestructiveOperation) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying cause.
+func (e *ErrDestructiveOperation) Unwrap() error {
+	return e.Err
+}
+
 // diffProcessor defines the interface needed for generic diff processing.
 // This interface is satisfied implicitly by all diff types without requiring
 // explicit method implementations.
 type diffProcessor interface {
 	GetDiffType() string // Returns the operation type (CREATE, ALTER, DROP, RENAME, etc.)
 	GetUpSQL() string    // Returns the forward migration SQL
+	GetDownSQL() string  // Returns the SQL reversing the forward migration
 }
 
 // Processing order configurations for each object type.
@@ -59,6 +92,10 @@ var (
 	// CREATE -> ALTER -> RENAME -> DROP
 	databaseProcessingOrder = []string{"CREATE", "ALTER", "RENAME", "DROP"}
 
+	// namedCollectionProcessingOrder defines the order for named collection operations
+	// CREATE -> ALTER -> DROP
+	namedCollectionProcessingOrder = []string{"CREATE", "ALTER", "DROP"}
+
 	// tableProcessingOrder defines the order for table operations
 	// CREATE -> ALTER -> RENAME -> DROP
 	tableProcessingOrder = []string{"CREATE", "ALTER", "RENAME", "DROP"}
@@ -94,14 +131,15 @@ func groupDiffsByType[T diffProcessor](diffs []T) map[string][]T {
 // Parameters:
 //   - groups: Map of diff type to slice of diffs (from groupDiffsByType)
 //   - order: Slice specifying the processing order (e.g., ["CREATE", "ALTER", "RENAME", "DROP"])
+//   - sqlFor: Selects the SQL of each diff (e.g., diffProcessor.GetUpSQL)
 //
 // Returns a slice of SQL statements in the correct order.
-func processDiffsInOrder[T diffProcessor](groups map[string][]T, order []string) []string {
+func processDiffsInOrder[T diffProcessor](groups map[string][]T, order []string, sqlFor func(diffProcessor) string) []string {
 	var statements []string
 	for _, diffType := range order {
 		if diffs, exists := groups[diffType]; exists {
 			for _, diff := range diffs {
-				statements = append(statements, diff.GetUpSQL())
+				statements = append(statements, sqlFor(diff))
 			}
 		}
 	}
@@ -114,11 +152,12 @@ func processDiffsInOrder[T diffProcessor](groups map[string][]T, order []string)
 // Parameters:
 //   - diffs: Slice of diffs to process
 //   - order: Processing order for the diff types
+//   - sqlFor: Selects the SQL of each diff
 //
 // Returns a slice of SQL statements in the correct order.
-func processAllDiffsInOrder[T diffProcessor](diffs []T, order []string) []string {
+func processAllDiffsInOrder[T diffProcessor](diffs []T, order []string, sqlFor func(diffProcessor) string) []string {
 	groups := groupDiffsByType(diffs)
-	return processDiffsInOrder(groups, order)
+	return processDiffsInOrder(groups, order, sqlFor)
 }
 
 // GenerateDiff creates a diff by comparing current and target schema states.
@@ -169,64 +208,228 @@ func processAllDiffsInOrder[T diffProcessor](diffs []T, order []string) []string
 //	var buf bytes.Buffer
 //	format.FormatSQL(&buf, format.Defaults, diff)
 //	fmt.Println(buf.String())
-//
-//nolint:gocyclo,funlen,maintidx,gocognit // Complex function handles multiple DDL statement types and migration ordering
 func GenerateDiff(current, target *parser.SQL) (*parser.SQL, error) {
-	// Compare databases and dictionaries to find differences
-	dbDiffs, err := compareDatabases(current, target)
+	return GenerateDiffWithOptions(current, target, CompareOptions{})
+}
+
+// CompareOptions controls how schema objects are compared by GenerateDiffWithOptions.
+// The zero value matches the behavior of GenerateDiff.
+type CompareOptions struct {
+	// StrictComments compares table, column, and dictionary comments exactly, so any
+	// edit to a comment generates a migration. By default comments are compared
+	// leniently: table and column comments ignore case, and dictionary comments
+	// ignore the case of SQL keywords (FROM, AS, BY, etc.) that ClickHouse may
+	// normalize. Database comments are always compared exactly.
+	StrictComments bool
+
+	// Concurrency is the maximum number of object types (tables, views, dictionaries,
+	// etc.) compared at the same time. The per-type comparisons operate on disjoint
+	// statements, so running them concurrently can speed up diffing very large
+	// schemas. Values of 0 or 1 compare each type serially. The generated diff is
+	// identical regardless of this setting.
+	Concurrency int
+
+	// UseSyncOnDrop appends SYNC to generated DROP DATABASE statements so the drop
+	// waits for the database's data to be removed before returning. This is mostly
+	// useful for Replicated databases, where the database is typically recreated
+	// or renamed shortly after being dropped.
+	UseSyncOnDrop bool
+
+	// PreferReplaceTable emits a single CREATE OR REPLACE TABLE, instead of DROP TABLE
+	// followed by CREATE TABLE, when a table has to be recreated (e.g. a change to its
+	// integration engine settings or ReplicatedMergeTree parameters). The replace is
+	// atomic, so there is no window where the table doesn't exist. Streaming engines
+	// (Kafka, RabbitMQ, NATS, S3Queue, AzureQueue) don't support it and always use
+	// DROP+CREATE. The replace requires an Atomic or Replicated database.
+	PreferReplaceTable bool
+
+	// DefaultCodec is the compression codec (e.g. "ZSTD(1)") the server uses for columns
+	// declared without a CODEC, as configured in the compression section of its config.
+	// A column without a codec matches a column whose codec is the default, so a dumped
+	// schema that reports the default codec doesn't produce spurious MODIFY COLUMNs.
+	// Defaults to LZ4, ClickHouse's built-in default.
+	DefaultCodec string
+
+	// DetectColumnRenames emits RENAME COLUMN, instead of DROP COLUMN followed by ADD
+	// COLUMN, when a dropped column and an added column of the same table have identical
+	// properties (type, default, codec, TTL, comment, and settings) and neither matches any
+	// other dropped or added column. DEFAULT, MATERIALIZED, and ALIAS expressions
+	// referencing a renamed column are compared as ClickHouse rewrites them.
+	//
+	// Columns of the same type are common, so an unrelated column replacing a dropped one
+	// would be renamed too, keeping the dropped column's data. Only enable this when the
+	// columns that changed name in the target schema are known to be renames.
+	DetectColumnRenames bool
+
+	// IncludeSQL fills in the UpSQL and DownSQL of the changes returned by
+	// CompareMetadataWithOptions. Without it, no SQL is generated for the changes at all.
+	// GenerateDiffWithOptions always generates the SQL of the migration.
+	IncludeSQL bool
+
+	// metadataOnly skips generating the SQL of the diffs, which CompareMetadataWithOptions
+	// sets unless IncludeSQL is
+	metadataOnly bool
+}
+
+// GenerateDiffWithOptions works like GenerateDiff, comparing schema objects according
+// to the given options.
+//
+// Example:
+//
+//	// Generate a migration for comment edits that only change case
+//	diff, err := GenerateDiffWithOptions(current, target, CompareOptions{StrictComments: true})
+func GenerateDiffWithOptions(current, target *parser.SQL, opts CompareOptions) (*parser.SQL, error) {
+	diffs, err := compareSchemas(current, target, opts)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to compare databases")
+		return nil, err
 	}
 
-	dictDiffs, err := compareDictionaries(current, target)
+	return parseDiffStatements(diffs.statements(diffProcessor.GetUpSQL))
+}
+
+// Diff holds the statements migrating between two schemas in both directions.
+type Diff struct {
+	// Up migrates the current schema to the target schema
+	Up *parser.SQL
+
+	// Down reverses Up, migrating the target schema back to the current schema. It's
+	// nil when none of the changes in Up can be reversed.
+	Down *parser.SQL
+}
+
+// GenerateReversibleDiff works like GenerateDiffWithOptions, additionally generating
+// the statements that roll the migration back. The down statements undo each change
+// in the reverse order it was applied.
+//
+// Example:
+//
+//	diff, err := GenerateReversibleDiff(current, target, CompareOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	format.FormatSQL(upFile, format.Defaults, diff.Up)
+//	format.FormatSQL(downFile, format.Defaults, diff.Down)
+func GenerateReversibleDiff(current, target *parser.SQL, opts CompareOptions) (*Diff, error) {
+	diffs, err := compareSchemas(current, target, opts)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to compare dictionaries")
+		return nil, err
 	}
 
-	viewDiffs, err := compareViews(current, target)
+	up, err := parseDiffStatements(diffs.statements(diffProcessor.GetUpSQL))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to compare views")
+		return nil, err
 	}
 
-	tableDiffs, err := compareTables(current, target)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to compare tables")
+	downStatements := slices.DeleteFunc(diffs.statements(diffProcessor.GetDownSQL), func(sql string) bool {
+		return strings.TrimSpace(sql) == ""
+	})
+	slices.Reverse(downStatements)
+
+	down, err := parseDiffStatements(downStatements)
+	if err != nil && !errors.Is(err, ErrNoDiff) {
+		return nil, err
 	}
 
-	roleDiffs := compareRoles(current, target)
+	return &Diff{Up: up, Down: down}, nil
+}
 
-	functionDiffs := compareFunctions(current, target)
+// schemaDiffs holds the differences found for each type of schema object
+type schemaDiffs struct {
+	databases    []*DatabaseDiff
+	dictionaries []*DictionaryDiff
+	views        []*ViewDiff
+	tables       []*TableDiff
+	roles        []*RoleDiff
+	functions    []*FunctionDiff
+	collections  []*NamedCollectionDiff
+}
 
-	if len(dbDiffs) == 0 && len(dictDiffs) == 0 && len(viewDiffs) == 0 && len(tableDiffs) == 0 && len(roleDiffs) == 0 && len(functionDiffs) == 0 {
+// compareSchemas compares every type of schema object in current and target, returning
+// ErrNoDiff when nothing differs.
+func compareSchemas(current, target *parser.SQL, opts CompareOptions) (*schemaDiffs, error) {
+	diffs := &schemaDiffs{}
+
+	// Each comparison only reads the parsed schemas and writes its own result, so they
+	// can safely run concurrently
+	err := runComparisons(opts.Concurrency,
+		func() (err error) {
+			diffs.databases, err = compareDatabases(current, target, opts)
+			return errors.Wrap(err, "failed to compare databases")
+		},
+		func() (err error) {
+			diffs.dictionaries, err = compareDictionaries(current, target, opts)
+			return errors.Wrap(err, "failed to compare dictionaries")
+		},
+		func() (err error) {
+			diffs.views, err = compareViews(current, target, opts)
+			return errors.Wrap(err, "failed to compare views")
+		},
+		func() (err error) {
+			diffs.tables, err = compareTables(current, target, opts)
+			return errors.Wrap(err, "failed to compare tables")
+		},
+		func() error {
+			diffs.roles = compareRoles(current, target, opts)
+			return nil
+		},
+		func() error {
+			diffs.functions = compareFunctions(current, target, opts)
+			return nil
+		},
+		func() (err error) {
+			diffs.collections, err = compareNamedCollections(current, target, opts)
+			return errors.Wrap(err, "failed to compare named collections")
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(diffs.databases) == 0 && len(diffs.dictionaries) == 0 && len(diffs.views) == 0 &&
+		len(diffs.tables) == 0 && len(diffs.roles) == 0 && len(diffs.functions) == 0 &&
+		len(diffs.collections) == 0 {
 		return nil, ErrNoDiff
 	}
 
-	// Process diffs in proper order: roles first (global objects), then functions (global objects), then databases, then tables, then dictionaries, then views
+	return diffs, nil
+}
+
+// statements returns the SQL selected by sqlFor for every diff, in the order the
+// changes must be applied.
+func (d *schemaDiffs) statements(sqlFor func(diffProcessor) string) []string {
+	// Process diffs in proper order: roles first (global objects), then functions (global objects), then databases,
+	// then named collections (used by table and dictionary engines), then tables, then dictionaries, then views
 	// Within each type: CREATE first, then ALTER/REPLACE, then RENAME, then DROP/GRANT/REVOKE
 	statements := make([]string, 0, 50) // Pre-allocate with estimated capacity
 
-	// Process all diffs using generic diff processor in proper order:
-	// Roles first (global objects), then functions (global objects), then databases,
-	// then tables, then dictionaries, then views
-
 	// Process roles: CREATE -> ALTER -> RENAME -> GRANT -> REVOKE -> DROP
-	statements = append(statements, processAllDiffsInOrder(roleDiffs, roleProcessingOrder)...)
+	statements = append(statements, processAllDiffsInOrder(d.roles, roleProcessingOrder, sqlFor)...)
 
 	// Process functions: CREATE -> REPLACE -> RENAME -> DROP
-	statements = append(statements, processAllDiffsInOrder(functionDiffs, functionProcessingOrder)...)
+	statements = append(statements, processAllDiffsInOrder(d.functions, functionProcessingOrder, sqlFor)...)
 
 	// Process databases: CREATE -> ALTER -> RENAME -> DROP
-	statements = append(statements, processAllDiffsInOrder(dbDiffs, databaseProcessingOrder)...)
+	statements = append(statements, processAllDiffsInOrder(d.databases, databaseProcessingOrder, sqlFor)...)
+
+	// Process named collections: CREATE -> ALTER -> DROP
+	statements = append(statements, processAllDiffsInOrder(d.collections, namedCollectionProcessingOrder, sqlFor)...)
 
 	// Process tables: CREATE -> ALTER -> RENAME -> DROP
-	statements = append(statements, processAllDiffsInOrder(tableDiffs, tableProcessingOrder)...)
+	statements = append(statements, processAllDiffsInOrder(d.tables, tableProcessingOrder, sqlFor)...)
 
 	// Process dictionaries: CREATE -> REPLACE -> RENAME -> DROP
-	statements = append(statements, processAllDiffsInOrder(dictDiffs, dictionaryProcessingOrder)...)
+	statements = append(statements, processAllDiffsInOrder(d.dictionaries, dictionaryProcessingOrder, sqlFor)...)
 
 	// Process views: CREATE -> ALTER -> RENAME -> DROP
-	statements = append(statements, processAllDiffsInOrder(viewDiffs, viewProcessingOrder)...)
+	statements = append(statements, processAllDiffsInOrder(d.views, viewProcessingOrder, sqlFor)...)
 
+	return statements
+}
+
+// parseDiffStatements parses generated SQL statements back into *parser.SQL, returning
+// ErrNoDiff when there are no statements.
+func parseDiffStatements(statements []string) (*parser.SQL, error) {
 	// Split any statements that contain multiple SQL statements (separated by \n\n)
 	// and ensure each individual SQL statement ends with a semicolon
 	var processedStatements []string
@@ -262,6 +465,45 @@ func GenerateDiff(current, target *parser.SQL) (*parser.SQL, error) {
 	return parsedSQL, nil
 }
 
+// runComparisons runs the given comparisons with at most concurrency of them in
+// flight, returning the error of the first failing comparison (in argument order).
+// When concurrency is less than 2 the comparisons run serially, stopping at the
+// first error.
+func runComparisons(concurrency int, comparisons ...func() error) error {
+	if concurrency < 2 {
+		for _, compare := range comparisons {
+			if err := compare(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(comparisons))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, compare := range comparisons {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[i] = compare()
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GenerateMigrationFile creates a timestamped migration file by comparing current and target schemas.
 // The migration file is named using UTC timestamp in yyyyMMddhhmmss format and written to the specified directory.
 // Returns the generated filename and any error encountered.
@@ -280,14 +522,76 @@ func GenerateDiff(current, target *parser.SQL) (*parser.SQL, error) {
 //	filename, err := GenerateMigrationFile("/path/to/migrations", currentSchema, targetSchema)
 //	// Creates: /path/to/migrations/20240806143022.sql
 func GenerateMigrationFile(migrationDir string, current, target *parser.SQL) (string, error) {
-	// Generate diff using existing function
-	diff, err := GenerateDiff(current, target)
-	if err != nil {
+	return GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{})
+}
+
+// migrationNamePattern matches the names that may be appended to a migration's timestamp
+var migrationNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// GenerateMigrationFileWithOptions creates a timestamped migration file like GenerateMigrationFile,
+// additionally rendering opts.HeaderTemplate as a block of SQL comments at the top of the file.
+// The header is made of comment lines only, so the migration still parses to the same statements.
+// When opts.Name is set, it's appended to the timestamp, e.g. "20240806143022_init.sql",
+// and when opts.Version is set, it replaces the generated version altogether.
+// When opts.SplitDown is set, the statements reverting the migration are written to a
+// paired down file, e.g. "20240806143022_init.down.sql".
+//
+// Example:
+//
+//	filename, err := GenerateMigrationFileWithOptions("/path/to/migrations", currentSchema, targetSchema,
+//		MigrationFileOptions{
+//			HeaderTemplate: "Generated by housekeeper {{ .ToolVersion }} at {{ .Timestamp }}",
+//			ToolVersion:    "v1.2.3",
+//		})
+func GenerateMigrationFileWithOptions(migrationDir string, current, target *parser.SQL, opts MigrationFileOptions) (string, error) {
+	if opts.Name != "" && !migrationNamePattern.MatchString(opts.Name) {
+		return "", errors.Errorf("invalid migration name %q: only letters, digits, underscores, and dashes are allowed", opts.Name)
+	}
+	if opts.Version != "" && !migrationNamePattern.MatchString(opts.Version) {
+		return "", errors.Errorf("invalid migration version %q: only letters, digits, underscores, and dashes are allowed", opts.Version)
+	}
+
+	// Generate diff using existing function, along with its reverse when it's written out
+	var (
+		diff, down *parser.SQL
+		err        error
+	)
+	if opts.SplitDown {
+		var reversible *Diff
+		if reversible, err = GenerateReversibleDiff(current, target, opts.Compare); err != nil {
+			return "", errors.Wrap(err, "failed to generate migration")
+		}
+		diff, down = reversible.Up, reversible.Down
+	} else if diff, err = GenerateDiffWithOptions(current, target, opts.Compare); err != nil {
 		return "", errors.Wrap(err, "failed to generate migration")
 	}
 
 	// Create timestamped filename using UTC
-	filename := time.Now().UTC().Format("20060102150405") + ".sql"
+	now := time.Now().UTC()
+	version := now.Format("20060102150405")
+	if opts.Name != "" {
+		version += "_" + opts.Name
+	}
+	if opts.Version != "" {
+		version = opts.Version
+	}
+	filename := version + ".sql"
+
+	var header string
+	if opts.HeaderTemplate != "" {
+		header, err = renderMigrationHeader(opts.HeaderTemplate, MigrationHeader{
+			Name:        filename,
+			Version:     version,
+			Timestamp:   now,
+			ToolVersion: opts.ToolVersion,
+			Environment: opts.Environment,
+			Description: opts.Description,
+			Stats:       newMigrationStats(diff),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
 
 	// Ensure migration directory exists
 	if err := os.MkdirAll(migrationDir, consts.ModeDir); err != nil {
@@ -296,8 +600,8 @@ func GenerateMigrationFile(migrationDir string, current, target *parser.SQL) (st
 
 	// Format the generated SQL using the formatter
 	var buf bytes.Buffer
-	err = format.FormatSQL(&buf, format.Defaults, diff)
-	if err != nil {
+	buf.WriteString(header)
+	if err := writeMigrationStatements(&buf, diff, opts.Sources); err != nil {
 		return "", errors.Wrap(err, "failed to format migration SQL")
 	}
 
@@ -309,5 +613,45 @@ func GenerateMigrationFile(migrationDir string, current, target *parser.SQL) (st
 		return "", errors.Wrapf(err, "failed to write migration file: %s", migrationPath)
 	}
 
+	if down != nil {
+		var downBuf bytes.Buffer
+		if err := format.FormatSQL(&downBuf, format.Defaults, down); err != nil {
+			return "", errors.Wrap(err, "failed to format down migration SQL")
+		}
+
+		downPath := filepath.Join(migrationDir, version+downFileSuffix)
+		if err := os.WriteFile(downPath, downBuf.Bytes(), consts.ModeFile); err != nil {
+			return "", errors.Wrapf(err, "failed to write down migration file: %s", downPath)
+		}
+	}
+
 	return filename, nil
 }
+
+// writeMigrationStatements formats the statements of a migration. When sources is set,
+// each statement whose object is found in it is preceded by a comment naming its file.
+func writeMigrationStatements(w io.Writer, sql *parser.SQL, sources SourceMap) error {
+	if sources == nil {
+		return format.FormatSQL(w, format.Defaults, sql)
+	}
+
+	for i, stmt := range sql.Statements {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n\n"); err != nil {
+				return err
+			}
+		}
+
+		if path, ok := sources.Lookup(stmt); ok {
+			if _, err := fmt.Fprintf(w, "-- from %s\n", filepath.ToSlash(path)); err != nil {
+				return err
+			}
+		}
+
+		if err := format.Format(w, format.Defaults, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
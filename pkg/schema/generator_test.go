@@ -0,0 +1,106 @@
+package schema_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	. "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDiffWithOptions_Concurrency(t *testing.T) {
+	current, target := largeMixedSchema(t, 20)
+
+	serial, err := GenerateDiffWithOptions(current, target, CompareOptions{})
+	require.NoError(t, err)
+
+	var expected bytes.Buffer
+	require.NoError(t, format.FormatSQL(&expected, format.Defaults, serial))
+
+	for _, concurrency := range []int{1, 2, 4, 16} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			diff, err := GenerateDiffWithOptions(current, target, CompareOptions{Concurrency: concurrency})
+			require.NoError(t, err)
+
+			var actual bytes.Buffer
+			require.NoError(t, format.FormatSQL(&actual, format.Defaults, diff))
+			require.Equal(t, expected.String(), actual.String())
+		})
+	}
+
+	t.Run("errors match serial comparison", func(t *testing.T) {
+		engineChange, err := parser.ParseString("CREATE DATABASE db0 ENGINE = Memory;")
+		require.NoError(t, err)
+
+		badTarget := &parser.SQL{Statements: append(engineChange.Statements, target.Statements[1:]...)}
+
+		_, serialErr := GenerateDiffWithOptions(current, badTarget, CompareOptions{})
+		require.Error(t, serialErr)
+
+		_, concurrentErr := GenerateDiffWithOptions(current, badTarget, CompareOptions{Concurrency: 4})
+		require.EqualError(t, concurrentErr, serialErr.Error())
+	})
+}
+
+func BenchmarkGenerateDiff(b *testing.B) {
+	current, target := largeMixedSchema(b, 200)
+
+	for _, concurrency := range []int{1, 6} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			opts := CompareOptions{Concurrency: concurrency}
+			for b.Loop() {
+				if _, err := GenerateDiffWithOptions(current, target, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// largeMixedSchema builds current and target schemas with n databases, each containing
+// tables, dictionaries, views, and materialized views. The target adds columns, replaces
+// dictionaries and views, and creates new objects so every comparison has work to do.
+func largeMixedSchema(tb testing.TB, n int) (*parser.SQL, *parser.SQL) {
+	tb.Helper()
+
+	var current, target strings.Builder
+	for i := range n {
+		db := fmt.Sprintf("db%d", i)
+		fmt.Fprintf(&current, "CREATE DATABASE %s ENGINE = Atomic;\n", db)
+		fmt.Fprintf(&target, "CREATE DATABASE %s ENGINE = Atomic COMMENT 'Database %d';\n", db, i)
+
+		for j := range 5 {
+			table := fmt.Sprintf("%s.table%d", db, j)
+			fmt.Fprintf(&current, "CREATE TABLE %s (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;\n", table)
+			fmt.Fprintf(&target, "CREATE TABLE %s (id UInt64, name String, created_at DateTime DEFAULT now()) ENGINE = MergeTree() ORDER BY id;\n", table)
+
+			fmt.Fprintf(&current,
+				"CREATE DICTIONARY %s.dict%d (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/%d')) LAYOUT(HASHED()) LIFETIME(300);\n",
+				db, j, j)
+			fmt.Fprintf(&target,
+				"CREATE DICTIONARY %s.dict%d (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/%d')) LAYOUT(HASHED()) LIFETIME(600);\n",
+				db, j, j)
+
+			fmt.Fprintf(&current, "CREATE VIEW %s.view%d AS SELECT id, name FROM %s;\n", db, j, table)
+			fmt.Fprintf(&target, "CREATE VIEW %s.view%d AS SELECT id, name, created_at FROM %s;\n", db, j, table)
+
+			mv := fmt.Sprintf("CREATE MATERIALIZED VIEW %s.mv%d ENGINE = MergeTree() ORDER BY id AS SELECT id, count() AS total FROM %s GROUP BY id;\n", db, j, table)
+			current.WriteString(mv)
+			target.WriteString(mv)
+		}
+
+		fmt.Fprintf(&target, "CREATE TABLE %s.new_table (id UInt64) ENGINE = MergeTree() ORDER BY id;\n", db)
+	}
+
+	currentSQL, err := parser.ParseString(current.String())
+	require.NoError(tb, err)
+
+	targetSQL, err := parser.ParseString(target.String())
+	require.NoError(tb, err)
+
+	return currentSQL, targetSQL
+}
@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+const (
+	// snapshotMarker mirrors the first-line marker the migrator uses to identify snapshot files
+	snapshotMarker = "-- housekeeper:snapshot"
+
+	// downFileSuffix mirrors the suffix the migrator uses to identify a migration's down file
+	downFileSuffix = ".down.sql"
+)
+
+type (
+	// MigrationFileOptions configures how GenerateMigrationFileWithOptions writes a migration.
+	MigrationFileOptions struct {
+		// HeaderTemplate is a text/template rendered at the top of the migration file.
+		// Every rendered line is written as a SQL comment, so the header never changes
+		// the statements in the migration. The template receives a MigrationHeader.
+		HeaderTemplate string
+
+		// ToolVersion is the housekeeper version exposed to the header template
+		ToolVersion string
+
+		// Environment is the environment name exposed to the header template
+		Environment string
+
+		// Description is a free-form description exposed to the header template
+		Description string
+
+		// Name, when set, is appended to the timestamp of the migration's filename,
+		// e.g. "init" produces 20240806143022_init.sql. It may only contain letters,
+		// digits, underscores, and dashes.
+		Name string
+
+		// Version, when set, is used as the migration's version in place of the timestamp
+		// and Name, e.g. to regenerate a pending migration under its original version.
+		// An existing migration with the same version is overwritten.
+		Version string
+
+		// Sources, when set, annotates each generated statement with a
+		// "-- from <path>" comment naming the schema file that defines its object
+		Sources SourceMap
+
+		// SplitDown writes the statements reverting the migration to a separate
+		// <version>.down.sql file next to it. No down file is written when none of
+		// the changes can be reversed.
+		SplitDown bool
+
+		// Compare controls how schema objects are compared when generating the migration
+		// (see GenerateDiffWithOptions)
+		Compare CompareOptions
+	}
+
+	// MigrationHeader is the data available to a migration header template.
+	//
+	// Example template:
+	//
+	//	Migration: {{ .Name }}
+	//	Generated: {{ .Timestamp.Format "2006-01-02T15:04:05Z07:00" }} by housekeeper {{ .ToolVersion }}
+	//	Changes: {{ .Stats.Creates }} create(s), {{ .Stats.Alters }} alter(s), {{ .Stats.Drops }} drop(s)
+	MigrationHeader struct {
+		Name        string         // Migration filename (e.g. "20240806143022.sql")
+		Version     string         // Migration version (the filename without its extension)
+		Timestamp   time.Time      // UTC time the migration was generated
+		ToolVersion string         // Housekeeper version that generated the migration
+		Environment string         // Environment the migration was generated for
+		Description string         // Free-form description of the migration
+		Stats       MigrationStats // Summary of the statements in the migration
+	}
+
+	// MigrationStats summarizes the statements in a generated migration.
+	MigrationStats struct {
+		Statements int // Total number of statements, excluding comments
+		Creates    int // CREATE statements
+		Alters     int // ALTER statements
+		Drops      int // DROP statements
+		Renames    int // RENAME statements
+	}
+)
+
+// newMigrationStats counts the statements in a generated migration by kind
+func newMigrationStats(sql *parser.SQL) MigrationStats {
+	var stats MigrationStats
+	for _, stmt := range sql.Statements {
+		if stmt.CommentStatement != nil {
+			continue
+		}
+
+		stats.Statements++
+		switch {
+		case stmt.CreateDatabase != nil, stmt.CreateTable != nil, stmt.CreateDictionary != nil,
+			stmt.CreateView != nil, stmt.CreateNamedCollection != nil, stmt.CreateRole != nil,
+			stmt.CreateUser != nil, stmt.CreateFunction != nil:
+			stats.Creates++
+		case stmt.AlterDatabase != nil, stmt.AlterTable != nil, stmt.AlterNamedCollection != nil,
+			stmt.AlterRole != nil, stmt.AlterUser != nil:
+			stats.Alters++
+		case stmt.DropDatabase != nil, stmt.DropTable != nil, stmt.DropDictionary != nil,
+			stmt.DropView != nil, stmt.DropNamedCollection != nil, stmt.DropRole != nil,
+			stmt.DropUser != nil, stmt.DropFunction != nil:
+			stats.Drops++
+		case stmt.RenameDatabase != nil, stmt.RenameTable != nil, stmt.RenameDictionary != nil:
+			stats.Renames++
+		}
+	}
+
+	return stats
+}
+
+// renderMigrationHeader executes the header template and converts the output into
+// SQL comment lines. Lines that are already comments are kept as-is.
+func renderMigrationHeader(tmpl string, data MigrationHeader) (string, error) {
+	t, err := template.New("header").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse migration header template")
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to render migration header template")
+	}
+
+	rendered := strings.TrimRight(buf.String(), "\n")
+	if strings.TrimSpace(rendered) == "" {
+		return "", nil
+	}
+
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "--"):
+			lines[i] = trimmed
+		case trimmed == "":
+			lines[i] = "--"
+		default:
+			lines[i] = "-- " + strings.TrimRight(line, " \t")
+		}
+	}
+
+	if lines[0] == snapshotMarker {
+		return "", errors.New("migration header must not start with the snapshot marker")
+	}
+
+	return strings.Join(lines, "\n") + "\n\n", nil
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+const (
+	// IndexDiffAdd indicates a data skipping index needs to be added
+	IndexDiffAdd IndexDiffType = "ADD"
+	// IndexDiffDrop indicates a data skipping index needs to be dropped
+	IndexDiffDrop IndexDiffType = "DROP"
+)
+
+// defaultIndexGranularity is the GRANULARITY ClickHouse uses when an index doesn't declare one
+const defaultIndexGranularity = "1"
+
+type (
+	// IndexInfo represents a data skipping index declared in a table definition
+	IndexInfo struct {
+		Name        string             // Index name
+		Expression  *parser.Expression // Indexed expression AST
+		Type        *parser.IndexType  // Index type AST
+		Granularity string             // Granularity (empty if not specified)
+	}
+
+	// IndexDiff represents a difference in data skipping index definitions. ClickHouse
+	// can't modify an index, so a changed index is expressed as a DROP followed by an ADD.
+	IndexDiff struct {
+		Type        IndexDiffType // Type of index operation
+		Index       *IndexInfo    // Index being added or dropped
+		Description string        // Human-readable description
+	}
+
+	// IndexDiffType represents the type of index difference
+	IndexDiffType string
+)
+
+// Equal compares two IndexInfo instances for equality. An omitted granularity matches
+// ClickHouse's default of 1, and redundant parentheses around the expression are ignored.
+func (i IndexInfo) Equal(other IndexInfo) bool {
+	return i.Name == other.Name &&
+		keyExpressionsEqual(i.Expression, other.Expression) &&
+		i.Type.String() == other.Type.String() &&
+		indexGranularity(i) == indexGranularity(other)
+}
+
+// indexGranularity returns the granularity of an index, defaulting to ClickHouse's
+func indexGranularity(index IndexInfo) string {
+	if index.Granularity == "" {
+		return defaultIndexGranularity
+	}
+	return index.Granularity
+}
+
+// newIndexInfo extracts index information from an index definition
+func newIndexInfo(def *parser.IndexDefinition) IndexInfo {
+	index := IndexInfo{
+		Name:       normalizeIdentifier(def.Name),
+		Expression: &def.Expression,
+		Type:       &def.IndexType,
+	}
+	if def.Granularity != nil {
+		index.Granularity = *def.Granularity
+	}
+	return index
+}
+
+// elementName returns the name the index is matched by (see tableElement)
+func (i IndexInfo) elementName() string {
+	return i.Name
+}
+
+// renameColumns returns the index with renamed columns replaced in its expression
+func (i IndexInfo) renameColumns(renames map[string]string) IndexInfo {
+	if i.Expression == nil || len(renames) == 0 {
+		return i
+	}
+
+	rewritten := copyRenamingIdentifiers(reflect.ValueOf(*i.Expression), renames).Interface().(parser.Expression)
+	i.Expression = &rewritten
+	return i
+}
+
+// newIndexDiff creates the IndexDiff adding or dropping an index (see compareTableElements)
+func newIndexDiff(add bool, index IndexInfo) IndexDiff {
+	if add {
+		return IndexDiff{Type: IndexDiffAdd, Index: &index, Description: "Add index " + index.Name}
+	}
+	return IndexDiff{Type: IndexDiffDrop, Index: &index, Description: "Drop index " + index.Name}
+}
+
+// change returns whether the index is added, and the index (see tableElementDiff)
+func (d IndexDiff) change() (bool, IndexInfo) {
+	return d.Type == IndexDiffAdd, *d.Index
+}
+
+// formatIndexDefinition formats an index for CREATE TABLE and ALTER TABLE ... ADD INDEX
+func formatIndexDefinition(index IndexInfo) string {
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "`%s` %s TYPE %s", index.Name, index.Expression.String(), index.Type.String())
+	if index.Granularity != "" {
+		sql.WriteString(" GRANULARITY ")
+		sql.WriteString(index.Granularity)
+	}
+	return sql.String()
+}
@@ -0,0 +1,469 @@
+package schema
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+const (
+	// ChangeAdditive classifies changes that only add to the schema: new objects, and
+	// new columns, indexes, constraints, and projections on existing tables
+	ChangeAdditive ChangeClass = "additive"
+	// ChangeDestructive classifies changes that remove an object or lose data (see
+	// Change.Destructive)
+	ChangeDestructive ChangeClass = "destructive"
+	// ChangeModifying classifies changes that alter existing objects in place without
+	// losing data, e.g. a changed comment, setting, view query, or widened column type
+	ChangeModifying ChangeClass = "modifying"
+)
+
+// ChangeClass classifies a change by its effect on the existing schema and data, e.g.
+// so that approval workflows can auto-approve purely additive migrations.
+type ChangeClass string
+
+// Change describes a single difference between two schemas, optionally along with the
+// SQL that applies and reverts it. Changes are returned by CompareMetadata.
+type Change struct {
+	// ObjectType is the kind of object that changed
+	ObjectType ObjectType
+
+	// Type is the operation type (CREATE, ALTER, DROP, RENAME, REPLACE, GRANT, REVOKE)
+	Type string
+
+	// Name is the name of the changed object
+	Name string
+
+	// NewName is the new name for rename operations (empty otherwise)
+	NewName string
+
+	// Description is a human-readable description of the change
+	Description string
+
+	// Destructive reports whether applying the change removes an object or loses data:
+	// it drops an object or column, narrows a column's type (see typeWidens), or
+	// recreates a table or materialized view that stores data. Everything else is safe
+	// to apply.
+	Destructive bool
+
+	// Class classifies the change as additive, destructive, or modifying. It's
+	// ChangeDestructive exactly when Destructive is set.
+	Class ChangeClass
+
+	// Mutation reports whether applying the change rewrites the existing data of a table,
+	// i.e. it changes the type of a column, which ClickHouse applies as a mutation
+	Mutation bool
+
+	// UpSQL is the unformatted SQL applying the change, as included in the migration
+	// generated by GenerateDiff. It's only set with CompareOptions.IncludeSQL.
+	UpSQL string
+
+	// DownSQL is the unformatted SQL reverting the change. It's a comment when the change
+	// can't be reverted automatically.
+	DownSQL string
+}
+
+// Changes is a list of changes returned by CompareMetadata.
+type Changes []Change
+
+// Classify buckets the changes into additive, destructive, and modifying changes (see
+// ChangeClass), preserving their order. A migration is purely additive when only the
+// first bucket has changes.
+//
+// Example:
+//
+//	additive, destructive, modifying := changes.Classify()
+//	switch {
+//	case len(destructive) > 0:
+//		fmt.Printf("%d destructive change(s) require sign-off\n", len(destructive))
+//	case len(modifying) == 0:
+//		fmt.Printf("auto-approving %d additive change(s)\n", len(additive))
+//	}
+func (c Changes) Classify() (additive, destructive, modifying Changes) {
+	for _, change := range c {
+		switch change.Class {
+		case ChangeAdditive:
+			additive = append(additive, change)
+		case ChangeDestructive:
+			destructive = append(destructive, change)
+		default:
+			modifying = append(modifying, change)
+		}
+	}
+
+	return additive, destructive, modifying
+}
+
+// CompareMetadata compares current and target like GenerateDiff, but returns a
+// structured summary of the changes instead of the migration statements. No SQL is
+// generated, parsed, or formatted, which makes this considerably faster for large
+// schemas when only what changed matters, e.g. for a quick "is there a change?" check
+// or a change summary in a UI.
+//
+// Changes are returned in the order GenerateDiff would apply them. It returns
+// ErrNoDiff when the schemas match, and the same errors as GenerateDiff for
+// unsupported changes.
+//
+// Example:
+//
+//	changes, err := schema.CompareMetadata(current, target)
+//	if errors.Is(err, schema.ErrNoDiff) {
+//		fmt.Println("schema is up to date")
+//		return nil
+//	}
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, change := range changes {
+//		if change.Destructive {
+//			fmt.Printf("⚠️  %s\n", change.Description)
+//		}
+//	}
+func CompareMetadata(current, target *parser.SQL) (Changes, error) {
+	return CompareMetadataWithOptions(current, target, CompareOptions{})
+}
+
+// CompareMetadataWithOptions works like CompareMetadata, comparing schema objects
+// according to the given options (see GenerateDiffWithOptions). With opts.IncludeSQL,
+// each change also carries the SQL that applies and reverts it.
+func CompareMetadataWithOptions(current, target *parser.SQL, opts CompareOptions) (Changes, error) {
+	opts.metadataOnly = !opts.IncludeSQL
+
+	diffs, err := compareSchemas(current, target, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffs.changes()
+	if len(changes) == 0 {
+		return nil, ErrNoDiff
+	}
+
+	return changes, nil
+}
+
+// changes returns a Change for every diff, in the order the changes must be applied
+// (see statements).
+func (d *schemaDiffs) changes() Changes {
+	changes := make(Changes, 0, len(d.roles)+len(d.functions)+len(d.databases)+
+		len(d.collections)+len(d.tables)+len(d.dictionaries)+len(d.views))
+
+	changes = appendChanges(changes, d.roles, roleProcessingOrder, func(diff *RoleDiff, change *Change) {
+		change.ObjectType = ObjectTypeRole
+		change.Class = objectChangeClass(diff.Type)
+	})
+
+	changes = appendChanges(changes, d.functions, functionProcessingOrder, func(diff *FunctionDiff, change *Change) {
+		change.ObjectType = ObjectTypeFunction
+		change.Class = objectChangeClass(diff.Type)
+	})
+
+	changes = appendChanges(changes, d.databases, databaseProcessingOrder, func(diff *DatabaseDiff, change *Change) {
+		change.ObjectType = ObjectTypeDatabase
+		change.Class = objectChangeClass(diff.Type)
+	})
+
+	changes = appendChanges(changes, d.collections, namedCollectionProcessingOrder, func(diff *NamedCollectionDiff, change *Change) {
+		change.ObjectType = ObjectTypeNamedCollection
+		change.Class = objectChangeClass(diff.Type)
+	})
+
+	changes = appendChanges(changes, d.tables, tableProcessingOrder, func(diff *TableDiff, change *Change) {
+		change.ObjectType = ObjectTypeTable
+		change.Class = tableChangeClass(diff)
+		change.Mutation = tableDiffIsMutation(diff)
+	})
+
+	changes = appendChanges(changes, d.dictionaries, dictionaryProcessingOrder, func(diff *DictionaryDiff, change *Change) {
+		change.ObjectType = ObjectTypeDictionary
+		change.Class = objectChangeClass(diff.Type)
+	})
+
+	changes = appendChanges(changes, d.views, viewProcessingOrder, func(diff *ViewDiff, change *Change) {
+		change.ObjectType = ObjectTypeView
+		if diff.IsMaterialized {
+			change.ObjectType = ObjectTypeMaterializedView
+		}
+		change.Class = viewChangeClass(diff)
+	})
+
+	return changes
+}
+
+// appendChanges appends a Change for each of the diffs in the given processing order,
+// letting classify fill in the details specific to the object type.
+func appendChanges[T interface {
+	diffProcessor
+	base() *DiffBase
+}](changes Changes, diffs []T, order []string, classify func(T, *Change)) Changes {
+	groups := groupDiffsByType(diffs)
+	for _, diffType := range order {
+		for _, diff := range groups[diffType] {
+			base := diff.base()
+			change := Change{
+				Type:        base.Type,
+				Name:        base.Name,
+				NewName:     base.NewName,
+				Description: base.Description,
+				UpSQL:       base.UpSQL,
+				DownSQL:     base.DownSQL,
+			}
+			classify(diff, &change)
+			change.Destructive = change.Class == ChangeDestructive
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// objectChangeClass classifies the diffs of objects that are only ever created,
+// dropped, or changed in place without losing data
+func objectChangeClass(diffType string) ChangeClass {
+	switch diffType {
+	case "CREATE":
+		return ChangeAdditive
+	case "DROP":
+		return ChangeDestructive
+	default:
+		return ChangeModifying
+	}
+}
+
+// tableChangeClass classifies a table diff. Altering a table is additive when it only
+// adds columns, indexes, constraints, or projections.
+func tableChangeClass(diff *TableDiff) ChangeClass {
+	switch {
+	case tableDiffIsDestructive(diff):
+		return ChangeDestructive
+	case diff.Type == string(TableDiffCreate):
+		return ChangeAdditive
+	case diff.Type == string(TableDiffAlter) && tableDiffOnlyAdds(diff):
+		return ChangeAdditive
+	default:
+		return ChangeModifying
+	}
+}
+
+// tableDiffOnlyAdds reports whether a table is altered in place only by adding columns,
+// indexes, constraints, and projections
+func tableDiffOnlyAdds(diff *TableDiff) bool {
+	if diff.Current == nil || diff.Target == nil || shouldUseDropCreate(diff.Current, diff.Target) {
+		return false
+	}
+
+	for _, change := range diff.ColumnChanges {
+		if change.Type != ColumnDiffAdd {
+			return false
+		}
+	}
+	for _, change := range diff.IndexChanges {
+		if change.Type != IndexDiffAdd {
+			return false
+		}
+	}
+	for _, change := range diff.ConstraintChanges {
+		if change.Type != ConstraintDiffAdd {
+			return false
+		}
+	}
+	for _, change := range diff.ProjectionChanges {
+		if change.Type != ProjectionDiffAdd {
+			return false
+		}
+	}
+
+	return len(tablePropertyChanges(diff.Current, diff.Target)) == 0
+}
+
+// tableDiffIsMutation reports whether altering a table changes the type of any of its
+// columns, which rewrites every part containing the column
+func tableDiffIsMutation(diff *TableDiff) bool {
+	if diff.Type != string(TableDiffAlter) {
+		return false
+	}
+
+	for _, change := range diff.ColumnChanges {
+		if change.Type == ColumnDiffModify && !columnDataType(*change.Current).Equal(columnDataType(*change.Target)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// viewChangeClass classifies a view diff
+func viewChangeClass(diff *ViewDiff) ChangeClass {
+	if viewDiffIsDestructive(diff) {
+		return ChangeDestructive
+	}
+	return objectChangeClass(diff.Type)
+}
+
+// tableDiffIsDestructive reports whether a table diff drops the table or any of its
+// columns, narrows the type of a column, or recreates a table that stores data
+func tableDiffIsDestructive(diff *TableDiff) bool {
+	switch diff.Type {
+	case string(TableDiffDrop):
+		return true
+	case string(TableDiffAlter):
+		for _, change := range diff.ColumnChanges {
+			if change.Type == ColumnDiffDrop {
+				return true
+			}
+			if change.Type == ColumnDiffModify && !typeWidens(columnDataType(*change.Current), columnDataType(*change.Target)) {
+				return true
+			}
+		}
+
+		return diff.Current != nil && diff.Target != nil &&
+			shouldUseDropCreate(diff.Current, diff.Target) &&
+			!isViewLikeEngine(diff.Current.Engine)
+	default:
+		return false
+	}
+}
+
+// viewDiffIsDestructive reports whether a view diff drops the view, or recreates a
+// materialized view that stores its data in an inner table (i.e. has no TO table)
+func viewDiffIsDestructive(diff *ViewDiff) bool {
+	switch diff.Type {
+	case string(ViewDiffDrop):
+		return true
+	case string(ViewDiffAlter):
+		return diff.IsMaterialized &&
+			diff.Current != nil && diff.Current.Statement != nil &&
+			diff.Current.Statement.To == nil
+	default:
+		return false
+	}
+}
+
+// columnDataType returns the declared type of a column, or the type inferred from its
+// default expression when the type is omitted
+func columnDataType(col ColumnInfo) *parser.DataType {
+	if col.DataType != nil {
+		return col.DataType
+	}
+	return parser.InferDataType(col.Default)
+}
+
+// typeWidens reports whether every value of type from converts to type to without loss,
+// so that changing a column's type from one to the other keeps its data intact. Only
+// well-known lossless conversions are recognized, e.g. widening integers, wrapping a type
+// in Nullable, or converting anything to String. Any other type change, including ones
+// that can't be determined, is considered narrowing.
+func typeWidens(from, to *parser.DataType) bool {
+	if from == nil || to == nil {
+		return from == nil && to == nil
+	}
+	if from.Equal(to) {
+		return true
+	}
+
+	// LowCardinality only changes how values are stored
+	if from.LowCardinality != nil {
+		return typeWidens(from.LowCardinality.Type, to)
+	}
+	if to.LowCardinality != nil {
+		return typeWidens(from, to.LowCardinality.Type)
+	}
+
+	switch {
+	case to.Nullable != nil:
+		if from.Nullable != nil {
+			return typeWidens(from.Nullable.Type, to.Nullable.Type)
+		}
+		return typeWidens(from, to.Nullable.Type)
+	case from.Nullable != nil:
+		// NULL values can't be converted
+		return false
+	case from.Array != nil && to.Array != nil:
+		return typeWidens(from.Array.Type, to.Array.Type)
+	case from.Map != nil && to.Map != nil:
+		return typeWidens(from.Map.KeyType, to.Map.KeyType) && typeWidens(from.Map.ValueType, to.Map.ValueType)
+	case to.Simple != nil && to.Simple.Name == "String" && len(to.Simple.Parameters) == 0:
+		return true
+	case from.Simple != nil && to.Simple != nil:
+		return simpleTypeWidens(from.Simple, to.Simple)
+	default:
+		return false
+	}
+}
+
+// simpleTypeWidens reports whether the values of a simple type convert to another simple
+// type without loss (see typeWidens)
+func simpleTypeWidens(from, to *parser.SimpleType) bool {
+	if fromBits, fromSigned, ok := integerType(from.Name); ok {
+		if toBits, toSigned, ok := integerType(to.Name); ok {
+			// Unsigned values fit in wider signed types, but negative values never fit in unsigned ones
+			return (fromSigned == toSigned && toBits >= fromBits) || (!fromSigned && toSigned && toBits > fromBits)
+		}
+
+		// Floats represent integers exactly up to the width of their mantissa
+		switch to.Name {
+		case "Float32":
+			return fromBits <= 16
+		case "Float64":
+			return fromBits <= 32
+		}
+		return false
+	}
+
+	params := typeParameterNumbers(from)
+	toParams := typeParameterNumbers(to)
+
+	switch from.Name {
+	case "Float32":
+		return to.Name == "Float64"
+	case "FixedString":
+		return to.Name == "FixedString" && len(params) == 1 && len(toParams) == 1 && toParams[0] >= params[0]
+	case "Decimal":
+		// The integer digits (precision - scale) and the scale must both be preserved
+		return to.Name == "Decimal" && len(params) == 2 && len(toParams) == 2 &&
+			toParams[0]-toParams[1] >= params[0]-params[1] && toParams[1] >= params[1]
+	case "Date":
+		return to.Name == "Date32" || to.Name == "DateTime" || to.Name == "DateTime64"
+	case "DateTime":
+		return to.Name == "DateTime64"
+	case "DateTime64":
+		return to.Name == "DateTime64" && len(params) > 0 && len(toParams) > 0 && toParams[0] >= params[0]
+	default:
+		return false
+	}
+}
+
+// integerType returns the width and signedness of an integer type name
+func integerType(name string) (bits int, signed, ok bool) {
+	signed = !strings.HasPrefix(name, "U")
+	width, found := strings.CutPrefix(strings.TrimPrefix(name, "U"), "Int")
+	if !found {
+		return 0, false, false
+	}
+
+	bits, err := strconv.Atoi(width)
+	if err != nil {
+		return 0, false, false
+	}
+
+	return bits, signed, true
+}
+
+// typeParameterNumbers returns the leading numeric parameters of a parametric type, e.g.
+// the precision and scale of Decimal(10, 2)
+func typeParameterNumbers(t *parser.SimpleType) []int {
+	var numbers []int
+	for _, param := range t.Parameters {
+		if param.Number == nil {
+			break
+		}
+		n, err := strconv.Atoi(*param.Number)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
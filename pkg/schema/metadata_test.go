@@ -0,0 +1,249 @@
+package schema_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/format"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	. "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareMetadata(t *testing.T) {
+	current, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE DATABASE legacy ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String, payload String) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.sessions (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.queue (id UInt64) ENGINE = Kafka('localhost:9092', 'topic', 'group', 'JSONEachRow');
+		CREATE VIEW analytics.daily AS SELECT id FROM analytics.events;
+		CREATE MATERIALIZED VIEW analytics.mv_totals ENGINE = MergeTree() ORDER BY id AS SELECT id, count() AS total FROM analytics.events GROUP BY id;`)
+	require.NoError(t, err)
+
+	target, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Analytics';
+		CREATE TABLE analytics.events (id UInt64, name String, created_at DateTime) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.sessions (id UInt64, started_at DateTime) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.queue (id UInt64, name String) ENGINE = Kafka('localhost:9092', 'topic', 'group', 'JSONEachRow');
+		CREATE TABLE analytics.users (id UInt64) ENGINE = MergeTree() ORDER BY id;
+		CREATE VIEW analytics.daily AS SELECT id, name FROM analytics.events;
+		CREATE MATERIALIZED VIEW analytics.mv_totals ENGINE = MergeTree() ORDER BY id AS SELECT id, name, count() AS total FROM analytics.events GROUP BY id, name;`)
+	require.NoError(t, err)
+
+	changes, err := CompareMetadata(current, target)
+	require.NoError(t, err)
+
+	require.Equal(t, Changes{
+		{ObjectType: ObjectTypeDatabase, Type: "ALTER", Name: "analytics", Description: "Alter database 'analytics'", Class: ChangeModifying},
+		{ObjectType: ObjectTypeDatabase, Type: "DROP", Name: "legacy", Description: "Drop database 'legacy'", Destructive: true, Class: ChangeDestructive},
+		{ObjectType: ObjectTypeTable, Type: "CREATE", Name: "analytics.users", Description: "Create table analytics.users", Class: ChangeAdditive},
+		{ObjectType: ObjectTypeTable, Type: "ALTER", Name: "analytics.events", Description: changes[3].Description, Destructive: true, Class: ChangeDestructive},
+		{ObjectType: ObjectTypeTable, Type: "ALTER", Name: "analytics.queue", Description: changes[4].Description, Destructive: true, Class: ChangeDestructive},
+		{ObjectType: ObjectTypeTable, Type: "ALTER", Name: "analytics.sessions", Description: changes[5].Description, Class: ChangeAdditive},
+		{ObjectType: ObjectTypeView, Type: "ALTER", Name: "analytics.daily", Description: "Alter view analytics.daily", Class: ChangeModifying},
+		{ObjectType: ObjectTypeMaterializedView, Type: "ALTER", Name: "analytics.mv_totals", Description: "Alter materialized view analytics.mv_totals", Destructive: true, Class: ChangeDestructive},
+	}, changes)
+
+	t.Run("with SQL", func(t *testing.T) {
+		withSQL, err := CompareMetadataWithOptions(current, target, CompareOptions{IncludeSQL: true})
+		require.NoError(t, err)
+		require.Len(t, withSQL, len(changes))
+
+		require.Equal(t, "ALTER DATABASE `analytics` MODIFY COMMENT 'Analytics';", withSQL[0].UpSQL)
+		require.Equal(t, "ALTER DATABASE `analytics` MODIFY COMMENT '';", withSQL[0].DownSQL)
+		require.Contains(t, withSQL[2].UpSQL, "CREATE TABLE analytics.users")
+		require.Contains(t, withSQL[2].DownSQL, "DROP TABLE")
+
+		for i, change := range withSQL {
+			require.NotEmpty(t, change.UpSQL, "%s has SQL", change.Description)
+			change.UpSQL, change.DownSQL = "", ""
+			require.Equal(t, changes[i], change)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		_, err := CompareMetadata(current, current)
+		require.ErrorIs(t, err, ErrNoDiff)
+	})
+
+	t.Run("unsupported changes", func(t *testing.T) {
+		engineChange, err := parser.ParseString("CREATE DATABASE analytics ENGINE = Memory;")
+		require.NoError(t, err)
+
+		_, expected := GenerateDiff(current, engineChange)
+		require.ErrorIs(t, expected, ErrUnsupported)
+
+		_, err = CompareMetadata(current, engineChange)
+		require.EqualError(t, err, expected.Error())
+	})
+}
+
+func TestChanges_Classify(t *testing.T) {
+	compare := func(t *testing.T, current, target string) Changes {
+		t.Helper()
+
+		currentSQL, err := parser.ParseString(current)
+		require.NoError(t, err)
+		targetSQL, err := parser.ParseString(target)
+		require.NoError(t, err)
+
+		changes, err := CompareMetadata(currentSQL, targetSQL)
+		require.NoError(t, err)
+		return changes
+	}
+
+	const events = "CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY id"
+
+	tests := []struct {
+		name   string
+		target string
+		class  ChangeClass
+	}{
+		{name: "new table", target: events + "; CREATE TABLE db.users (id UInt64) ENGINE = MergeTree() ORDER BY id;", class: ChangeAdditive},
+		{name: "new column", target: "CREATE TABLE db.events (id UInt64, count UInt32, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;", class: ChangeAdditive},
+		{name: "new index", target: "CREATE TABLE db.events (id UInt64, count UInt32, name String, INDEX name_idx name TYPE bloom_filter GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;", class: ChangeAdditive},
+		{name: "new column and setting", target: "CREATE TABLE db.events (id UInt64, count UInt32, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id SETTINGS merge_with_ttl_timeout = 3600;", class: ChangeModifying},
+		{name: "widened column", target: "CREATE TABLE db.events (id UInt64, count Nullable(UInt64), name LowCardinality(String)) ENGINE = MergeTree() ORDER BY id;", class: ChangeModifying},
+		{name: "comment", target: events + " COMMENT 'Events';", class: ChangeModifying},
+		{name: "replaced column", target: "CREATE TABLE db.events (id UInt64, total UInt32, name String) ENGINE = MergeTree() ORDER BY id;", class: ChangeDestructive},
+		{name: "narrowed column", target: "CREATE TABLE db.events (id UInt64, count UInt16, name String) ENGINE = MergeTree() ORDER BY id;", class: ChangeDestructive},
+		{name: "signedness change", target: "CREATE TABLE db.events (id UInt64, count Int32, name String) ENGINE = MergeTree() ORDER BY id;", class: ChangeDestructive},
+		{name: "dropped column", target: "CREATE TABLE db.events (id UInt64, count UInt32) ENGINE = MergeTree() ORDER BY id;", class: ChangeDestructive},
+		{name: "dropped table", target: "CREATE DATABASE db ENGINE = Atomic;", class: ChangeDestructive},
+		{name: "recreated table", target: "CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY name;", class: ChangeDestructive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := compare(t, "CREATE DATABASE db ENGINE = Atomic; "+events+";", "CREATE DATABASE db ENGINE = Atomic; "+tt.target)
+			require.Len(t, changes, 1)
+			require.Equal(t, tt.class, changes[0].Class)
+			require.Equal(t, tt.class == ChangeDestructive, changes[0].Destructive)
+		})
+	}
+
+	t.Run("renamed column", func(t *testing.T) {
+		current, err := parser.ParseString("CREATE DATABASE db ENGINE = Atomic; " + events + ";")
+		require.NoError(t, err)
+		target, err := parser.ParseString("CREATE DATABASE db ENGINE = Atomic; CREATE TABLE db.events (id UInt64, total UInt32, name String) ENGINE = MergeTree() ORDER BY id;")
+		require.NoError(t, err)
+
+		changes, err := CompareMetadataWithOptions(current, target, CompareOptions{DetectColumnRenames: true})
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, ChangeModifying, changes[0].Class)
+	})
+
+	t.Run("buckets", func(t *testing.T) {
+		changes := compare(t,
+			"CREATE DATABASE db ENGINE = Atomic; "+events+"; CREATE TABLE db.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;",
+			"CREATE DATABASE db ENGINE = Atomic COMMENT 'Main'; "+events+" COMMENT 'Events'; CREATE TABLE db.users (id UInt64, email String) ENGINE = MergeTree() ORDER BY id;",
+		)
+
+		additive, destructive, modifying := changes.Classify()
+		require.Len(t, additive, 1)
+		require.Equal(t, "db.users", additive[0].Name)
+		require.Len(t, destructive, 1)
+		require.Equal(t, "db.legacy", destructive[0].Name)
+		require.Len(t, modifying, 2)
+		require.Equal(t, "db", modifying[0].Name)
+		require.Equal(t, "db.events", modifying[1].Name)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		additive, destructive, modifying := Changes(nil).Classify()
+		require.Empty(t, additive)
+		require.Empty(t, destructive)
+		require.Empty(t, modifying)
+	})
+}
+
+// droppedObject matches generated statements that remove an object or column, or
+// replace a table in place
+var droppedObject = regexp.MustCompile(`(?m)^(DROP (DATABASE|TABLE|DICTIONARY|VIEW|FUNCTION|ROLE|NAMED COLLECTION)|CREATE OR REPLACE TABLE)\b|\bDROP COLUMN\b`)
+
+// modifiedColumn matches generated statements that change a column's type, which is
+// destructive when the type is narrowed
+var modifiedColumn = regexp.MustCompile(`\bMODIFY COLUMN\b`)
+
+func TestCompareMetadata_MatchesGenerateDiff(t *testing.T) {
+	inputFiles, err := filepath.Glob("testdata/*.in.sql")
+	require.NoError(t, err)
+
+	for _, inputPath := range inputFiles {
+		testName := strings.TrimSuffix(filepath.Base(inputPath), ".in.sql")
+
+		t.Run(testName, func(t *testing.T) {
+			current, target := loadDiffFixture(t, inputPath)
+
+			diff, diffErr := GenerateDiff(current, target)
+			changes, err := CompareMetadata(current, target)
+			if diffErr != nil {
+				if errors.Is(diffErr, ErrNoDiff) {
+					require.ErrorIs(t, err, ErrNoDiff)
+				} else {
+					require.EqualError(t, err, diffErr.Error())
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotEmpty(t, changes)
+
+			// The SQL is only generated when it's asked for, and doesn't change what's compared
+			withSQL, err := CompareMetadataWithOptions(current, target, CompareOptions{IncludeSQL: true})
+			require.NoError(t, err)
+			require.Len(t, withSQL, len(changes))
+			for i, change := range withSQL {
+				require.Empty(t, changes[i].UpSQL+changes[i].DownSQL)
+				require.NotEmpty(t, strings.TrimSpace(change.UpSQL), "%s has SQL", change.Description)
+				change.UpSQL, change.DownSQL = "", ""
+				require.Equal(t, change, changes[i])
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, format.FormatSQL(&buf, format.Defaults, diff))
+
+			// Every destructive change is applied by dropping or replacing something, or
+			// by narrowing a column
+			for _, change := range changes {
+				require.Equal(t, change.Class == ChangeDestructive, change.Destructive)
+				if change.Destructive && !modifiedColumn.MatchString(buf.String()) {
+					require.Regexp(t, droppedObject, buf.String(), "%s is destructive", change.Description)
+				}
+			}
+
+			// A migration that doesn't drop, replace, or modify anything has no destructive changes
+			if !droppedObject.MatchString(buf.String()) && !modifiedColumn.MatchString(buf.String()) {
+				for _, change := range changes {
+					require.False(t, change.Destructive, "%s isn't destructive", change.Description)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCompareMetadata(b *testing.B) {
+	current, target := largeMixedSchema(b, 200)
+
+	b.Run("CompareMetadata", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := CompareMetadata(current, target); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("GenerateDiff", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := GenerateDiff(current, target); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// MigrationChanges returns a Change for every statement of an existing migration,
+// classified like the changes returned by CompareMetadata, so that a migration that's
+// about to be applied can be scored with ScoreDiff. The changes have no UpSQL or DownSQL.
+//
+// current is the schema the migration is applied to, e.g. dumped from the live server.
+// It tells whether a MODIFY COLUMN widens the column's type (see typeWidens). Type
+// changes of columns that aren't in current are considered narrowing, so current may be
+// nil to classify the migration by its statements only.
+//
+// Statements that don't change the schema or data (comments, SELECT, SYSTEM, and SET
+// ROLE) aren't changes. ALTER TABLE operations that delete or replace data, such as
+// DELETE, UPDATE, CLEAR COLUMN, and DROP PARTITION, are destructive.
+//
+// Example:
+//
+//	current, err := client.GetSchema(ctx)
+//	if err != nil {
+//		return err
+//	}
+//
+//	report := schema.ScoreDiff(schema.MigrationChanges(current, migration.Statements), rows)
+//	fmt.Printf("risk: %s (weight %d)\n", report.Score, report.Weight)
+func MigrationChanges(current *parser.SQL, statements []*parser.Statement) Changes {
+	var tables map[string]*TableInfo
+	if current != nil {
+		// Tables that can't be extracted only lose the type information of their columns
+		tables, _ = extractTablesFromSQL(current)
+	}
+
+	var changes Changes
+	for _, stmt := range statements {
+		change, ok := statementChange(stmt, tables)
+		if !ok {
+			continue
+		}
+
+		change.Destructive = change.Class == ChangeDestructive
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// statementChange describes the change applied by a migration statement, returning false
+// for statements that don't change the schema or data
+//
+//nolint:gocyclo,cyclop // One case per statement type
+func statementChange(stmt *parser.Statement, tables map[string]*TableInfo) (Change, bool) {
+	created := func(objectType ObjectType, name string, replace bool) Change {
+		change := Change{ObjectType: objectType, Type: "CREATE", Name: name, Class: ChangeAdditive}
+		if replace {
+			change.Type, change.Class = "REPLACE", ChangeModifying
+		}
+		change.Description = changeDescription(change.Type, objectType, name)
+		return change
+	}
+	dropped := func(objectType ObjectType, name string) Change {
+		return Change{
+			ObjectType:  objectType,
+			Type:        "DROP",
+			Name:        name,
+			Description: changeDescription("DROP", objectType, name),
+			Class:       ChangeDestructive,
+		}
+	}
+	modified := func(objectType ObjectType, diffType, name string) Change {
+		return Change{
+			ObjectType:  objectType,
+			Type:        diffType,
+			Name:        name,
+			Description: changeDescription(diffType, objectType, name),
+			Class:       ChangeModifying,
+		}
+	}
+
+	switch {
+	case stmt.CreateDatabase != nil:
+		return created(ObjectTypeDatabase, normalizeIdentifier(stmt.CreateDatabase.Name), false), true
+	case stmt.AlterDatabase != nil:
+		return modified(ObjectTypeDatabase, "ALTER", normalizeIdentifier(stmt.AlterDatabase.Name)), true
+	case stmt.DropDatabase != nil:
+		return dropped(ObjectTypeDatabase, normalizeIdentifier(stmt.DropDatabase.Name)), true
+	case stmt.RenameDatabase != nil:
+		rename := stmt.RenameDatabase.Renames[0]
+		change := modified(ObjectTypeDatabase, "RENAME", normalizeIdentifier(rename.From))
+		change.NewName = normalizeIdentifier(rename.To)
+		return change, true
+	case stmt.CreateTable != nil:
+		name := sourceName(stmt.CreateTable.Database, stmt.CreateTable.Name)
+		change := created(ObjectTypeTable, name, stmt.CreateTable.OrReplace)
+		if stmt.CreateTable.OrReplace && !isViewLikeEngine(stmt.CreateTable.Engine) {
+			// Replacing a table that stores data discards its data
+			change.Class = ChangeDestructive
+		}
+		return change, true
+	case stmt.AlterTable != nil:
+		return alterTableChange(stmt.AlterTable, tables), true
+	case stmt.DropTable != nil:
+		return dropped(ObjectTypeTable, sourceName(stmt.DropTable.Database, stmt.DropTable.Name)), true
+	case stmt.TruncateTable != nil:
+		change := dropped(ObjectTypeTable, sourceName(stmt.TruncateTable.Database, stmt.TruncateTable.Name))
+		change.Type, change.Description = "TRUNCATE", "Truncate table "+change.Name
+		return change, true
+	case stmt.RenameTable != nil:
+		rename := stmt.RenameTable.Renames[0]
+		change := modified(ObjectTypeTable, "RENAME", sourceName(rename.FromDatabase, rename.FromName))
+		change.NewName = sourceName(rename.ToDatabase, rename.ToName)
+		return change, true
+	case stmt.ExchangeTables != nil, stmt.AttachTable != nil, stmt.DetachTable != nil:
+		return modified(ObjectTypeTable, "ALTER", ""), true
+	case stmt.CreateView != nil:
+		view := stmt.CreateView
+		objectType := ObjectTypeView
+		if view.Materialized {
+			objectType = ObjectTypeMaterializedView
+		}
+		change := created(objectType, sourceName(view.Database, view.Name), view.OrReplace)
+		if view.OrReplace && view.Materialized && view.To == nil {
+			// The inner table storing the view's data is replaced too
+			change.Class = ChangeDestructive
+		}
+		return change, true
+	case stmt.DropView != nil:
+		return dropped(ObjectTypeView, sourceName(stmt.DropView.Database, stmt.DropView.Name)), true
+	case stmt.AttachView != nil, stmt.DetachView != nil:
+		return modified(ObjectTypeView, "ALTER", ""), true
+	case stmt.CreateDictionary != nil:
+		dict := stmt.CreateDictionary
+		return created(ObjectTypeDictionary, sourceName(dict.Database, dict.Name), dict.OrReplace), true
+	case stmt.DropDictionary != nil:
+		return dropped(ObjectTypeDictionary, sourceName(stmt.DropDictionary.Database, stmt.DropDictionary.Name)), true
+	case stmt.RenameDictionary != nil, stmt.ExchangeDictionaries != nil, stmt.AttachDictionary != nil, stmt.DetachDictionary != nil:
+		return modified(ObjectTypeDictionary, "ALTER", ""), true
+	case stmt.CreateFunction != nil:
+		return created(ObjectTypeFunction, normalizeIdentifier(stmt.CreateFunction.Name), stmt.CreateFunction.OrReplace), true
+	case stmt.DropFunction != nil:
+		return dropped(ObjectTypeFunction, normalizeIdentifier(stmt.DropFunction.Name)), true
+	case stmt.CreateNamedCollection != nil:
+		collection := stmt.CreateNamedCollection
+		return created(ObjectTypeNamedCollection, normalizeIdentifier(collection.Name), collection.OrReplace), true
+	case stmt.AlterNamedCollection != nil:
+		return modified(ObjectTypeNamedCollection, "ALTER", normalizeIdentifier(stmt.AlterNamedCollection.Name)), true
+	case stmt.DropNamedCollection != nil:
+		return dropped(ObjectTypeNamedCollection, normalizeIdentifier(stmt.DropNamedCollection.Name)), true
+	case stmt.CreateRole != nil:
+		return created(ObjectTypeRole, normalizeIdentifier(stmt.CreateRole.Name), stmt.CreateRole.OrReplace), true
+	case stmt.AlterRole != nil:
+		return modified(ObjectTypeRole, "ALTER", normalizeIdentifier(stmt.AlterRole.Name)), true
+	case stmt.DropRole != nil:
+		return dropped(ObjectTypeRole, normalizeIdentifier(stmt.DropRole.Names[0])), true
+	case stmt.Grant != nil:
+		return modified(ObjectTypeRole, "GRANT", ""), true
+	case stmt.Revoke != nil:
+		return modified(ObjectTypeRole, "REVOKE", ""), true
+	case stmt.CreateUser != nil, stmt.AlterUser != nil, stmt.DropUser != nil, stmt.SetDefaultRole != nil:
+		// Users aren't managed by the schema, but changing them affects access like roles do
+		change := modified(ObjectTypeRole, "ALTER", "")
+		change.Description = "Alter user"
+		return change, true
+	default:
+		// Comments, SELECT, SYSTEM, and SET ROLE don't change anything
+		return Change{}, false
+	}
+}
+
+// alterTableChange classifies an ALTER TABLE statement by its most severe operation. It's
+// additive when every operation adds a column, index, constraint, or projection, and a
+// mutation when the type of a column changes.
+func alterTableChange(stmt *parser.AlterTableStmt, tables map[string]*TableInfo) Change {
+	name := sourceName(stmt.Database, stmt.Name)
+	change := Change{
+		ObjectType:  ObjectTypeTable,
+		Type:        string(TableDiffAlter),
+		Name:        name,
+		Description: "Alter table " + name,
+		Class:       ChangeAdditive,
+	}
+
+	var columns map[string]ColumnInfo
+	if table := tables[name]; table != nil {
+		columns = make(map[string]ColumnInfo, len(table.Columns))
+		for _, col := range table.Columns {
+			columns[col.Name] = col
+		}
+	}
+
+	for i := range stmt.Operations {
+		op := &stmt.Operations[i]
+
+		class := ChangeModifying
+		switch {
+		case op.AddColumn != nil, op.AddIndex != nil, op.AddConstraint != nil, op.AddProjection != nil:
+			class = ChangeAdditive
+		case op.DropColumn != nil, op.ClearColumn != nil, op.Delete != nil, op.Update != nil,
+			op.DropPartition != nil, op.DetachPartition != nil, op.MovePartition != nil, op.ReplacePartition != nil:
+			class = ChangeDestructive
+		case op.ModifyColumn != nil && op.ModifyColumn.Type != nil:
+			var currentType *parser.DataType
+			if col, exists := columns[normalizeIdentifier(op.ModifyColumn.Name)]; exists {
+				currentType = columnDataType(col)
+			}
+
+			if currentType == nil || !currentType.Equal(op.ModifyColumn.Type) {
+				change.Mutation = true
+			}
+			if !typeWidens(currentType, op.ModifyColumn.Type) {
+				class = ChangeDestructive
+			}
+		}
+
+		switch {
+		case class == ChangeDestructive:
+			change.Class = ChangeDestructive
+		case class == ChangeModifying && change.Class == ChangeAdditive:
+			change.Class = ChangeModifying
+		}
+	}
+
+	return change
+}
+
+// changeDescription describes a change the way the diffs of CompareMetadata do, e.g.
+// "Drop table analytics.events"
+func changeDescription(diffType string, objectType ObjectType, name string) string {
+	description := map[string]string{
+		"CREATE":  "Create",
+		"REPLACE": "Replace",
+		"ALTER":   "Alter",
+		"DROP":    "Drop",
+		"RENAME":  "Rename",
+		"GRANT":   "Grant",
+		"REVOKE":  "Revoke",
+	}[diffType] + " " + strings.ReplaceAll(string(objectType), "_", " ")
+
+	if name != "" {
+		description += " " + name
+	}
+	return description
+}
@@ -0,0 +1,86 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	. "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationChanges(t *testing.T) {
+	current, err := parser.ParseString(`
+		CREATE DATABASE db ENGINE = Atomic;
+		CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		migration   string
+		class       ChangeClass
+		mutation    bool
+		description string
+	}{
+		{name: "create table", migration: "CREATE TABLE db.users (id UInt64) ENGINE = MergeTree() ORDER BY id;", class: ChangeAdditive, description: "Create table db.users"},
+		{name: "add column", migration: "ALTER TABLE db.events ADD COLUMN ts DateTime, ADD INDEX name_idx name TYPE bloom_filter;", class: ChangeAdditive, description: "Alter table db.events"},
+		{name: "comment", migration: "ALTER TABLE `db`.`events` MODIFY COMMENT 'Events';", class: ChangeModifying, description: "Alter table db.events"},
+		{name: "widened column", migration: "ALTER TABLE db.events MODIFY COLUMN count UInt64;", class: ChangeModifying, mutation: true},
+		{name: "narrowed column", migration: "ALTER TABLE db.events MODIFY COLUMN count UInt16;", class: ChangeDestructive, mutation: true},
+		{name: "unknown column", migration: "ALTER TABLE db.events MODIFY COLUMN missing UInt64;", class: ChangeDestructive, mutation: true},
+		{name: "unchanged type", migration: "ALTER TABLE db.events MODIFY COLUMN count UInt32 COMMENT 'Count';", class: ChangeModifying},
+		{name: "added and dropped columns", migration: "ALTER TABLE db.events ADD COLUMN ts DateTime, DROP COLUMN name;", class: ChangeDestructive},
+		{name: "delete", migration: "ALTER TABLE db.events DELETE WHERE id = 1;", class: ChangeDestructive},
+		{name: "drop partition", migration: "ALTER TABLE db.events DROP PARTITION '2024-01-01';", class: ChangeDestructive},
+		{name: "drop table", migration: "DROP TABLE db.events;", class: ChangeDestructive, description: "Drop table db.events"},
+		{name: "truncate table", migration: "TRUNCATE TABLE db.events;", class: ChangeDestructive, description: "Truncate table db.events"},
+		{name: "replace table", migration: "CREATE OR REPLACE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id;", class: ChangeDestructive},
+		{name: "replace distributed table", migration: "CREATE OR REPLACE TABLE db.events_all (id UInt64) ENGINE = Distributed(cluster, db, events);", class: ChangeModifying},
+		{name: "rename table", migration: "RENAME TABLE db.events TO db.events_v2;", class: ChangeModifying, description: "Rename table db.events"},
+		{name: "replace materialized view", migration: "CREATE OR REPLACE MATERIALIZED VIEW db.totals ENGINE = MergeTree() ORDER BY id AS SELECT id FROM db.events;", class: ChangeDestructive},
+		{name: "replace view", migration: "CREATE OR REPLACE VIEW db.recent AS SELECT id FROM db.events;", class: ChangeModifying, description: "Replace view db.recent"},
+		{name: "drop database", migration: "DROP DATABASE db;", class: ChangeDestructive, description: "Drop database db"},
+		{name: "create function", migration: "CREATE FUNCTION double AS (x) -> x * 2;", class: ChangeAdditive, description: "Create function double"},
+		{name: "grant", migration: "GRANT SELECT ON db.* TO reader;", class: ChangeModifying},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := parser.ParseString(tt.migration)
+			require.NoError(t, err)
+
+			changes := MigrationChanges(current, migration.Statements)
+			require.Len(t, changes, 1)
+			require.Equal(t, tt.class, changes[0].Class)
+			require.Equal(t, tt.class == ChangeDestructive, changes[0].Destructive)
+			require.Equal(t, tt.mutation, changes[0].Mutation)
+			if tt.description != "" {
+				require.Equal(t, tt.description, changes[0].Description)
+			}
+		})
+	}
+
+	t.Run("statements that change nothing", func(t *testing.T) {
+		migration, err := parser.ParseString("-- Cleanup\nSELECT 1;\nSYSTEM FLUSH LOGS;")
+		require.NoError(t, err)
+		require.Empty(t, MigrationChanges(current, migration.Statements))
+	})
+
+	t.Run("without a current schema", func(t *testing.T) {
+		migration, err := parser.ParseString("ALTER TABLE db.events MODIFY COLUMN count UInt64;")
+		require.NoError(t, err)
+
+		changes := MigrationChanges(nil, migration.Statements)
+		require.Len(t, changes, 1)
+		require.Equal(t, ChangeDestructive, changes[0].Class, "type changes of unknown columns are considered narrowing")
+	})
+
+	t.Run("scored with row counts", func(t *testing.T) {
+		migration, err := parser.ParseString("CREATE TABLE db.users (id UInt64) ENGINE = MergeTree() ORDER BY id; ALTER TABLE db.events MODIFY COLUMN count UInt64;")
+		require.NoError(t, err)
+
+		report := ScoreDiff(MigrationChanges(current, migration.Statements), TableRows{"db.events": LargeTableRows})
+		require.Equal(t, RiskMedium, report.Score)
+		require.Equal(t, RiskWeightAdditive+RiskWeightLargeMutation, report.Weight)
+		require.Equal(t, uint64(LargeTableRows), *report.Items[1].Rows)
+	})
+}
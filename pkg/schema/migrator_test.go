@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -27,77 +28,7 @@ func TestDiffGeneration(t *testing.T) {
 		testName := strings.TrimSuffix(inputFile, ".in.sql")
 
 		t.Run(testName, func(t *testing.T) {
-			// Read input file containing 2 SQL statements
-			inputData, err := os.ReadFile(inputPath)
-			require.NoError(t, err)
-
-			inputSQL := string(inputData)
-
-			// Split input into current and target SQL by looking for comment sections
-			// Each .in.sql file should have a current state section and a target state section
-			lines := strings.Split(inputSQL, "\n")
-
-			var currentSQLLines, targetSQLLines []string
-			inTargetSection := false
-
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if strings.HasPrefix(line, "-- Target state:") {
-					inTargetSection = true
-					continue
-				}
-				if strings.HasPrefix(line, "--") {
-					// Skip other comment lines
-					continue
-				}
-				if line == "" {
-					// Skip empty lines
-					continue
-				}
-
-				// Skip lines that are just a semicolon (empty state markers)
-				if line == ";" {
-					continue
-				}
-
-				if inTargetSection {
-					targetSQLLines = append(targetSQLLines, line)
-				} else {
-					currentSQLLines = append(currentSQLLines, line)
-				}
-			}
-
-			currentSQLText := strings.Join(currentSQLLines, " ")
-			targetSQLText := strings.Join(targetSQLLines, " ")
-
-			// Clean up any double spaces and normalize
-			currentSQLText = strings.Join(strings.Fields(currentSQLText), " ")
-			targetSQLText = strings.Join(strings.Fields(targetSQLText), " ")
-
-			// Parse current and target SQL
-			var currentSQL, targetSQL *parser.SQL
-
-			if currentSQLText == "" {
-				currentSQL = &parser.SQL{Statements: []*parser.Statement{}}
-			} else {
-				// Ensure currentSQLText ends with semicolon for parser
-				if !strings.HasSuffix(currentSQLText, ";") {
-					currentSQLText += ";"
-				}
-				currentSQL, err = parser.ParseString(currentSQLText)
-				require.NoError(t, err)
-			}
-
-			if targetSQLText == "" {
-				targetSQL = &parser.SQL{Statements: []*parser.Statement{}}
-			} else {
-				// Ensure targetSQLText ends with semicolon for parser
-				if !strings.HasSuffix(targetSQLText, ";") {
-					targetSQLText += ";"
-				}
-				targetSQL, err = parser.ParseString(targetSQLText)
-				require.NoError(t, err)
-			}
+			currentSQL, targetSQL := loadDiffFixture(t, inputPath)
 
 			// Generate diff
 			diff, err := GenerateDiff(currentSQL, targetSQL)
@@ -129,6 +60,129 @@ func TestDiffGeneration(t *testing.T) {
 	}
 }
 
+func TestGenerateDiffWithOptions_StrictComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		target   string
+		contains string
+	}{
+		{
+			name:     "column comment case",
+			current:  "CREATE TABLE db.events (id UInt64 COMMENT 'Event ID') ENGINE = MergeTree() ORDER BY id;",
+			target:   "CREATE TABLE db.events (id UInt64 COMMENT 'event id') ENGINE = MergeTree() ORDER BY id;",
+			contains: "COMMENT COLUMN `id` 'event id'",
+		},
+		{
+			name:     "dictionary comment keyword case",
+			current:  "CREATE DICTIONARY db.users (id UInt64) PRIMARY KEY id SOURCE(HTTP(url 'test')) LAYOUT(FLAT()) LIFETIME(600) COMMENT 'Loaded FROM users';",
+			target:   "CREATE DICTIONARY db.users (id UInt64) PRIMARY KEY id SOURCE(HTTP(url 'test')) LAYOUT(FLAT()) LIFETIME(600) COMMENT 'Loaded from users';",
+			contains: "COMMENT 'Loaded from users'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := parser.ParseString(tt.current)
+			require.NoError(t, err)
+			target, err := parser.ParseString(tt.target)
+			require.NoError(t, err)
+
+			// Lenient comparison (the default) ignores the change
+			_, err = GenerateDiff(current, target)
+			require.ErrorIs(t, err, ErrNoDiff)
+
+			_, err = GenerateDiffWithOptions(current, target, CompareOptions{})
+			require.ErrorIs(t, err, ErrNoDiff)
+
+			// Strict comparison generates a migration for it
+			diff, err := GenerateDiffWithOptions(current, target, CompareOptions{StrictComments: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, format.FormatSQL(&buf, format.Defaults, diff))
+			require.Contains(t, buf.String(), tt.contains)
+		})
+	}
+
+	t.Run("identical comments", func(t *testing.T) {
+		sql, err := parser.ParseString("CREATE TABLE db.events (id UInt64 COMMENT 'Event ID') ENGINE = MergeTree() ORDER BY id COMMENT 'User events';")
+		require.NoError(t, err)
+
+		_, err = GenerateDiffWithOptions(sql, sql, CompareOptions{StrictComments: true})
+		require.ErrorIs(t, err, ErrNoDiff)
+	})
+}
+
+func TestGenerateDiffWithOptions_PreferReplaceTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		target   string
+		expected []string
+	}{
+		{
+			name:    "integration engine",
+			current: "CREATE TABLE db.users (id UInt64) ENGINE = MySQL('localhost:3306', 'db', 'users', 'user', 'pass');",
+			target:  "CREATE TABLE db.users (id UInt64, name String) ENGINE = MySQL('localhost:3306', 'db', 'users', 'user', 'pass');",
+			expected: []string{
+				"CREATE OR REPLACE TABLE `db`.`users` (\n    `id`   UInt64,\n    `name` String\n)\nENGINE = MySQL('localhost:3306', 'db', 'users', 'user', 'pass');",
+			},
+		},
+		{
+			name:    "replicated merge tree parameters",
+			current: "CREATE TABLE db.events (id UInt64) ENGINE = ReplicatedMergeTree('/clickhouse/tables/events_v1', '{replica}') ORDER BY id;",
+			target:  "CREATE TABLE db.events (id UInt64) ENGINE = ReplicatedMergeTree('/clickhouse/tables/events_v2', '{replica}') ORDER BY id;",
+			expected: []string{
+				"CREATE OR REPLACE TABLE `db`.`events` (\n    `id` UInt64\n)\nENGINE = ReplicatedMergeTree('/clickhouse/tables/events_v2', '{replica}')\nORDER BY `id`;",
+			},
+		},
+		{
+			name:    "streaming engine falls back to drop and create",
+			current: "CREATE TABLE db.queue (id UInt64) ENGINE = Kafka('broker:9092', 'topic', 'group', 'JSONEachRow');",
+			target:  "CREATE TABLE db.queue (id UInt64, name String) ENGINE = Kafka('broker:9092', 'topic', 'group', 'JSONEachRow');",
+			expected: []string{
+				"DROP TABLE `db`.`queue`;",
+				"CREATE TABLE `db`.`queue` (\n    `id`   UInt64,\n    `name` String\n)\nENGINE = Kafka('broker:9092', 'topic', 'group', 'JSONEachRow');",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, err := parser.ParseString(tt.current)
+			require.NoError(t, err)
+			target, err := parser.ParseString(tt.target)
+			require.NoError(t, err)
+
+			diff, err := GenerateDiffWithOptions(current, target, CompareOptions{PreferReplaceTable: true})
+			require.NoError(t, err)
+
+			var statements []string
+			for _, stmt := range diff.Statements {
+				var buf bytes.Buffer
+				require.NoError(t, format.FormatSQL(&buf, format.Defaults, &parser.SQL{Statements: []*parser.Statement{stmt}}))
+				statements = append(statements, strings.TrimSpace(buf.String()))
+			}
+			require.Equal(t, tt.expected, statements)
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		current, err := parser.ParseString(tests[0].current)
+		require.NoError(t, err)
+		target, err := parser.ParseString(tests[0].target)
+		require.NoError(t, err)
+
+		diff, err := GenerateDiff(current, target)
+		require.NoError(t, err)
+		require.Len(t, diff.Statements, 2)
+		require.NotNil(t, diff.Statements[0].DropTable)
+		require.NotNil(t, diff.Statements[1].CreateTable)
+		require.False(t, diff.Statements[1].CreateTable.OrReplace)
+	})
+}
+
 func TestGenerateMigrationFile(t *testing.T) {
 	t.Run("generates migration file with correct timestamp format", func(t *testing.T) {
 		// Create temporary migration directory
@@ -336,3 +390,377 @@ CREATE TABLE test.users (id UInt64) ENGINE = MergeTree() ORDER BY id;`
 		require.Contains(t, err.Error(), "failed to write migration file")
 	})
 }
+
+func TestGenerateMigrationFileWithOptions(t *testing.T) {
+	current, err := parser.ParseString(`CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.old_events (id UInt64) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	target, err := parser.ParseString(`CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Analytics';
+CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;`)
+	require.NoError(t, err)
+
+	t.Run("renders header as comments", func(t *testing.T) {
+		migrationDir := t.TempDir()
+
+		filename, err := GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{
+			HeaderTemplate: `Migration: {{ .Name }} (version {{ .Version }})
+Generated: {{ .Timestamp.Format "2006-01-02" }} by housekeeper {{ .ToolVersion }}
+Environment: {{ .Environment }}
+
+-- {{ .Description }}
+Changes: {{ .Stats.Statements }} statement(s), {{ .Stats.Creates }} create(s), {{ .Stats.Alters }} alter(s), {{ .Stats.Drops }} drop(s)
+`,
+			ToolVersion: "v1.2.3",
+			Environment: "staging",
+			Description: "Replace old events table",
+		})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(filepath.Join(migrationDir, filename))
+		require.NoError(t, err)
+
+		version := strings.TrimSuffix(filename, ".sql")
+		expectedHeader := "-- Migration: " + filename + " (version " + version + ")\n" +
+			"-- Generated: " + time.Now().UTC().Format("2006-01-02") + " by housekeeper v1.2.3\n" +
+			"-- Environment: staging\n" +
+			"--\n" +
+			"-- Replace old events table\n" +
+			"-- Changes: 3 statement(s), 1 create(s), 1 alter(s), 1 drop(s)\n\n"
+		require.True(t, strings.HasPrefix(string(content), expectedHeader), string(content))
+
+		// The header only adds comments, so the migration parses to the same statements
+		withHeader, err := parser.ParseString(string(content))
+		require.NoError(t, err)
+
+		withoutHeader, err := parser.ParseString(strings.TrimPrefix(string(content), expectedHeader))
+		require.NoError(t, err)
+
+		require.Len(t, withHeader.Statements, len(withoutHeader.Statements)+6)
+		for _, stmt := range withHeader.Statements[:6] {
+			require.NotNil(t, stmt.CommentStatement)
+		}
+
+		var withBuf, withoutBuf bytes.Buffer
+		require.NoError(t, format.FormatSQL(&withBuf, format.Defaults, &parser.SQL{Statements: withHeader.Statements[6:]}))
+		require.NoError(t, format.FormatSQL(&withoutBuf, format.Defaults, withoutHeader))
+		require.Equal(t, withoutBuf.String(), withBuf.String())
+	})
+
+	t.Run("annotates statement sources", func(t *testing.T) {
+		migrationDir := t.TempDir()
+
+		filename, err := GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{
+			Sources: SourceMap{
+				"database:analytics":     "db/schemas/analytics/schema.sql",
+				"table:analytics.events": "db/schemas/analytics/tables/events.sql",
+			},
+		})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(filepath.Join(migrationDir, filename))
+		require.NoError(t, err)
+		require.Equal(t, "-- from db/schemas/analytics/schema.sql\n"+
+			"ALTER DATABASE `analytics` MODIFY COMMENT 'Analytics';\n\n"+
+			"-- from db/schemas/analytics/tables/events.sql\n"+
+			"CREATE TABLE `analytics`.`events` (\n"+
+			"    `id`   UInt64,\n"+
+			"    `name` String\n"+
+			")\n"+
+			"ENGINE = MergeTree()\n"+
+			"ORDER BY `id`;\n\n"+
+			"DROP TABLE `analytics`.`old_events`;", string(content))
+
+		// The annotations are comments, so the migration parses to the same statements
+		annotated, err := parser.ParseString(string(content))
+		require.NoError(t, err)
+
+		var annotatedBuf, plainBuf bytes.Buffer
+		require.NoError(t, format.FormatSQL(&annotatedBuf, format.Defaults, &parser.SQL{Statements: slices.DeleteFunc(annotated.Statements, func(stmt *parser.Statement) bool {
+			return stmt.CommentStatement != nil
+		})}))
+
+		diff, err := GenerateDiff(current, target)
+		require.NoError(t, err)
+		require.NoError(t, format.FormatSQL(&plainBuf, format.Defaults, diff))
+		require.Equal(t, plainBuf.String(), annotatedBuf.String())
+	})
+
+	t.Run("appends name to the version", func(t *testing.T) {
+		migrationDir := t.TempDir()
+
+		filename, err := GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{
+			HeaderTemplate: "Version: {{ .Version }}",
+			Name:           "init",
+		})
+		require.NoError(t, err)
+		require.Regexp(t, `^\d{14}_init\.sql$`, filename)
+
+		content, err := os.ReadFile(filepath.Join(migrationDir, filename))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(content), "-- Version: "+strings.TrimSuffix(filename, ".sql")+"\n"))
+	})
+
+	t.Run("uses the given version", func(t *testing.T) {
+		migrationDir := t.TempDir()
+
+		filename, err := GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{
+			HeaderTemplate: "Version: {{ .Version }}",
+			Name:           "init",
+			Version:        "20240101000000_events",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "20240101000000_events.sql", filename)
+
+		content, err := os.ReadFile(filepath.Join(migrationDir, filename))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(content), "-- Version: 20240101000000_events\n"))
+
+		_, err = GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{Version: "../events"})
+		require.ErrorContains(t, err, `invalid migration version "../events"`)
+	})
+
+	t.Run("writes paired down file", func(t *testing.T) {
+		migrationDir := t.TempDir()
+
+		filename, err := GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{
+			Name:      "events",
+			SplitDown: true,
+		})
+		require.NoError(t, err)
+
+		up, err := os.ReadFile(filepath.Join(migrationDir, filename))
+		require.NoError(t, err)
+		require.NotContains(t, string(up), "housekeeper:down")
+
+		down, err := os.ReadFile(filepath.Join(migrationDir, strings.TrimSuffix(filename, ".sql")+".down.sql"))
+		require.NoError(t, err)
+		require.Equal(t, "CREATE TABLE `analytics`.`old_events` (\n"+
+			"    `id` UInt64\n"+
+			")\n"+
+			"ENGINE = MergeTree()\n"+
+			"ORDER BY `id`;\n\n"+
+			"DROP TABLE `analytics`.`events`;\n\n"+
+			"ALTER DATABASE `analytics` MODIFY COMMENT '';", string(down))
+
+		// Without the option, only the migration itself is written
+		migrationDir = t.TempDir()
+		_, err = GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{Name: "events"})
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(migrationDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("rejects invalid name", func(t *testing.T) {
+		migrationDir := t.TempDir()
+
+		_, err := GenerateMigrationFileWithOptions(migrationDir, current, target, MigrationFileOptions{Name: "add events"})
+		require.ErrorContains(t, err, `invalid migration name "add events"`)
+
+		entries, err := os.ReadDir(migrationDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("rejects snapshot marker", func(t *testing.T) {
+		_, err := GenerateMigrationFileWithOptions(t.TempDir(), current, target, MigrationFileOptions{
+			HeaderTemplate: "housekeeper:snapshot",
+		})
+		require.ErrorContains(t, err, "snapshot marker")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := GenerateMigrationFileWithOptions(t.TempDir(), current, target, MigrationFileOptions{
+			HeaderTemplate: "{{ .Unknown }}",
+		})
+		require.ErrorContains(t, err, "failed to render migration header template")
+	})
+}
+
+func TestGenerateDiffWithOptions_DefaultCodec(t *testing.T) {
+	current, err := parser.ParseString("CREATE TABLE db.events (id UInt64 CODEC(ZSTD(1)), name String CODEC(LZ4)) ENGINE = MergeTree() ORDER BY id;")
+	require.NoError(t, err)
+	target, err := parser.ParseString("CREATE TABLE db.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;")
+	require.NoError(t, err)
+
+	t.Run("configured default", func(t *testing.T) {
+		diff, err := GenerateDiffWithOptions(current, target, CompareOptions{DefaultCodec: "ZSTD(1)"})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, format.FormatSQL(&buf, format.Defaults, diff))
+		require.Equal(t, "ALTER TABLE `db`.`events`\n    MODIFY COLUMN `name` String;", strings.TrimSpace(buf.String()),
+			"only the column whose codec differs from the configured default should change")
+	})
+
+	t.Run("matching default", func(t *testing.T) {
+		current, err := parser.ParseString("CREATE TABLE db.events (id UInt64 CODEC(ZSTD(1)), name String CODEC(ZSTD(1))) ENGINE = MergeTree() ORDER BY id;")
+		require.NoError(t, err)
+
+		_, err = GenerateDiffWithOptions(current, target, CompareOptions{DefaultCodec: "CODEC(ZSTD(1))"})
+		require.ErrorIs(t, err, ErrNoDiff)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		_, err := GenerateDiffWithOptions(current, target, CompareOptions{DefaultCodec: "ZSTD("})
+		require.ErrorContains(t, err, "invalid default codec")
+	})
+}
+
+func TestGenerateDiffWithOptions_DetectColumnRenames(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected string
+	}{
+		{
+			fixture:  "column_rename_materialized_dependency",
+			expected: "ALTER TABLE `analytics`.`orders`\n    RENAME COLUMN `amount` TO `price`;",
+		},
+		{
+			fixture:  "column_rename_dependency_changed",
+			expected: "ALTER TABLE `analytics`.`orders`\n    RENAME COLUMN `amount` TO `price`,\n    MODIFY COLUMN `total` Decimal(18, 2) MATERIALIZED `price` * 3;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			current, target := loadDiffFixture(t, filepath.Join("testdata", tt.fixture+".in.sql"))
+
+			diff, err := GenerateDiffWithOptions(current, target, CompareOptions{DetectColumnRenames: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, format.FormatSQL(&buf, format.Defaults, diff))
+			require.Equal(t, tt.expected, strings.TrimSpace(buf.String()))
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		current, err := parser.ParseString("CREATE TABLE db.users (id UInt64, legacy_email String) ENGINE = MergeTree() ORDER BY id;")
+		require.NoError(t, err)
+		target, err := parser.ParseString("CREATE TABLE db.users (id UInt64, phone_number String) ENGINE = MergeTree() ORDER BY id;")
+		require.NoError(t, err)
+
+		diff, err := GenerateDiff(current, target)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, format.FormatSQL(&buf, format.Defaults, diff))
+		require.Equal(t, "ALTER TABLE `db`.`users`\n    ADD COLUMN `phone_number` String,\n    DROP COLUMN `legacy_email`;", strings.TrimSpace(buf.String()),
+			"an unrelated column of the same type must not inherit the dropped column's data")
+	})
+}
+
+func TestGenerateReversibleDiff(t *testing.T) {
+	current, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+	`)
+	require.NoError(t, err)
+	target, err := parser.ParseString(`
+		CREATE DATABASE analytics ENGINE = Atomic;
+		CREATE TABLE analytics.events (id UInt64, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.sessions (id UInt64) ENGINE = MergeTree() ORDER BY id;
+	`)
+	require.NoError(t, err)
+
+	diff, err := GenerateReversibleDiff(current, target, CompareOptions{})
+	require.NoError(t, err)
+
+	up, err := GenerateDiff(current, target)
+	require.NoError(t, err)
+	require.Equal(t, up, diff.Up)
+
+	// Down undoes each change in reverse order
+	var buf bytes.Buffer
+	require.NoError(t, format.FormatSQL(&buf, format.Defaults, diff.Down))
+	require.Equal(t, "ALTER TABLE `analytics`.`events`\n    DROP COLUMN `ts`;\n\nDROP TABLE `analytics`.`sessions`;",
+		strings.TrimSpace(buf.String()))
+
+	t.Run("no differences", func(t *testing.T) {
+		_, err := GenerateReversibleDiff(current, current, CompareOptions{})
+		require.ErrorIs(t, err, ErrNoDiff)
+	})
+}
+
+// loadDiffFixture parses the current and target schemas of a testdata/*.in.sql file. The
+// target section starts at the "-- Target state:" comment.
+func loadDiffFixture(t *testing.T, inputPath string) (*parser.SQL, *parser.SQL) {
+	t.Helper()
+
+	// Read input file containing 2 SQL statements
+	inputData, err := os.ReadFile(inputPath)
+	require.NoError(t, err)
+
+	inputSQL := string(inputData)
+
+	// Split input into current and target SQL by looking for comment sections
+	// Each .in.sql file should have a current state section and a target state section
+	lines := strings.Split(inputSQL, "\n")
+
+	var currentSQLLines, targetSQLLines []string
+	inTargetSection := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-- Target state:") {
+			inTargetSection = true
+			continue
+		}
+		if strings.HasPrefix(line, "--") {
+			// Skip other comment lines
+			continue
+		}
+		if line == "" {
+			// Skip empty lines
+			continue
+		}
+
+		// Skip lines that are just a semicolon (empty state markers)
+		if line == ";" {
+			continue
+		}
+
+		if inTargetSection {
+			targetSQLLines = append(targetSQLLines, line)
+		} else {
+			currentSQLLines = append(currentSQLLines, line)
+		}
+	}
+
+	currentSQLText := strings.Join(currentSQLLines, " ")
+	targetSQLText := strings.Join(targetSQLLines, " ")
+
+	// Clean up any double spaces and normalize
+	currentSQLText = strings.Join(strings.Fields(currentSQLText), " ")
+	targetSQLText = strings.Join(strings.Fields(targetSQLText), " ")
+
+	// Parse current and target SQL
+	var currentSQL, targetSQL *parser.SQL
+
+	if currentSQLText == "" {
+		currentSQL = &parser.SQL{Statements: []*parser.Statement{}}
+	} else {
+		// Ensure currentSQLText ends with semicolon for parser
+		if !strings.HasSuffix(currentSQLText, ";") {
+			currentSQLText += ";"
+		}
+		currentSQL, err = parser.ParseString(currentSQLText)
+		require.NoError(t, err)
+	}
+
+	if targetSQLText == "" {
+		targetSQL = &parser.SQL{Statements: []*parser.Statement{}}
+	} else {
+		// Ensure targetSQLText ends with semicolon for parser
+		if !strings.HasSuffix(targetSQLText, ";") {
+			targetSQLText += ";"
+		}
+		targetSQL, err = parser.ParseString(targetSQLText)
+		require.NoError(t, err)
+	}
+
+	return currentSQL, targetSQL
+}
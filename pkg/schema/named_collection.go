@@ -0,0 +1,294 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/consts"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/utils"
+)
+
+const (
+	// NamedCollectionDiffCreate indicates a named collection needs to be created
+	NamedCollectionDiffCreate NamedCollectionDiffType = "CREATE"
+	// NamedCollectionDiffAlter indicates a named collection needs to be altered
+	NamedCollectionDiffAlter NamedCollectionDiffType = "ALTER"
+	// NamedCollectionDiffDrop indicates a named collection needs to be dropped
+	NamedCollectionDiffDrop NamedCollectionDiffType = "DROP"
+)
+
+type (
+	// NamedCollectionDiff represents a difference between current and target named collection states.
+	// It contains all information needed to generate migration SQL statements for
+	// named collection operations including CREATE, ALTER, and DROP.
+	NamedCollectionDiff struct {
+		DiffBase                      // Embeds Type, Name, NewName, Description, UpSQL, DownSQL
+		Current  *NamedCollectionInfo // Current state (nil if collection doesn't exist)
+		Target   *NamedCollectionInfo // Target state (nil if collection should be dropped)
+	}
+
+	// NamedCollectionDiffType represents the type of named collection difference
+	NamedCollectionDiffType string
+
+	// NamedCollectionInfo represents parsed named collection information extracted from DDL statements.
+	// Comments aren't tracked since ClickHouse doesn't report them in system.named_collections
+	// and they can't be changed with ALTER NAMED COLLECTION.
+	NamedCollectionInfo struct {
+		Name       string                           // Collection name
+		Keys       []string                         // Parameter keys in declaration order
+		Parameters map[string]*NamedCollectionParam // Parameters by key
+		Cluster    string                           // Cluster name if specified (empty if not clustered)
+	}
+
+	// NamedCollectionParam is a single key/value pair of a named collection.
+	NamedCollectionParam struct {
+		Value    string                          // Value as written in SQL, e.g. 'localhost' or 5432
+		Override *parser.NamedCollectionOverride // [NOT] OVERRIDABLE clause (nil if unspecified)
+	}
+)
+
+// GetName implements SchemaObject interface
+func (n *NamedCollectionInfo) GetName() string {
+	return n.Name
+}
+
+// GetCluster implements SchemaObject interface
+func (n *NamedCollectionInfo) GetCluster() string {
+	return n.Cluster
+}
+
+// PropertiesMatch implements SchemaObject interface.
+// Returns true if the two collections have identical parameters (excluding name).
+func (n *NamedCollectionInfo) PropertiesMatch(other SchemaObject) bool {
+	otherNC, ok := other.(*NamedCollectionInfo)
+	if !ok {
+		return false
+	}
+	set, deleted := namedCollectionChanges(n, otherNC)
+	return n.Cluster == otherNC.Cluster && len(set) == 0 && len(deleted) == 0
+}
+
+// compareNamedCollections compares current and target named collections and returns migration diffs.
+//
+// Collections present only in the target are created, collections present only in the current
+// schema are dropped, and collections whose parameters differ are altered with SET/DELETE.
+// Renames aren't supported by ClickHouse, so a renamed collection is a DROP and a CREATE.
+//
+// Values dumped from ClickHouse may be masked (see consts.HiddenSecretValue), in which case
+// they're considered equal to any target value since the actual value can't be compared.
+func compareNamedCollections(current, target *parser.SQL, opts CompareOptions) ([]*NamedCollectionDiff, error) {
+	currentMap := extractNamedCollectionInfo(current)
+	targetMap := extractNamedCollectionInfo(target)
+
+	diffs := make([]*NamedCollectionDiff, 0, len(currentMap)+len(targetMap))
+
+	// Find collections to create or modify (sorted for deterministic order)
+	for _, name := range SortedKeys(targetMap) {
+		targetNC := targetMap[name]
+		currentNC, exists := currentMap[name]
+
+		if !exists {
+			diffs = append(diffs, &NamedCollectionDiff{
+				DiffBase: DiffBase{
+					Type:        string(NamedCollectionDiffCreate),
+					Name:        name,
+					Description: "Create named collection " + name,
+				}.withSQL(opts, func() (string, string) {
+					return generateCreateNamedCollectionSQL(targetNC), generateDropNamedCollectionSQL(targetNC)
+				}),
+				Target: targetNC,
+			})
+			continue
+		}
+
+		if currentNC.Cluster != targetNC.Cluster {
+			return nil, errors.Wrapf(ErrUnsupported,
+				"cannot change cluster of named collection %s from '%s' to '%s': %v",
+				name, currentNC.Cluster, targetNC.Cluster, ErrClusterChange)
+		}
+
+		if set, deleted := namedCollectionChanges(currentNC, targetNC); len(set) > 0 || len(deleted) > 0 {
+			diffs = append(diffs, &NamedCollectionDiff{
+				DiffBase: DiffBase{
+					Type:        string(NamedCollectionDiffAlter),
+					Name:        name,
+					Description: "Alter named collection " + name,
+				}.withSQL(opts, func() (string, string) {
+					return generateAlterNamedCollectionSQL(currentNC, targetNC), generateAlterNamedCollectionSQL(targetNC, currentNC)
+				}),
+				Current: currentNC,
+				Target:  targetNC,
+			})
+		}
+	}
+
+	// Find collections to drop (sorted for deterministic order)
+	for _, name := range FilterExcluding(currentMap, targetMap) {
+		currentNC := currentMap[name]
+		diffs = append(diffs, &NamedCollectionDiff{
+			DiffBase: DiffBase{
+				Type:        string(NamedCollectionDiffDrop),
+				Name:        name,
+				Description: "Drop named collection " + name,
+			}.withSQL(opts, func() (string, string) {
+				return generateDropNamedCollectionSQL(currentNC), generateCreateNamedCollectionSQL(currentNC)
+			}),
+			Current: currentNC,
+		})
+	}
+
+	return diffs, nil
+}
+
+// extractNamedCollectionInfo extracts named collection information from parsed SQL statements
+func extractNamedCollectionInfo(sql *parser.SQL) map[string]*NamedCollectionInfo {
+	collections := make(map[string]*NamedCollectionInfo)
+
+	for _, stmt := range sql.Statements {
+		if stmt.CreateNamedCollection == nil {
+			continue
+		}
+
+		create := stmt.CreateNamedCollection
+		nc := &NamedCollectionInfo{
+			Name:       create.Name,
+			Parameters: make(map[string]*NamedCollectionParam, len(create.Parameters)),
+		}
+
+		for _, param := range create.Parameters {
+			override := param.Override
+			if override == nil {
+				override = create.GlobalOverride
+			}
+
+			if _, exists := nc.Parameters[param.Key]; !exists {
+				nc.Keys = append(nc.Keys, param.Key)
+			}
+			nc.Parameters[param.Key] = &NamedCollectionParam{
+				Value:    namedCollectionValueSQL(param.Value),
+				Override: override,
+			}
+		}
+
+		if create.OnCluster != nil {
+			nc.Cluster = *create.OnCluster
+		}
+
+		collections[nc.Name] = nc
+	}
+
+	return collections
+}
+
+// namedCollectionValueSQL returns the SQL representation of a parameter value
+func namedCollectionValueSQL(value *parser.NamedCollectionValue) string {
+	if value.String != nil {
+		return *value.String
+	}
+	return value.GetValue()
+}
+
+// namedCollectionChanges returns the keys of target that must be set, and the keys of
+// current that must be deleted, to migrate from current to target
+func namedCollectionChanges(current, target *NamedCollectionInfo) (set, deleted []string) {
+	for _, key := range target.Keys {
+		currentParam, exists := current.Parameters[key]
+		if !exists || !namedCollectionParamsEqual(currentParam, target.Parameters[key]) {
+			set = append(set, key)
+		}
+	}
+
+	for _, key := range current.Keys {
+		if _, exists := target.Parameters[key]; !exists {
+			deleted = append(deleted, key)
+		}
+	}
+
+	return set, deleted
+}
+
+// namedCollectionParamsEqual compares two parameters. String and numeric values are compared
+// by content (ClickHouse reports all values as strings), and a masked value matches any value.
+// Overrides are only compared when both are specified, since ClickHouse doesn't report them.
+func namedCollectionParamsEqual(a, b *NamedCollectionParam) bool {
+	aValue, bValue := removeQuotes(a.Value), removeQuotes(b.Value)
+	if aValue != bValue && aValue != consts.HiddenSecretValue && bValue != consts.HiddenSecretValue {
+		return false
+	}
+
+	if a.Override != nil && b.Override != nil {
+		return a.Override.IsOverridable() == b.Override.IsOverridable()
+	}
+
+	return true
+}
+
+// generateCreateNamedCollectionSQL generates CREATE NAMED COLLECTION SQL statement
+func generateCreateNamedCollectionSQL(nc *NamedCollectionInfo) string {
+	params := make([]string, 0, len(nc.Keys))
+	for _, key := range nc.Keys {
+		params = append(params, namedCollectionParamSQL(key, nc.Parameters[key]))
+	}
+
+	return utils.NewSQLBuilder().
+		Create("NAMED COLLECTION").
+		Name(nc.Name).
+		OnCluster(nc.Cluster).
+		As(strings.Join(params, ", ")).
+		String()
+}
+
+// generateAlterNamedCollectionSQL generates the ALTER NAMED COLLECTION SQL statement setting
+// the changed parameters of target and deleting the parameters missing from it
+func generateAlterNamedCollectionSQL(current, target *NamedCollectionInfo) string {
+	set, deleted := namedCollectionChanges(current, target)
+
+	sets := make([]string, len(set))
+	for i, key := range set {
+		sets[i] = namedCollectionParamSQL(key, target.Parameters[key])
+	}
+
+	deletes := make([]string, len(deleted))
+	for i, key := range deleted {
+		deletes[i] = utils.BacktickIdentifier(key)
+	}
+
+	builder := utils.NewSQLBuilder().
+		Alter("NAMED COLLECTION").
+		Name(target.Name).
+		OnCluster(target.Cluster)
+
+	if len(sets) > 0 {
+		builder.Raw("SET " + strings.Join(sets, ", "))
+	}
+	if len(deletes) > 0 {
+		builder.Raw("DELETE " + strings.Join(deletes, ", "))
+	}
+
+	return builder.String()
+}
+
+// namedCollectionParamSQL returns the key = value [[NOT] OVERRIDABLE] clause of a parameter
+func namedCollectionParamSQL(key string, param *NamedCollectionParam) string {
+	sql := fmt.Sprintf("%s = %s", utils.BacktickIdentifier(key), param.Value)
+	if param.Override != nil {
+		if param.Override.IsOverridable() {
+			sql += " OVERRIDABLE"
+		} else {
+			sql += " NOT OVERRIDABLE"
+		}
+	}
+	return sql
+}
+
+// generateDropNamedCollectionSQL generates DROP NAMED COLLECTION SQL statement
+func generateDropNamedCollectionSQL(nc *NamedCollectionInfo) string {
+	return utils.NewSQLBuilder().
+		Drop("NAMED COLLECTION").
+		IfExists().
+		Name(nc.Name).
+		OnCluster(nc.Cluster).
+		String()
+}
@@ -42,6 +42,9 @@ func FlattenNestedColumns(table *TableInfo) *TableInfo {
 		AsSourceTable: table.AsSourceTable,
 		AsDependents:  copyBoolMap(table.AsDependents),
 		Columns:       make([]ColumnInfo, 0, len(table.Columns)),
+		Indexes:       table.Indexes,
+		Constraints:   table.Constraints,
+		Projections:   table.Projections,
 	}
 
 	// Process each column
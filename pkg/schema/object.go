@@ -1,5 +1,12 @@
 package schema
 
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
 // SchemaObject is the interface implemented by all schema object Info types
 // (DatabaseInfo, TableInfo, DictionaryInfo, ViewInfo, FunctionInfo, RoleInfo).
 //
@@ -23,3 +30,105 @@ type SchemaObject interface {
 	// but implementations must still perform type checking via type assertion.
 	PropertiesMatch(other SchemaObject) bool
 }
+
+// Object types reported by ListObjects
+const (
+	ObjectTypeDatabase         ObjectType = "database"
+	ObjectTypeTable            ObjectType = "table"
+	ObjectTypeDictionary       ObjectType = "dictionary"
+	ObjectTypeView             ObjectType = "view"
+	ObjectTypeMaterializedView ObjectType = "materialized_view"
+	ObjectTypeFunction         ObjectType = "function"
+	ObjectTypeRole             ObjectType = "role"
+	ObjectTypeNamedCollection  ObjectType = "named_collection"
+)
+
+type (
+	// ObjectType identifies the kind of a schema object
+	ObjectType string
+
+	// ObjectRef is a flat reference to a schema object returned by ListObjects.
+	ObjectRef struct {
+		Type    ObjectType // Kind of object
+		Name    string     // Fully-qualified name (database.name for tables, views, and dictionaries)
+		Cluster string     // Cluster name if specified (empty if not clustered)
+	}
+)
+
+// objectTypeOrder is the order in which ListObjects reports object types
+var objectTypeOrder = []ObjectType{
+	ObjectTypeDatabase,
+	ObjectTypeTable,
+	ObjectTypeDictionary,
+	ObjectTypeView,
+	ObjectTypeMaterializedView,
+	ObjectTypeFunction,
+	ObjectTypeRole,
+	ObjectTypeNamedCollection,
+}
+
+// ListObjects returns an inventory of every database, table, dictionary, view,
+// function, role, and named collection created in the parsed SQL. Objects are ordered by type
+// (databases first, named collections last) and then by name, giving tools a stable, flat
+// listing without walking the AST.
+//
+// Example:
+//
+//	sql, err := parser.ParseString(schemaSQL)
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, obj := range schema.ListObjects(sql) {
+//		fmt.Printf("%s %s\n", obj.Type, obj.Name)
+//	}
+func ListObjects(sql *parser.SQL) []ObjectRef {
+	var objects []ObjectRef
+	add := func(typ ObjectType, obj SchemaObject) {
+		objects = append(objects, ObjectRef{Type: typ, Name: obj.GetName(), Cluster: obj.GetCluster()})
+	}
+
+	for _, db := range extractDatabaseInfo(sql) {
+		add(ObjectTypeDatabase, db)
+	}
+
+	// AS reference resolution errors only affect column inheritance, which the inventory
+	// doesn't need, so the tables are listed regardless
+	tables, _ := extractTablesFromSQL(sql)
+	for _, table := range tables {
+		add(ObjectTypeTable, table)
+	}
+
+	for _, dict := range extractDictionaryInfo(sql) {
+		add(ObjectTypeDictionary, dict)
+	}
+
+	for _, view := range extractViewsFromSQL(sql) {
+		if view.IsMaterialized {
+			add(ObjectTypeMaterializedView, view)
+		} else {
+			add(ObjectTypeView, view)
+		}
+	}
+
+	for _, fn := range extractFunctionInfoAsMap(sql) {
+		add(ObjectTypeFunction, fn)
+	}
+
+	for _, role := range extractRoleInfo(sql) {
+		add(ObjectTypeRole, role)
+	}
+
+	for _, nc := range extractNamedCollectionInfo(sql) {
+		add(ObjectTypeNamedCollection, nc)
+	}
+
+	slices.SortFunc(objects, func(a, b ObjectRef) int {
+		if c := cmp.Compare(slices.Index(objectTypeOrder, a.Type), slices.Index(objectTypeOrder, b.Type)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return objects
+}
@@ -0,0 +1,44 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListObjects(t *testing.T) {
+	sql, err := parser.ParseString(`
+		CREATE ROLE IF NOT EXISTS analyst ON CLUSTER prod;
+		CREATE FUNCTION normalize_os ON CLUSTER prod AS (os) -> lower(os);
+		CREATE MATERIALIZED VIEW analytics.daily_events ENGINE = MergeTree() ORDER BY day
+			AS SELECT toDate(ts) AS day, count() AS total FROM analytics.events GROUP BY day;
+		CREATE VIEW analytics.recent_events AS SELECT * FROM analytics.events;
+		CREATE DICTIONARY analytics.users_dict (id UInt64, name String)
+			PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/users' format 'TSV')) LAYOUT(HASHED()) LIFETIME(300);
+		CREATE TABLE analytics.events ON CLUSTER prod (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE analytics.events_copy AS analytics.events ENGINE = MergeTree() ORDER BY id;
+		CREATE DATABASE analytics ON CLUSTER prod ENGINE = Atomic;
+		CREATE DATABASE logs;
+	`)
+	require.NoError(t, err)
+
+	require.Equal(t, []schema.ObjectRef{
+		{Type: schema.ObjectTypeDatabase, Name: "analytics", Cluster: "prod"},
+		{Type: schema.ObjectTypeDatabase, Name: "logs"},
+		{Type: schema.ObjectTypeTable, Name: "analytics.events", Cluster: "prod"},
+		{Type: schema.ObjectTypeTable, Name: "analytics.events_copy"},
+		{Type: schema.ObjectTypeDictionary, Name: "analytics.users_dict"},
+		{Type: schema.ObjectTypeView, Name: "analytics.recent_events"},
+		{Type: schema.ObjectTypeMaterializedView, Name: "analytics.daily_events"},
+		{Type: schema.ObjectTypeFunction, Name: "normalize_os", Cluster: "prod"},
+		{Type: schema.ObjectTypeRole, Name: "analyst", Cluster: "prod"},
+	}, schema.ListObjects(sql))
+}
+
+func TestListObjects_Empty(t *testing.T) {
+	sql, err := parser.ParseString(`-- nothing here`)
+	require.NoError(t, err)
+	require.Empty(t, schema.ListObjects(sql))
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/pseudomuto/housekeeper/pkg/compare"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+const (
+	// ProjectionDiffAdd indicates a projection needs to be added
+	ProjectionDiffAdd ProjectionDiffType = "ADD"
+	// ProjectionDiffDrop indicates a projection needs to be dropped
+	ProjectionDiffDrop ProjectionDiffType = "DROP"
+)
+
+type (
+	// ProjectionInfo represents a projection declared in a table definition
+	ProjectionInfo struct {
+		Name   string                  // Projection name
+		Select *parser.SelectStatement // Projection SELECT AST
+	}
+
+	// ProjectionDiff represents a difference in projection definitions. ClickHouse
+	// can't modify a projection, so a changed projection is expressed as a DROP followed by an ADD.
+	ProjectionDiff struct {
+		Type        ProjectionDiffType // Type of projection operation
+		Projection  *ProjectionInfo    // Projection being added or dropped
+		Description string             // Human-readable description
+	}
+
+	// ProjectionDiffType represents the type of projection difference
+	ProjectionDiffType string
+)
+
+// Equal compares two ProjectionInfo instances for equality, comparing the projection
+// queries structurally (see projectionSelectsAreEqual)
+func (p ProjectionInfo) Equal(other ProjectionInfo) bool {
+	return p.Name == other.Name && projectionSelectsAreEqual(p.Select, other.Select)
+}
+
+// projectionSelectsAreEqual compares the queries of two projections. A projection query
+// is made of its selected columns (including the aggregations), GROUP BY, and ORDER BY,
+// so each of them is compared in full. Unlike views, the lenient comparison of
+// selectClausesAreEqualWithTolerance isn't used, since it would miss a changed GROUP BY
+// key or aggregate function and leave the projection stale.
+func projectionSelectsAreEqual(select1, select2 *parser.SelectStatement) bool {
+	if eq, done := compare.NilCheck(select1, select2); !done {
+		return eq
+	}
+
+	return selectColumnsAreEqual(select1.Columns, select2.Columns) &&
+		groupByClausesAreEqual(select1.GroupBy, select2.GroupBy) &&
+		selectOrderByClausesAreEqual(select1.OrderBy, select2.OrderBy)
+}
+
+// newProjectionInfo extracts projection information from a projection definition
+func newProjectionInfo(def *parser.ProjectionDefinition) ProjectionInfo {
+	return ProjectionInfo{
+		Name:   normalizeIdentifier(def.Name),
+		Select: &def.SelectClause.SelectStmt,
+	}
+}
+
+// elementName returns the name the projection is matched by (see tableElement)
+func (p ProjectionInfo) elementName() string {
+	return p.Name
+}
+
+// renameColumns returns the projection with renamed columns replaced in its query
+func (p ProjectionInfo) renameColumns(renames map[string]string) ProjectionInfo {
+	if p.Select == nil || len(renames) == 0 {
+		return p
+	}
+
+	rewritten := copyRenamingIdentifiers(reflect.ValueOf(*p.Select), renames).Interface().(parser.SelectStatement)
+	p.Select = &rewritten
+	return p
+}
+
+// newProjectionDiff creates the ProjectionDiff adding or dropping a projection (see compareTableElements)
+func newProjectionDiff(add bool, projection ProjectionInfo) ProjectionDiff {
+	if add {
+		return ProjectionDiff{Type: ProjectionDiffAdd, Projection: &projection, Description: "Add projection " + projection.Name}
+	}
+	return ProjectionDiff{Type: ProjectionDiffDrop, Projection: &projection, Description: "Drop projection " + projection.Name}
+}
+
+// change returns whether the projection is added, and the projection (see tableElementDiff)
+func (d ProjectionDiff) change() (bool, ProjectionInfo) {
+	return d.Type == ProjectionDiffAdd, *d.Projection
+}
+
+// formatProjectionDefinition formats a projection for CREATE TABLE and ALTER TABLE ... ADD PROJECTION
+func formatProjectionDefinition(projection ProjectionInfo) string {
+	return "`" + projection.Name + "` (" + selectStatementToString(projection.Select) + ")"
+}
@@ -0,0 +1,125 @@
+package schema
+
+const (
+	// RiskLow is the score of migrations that only add to the schema
+	RiskLow RiskScore = "low"
+	// RiskMedium is the score of migrations that modify existing objects in place,
+	// including column type changes that rewrite a table's data as a mutation
+	RiskMedium RiskScore = "medium"
+	// RiskHigh is the score of migrations that drop objects, recreate tables or
+	// materialized views that store data, or narrow column types
+	RiskHigh RiskScore = "high"
+)
+
+// Weights of the changes in a RiskReport. Every change contributes the weight of its
+// class, so that migrations with the same score can still be ranked by how much they
+// change. Mutations weigh more than other in-place modifications since they rewrite
+// data, and more still when the table is known to have at least LargeTableRows rows.
+const (
+	// RiskWeightAdditive is the weight of an additive change
+	RiskWeightAdditive = 1
+	// RiskWeightModifying is the weight of a modifying change that doesn't rewrite data
+	RiskWeightModifying = 10
+	// RiskWeightMutation is the weight of a modifying change that rewrites a table's data
+	RiskWeightMutation = 25
+	// RiskWeightLargeMutation is the weight of a mutation on a table with at least
+	// LargeTableRows rows
+	RiskWeightLargeMutation = 50
+	// RiskWeightDestructive is the weight of a destructive change
+	RiskWeightDestructive = 100
+
+	// LargeTableRows is the row count from which a table is considered large
+	LargeTableRows = 10_000_000
+)
+
+type (
+	// RiskScore is an at-a-glance indicator of how risky it is to apply a migration
+	RiskScore string
+
+	// TableRows holds the row counts of live tables keyed by their qualified name (e.g.
+	// "analytics.events"), which ScoreDiff uses to weigh mutations by the amount of data
+	// they rewrite. Tables without a row count are scored by their structure only.
+	TableRows map[string]uint64
+
+	// RiskItem is the scored risk of a single change
+	RiskItem struct {
+		Change Change    // The scored change
+		Score  RiskScore // The risk of applying the change
+		Weight int       // The weight of the change (see RiskWeightAdditive etc.)
+		Rows   *uint64   // The row count of the changed table, when known
+	}
+
+	// RiskReport is the scored risk of a migration, as returned by ScoreDiff
+	RiskReport struct {
+		Score  RiskScore  // The highest risk of any change, or RiskLow when there are none
+		Weight int        // The sum of the weights of the changes
+		Items  []RiskItem // The scored changes, in the order they're applied
+	}
+)
+
+// ScoreDiff scores the risk of applying the changes returned by CompareMetadata. The
+// score of a migration is the highest score of its changes:
+//
+//   - RiskLow: additive changes, e.g. new objects or new columns on existing tables
+//   - RiskMedium: in-place modifications, e.g. changed settings, comments, or view
+//     queries, and widening column type changes, which rewrite data as a mutation
+//   - RiskHigh: destructive changes, i.e. drops, recreated tables and materialized
+//     views that store data, and narrowing column type changes
+//
+// The rows are optional: without them, changes are scored offline by their structure
+// only. With them, mutations on tables with at least LargeTableRows rows are weighed
+// as such, and every item on a table with a known row count reports it.
+//
+// Example:
+//
+//	changes, err := schema.CompareMetadata(current, target)
+//	if err != nil {
+//		return err
+//	}
+//
+//	report := schema.ScoreDiff(changes, nil)
+//	fmt.Printf("risk: %s (weight %d)\n", report.Score, report.Weight)
+func ScoreDiff(changes Changes, rows TableRows) RiskReport {
+	report := RiskReport{Score: RiskLow}
+	for _, change := range changes {
+		item := RiskItem{Change: change}
+		if change.ObjectType == ObjectTypeTable {
+			if count, ok := rows[change.Name]; ok {
+				item.Rows = &count
+			}
+		}
+
+		switch {
+		case change.Class == ChangeDestructive:
+			item.Score, item.Weight = RiskHigh, RiskWeightDestructive
+		case change.Class == ChangeAdditive:
+			item.Score, item.Weight = RiskLow, RiskWeightAdditive
+		case change.Mutation && item.Rows != nil && *item.Rows >= LargeTableRows:
+			item.Score, item.Weight = RiskMedium, RiskWeightLargeMutation
+		case change.Mutation:
+			item.Score, item.Weight = RiskMedium, RiskWeightMutation
+		default:
+			item.Score, item.Weight = RiskMedium, RiskWeightModifying
+		}
+
+		report.Items = append(report.Items, item)
+		report.Weight += item.Weight
+		if riskRank(item.Score) > riskRank(report.Score) {
+			report.Score = item.Score
+		}
+	}
+
+	return report
+}
+
+// riskRank orders risk scores from lowest to highest
+func riskRank(score RiskScore) int {
+	switch score {
+	case RiskHigh:
+		return 2
+	case RiskMedium:
+		return 1
+	default:
+		return 0
+	}
+}
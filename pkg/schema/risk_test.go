@@ -0,0 +1,129 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	. "github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreDiff(t *testing.T) {
+	const current = `
+		CREATE DATABASE db ENGINE = Atomic;
+		CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY id;
+		CREATE TABLE db.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;`
+
+	changes := func(t *testing.T, target string) Changes {
+		t.Helper()
+
+		currentSQL, err := parser.ParseString(current)
+		require.NoError(t, err)
+		targetSQL, err := parser.ParseString(target)
+		require.NoError(t, err)
+
+		changes, err := CompareMetadata(currentSQL, targetSQL)
+		require.NoError(t, err)
+		return changes
+	}
+
+	const (
+		db     = "CREATE DATABASE db ENGINE = Atomic;"
+		legacy = "CREATE TABLE db.legacy (id UInt64) ENGINE = MergeTree() ORDER BY id;"
+	)
+
+	tests := []struct {
+		name   string
+		target string
+		rows   TableRows
+		score  RiskScore
+		weight int
+	}{
+		{
+			name:   "additive only",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt32, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id; CREATE TABLE db.users (id UInt64) ENGINE = MergeTree() ORDER BY id;",
+			score:  RiskLow,
+			weight: 2 * RiskWeightAdditive,
+		},
+		{
+			name:   "in-place modification",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY id COMMENT 'Events';",
+			score:  RiskMedium,
+			weight: RiskWeightModifying,
+		},
+		{
+			name:   "mutation",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt64, name String) ENGINE = MergeTree() ORDER BY id;",
+			score:  RiskMedium,
+			weight: RiskWeightMutation,
+		},
+		{
+			name:   "mutation on a small table",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt64, name String) ENGINE = MergeTree() ORDER BY id;",
+			rows:   TableRows{"db.events": 1_000},
+			score:  RiskMedium,
+			weight: RiskWeightMutation,
+		},
+		{
+			name:   "mutation on a large table",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt64, name String) ENGINE = MergeTree() ORDER BY id;",
+			rows:   TableRows{"db.events": LargeTableRows},
+			score:  RiskMedium,
+			weight: RiskWeightLargeMutation,
+		},
+		{
+			name:   "narrowing",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt16, name String) ENGINE = MergeTree() ORDER BY id;",
+			score:  RiskHigh,
+			weight: RiskWeightDestructive,
+		},
+		{
+			name:   "drop",
+			target: db + "CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY id;",
+			score:  RiskHigh,
+			weight: RiskWeightDestructive,
+		},
+		{
+			name:   "recreate",
+			target: db + legacy + "CREATE TABLE db.events (id UInt64, count UInt32, name String) ENGINE = MergeTree() ORDER BY name;",
+			score:  RiskHigh,
+			weight: RiskWeightDestructive,
+		},
+		{
+			name:   "mixed",
+			target: "CREATE DATABASE db ENGINE = Atomic COMMENT 'Main'; CREATE TABLE db.events (id UInt64, count UInt32, name String, ts DateTime) ENGINE = MergeTree() ORDER BY id;",
+			score:  RiskHigh,
+			weight: RiskWeightModifying + RiskWeightAdditive + RiskWeightDestructive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := ScoreDiff(changes(t, tt.target), tt.rows)
+			require.Equal(t, tt.score, report.Score)
+			require.Equal(t, tt.weight, report.Weight)
+		})
+	}
+
+	t.Run("items", func(t *testing.T) {
+		report := ScoreDiff(
+			changes(t, db+"CREATE TABLE db.events (id UInt64, count UInt64, name String) ENGINE = MergeTree() ORDER BY id;"),
+			TableRows{"db.events": 42, "db.legacy": 7},
+		)
+
+		require.Len(t, report.Items, 2)
+		require.Equal(t, "db.events", report.Items[0].Change.Name)
+		require.Equal(t, RiskMedium, report.Items[0].Score)
+		require.Equal(t, uint64(42), *report.Items[0].Rows)
+		require.Equal(t, "db.legacy", report.Items[1].Change.Name)
+		require.Equal(t, RiskHigh, report.Items[1].Score)
+		require.Equal(t, uint64(7), *report.Items[1].Rows)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		report := ScoreDiff(nil, nil)
+		require.Equal(t, RiskLow, report.Score)
+		require.Zero(t, report.Weight)
+		require.Empty(t, report.Items)
+	})
+}
@@ -106,7 +106,7 @@ func (r *RoleInfo) PropertiesMatch(other SchemaObject) bool {
 // The function intelligently detects rename operations by comparing role properties
 // (settings, cluster) excluding the name. If two roles have identical properties
 // but different names, it generates a RENAME operation instead of DROP+CREATE.
-func compareRoles(current, target *parser.SQL) []*RoleDiff {
+func compareRoles(current, target *parser.SQL, opts CompareOptions) []*RoleDiff {
 	// Extract role information from both SQL structures
 	currentRoles := extractRoleInfo(current)
 	targetRoles := extractRoleInfo(target)
@@ -131,9 +131,9 @@ func compareRoles(current, target *parser.SQL) []*RoleDiff {
 				Name:        rename.OldName,
 				NewName:     rename.NewName,
 				Description: fmt.Sprintf("Rename role '%s' to '%s'", rename.OldName, rename.NewName),
-				UpSQL:       generateRenameRoleSQL(currentRole, rename.NewName),
-				DownSQL:     generateRenameRoleSQL(targetRole, rename.OldName),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateRenameRoleSQL(currentRole, rename.NewName), generateRenameRoleSQL(targetRole, rename.OldName)
+			}),
 			Current: currentRole,
 			Target:  targetRole,
 		}
@@ -152,9 +152,9 @@ func compareRoles(current, target *parser.SQL) []*RoleDiff {
 					Type:        string(RoleDiffCreate),
 					Name:        name,
 					Description: fmt.Sprintf("Create role '%s'", name),
-					UpSQL:       generateCreateRoleSQL(targetRole),
-					DownSQL:     generateDropRoleSQL(targetRole),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateCreateRoleSQL(targetRole), generateDropRoleSQL(targetRole)
+				}),
 				Target: targetRole,
 			}
 			diffs = append(diffs, diff)
@@ -165,9 +165,9 @@ func compareRoles(current, target *parser.SQL) []*RoleDiff {
 					Type:        string(RoleDiffAlter),
 					Name:        name,
 					Description: fmt.Sprintf("Alter role '%s'", name),
-					UpSQL:       generateAlterRoleSQL(currentRole, targetRole),
-					DownSQL:     generateAlterRoleSQL(targetRole, currentRole),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateAlterRoleSQL(currentRole, targetRole), generateAlterRoleSQL(targetRole, currentRole)
+				}),
 				Current: currentRole,
 				Target:  targetRole,
 			}
@@ -183,16 +183,16 @@ func compareRoles(current, target *parser.SQL) []*RoleDiff {
 				Type:        string(RoleDiffDrop),
 				Name:        name,
 				Description: fmt.Sprintf("Drop role '%s'", name),
-				UpSQL:       generateDropRoleSQL(currentRole),
-				DownSQL:     generateCreateRoleSQL(currentRole),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropRoleSQL(currentRole), generateCreateRoleSQL(currentRole)
+			}),
 			Current: currentRole,
 		}
 		diffs = append(diffs, diff)
 	}
 
 	// Compare grants and generate GRANT/REVOKE operations
-	grantDiffs := compareGrants(currentGrants, targetGrants)
+	grantDiffs := compareGrants(currentGrants, targetGrants, opts)
 	diffs = append(diffs, grantDiffs...)
 
 	return diffs
@@ -326,7 +326,7 @@ func generateGrantKey(grant *GrantInfo) string {
 }
 
 // compareGrants compares grant information and generates GRANT/REVOKE diffs
-func compareGrants(current, target []*GrantInfo) []*RoleDiff {
+func compareGrants(current, target []*GrantInfo, opts CompareOptions) []*RoleDiff {
 	var diffs []*RoleDiff
 
 	// Build maps for easier comparison
@@ -349,9 +349,9 @@ func compareGrants(current, target []*GrantInfo) []*RoleDiff {
 				DiffBase: DiffBase{
 					Type:        string(RoleDiffGrant),
 					Description: fmt.Sprintf("Grant %s to %s", strings.Join(targetGrant.Privileges, ", "), targetGrant.Grantee),
-					UpSQL:       generateGrantSQL(targetGrant),
-					DownSQL:     generateRevokeSQL(targetGrant),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateGrantSQL(targetGrant), generateRevokeSQL(targetGrant)
+				}),
 			}
 			diffs = append(diffs, diff)
 		}
@@ -364,9 +364,9 @@ func compareGrants(current, target []*GrantInfo) []*RoleDiff {
 				DiffBase: DiffBase{
 					Type:        string(RoleDiffRevoke),
 					Description: fmt.Sprintf("Revoke %s from %s", strings.Join(currentGrant.Privileges, ", "), currentGrant.Grantee),
-					UpSQL:       generateRevokeSQL(currentGrant),
-					DownSQL:     generateGrantSQL(currentGrant),
-				},
+				}.withSQL(opts, func() (string, string) {
+					return generateRevokeSQL(currentGrant), generateGrantSQL(currentGrant)
+				}),
 			}
 			diffs = append(diffs, diff)
 		}
@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// sortKeyChange classifies how the ORDER BY of a table changes between two versions
+type sortKeyChange int
+
+const (
+	// sortKeyUnchanged means both versions have the same sorting key
+	sortKeyUnchanged sortKeyChange = iota
+	// sortKeyExtended means the target appends expressions over newly added columns,
+	// which ALTER TABLE ... MODIFY ORDER BY can apply in place
+	sortKeyExtended
+	// sortKeyIncompatible means the table has to be recreated to change its sorting key
+	sortKeyIncompatible
+)
+
+// compareSortKeys reports how the ORDER BY changes from current to target. ClickHouse can
+// only change a sorting key in place by appending expressions that use columns added in
+// the same ALTER statement, so any other change is incompatible. The returned reason
+// explains why an incompatible change requires recreating the table.
+func compareSortKeys(current, target *TableInfo) (sortKeyChange, string) {
+	if keyExpressionsEqual(current.OrderBy, target.OrderBy) {
+		return sortKeyUnchanged, ""
+	}
+
+	from, to := sortKeyString(current.OrderBy), sortKeyString(target.OrderBy)
+	if current.OrderBy == nil || target.OrderBy == nil {
+		return sortKeyIncompatible, fmt.Sprintf("ORDER BY change from %s to %s: a sorting key can't be added or removed in place", from, to)
+	}
+
+	if target.Engine == nil || !strings.HasSuffix(target.Engine.Name, "MergeTree") {
+		return sortKeyIncompatible, fmt.Sprintf("ORDER BY change from %s to %s: only MergeTree engines support MODIFY ORDER BY", from, to)
+	}
+
+	currentKeys, targetKeys := sortKeyElements(current.OrderBy), sortKeyElements(target.OrderBy)
+	if len(targetKeys) <= len(currentKeys) || !slices.EqualFunc(currentKeys, targetKeys[:len(currentKeys)], keyExpressionsEqual) {
+		return sortKeyIncompatible, fmt.Sprintf("ORDER BY change from %s to %s: a sorting key can only be extended by appending columns, not reordered or shortened", from, to)
+	}
+
+	existing := make(map[string]bool, len(current.Columns))
+	for _, col := range current.Columns {
+		existing[col.Name] = true
+	}
+	for _, key := range targetKeys[len(currentKeys):] {
+		for _, name := range referencedColumns(key) {
+			if existing[name] {
+				return sortKeyIncompatible, fmt.Sprintf("ORDER BY change from %s to %s: appends existing column %s, but only columns added in the same ALTER can extend a sorting key", from, to, name)
+			}
+		}
+	}
+
+	return sortKeyExtended, ""
+}
+
+// sortKeyElements splits a sorting key into its expressions, e.g. (date, id) -> [date, id].
+func sortKeyElements(expr *parser.Expression) []*parser.Expression {
+	expr = unwrapParentheses(expr)
+	if expr == nil {
+		return nil
+	}
+
+	if expr.Or != nil {
+		if primary := barePrimary(expr.Or); primary != nil && primary.Tuple != nil {
+			elements := make([]*parser.Expression, len(primary.Tuple.Elements))
+			for i := range primary.Tuple.Elements {
+				elements[i] = &primary.Tuple.Elements[i]
+			}
+			return elements
+		}
+	}
+
+	return []*parser.Expression{expr}
+}
+
+// sortKeyString returns the SQL for a sorting key, or "none" when there isn't one
+func sortKeyString(expr *parser.Expression) string {
+	if expr == nil {
+		return "none"
+	}
+	return expr.String()
+}
+
+// referencedColumns returns the names of the unqualified columns an expression references.
+func referencedColumns(expr *parser.Expression) []string {
+	var names []string
+	collectNodes(reflect.ValueOf(expr), func(ident *parser.IdentifierExpr) {
+		if ident.Database == nil && ident.Table == nil {
+			names = append(names, normalizeIdentifier(ident.Name))
+		}
+	})
+	return names
+}
@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// SourceMap records the schema file each object is defined in, allowing statements
+// generated for an object to be traced back to the file that declares it.
+type SourceMap map[string]string
+
+// CompileSources walks a schema file and its imports like Compile, recording the file
+// that defines each object. Paths are recorded as they're resolved from path, so
+// compiling a relative entrypoint (e.g. "db/main.sql") produces paths relative to the
+// same directory.
+//
+// Example:
+//
+//	sources, err := schema.CompileSources("db/main.sql")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if file, ok := sources.Lookup(stmt); ok {
+//		fmt.Printf("-- from %s\n", file)
+//	}
+func CompileSources(path string) (SourceMap, error) {
+	sources := make(SourceMap)
+	if err := compileSources(path, sources); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// compileSources parses the statements in path, excluding its imports, recording their
+// source before recursing into each imported file.
+func compileSources(path string, sources SourceMap) error {
+	var (
+		content strings.Builder
+		imports []string
+	)
+
+	err := scanSchemaFile(
+		path,
+		func(line string) {
+			content.WriteString(line)
+			content.WriteString("\n")
+		},
+		func(importPath string) error {
+			imports = append(imports, importPath)
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	sql, err := parser.ParseString(content.String())
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s", path)
+	}
+
+	for _, stmt := range sql.Statements {
+		if key := sourceKey(stmt); key != "" {
+			sources[key] = path
+		}
+	}
+
+	for _, importPath := range imports {
+		if err := compileSources(importPath, sources); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the schema file defining the object stmt creates, alters, or renames
+// to. Statements for objects that aren't in the schema, such as drops, aren't found.
+func (s SourceMap) Lookup(stmt *parser.Statement) (string, bool) {
+	key := sourceKey(stmt)
+	if key == "" {
+		return "", false
+	}
+
+	path, ok := s[key]
+	return path, ok
+}
+
+// sourceKey identifies the object a statement targets. Tables, views, and dictionaries
+// share a key space since ClickHouse alters views and dictionaries with ALTER TABLE.
+func sourceKey(stmt *parser.Statement) string {
+	switch {
+	case stmt.CreateDatabase != nil:
+		return "database:" + stmt.CreateDatabase.Name
+	case stmt.AlterDatabase != nil:
+		return "database:" + stmt.AlterDatabase.Name
+	case stmt.RenameDatabase != nil && len(stmt.RenameDatabase.Renames) == 1:
+		return "database:" + stmt.RenameDatabase.Renames[0].To
+	case stmt.CreateTable != nil:
+		return "table:" + sourceName(stmt.CreateTable.Database, stmt.CreateTable.Name)
+	case stmt.AlterTable != nil:
+		return "table:" + sourceName(stmt.AlterTable.Database, stmt.AlterTable.Name)
+	case stmt.RenameTable != nil && len(stmt.RenameTable.Renames) == 1:
+		rename := stmt.RenameTable.Renames[0]
+		return "table:" + sourceName(rename.ToDatabase, rename.ToName)
+	case stmt.CreateDictionary != nil:
+		return "table:" + sourceName(stmt.CreateDictionary.Database, stmt.CreateDictionary.Name)
+	case stmt.RenameDictionary != nil && len(stmt.RenameDictionary.Renames) == 1:
+		rename := stmt.RenameDictionary.Renames[0]
+		return "table:" + sourceName(rename.ToDatabase, rename.ToName)
+	case stmt.CreateView != nil:
+		return "table:" + sourceName(stmt.CreateView.Database, stmt.CreateView.Name)
+	case stmt.CreateNamedCollection != nil:
+		return "collection:" + stmt.CreateNamedCollection.Name
+	case stmt.AlterNamedCollection != nil:
+		return "collection:" + stmt.AlterNamedCollection.Name
+	case stmt.CreateRole != nil:
+		return "role:" + stmt.CreateRole.Name
+	case stmt.AlterRole != nil:
+		return "role:" + stmt.AlterRole.Name
+	case stmt.CreateFunction != nil:
+		return "function:" + stmt.CreateFunction.Name
+	default:
+		return ""
+	}
+}
+
+// sourceName returns the possibly database-qualified name of an object, without
+// backticks around the identifiers
+func sourceName(database *string, name string) string {
+	name = normalizeIdentifier(name)
+	if db := normalizeIdentifier(getStringValue(database)); db != "" {
+		return db + "." + name
+	}
+	return name
+}
@@ -0,0 +1,72 @@
+package schema_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/consts"
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.sql": `-- housekeeper:import schemas/_global/roles.sql
+-- housekeeper:import schemas/analytics/schema.sql`,
+		"schemas/_global/roles.sql": "CREATE ROLE analyst;",
+		"schemas/analytics/schema.sql": `CREATE DATABASE analytics ENGINE = Atomic;
+-- housekeeper:import tables/events.sql
+-- housekeeper:import views/daily.sql`,
+		"schemas/analytics/tables/events.sql": "CREATE TABLE analytics.events (id UInt64) ENGINE = MergeTree() ORDER BY id;",
+		"schemas/analytics/views/daily.sql":   "CREATE VIEW analytics.daily AS SELECT count() FROM analytics.events;",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), consts.ModeDir))
+		require.NoError(t, os.WriteFile(path, []byte(content), consts.ModeFile))
+	}
+
+	sources, err := schema.CompileSources(filepath.Join(tmpDir, "main.sql"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		sql    string
+		source string
+	}{
+		{sql: "CREATE ROLE analyst;", source: "schemas/_global/roles.sql"},
+		{sql: "ALTER DATABASE analytics MODIFY COMMENT 'Analytics';", source: "schemas/analytics/schema.sql"},
+		{sql: "ALTER TABLE analytics.events ADD COLUMN name String;", source: "schemas/analytics/tables/events.sql"},
+		{sql: "ALTER TABLE `analytics`.`events` ADD COLUMN name String;", source: "schemas/analytics/tables/events.sql"},
+		{sql: "RENAME TABLE analytics.old_events TO analytics.events;", source: "schemas/analytics/tables/events.sql"},
+		{sql: "CREATE OR REPLACE VIEW analytics.daily AS SELECT 1;", source: "schemas/analytics/views/daily.sql"},
+		{sql: "DROP TABLE analytics.old_events;"},
+		{sql: "ALTER TABLE analytics.unknown ADD COLUMN name String;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			sql, err := parser.ParseString(tt.sql)
+			require.NoError(t, err)
+
+			source, ok := sources.Lookup(sql.Statements[0])
+			if tt.source == "" {
+				require.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			require.Equal(t, filepath.Join(tmpDir, tt.source), source)
+		})
+	}
+
+	t.Run("missing import", func(t *testing.T) {
+		mainFile := filepath.Join(t.TempDir(), "main.sql")
+		require.NoError(t, os.WriteFile(mainFile, []byte("-- housekeeper:import missing.sql"), consts.ModeFile))
+
+		_, err := schema.CompileSources(mainFile)
+		require.ErrorContains(t, err, "failed to read file")
+	})
+}
@@ -1,10 +1,13 @@
 package schema
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/pseudomuto/housekeeper/pkg/compare"
 	"github.com/pseudomuto/housekeeper/pkg/consts"
 	"github.com/pseudomuto/housekeeper/pkg/parser"
@@ -27,10 +30,13 @@ type (
 	// It contains all information needed to generate migration SQL statements for
 	// table operations including CREATE, ALTER, DROP, and RENAME.
 	TableDiff struct {
-		DiffBase                   // Embeds Type, Name, NewName, Description, UpSQL, DownSQL
-		Current       *TableInfo   // Current state (nil if table doesn't exist)
-		Target        *TableInfo   // Target state (nil if table should be dropped)
-		ColumnChanges []ColumnDiff // For ALTER operations - specific column changes
+		DiffBase                           // Embeds Type, Name, NewName, Description, UpSQL, DownSQL
+		Current           *TableInfo       // Current state (nil if table doesn't exist)
+		Target            *TableInfo       // Target state (nil if table should be dropped)
+		ColumnChanges     []ColumnDiff     // For ALTER operations - specific column changes
+		IndexChanges      []IndexDiff      // For ALTER operations - data skipping index changes
+		ConstraintChanges []ConstraintDiff // For ALTER operations - CHECK constraint changes
+		ProjectionChanges []ProjectionDiff // For ALTER operations - projection changes
 	}
 
 	// TableDiffType represents the type of table difference
@@ -52,21 +58,30 @@ type (
 		TTL           *parser.Expression  // Table-level TTL expression AST
 		Settings      map[string]string   // Table settings
 		Columns       []ColumnInfo        // Column definitions
+		Indexes       []IndexInfo         // Data skipping index definitions
+		Constraints   []ConstraintInfo    // CHECK constraint definitions
+		Projections   []ProjectionInfo    // Projection definitions
 		OrReplace     bool                // Whether CREATE OR REPLACE was used
 		IfNotExists   bool                // Whether IF NOT EXISTS was used
 		AsSourceTable *string             // If this table uses AS, the source table name (qualified)
 		AsDependents  map[string]bool     // Tables that use AS to reference this table
+
+		strictComments bool // Compare comments exactly (see CompareOptions.StrictComments)
 	}
 
 	// ColumnInfo represents a single column definition
 	ColumnInfo struct {
-		Name        string              // Column name
-		DataType    *parser.DataType    // Data type AST
-		DefaultType string              // Default type: DEFAULT, MATERIALIZED, EPHEMERAL, ALIAS
-		Default     *parser.Expression  // Default expression AST
-		Codec       *parser.CodecClause // Codec AST
-		TTL         *parser.TTLClause   // TTL AST
-		Comment     string              // Column comment
+		Name        string                       // Column name
+		DataType    *parser.DataType             // Data type AST
+		DefaultType string                       // Default type: DEFAULT, MATERIALIZED, EPHEMERAL, ALIAS
+		Default     *parser.Expression           // Default expression AST
+		Codec       *parser.CodecClause          // Codec AST
+		TTL         *parser.TTLClause            // TTL AST
+		Comment     string                       // Column comment
+		Settings    *parser.ColumnSettingsClause // Per-column settings AST
+
+		strictComments bool                // Compare comments exactly (see CompareOptions.StrictComments)
+		defaultCodec   *parser.CodecClause // Codec used when none is declared (see CompareOptions.DefaultCodec)
 	}
 
 	// ColumnDiff represents a difference in column definitions
@@ -76,6 +91,8 @@ type (
 		Current     *ColumnInfo    // Current column definition (nil for ADD)
 		Target      *ColumnInfo    // Target column definition (nil for DROP)
 		Description string         // Human-readable description
+		After       string         // Column an added column is placed after (empty when appended or First)
+		First       bool           // Whether an added column is placed first
 	}
 
 	// ColumnDiffType represents the type of column difference
@@ -89,8 +106,16 @@ const (
 	ColumnDiffDrop ColumnDiffType = "DROP"
 	// ColumnDiffModify indicates a column needs to be modified
 	ColumnDiffModify ColumnDiffType = "MODIFY"
+	// ColumnDiffRename indicates a column needs to be renamed
+	ColumnDiffRename ColumnDiffType = "RENAME"
+	// ColumnDiffComment indicates only a column's comment needs to be changed
+	ColumnDiffComment ColumnDiffType = "COMMENT"
 )
 
+// defaultCompressionCodec is the codec ClickHouse uses for columns without a CODEC
+// when the server config doesn't specify one
+const defaultCompressionCodec = "LZ4"
+
 // GetName implements SchemaObject interface.
 // Returns the full table name (database.name or just name).
 func (t *TableInfo) GetName() string {
@@ -123,38 +148,72 @@ func (t *TableInfo) Equal(other *TableInfo) bool {
 
 	// Compare basic fields
 	if t.Name != other.Name || t.Database != other.Database || t.Cluster != other.Cluster ||
-		!strings.EqualFold(t.Comment, other.Comment) {
+		!caseInsensitiveCommentsEqual(t.strictComments || other.strictComments, t.Comment, other.Comment) {
 		return false
 	}
 
 	// Compare AST fields
 	if !enginesEqual(t.Engine, other.Engine) ||
-		!equalAST(t.OrderBy, other.OrderBy) ||
-		!equalAST(t.PartitionBy, other.PartitionBy) ||
-		!equalAST(t.PrimaryKey, other.PrimaryKey) ||
-		!equalAST(t.SampleBy, other.SampleBy) ||
+		!keyExpressionsEqual(t.OrderBy, other.OrderBy) ||
+		!keyExpressionsEqual(t.PartitionBy, other.PartitionBy) ||
+		!keyExpressionsEqual(t.PrimaryKey, other.PrimaryKey) ||
+		!keyExpressionsEqual(t.SampleBy, other.SampleBy) ||
 		!equalAST(t.TTL, other.TTL) {
 		return false
 	}
 
-	// Compare settings and columns
-	return compare.Maps(t.Settings, other.Settings) &&
+	// Compare settings, columns, indexes, constraints, and projections. Index names are
+	// unique, so indexes are compared as a set since their declaration order doesn't matter.
+	return settingsEqual(t.Engine, t.Settings, other.Settings) &&
 		compare.Slices(t.Columns, other.Columns, func(a, b ColumnInfo) bool {
 			return a.Equal(b)
+		}) &&
+		compare.SlicesUnordered(t.Indexes, other.Indexes, func(a, b IndexInfo) bool {
+			return a.Equal(b)
+		}) &&
+		compare.Slices(t.Constraints, other.Constraints, func(a, b ConstraintInfo) bool {
+			return a.Equal(b)
+		}) &&
+		compare.Slices(t.Projections, other.Projections, func(a, b ProjectionInfo) bool {
+			return a.Equal(b)
 		})
 }
 
 // Equal compares two ColumnInfo instances for equality using AST comparison
 func (c ColumnInfo) Equal(other ColumnInfo) bool {
 	if c.Name != other.Name || c.DefaultType != other.DefaultType ||
-		!strings.EqualFold(c.Comment, other.Comment) {
+		!caseInsensitiveCommentsEqual(c.strictComments || other.strictComments, c.Comment, other.Comment) {
 		return false
 	}
 
-	return equalAST(c.DataType, other.DataType) &&
+	return columnTypesEqual(c, other) &&
 		equalAST(c.Default, other.Default) &&
-		equalAST(c.Codec, other.Codec) &&
-		equalAST(c.TTL, other.TTL)
+		codecsEqual(c, other) &&
+		equalAST(c.TTL, other.TTL) &&
+		equalAST(c.Settings, other.Settings)
+}
+
+// columnTypesEqual compares the data types of two columns. When exactly one of the
+// columns omits its type (e.g. `created_at DEFAULT now()`), the type ClickHouse would
+// infer from the default expression is used instead, so the column matches its dumped
+// form (`created_at DateTime DEFAULT now()`). If the type can't be inferred, the types
+// are considered equal since ClickHouse derives it from the (separately compared) default.
+func columnTypesEqual(a, b ColumnInfo) bool {
+	if (a.DataType == nil) == (b.DataType == nil) {
+		return equalAST(a.DataType, b.DataType)
+	}
+
+	typed, untyped := a, b
+	if a.DataType == nil {
+		typed, untyped = b, a
+	}
+
+	inferred := parser.InferDataType(untyped.Default)
+	if inferred == nil {
+		return true
+	}
+
+	return typed.DataType.Equal(inferred)
 }
 
 // enginesEqual compares two table engines with special handling for ReplicatedMergeTree.
@@ -177,6 +236,73 @@ func enginesEqual(target, current *parser.TableEngine) bool {
 	return equalAST(target, current)
 }
 
+// engineSettingDefaults holds the values ClickHouse implicitly applies to table settings,
+// keyed by engine name. Dumped schemas list these explicitly, so a setting omitted from the
+// declared schema is considered equal to one set to its default. Defaults registered for
+// "MergeTree" apply to every engine in the MergeTree family.
+var engineSettingDefaults = map[string]map[string]string{
+	"MergeTree": {
+		"index_granularity":              "8192",
+		"index_granularity_bytes":        "10485760",
+		"min_index_granularity_bytes":    "1024",
+		"enable_mixed_granularity_parts": "1",
+		"merge_with_ttl_timeout":         "14400",
+		"min_bytes_for_wide_part":        "10485760",
+		"storage_policy":                 "'default'",
+	},
+}
+
+// RegisterEngineSettingDefault records the implicit default value of a table setting for
+// the given engine, so that omitting the setting doesn't produce a diff against a dumped
+// schema that includes it. Registering for "MergeTree" covers the whole MergeTree family.
+// This is not safe for concurrent use and should be called during initialization.
+//
+// Example:
+//
+//	schema.RegisterEngineSettingDefault("MergeTree", "max_parts_in_total", "100000")
+func RegisterEngineSettingDefault(engine, setting, value string) {
+	if engineSettingDefaults[engine] == nil {
+		engineSettingDefaults[engine] = make(map[string]string)
+	}
+	engineSettingDefaults[engine][setting] = value
+}
+
+// engineSettingDefault returns the implicit default for a setting on the given engine.
+// Engine specific defaults take precedence over MergeTree family defaults.
+func engineSettingDefault(engine *parser.TableEngine, setting string) (string, bool) {
+	if engine == nil {
+		return "", false
+	}
+
+	if value, ok := engineSettingDefaults[engine.Name][setting]; ok {
+		return value, true
+	}
+
+	if strings.HasSuffix(engine.Name, "MergeTree") {
+		value, ok := engineSettingDefaults["MergeTree"][setting]
+		return value, ok
+	}
+
+	return "", false
+}
+
+// settingsEqual compares table settings, treating a setting that is only present on one
+// side as equal when its value is the engine's implicit default.
+func settingsEqual(engine *parser.TableEngine, a, b map[string]string) bool {
+	return compare.Maps(withoutDefaultSettings(engine, a), withoutDefaultSettings(engine, b))
+}
+
+func withoutDefaultSettings(engine *parser.TableEngine, settings map[string]string) map[string]string {
+	result := make(map[string]string, len(settings))
+	for name, value := range settings {
+		if def, ok := engineSettingDefault(engine, name); ok && def == value {
+			continue
+		}
+		result[name] = value
+	}
+	return result
+}
+
 // compareTables compares current and target parsed schemas to find table differences.
 // It identifies tables that need to be created, altered, dropped, or renamed.
 //
@@ -185,7 +311,7 @@ func enginesEqual(target, current *parser.TableEngine) bool {
 // - Column definitions and modifications
 // - Rename detection based on content similarity
 // - Proper ordering for migration generation
-func compareTables(current, target *parser.SQL) ([]*TableDiff, error) {
+func compareTables(current, target *parser.SQL, opts CompareOptions) ([]*TableDiff, error) {
 	currentTables, err := extractTablesFromSQL(current)
 	if err != nil {
 		return nil, err
@@ -196,6 +322,18 @@ func compareTables(current, target *parser.SQL) ([]*TableDiff, error) {
 		return nil, err
 	}
 
+	if opts.StrictComments {
+		useStrictTableComments(currentTables)
+		useStrictTableComments(targetTables)
+	}
+
+	defaultCodec, err := parser.ParseCodec(cmp.Or(opts.DefaultCodec, defaultCompressionCodec))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid default codec")
+	}
+	useDefaultCodec(currentTables, defaultCodec)
+	useDefaultCodec(targetTables, defaultCodec)
+
 	// Pre-allocate diffs slice with estimated capacity
 	diffs := make([]*TableDiff, 0, len(currentTables)+len(targetTables))
 
@@ -203,7 +341,7 @@ func compareTables(current, target *parser.SQL) ([]*TableDiff, error) {
 	for _, tableName := range SortedKeys(targetTables) {
 		targetTable := targetTables[tableName]
 		currentTable, exists := currentTables[tableName]
-		diff, err := createTableDiff(tableName, currentTable, targetTable, currentTables, targetTables, exists)
+		diff, err := createTableDiff(tableName, currentTable, targetTable, currentTables, targetTables, exists, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -221,6 +359,7 @@ func compareTables(current, target *parser.SQL) ([]*TableDiff, error) {
 					targetTable.AsDependents,
 					currentTables,
 					targetTables,
+					opts,
 				)
 				diffs = append(diffs, propagatedDiffs...)
 			}
@@ -235,9 +374,9 @@ func compareTables(current, target *parser.SQL) ([]*TableDiff, error) {
 				Type:        string(TableDiffDrop),
 				Name:        tableName,
 				Description: "Drop table " + tableName,
-				UpSQL:       generateDropTableSQL(currentTable),
-				DownSQL:     generateCreateTableSQL(currentTable),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropTableSQL(currentTable), generateCreateTableSQL(currentTable)
+			}),
 			Current: currentTable,
 		}
 		diffs = append(diffs, diff)
@@ -252,6 +391,7 @@ func propagateColumnChangesToDependents(
 	sourceDiff *TableDiff,
 	dependents map[string]bool,
 	currentTables, targetTables map[string]*TableInfo,
+	opts CompareOptions,
 ) []*TableDiff {
 	// Only process if there are column changes to propagate
 	if len(sourceDiff.ColumnChanges) == 0 {
@@ -275,27 +415,27 @@ func propagateColumnChangesToDependents(
 				Type:        string(TableDiffAlter),
 				Name:        dependentName,
 				Description: fmt.Sprintf("Propagated column changes from %s (AS dependency)", sourceDiff.Name),
-			},
+			}.withSQL(opts, func() (string, string) {
+				// Generate SQL based on engine type
+				if isViewLikeEngine(targetDep.Engine) {
+					// For Distributed, Memory, etc.: DROP + CREATE is safe and necessary
+					return fmt.Sprintf("-- Recreate to match schema changes from %s\n", sourceDiff.Name) +
+							generateDropTableSQL(currentDep) + ";\n" +
+							generateCreateTableSQL(targetDep),
+						generateDropTableSQL(targetDep) + ";\n" +
+							generateCreateTableSQL(currentDep)
+				}
+
+				// For MergeTree, etc.: Use ALTER to preserve data
+				return fmt.Sprintf("-- Propagated from %s (AS dependency)\n", sourceDiff.Name) +
+						generateAlterTableSQL(targetDep, sourceDiff.ColumnChanges, nil, nil, nil, nil),
+					generateAlterTableSQL(currentDep, reverseColumnChanges(sourceDiff.ColumnChanges), nil, nil, nil, nil)
+			}),
 			Current:       currentDep,
 			Target:        targetDep,
 			ColumnChanges: sourceDiff.ColumnChanges, // Same column changes
 		}
 
-		// Generate SQL based on engine type
-		if isViewLikeEngine(targetDep.Engine) {
-			// For Distributed, Memory, etc.: DROP + CREATE is safe and necessary
-			propDiff.UpSQL = fmt.Sprintf("-- Recreate to match schema changes from %s\n", sourceDiff.Name) +
-				generateDropTableSQL(currentDep) + ";\n" +
-				generateCreateTableSQL(targetDep)
-			propDiff.DownSQL = generateDropTableSQL(targetDep) + ";\n" +
-				generateCreateTableSQL(currentDep)
-		} else {
-			// For MergeTree, etc.: Use ALTER to preserve data
-			propDiff.UpSQL = fmt.Sprintf("-- Propagated from %s (AS dependency)\n", sourceDiff.Name) +
-				generateAlterTableSQL(targetDep, sourceDiff.ColumnChanges)
-			propDiff.DownSQL = generateAlterTableSQL(currentDep, reverseColumnChanges(sourceDiff.ColumnChanges))
-		}
-
 		propagatedDiffs = append(propagatedDiffs, propDiff)
 	}
 
@@ -322,6 +462,23 @@ func isViewLikeEngine(engine *parser.TableEngine) bool {
 	return viewLikeEngines[engine.Name]
 }
 
+// supportsCreateOrReplace reports whether a table using the engine can be recreated with
+// CREATE OR REPLACE TABLE. ClickHouse implements the replace by creating the new table
+// and exchanging it with the old one, so for a moment both tables exist. Streaming
+// engines that consume from an external queue (Kafka, RabbitMQ, NATS, and the S3/Azure
+// queue engines) would briefly have two consumers, so they keep using DROP+CREATE.
+// All other engines, including the MergeTree family, support the replace form.
+//
+// Note that CREATE OR REPLACE TABLE requires the table's database to use the Atomic
+// (default) or Replicated database engine.
+func supportsCreateOrReplace(engine *parser.TableEngine) bool {
+	if engine == nil {
+		return false
+	}
+
+	return !streamingEngines[engine.Name]
+}
+
 // requiresDropCreate determines if changing from current engine to target engine
 // requires DROP+CREATE rather than ALTER TABLE operations
 func requiresDropCreate(current, target *parser.TableEngine) bool {
@@ -488,16 +645,28 @@ func extractTablesFromSQL(sql *parser.SQL) (map[string]*TableInfo, error) {
 			if settings := table.GetSettings(); settings != nil {
 				settingMap := make(map[string]string)
 				for _, setting := range settings.Settings {
-					settingMap[setting.Name] = setting.Value
+					settingMap[setting.Name] = setting.GetValue()
 				}
 				tableInfo.Settings = settingMap
 			}
 
-			// Process columns from table elements
+			// Process columns, indexes, constraints, and projections from table elements
 			var columns []ColumnInfo
 			for _, element := range table.Elements {
+				if element.Index != nil {
+					tableInfo.Indexes = append(tableInfo.Indexes, newIndexInfo(element.Index))
+					continue
+				}
+				if element.Constraint != nil {
+					tableInfo.Constraints = append(tableInfo.Constraints, newConstraintInfo(element.Constraint))
+					continue
+				}
+				if element.Projection != nil {
+					tableInfo.Projections = append(tableInfo.Projections, newProjectionInfo(element.Projection))
+					continue
+				}
 				if element.Column == nil {
-					continue // Skip indexes and constraints for now
+					continue
 				}
 				col := element.Column
 				columnInfo := ColumnInfo{
@@ -506,7 +675,7 @@ func extractTablesFromSQL(sql *parser.SQL) (map[string]*TableInfo, error) {
 				}
 				if defaultClause := col.GetDefault(); defaultClause != nil {
 					columnInfo.DefaultType = defaultClause.Type
-					columnInfo.Default = &defaultClause.Expression
+					columnInfo.Default = defaultClause.Expression
 				}
 				if codecClause := col.GetCodec(); codecClause != nil {
 					columnInfo.Codec = codecClause
@@ -517,6 +686,7 @@ func extractTablesFromSQL(sql *parser.SQL) (map[string]*TableInfo, error) {
 				if comment := col.GetComment(); comment != nil {
 					columnInfo.Comment = removeQuotes(*comment)
 				}
+				columnInfo.Settings = col.GetSettings()
 				columns = append(columns, columnInfo)
 			}
 			tableInfo.Columns = columns
@@ -556,11 +726,94 @@ func findRenamedTable(targetTable *TableInfo, currentTables, targetTables map[st
 	return ""
 }
 
+// useStrictTableComments marks the tables and their columns for exact comment comparison
+func useStrictTableComments(tables map[string]*TableInfo) {
+	for _, table := range tables {
+		table.strictComments = true
+		for i := range table.Columns {
+			table.Columns[i].strictComments = true
+		}
+	}
+}
+
+// useDefaultCodec sets the codec that columns declared without one are compressed with
+func useDefaultCodec(tables map[string]*TableInfo, codec *parser.CodecClause) {
+	for _, table := range tables {
+		for i := range table.Columns {
+			table.Columns[i].defaultCodec = codec
+		}
+	}
+}
+
+// codecsEqual compares the codecs of two columns. A column declared without a codec
+// matches one whose codec is the default codec, since ClickHouse compresses both the
+// same way and may report the default for columns that didn't declare a codec.
+func codecsEqual(a, b ColumnInfo) bool {
+	if (a.Codec == nil) == (b.Codec == nil) {
+		return equalAST(a.Codec, b.Codec)
+	}
+
+	declared, defaultCodec := a.Codec, a.defaultCodec
+	if declared == nil {
+		declared, defaultCodec = b.Codec, b.defaultCodec
+	}
+
+	return defaultCodec != nil && declared.Equal(defaultCodec)
+}
+
+// caseInsensitiveCommentsEqual compares table and column comments, ignoring case unless strict
+func caseInsensitiveCommentsEqual(strict bool, comment1, comment2 string) bool {
+	if strict {
+		return comment1 == comment2
+	}
+	return strings.EqualFold(comment1, comment2)
+}
+
 // tablesEqual compares two tables for equality
 func tablesEqual(a, b *TableInfo) bool {
 	return a.Equal(b)
 }
 
+// keyExpressionsEqual compares ORDER BY, PARTITION BY, PRIMARY KEY, and SAMPLE BY expressions.
+// Identifier quoting and whitespace are already normalized by the parser; redundant outer
+// parentheses are removed here since ClickHouse dumps `ORDER BY (id)` as `ORDER BY id`.
+func keyExpressionsEqual(a, b *parser.Expression) bool {
+	return equalAST(unwrapParentheses(a), unwrapParentheses(b))
+}
+
+// unwrapParentheses strips parentheses surrounding an entire expression, e.g. ((id)) -> id.
+// Tuples such as (date, id) are left untouched.
+func unwrapParentheses(expr *parser.Expression) *parser.Expression {
+	for expr != nil && expr.Or != nil {
+		primary := barePrimary(expr.Or)
+		if primary == nil || primary.Parentheses == nil {
+			break
+		}
+		expr = &primary.Parentheses.Expression
+	}
+	return expr
+}
+
+// barePrimary returns the primary expression when the expression consists of nothing else
+func barePrimary(or *parser.OrExpression) *parser.PrimaryExpression {
+	if or == nil || len(or.Rest) > 0 || or.And == nil || len(or.And.Rest) > 0 || or.And.Not == nil || or.And.Not.Not {
+		return nil
+	}
+
+	comparison := or.And.Not.Comparison
+	if comparison == nil || comparison.Rest != nil || comparison.IsNull != nil ||
+		comparison.Addition == nil || len(comparison.Addition.Rest) > 0 {
+		return nil
+	}
+
+	multiplication := comparison.Addition.Multiplication
+	if multiplication == nil || len(multiplication.Rest) > 0 || multiplication.Unary == nil || multiplication.Unary.Op != "" {
+		return nil
+	}
+
+	return multiplication.Unary.Primary
+}
+
 // tablesEqualIgnoringName compares two tables for equality ignoring name and database
 func tablesEqualIgnoringName(a, b *TableInfo) bool {
 	// Create copies with normalized names to use Equal()
@@ -573,8 +826,18 @@ func tablesEqualIgnoringName(a, b *TableInfo) bool {
 	return aCopy.Equal(&bCopy)
 }
 
-// compareColumns compares column definitions and returns differences
-func compareColumns(current, target []ColumnInfo) []ColumnDiff {
+// compareColumns compares column definitions and returns differences.
+//
+// When detectRenames is set, renamed columns are detected (see detectColumnRenames) and
+// emitted first, so the remaining changes apply to the renamed columns. Expressions
+// referencing a renamed column are compared as ClickHouse rewrites them after the rename.
+// Otherwise a column missing from target is dropped and a column missing from current
+// is added, even when their properties match.
+//
+// Added columns are placed where the target declares them (see columnPosition), and
+// dropped columns record where they were so that reverting the drop restores them in
+// place. The order of existing columns isn't compared.
+func compareColumns(current, target []ColumnInfo, detectRenames bool) []ColumnDiff {
 	var diffs []ColumnDiff
 
 	// Create maps for easier lookup
@@ -588,45 +851,95 @@ func compareColumns(current, target []ColumnInfo) []ColumnDiff {
 		targetCols[col.Name] = col
 	}
 
+	// Find renamed columns and treat them as existing under their new name
+	var renames []RenamePair
+	if detectRenames {
+		renames = detectColumnRenames(current, target, currentCols, targetCols)
+	}
+	renamed := make(map[string]string, len(renames))
+	for _, rename := range renames {
+		currentColCopy := currentCols[rename.OldName]
+		targetColCopy := targetCols[rename.NewName]
+		diffs = append(diffs, ColumnDiff{
+			Type:        ColumnDiffRename,
+			ColumnName:  rename.OldName,
+			Current:     &currentColCopy,
+			Target:      &targetColCopy,
+			Description: fmt.Sprintf("Rename column %s to %s", rename.OldName, rename.NewName),
+		})
+
+		renamedCol := currentColCopy
+		renamedCol.Name = rename.NewName
+		delete(currentCols, rename.OldName)
+		currentCols[rename.NewName] = renamedCol
+		renamed[rename.OldName] = rename.NewName
+	}
+	for name, col := range currentCols {
+		currentCols[name] = renameColumnReferences(col, renamed)
+	}
+
 	// Find columns to add or modify
-	for _, targetCol := range target {
+	for i, targetCol := range target {
 		if currentCol, exists := currentCols[targetCol.Name]; exists {
 			// Column exists - check for changes using Equal() method
 			if !currentCol.Equal(targetCol) {
 				// Fix: Create copies to avoid loop variable pointer issues
 				currentColCopy := currentCol
 				targetColCopy := targetCol
-				diffs = append(diffs, ColumnDiff{
+				diff := ColumnDiff{
 					Type:        ColumnDiffModify,
 					ColumnName:  targetCol.Name,
 					Current:     &currentColCopy,
 					Target:      &targetColCopy,
 					Description: "Modify column " + targetCol.Name,
-				})
+				}
+				if onlyColumnCommentChanged(currentCol, targetCol) {
+					diff.Type = ColumnDiffComment
+					diff.Description = "Change comment of column " + targetCol.Name
+				}
+				diffs = append(diffs, diff)
 			}
 		} else {
 			// Column needs to be added
 			// Fix: Create copy to avoid loop variable pointer issues
 			targetColCopy := targetCol
+			after, first := columnPosition(target, i, func(name string) bool {
+				_, exists := currentCols[name]
+				return !exists
+			})
 			diffs = append(diffs, ColumnDiff{
 				Type:        ColumnDiffAdd,
 				ColumnName:  targetCol.Name,
 				Target:      &targetColCopy,
 				Description: "Add column " + targetCol.Name,
+				After:       after,
+				First:       first,
 			})
 		}
 	}
 
 	// Find columns to drop
-	for _, currentCol := range current {
-		if _, exists := targetCols[currentCol.Name]; !exists {
+	dropped := func(name string) bool {
+		_, wasRenamed := renamed[name]
+		_, exists := targetCols[name]
+		return !wasRenamed && !exists
+	}
+	for i, currentCol := range current {
+		if dropped(currentCol.Name) {
 			// Fix: Create copy to avoid loop variable pointer issues
 			currentColCopy := currentCol
+			after, first := columnPosition(current, i, dropped)
+			if newName, wasRenamed := renamed[after]; wasRenamed {
+				// Renames are reverted after the drop is, so the column is restored after the new name
+				after = newName
+			}
 			diffs = append(diffs, ColumnDiff{
 				Type:        ColumnDiffDrop,
 				ColumnName:  currentCol.Name,
 				Current:     &currentColCopy,
 				Description: "Drop column " + currentCol.Name,
+				After:       after,
+				First:       first,
 			})
 		}
 	}
@@ -634,9 +947,53 @@ func compareColumns(current, target []ColumnInfo) []ColumnDiff {
 	return diffs
 }
 
-// reverseColumnChanges reverses column changes for down migration
+// columnPosition returns where the i-th column must be added for it to land at its
+// position in columns, given which columns are being added along with it. Columns that
+// only precede other added columns are appended, so no position is needed. Otherwise the
+// column is placed first or after the column preceding it, which already exists or is
+// added before it.
+func columnPosition(columns []ColumnInfo, i int, added func(string) bool) (after string, first bool) {
+	appended := true
+	for _, col := range columns[i+1:] {
+		if !added(col.Name) {
+			appended = false
+			break
+		}
+	}
+
+	switch {
+	case appended:
+		return "", false
+	case i == 0:
+		return "", true
+	default:
+		return columns[i-1].Name, false
+	}
+}
+
+// columnPositionClause returns the FIRST or AFTER clause placing an added column, if any
+func columnPositionClause(change ColumnDiff) string {
+	switch {
+	case change.First:
+		return " FIRST"
+	case change.After != "":
+		return " AFTER `" + change.After + "`"
+	default:
+		return ""
+	}
+}
+
+// onlyColumnCommentChanged reports whether two columns differ only in their comment, in
+// which case a COMMENT COLUMN is enough and the type, codec, TTL etc. needn't be re-specified
+func onlyColumnCommentChanged(current, target ColumnInfo) bool {
+	current.Comment = target.Comment
+	return current.Equal(target)
+}
+
+// reverseColumnChanges reverses column changes for down migration. Renames are reverted
+// last, since the other reverted changes still refer to the renamed columns.
 func reverseColumnChanges(changes []ColumnDiff) []ColumnDiff {
-	var reversed []ColumnDiff
+	var reversed, renames []ColumnDiff
 	for _, change := range changes {
 		switch change.Type {
 		case ColumnDiffAdd:
@@ -656,6 +1013,8 @@ func reverseColumnChanges(changes []ColumnDiff) []ColumnDiff {
 				ColumnName:  change.ColumnName,
 				Target:      &targetCopy,
 				Description: "Add column " + change.ColumnName,
+				After:       change.After,
+				First:       change.First,
 			})
 		case ColumnDiffModify:
 			// Fix: Create copies to avoid pointer corruption between UP and DOWN SQL generation
@@ -668,9 +1027,29 @@ func reverseColumnChanges(changes []ColumnDiff) []ColumnDiff {
 				Target:      &targetCopy,
 				Description: "Modify column " + change.ColumnName,
 			})
+		case ColumnDiffComment:
+			currentCopy := *change.Target
+			targetCopy := *change.Current
+			reversed = append(reversed, ColumnDiff{
+				Type:        ColumnDiffComment,
+				ColumnName:  change.ColumnName,
+				Current:     &currentCopy,
+				Target:      &targetCopy,
+				Description: "Change comment of column " + change.ColumnName,
+			})
+		case ColumnDiffRename:
+			currentCopy := *change.Target
+			targetCopy := *change.Current
+			renames = append(renames, ColumnDiff{
+				Type:        ColumnDiffRename,
+				ColumnName:  currentCopy.Name,
+				Current:     &currentCopy,
+				Target:      &targetCopy,
+				Description: fmt.Sprintf("Rename column %s to %s", currentCopy.Name, targetCopy.Name),
+			})
 		}
 	}
-	return reversed
+	return append(reversed, renames...)
 }
 
 // SQL generation helper functions
@@ -697,12 +1076,23 @@ func formatColumnDefinition(col ColumnInfo) string {
 	// Always be backticking
 	sql.WriteString("`")
 	sql.WriteString(col.Name)
-	sql.WriteString("` ")
-	sql.WriteString(col.DataType.String())
+	sql.WriteString("`")
+
+	// Spell out the inferred type when omitted so MODIFY COLUMN also changes the type
+	dataType := col.DataType
+	if dataType == nil {
+		dataType = parser.InferDataType(col.Default)
+	}
+	if dataType != nil {
+		sql.WriteString(" ")
+		sql.WriteString(dataType.String())
+	}
 
-	if col.DefaultType != "" && col.Default != nil {
+	if col.DefaultType != "" {
 		sql.WriteString(" ")
 		sql.WriteString(col.DefaultType)
+	}
+	if col.Default != nil {
 		sql.WriteString(" ")
 		sql.WriteString(col.Default.String())
 	}
@@ -719,6 +1109,10 @@ func formatColumnDefinition(col ColumnInfo) string {
 		sql.WriteString(col.Comment)
 		sql.WriteString("'")
 	}
+	if col.Settings != nil {
+		sql.WriteString(" ")
+		sql.WriteString(col.Settings.String())
+	}
 	return sql.String()
 }
 
@@ -734,6 +1128,15 @@ func generateCreateTableSQL(table *TableInfo) string {
 	return sql.String()
 }
 
+// generateReplaceTableSQL generates CREATE OR REPLACE TABLE SQL from table info
+func generateReplaceTableSQL(table *TableInfo) string {
+	replacement := *table
+	replacement.OrReplace = true
+	replacement.IfNotExists = false
+
+	return generateCreateTableSQL(&replacement)
+}
+
 func writeTableHeader(sql *strings.Builder, table *TableInfo) {
 	sql.WriteString("CREATE ")
 	if table.OrReplace {
@@ -756,6 +1159,18 @@ func writeTableColumns(sql *strings.Builder, table *TableInfo) {
 		sql.WriteString("    ")
 		sql.WriteString(formatColumnDefinition(col))
 	}
+	for _, index := range table.Indexes {
+		sql.WriteString(",\n    INDEX ")
+		sql.WriteString(formatIndexDefinition(index))
+	}
+	for _, constraint := range table.Constraints {
+		sql.WriteString(",\n    CONSTRAINT ")
+		sql.WriteString(formatConstraintDefinition(constraint))
+	}
+	for _, projection := range table.Projections {
+		sql.WriteString(",\n    PROJECTION ")
+		sql.WriteString(formatProjectionDefinition(projection))
+	}
 	sql.WriteString("\n)")
 }
 
@@ -840,8 +1255,69 @@ func generateRenameTableSQL(from, to *TableInfo, fromName, toName string) string
 		String()
 }
 
-func generateAlterTableSQL(target *TableInfo, columnChanges []ColumnDiff) string {
-	if len(columnChanges) == 0 {
+// generateAlterTableSQL builds a single ALTER TABLE statement applying the column changes
+// followed by the table-level changes (see tablePropertyChanges). Combining every operation
+// into one statement lets ClickHouse validate and apply them as a single metadata change,
+// rather than one ALTER (and potentially one mutation) per operation. Indexes, constraints,
+// and projections are dropped before the column changes and added after them, so they never
+// refer to a column that is being dropped or hasn't been added yet.
+func generateAlterTableSQL(
+	target *TableInfo,
+	columnChanges []ColumnDiff,
+	indexChanges []IndexDiff,
+	constraintChanges []ConstraintDiff,
+	projectionChanges []ProjectionDiff,
+	tableChanges []string,
+) string {
+	operations := make([]string, 0, len(columnChanges)+len(indexChanges)+len(constraintChanges)+len(projectionChanges)+len(tableChanges))
+	for _, change := range projectionChanges {
+		if change.Type == ProjectionDiffDrop {
+			operations = append(operations, "DROP PROJECTION `"+change.Projection.Name+"`")
+		}
+	}
+	for _, change := range constraintChanges {
+		if change.Type == ConstraintDiffDrop {
+			operations = append(operations, "DROP CONSTRAINT `"+change.Constraint.Name+"`")
+		}
+	}
+	for _, change := range indexChanges {
+		if change.Type == IndexDiffDrop {
+			operations = append(operations, "DROP INDEX `"+change.Index.Name+"`")
+		}
+	}
+	for _, change := range columnChanges {
+		switch change.Type {
+		case ColumnDiffAdd:
+			operations = append(operations, "ADD COLUMN "+formatColumnDefinition(*change.Target)+columnPositionClause(change))
+		case ColumnDiffDrop:
+			// Always backtick column names for consistency
+			operations = append(operations, "DROP COLUMN `"+change.ColumnName+"`")
+		case ColumnDiffModify:
+			operations = append(operations, "MODIFY COLUMN "+formatColumnDefinition(*change.Target))
+		case ColumnDiffComment:
+			operations = append(operations, "COMMENT COLUMN `"+change.Target.Name+"` '"+change.Target.Comment+"'")
+		case ColumnDiffRename:
+			operations = append(operations, "RENAME COLUMN `"+change.Current.Name+"` TO `"+change.Target.Name+"`")
+		}
+	}
+	for _, change := range indexChanges {
+		if change.Type == IndexDiffAdd {
+			operations = append(operations, "ADD INDEX "+formatIndexDefinition(*change.Index))
+		}
+	}
+	for _, change := range constraintChanges {
+		if change.Type == ConstraintDiffAdd {
+			operations = append(operations, "ADD CONSTRAINT "+formatConstraintDefinition(*change.Constraint))
+		}
+	}
+	for _, change := range projectionChanges {
+		if change.Type == ProjectionDiffAdd {
+			operations = append(operations, "ADD PROJECTION "+formatProjectionDefinition(*change.Projection))
+		}
+	}
+	operations = append(operations, tableChanges...)
+
+	if len(operations) == 0 {
 		return ""
 	}
 
@@ -849,56 +1325,83 @@ func generateAlterTableSQL(target *TableInfo, columnChanges []ColumnDiff) string
 	sql.WriteString("ALTER TABLE ")
 	sql.WriteString(formatQualifiedTableName(target.Database, target.Name))
 	writeOnClusterClause(&sql, target.Cluster)
+	sql.WriteString("\n    ")
+	sql.WriteString(strings.Join(operations, ",\n    "))
 
-	// Generate column modifications
-	for i, change := range columnChanges {
-		if i > 0 {
-			sql.WriteString(",")
+	return sql.String()
+}
+
+// tablePropertyChanges returns the ALTER TABLE operations that change the ORDER BY, SAMPLE BY,
+// TTL, settings, and comment of current to match target. ORDER BY changes are expected to be
+// extensions (see compareSortKeys); anything else is recreated instead of altered. Settings set to the engine's implicit
+// default are treated as unset, so changing a setting back to its default resets it.
+func tablePropertyChanges(current, target *TableInfo) []string {
+	var operations []string
+
+	if !keyExpressionsEqual(current.OrderBy, target.OrderBy) && target.OrderBy != nil {
+		operations = append(operations, "MODIFY ORDER BY "+target.OrderBy.String())
+	}
+
+	if !keyExpressionsEqual(current.SampleBy, target.SampleBy) {
+		if target.SampleBy == nil {
+			operations = append(operations, "REMOVE SAMPLE BY")
+		} else {
+			operations = append(operations, "MODIFY SAMPLE BY "+target.SampleBy.String())
 		}
-		sql.WriteString("\n    ")
+	}
 
-		switch change.Type {
-		case ColumnDiffAdd:
-			sql.WriteString("ADD COLUMN ")
-			sql.WriteString(formatColumnDefinition(*change.Target))
-		case ColumnDiffDrop:
-			sql.WriteString("DROP COLUMN ")
-			// Always backtick column names for consistency
-			sql.WriteString("`")
-			sql.WriteString(change.ColumnName)
-			sql.WriteString("`")
-		case ColumnDiffModify:
-			sql.WriteString("MODIFY COLUMN ")
-			sql.WriteString(formatColumnDefinition(*change.Target))
+	if !equalAST(current.TTL, target.TTL) {
+		if target.TTL == nil {
+			operations = append(operations, "REMOVE TTL")
+		} else {
+			operations = append(operations, "MODIFY TTL "+target.TTL.String())
 		}
 	}
 
-	return sql.String()
+	currentSettings := withoutDefaultSettings(current.Engine, current.Settings)
+	targetSettings := withoutDefaultSettings(target.Engine, target.Settings)
+	for _, name := range slices.Sorted(maps.Keys(targetSettings)) {
+		if value, exists := currentSettings[name]; !exists || value != targetSettings[name] {
+			operations = append(operations, "MODIFY SETTING "+name+" = "+targetSettings[name])
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(currentSettings)) {
+		if _, exists := targetSettings[name]; !exists {
+			operations = append(operations, "RESET SETTING "+name)
+		}
+	}
+
+	strict := current.strictComments || target.strictComments
+	if !caseInsensitiveCommentsEqual(strict, current.Comment, target.Comment) {
+		operations = append(operations, "MODIFY COMMENT '"+target.Comment+"'")
+	}
+
+	return operations
 }
 
-func createTableDiff(tableName string, currentTable, targetTable *TableInfo, currentTables, targetTables map[string]*TableInfo, exists bool) (*TableDiff, error) {
+func createTableDiff(tableName string, currentTable, targetTable *TableInfo, currentTables, targetTables map[string]*TableInfo, exists bool, opts CompareOptions) (*TableDiff, error) {
 	// Validate operation before proceeding
 	if err := validateTableOperation(currentTable, targetTable); err != nil {
 		return nil, err
 	}
 
 	if !exists {
-		return handleTableNotExists(tableName, targetTable, currentTables, targetTables)
+		return handleTableNotExists(tableName, targetTable, currentTables, targetTables, opts)
 	}
 
-	return handleTableExists(tableName, currentTable, targetTable)
+	return handleTableExists(tableName, currentTable, targetTable, opts)
 }
 
 // handleTableNotExists handles the case when a table doesn't exist in the current schema
-func handleTableNotExists(tableName string, targetTable *TableInfo, currentTables, targetTables map[string]*TableInfo) (*TableDiff, error) {
+func handleTableNotExists(tableName string, targetTable *TableInfo, currentTables, targetTables map[string]*TableInfo, opts CompareOptions) (*TableDiff, error) {
 	// Check if this might be a renamed table
 	renamedFrom := findRenamedTable(targetTable, currentTables, targetTables)
 	if renamedFrom != "" {
-		return createRenameDiff(renamedFrom, tableName, currentTables[renamedFrom], targetTable), nil
+		return createRenameDiff(renamedFrom, tableName, currentTables[renamedFrom], targetTable, opts), nil
 	}
 
 	// This is a create operation
-	return createCreateDiff(tableName, targetTable), nil
+	return createCreateDiff(tableName, targetTable, opts), nil
 }
 
 // handleTableExists determines the appropriate action when a table exists in both the current and target schemas.
@@ -907,8 +1410,10 @@ func handleTableNotExists(tableName string, targetTable *TableInfo, currentTable
 // The function first flattens nested columns in the target table to match ClickHouse's internal representation.
 // If the tables are equal after flattening, no changes are needed.
 // If significant differences are detected (e.g., engine or partition changes), a DROP+CREATE is performed.
+// When opts.PreferReplaceTable is set and the engine supports it, the DROP+CREATE is emitted as a single
+// CREATE OR REPLACE TABLE instead.
 // Otherwise, column-level differences are computed and an ALTER operation is generated.
-func handleTableExists(tableName string, currentTable, targetTable *TableInfo) (*TableDiff, error) {
+func handleTableExists(tableName string, currentTable, targetTable *TableInfo, opts CompareOptions) (*TableDiff, error) {
 	// Table exists in both - check for changes
 	// For comparison purposes, flatten the target table to match ClickHouse's internal representation
 	// Current table is already flattened by ClickHouse, but target table may have Nested syntax
@@ -919,14 +1424,17 @@ func handleTableExists(tableName string, currentTable, targetTable *TableInfo) (
 
 	// Check if we need DROP+CREATE strategy
 	if shouldUseDropCreate(currentTable, targetTable) {
-		return createDropCreateDiff(tableName, currentTable, targetTable), nil
+		if opts.PreferReplaceTable && supportsCreateOrReplace(currentTable.Engine) && supportsCreateOrReplace(targetTable.Engine) {
+			return createReplaceDiff(tableName, currentTable, targetTable, opts), nil
+		}
+		return createDropCreateDiff(tableName, currentTable, targetTable, opts), nil
 	}
 
 	// Generate column diffs for regular tables
 	// Use flattened target table for comparison but preserve original for SQL generation
-	columnChanges := compareColumns(currentTable.Columns, flattenedTargetTable.Columns)
+	columnChanges := compareColumns(currentTable.Columns, flattenedTargetTable.Columns, opts.DetectColumnRenames)
 
-	return createAlterDiff(tableName, currentTable, targetTable, columnChanges), nil
+	return createAlterDiff(tableName, currentTable, targetTable, columnChanges, opts), nil
 }
 
 // shouldUseDropCreate determines if a table modification requires DROP+CREATE strategy.
@@ -936,79 +1444,154 @@ func handleTableExists(tableName string, currentTable, targetTable *TableInfo) (
 //     Any modification to tables using these engines cannot be performed via ALTER and requires dropping and recreating the table.
 //   - Certain engine changes, such as ReplicatedMergeTree parameter changes (e.g., changing the replica path, zookeeper path, or other engine settings),
 //     cannot be altered in-place and require the table to be dropped and recreated.
+//   - ORDER BY changes other than appending newly added columns, since ClickHouse can't reorder or shorten a sorting key.
 //
 // This function checks for these conditions and returns true if DROP+CREATE is necessary.
 func shouldUseDropCreate(currentTable, targetTable *TableInfo) bool {
 	// For integration engines or engine changes that require DROP+CREATE, use DROP+CREATE strategy
 	// Integration engines are read-only from ClickHouse perspective and modifications require recreating the table
 	// ReplicatedMergeTree parameter changes also require DROP+CREATE as they cannot be altered
-	return isIntegrationEngine(currentTable.Engine) ||
+	if isIntegrationEngine(currentTable.Engine) ||
 		isIntegrationEngine(targetTable.Engine) ||
-		requiresDropCreate(currentTable.Engine, targetTable.Engine)
+		requiresDropCreate(currentTable.Engine, targetTable.Engine) {
+		return true
+	}
+
+	// Sorting keys can only be extended in place (see compareSortKeys)
+	change, _ := compareSortKeys(currentTable, targetTable)
+	return change == sortKeyIncompatible
 }
 
 // createRenameDiff creates a TableDiff for rename operation
-func createRenameDiff(oldName, newName string, currentTable, targetTable *TableInfo) *TableDiff {
+func createRenameDiff(oldName, newName string, currentTable, targetTable *TableInfo, opts CompareOptions) *TableDiff {
 	return &TableDiff{
 		DiffBase: DiffBase{
 			Type:        string(TableDiffRename),
 			Name:        oldName,
 			NewName:     newName,
 			Description: fmt.Sprintf("Rename table %s to %s", oldName, newName),
-			UpSQL:       generateRenameTableSQL(currentTable, targetTable, oldName, newName),
-			DownSQL:     generateRenameTableSQL(targetTable, currentTable, newName, oldName),
-		},
+		}.withSQL(opts, func() (string, string) {
+			return generateRenameTableSQL(currentTable, targetTable, oldName, newName),
+				generateRenameTableSQL(targetTable, currentTable, newName, oldName)
+		}),
 		Current: currentTable,
 		Target:  targetTable,
 	}
 }
 
 // createCreateDiff creates a TableDiff for create operation
-func createCreateDiff(tableName string, targetTable *TableInfo) *TableDiff {
+func createCreateDiff(tableName string, targetTable *TableInfo, opts CompareOptions) *TableDiff {
 	return &TableDiff{
 		DiffBase: DiffBase{
 			Type:        string(TableDiffCreate),
 			Name:        tableName,
 			Description: "Create table " + tableName,
-			UpSQL:       generateCreateTableSQL(targetTable),
-			DownSQL:     generateDropTableSQL(targetTable),
-		},
+		}.withSQL(opts, func() (string, string) {
+			return generateCreateTableSQL(targetTable), generateDropTableSQL(targetTable)
+		}),
 		Target: targetTable,
 	}
 }
 
 // createDropCreateDiff creates a TableDiff for DROP+CREATE operation
-func createDropCreateDiff(tableName string, currentTable, targetTable *TableInfo) *TableDiff {
-	reason := "integration engine"
-	if requiresDropCreate(currentTable.Engine, targetTable.Engine) {
-		reason = "engine parameter change"
-	}
+func createDropCreateDiff(tableName string, currentTable, targetTable *TableInfo, opts CompareOptions) *TableDiff {
+	reason := recreateReason(currentTable, targetTable)
 
 	return &TableDiff{
 		DiffBase: DiffBase{
 			Type:        string(TableDiffAlter),
 			Name:        tableName,
 			Description: fmt.Sprintf("Alter table %s (DROP+CREATE for %s)", tableName, reason),
-			UpSQL:       generateDropTableSQL(currentTable) + "\n\n" + generateCreateTableSQL(targetTable),
-			DownSQL:     generateDropTableSQL(targetTable) + "\n\n" + generateCreateTableSQL(currentTable),
-		},
+		}.withSQL(opts, func() (string, string) {
+			return generateDropTableSQL(currentTable) + "\n\n" + generateCreateTableSQL(targetTable),
+				generateDropTableSQL(targetTable) + "\n\n" + generateCreateTableSQL(currentTable)
+		}),
+		Current: currentTable,
+		Target:  targetTable,
+	}
+}
+
+// createReplaceDiff creates a TableDiff that recreates a table with a single atomic
+// CREATE OR REPLACE TABLE rather than DROP+CREATE
+func createReplaceDiff(tableName string, currentTable, targetTable *TableInfo, opts CompareOptions) *TableDiff {
+	reason := recreateReason(currentTable, targetTable)
+
+	return &TableDiff{
+		DiffBase: DiffBase{
+			Type:        string(TableDiffAlter),
+			Name:        tableName,
+			Description: fmt.Sprintf("Alter table %s (CREATE OR REPLACE for %s)", tableName, reason),
+		}.withSQL(opts, func() (string, string) {
+			return generateReplaceTableSQL(targetTable), generateReplaceTableSQL(currentTable)
+		}),
 		Current: currentTable,
 		Target:  targetTable,
 	}
 }
 
-// createAlterDiff creates a TableDiff for alter operation
-func createAlterDiff(tableName string, currentTable, targetTable *TableInfo, columnChanges []ColumnDiff) *TableDiff {
+// recreateReason describes why a table has to be recreated instead of altered
+func recreateReason(currentTable, targetTable *TableInfo) string {
+	if requiresDropCreate(currentTable.Engine, targetTable.Engine) {
+		return "engine parameter change"
+	}
+	if isIntegrationEngine(currentTable.Engine) || isIntegrationEngine(targetTable.Engine) {
+		return "integration engine"
+	}
+	if change, reason := compareSortKeys(currentTable, targetTable); change == sortKeyIncompatible {
+		return reason
+	}
+	return "integration engine"
+}
+
+// createAlterDiff creates a TableDiff for alter operation, returning nil when there's
+// nothing to alter
+func createAlterDiff(tableName string, currentTable, targetTable *TableInfo, columnChanges []ColumnDiff, opts CompareOptions) *TableDiff {
+	indexChanges := compareTableElements(currentTable.Indexes, targetTable.Indexes, columnChanges, newIndexDiff)
+	constraintChanges := compareTableElements(currentTable.Constraints, targetTable.Constraints, columnChanges, newConstraintDiff)
+	projectionChanges := compareTableElements(currentTable.Projections, targetTable.Projections, columnChanges, newProjectionDiff)
+
+	// Tables that only differ in equivalent ways (e.g. engine parameters) have nothing to alter
+	if len(columnChanges) == 0 && len(indexChanges) == 0 && len(constraintChanges) == 0 &&
+		len(projectionChanges) == 0 && len(tablePropertyChanges(currentTable, targetTable)) == 0 {
+		return nil
+	}
+
 	return &TableDiff{
 		DiffBase: DiffBase{
 			Type:        string(TableDiffAlter),
 			Name:        tableName,
 			Description: "Alter table " + tableName,
-			UpSQL:       generateAlterTableSQL(targetTable, columnChanges),
-			DownSQL:     generateAlterTableSQL(currentTable, reverseColumnChanges(columnChanges)),
-		},
-		Current:       currentTable,
-		Target:        targetTable,
-		ColumnChanges: columnChanges,
+		}.withSQL(opts, func() (string, string) {
+			upSQL := generateAlterTableSQL(
+				targetTable,
+				columnChanges,
+				indexChanges,
+				constraintChanges,
+				projectionChanges,
+				tablePropertyChanges(currentTable, targetTable),
+			)
+
+			// A sorting key can't be shortened, so undoing an extension means recreating the table.
+			// That would lose the table's data, so it's left to be done manually.
+			if change, _ := compareSortKeys(currentTable, targetTable); change == sortKeyExtended {
+				return upSQL, "-- ClickHouse cannot shorten the sorting key of " + tableName +
+					" without recreating the table (losing its data), revert manually"
+			}
+
+			return upSQL, generateAlterTableSQL(
+				currentTable,
+				reverseColumnChanges(columnChanges),
+				reverseTableElementChanges(indexChanges, newIndexDiff),
+				reverseTableElementChanges(constraintChanges, newConstraintDiff),
+				reverseTableElementChanges(projectionChanges, newProjectionDiff),
+				tablePropertyChanges(targetTable, currentTable),
+			)
+		}),
+		Current:           currentTable,
+		Target:            targetTable,
+		ColumnChanges:     columnChanges,
+		IndexChanges:      indexChanges,
+		ConstraintChanges: constraintChanges,
+		ProjectionChanges: projectionChanges,
 	}
 }
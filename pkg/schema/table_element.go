@@ -0,0 +1,73 @@
+package schema
+
+type (
+	// tableElement is an element of a table definition that's matched by name and that
+	// ClickHouse can only add or drop: a data skipping index, a CHECK constraint, or a
+	// projection. A changed element is expressed as a DROP followed by an ADD.
+	tableElement[T any] interface {
+		elementName() string
+		Equal(other T) bool
+		// renameColumns returns the element with renamed columns replaced in its definition
+		renameColumns(renames map[string]string) T
+	}
+
+	// tableElementDiff is the diff type of a tableElement (e.g. IndexDiff for IndexInfo)
+	tableElementDiff[T any] interface {
+		// change returns whether the element is added (rather than dropped), and the element
+		change() (add bool, element T)
+	}
+)
+
+// compareTableElements compares the elements of two tables and returns the differences
+// built by newDiff. Elements are matched by name, so declaring the same elements in a
+// different order (or in a different position among the columns) produces no changes.
+//
+// Drops are listed before adds (each in declaration order) so that a changed element is
+// dropped before it's added again under the same name. Columns renamed in the same ALTER
+// are applied to the current elements first, since ClickHouse rewrites the elements
+// referencing a column when renaming it.
+func compareTableElements[T tableElement[T], D any](current, target []T, columnChanges []ColumnDiff, newDiff func(add bool, element T) D) []D {
+	renamed := make(map[string]string)
+	for _, change := range columnChanges {
+		if change.Type == ColumnDiffRename {
+			renamed[change.Current.Name] = change.Target.Name
+		}
+	}
+
+	currentElements := make(map[string]T, len(current))
+	for _, element := range current {
+		currentElements[element.elementName()] = element.renameColumns(renamed)
+	}
+	targetElements := make(map[string]T, len(target))
+	for _, element := range target {
+		targetElements[element.elementName()] = element
+	}
+
+	var drops, adds []D
+	for _, element := range current {
+		if targetElement, exists := targetElements[element.elementName()]; !exists || !currentElements[element.elementName()].Equal(targetElement) {
+			drops = append(drops, newDiff(false, element))
+		}
+	}
+	for _, element := range target {
+		if currentElement, exists := currentElements[element.elementName()]; !exists || !currentElement.Equal(element) {
+			adds = append(adds, newDiff(true, element))
+		}
+	}
+
+	return append(drops, adds...)
+}
+
+// reverseTableElementChanges reverses element changes for down migration. Drops of the
+// reversed changes (the original adds) are listed first, matching compareTableElements.
+func reverseTableElementChanges[T any, D tableElementDiff[T]](changes []D, newDiff func(add bool, element T) D) []D {
+	var drops, adds []D
+	for _, change := range changes {
+		if add, element := change.change(); add {
+			drops = append(drops, newDiff(false, element))
+		} else {
+			adds = append(adds, newDiff(true, element))
+		}
+	}
+	return append(drops, adds...)
+}
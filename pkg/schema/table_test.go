@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pseudomuto/housekeeper/pkg/parser"
@@ -59,6 +61,12 @@ func TestEnginesEqual(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:     "engine names are case-sensitive",
+			target:   &parser.TableEngine{Name: "mergetree"},
+			current:  &parser.TableEngine{Name: "MergeTree"},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -69,6 +77,12 @@ func TestEnginesEqual(t *testing.T) {
 	}
 }
 
+func TestViewEnginesEqual_Case(t *testing.T) {
+	require.True(t, viewEnginesEqual(&parser.ViewEngine{Name: "MergeTree"}, &parser.ViewEngine{Name: "MergeTree"}))
+	require.False(t, viewEnginesEqual(&parser.ViewEngine{Name: "mergetree"}, &parser.ViewEngine{Name: "MergeTree"}),
+		"view engines are compared case-sensitively like table engines")
+}
+
 func TestTableInfoEqual(t *testing.T) {
 	// Test ReplicatedMergeTree with different explicit parameters
 	currentTable := &TableInfo{
@@ -112,3 +126,702 @@ func TestTableInfoEqual(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// parseTableInfo parses a single CREATE TABLE statement and returns its table
+func parseTableInfo(t *testing.T, sql string) *TableInfo {
+	t.Helper()
+
+	parsed, err := parser.ParseString(sql)
+	require.NoError(t, err)
+
+	tables, err := extractTablesFromSQL(parsed)
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	for _, table := range tables {
+		return table
+	}
+	return nil
+}
+
+func TestTableInfoEqual_CompoundSettings(t *testing.T) {
+	tableInfo := func(settings string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events (id UInt64) ENGINE = MergeTree() ORDER BY id SETTINGS "+settings+";")
+	}
+
+	base := tableInfo("additional_table_filters = {'events': 'id > 0'}, allowed_disks = ['hot', 'cold']")
+	require.Equal(t, "{'events': 'id > 0'}", base.Settings["additional_table_filters"])
+	require.Equal(t, "['hot', 'cold']", base.Settings["allowed_disks"])
+
+	require.True(t, base.Equal(tableInfo("additional_table_filters = { 'events' : 'id > 0' }, allowed_disks = ['hot','cold']")),
+		"whitespace differences in compound values should not affect equality")
+	require.False(t, base.Equal(tableInfo("additional_table_filters = {'events': 'id > 0', 'users': 'id > 0'}, allowed_disks = ['hot', 'cold']")),
+		"added map entries should be detected")
+	require.False(t, base.Equal(tableInfo("additional_table_filters = {'events': 'id > 0'}, allowed_disks = ['cold', 'hot']")),
+		"array order should be significant")
+}
+
+func TestTableInfoEqual_ImplicitSettingDefaults(t *testing.T) {
+	tableInfo := func(engine, settings string) *TableInfo {
+		sql := "CREATE TABLE db.events (id UInt64) ENGINE = " + engine + " ORDER BY id"
+		if settings != "" {
+			sql += " SETTINGS " + settings
+		}
+
+		return parseTableInfo(t, sql+";")
+	}
+
+	declared := tableInfo("MergeTree()", "")
+	require.True(t, declared.Equal(tableInfo("MergeTree()", "index_granularity = 8192")),
+		"omitted index_granularity should match the dumped default")
+	require.True(t, tableInfo("MergeTree()", "index_granularity = 8192").Equal(declared),
+		"comparison should be symmetric")
+	require.True(t, declared.Equal(tableInfo("MergeTree()", "index_granularity = 8192, index_granularity_bytes = 10485760")),
+		"multiple dumped defaults should be ignored")
+	require.True(t, tableInfo("ReplicatedMergeTree()", "").Equal(tableInfo("ReplicatedMergeTree()", "index_granularity = 8192")),
+		"defaults apply to the whole MergeTree family")
+
+	require.False(t, declared.Equal(tableInfo("MergeTree()", "index_granularity = 4096")),
+		"non-default values should be detected")
+	require.False(t, tableInfo("MergeTree()", "index_granularity = 8192").Equal(tableInfo("MergeTree()", "index_granularity = 4096")),
+		"changing away from the default should be detected")
+	require.False(t, tableInfo("Log", "").Equal(tableInfo("Log", "index_granularity = 8192")),
+		"defaults are engine specific")
+
+	RegisterEngineSettingDefault("StripeLog", "max_compress_block_size", "1048576")
+	require.True(t, tableInfo("StripeLog", "").Equal(tableInfo("StripeLog", "max_compress_block_size = 1048576")),
+		"registered defaults should be honored")
+}
+
+func TestColumnInfoEqual_Ephemeral(t *testing.T) {
+	column := func(definition string) ColumnInfo {
+		return parseTableInfo(t, "CREATE TABLE db.t (id UInt64, "+definition+") ENGINE = MergeTree() ORDER BY id;").Columns[1]
+	}
+
+	require.True(t, column("c String EPHEMERAL").Equal(column("`c` String EPHEMERAL")))
+	require.True(t, column("c UInt8 EPHEMERAL 0").Equal(column("c UInt8 EPHEMERAL 0")))
+
+	require.False(t, column("c UInt8 EPHEMERAL 0").Equal(column("c UInt8 EPHEMERAL 1")),
+		"changed default expressions should be detected")
+	require.False(t, column("c UInt8 EPHEMERAL").Equal(column("c UInt8 EPHEMERAL 0")),
+		"adding a default expression should be detected")
+	require.False(t, column("c UInt8 EPHEMERAL").Equal(column("c UInt8 DEFAULT 0")),
+		"changing the default type should be detected")
+	require.False(t, column("c UInt8 EPHEMERAL").Equal(column("c UInt8")),
+		"removing EPHEMERAL should be detected")
+
+	require.Equal(t, "`c` String EPHEMERAL", formatColumnDefinition(column("c String EPHEMERAL")))
+}
+
+func TestTableInfoEqual_StrictComments(t *testing.T) {
+	tableInfo := func(tableComment, columnComment string, strict bool) *TableInfo {
+		sql := "CREATE TABLE db.events (id UInt64 COMMENT '" + columnComment + "') ENGINE = MergeTree() ORDER BY id COMMENT '" + tableComment + "';"
+		parsed, err := parser.ParseString(sql)
+		require.NoError(t, err)
+
+		tables, err := extractTablesFromSQL(parsed)
+		require.NoError(t, err)
+		if strict {
+			useStrictTableComments(tables)
+		}
+		return tables["db.events"]
+	}
+
+	require.True(t, tableInfo("User events", "Event ID", false).Equal(tableInfo("user events", "event id", false)),
+		"lenient comparison should ignore comment case")
+	require.False(t, tableInfo("User events", "Event ID", true).Equal(tableInfo("user events", "Event ID", true)),
+		"strict comparison should detect table comment case changes")
+	require.False(t, tableInfo("User events", "Event ID", true).Equal(tableInfo("User events", "event id", true)),
+		"strict comparison should detect column comment case changes")
+	require.True(t, tableInfo("User events", "Event ID", true).Equal(tableInfo("User events", "Event ID", true)),
+		"strict comparison should match identical comments")
+}
+
+func TestTableInfoEqual_SortKeyExpressions(t *testing.T) {
+	tableInfo := func(clauses string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events (date Date, id UInt64, user_id UInt64) ENGINE = MergeTree() "+clauses+";")
+	}
+
+	base := tableInfo("PARTITION BY toYYYYMM(date) ORDER BY (toYYYYMM(date), id, intHash32(user_id)) SAMPLE BY intHash32(user_id)")
+
+	require.True(t, base.Equal(tableInfo("PARTITION BY toYYYYMM(`date`) ORDER BY (toYYYYMM(`date`), `id`, intHash32(`user_id`)) SAMPLE BY intHash32(`user_id`)")),
+		"backticked identifiers should not affect equality")
+	require.True(t, base.Equal(tableInfo("PARTITION BY toYYYYMM( date ) ORDER BY ( toYYYYMM(date),id,intHash32( user_id ) ) SAMPLE BY intHash32(user_id)")),
+		"whitespace differences should not affect equality")
+	require.True(t, tableInfo("ORDER BY id").Equal(tableInfo("ORDER BY (id)")),
+		"a parenthesized single column sort key should match the bare column")
+	require.True(t, tableInfo("ORDER BY toYYYYMM(date)").Equal(tableInfo("ORDER BY (toYYYYMM(`date`))")),
+		"a parenthesized single expression sort key should match the bare expression")
+
+	require.False(t, base.Equal(tableInfo("PARTITION BY toYYYYMM(date) ORDER BY (toStartOfWeek(date), id, intHash32(user_id)) SAMPLE BY intHash32(user_id)")),
+		"function changes should be detected")
+	require.False(t, base.Equal(tableInfo("PARTITION BY toYYYYMM(date) ORDER BY (id, toYYYYMM(date), intHash32(user_id)) SAMPLE BY intHash32(user_id)")),
+		"sort key order should be significant")
+	require.False(t, base.Equal(tableInfo("PARTITION BY toMonday(date) ORDER BY (toYYYYMM(date), id, intHash32(user_id)) SAMPLE BY intHash32(user_id)")),
+		"partition expression changes should be detected")
+}
+
+func TestTableInfoEqual_IndexOrder(t *testing.T) {
+	tableInfo := func(elements string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.logs ("+elements+") ENGINE = MergeTree() ORDER BY id;")
+	}
+
+	base := tableInfo("id UInt64, query String, ts DateTime, INDEX query_bloom query TYPE bloom_filter, INDEX ts_minmax ts TYPE minmax GRANULARITY 4")
+
+	require.True(t, base.Equal(tableInfo("id UInt64, query String, ts DateTime, INDEX ts_minmax ts TYPE minmax GRANULARITY 4, INDEX query_bloom query TYPE bloom_filter")),
+		"index declaration order should not affect equality")
+	require.True(t, base.Equal(tableInfo("id UInt64, INDEX ts_minmax ts TYPE minmax GRANULARITY 4, query String, INDEX query_bloom query TYPE bloom_filter, ts DateTime")),
+		"indexes declared between columns should match indexes declared after them")
+
+	require.False(t, base.Equal(tableInfo("id UInt64, query String, ts DateTime, INDEX ts_minmax ts TYPE minmax GRANULARITY 2, INDEX query_bloom query TYPE bloom_filter")),
+		"index changes should still be detected")
+	require.False(t, base.Equal(tableInfo("id UInt64, query String, ts DateTime, INDEX ts_minmax ts TYPE minmax GRANULARITY 4")),
+		"dropped indexes should still be detected")
+	require.Empty(t, compareTableElements(base.Indexes, tableInfo("id UInt64, query String, ts DateTime, INDEX ts_minmax ts TYPE minmax GRANULARITY 4, INDEX query_bloom query TYPE bloom_filter").Indexes, nil, newIndexDiff))
+}
+
+func TestCompareColumns_RenameWithDependents(t *testing.T) {
+	tableInfo := func(columns string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.orders ("+columns+") ENGINE = MergeTree() ORDER BY id;")
+	}
+
+	current := tableInfo("id UInt64, amount UInt64, total UInt64 MATERIALIZED amount * 2")
+	target := tableInfo("id UInt64, price UInt64, total UInt64 MATERIALIZED price * 3")
+	currentDefault := current.Columns[2].Default.String()
+
+	changes := compareColumns(current.Columns, target.Columns, true)
+	require.Len(t, changes, 2)
+	require.Equal(t, ColumnDiffRename, changes[0].Type)
+	require.Equal(t, "amount", changes[0].Current.Name)
+	require.Equal(t, "price", changes[0].Target.Name)
+	require.Equal(t, ColumnDiffModify, changes[1].Type)
+	require.Equal(t, currentDefault, current.Columns[2].Default.String(), "the current table AST must not be modified")
+
+	require.Equal(t,
+		"ALTER TABLE db.orders\n    RENAME COLUMN `amount` TO `price`,\n    MODIFY COLUMN `total` UInt64 MATERIALIZED price * 3",
+		generateAlterTableSQL(target, changes, nil, nil, nil, nil),
+	)
+
+	// The down migration restores the expression before renaming the column back, so
+	// ClickHouse rewrites it to reference the original name
+	require.Equal(t,
+		"ALTER TABLE db.orders\n    MODIFY COLUMN `total` UInt64 MATERIALIZED price * 2,\n    RENAME COLUMN `price` TO `amount`",
+		generateAlterTableSQL(current, reverseColumnChanges(changes), nil, nil, nil, nil),
+	)
+
+	t.Run("rename only", func(t *testing.T) {
+		changes := compareColumns(current.Columns, tableInfo("id UInt64, price UInt64, total UInt64 MATERIALIZED price * 2").Columns, true)
+		require.Len(t, changes, 1)
+		require.Equal(t, ColumnDiffRename, changes[0].Type)
+	})
+
+	t.Run("renames are not detected by default", func(t *testing.T) {
+		current := tableInfo("id UInt64, legacy_email String")
+		target := tableInfo("id UInt64, phone_number String")
+
+		changes := compareColumns(current.Columns, target.Columns, false)
+		require.Len(t, changes, 2)
+		require.Equal(t, ColumnDiffAdd, changes[0].Type)
+		require.Equal(t, ColumnDiffDrop, changes[1].Type)
+		require.Equal(t,
+			"ALTER TABLE db.orders\n    ADD COLUMN `phone_number` String,\n    DROP COLUMN `legacy_email`",
+			generateAlterTableSQL(target, changes, nil, nil, nil, nil),
+		)
+	})
+
+	t.Run("ambiguous matches are not renames", func(t *testing.T) {
+		current := tableInfo("id UInt64, a String, b String")
+		target := tableInfo("id UInt64, c String, d String")
+
+		changes := compareColumns(current.Columns, target.Columns, true)
+		for _, change := range changes {
+			require.NotEqual(t, ColumnDiffRename, change.Type)
+		}
+	})
+}
+
+func TestCompareColumns_CommentOnly(t *testing.T) {
+	tableInfo := func(columns string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events ("+columns+") ENGINE = MergeTree() ORDER BY id;")
+	}
+
+	current := tableInfo("id UInt64, ts DateTime CODEC(Delta, ZSTD(1)) TTL ts + INTERVAL 1 DAY COMMENT 'Event time'")
+	target := tableInfo("id UInt64, ts DateTime CODEC(Delta, ZSTD(1)) TTL ts + INTERVAL 1 DAY COMMENT 'When the event happened'")
+
+	changes := compareColumns(current.Columns, target.Columns, false)
+	require.Len(t, changes, 1)
+	require.Equal(t, ColumnDiffComment, changes[0].Type)
+	require.Equal(t,
+		"ALTER TABLE db.events\n    COMMENT COLUMN `ts` 'When the event happened'",
+		generateAlterTableSQL(target, changes, nil, nil, nil, nil),
+	)
+	require.Equal(t,
+		"ALTER TABLE db.events\n    COMMENT COLUMN `ts` 'Event time'",
+		generateAlterTableSQL(current, reverseColumnChanges(changes), nil, nil, nil, nil),
+	)
+
+	t.Run("removed comment", func(t *testing.T) {
+		changes := compareColumns(current.Columns, tableInfo("id UInt64, ts DateTime CODEC(Delta, ZSTD(1)) TTL ts + INTERVAL 1 DAY").Columns, false)
+		require.Len(t, changes, 1)
+		require.Equal(t, "ALTER TABLE db.events\n    COMMENT COLUMN `ts` ''", generateAlterTableSQL(target, changes, nil, nil, nil, nil))
+	})
+
+	t.Run("other changes still modify the column", func(t *testing.T) {
+		changes := compareColumns(current.Columns, tableInfo("id UInt64, ts DateTime64 CODEC(Delta, ZSTD(1)) TTL ts + INTERVAL 1 DAY COMMENT 'When'").Columns, false)
+		require.Len(t, changes, 1)
+		require.Equal(t, ColumnDiffModify, changes[0].Type)
+	})
+}
+
+func TestCompareColumns_Position(t *testing.T) {
+	tableInfo := func(columns string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.users ("+columns+") ENGINE = MergeTree() ORDER BY id;")
+	}
+
+	current := tableInfo("id UInt64, name String")
+
+	tests := []struct {
+		name          string
+		target        string
+		detectRenames bool
+		up            string
+		down          string
+	}{
+		{
+			name:   "insert at front",
+			target: "tenant_id UInt32, id UInt64, name String",
+			up:     "ALTER TABLE db.users\n    ADD COLUMN `tenant_id` UInt32 FIRST",
+			down:   "ALTER TABLE db.users\n    DROP COLUMN `tenant_id`",
+		},
+		{
+			name:   "insert in middle",
+			target: "id UInt64, email String, name String",
+			up:     "ALTER TABLE db.users\n    ADD COLUMN `email` String AFTER `id`",
+			down:   "ALTER TABLE db.users\n    DROP COLUMN `email`",
+		},
+		{
+			name:   "consecutive inserts",
+			target: "id UInt64, email String, phone String, name String",
+			up:     "ALTER TABLE db.users\n    ADD COLUMN `email` String AFTER `id`,\n    ADD COLUMN `phone` String AFTER `email`",
+			down:   "ALTER TABLE db.users\n    DROP COLUMN `email`,\n    DROP COLUMN `phone`",
+		},
+		{
+			name:   "append",
+			target: "id UInt64, name String, email String, phone String",
+			up:     "ALTER TABLE db.users\n    ADD COLUMN `email` String,\n    ADD COLUMN `phone` String",
+			down:   "ALTER TABLE db.users\n    DROP COLUMN `email`,\n    DROP COLUMN `phone`",
+		},
+		{
+			name:          "insert after renamed column",
+			target:        "user_id UInt64, email String, name String",
+			detectRenames: true,
+			up:            "ALTER TABLE db.users\n    RENAME COLUMN `id` TO `user_id`,\n    ADD COLUMN `email` String AFTER `user_id`",
+			down:          "ALTER TABLE db.users\n    DROP COLUMN `email`,\n    RENAME COLUMN `user_id` TO `id`",
+		},
+		{
+			name:   "drop restores position",
+			target: "name String",
+			up:     "ALTER TABLE db.users\n    DROP COLUMN `id`",
+			down:   "ALTER TABLE db.users\n    ADD COLUMN `id` UInt64 FIRST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tableInfo(tt.target)
+
+			changes := compareColumns(current.Columns, target.Columns, tt.detectRenames)
+			require.Equal(t, tt.up, generateAlterTableSQL(current, changes, nil, nil, nil, nil))
+			require.Equal(t, tt.down, generateAlterTableSQL(current, reverseColumnChanges(changes), nil, nil, nil, nil))
+		})
+	}
+
+	t.Run("reordered columns are not changed", func(t *testing.T) {
+		require.Empty(t, compareColumns(current.Columns, tableInfo("name String, id UInt64").Columns, false))
+	})
+}
+
+func TestCreateAlterDiff_TableProperties(t *testing.T) {
+	tableInfo := func(sql string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events (id UInt64, ts DateTime"+sql+";")
+	}
+
+	current := tableInfo(") ENGINE = MergeTree() ORDER BY id TTL ts + INTERVAL 30 DAY SETTINGS ttl_only_drop_parts = 1")
+	target := tableInfo(", name String) ENGINE = MergeTree() ORDER BY id SAMPLE BY id TTL ts + INTERVAL 90 DAY SETTINGS merge_with_ttl_timeout = 3600 COMMENT 'Events'")
+
+	diff := createAlterDiff("db.events", current, target, compareColumns(current.Columns, target.Columns, false), CompareOptions{})
+	require.Equal(t, "ALTER TABLE db.events\n"+
+		"    ADD COLUMN `name` String,\n"+
+		"    MODIFY SAMPLE BY id,\n"+
+		"    MODIFY TTL ts + INTERVAL 90 DAY,\n"+
+		"    MODIFY SETTING merge_with_ttl_timeout = 3600,\n"+
+		"    RESET SETTING ttl_only_drop_parts,\n"+
+		"    MODIFY COMMENT 'Events'",
+		diff.UpSQL,
+		"all operations should be combined into a single ALTER",
+	)
+	require.Equal(t, "ALTER TABLE db.events\n"+
+		"    DROP COLUMN `name`,\n"+
+		"    REMOVE SAMPLE BY,\n"+
+		"    MODIFY TTL ts + INTERVAL 30 DAY,\n"+
+		"    MODIFY SETTING ttl_only_drop_parts = 1,\n"+
+		"    RESET SETTING merge_with_ttl_timeout,\n"+
+		"    MODIFY COMMENT ''",
+		diff.DownSQL,
+	)
+
+	withTimeout := tableInfo(") ENGINE = MergeTree() ORDER BY id SETTINGS merge_with_ttl_timeout = 3600")
+	require.Empty(t, tablePropertyChanges(withTimeout, tableInfo(") ENGINE = MergeTree() ORDER BY id SETTINGS merge_with_ttl_timeout = 3600")))
+	require.Equal(t, []string{"RESET SETTING merge_with_ttl_timeout"},
+		tablePropertyChanges(withTimeout, tableInfo(") ENGINE = MergeTree() ORDER BY id SETTINGS merge_with_ttl_timeout = 14400")),
+		"changing a setting back to the engine default should reset it")
+
+	// CREATE-only settings can't be altered, so the change is rejected rather than emitted
+	_, err := createTableDiff("db.events", current, tableInfo(") ENGINE = MergeTree() ORDER BY id TTL ts + INTERVAL 30 DAY SETTINGS ttl_only_drop_parts = 1, index_granularity = 4096"), nil, nil, true, CompareOptions{})
+	var destructive *ErrDestructiveOperation
+	require.ErrorAs(t, err, &destructive)
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.ErrorContains(t, err, "cannot change setting index_granularity of table db.events")
+}
+
+func TestCreateAlterDiff_TableTTL(t *testing.T) {
+	tableInfo := func(sql string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY id"+sql+";")
+	}
+
+	withoutTTL := tableInfo("")
+	withTTL := tableInfo(" TTL ts + INTERVAL 30 DAY")
+
+	diff, err := handleTableExists("db.events", withoutTTL, withTTL, CompareOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE db.events\n    MODIFY TTL ts + INTERVAL 30 DAY", diff.UpSQL)
+	require.Equal(t, "ALTER TABLE db.events\n    REMOVE TTL", diff.DownSQL)
+
+	diff, err = handleTableExists("db.events", withTTL, withoutTTL, CompareOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE db.events\n    REMOVE TTL", diff.UpSQL)
+	require.Equal(t, "ALTER TABLE db.events\n    MODIFY TTL ts + INTERVAL 30 DAY", diff.DownSQL)
+}
+
+func TestHandleTableExists_SortKey(t *testing.T) {
+	tableInfo := func(sql string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events ("+sql+";")
+	}
+
+	current := tableInfo("id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (id, ts)")
+
+	t.Run("appending new columns", func(t *testing.T) {
+		target := tableInfo("id UInt64, ts DateTime, source String) ENGINE = MergeTree() ORDER BY (id, ts, lower(source))")
+
+		diff, err := handleTableExists("db.events", current, target, CompareOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "Alter table db.events", diff.Description)
+		require.Equal(t, "ALTER TABLE db.events\n"+
+			"    ADD COLUMN `source` String,\n"+
+			"    MODIFY ORDER BY (id,ts,lower(source))",
+			diff.UpSQL)
+		require.Equal(t, "-- ClickHouse cannot shorten the sorting key of db.events without recreating the table (losing its data), revert manually",
+			diff.DownSQL, "a sorting key can't be shortened in place, and recreating the table would lose its data")
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		reason string
+	}{
+		{
+			name:   "reordering",
+			target: "id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, id)",
+			reason: "not reordered or shortened",
+		},
+		{
+			name:   "removing a column",
+			target: "id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY id",
+			reason: "not reordered or shortened",
+		},
+		{
+			name:   "appending an existing column",
+			target: "id UInt64, ts DateTime, source String) ENGINE = MergeTree() ORDER BY (id, ts, toDate(ts))",
+			reason: "appends existing column ts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := handleTableExists("db.events", current, tableInfo(tt.target), CompareOptions{})
+			require.NoError(t, err)
+			require.Contains(t, diff.Description, "DROP+CREATE for ORDER BY change from (id,ts) to ")
+			require.Contains(t, diff.Description, tt.reason)
+			require.True(t, strings.HasPrefix(diff.UpSQL, "DROP TABLE"))
+		})
+	}
+}
+
+func TestHandleTableExists_TableElements(t *testing.T) {
+	// Indexes, constraints, and projections are all compared by compareTableElements. Each
+	// element kind has two versions of the same named element over columns a and b, and is
+	// declared in a table by its SQL (ADD) keyword.
+	elements := []struct {
+		kind       string
+		keyword    string
+		name       string
+		definition string // Declaration with %s standing for the element's column
+		current    string // Formatted current version (over column a)
+		target     string // Formatted target version (over column b)
+		changed    string // Declaration over column a that differs in more than its column
+	}{
+		{
+			kind:       "index",
+			keyword:    "INDEX",
+			name:       "el_idx",
+			definition: "INDEX el_idx %s TYPE minmax GRANULARITY 2",
+			current:    "`el_idx` a TYPE minmax GRANULARITY 2",
+			target:     "`el_idx` b TYPE minmax GRANULARITY 2",
+			changed:    "INDEX el_idx a TYPE set(10) GRANULARITY 2",
+		},
+		{
+			kind:       "constraint",
+			keyword:    "CONSTRAINT",
+			name:       "el_check",
+			definition: "CONSTRAINT el_check CHECK %s >= 18",
+			current:    "`el_check` CHECK a >= 18",
+			target:     "`el_check` CHECK b >= 18",
+			changed:    "CONSTRAINT el_check CHECK a >= 13",
+		},
+		{
+			kind:       "projection",
+			keyword:    "PROJECTION",
+			name:       "el_proj",
+			definition: "PROJECTION el_proj (SELECT %[1]s, count() GROUP BY %[1]s)",
+			current:    "`el_proj` (SELECT `a`, count() GROUP BY `a`)",
+			target:     "`el_proj` (SELECT `b`, count() GROUP BY `b`)",
+			changed:    "PROJECTION el_proj (SELECT * ORDER BY a)",
+		},
+	}
+
+	for _, el := range elements {
+		t.Run(el.kind, func(t *testing.T) {
+			tableInfo := func(columns, element string) *TableInfo {
+				if element != "" {
+					element = ", " + element
+				}
+				return parseTableInfo(t, "CREATE TABLE db.t (id UInt64, "+columns+element+") ENGINE = MergeTree() ORDER BY id;")
+			}
+
+			plain := tableInfo("a UInt8, b UInt8", "")
+			current := tableInfo("a UInt8, b UInt8", fmt.Sprintf(el.definition, "a"))
+			target := tableInfo("a UInt8, b UInt8", fmt.Sprintf(el.definition, "b"))
+			alter := func(ops ...string) string {
+				return "ALTER TABLE db.t\n    " + strings.Join(ops, ",\n    ")
+			}
+			add := func(definition string) string { return "ADD " + el.keyword + " " + definition }
+			drop := "DROP " + el.keyword + " `" + el.name + "`"
+
+			tests := []struct {
+				name    string
+				current *TableInfo
+				target  *TableInfo
+				up      string
+				down    string
+			}{
+				{name: "add", current: plain, target: current, up: alter(add(el.current)), down: alter(drop)},
+				{name: "drop", current: current, target: plain, up: alter(drop), down: alter(add(el.current))},
+				{name: "modify", current: current, target: target, up: alter(drop, add(el.target)), down: alter(drop, add(el.current))},
+				{name: "declared between columns", current: current, target: tableInfo("a UInt8, "+fmt.Sprintf(el.definition, "a")+", b UInt8", "")},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					diff, err := handleTableExists("db.t", tt.current, tt.target, CompareOptions{})
+					require.NoError(t, err)
+					if tt.up == "" {
+						require.Nil(t, diff)
+						return
+					}
+
+					require.Equal(t, tt.up, diff.UpSQL)
+					require.Equal(t, tt.down, diff.DownSQL)
+				})
+			}
+
+			t.Run("changed definition", func(t *testing.T) {
+				diff, err := handleTableExists("db.t", current, tableInfo("a UInt8, b UInt8", el.changed), CompareOptions{})
+				require.NoError(t, err)
+				require.True(t, strings.HasPrefix(diff.UpSQL, alter(drop)+",\n    ADD "+el.keyword), diff.UpSQL)
+			})
+
+			t.Run("renamed column", func(t *testing.T) {
+				renamed := tableInfo("c UInt8, b UInt8", fmt.Sprintf(el.definition, "c"))
+				diff, err := handleTableExists("db.t", current, renamed, CompareOptions{DetectColumnRenames: true})
+				require.NoError(t, err)
+				require.Equal(t, alter("RENAME COLUMN `a` TO `c`"), diff.UpSQL,
+					"ClickHouse rewrites the %s when renaming its column", el.kind)
+			})
+
+			t.Run("create table", func(t *testing.T) {
+				require.Contains(t, generateCreateTableSQL(current), ",\n    "+el.keyword+" "+el.current+"\n)")
+			})
+		})
+	}
+}
+
+func TestHandleTableExists_Indexes(t *testing.T) {
+	tableInfo := func(sql string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.logs ("+sql+") ENGINE = MergeTree() ORDER BY id;")
+	}
+
+	t.Run("index on a new column", func(t *testing.T) {
+		current := tableInfo("id UInt64")
+		target := tableInfo("id UInt64, level String, INDEX level_idx level TYPE set(10)")
+
+		diff, err := handleTableExists("db.logs", current, target, CompareOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE db.logs\n"+
+			"    ADD COLUMN `level` String,\n"+
+			"    ADD INDEX `level_idx` level TYPE set(10)",
+			diff.UpSQL)
+		require.Equal(t, "ALTER TABLE db.logs\n"+
+			"    DROP INDEX `level_idx`,\n"+
+			"    DROP COLUMN `level`",
+			diff.DownSQL)
+	})
+
+	t.Run("omitted granularity", func(t *testing.T) {
+		current := tableInfo("id UInt64, level String, INDEX level_idx level TYPE set(10)")
+		target := tableInfo("id UInt64, level String, INDEX level_idx level TYPE set(10) GRANULARITY 1")
+
+		diff, err := handleTableExists("db.logs", current, target, CompareOptions{})
+		require.NoError(t, err)
+		require.Nil(t, diff, "an omitted granularity is ClickHouse's default of 1")
+	})
+}
+
+func TestHandleTableExists_ModifyColumnDefinition(t *testing.T) {
+	tableInfo := func(column string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.events (id UInt64, "+column+") ENGINE = MergeTree() ORDER BY id;")
+	}
+
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		up      string
+		down    string
+	}{
+		{
+			name:    "type and default",
+			current: "status UInt8 DEFAULT 0",
+			target:  "status UInt16 DEFAULT 1",
+			up:      "ALTER TABLE db.events\n    MODIFY COLUMN `status` UInt16 DEFAULT 1",
+			down:    "ALTER TABLE db.events\n    MODIFY COLUMN `status` UInt8 DEFAULT 0",
+		},
+		{
+			name:    "type and codec",
+			current: "status UInt8 CODEC(LZ4)",
+			target:  "status UInt16 CODEC(ZSTD(3))",
+			up:      "ALTER TABLE db.events\n    MODIFY COLUMN `status` UInt16 CODEC(ZSTD(3))",
+			down:    "ALTER TABLE db.events\n    MODIFY COLUMN `status` UInt8 CODEC(LZ4)",
+		},
+		{
+			name:    "type, default, and comment",
+			current: "status UInt8 DEFAULT 0 COMMENT 'Status'",
+			target:  "status UInt16 DEFAULT 1 COMMENT 'Status code'",
+			up:      "ALTER TABLE db.events\n    MODIFY COLUMN `status` UInt16 DEFAULT 1 COMMENT 'Status code'",
+			down:    "ALTER TABLE db.events\n    MODIFY COLUMN `status` UInt8 DEFAULT 0 COMMENT 'Status'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, target := tableInfo(tt.current), tableInfo(tt.target)
+
+			diff, err := handleTableExists("db.events", current, target, CompareOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.up, diff.UpSQL)
+			require.Equal(t, tt.down, diff.DownSQL)
+
+			// The generated statement must parse back into the full target column definition
+			parsed, err := parser.ParseString(diff.UpSQL + ";")
+			require.NoError(t, err)
+			require.Len(t, parsed.Statements, 1)
+			require.NotNil(t, parsed.Statements[0].AlterTable)
+			require.Len(t, parsed.Statements[0].AlterTable.Operations, 1)
+
+			modify := parsed.Statements[0].AlterTable.Operations[0].ModifyColumn
+			require.NotNil(t, modify)
+			roundTripped := ColumnInfo{
+				Name:     normalizeIdentifier(modify.Name),
+				DataType: modify.Type,
+				Codec:    modify.Codec,
+			}
+			if modify.Default != nil {
+				roundTripped.DefaultType = modify.Default.Type
+				roundTripped.Default = modify.Default.Expression
+			}
+			if modify.Comment != nil {
+				roundTripped.Comment = removeQuotes(*modify.Comment)
+			}
+			require.True(t, roundTripped.Equal(target.Columns[1]), "round-tripped column differs from target")
+		})
+	}
+}
+
+func TestExtractTablesFromSQL_QuotedNames(t *testing.T) {
+	sql, err := parser.ParseString("CREATE TABLE `analytics`.`events` (`id` UInt64) ENGINE = MergeTree() ORDER BY `id`;")
+	require.NoError(t, err)
+
+	tables, err := extractTablesFromSQL(sql)
+	require.NoError(t, err)
+	require.Contains(t, tables, "analytics.events")
+	require.Equal(t, "analytics", tables["analytics.events"].Database)
+	require.Equal(t, "events", tables["analytics.events"].Name)
+}
+
+func TestTypeWidens(t *testing.T) {
+	dataType := func(typ string) *parser.DataType {
+		parsed, err := parser.ParseString("CREATE TABLE t (c " + typ + ") ENGINE = Memory;")
+		require.NoError(t, err)
+		return parsed.Statements[0].CreateTable.Elements[0].Column.DataType
+	}
+
+	tests := []struct {
+		from, to string
+		widens   bool
+	}{
+		{"UInt8", "UInt32", true},
+		{"UInt32", "Int64", true},
+		{"Int32", "Int64", true},
+		{"UInt32", "Int32", false},
+		{"Int32", "UInt64", false},
+		{"Int64", "Int32", false},
+		{"Int16", "Float32", true},
+		{"Int64", "Float64", false},
+		{"Float32", "Float64", true},
+		{"Float64", "Float32", false},
+		{"UInt64", "String", true},
+		{"String", "UInt64", false},
+		{"String", "FixedString(64)", false},
+		{"FixedString(8)", "FixedString(9)", true},
+		{"FixedString(9)", "FixedString(8)", false},
+		{"String", "Nullable(String)", true},
+		{"Nullable(String)", "String", false},
+		{"Nullable(UInt8)", "Nullable(UInt16)", true},
+		{"String", "LowCardinality(String)", true},
+		{"LowCardinality(String)", "String", true},
+		{"Array(UInt8)", "Array(UInt16)", true},
+		{"Array(UInt16)", "Array(UInt8)", false},
+		{"Map(String, UInt8)", "Map(String, UInt64)", true},
+		{"Decimal(10, 2)", "Decimal(12, 4)", true},
+		{"Decimal(10, 2)", "Decimal(10, 4)", false},
+		{"Date", "DateTime", true},
+		{"DateTime", "Date", false},
+		{"DateTime64(3)", "DateTime64(6)", true},
+		{"DateTime64(6)", "DateTime64(3)", false},
+		{"UUID", "FixedString(16)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			require.Equal(t, tt.widens, typeWidens(dataType(tt.from), dataType(tt.to)))
+		})
+	}
+}
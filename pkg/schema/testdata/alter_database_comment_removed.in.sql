@@ -0,0 +1,4 @@
+-- Current state: database with a comment
+CREATE DATABASE analytics ENGINE = Atomic COMMENT 'Analytics database';
+-- Target state: comment removed
+CREATE DATABASE analytics ENGINE = Atomic;
@@ -0,0 +1 @@
+ALTER DATABASE `analytics` MODIFY COMMENT '';
\ No newline at end of file
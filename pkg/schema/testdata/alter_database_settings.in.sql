@@ -0,0 +1,4 @@
+-- Current state: replicated database with default lag settings
+CREATE DATABASE analytics ENGINE = Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 1 COMMENT 'Analytics database';
+-- Target state: tighter broken table ratio and an explicit replication lag
+CREATE DATABASE analytics ENGINE = Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') SETTINGS max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50 COMMENT 'Analytics database';
@@ -0,0 +1 @@
+ALTER DATABASE `analytics` MODIFY SETTING max_broken_tables_ratio = 0.5, max_replication_lag_to_enqueue = 50;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: named collection with an old host and an unused key
+CREATE NAMED COLLECTION pg_conn AS host = 'old-host', port = '5432', schema = 'public';
+-- Target state: new host, same port written as a number, new database key, schema removed
+CREATE NAMED COLLECTION pg_conn AS host = 'new-host', port = 5432, database = 'analytics';
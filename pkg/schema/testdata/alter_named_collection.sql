@@ -0,0 +1,3 @@
+ALTER NAMED COLLECTION `pg_conn`
+    SET `host` = 'new-host', `database` = 'analytics'
+    DELETE `schema`;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: events table with a 30 day TTL and custom merge settings
+CREATE TABLE analytics.events (id UInt64, ts DateTime, payload String) ENGINE = MergeTree() ORDER BY id TTL ts + INTERVAL 30 DAY SETTINGS ttl_only_drop_parts = 1 COMMENT 'Raw events';
+-- Target state: new column, sampling, longer TTL, and updated settings and comment
+CREATE TABLE analytics.events (id UInt64, ts DateTime, payload String, source LowCardinality(String)) ENGINE = MergeTree() ORDER BY id SAMPLE BY id TTL ts + INTERVAL 90 DAY SETTINGS merge_with_ttl_timeout = 3600 COMMENT 'Raw events with source';
@@ -0,0 +1,7 @@
+ALTER TABLE `analytics`.`events`
+    ADD COLUMN `source` LowCardinality(String),
+    MODIFY SAMPLE BY `id`,
+    MODIFY TTL `ts` + INTERVAL 90 DAY,
+    MODIFY SETTING `merge_with_ttl_timeout` = 3600,
+    RESET SETTING `ttl_only_drop_parts`,
+    MODIFY COMMENT 'Raw events with source';
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: table with sampling, TTL, and a custom setting
+CREATE TABLE analytics.sessions (id UInt64, started_at DateTime) ENGINE = MergeTree() ORDER BY id SAMPLE BY id TTL started_at + INTERVAL 7 DAY SETTINGS merge_with_ttl_timeout = 3600;
+-- Target state: sampling and TTL removed, setting changed
+CREATE TABLE analytics.sessions (id UInt64, started_at DateTime) ENGINE = MergeTree() ORDER BY id SETTINGS merge_with_ttl_timeout = 7200;
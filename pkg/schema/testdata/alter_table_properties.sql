@@ -0,0 +1,4 @@
+ALTER TABLE `analytics`.`sessions`
+    REMOVE SAMPLE BY,
+    REMOVE TTL,
+    MODIFY SETTING `merge_with_ttl_timeout` = 7200;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- Current schema: Bool columns as reported by ClickHouse (UInt8 storage) and IP aliases
+CREATE TABLE flags (
+    id UInt64,
+    is_active UInt8,
+    is_deleted Nullable(Bool),
+    client_ip IPv4,
+    server_ip IPv6
+) ENGINE = MergeTree() ORDER BY id;
+
+-- Target state: same columns declared with their aliases - should not generate any diff
+CREATE TABLE flags (
+    id UInt64,
+    is_active Bool,
+    is_deleted Nullable(UInt8),
+    client_ip INET4,
+    server_ip INET6
+) ENGINE = MergeTree() ORDER BY id;
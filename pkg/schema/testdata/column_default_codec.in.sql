@@ -0,0 +1,4 @@
+-- Current state: dumped table reporting the default LZ4 codec on columns declared without one
+CREATE TABLE analytics.events (`id` UInt64 CODEC(LZ4), `name` String CODEC(LZ4), `payload` String CODEC(ZSTD(3))) ENGINE = MergeTree() ORDER BY id;
+-- Target state: only payload declares a codec; name now uses ZSTD
+CREATE TABLE analytics.events (id UInt64, name String CODEC(ZSTD(1)), payload String CODEC(ZSTD(3))) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events`
+    MODIFY COLUMN `name` String CODEC(ZSTD(1));
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: total is materialized from amount
+CREATE TABLE analytics.orders (id UInt64, amount Decimal(18, 2), total Decimal(18, 2) MATERIALIZED amount * 2) ENGINE = MergeTree() ORDER BY id;
+-- Target state: amount is replaced by price and the dependent expression also changes (renamed with DetectColumnRenames)
+CREATE TABLE analytics.orders (id UInt64, price Decimal(18, 2), total Decimal(18, 2) MATERIALIZED price * 3) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,4 @@
+ALTER TABLE `analytics`.`orders`
+    ADD COLUMN `price` Decimal(18, 2) AFTER `id`,
+    MODIFY COLUMN `total` Decimal(18, 2) MATERIALIZED `price` * 3,
+    DROP COLUMN `amount`;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE `analytics`.`orders`
+    ADD COLUMN `price` Decimal(18, 2) AFTER `id`,
+    MODIFY COLUMN `total` Decimal(18, 2) MATERIALIZED `price` * 2,
+    MODIFY COLUMN `label` String ALIAS concat('order-', toString(`price`)),
+    DROP COLUMN `amount`;
\ No newline at end of file
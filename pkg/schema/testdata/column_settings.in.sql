@@ -0,0 +1,10 @@
+-- Current state: payload column with per-column compression settings
+CREATE TABLE analytics.blobs (
+    id UInt64,
+    payload String SETTINGS (max_compress_block_size = 1048576, min_compress_block_size = 65536)
+) ENGINE = MergeTree() ORDER BY id;
+-- Target state: max_compress_block_size raised for the payload column
+CREATE TABLE analytics.blobs (
+    id UInt64,
+    payload String SETTINGS (min_compress_block_size = 65536, max_compress_block_size = 2097152)
+) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`blobs`
+    MODIFY COLUMN `payload` String SETTINGS (min_compress_block_size = 65536, max_compress_block_size = 2097152);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: comments written before CODEC and before SETTINGS
+CREATE TABLE analytics.sessions (id UInt64 COMMENT 'Session ID' CODEC(Delta, ZSTD(1))) ENGINE = MergeTree() ORDER BY id COMMENT 'User sessions' SETTINGS index_granularity = 8192;
+-- Target state: both comments changed
+CREATE TABLE analytics.sessions (id UInt64 CODEC(Delta, ZSTD(1)) COMMENT 'Session identifier') ENGINE = MergeTree() ORDER BY id SETTINGS index_granularity = 8192 COMMENT 'Login sessions';
@@ -0,0 +1,3 @@
+ALTER TABLE `analytics`.`sessions`
+    COMMENT COLUMN `id` 'Session identifier',
+    MODIFY COMMENT 'Login sessions';
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: comments written before CODEC and before SETTINGS
+CREATE TABLE analytics.sessions (id UInt64 COMMENT 'Session ID' CODEC(Delta, ZSTD(1)), token String DEFAULT '' COMMENT 'Opaque token' CODEC(ZSTD(3))) ENGINE = MergeTree() ORDER BY id COMMENT 'User sessions' SETTINGS index_granularity = 8192;
+-- Target state: the same comments as dumped by ClickHouse
+CREATE TABLE analytics.sessions (id UInt64 CODEC(Delta, ZSTD(1)) COMMENT 'Session ID', token String DEFAULT '' CODEC(ZSTD(3)) COMMENT 'Opaque token') ENGINE = MergeTree() ORDER BY id SETTINGS index_granularity = 8192 COMMENT 'User sessions';
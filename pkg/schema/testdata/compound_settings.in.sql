@@ -0,0 +1,13 @@
+-- Current schema: array and map settings as dumped by ClickHouse
+CREATE TABLE filtered (
+    id UInt64,
+    tenant String
+) ENGINE = MergeTree() ORDER BY id
+SETTINGS index_granularity = 8192, allowed_disks = ['hot', 'cold'], additional_table_filters = {'filtered': 'tenant = 1'};
+
+-- Target state: same settings with different whitespace - should not generate any diff
+CREATE TABLE filtered (
+    id UInt64,
+    tenant String
+) ENGINE = MergeTree() ORDER BY id
+SETTINGS index_granularity = 8192, allowed_disks = [ 'hot','cold' ], additional_table_filters = { 'filtered' : 'tenant = 1' };
@@ -0,0 +1,4 @@
+-- Current state: no named collections
+;
+-- Target state: named collection with an overridable host and a locked password
+CREATE NAMED COLLECTION pg_conn AS host = 'localhost', port = 5432, password = 'secret' NOT OVERRIDABLE;
@@ -0,0 +1,4 @@
+CREATE NAMED COLLECTION `pg_conn` AS
+    `host` = 'localhost',
+    `port` = 5432,
+    `password` = 'secret' NOT OVERRIDABLE;
\ No newline at end of file
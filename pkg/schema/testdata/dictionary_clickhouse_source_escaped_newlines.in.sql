@@ -0,0 +1,14 @@
+-- Current state: multi-line ClickHouse source query, as dumped by the server with escaped line breaks
+CREATE DICTIONARY analytics.active_users (id UInt64, name String) PRIMARY KEY id SOURCE(CLICKHOUSE(query 'SELECT id, name\nFROM analytics.users\nWHERE status = \'active\'\n\tAND name != \'\'' user 'default')) LAYOUT(HASHED()) LIFETIME(300);
+-- Target state: same query, declared with real line breaks
+CREATE DICTIONARY analytics.active_users (id UInt64, name String)
+PRIMARY KEY id
+SOURCE(CLICKHOUSE(
+    query 'SELECT id, name
+FROM analytics.users
+WHERE status = \'active\'
+    AND name != \'\''
+    user 'default'
+))
+LAYOUT(HASHED())
+LIFETIME(300);
@@ -0,0 +1,4 @@
+-- Current state: dictionary sourced from a ClickHouse query
+CREATE DICTIONARY analytics.active_users (id UInt64, name String) PRIMARY KEY id SOURCE(CLICKHOUSE(query 'SELECT id, name FROM analytics.users WHERE status = \'active\'')) LAYOUT(HASHED()) LIFETIME(300);
+-- Target state: query filters on a different status
+CREATE DICTIONARY analytics.active_users (id UInt64, name String) PRIMARY KEY id SOURCE(CLICKHOUSE(query 'SELECT id, name FROM analytics.users WHERE status = \'enabled\'')) LAYOUT(HASHED()) LIFETIME(300);
@@ -0,0 +1,8 @@
+CREATE OR REPLACE DICTIONARY `analytics`.`active_users` (
+    `id`   UInt64,
+    `name` String
+)
+PRIMARY KEY `id`
+SOURCE(CLICKHOUSE(query 'SELECT id, name FROM analytics.users WHERE status = \'enabled\''))
+LAYOUT(HASHED())
+LIFETIME(300);
\ No newline at end of file
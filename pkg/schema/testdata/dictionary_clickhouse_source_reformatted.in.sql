@@ -0,0 +1,15 @@
+-- Current state: dictionary sourced from a ClickHouse query, as dumped by the server
+CREATE DICTIONARY analytics.active_users (id UInt64, name String) PRIMARY KEY id SOURCE(CLICKHOUSE(query 'SELECT id, name FROM analytics.users WHERE status = \'active\'' user 'default')) LAYOUT(HASHED()) LIFETIME(300);
+-- Target state: same query, declared with different formatting
+CREATE DICTIONARY analytics.active_users (id UInt64, name String)
+PRIMARY KEY id
+SOURCE(CLICKHOUSE(
+    user 'default'
+    query '
+        select id,   name
+        from analytics.users
+        where status = \'active\'
+    '
+))
+LAYOUT(HASHED())
+LIFETIME(300);
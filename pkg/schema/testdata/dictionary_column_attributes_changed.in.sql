@@ -0,0 +1,4 @@
+-- Current state: hierarchical dictionary
+CREATE DICTIONARY analytics.regions (id UInt64 IS_OBJECT_ID, parent_id UInt64 DEFAULT 0 HIERARCHICAL, code String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);
+-- Target state: hierarchy becomes bidirectional and code becomes injective
+CREATE DICTIONARY analytics.regions (id UInt64 IS_OBJECT_ID, parent_id UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL, code String INJECTIVE) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);
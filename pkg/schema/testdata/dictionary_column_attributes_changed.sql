@@ -0,0 +1,9 @@
+CREATE OR REPLACE DICTIONARY `analytics`.`regions` (
+    `id`        UInt64 IS_OBJECT_ID,
+    `parent_id` UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL,
+    `code`      String INJECTIVE
+)
+PRIMARY KEY `id`
+SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow'))
+LAYOUT(HASHED())
+LIFETIME(3600);
\ No newline at end of file
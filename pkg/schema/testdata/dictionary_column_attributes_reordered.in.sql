@@ -0,0 +1,4 @@
+-- Current state: dictionary as returned by ClickHouse, with attributes in canonical order
+CREATE DICTIONARY `analytics`.`regions` (`id` UInt64 IS_OBJECT_ID, `parent_id` UInt64 DEFAULT 0 HIERARCHICAL BIDIRECTIONAL, `code` String INJECTIVE, `label` String EXPRESSION upper(code) INJECTIVE IS_OBJECT_ID) PRIMARY KEY `id` SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);
+-- Target state: same attributes written in a different order
+CREATE DICTIONARY analytics.regions (id UInt64 IS_OBJECT_ID, parent_id UInt64 DEFAULT 0 BIDIRECTIONAL HIERARCHICAL, code String INJECTIVE, label String EXPRESSION upper(code) IS_OBJECT_ID INJECTIVE) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/regions.json' format 'JSONEachRow')) LAYOUT(HASHED()) LIFETIME(3600);
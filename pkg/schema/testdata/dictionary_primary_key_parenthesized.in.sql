@@ -0,0 +1,6 @@
+-- Current state: dictionaries as dumped by the server, with parenthesized primary keys
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY (id) SOURCE(HTTP(url 'http://api.com/users')) LAYOUT(HASHED()) LIFETIME(3600);
+CREATE DICTIONARY analytics.user_mapping (user_id UInt64, group_id UInt32, name String) PRIMARY KEY (user_id, group_id) SOURCE(HTTP(url 'http://api.com/mapping')) LAYOUT(COMPLEX_KEY_HASHED()) LIFETIME(3600);
+-- Target state: same keys declared without parentheses
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY `id` SOURCE(HTTP(url 'http://api.com/users')) LAYOUT(HASHED()) LIFETIME(3600);
+CREATE DICTIONARY analytics.user_mapping (user_id UInt64, group_id UInt32, name String) PRIMARY KEY user_id, group_id SOURCE(HTTP(url 'http://api.com/mapping')) LAYOUT(COMPLEX_KEY_HASHED()) LIFETIME(3600);
@@ -0,0 +1,4 @@
+-- Current state: dictionary with a composite primary key
+CREATE DICTIONARY analytics.user_mapping (user_id UInt64, group_id UInt32, name String) PRIMARY KEY (user_id, group_id) SOURCE(HTTP(url 'http://api.com/mapping')) LAYOUT(COMPLEX_KEY_HASHED()) LIFETIME(3600);
+-- Target state: composite key order changes, which requires replacing the dictionary
+CREATE DICTIONARY analytics.user_mapping (user_id UInt64, group_id UInt32, name String) PRIMARY KEY group_id, user_id SOURCE(HTTP(url 'http://api.com/mapping')) LAYOUT(COMPLEX_KEY_HASHED()) LIFETIME(3600);
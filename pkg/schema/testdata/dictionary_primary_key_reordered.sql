@@ -0,0 +1,9 @@
+CREATE OR REPLACE DICTIONARY `analytics`.`user_mapping` (
+    `user_id`  UInt64,
+    `group_id` UInt32,
+    `name`     String
+)
+PRIMARY KEY `group_id`, `user_id`
+SOURCE(HTTP(url 'http://api.com/mapping'))
+LAYOUT(COMPLEX_KEY_HASHED())
+LIFETIME(3600);
\ No newline at end of file
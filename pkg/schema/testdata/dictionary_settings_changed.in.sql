@@ -0,0 +1,4 @@
+-- Current state: dictionary with settings
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://api.com/users')) LAYOUT(HASHED()) LIFETIME(3600) SETTINGS(max_threads = 4, format_csv_allow_single_quotes = 0);
+-- Target state: a setting value changes, settings are emitted in sorted order
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://api.com/users')) LAYOUT(HASHED()) LIFETIME(3600) SETTINGS(max_threads = 8, format_csv_allow_single_quotes = 0);
@@ -0,0 +1,9 @@
+CREATE OR REPLACE DICTIONARY `analytics`.`users_dict` (
+    `id`   UInt64,
+    `name` String
+)
+PRIMARY KEY `id`
+SOURCE(HTTP(url 'http://api.com/users'))
+LAYOUT(HASHED())
+LIFETIME(3600)
+SETTINGS(format_csv_allow_single_quotes = 0, max_threads = 8);
\ No newline at end of file
@@ -0,0 +1 @@
+ErrNoDiff: no differences found
\ No newline at end of file
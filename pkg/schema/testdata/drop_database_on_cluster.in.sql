@@ -0,0 +1,4 @@
+-- Current state: replicated database exists on the cluster
+CREATE DATABASE analytics ON CLUSTER production ENGINE = Replicated('/clickhouse/databases/analytics', '{shard}', '{replica}') COMMENT 'Analytics database';
+-- Target state: database should be removed
+-- empty target state
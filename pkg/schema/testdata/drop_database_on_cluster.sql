@@ -0,0 +1 @@
+DROP DATABASE IF EXISTS `analytics` ON CLUSTER `production`;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Current state: two named collections
+CREATE NAMED COLLECTION kafka_conn AS kafka_broker_list = 'kafka:9092';
+CREATE NAMED COLLECTION pg_conn AS host = 'localhost', port = 5432;
+-- Target state: only one named collection remains
+CREATE NAMED COLLECTION pg_conn AS host = 'localhost', port = 5432;
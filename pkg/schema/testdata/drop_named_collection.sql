@@ -0,0 +1 @@
+DROP NAMED COLLECTION IF EXISTS `kafka_conn`;
\ No newline at end of file
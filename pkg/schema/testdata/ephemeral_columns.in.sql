@@ -0,0 +1,6 @@
+-- Current state: table with EPHEMERAL columns as returned by ClickHouse
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.hex_values (id UInt64, unhexed String EPHEMERAL, flag UInt8 EPHEMERAL 0, hexed FixedString(4) DEFAULT unhex(unhexed)) ENGINE = MergeTree() ORDER BY id;
+-- Target state: changed default, default added to a bare EPHEMERAL, and a new EPHEMERAL column
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.hex_values (id UInt64, unhexed String EPHEMERAL '00000000', flag UInt8 EPHEMERAL 1, raw String EPHEMERAL, hexed FixedString(4) DEFAULT unhex(unhexed)) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,4 @@
+ALTER TABLE `analytics`.`hex_values`
+    MODIFY COLUMN `unhexed` String EPHEMERAL '00000000',
+    MODIFY COLUMN `flag` UInt8 EPHEMERAL 1,
+    ADD COLUMN `raw` String EPHEMERAL AFTER `flag`;
\ No newline at end of file
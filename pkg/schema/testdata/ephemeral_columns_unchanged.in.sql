@@ -0,0 +1,11 @@
+-- Current state: table with EPHEMERAL columns as returned by ClickHouse
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.hex_values (id UInt64, unhexed String EPHEMERAL, flag UInt8 EPHEMERAL 0, hexed FixedString(4) DEFAULT unhex(unhexed)) ENGINE = MergeTree() ORDER BY id;
+-- Target state: same table with quoted identifiers and different layout
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE `analytics`.`hex_values` (
+    `id` UInt64,
+    `unhexed` String EPHEMERAL,
+    `flag` UInt8 EPHEMERAL 0,
+    `hexed` FixedString(4) DEFAULT unhex(`unhexed`)
+) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,4 @@
+-- Current state: table without sampling
+CREATE TABLE analytics.hits (user_id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY ts;
+-- Target state: sampling by an expression that isn't part of the primary key
+CREATE TABLE analytics.hits (user_id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY ts SAMPLE BY intHash32(user_id);
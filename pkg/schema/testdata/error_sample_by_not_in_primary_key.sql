@@ -0,0 +1 @@
+ErrUnsupported: failed to compare tables: SAMPLE BY intHash32(user_id) of table analytics.hits must be part of the primary key (ts): invalid SAMPLE BY expression: unsupported operation
\ No newline at end of file
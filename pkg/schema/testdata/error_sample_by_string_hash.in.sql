@@ -0,0 +1,4 @@
+-- Current state: no table
+;
+-- Target state: sampling by a hash that returns a FixedString
+CREATE TABLE analytics.hits (user_id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, MD5(toString(user_id))) SAMPLE BY MD5(toString(user_id));
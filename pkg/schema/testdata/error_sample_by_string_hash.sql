@@ -0,0 +1 @@
+ErrUnsupported: failed to compare tables: SAMPLE BY MD5(toString(user_id)) of table analytics.hits must return an unsigned integer, but MD5 returns FixedString(16): invalid SAMPLE BY expression: unsupported operation
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: identifiers stored as strings of varying widths
+CREATE TABLE analytics.sessions (id FixedString(36), token String, code FixedString(8), label String) ENGINE = MergeTree() ORDER BY id;
+-- Target state: widths changed and columns moved between String and FixedString
+CREATE TABLE analytics.sessions (id FixedString(36), token FixedString(64), code FixedString(9), label String) ENGINE = MergeTree() ORDER BY id;
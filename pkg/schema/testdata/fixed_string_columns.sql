@@ -0,0 +1,3 @@
+ALTER TABLE `analytics`.`sessions`
+    MODIFY COLUMN `token` FixedString(64),
+    MODIFY COLUMN `code` FixedString(9);
\ No newline at end of file
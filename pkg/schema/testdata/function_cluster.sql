@@ -1 +1 @@
-CREATE FUNCTION `local_function` ON CLUSTER `production` AS (`x`) -> multiply(`x`, 2);
\ No newline at end of file
+CREATE OR REPLACE FUNCTION `local_function` ON CLUSTER `production` AS (`x`) -> multiply(`x`, 2);
\ No newline at end of file
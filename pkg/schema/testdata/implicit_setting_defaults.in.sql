@@ -0,0 +1,12 @@
+-- Current schema: dumped by ClickHouse with the implicit index_granularity default
+CREATE TABLE events (
+    id UInt64,
+    name String
+) ENGINE = ReplacingMergeTree() ORDER BY id
+SETTINGS index_granularity = 8192;
+
+-- Target state: index_granularity omitted - should not generate any diff
+CREATE TABLE events (
+    id UInt64,
+    name String
+) ENGINE = ReplacingMergeTree() ORDER BY id;
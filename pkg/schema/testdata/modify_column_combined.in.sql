@@ -0,0 +1,4 @@
+-- Current state
+CREATE TABLE analytics.events (id UInt64, status UInt8 DEFAULT 0, payload String CODEC(LZ4), score Float32 DEFAULT 0 COMMENT 'Score') ENGINE = MergeTree() ORDER BY id;
+-- Target state: each column changes its type together with its default, codec, or comment
+CREATE TABLE analytics.events (id UInt64, status UInt16 DEFAULT 1, payload LowCardinality(String) CODEC(ZSTD(3)), score Float64 DEFAULT 1.5 COMMENT 'Weighted score') ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,4 @@
+ALTER TABLE `analytics`.`events`
+    MODIFY COLUMN `status` UInt16 DEFAULT 1,
+    MODIFY COLUMN `payload` LowCardinality(String) CODEC(ZSTD(3)),
+    MODIFY COLUMN `score` Float64 DEFAULT 1.5 COMMENT 'Weighted score';
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Current state: tables sorted by their leading columns
+CREATE TABLE analytics.events (id UInt64, ts DateTime, user_id UInt64) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.sessions (id UInt64, started_at DateTime, user_id UInt64) ENGINE = MergeTree() ORDER BY (user_id, id);
+CREATE TABLE analytics.visits (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (id, ts);
+-- Target state: sorting keys extended with existing and new columns, and one reordered
+CREATE TABLE analytics.events (id UInt64, ts DateTime, user_id UInt64, source String) ENGINE = MergeTree() ORDER BY (id, source);
+CREATE TABLE analytics.sessions (id UInt64, started_at DateTime, user_id UInt64) ENGINE = MergeTree() ORDER BY (user_id, id, started_at);
+CREATE TABLE analytics.visits (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, id);
@@ -0,0 +1,22 @@
+ALTER TABLE `analytics`.`events`
+    ADD COLUMN `source` String,
+    MODIFY ORDER BY (`id`, `source`);
+
+DROP TABLE `analytics`.`sessions`;
+
+CREATE TABLE `analytics`.`sessions` (
+    `id`         UInt64,
+    `started_at` DateTime,
+    `user_id`    UInt64
+)
+ENGINE = MergeTree()
+ORDER BY (`user_id`, `id`, `started_at`);
+
+DROP TABLE `analytics`.`visits`;
+
+CREATE TABLE `analytics`.`visits` (
+    `id` UInt64,
+    `ts` DateTime
+)
+ENGINE = MergeTree()
+ORDER BY (`ts`, `id`);
\ No newline at end of file
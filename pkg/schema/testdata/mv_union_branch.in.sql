@@ -0,0 +1,6 @@
+-- Current state: materialized view combining web and mobile events
+CREATE TABLE analytics.events (id UInt64, ts DateTime, source String) ENGINE = MergeTree() ORDER BY (ts, id);
+CREATE MATERIALIZED VIEW analytics.mv_all_events TO analytics.events AS SELECT id, ts, 'web' AS source FROM analytics.web_events UNION ALL SELECT id, ts, 'mobile' AS source FROM analytics.mobile_events;
+-- Target state: only the second union branch filters its rows
+CREATE TABLE analytics.events (id UInt64, ts DateTime, source String) ENGINE = MergeTree() ORDER BY (ts, id);
+CREATE MATERIALIZED VIEW analytics.mv_all_events TO analytics.events AS SELECT id, ts, 'web' AS source FROM analytics.web_events UNION ALL SELECT id, ts, 'mobile' AS source FROM analytics.mobile_events WHERE id > 0;
@@ -0,0 +1,16 @@
+DROP TABLE `analytics`.`mv_all_events`;
+
+CREATE MATERIALIZED VIEW `analytics`.`mv_all_events`
+TO `analytics`.`events`
+AS SELECT
+    `id`,
+    `ts`,
+    'web' AS `source`
+FROM `analytics`.`web_events`
+UNION ALL
+SELECT
+    `id`,
+    `ts`,
+    'mobile' AS `source`
+FROM `analytics`.`mobile_events`
+WHERE `id` > 0;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: named collection dumped from ClickHouse with its password masked
+CREATE NAMED COLLECTION s3_conn AS url = 'https://bucket.s3.amazonaws.com/', secret_access_key = '[HIDDEN]';
+-- Target state: the masked value can't be compared, so only the url change is applied
+CREATE NAMED COLLECTION s3_conn AS url = 'https://other.s3.amazonaws.com/', secret_access_key = 'real-secret';
@@ -0,0 +1,2 @@
+ALTER NAMED COLLECTION `s3_conn`
+    SET `url` = 'https://other.s3.amazonaws.com/';
\ No newline at end of file
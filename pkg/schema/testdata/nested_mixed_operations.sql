@@ -1,4 +1,4 @@
 ALTER TABLE `events`
-    ADD COLUMN `created_at` DateTime DEFAULT now(),
+    ADD COLUMN `created_at` DateTime DEFAULT now() AFTER `id`,
     ADD COLUMN `metadata.version` Array(UInt16),
     ADD COLUMN `metadata.tags` Array(Array(String));
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: placeholder column that can only be NULL
+CREATE TABLE analytics.placeholders (id UInt64, value Nullable(Nothing), tags Array(Nothing)) ENGINE = MergeTree() ORDER BY id;
+-- Target state: placeholder columns given concrete types
+CREATE TABLE analytics.placeholders (id UInt64, value Nullable(String), tags Array(String)) ENGINE = MergeTree() ORDER BY id;
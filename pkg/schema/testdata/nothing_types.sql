@@ -0,0 +1,3 @@
+ALTER TABLE `analytics`.`placeholders`
+    MODIFY COLUMN `value` Nullable(String),
+    MODIFY COLUMN `tags` Array(String);
\ No newline at end of file
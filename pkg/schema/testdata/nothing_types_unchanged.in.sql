@@ -0,0 +1,9 @@
+-- Current state: columns using the Nothing type as returned by ClickHouse
+CREATE TABLE analytics.placeholders (`id` UInt64, `always_null` Nullable(Nothing), `empty_list` Array(Nothing), `null_list` Array(Nullable(Nothing))) ENGINE = MergeTree() ORDER BY id;
+-- Target state: same table written without backticks
+CREATE TABLE analytics.placeholders (
+    id UInt64,
+    always_null Nullable(Nothing),
+    empty_list Array(Nothing),
+    null_list Array(Nullable(Nothing))
+) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,17 @@
+-- Current schema: columns as dumped by ClickHouse with their inferred types
+CREATE TABLE events (
+    id UInt64,
+    created_at DateTime DEFAULT now(),
+    event_date Date DEFAULT today(),
+    source String DEFAULT 'web',
+    attempts UInt8 DEFAULT 0
+) ENGINE = MergeTree() ORDER BY id;
+
+-- Target state: same columns declared without types - should not generate any diff
+CREATE TABLE events (
+    id UInt64,
+    created_at DEFAULT now(),
+    event_date DEFAULT today(),
+    source DEFAULT 'web',
+    attempts DEFAULT 0
+) ENGINE = MergeTree() ORDER BY id;
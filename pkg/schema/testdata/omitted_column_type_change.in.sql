@@ -0,0 +1,11 @@
+-- Current schema: column dumped with an explicit Date type
+CREATE TABLE events (
+    id UInt64,
+    created_at Date DEFAULT now()
+) ENGINE = MergeTree() ORDER BY id;
+
+-- Target state: type omitted, inferred as DateTime - should generate MODIFY COLUMN
+CREATE TABLE events (
+    id UInt64,
+    created_at DEFAULT now()
+) ENGINE = MergeTree() ORDER BY id;
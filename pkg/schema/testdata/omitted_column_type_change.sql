@@ -0,0 +1,2 @@
+ALTER TABLE `events`
+    MODIFY COLUMN `created_at` DateTime DEFAULT now();
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE DATABASE analytics ENGINE = Atomic;
+
+CREATE TABLE analytics.events (
+    `date` Date,
+    `id` UInt64,
+    `user_id` UInt64
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(`date`)
+ORDER BY (toYYYYMM(`date`), `id`, intHash32(`user_id`))
+SAMPLE BY intHash32(`user_id`)
+PRIMARY KEY (toYYYYMM(`date`), `id`);
+
+-- Target state:
+CREATE DATABASE analytics ENGINE = Atomic;
+
+CREATE TABLE analytics.events (
+    date Date,
+    id UInt64,
+    user_id UInt64
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM( date )
+ORDER BY ( toYYYYMM(date),id, intHash32( user_id ) )
+SAMPLE BY intHash32(user_id)
+PRIMARY KEY (toYYYYMM(date), id);
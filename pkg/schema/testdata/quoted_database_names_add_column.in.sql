@@ -0,0 +1,6 @@
+-- Current state: table as returned by ClickHouse, with quoted database and table names
+CREATE DATABASE `analytics` ENGINE = Atomic;
+CREATE TABLE `analytics`.`events` (`id` UInt64, `ts` DateTime) ENGINE = MergeTree() ORDER BY `id`;
+-- Target state: the same table written unquoted, with an added column
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.events (id UInt64, ts DateTime, user_id UInt64) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events`
+    ADD COLUMN `user_id` UInt64;
\ No newline at end of file
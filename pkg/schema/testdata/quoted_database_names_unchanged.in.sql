@@ -0,0 +1,16 @@
+-- Current state: objects as returned by ClickHouse, with quoted database and object names
+CREATE DATABASE `analytics` ENGINE = Atomic;
+CREATE TABLE `analytics`.`events` (`id` UInt64, `ts` DateTime) ENGINE = MergeTree() ORDER BY `id`;
+CREATE TABLE `analytics`.`events_all` AS `analytics`.`events` ENGINE = Distributed('default', 'analytics', 'events', rand());
+CREATE TABLE `analytics`.`daily_totals` (`date` Date, `total` UInt64) ENGINE = SummingMergeTree() ORDER BY `date`;
+CREATE DICTIONARY `analytics`.`users_dict` (`id` UInt64, `name` String) PRIMARY KEY `id` SOURCE(CLICKHOUSE(db 'analytics' table 'users')) LAYOUT(HASHED()) LIFETIME(3600);
+CREATE MATERIALIZED VIEW `analytics`.`mv_daily_totals` TO `analytics`.`daily_totals` AS SELECT toDate(`ts`) AS `date`, count() AS `total` FROM `analytics`.`events` GROUP BY `date`;
+CREATE VIEW `analytics`.`recent_events` AS SELECT `id`, `ts` FROM `analytics`.`events` WHERE `ts` > now() - INTERVAL 1 DAY;
+-- Target state: the same objects written with a mix of unquoted and quoted names
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE TABLE analytics.events (id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE `analytics`.events_all AS analytics.`events` ENGINE = Distributed('default', 'analytics', 'events', rand());
+CREATE TABLE analytics.`daily_totals` (date Date, total UInt64) ENGINE = SummingMergeTree() ORDER BY date;
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(CLICKHOUSE(db 'analytics' table 'users')) LAYOUT(HASHED()) LIFETIME(3600);
+CREATE MATERIALIZED VIEW analytics.mv_daily_totals TO analytics.daily_totals AS SELECT toDate(ts) AS date, count() AS total FROM analytics.events GROUP BY date;
+CREATE VIEW `analytics`.recent_events AS SELECT id, ts FROM analytics.events WHERE ts > now() - INTERVAL 1 DAY;
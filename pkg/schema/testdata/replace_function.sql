@@ -1,3 +1,3 @@
-CREATE FUNCTION `calculate_tax` AS (`amount`) -> multiply(`amount`, 0.10);
+CREATE OR REPLACE FUNCTION `calculate_tax` AS (`amount`) -> multiply(`amount`, 0.10);
 
-CREATE FUNCTION `format_currency` AS (`value`, `currency`) -> concat(`currency`, ' ', toString(`value`));
\ No newline at end of file
+CREATE OR REPLACE FUNCTION `format_currency` AS (`value`, `currency`) -> concat(`currency`, ' ', toString(`value`));
\ No newline at end of file
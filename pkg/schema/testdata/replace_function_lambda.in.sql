@@ -0,0 +1,8 @@
+-- Current state: multi-argument lambdas
+CREATE FUNCTION linear_eq AS (x, k, b) -> k*x + b;
+CREATE FUNCTION clamp ON CLUSTER production AS (value, low, high) -> greatest(low, least(value, high));
+CREATE FUNCTION weighted_avg AS (a, b, w) -> a * w + b * (1 - w);
+-- Target state: linear_eq changes its body, clamp is reformatted, weighted_avg gains a parameter
+CREATE FUNCTION linear_eq AS (x, k, b) -> k * x - b;
+CREATE FUNCTION clamp ON CLUSTER production AS (value, low, high) -> greatest(low,least(value,high));
+CREATE FUNCTION weighted_avg AS (a, b, w, scale) -> (a * w + b * (1 - w)) * scale;
@@ -0,0 +1,3 @@
+CREATE OR REPLACE FUNCTION `linear_eq` AS (`x`, `k`, `b`) -> `k` * `x` - `b`;
+
+CREATE OR REPLACE FUNCTION `weighted_avg` AS (`a`, `b`, `w`, `scale`) -> (`a` * `w` + `b` * (1 - `w`)) * `scale`;
\ No newline at end of file
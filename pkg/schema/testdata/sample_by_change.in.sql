@@ -0,0 +1,6 @@
+-- Current state: sampling by a 32-bit hash of the user
+CREATE TABLE analytics.hits (user_id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, intHash32(user_id), cityHash64(user_id)) SAMPLE BY intHash32(user_id);
+CREATE TABLE analytics.visits (visitor UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, visitor) SAMPLE BY visitor;
+-- Target state: sampling by another hash in the primary key, and removing sampling
+CREATE TABLE analytics.hits (user_id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, intHash32(user_id), cityHash64(user_id)) SAMPLE BY cityHash64(user_id);
+CREATE TABLE analytics.visits (visitor UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, visitor);
@@ -0,0 +1,5 @@
+ALTER TABLE `analytics`.`hits`
+    MODIFY SAMPLE BY cityHash64(`user_id`);
+
+ALTER TABLE `analytics`.`visits`
+    REMOVE SAMPLE BY;
\ No newline at end of file
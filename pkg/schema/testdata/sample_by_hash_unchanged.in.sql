@@ -0,0 +1,6 @@
+-- Current state: sampling expressions as returned by ClickHouse
+CREATE TABLE analytics.hits (`user_id` UInt64, `ts` DateTime) ENGINE = MergeTree() ORDER BY (`ts`, intHash32(`user_id`)) SAMPLE BY intHash32(`user_id`);
+CREATE TABLE analytics.visits (`visitor` String, `ts` DateTime) ENGINE = MergeTree() ORDER BY (cityHash64(`visitor`, `ts`), `ts`) SAMPLE BY cityHash64(`visitor`, `ts`);
+-- Target state: the same expressions with different quoting, spacing and parentheses
+CREATE TABLE analytics.hits (user_id UInt64, ts DateTime) ENGINE = MergeTree() ORDER BY (ts, intHash32( user_id )) SAMPLE BY (intHash32(user_id));
+CREATE TABLE analytics.visits (visitor String, ts DateTime) ENGINE = MergeTree() ORDER BY (cityHash64(visitor,ts), ts) SAMPLE BY cityHash64(visitor, ts);
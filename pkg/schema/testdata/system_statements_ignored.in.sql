@@ -0,0 +1,8 @@
+-- Current state: dictionary as returned by ClickHouse
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/users')) LAYOUT(HASHED()) LIFETIME(300);
+-- Target state: schema file that also reloads the dictionary
+CREATE DATABASE analytics ENGINE = Atomic;
+CREATE DICTIONARY analytics.users_dict (id UInt64, name String) PRIMARY KEY id SOURCE(HTTP(url 'http://localhost/users')) LAYOUT(HASHED()) LIFETIME(300);
+SYSTEM RELOAD DICTIONARY analytics.users_dict;
+SYSTEM FLUSH LOGS;
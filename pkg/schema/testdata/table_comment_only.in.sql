@@ -0,0 +1,10 @@
+CREATE TABLE analytics.events (
+    id UInt64,
+    name String
+) ENGINE = MergeTree() ORDER BY id COMMENT 'Raw events';
+
+-- Target state:
+CREATE TABLE analytics.events (
+    id UInt64,
+    name String
+) ENGINE = MergeTree() ORDER BY id COMMENT 'Raw event stream';
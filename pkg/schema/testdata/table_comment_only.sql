@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events`
+    MODIFY COMMENT 'Raw event stream';
\ No newline at end of file
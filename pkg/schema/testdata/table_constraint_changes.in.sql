@@ -0,0 +1,6 @@
+-- Current state: tables with CHECK constraints
+CREATE TABLE analytics.users (id UInt64, age UInt8, email String, CONSTRAINT valid_age CHECK age BETWEEN 13 AND 120, CONSTRAINT valid_email CHECK email LIKE '%@%') ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.orders (id UInt64, amount Decimal(18, 2)) ENGINE = MergeTree() ORDER BY id;
+-- Target state: constraint modified, constraint dropped, and constraint added on a new column
+CREATE TABLE analytics.users (id UInt64, age UInt8, email String, CONSTRAINT valid_age CHECK age BETWEEN 18 AND 120) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.orders (id UInt64, amount Decimal(18, 2), quantity UInt32, CONSTRAINT positive_amount CHECK amount > 0, CONSTRAINT positive_quantity CHECK quantity > 0) ENGINE = MergeTree() ORDER BY id;
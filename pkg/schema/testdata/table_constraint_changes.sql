@@ -0,0 +1,9 @@
+ALTER TABLE `analytics`.`orders`
+    ADD COLUMN `quantity` UInt32,
+    ADD CONSTRAINT `positive_amount` CHECK `amount` > 0,
+    ADD CONSTRAINT `positive_quantity` CHECK `quantity` > 0;
+
+ALTER TABLE `analytics`.`users`
+    DROP CONSTRAINT `valid_age`,
+    DROP CONSTRAINT `valid_email`,
+    ADD CONSTRAINT `valid_age` CHECK `age` BETWEEN 18 AND 120;
\ No newline at end of file
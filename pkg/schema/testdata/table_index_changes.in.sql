@@ -0,0 +1,6 @@
+-- Current state: tables with data skipping indexes
+CREATE TABLE analytics.search_logs (id UInt64, query String, user_id UInt64, category String, INDEX query_bloom query TYPE bloom_filter GRANULARITY 1, INDEX category_set category TYPE set(100) GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.events (id UInt64, ts DateTime, legacy String, INDEX legacy_bloom legacy TYPE bloom_filter GRANULARITY 4) ENGINE = MergeTree() ORDER BY id;
+-- Target state: index added, index type changed, index dropped, and indexes following column changes
+CREATE TABLE analytics.search_logs (id UInt64, query String, user_id UInt64, category String, INDEX query_bloom query TYPE tokenbf_v1(512, 3, 0) GRANULARITY 1, INDEX category_set category TYPE set(100) GRANULARITY 1, INDEX user_minmax user_id TYPE minmax GRANULARITY 2) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.events (id UInt64, ts DateTime, session_id UUID, INDEX session_bloom session_id TYPE bloom_filter GRANULARITY 4) ENGINE = MergeTree() ORDER BY id;
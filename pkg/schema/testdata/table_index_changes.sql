@@ -0,0 +1,10 @@
+ALTER TABLE `analytics`.`events`
+    DROP INDEX `legacy_bloom`,
+    ADD COLUMN `session_id` UUID,
+    DROP COLUMN `legacy`,
+    ADD INDEX `session_bloom` `session_id` TYPE bloom_filter GRANULARITY 4;
+
+ALTER TABLE `analytics`.`search_logs`
+    DROP INDEX `query_bloom`,
+    ADD INDEX `query_bloom` `query` TYPE tokenbf_v1(512, 3, 0) GRANULARITY 1,
+    ADD INDEX `user_minmax` `user_id` TYPE minmax GRANULARITY 2;
\ No newline at end of file
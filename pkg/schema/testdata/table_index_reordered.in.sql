@@ -0,0 +1,9 @@
+-- Current state: indexes declared after the columns, in the order returned by ClickHouse
+CREATE TABLE analytics.search_logs (`id` UInt64, `query` String, `user_id` UInt64, `ts` DateTime, INDEX query_bloom query TYPE bloom_filter GRANULARITY 1, INDEX user_minmax user_id TYPE minmax GRANULARITY 2, INDEX ts_minmax ts TYPE minmax GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.events (`id` UInt64, `session_id` UUID, `kind` String, INDEX session_bloom session_id TYPE bloom_filter GRANULARITY 4, INDEX kind_set kind TYPE set(100) GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.sessions (`id` UInt64, `user_id` UInt64, `country` String, INDEX user_bloom user_id TYPE bloom_filter GRANULARITY 1, INDEX country_set country TYPE set(100) GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
+-- Target state: the same indexes declared in a different order, some between the columns, and only
+-- the changed country_set index is recreated
+CREATE TABLE analytics.search_logs (id UInt64, INDEX ts_minmax ts TYPE minmax GRANULARITY 1, query String, INDEX user_minmax user_id TYPE minmax GRANULARITY 2, user_id UInt64, ts DateTime, INDEX query_bloom query TYPE bloom_filter GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.events (id UInt64, session_id UUID, kind String, INDEX kind_set kind TYPE set(100) GRANULARITY 1, INDEX session_bloom session_id TYPE bloom_filter GRANULARITY 4) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.sessions (id UInt64, user_id UInt64, country String, INDEX country_set country TYPE set(500) GRANULARITY 1, INDEX user_bloom user_id TYPE bloom_filter GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,3 @@
+ALTER TABLE `analytics`.`sessions`
+    DROP INDEX `country_set`,
+    ADD INDEX `country_set` `country` TYPE set(500) GRANULARITY 1;
\ No newline at end of file
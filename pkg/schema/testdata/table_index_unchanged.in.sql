@@ -0,0 +1,4 @@
+-- Current state: table with indexes as returned by ClickHouse
+CREATE TABLE analytics.search_logs (`id` UInt64, `user_id` UInt64, `ts` DateTime, INDEX user_date (user_id, toDate(ts)) TYPE minmax GRANULARITY 1, INDEX user_bloom user_id TYPE bloom_filter GRANULARITY 1) ENGINE = MergeTree() ORDER BY id;
+-- Target state: same indexes with the default granularity omitted
+CREATE TABLE analytics.search_logs (id UInt64, user_id UInt64, ts DateTime, INDEX user_date (user_id, toDate(ts)) TYPE minmax, INDEX user_bloom (user_id) TYPE bloom_filter) ENGINE = MergeTree() ORDER BY id;
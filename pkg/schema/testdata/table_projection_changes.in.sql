@@ -0,0 +1,6 @@
+-- Current state
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, ts DateTime, PROJECTION by_user (SELECT user_id, count() GROUP BY user_id), PROJECTION by_ts (SELECT * ORDER BY ts)) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.sessions (id UInt64, user_id UInt64) ENGINE = MergeTree() ORDER BY id;
+-- Target state: events drops by_ts and counts by_user per day; sessions gains a projection on a new column
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, ts DateTime, PROJECTION by_user (SELECT user_id, toDate(ts) AS day, count() GROUP BY user_id, day)) ENGINE = MergeTree() ORDER BY id;
+CREATE TABLE analytics.sessions (id UInt64, user_id UInt64, started_at DateTime, PROJECTION by_start (SELECT * ORDER BY started_at)) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,13 @@
+ALTER TABLE `analytics`.`events`
+    DROP PROJECTION `by_user`,
+    DROP PROJECTION `by_ts`,
+    ADD PROJECTION `by_user` (SELECT
+    `user_id`,
+    toDate(`ts`) AS `day`,
+    count()
+GROUP BY `user_id`, `day`);
+
+ALTER TABLE `analytics`.`sessions`
+    ADD COLUMN `started_at` DateTime,
+    ADD PROJECTION `by_start` (SELECT *
+ORDER BY `started_at`);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: projections aggregating per user
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, event_type String, amount Float64, PROJECTION totals (SELECT user_id, sum(amount) GROUP BY user_id), PROJECTION counts (SELECT user_id, count() GROUP BY user_id)) ENGINE = MergeTree() ORDER BY id;
+-- Target state: totals groups by event type instead, counts aggregates uniq instead of count
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, event_type String, amount Float64, PROJECTION totals (SELECT event_type, sum(amount) GROUP BY event_type), PROJECTION counts (SELECT user_id, uniq(event_type) GROUP BY user_id)) ENGINE = MergeTree() ORDER BY id;
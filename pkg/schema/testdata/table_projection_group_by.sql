@@ -0,0 +1,11 @@
+ALTER TABLE `analytics`.`events`
+    DROP PROJECTION `totals`,
+    DROP PROJECTION `counts`,
+    ADD PROJECTION `totals` (SELECT
+    `event_type`,
+    sum(`amount`)
+GROUP BY `event_type`),
+    ADD PROJECTION `counts` (SELECT
+    `user_id`,
+    uniq(`event_type`)
+GROUP BY `user_id`);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: projection ordered by user
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, ts DateTime, PROJECTION sorted (SELECT * ORDER BY user_id)) ENGINE = MergeTree() ORDER BY id;
+-- Target state: same projection ordered by user and timestamp
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, ts DateTime, PROJECTION sorted (SELECT * ORDER BY (user_id, ts))) ENGINE = MergeTree() ORDER BY id;
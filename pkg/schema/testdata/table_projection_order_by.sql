@@ -0,0 +1,4 @@
+ALTER TABLE `analytics`.`events`
+    DROP PROJECTION `sorted`,
+    ADD PROJECTION `sorted` (SELECT *
+ORDER BY (`user_id`, `ts`));
\ No newline at end of file
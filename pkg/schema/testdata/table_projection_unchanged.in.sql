@@ -0,0 +1,4 @@
+-- Current state: projection as reported by ClickHouse
+CREATE TABLE analytics.events (`id` UInt64, `user_id` UInt64, PROJECTION by_user (SELECT `user_id`, count() GROUP BY `user_id`)) ENGINE = MergeTree() ORDER BY id;
+-- Target state:
+CREATE TABLE analytics.events (id UInt64, user_id UInt64, PROJECTION by_user (SELECT user_id, count() GROUP BY user_id)) ENGINE = MergeTree() ORDER BY id;
@@ -0,0 +1,8 @@
+-- Current state: replicated tables with and without TTLs
+CREATE TABLE analytics.events ON CLUSTER main (id UInt64, ts DateTime) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}') ORDER BY id;
+CREATE TABLE analytics.sessions ON CLUSTER main (id UInt64, started_at DateTime) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/sessions', '{replica}') ORDER BY id TTL started_at + INTERVAL 7 DAY;
+CREATE TABLE analytics.logs ON CLUSTER main (id UInt64, ts DateTime, level String) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/logs', '{replica}') ORDER BY id TTL ts + INTERVAL 30 DAY;
+-- Target state: TTL added, TTL extended, and TTL changed alongside a new column
+CREATE TABLE analytics.events ON CLUSTER main (id UInt64, ts DateTime) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}') ORDER BY id TTL ts + INTERVAL 90 DAY;
+CREATE TABLE analytics.sessions ON CLUSTER main (id UInt64, started_at DateTime) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/sessions', '{replica}') ORDER BY id TTL started_at + INTERVAL 30 DAY;
+CREATE TABLE analytics.logs ON CLUSTER main (id UInt64, ts DateTime, level String, message String) ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/logs', '{replica}') ORDER BY id TTL ts + INTERVAL 14 DAY;
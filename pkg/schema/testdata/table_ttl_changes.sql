@@ -0,0 +1,9 @@
+ALTER TABLE `analytics`.`events` ON CLUSTER `main`
+    MODIFY TTL `ts` + INTERVAL 90 DAY;
+
+ALTER TABLE `analytics`.`logs` ON CLUSTER `main`
+    ADD COLUMN `message` String,
+    MODIFY TTL `ts` + INTERVAL 14 DAY;
+
+ALTER TABLE `analytics`.`sessions` ON CLUSTER `main`
+    MODIFY TTL `started_at` + INTERVAL 30 DAY;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- Current state: events table
+CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+-- Target state: same table, truncating data is not a schema change
+CREATE TABLE analytics.events (id UInt64, name String) ENGINE = MergeTree() ORDER BY id;
+TRUNCATE TABLE analytics.events;
@@ -0,0 +1,4 @@
+-- Current state: sessions without pages are dropped
+CREATE VIEW analytics.session_pages AS SELECT session_id, url, position FROM analytics.sessions ARRAY JOIN urls AS url, arrayEnumerate(urls) AS position;
+-- Target state: sessions without pages are kept with LEFT ARRAY JOIN
+CREATE VIEW analytics.session_pages AS SELECT session_id, url, position FROM analytics.sessions LEFT ARRAY JOIN urls AS url, arrayEnumerate(urls) AS position;
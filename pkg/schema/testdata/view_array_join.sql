@@ -0,0 +1,7 @@
+CREATE OR REPLACE VIEW `analytics`.`session_pages`
+AS SELECT
+    `session_id`,
+    `url`,
+    `position`
+FROM `analytics`.`sessions`
+LEFT ARRAY JOIN `urls` AS `url`, arrayEnumerate(`urls`) AS `position`;
\ No newline at end of file
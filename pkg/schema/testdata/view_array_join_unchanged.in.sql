@@ -0,0 +1,4 @@
+-- Current state: view as returned by ClickHouse
+CREATE VIEW `analytics`.`session_pages` AS SELECT `session_id`, `url`, `position` FROM `analytics`.`sessions` LEFT ARRAY JOIN `urls` AS `url`, arrayEnumerate(`urls`) AS `position`;
+-- Target state: same view as written in the schema
+CREATE VIEW analytics.session_pages AS SELECT session_id, url, position FROM analytics.sessions LEFT ARRAY JOIN urls AS url, arrayEnumerate(urls) AS position;
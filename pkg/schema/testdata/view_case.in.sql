@@ -0,0 +1,4 @@
+-- Current state: orders split into two tiers
+CREATE VIEW analytics.order_tiers AS SELECT id, CASE WHEN amount >= 1000 THEN 'large' ELSE 'small' END AS tier FROM analytics.orders;
+-- Target state: a medium tier is added
+CREATE VIEW analytics.order_tiers AS SELECT id, CASE WHEN amount >= 1000 THEN 'large' WHEN amount >= 100 THEN 'medium' ELSE 'small' END AS tier FROM analytics.orders;
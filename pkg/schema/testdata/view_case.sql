@@ -0,0 +1,5 @@
+CREATE OR REPLACE VIEW `analytics`.`order_tiers`
+AS SELECT
+    `id`,
+    CASE WHEN `amount` >= 1000 THEN 'large' WHEN `amount` >= 100 THEN 'medium' ELSE 'small' END AS `tier`
+FROM `analytics`.`orders`;
\ No newline at end of file
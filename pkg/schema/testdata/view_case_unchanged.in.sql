@@ -0,0 +1,8 @@
+-- Current state: view as returned by ClickHouse
+CREATE VIEW `analytics`.`order_tiers` AS SELECT `id`, CASE WHEN `amount` >= 1000 THEN 'large' WHEN `amount` >= 100 THEN 'medium' ELSE 'small' END AS `tier` FROM `analytics`.`orders`;
+-- Target state: same view with different whitespace inside the CASE
+CREATE VIEW analytics.order_tiers AS SELECT id, CASE
+    WHEN amount>=1000 THEN 'large'
+    WHEN amount >= 100   THEN 'medium'
+    ELSE 'small'
+END AS tier FROM analytics.orders;
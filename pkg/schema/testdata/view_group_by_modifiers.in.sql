@@ -0,0 +1,4 @@
+-- Current state: daily revenue rolled up by region and country
+CREATE MATERIALIZED VIEW analytics.daily_revenue_mv TO analytics.daily_revenue AS SELECT toDate(ts) AS day, region, country, sum(amount) AS revenue FROM analytics.orders GROUP BY day, region, country WITH ROLLUP;
+-- Target state: every combination of region and country is aggregated with CUBE
+CREATE MATERIALIZED VIEW analytics.daily_revenue_mv TO analytics.daily_revenue AS SELECT toDate(ts) AS day, region, country, sum(amount) AS revenue FROM analytics.orders GROUP BY day, region, country WITH CUBE;
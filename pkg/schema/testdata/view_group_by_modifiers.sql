@@ -0,0 +1,11 @@
+DROP TABLE `analytics`.`daily_revenue_mv`;
+
+CREATE MATERIALIZED VIEW `analytics`.`daily_revenue_mv`
+TO `analytics`.`daily_revenue`
+AS SELECT
+    toDate(`ts`) AS `day`,
+    `region`,
+    `country`,
+    sum(`amount`) AS `revenue`
+FROM `analytics`.`orders`
+GROUP BY `day`, `region`, `country` WITH CUBE;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: materialized view as returned by ClickHouse
+CREATE MATERIALIZED VIEW `analytics`.`daily_revenue_mv` TO `analytics`.`daily_revenue` AS SELECT toDate(`ts`) AS `day`, `region`, `country`, sum(`amount`) AS `revenue` FROM `analytics`.`orders` GROUP BY `day`, `region`, `country` WITH ROLLUP WITH TOTALS;
+-- Target state: same materialized view as written in the schema
+CREATE MATERIALIZED VIEW analytics.daily_revenue_mv TO analytics.daily_revenue AS SELECT toDate(ts) AS day, region, country, sum(amount) AS revenue FROM analytics.orders GROUP BY day, region, country WITH ROLLUP WITH TOTALS;
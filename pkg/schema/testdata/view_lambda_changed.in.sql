@@ -0,0 +1,4 @@
+-- Current state: view lowercasing tags
+CREATE VIEW analytics.event_tags AS SELECT id, arrayMap(t -> lower(t), tags) AS tags FROM analytics.events;
+-- Target state: the lambda body changes
+CREATE VIEW analytics.event_tags AS SELECT id, arrayMap(t -> upper(t), tags) AS tags FROM analytics.events;
@@ -0,0 +1,5 @@
+CREATE OR REPLACE VIEW `analytics`.`event_tags`
+AS SELECT
+    `id`,
+    arrayMap(`t` -> upper(`t`), `tags`) AS `tags`
+FROM `analytics`.`events`;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Current state: view with higher-order functions as returned by ClickHouse
+CREATE VIEW analytics.event_tags AS SELECT `id`, arrayMap(`t` -> lower(`t`), `tags`) AS `tags`, arrayFilter((`t`, `w`) -> (`w` > 0.5), `tags`, `weights`) AS `strong_tags` FROM `analytics`.`events`;
+-- Target state: the same view with unquoted names and different spacing
+CREATE VIEW analytics.event_tags AS SELECT id, arrayMap(t->lower(t), tags) AS tags, arrayFilter((t, w) -> (w > 0.5), tags, weights) AS strong_tags FROM analytics.events;
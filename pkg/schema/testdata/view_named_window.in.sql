@@ -0,0 +1,4 @@
+-- Current state: view partitioning its named window by user
+CREATE VIEW analytics.running_totals AS SELECT user_id, sum(amount) OVER w AS running_total FROM analytics.orders WINDOW w AS (PARTITION BY user_id ORDER BY created_at);
+-- Target state: window also partitioned by region
+CREATE VIEW analytics.running_totals AS SELECT user_id, sum(amount) OVER w AS running_total FROM analytics.orders WINDOW w AS (PARTITION BY user_id, region ORDER BY created_at);
@@ -0,0 +1,6 @@
+CREATE OR REPLACE VIEW `analytics`.`running_totals`
+AS SELECT
+    `user_id`,
+    sum(`amount`) OVER `w` AS `running_total`
+FROM `analytics`.`orders`
+WINDOW `w` AS (PARTITION BY user_id, region ORDER BY created_at);
\ No newline at end of file
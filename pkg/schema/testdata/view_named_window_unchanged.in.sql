@@ -0,0 +1,7 @@
+-- Current state: view using a named window as returned by ClickHouse
+CREATE VIEW analytics.running_totals AS SELECT user_id, sum(amount) OVER w AS running_total FROM analytics.orders WINDOW w AS (PARTITION BY user_id ORDER BY created_at ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW);
+-- Target state: same view with quoted identifiers and different layout
+CREATE VIEW `analytics`.`running_totals` AS
+SELECT `user_id`, sum(`amount`) OVER `w` AS `running_total`
+FROM `analytics`.`orders`
+WINDOW `w` AS (PARTITION BY user_id ORDER BY created_at ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW);
@@ -0,0 +1,4 @@
+-- Current state: pages collected in timestamp order
+CREATE VIEW analytics.user_journeys AS SELECT user_id, groupArray(page ORDER BY ts) AS pages FROM analytics.events GROUP BY user_id;
+-- Target state: pages collected newest first
+CREATE VIEW analytics.user_journeys AS SELECT user_id, groupArray(page ORDER BY ts DESC) AS pages FROM analytics.events GROUP BY user_id;
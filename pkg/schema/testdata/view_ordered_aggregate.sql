@@ -0,0 +1,6 @@
+CREATE OR REPLACE VIEW `analytics`.`user_journeys`
+AS SELECT
+    `user_id`,
+    groupArray(`page` ORDER BY `ts` DESC) AS `pages`
+FROM `analytics`.`events`
+GROUP BY `user_id`;
\ No newline at end of file
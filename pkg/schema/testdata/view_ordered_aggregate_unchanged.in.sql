@@ -0,0 +1,7 @@
+-- Current state: view with an ordered aggregate as returned by ClickHouse
+CREATE VIEW analytics.user_journeys AS SELECT user_id, groupArray(page ORDER BY ts ASC) AS pages FROM analytics.events GROUP BY user_id;
+-- Target state: same view with quoted identifiers and different layout
+CREATE VIEW `analytics`.`user_journeys` AS
+SELECT `user_id`, groupArray(`page` ORDER BY `ts`) AS `pages`
+FROM `analytics`.`events`
+GROUP BY `user_id`;
@@ -0,0 +1,4 @@
+-- Current state: completed orders filtered with WHERE only
+CREATE VIEW analytics.completed_orders AS SELECT id, user_id, amount FROM analytics.orders WHERE status = 'completed';
+-- Target state: recent orders skipped early with PREWHERE
+CREATE VIEW analytics.completed_orders AS SELECT id, user_id, amount FROM analytics.orders PREWHERE event_date >= '2024-01-01' WHERE status = 'completed';
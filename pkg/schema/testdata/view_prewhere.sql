@@ -0,0 +1,8 @@
+CREATE OR REPLACE VIEW `analytics`.`completed_orders`
+AS SELECT
+    `id`,
+    `user_id`,
+    `amount`
+FROM `analytics`.`orders`
+PREWHERE `event_date` >= '2024-01-01'
+WHERE `status` = 'completed';
\ No newline at end of file
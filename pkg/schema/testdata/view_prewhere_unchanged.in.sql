@@ -0,0 +1,4 @@
+-- Current state: view as returned by ClickHouse
+CREATE VIEW `analytics`.`completed_orders` AS SELECT `id`, `user_id`, `amount` FROM `analytics`.`orders` PREWHERE `event_date` >= '2024-01-01' WHERE `status` = 'completed';
+-- Target state: same view as written in the schema
+CREATE VIEW analytics.completed_orders AS SELECT id, user_id, amount FROM analytics.orders PREWHERE event_date >= '2024-01-01' WHERE status = 'completed';
@@ -0,0 +1,8 @@
+-- Current state: views with security clauses as dumped by ClickHouse
+CREATE VIEW analytics.summary DEFINER = analyst SQL SECURITY INVOKER AS SELECT id, total FROM orders;
+CREATE VIEW analytics.defaults DEFINER = default SQL SECURITY INVOKER AS SELECT id FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_totals TO analytics.totals DEFINER = etl SQL SECURITY DEFINER AS SELECT id, sum(amount) AS total FROM orders GROUP BY id;
+-- Target state: summary switches to DEFINER, defaults omits the clauses, and the materialized view changes definer
+CREATE VIEW analytics.summary DEFINER = analyst SQL SECURITY DEFINER AS SELECT id, total FROM orders;
+CREATE VIEW analytics.defaults AS SELECT id FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_totals TO analytics.totals DEFINER = loader SQL SECURITY DEFINER AS SELECT id, sum(amount) AS total FROM orders GROUP BY id;
@@ -0,0 +1,17 @@
+DROP TABLE `analytics`.`mv_totals`;
+
+CREATE MATERIALIZED VIEW `analytics`.`mv_totals`
+TO `analytics`.`totals`
+DEFINER = `loader` SQL SECURITY DEFINER
+AS SELECT
+    `id`,
+    sum(`amount`) AS `total`
+FROM `orders`
+GROUP BY `id`;
+
+CREATE OR REPLACE VIEW `analytics`.`summary`
+DEFINER = `analyst` SQL SECURITY DEFINER
+AS SELECT
+    `id`,
+    `total`
+FROM `orders`;
\ No newline at end of file
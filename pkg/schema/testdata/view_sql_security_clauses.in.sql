@@ -0,0 +1,18 @@
+-- Current state: views adding and removing each security clause
+CREATE VIEW analytics.add_definer AS SELECT id FROM orders;
+CREATE VIEW analytics.remove_definer DEFINER = analyst AS SELECT id FROM orders;
+CREATE VIEW analytics.rename_definer DEFINER = analyst AS SELECT id FROM orders;
+CREATE VIEW analytics.add_security AS SELECT id FROM orders;
+CREATE VIEW analytics.remove_security SQL SECURITY NONE AS SELECT id FROM orders;
+CREATE VIEW analytics.default_security AS SELECT id FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_default_security TO analytics.totals AS SELECT id FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_remove_definer TO analytics.totals DEFINER = etl AS SELECT id FROM orders;
+-- Target state: adding or removing a clause is a change unless the unset side is the server default
+CREATE VIEW analytics.add_definer DEFINER = analyst AS SELECT id FROM orders;
+CREATE VIEW analytics.remove_definer AS SELECT id FROM orders;
+CREATE VIEW analytics.rename_definer DEFINER = Analyst AS SELECT id FROM orders;
+CREATE VIEW analytics.add_security SQL SECURITY DEFINER AS SELECT id FROM orders;
+CREATE VIEW analytics.remove_security AS SELECT id FROM orders;
+CREATE VIEW analytics.default_security SQL SECURITY INVOKER AS SELECT id FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_default_security TO analytics.totals SQL SECURITY DEFINER AS SELECT id FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_remove_definer TO analytics.totals AS SELECT id FROM orders;
@@ -0,0 +1,29 @@
+CREATE OR REPLACE VIEW `analytics`.`add_definer`
+DEFINER = `analyst`
+AS SELECT `id`
+FROM `orders`;
+
+CREATE OR REPLACE VIEW `analytics`.`add_security`
+SQL SECURITY DEFINER
+AS SELECT `id`
+FROM `orders`;
+
+DROP TABLE `analytics`.`mv_remove_definer`;
+
+CREATE MATERIALIZED VIEW `analytics`.`mv_remove_definer`
+TO `analytics`.`totals`
+AS SELECT `id`
+FROM `orders`;
+
+CREATE OR REPLACE VIEW `analytics`.`remove_definer`
+AS SELECT `id`
+FROM `orders`;
+
+CREATE OR REPLACE VIEW `analytics`.`remove_security`
+AS SELECT `id`
+FROM `orders`;
+
+CREATE OR REPLACE VIEW `analytics`.`rename_definer`
+DEFINER = `Analyst`
+AS SELECT `id`
+FROM `orders`;
\ No newline at end of file
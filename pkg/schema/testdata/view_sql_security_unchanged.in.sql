@@ -0,0 +1,6 @@
+-- Current state: views with security clauses as dumped by ClickHouse
+CREATE VIEW analytics.summary DEFINER = analyst SQL SECURITY DEFINER AS SELECT id, total FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_totals TO analytics.totals DEFINER = etl SQL SECURITY DEFINER AS SELECT id FROM orders;
+-- Target state: identical security clauses with different casing - should not generate any diff
+CREATE VIEW analytics.summary DEFINER = analyst SQL SECURITY definer AS SELECT id, total FROM orders;
+CREATE MATERIALIZED VIEW analytics.mv_totals TO analytics.totals DEFINER = etl SQL SECURITY DEFINER AS SELECT id FROM orders;
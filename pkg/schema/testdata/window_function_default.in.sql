@@ -0,0 +1,16 @@
+-- Current state: materialized columns using window functions
+CREATE TABLE analytics.events (
+    user_id UInt64,
+    ts DateTime,
+    value UInt64,
+    running UInt64 MATERIALIZED sum(value) OVER (PARTITION BY user_id ORDER BY ts),
+    ranked UInt64 MATERIALIZED rank() OVER (ORDER BY ts ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)
+) ENGINE = MergeTree() ORDER BY ts;
+-- Target state: running column partitions differently, ranked only differs in whitespace and case
+CREATE TABLE analytics.events (
+    user_id UInt64,
+    ts DateTime,
+    value UInt64,
+    running UInt64 MATERIALIZED sum(value) OVER (PARTITION BY ts ORDER BY ts),
+    ranked UInt64 MATERIALIZED rank() over (order by ts rows between unbounded preceding and current row)
+) ENGINE = MergeTree() ORDER BY ts;
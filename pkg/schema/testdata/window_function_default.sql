@@ -0,0 +1,2 @@
+ALTER TABLE `analytics`.`events`
+    MODIFY COLUMN `running` UInt64 MATERIALIZED sum(`value`) OVER (PARTITION BY ts ORDER BY ts);
\ No newline at end of file
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/pseudomuto/housekeeper/pkg/compare"
@@ -87,27 +88,23 @@ func caseExpressionsEqual(case1, case2 *parser.CaseExpression) bool {
 	}) && compare.PointersWithEqual(case1.ElseClause, case2.ElseClause, elseClausesEqual)
 }
 
-// whenClausesEqual compares WHEN clauses (using string comparison for now)
+// whenClausesEqual compares WHEN clauses structurally
 func whenClausesEqual(when1, when2 *parser.WhenClause) bool {
 	if eq, needsMoreChecks := compare.NilCheck(when1, when2); !needsMoreChecks {
 		return eq
 	}
 
-	// For now, use string comparison on the parsed string values
-	// This could be enhanced to parse the condition and result as expressions
-	return strings.TrimSpace(when1.Condition) == strings.TrimSpace(when2.Condition) &&
-		strings.TrimSpace(when1.Result) == strings.TrimSpace(when2.Result)
+	return expressionsAreEqual(&when1.Condition, &when2.Condition) &&
+		expressionsAreEqual(&when1.Result, &when2.Result)
 }
 
-// elseClausesEqual compares ELSE clauses (using string comparison for now)
+// elseClausesEqual compares ELSE clauses structurally
 func elseClausesEqual(else1, else2 *parser.ElseClause) bool {
 	if eq, needsMoreChecks := compare.NilCheck(else1, else2); !needsMoreChecks {
 		return eq
 	}
 
-	// For now, use string comparison on the parsed string value
-	// This could be enhanced to parse the result as an expression
-	return strings.TrimSpace(else1.Result) == strings.TrimSpace(else2.Result)
+	return expressionsAreEqual(&else1.Result, &else2.Result)
 }
 
 // orExpressionsEqual compares OR expressions structurally
@@ -322,11 +319,29 @@ func primaryExpressionsEqual(prim1, prim2 *parser.PrimaryExpression) bool {
 		return false
 	}
 
+	// Compare lambdas
+	if prim1.Lambda != nil && prim2.Lambda != nil {
+		return lambdasEqual(prim1.Lambda, prim2.Lambda)
+	}
+	if prim1.Lambda != nil || prim2.Lambda != nil {
+		return false
+	}
+
 	// For other expression types (Interval, Extract, Cast, Tuple, Array),
 	// we can add more detailed comparisons as needed
 	return true
 }
 
+// lambdasEqual compares lambda parameters and bodies
+func lambdasEqual(lambda1, lambda2 *parser.LambdaExpression) bool {
+	if eq, needsMoreChecks := compare.NilCheck(lambda1, lambda2); !needsMoreChecks {
+		return eq
+	}
+
+	return slices.Equal(lambda1.Parameters, lambda2.Parameters) &&
+		expressionsAreEqual(&lambda1.Body, &lambda2.Body)
+}
+
 // literalsEqual compares literal values
 func literalsEqual(lit1, lit2 *parser.Literal) bool {
 	if eq, needsMoreChecks := compare.NilCheck(lit1, lit2); !needsMoreChecks {
@@ -390,6 +405,11 @@ func functionCallsEqual(func1, func2 *parser.FunctionCall) bool {
 		return false
 	}
 
+	// Compare ORDER BY within the arguments (e.g. groupArray(x ORDER BY y))
+	if !compare.Slices(func1.OrderBy, func2.OrderBy, argumentOrderByEqual) {
+		return false
+	}
+
 	// Compare second parentheses argument lists (for parameterized functions)
 	if !compare.Slices(func1.SecondParentheses, func2.SecondParentheses, func(a, b parser.FunctionArg) bool {
 		return functionArgsEqual(&a, &b)
@@ -471,3 +491,10 @@ func functionArgsEqual(arg1, arg2 *parser.FunctionArg) bool {
 
 	return true
 }
+
+// argumentOrderByEqual compares a single ORDER BY element within function arguments
+func argumentOrderByEqual(a, b parser.OrderByExpr) bool {
+	return expressionsAreEqual(&a.Expression, &b.Expression) &&
+		a.Desc == b.Desc &&
+		compare.PointersWithEqual(a.Nulls, b.Nulls, func(x, y *string) bool { return strings.EqualFold(*x, *y) })
+}
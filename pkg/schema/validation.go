@@ -1,7 +1,10 @@
 package schema
 
 import (
+	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -25,6 +28,16 @@ var (
 		"File":       true,
 	}
 
+	// streamingEngines contains integration engines that continuously consume from an
+	// external queue
+	streamingEngines = map[string]bool{
+		"Kafka":      true,
+		"RabbitMQ":   true,
+		"NATS":       true,
+		"S3Queue":    true,
+		"AzureQueue": true,
+	}
+
 	// systemDatabases contains system databases that are protected from modification
 	systemDatabases = map[string]bool{
 		"system":             true,
@@ -41,6 +54,45 @@ var (
 		"LiveView":    {"PRIMARY KEY", "PARTITION BY", "SAMPLE BY", "ORDER BY"},
 		"Memory":      {"PARTITION BY", "SAMPLE BY"}, // Memory supports ORDER BY and PRIMARY KEY
 	}
+
+	// createOnlySettings contains the MergeTree settings that ClickHouse only accepts when a
+	// table is created. ALTER TABLE rejects modifying or resetting them as readonly settings.
+	createOnlySettings = map[string]bool{
+		"index_granularity":                    true,
+		"index_granularity_bytes":              true,
+		"enable_mixed_granularity_parts":       true,
+		"add_minmax_index_for_numeric_columns": true,
+		"add_minmax_index_for_string_columns":  true,
+		"table_disk":                           true,
+	}
+
+	// samplingColumnTypes contains the types ClickHouse accepts for a sampling expression
+	samplingColumnTypes = map[string]bool{
+		"UInt8":  true,
+		"UInt16": true,
+		"UInt32": true,
+		"UInt64": true,
+	}
+
+	// nonSamplingHashFunctions maps hash functions that can't be used in SAMPLE BY to the
+	// type they return, since sampling requires an unsigned integer
+	nonSamplingHashFunctions = map[string]string{
+		"MD4":                 "FixedString(16)",
+		"MD5":                 "FixedString(16)",
+		"SHA1":                "FixedString(20)",
+		"SHA224":              "FixedString(28)",
+		"SHA256":              "FixedString(32)",
+		"SHA384":              "FixedString(48)",
+		"SHA512":              "FixedString(64)",
+		"sipHash128":          "FixedString(16)",
+		"sipHash128Keyed":     "FixedString(16)",
+		"sipHash128Reference": "FixedString(16)",
+		"murmurHash3_128":     "FixedString(16)",
+		"xxh128":              "UInt128",
+		"javaHash":            "Int32",
+		"javaHashUTF16LE":     "Int32",
+		"hiveHash":            "Int32",
+	}
 )
 
 // equalAST is a generic helper for comparing AST types with Equal() methods
@@ -102,8 +154,25 @@ func validateTableOperation(current, target *TableInfo) error {
 
 		// Only prevent actual engine type changes, not parameter changes within same type
 		if currentEngineName != targetEngineName {
-			return errors.Wrapf(ErrUnsupported,
-				"cannot change engine from %s to %s: %v", currentEngineName, targetEngineName, ErrEngineChange)
+			return &ErrDestructiveOperation{
+				ObjectType: "table",
+				Object:     target.GetName(),
+				Err: errors.Wrapf(ErrUnsupported,
+					"cannot change engine from %s to %s: %v", currentEngineName, targetEngineName, ErrEngineChange),
+			}
+		}
+	}
+
+	// Category 10: CREATE-only Settings
+	// Tables that are recreated anyway (see shouldUseDropCreate) pick up the new value
+	if current != nil && target != nil && !shouldUseDropCreate(current, target) {
+		if name, changed := changedCreateOnlySetting(current, target); changed {
+			return &ErrDestructiveOperation{
+				ObjectType: "table",
+				Object:     target.GetName(),
+				Err: errors.Wrapf(ErrUnsupported,
+					"cannot change setting %s of table %s: it can only be set when the table is created", name, target.GetName()),
+			}
 		}
 	}
 
@@ -124,9 +193,94 @@ func validateTableOperation(current, target *TableInfo) error {
 		}
 	}
 
+	// Category 9: SAMPLE BY Requirements
+	// Only checked when the sampling or primary key changes, so tables ClickHouse already
+	// accepted are never rejected
+	if target != nil && target.SampleBy != nil && (current == nil ||
+		!keyExpressionsEqual(current.SampleBy, target.SampleBy) ||
+		!keyExpressionsEqual(primaryKeyOf(current), primaryKeyOf(target))) {
+		if err := validateSampleBy(target); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// changedCreateOnlySetting returns the first CREATE-only setting (see createOnlySettings)
+// whose value differs between the MergeTree tables current and target. A setting that's
+// omitted is equal to one set to the engine's implicit default.
+func changedCreateOnlySetting(current, target *TableInfo) (string, bool) {
+	if current.Engine == nil || target.Engine == nil ||
+		!strings.HasSuffix(current.Engine.Name, "MergeTree") || !strings.HasSuffix(target.Engine.Name, "MergeTree") {
+		return "", false
+	}
+
+	currentSettings := withoutDefaultSettings(current.Engine, current.Settings)
+	targetSettings := withoutDefaultSettings(target.Engine, target.Settings)
+	for _, name := range slices.Sorted(maps.Keys(createOnlySettings)) {
+		if currentSettings[name] != targetSettings[name] {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// primaryKeyOf returns the primary key of a table, which defaults to its ORDER BY
+func primaryKeyOf(table *TableInfo) *parser.Expression {
+	if table.PrimaryKey != nil {
+		return table.PrimaryKey
+	}
+	return table.OrderBy
+}
+
+// validateSampleBy checks the requirements ClickHouse places on a SAMPLE BY expression:
+// it must be one of the expressions in the primary key, and it must evaluate to an
+// unsigned integer (e.g. intHash32(user_id) or cityHash64(user_id), but not MD5(user_id)).
+func validateSampleBy(table *TableInfo) error {
+	sampleBy := unwrapParentheses(table.SampleBy)
+	name := formatQualifiedTableName(table.Database, table.Name)
+
+	keys := sortKeyElements(primaryKeyOf(table))
+	if !slices.ContainsFunc(keys, func(key *parser.Expression) bool { return keyExpressionsEqual(key, sampleBy) }) {
+		return invalidSampleBy("SAMPLE BY %s of table %s must be part of the primary key (%s)",
+			sampleBy.String(), name, sortKeyString(primaryKeyOf(table)))
+	}
+
+	if sampleBy.Or == nil {
+		return nil
+	}
+
+	primary := barePrimary(sampleBy.Or)
+	switch {
+	case primary == nil:
+		return nil
+	case primary.Function != nil:
+		if returnType, invalid := nonSamplingHashFunctions[primary.Function.Name]; invalid {
+			return invalidSampleBy("SAMPLE BY %s of table %s must return an unsigned integer, but %s returns %s",
+				sampleBy.String(), name, primary.Function.Name, returnType)
+		}
+	case primary.Identifier != nil:
+		for _, col := range table.Columns {
+			if col.Name != primary.Identifier.Name || col.DataType == nil || col.DataType.Simple == nil {
+				continue
+			}
+			if typeName := col.DataType.Simple.Name; !samplingColumnTypes[typeName] {
+				return invalidSampleBy("SAMPLE BY column %s of table %s must be an unsigned integer, not %s",
+					col.Name, name, typeName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// invalidSampleBy returns an error matching both ErrInvalidSampleBy and ErrUnsupported
+func invalidSampleBy(format string, args ...any) error {
+	return fmt.Errorf("%s: %w: %w", fmt.Sprintf(format, args...), ErrInvalidSampleBy, ErrUnsupported)
+}
+
 // validateDatabaseOperation validates database operations for invalid migration rules
 func validateDatabaseOperation(current, target *DatabaseInfo) error {
 	// Category 3: ON CLUSTER Configuration Changes
@@ -140,8 +294,12 @@ func validateDatabaseOperation(current, target *DatabaseInfo) error {
 	// Category 4: Engine Type Changes
 	if current != nil && target != nil {
 		if current.Engine != target.Engine {
-			return errors.Wrapf(ErrUnsupported,
-				"cannot change database engine from %s to %s: %v", current.Engine, target.Engine, ErrEngineChange)
+			return &ErrDestructiveOperation{
+				ObjectType: "database",
+				Object:     target.Name,
+				Err: errors.Wrapf(ErrUnsupported,
+					"cannot change database engine from %s to %s: %v", current.Engine, target.Engine, ErrEngineChange),
+			}
 		}
 	}
 
@@ -183,6 +183,38 @@ func TestValidateTableOperation(t *testing.T) {
 			expectError: true,
 			errorType:   ErrUnsupported,
 		},
+		{
+			name: "invalid - create-only setting change",
+			current: &TableInfo{
+				Name:     "events",
+				Database: "analytics",
+				Engine:   makeEngine("ReplicatedMergeTree"),
+				Settings: map[string]string{"index_granularity": "4096"},
+			},
+			target: &TableInfo{
+				Name:     "events",
+				Database: "analytics",
+				Engine:   makeEngine("ReplicatedMergeTree"),
+			},
+			expectError: true,
+			errorType:   ErrUnsupported,
+		},
+		{
+			name: "valid - create-only setting at engine default",
+			current: &TableInfo{
+				Name:     "events",
+				Database: "analytics",
+				Engine:   makeEngine("MergeTree"),
+				Settings: map[string]string{"index_granularity": "8192"},
+			},
+			target: &TableInfo{
+				Name:     "events",
+				Database: "analytics",
+				Engine:   makeEngine("MergeTree"),
+				Settings: map[string]string{"merge_with_ttl_timeout": "3600"},
+			},
+			expectError: false,
+		},
 		{
 			name: "invalid - system table modification",
 			current: &TableInfo{
@@ -261,6 +293,46 @@ func TestValidateTableOperation(t *testing.T) {
 	}
 }
 
+func TestDestructiveOperationError(t *testing.T) {
+	t.Run("table engine change", func(t *testing.T) {
+		err := validateTableOperation(
+			&TableInfo{Name: "events", Database: "analytics", Engine: makeEngine("MergeTree")},
+			&TableInfo{Name: "events", Database: "analytics", Engine: makeEngine("ReplacingMergeTree")},
+		)
+
+		var destructive *ErrDestructiveOperation
+		require.ErrorAs(t, err, &destructive)
+		require.Equal(t, "table", destructive.ObjectType)
+		require.Equal(t, "analytics.events", destructive.Object)
+		require.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("database engine change", func(t *testing.T) {
+		err := validateDatabaseOperation(
+			&DatabaseInfo{Name: "analytics", Engine: "Atomic"},
+			&DatabaseInfo{Name: "analytics", Engine: "Lazy(3600)"},
+		)
+
+		var destructive *ErrDestructiveOperation
+		require.ErrorAs(t, err, &destructive)
+		require.Equal(t, "database", destructive.ObjectType)
+		require.Equal(t, "analytics", destructive.Object)
+	})
+
+	t.Run("surfaces through GenerateDiff", func(t *testing.T) {
+		current, err := parser.ParseString("CREATE TABLE events (id UInt64) ENGINE = MergeTree() ORDER BY id;")
+		require.NoError(t, err)
+		target, err := parser.ParseString("CREATE TABLE events (id UInt64) ENGINE = ReplacingMergeTree() ORDER BY id;")
+		require.NoError(t, err)
+
+		_, err = GenerateDiff(current, target)
+
+		var destructive *ErrDestructiveOperation
+		require.ErrorAs(t, err, &destructive)
+		require.Equal(t, "events", destructive.Object)
+	})
+}
+
 func TestValidateDatabaseOperation(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -753,6 +825,56 @@ func TestValidateTableClauses(t *testing.T) {
 	}
 }
 
+func TestValidateSampleBy(t *testing.T) {
+	tableInfo := func(sql string) *TableInfo {
+		return parseTableInfo(t, "CREATE TABLE db.hits (user_id UInt64, name String, ts DateTime) ENGINE = MergeTree() "+sql+";")
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		errorMsg string
+	}{
+		{name: "hash in sorting key", sql: "ORDER BY (ts, intHash32(user_id)) SAMPLE BY intHash32(user_id)"},
+		{name: "column in sorting key", sql: "ORDER BY (ts, user_id) SAMPLE BY user_id"},
+		{name: "hash in explicit primary key", sql: "ORDER BY (ts, cityHash64(user_id), name) PRIMARY KEY (ts, cityHash64(user_id)) SAMPLE BY cityHash64(user_id)"},
+		{
+			name:     "not in primary key",
+			sql:      "ORDER BY (ts, intHash32(user_id)) PRIMARY KEY ts SAMPLE BY intHash32(user_id)",
+			errorMsg: "SAMPLE BY intHash32(user_id) of table db.hits must be part of the primary key (ts)",
+		},
+		{
+			name:     "signed hash",
+			sql:      "ORDER BY (ts, javaHash(name)) SAMPLE BY javaHash(name)",
+			errorMsg: "but javaHash returns Int32",
+		},
+		{
+			name:     "non-integer column",
+			sql:      "ORDER BY (ts, name) SAMPLE BY name",
+			errorMsg: "SAMPLE BY column name of table db.hits must be an unsigned integer, not String",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTableOperation(nil, tableInfo(tt.sql))
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrUnsupported)
+			require.ErrorIs(t, err, ErrInvalidSampleBy)
+			require.ErrorContains(t, err, tt.errorMsg)
+		})
+	}
+
+	t.Run("unchanged sampling isn't validated", func(t *testing.T) {
+		table := tableInfo("ORDER BY ts SAMPLE BY intHash32(user_id)")
+		require.NoError(t, validateTableOperation(table, table))
+	})
+}
+
 func TestShouldCopyClause(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -3,6 +3,7 @@ package schema
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -88,7 +89,7 @@ func (v *ViewInfo) PropertiesMatch(other SchemaObject) bool {
 // - Uses CREATE OR REPLACE for content changes
 // - Uses standard CREATE/DROP for creation/deletion
 // - Uses RENAME TABLE for renames
-func compareViews(current, target *parser.SQL) ([]*ViewDiff, error) { // nolint: unparam
+func compareViews(current, target *parser.SQL, opts CompareOptions) ([]*ViewDiff, error) { // nolint: unparam
 	// Extract views from both schemas
 	currentViews := extractViewsFromSQL(current)
 	targetViews := extractViewsFromSQL(target)
@@ -96,19 +97,19 @@ func compareViews(current, target *parser.SQL) ([]*ViewDiff, error) { // nolint:
 	var diffs []*ViewDiff
 
 	// Find views to create, drop, alter, or rename using helper functions
-	createD, err := findViewsToCreate(targetViews, currentViews)
+	createD, err := findViewsToCreate(targetViews, currentViews, opts)
 	if err != nil {
 		return nil, err
 	}
 	diffs = append(diffs, createD...)
 
-	dropD, err := findViewsToDrop(currentViews, targetViews)
+	dropD, err := findViewsToDrop(currentViews, targetViews, opts)
 	if err != nil {
 		return nil, err
 	}
 	diffs = append(diffs, dropD...)
 
-	alterD, err := findViewsToAlterOrRename(currentViews, targetViews)
+	alterD, err := findViewsToAlterOrRename(currentViews, targetViews, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +119,7 @@ func compareViews(current, target *parser.SQL) ([]*ViewDiff, error) { // nolint:
 }
 
 // findViewsToCreate finds views that need to be created (exist in target but not in current)
-func findViewsToCreate(targetViews, currentViews map[string]*ViewInfo) ([]*ViewDiff, error) {
+func findViewsToCreate(targetViews, currentViews map[string]*ViewInfo, opts CompareOptions) ([]*ViewDiff, error) {
 	// Count views to create for pre-allocation
 	createCount := 0
 	for name := range targetViews {
@@ -150,9 +151,9 @@ func findViewsToCreate(targetViews, currentViews map[string]*ViewInfo) ([]*ViewD
 				Type:        string(ViewDiffCreate),
 				Name:        name,
 				Description: fmt.Sprintf("Create %s %s", getViewType(targetView), name),
-				UpSQL:       generateCreateViewSQL(targetView),
-				DownSQL:     generateDropViewSQL(targetView),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateCreateViewSQL(targetView), generateDropViewSQL(targetView)
+			}),
 			Target:         targetView,
 			IsMaterialized: targetView.IsMaterialized,
 		}
@@ -163,7 +164,7 @@ func findViewsToCreate(targetViews, currentViews map[string]*ViewInfo) ([]*ViewD
 }
 
 // findViewsToDrop finds views that need to be dropped (exist in current but not in target)
-func findViewsToDrop(currentViews, targetViews map[string]*ViewInfo) ([]*ViewDiff, error) {
+func findViewsToDrop(currentViews, targetViews map[string]*ViewInfo, opts CompareOptions) ([]*ViewDiff, error) {
 	// Count views to drop for pre-allocation
 	dropCount := 0
 	for name := range currentViews {
@@ -195,9 +196,9 @@ func findViewsToDrop(currentViews, targetViews map[string]*ViewInfo) ([]*ViewDif
 				Type:        string(ViewDiffDrop),
 				Name:        name,
 				Description: fmt.Sprintf("Drop %s %s", getViewType(currentView), name),
-				UpSQL:       generateDropViewSQL(currentView),
-				DownSQL:     generateCreateViewSQL(currentView),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateDropViewSQL(currentView), generateCreateViewSQL(currentView)
+			}),
 			Current:        currentView,
 			IsMaterialized: currentView.IsMaterialized,
 		}
@@ -208,7 +209,7 @@ func findViewsToDrop(currentViews, targetViews map[string]*ViewInfo) ([]*ViewDif
 }
 
 // findViewsToAlterOrRename finds views that need to be altered or renamed
-func findViewsToAlterOrRename(currentViews, targetViews map[string]*ViewInfo) ([]*ViewDiff, error) {
+func findViewsToAlterOrRename(currentViews, targetViews map[string]*ViewInfo, opts CompareOptions) ([]*ViewDiff, error) {
 	diffs := make([]*ViewDiff, 0, len(currentViews))
 
 	// Use generic rename detection algorithm
@@ -230,9 +231,9 @@ func findViewsToAlterOrRename(currentViews, targetViews map[string]*ViewInfo) ([
 				Name:        rename.OldName,
 				NewName:     rename.NewName,
 				Description: fmt.Sprintf("Rename %s %s to %s", getViewType(currentView), rename.OldName, rename.NewName),
-				UpSQL:       generateRenameViewSQL(currentView, targetView),
-				DownSQL:     generateRenameViewSQL(targetView, currentView),
-			},
+			}.withSQL(opts, func() (string, string) {
+				return generateRenameViewSQL(currentView, targetView), generateRenameViewSQL(targetView, currentView)
+			}),
 			Current:        currentView,
 			Target:         targetView,
 			IsMaterialized: currentView.IsMaterialized,
@@ -260,26 +261,20 @@ func findViewsToAlterOrRename(currentViews, targetViews map[string]*ViewInfo) ([
 
 		if !viewsAreEqual(currentView, targetView) {
 			// View needs to be altered
-			var upSQL, downSQL string
-
-			// For materialized views, use DROP+CREATE (more reliable than ALTER TABLE MODIFY QUERY)
-			// For regular views, use CREATE OR REPLACE
-			if currentView.IsMaterialized {
-				upSQL = generateDropViewSQL(currentView) + "\n\n" + generateCreateViewSQL(targetView)
-				downSQL = generateDropViewSQL(targetView) + "\n\n" + generateCreateViewSQL(currentView)
-			} else {
-				upSQL = generateCreateOrReplaceViewSQL(targetView)
-				downSQL = generateCreateOrReplaceViewSQL(currentView)
-			}
-
 			diff := &ViewDiff{
 				DiffBase: DiffBase{
 					Type:        string(ViewDiffAlter),
 					Name:        name,
 					Description: fmt.Sprintf("Alter %s %s", getViewType(currentView), name),
-					UpSQL:       upSQL,
-					DownSQL:     downSQL,
-				},
+				}.withSQL(opts, func() (string, string) {
+					// For materialized views, use DROP+CREATE (more reliable than ALTER TABLE MODIFY QUERY)
+					// For regular views, use CREATE OR REPLACE
+					if currentView.IsMaterialized {
+						return generateDropViewSQL(currentView) + "\n\n" + generateCreateViewSQL(targetView),
+							generateDropViewSQL(targetView) + "\n\n" + generateCreateViewSQL(currentView)
+					}
+					return generateCreateOrReplaceViewSQL(targetView), generateCreateOrReplaceViewSQL(currentView)
+				}),
 				Current:        currentView,
 				Target:         targetView,
 				IsMaterialized: currentView.IsMaterialized,
@@ -394,6 +389,11 @@ func viewStatementsAreEqual(stmt1, stmt2 *parser.CreateViewStmt) bool {
 	_ = stmt1.Populate // Acknowledge that we're intentionally ignoring this field
 	_ = stmt2.Populate
 
+	// Compare DEFINER and SQL SECURITY clauses
+	if !viewSecurityIsEqual(stmt1, stmt2) {
+		return false
+	}
+
 	// Compare SELECT clauses with formatting tolerance
 	if !selectClausesAreEqualWithTolerance(stmt1.AsSelect, stmt2.AsSelect) {
 		return false
@@ -402,6 +402,36 @@ func viewStatementsAreEqual(stmt1, stmt2 *parser.CreateViewStmt) bool {
 	return true
 }
 
+// viewSecurityIsEqual compares the DEFINER and SQL SECURITY clauses of two views. A clause
+// that only one view specifies is equal when its value is the server default, since
+// ClickHouse reports the defaults for views that were created without them.
+func viewSecurityIsEqual(stmt1, stmt2 *parser.CreateViewStmt) bool {
+	return viewDefiner(stmt1) == viewDefiner(stmt2) && viewSQLSecurity(stmt1) == viewSQLSecurity(stmt2)
+}
+
+// viewDefiner returns the definer of a view, or "" when it's the server default. The default
+// definer is the user creating the view (CURRENT_USER), which ClickHouse reports by name, so
+// the default user is treated as the default as well. User names are case-sensitive.
+func viewDefiner(stmt *parser.CreateViewStmt) string {
+	if stmt.Definer == nil || strings.EqualFold(*stmt.Definer, "CURRENT_USER") || *stmt.Definer == "default" {
+		return ""
+	}
+	return *stmt.Definer
+}
+
+// viewSQLSecurity returns the SQL SECURITY of a view, defaulting to the server defaults of
+// INVOKER for views and DEFINER for materialized views
+func viewSQLSecurity(stmt *parser.CreateViewStmt) string {
+	switch {
+	case stmt.SQLSecurity != nil:
+		return strings.ToUpper(*stmt.SQLSecurity)
+	case stmt.Materialized:
+		return "DEFINER"
+	default:
+		return "INVOKER"
+	}
+}
+
 // viewStatementsHaveSameProperties compares statements ignoring names (for rename detection)
 func viewStatementsHaveSameProperties(stmt1, stmt2 *parser.CreateViewStmt) bool {
 	// For rename detection, we compare everything except the view name
@@ -482,8 +512,9 @@ func viewEnginesEqual(engine1, engine2 *parser.ViewEngine) bool {
 		return false
 	}
 
-	// Compare engine names (case-insensitive)
-	if !strings.EqualFold(engine1.Name, engine2.Name) {
+	// Compare engine names (case-sensitive, matching TableEngine.Equal since ClickHouse
+	// only accepts the canonical casing)
+	if engine1.Name != engine2.Name {
 		return false
 	}
 
@@ -584,9 +615,33 @@ func selectStatementsAreEqualNormalized(stmt1, stmt2 *parser.SelectStatement) bo
 	if (stmt1.From == nil) != (stmt2.From == nil) {
 		return false // Different FROM clause presence
 	}
+	if (stmt1.PreWhere == nil) != (stmt2.PreWhere == nil) {
+		return false // Different PREWHERE presence
+	}
+	if !arrayJoinClausesAreEqual(stmt1.ArrayJoin, stmt2.ArrayJoin) {
+		return false // ARRAY JOIN changes the rows a view produces, so it must match
+	}
 	if (stmt1.OrderBy == nil) != (stmt2.OrderBy == nil) {
 		return false // Different ORDER BY presence
 	}
+	if !groupByModifiersAreEqual(stmt1.GroupBy, stmt2.GroupBy) {
+		return false // ROLLUP, CUBE, and TOTALS add subtotal rows, so they must match
+	}
+	if !windowClausesAreEqual(stmt1.Window, stmt2.Window) {
+		return false // Named windows are preserved as written, so they must match exactly
+	}
+	if !unionClausesAreEqual(stmt1.Union, stmt2.Union) {
+		return false // Every UNION branch is compared in full so a change in any branch is detected
+	}
+	if !argumentOrderingsAreEqual(stmt1.Columns, stmt2.Columns) {
+		return false // The ORDER BY of an aggregate changes its result, so it must match
+	}
+	if !caseExpressionsInColumnsAreEqual(stmt1.Columns, stmt2.Columns) {
+		return false // Changing a CASE branch changes the column value, so CASE expressions must match
+	}
+	if !lambdasInColumnsAreEqual(stmt1.Columns, stmt2.Columns) {
+		return false // Lambdas decide what higher-order functions like arrayMap produce, so they must match
+	}
 
 	// If we get here, the basic structure is similar
 	// For ClickHouse formatting tolerance, we'll be optimistic and assume they're equivalent
@@ -595,6 +650,77 @@ func selectStatementsAreEqualNormalized(stmt1, stmt2 *parser.SelectStatement) bo
 	return true
 }
 
+// argumentOrderingsAreEqual compares the ORDER BY within the arguments of every function
+// call in two column lists, e.g. the ordering of groupArray(x ORDER BY y)
+func argumentOrderingsAreEqual(columns1, columns2 []parser.SelectColumn) bool {
+	var orderings1, orderings2 [][]parser.OrderByExpr
+	collectNodes(reflect.ValueOf(columns1), func(fn *parser.FunctionCall) {
+		if len(fn.OrderBy) > 0 {
+			orderings1 = append(orderings1, fn.OrderBy)
+		}
+	})
+	collectNodes(reflect.ValueOf(columns2), func(fn *parser.FunctionCall) {
+		if len(fn.OrderBy) > 0 {
+			orderings2 = append(orderings2, fn.OrderBy)
+		}
+	})
+
+	return compare.Slices(orderings1, orderings2, func(a, b []parser.OrderByExpr) bool {
+		return compare.Slices(a, b, argumentOrderByEqual)
+	})
+}
+
+// caseExpressionsInColumnsAreEqual compares every CASE expression in two column lists,
+// including CASE expressions nested within other expressions
+func caseExpressionsInColumnsAreEqual(columns1, columns2 []parser.SelectColumn) bool {
+	var cases1, cases2 []*parser.CaseExpression
+	collectNodes(reflect.ValueOf(columns1), func(c *parser.CaseExpression) {
+		cases1 = append(cases1, c)
+	})
+	collectNodes(reflect.ValueOf(columns2), func(c *parser.CaseExpression) {
+		cases2 = append(cases2, c)
+	})
+
+	return compare.Slices(cases1, cases2, caseExpressionsEqual)
+}
+
+// lambdasInColumnsAreEqual compares every lambda (including nested ones) in the selected columns
+func lambdasInColumnsAreEqual(columns1, columns2 []parser.SelectColumn) bool {
+	var lambdas1, lambdas2 []*parser.LambdaExpression
+	collectNodes(reflect.ValueOf(columns1), func(l *parser.LambdaExpression) {
+		lambdas1 = append(lambdas1, l)
+	})
+	collectNodes(reflect.ValueOf(columns2), func(l *parser.LambdaExpression) {
+		lambdas2 = append(lambdas2, l)
+	})
+
+	return compare.Slices(lambdas1, lambdas2, lambdasEqual)
+}
+
+// collectNodes walks the AST rooted at v and calls fn for every node of type T,
+// including nested ones.
+func collectNodes[T any](v reflect.Value, fn func(*T)) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			collectNodes(v.Elem(), fn)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			collectNodes(v.Index(i), fn)
+		}
+	case reflect.Struct:
+		if node, ok := v.Interface().(T); ok {
+			fn(&node)
+		}
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				collectNodes(v.Field(i), fn)
+			}
+		}
+	}
+}
+
 // selectStatementsAreEqualAST compares two SELECT statements using AST-based comparison
 func selectStatementsAreEqualAST(stmt1, stmt2 *parser.SelectStatement) bool {
 	// Compare WITH clauses (CTEs)
@@ -612,6 +738,16 @@ func selectStatementsAreEqualAST(stmt1, stmt2 *parser.SelectStatement) bool {
 		return false
 	}
 
+	// Compare ARRAY JOIN clauses
+	if !arrayJoinClausesAreEqual(stmt1.ArrayJoin, stmt2.ArrayJoin) {
+		return false
+	}
+
+	// Compare PREWHERE clauses
+	if !preWhereClausesAreEqual(stmt1.PreWhere, stmt2.PreWhere) {
+		return false
+	}
+
 	// Compare WHERE clauses
 	if !whereClausesAreEqual(stmt1.Where, stmt2.Where) {
 		return false
@@ -627,6 +763,11 @@ func selectStatementsAreEqualAST(stmt1, stmt2 *parser.SelectStatement) bool {
 		return false
 	}
 
+	// Compare WINDOW clauses
+	if !windowClausesAreEqual(stmt1.Window, stmt2.Window) {
+		return false
+	}
+
 	// Compare ORDER BY clauses
 	if !selectOrderByClausesAreEqual(stmt1.OrderBy, stmt2.OrderBy) {
 		return false
@@ -642,9 +783,33 @@ func selectStatementsAreEqualAST(stmt1, stmt2 *parser.SelectStatement) bool {
 		return false
 	}
 
+	// Compare UNION branches
+	if !unionClausesAreEqual(stmt1.Union, stmt2.Union) {
+		return false
+	}
+
 	return true
 }
 
+// unionClausesAreEqual compares UNION clauses, recursing into the SELECT of each branch
+func unionClausesAreEqual(union1, union2 *parser.UnionClause) bool {
+	if eq, done := compare.NilCheck(union1, union2); !done {
+		return eq
+	}
+	if !strings.EqualFold(unionMode(union1), unionMode(union2)) {
+		return false
+	}
+	return selectStatementsAreEqualAST(union1.Select, union2.Select)
+}
+
+// unionMode returns the ALL or DISTINCT modifier of a UNION clause, or "" when it's omitted
+func unionMode(union *parser.UnionClause) string {
+	if union.Mode == nil {
+		return ""
+	}
+	return *union.Mode
+}
+
 // withClausesAreEqual compares WITH clauses (CTEs)
 func withClausesAreEqual(with1, with2 *parser.WithClause) bool {
 	if eq, done := compare.NilCheck(with1, with2); !done {
@@ -656,6 +821,30 @@ func withClausesAreEqual(with1, with2 *parser.WithClause) bool {
 	})
 }
 
+// arrayJoinClausesAreEqual compares ARRAY JOIN clauses, including the LEFT modifier
+// and the alias of each array
+func arrayJoinClausesAreEqual(arrayJoin1, arrayJoin2 *parser.ArrayJoinClause) bool {
+	if eq, done := compare.NilCheck(arrayJoin1, arrayJoin2); !done {
+		return eq
+	}
+
+	return arrayJoin1.Left == arrayJoin2.Left &&
+		compare.Slices(arrayJoin1.Arrays, arrayJoin2.Arrays, func(a, b parser.ArrayJoinElement) bool {
+			return expressionsAreEqual(&a.Expression, &b.Expression) &&
+				compare.PointersWithEqual(a.Alias, b.Alias, func(x, y *string) bool {
+					return normalizeIdentifier(*x) == normalizeIdentifier(*y)
+				})
+		})
+}
+
+// preWhereClausesAreEqual compares PREWHERE clauses
+func preWhereClausesAreEqual(preWhere1, preWhere2 *parser.PreWhereClause) bool {
+	if eq, done := compare.NilCheck(preWhere1, preWhere2); !done {
+		return eq
+	}
+	return expressionsAreEqual(&preWhere1.Condition, &preWhere2.Condition)
+}
+
 // whereClausesAreEqual compares WHERE clauses
 func whereClausesAreEqual(where1, where2 *parser.WhereClause) bool {
 	if eq, done := compare.NilCheck(where1, where2); !done {
@@ -672,6 +861,16 @@ func havingClausesAreEqual(having1, having2 *parser.HavingClause) bool {
 	return expressionsAreEqual(&having1.Condition, &having2.Condition)
 }
 
+// windowClausesAreEqual compares WINDOW clauses. Named windows are compared in order.
+func windowClausesAreEqual(window1, window2 *parser.WindowClause) bool {
+	if eq, done := compare.NilCheck(window1, window2); !done {
+		return eq
+	}
+	return compare.Slices(window1.Definitions, window2.Definitions, func(a, b parser.WindowDefinition) bool {
+		return a.Equal(&b)
+	})
+}
+
 // selectOrderByClausesAreEqual compares SELECT ORDER BY clauses
 func selectOrderByClausesAreEqual(order1, order2 *parser.SelectOrderByClause) bool {
 	if eq, done := compare.NilCheck(order1, order2); !done {
@@ -909,7 +1108,7 @@ func joinsAreEqual(join1, join2 *parser.JoinClause) bool {
 	return true
 }
 
-// groupByClausesAreEqual compares GROUP BY clauses
+// groupByClausesAreEqual compares GROUP BY clauses, including their modifiers
 func groupByClausesAreEqual(group1, group2 *parser.GroupByClause) bool {
 	if group1 == nil && group2 == nil {
 		return true
@@ -917,6 +1116,9 @@ func groupByClausesAreEqual(group1, group2 *parser.GroupByClause) bool {
 	if group1 == nil || group2 == nil {
 		return false
 	}
+	if group1.All != group2.All || !groupByModifiersAreEqual(group1, group2) {
+		return false
+	}
 	if len(group1.Columns) != len(group2.Columns) {
 		return false
 	}
@@ -929,6 +1131,21 @@ func groupByClausesAreEqual(group1, group2 *parser.GroupByClause) bool {
 	return true
 }
 
+// groupByModifiersAreEqual compares the WITH ROLLUP, WITH CUBE, and WITH TOTALS modifiers of
+// two GROUP BY clauses, either of which may be nil
+func groupByModifiersAreEqual(group1, group2 *parser.GroupByClause) bool {
+	var modifiers1, modifiers2 parser.GroupByClause
+	if group1 != nil {
+		modifiers1 = *group1
+	}
+	if group2 != nil {
+		modifiers2 = *group2
+	}
+	return modifiers1.WithRollup == modifiers2.WithRollup &&
+		modifiers1.WithCube == modifiers2.WithCube &&
+		modifiers1.WithTotals == modifiers2.WithTotals
+}
+
 // limitClausesAreEqual compares LIMIT clauses
 func limitClausesAreEqual(limit1, limit2 *parser.LimitClause) bool {
 	if limit1 == nil && limit2 == nil {
@@ -1077,6 +1294,8 @@ func generateCreateViewSQL(view *ViewInfo) string {
 		sql += " POPULATE"
 	}
 
+	sql += viewSecuritySQL(view.Statement)
+
 	if view.Statement.AsSelect != nil {
 		sql += " AS " + selectStatementToString(view.Statement.AsSelect)
 	}
@@ -1113,6 +1332,8 @@ func generateCreateOrReplaceViewSQL(view *ViewInfo) string {
 		sql += " ON CLUSTER " + view.Cluster
 	}
 
+	sql += viewSecuritySQL(view.Statement)
+
 	if view.Statement.AsSelect != nil {
 		sql += " AS " + selectStatementToString(view.Statement.AsSelect)
 	}
@@ -1120,6 +1341,23 @@ func generateCreateOrReplaceViewSQL(view *ViewInfo) string {
 	return sql + ";"
 }
 
+// viewSecuritySQL returns the DEFINER and SQL SECURITY clauses of a view (with a leading
+// space), or an empty string when neither is set
+func viewSecuritySQL(stmt *parser.CreateViewStmt) string {
+	var sql string
+	if stmt.Definer != nil {
+		if strings.EqualFold(*stmt.Definer, "CURRENT_USER") {
+			sql += " DEFINER = CURRENT_USER"
+		} else {
+			sql += " DEFINER = " + utils.BacktickIdentifier(*stmt.Definer)
+		}
+	}
+	if stmt.SQLSecurity != nil {
+		sql += " SQL SECURITY " + strings.ToUpper(*stmt.SQLSecurity)
+	}
+	return sql
+}
+
 // generateRenameViewSQL generates RENAME TABLE SQL for both regular and materialized views
 func generateRenameViewSQL(from, to *ViewInfo) string {
 	var fromDB, toDB *string
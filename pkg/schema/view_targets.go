@@ -0,0 +1,223 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+)
+
+// ViewTargetWarning describes a materialized view whose SELECT doesn't line up with
+// the columns of the table it writes to.
+type ViewTargetWarning struct {
+	// View is the fully-qualified name of the materialized view
+	View string
+
+	// Target is the fully-qualified name of the view's TO table
+	Target string
+
+	// Message describes the mismatch
+	Message string
+}
+
+// String returns a human readable description of the warning.
+func (w ViewTargetWarning) String() string {
+	return fmt.Sprintf("materialized view %s (TO %s): %s", w.View, w.Target, w.Message)
+}
+
+// ValidateViewTargets checks that every materialized view with a TO table selects
+// columns that are compatible with that table. ClickHouse matches the SELECT output to
+// the target's columns by name, so the following are reported:
+//   - SELECT expressions without an alias, which can't be matched to a column
+//   - SELECT columns that don't exist in (or can't be inserted into) the target
+//   - Target columns without a DEFAULT that the view never populates
+//   - -State aggregates written to columns that aren't AggregateFunction, and
+//     AggregateFunction columns populated by anything other than a -State aggregate
+//
+// Views whose target isn't defined in sql, or whose SELECT uses *, are skipped. The
+// checks are heuristics, so mismatches are reported as warnings rather than errors.
+//
+// Example:
+//
+//	for _, warning := range schema.ValidateViewTargets(target) {
+//		fmt.Println("Warning:", warning)
+//	}
+func ValidateViewTargets(sql *parser.SQL) []ViewTargetWarning {
+	tables := make(map[string]*parser.CreateTableStmt)
+	for _, stmt := range sql.Statements {
+		if stmt.CreateTable != nil {
+			tables[sourceName(stmt.CreateTable.Database, stmt.CreateTable.Name)] = stmt.CreateTable
+		}
+	}
+
+	var warnings []ViewTargetWarning
+	for _, stmt := range sql.Statements {
+		view := stmt.CreateView
+		if view == nil || !view.Materialized || view.To == nil || view.To.Table == nil || view.AsSelect == nil {
+			continue
+		}
+
+		targetName := sourceName(view.To.Database, *view.To.Table)
+		table, ok := tables[targetName]
+		if !ok || len(table.Elements) == 0 {
+			continue
+		}
+
+		viewName := sourceName(view.Database, view.Name)
+		for _, message := range compareViewTarget(view.AsSelect, table) {
+			warnings = append(warnings, ViewTargetWarning{View: viewName, Target: targetName, Message: message})
+		}
+	}
+
+	return warnings
+}
+
+// compareViewTarget returns a message for each incompatibility between the SELECT
+// output of a materialized view and the columns of its target table.
+func compareViewTarget(query *parser.SelectStatement, table *parser.CreateTableStmt) []string {
+	columns := make(map[string]*parser.Column)
+	for _, element := range table.Elements {
+		if element.Column != nil {
+			columns[normalizeIdentifier(element.Column.Name)] = element.Column
+		}
+	}
+
+	var messages []string
+	selected := make(map[string]bool)
+	for i, col := range query.Columns {
+		if col.Star != nil {
+			return nil
+		}
+
+		name, ok := selectColumnName(&col)
+		if !ok {
+			messages = append(messages, fmt.Sprintf(
+				"SELECT expression %d (%s) has no alias, so it can't be matched to a target column", i+1, col.Expression.String()))
+			continue
+		}
+
+		selected[name] = true
+		target, ok := columns[name]
+		if !ok {
+			messages = append(messages, fmt.Sprintf("column %s is not defined in the target table", name))
+			continue
+		}
+
+		if def := target.GetDefault(); def != nil && (def.Type == "MATERIALIZED" || def.Type == "ALIAS") {
+			messages = append(messages, fmt.Sprintf("column %s is %s in the target table and can't be inserted into", name, def.Type))
+			continue
+		}
+
+		if message := compareAggregateState(name, col.Expression, target.DataType); message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	for _, element := range table.Elements {
+		col := element.Column
+		if col == nil || col.GetDefault() != nil || selected[normalizeIdentifier(col.Name)] {
+			continue
+		}
+
+		messages = append(messages, fmt.Sprintf("target column %s has no default and is not populated by the view", col.Name))
+	}
+
+	return messages
+}
+
+// stateCombinators are the aggregate function combinators whose -State is the state of
+// the function they're applied to, e.g. uniqIfState(x, cond) produces a uniq state and
+// uniqMergeState(state) merges uniq states into one.
+var stateCombinators = []string{"If", "Array", "Merge"}
+
+// compareAggregateState checks that -State aggregates are written to AggregateFunction
+// columns of the same function, and that AggregateFunction columns are populated by one.
+func compareAggregateState(name string, expr *parser.Expression, dataType *parser.DataType) string {
+	fn := selectFunction(expr)
+	if fn == nil {
+		return ""
+	}
+
+	aggregate, isAggregateColumn := aggregateFunctionName(dataType)
+	isState := strings.HasSuffix(fn.Name, "State")
+
+	switch {
+	case isState && !isAggregateColumn:
+		return fmt.Sprintf("column %s is populated by %s but is not an AggregateFunction column", name, fn.Name)
+	case !isState && isAggregateColumn:
+		return fmt.Sprintf("column %s is AggregateFunction(%s, ...) but is populated by %s instead of %sState", name, aggregate, fn.Name, aggregate)
+	case isState && !producesState(fn.Name, aggregate):
+		return fmt.Sprintf("column %s is AggregateFunction(%s, ...) but is populated by %s", name, aggregate, fn.Name)
+	}
+
+	return ""
+}
+
+// producesState reports whether the -State function fn produces a state of aggregate,
+// either directly (aggregate+"State") or through the combinators in stateCombinators. Any
+// other suffix names a different function, e.g. uniqExactState isn't a uniq state.
+func producesState(fn, aggregate string) bool {
+	if fn == aggregate+"State" {
+		return true
+	}
+
+	base := strings.TrimSuffix(fn, "State")
+	for _, combinator := range stateCombinators {
+		if nested, ok := strings.CutSuffix(base, combinator); ok && nested != "" && producesState(nested+"State", aggregate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectColumnName returns the name ClickHouse gives a SELECT column when matching it
+// against the target table: its alias or, for plain column references, the column name.
+func selectColumnName(col *parser.SelectColumn) (string, bool) {
+	if col.Alias != nil {
+		return normalizeIdentifier(*col.Alias), true
+	}
+
+	if col.Expression == nil {
+		return "", false
+	}
+
+	if primary := barePrimary(col.Expression.Or); primary != nil && primary.Identifier != nil {
+		return normalizeIdentifier(primary.Identifier.Name), true
+	}
+
+	return "", false
+}
+
+// selectFunction returns the function call an expression consists of, if any.
+func selectFunction(expr *parser.Expression) *parser.FunctionCall {
+	if expr == nil {
+		return nil
+	}
+
+	if primary := barePrimary(expr.Or); primary != nil {
+		return primary.Function
+	}
+	return nil
+}
+
+// aggregateFunctionName returns the aggregate of an AggregateFunction(func, ...) type.
+func aggregateFunctionName(dataType *parser.DataType) (string, bool) {
+	if dataType == nil || dataType.Simple == nil || dataType.Simple.Name != "AggregateFunction" {
+		return "", false
+	}
+
+	params := dataType.Simple.Parameters
+	if len(params) == 0 {
+		return "", false
+	}
+
+	switch {
+	case params[0].Function != nil:
+		return params[0].Function.Name, true
+	case params[0].Ident != nil:
+		return *params[0].Ident, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,106 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/pseudomuto/housekeeper/pkg/parser"
+	"github.com/pseudomuto/housekeeper/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateViewTargets(t *testing.T) {
+	const target = `
+		CREATE TABLE analytics.daily_users (
+			day Date,
+			users AggregateFunction(uniq, String),
+			latency AggregateFunction(quantiles(0.5, 0.99), Float64),
+			updated_at DateTime DEFAULT now()
+		) ENGINE = AggregatingMergeTree() ORDER BY day;`
+
+	tests := []struct {
+		name     string
+		view     string
+		warnings []string
+	}{
+		{
+			name: "matching",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT toDate(ts) AS day, uniqState(user_id) AS users, quantilesState(0.5, 0.99)(latency) AS latency
+				FROM analytics.events GROUP BY day;`,
+		},
+		{
+			name: "combinator state",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT toDate(ts) AS day, uniqIfState(user_id, user_id != '') AS users, latency
+				FROM analytics.events_agg GROUP BY day;`,
+		},
+		{
+			name: "merged combinator state",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT toDate(ts) AS day, uniqMergeState(users) AS users, quantilesArrayIfState(0.5, 0.99)(latencies, ok) AS latency
+				FROM analytics.hourly_users GROUP BY day;`,
+		},
+		{
+			name: "select star",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS SELECT * FROM analytics.staging;`,
+		},
+		{
+			name: "undefined target",
+			view: `CREATE MATERIALIZED VIEW analytics.other_mv TO analytics.other AS SELECT uniqState(user_id) FROM analytics.events;`,
+		},
+		{
+			name: "mismatched aggregates",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT toDate(ts) AS day, uniq(user_id) AS users, avgState(latency) AS latency
+				FROM analytics.events GROUP BY day;`,
+			warnings: []string{
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): column users is AggregateFunction(uniq, ...) but is populated by uniq instead of uniqState",
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): column latency is AggregateFunction(quantiles, ...) but is populated by avgState",
+			},
+		},
+		{
+			name: "functions sharing a prefix",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT toDate(ts) AS day, uniqExactState(user_id) AS users, quantilesTimingState(0.5, 0.99)(latency) AS latency
+				FROM analytics.events GROUP BY day;`,
+			warnings: []string{
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): column users is AggregateFunction(uniq, ...) but is populated by uniqExactState",
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): column latency is AggregateFunction(quantiles, ...) but is populated by quantilesTimingState",
+			},
+		},
+		{
+			name: "state into plain column",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT uniqState(user_id) AS day, uniqState(user_id) AS users, latency
+				FROM analytics.events GROUP BY day;`,
+			warnings: []string{
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): column day is populated by uniqState but is not an AggregateFunction column",
+			},
+		},
+		{
+			name: "unmatched columns",
+			view: `CREATE MATERIALIZED VIEW analytics.daily_users_mv TO analytics.daily_users AS
+				SELECT toDate(ts), uniqState(user_id) AS users, count() AS total
+				FROM analytics.events GROUP BY toDate(ts);`,
+			warnings: []string{
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): SELECT expression 1 (toDate(ts)) has no alias, so it can't be matched to a target column",
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): column total is not defined in the target table",
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): target column day has no default and is not populated by the view",
+				"materialized view analytics.daily_users_mv (TO analytics.daily_users): target column latency has no default and is not populated by the view",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := parser.ParseString(target + tt.view)
+			require.NoError(t, err)
+
+			var warnings []string
+			for _, warning := range schema.ValidateViewTargets(sql) {
+				warnings = append(warnings, warning.String())
+			}
+			require.Equal(t, tt.warnings, warnings)
+		})
+	}
+}